@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	apiv1 "github.com/bavix/apis/pkg/bavix/api/v1"
+	"github.com/bavix/apis/pkg/uuidconv"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/metadata"
+
+	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+)
+
+var (
+	auditAddr  string
+	auditToken string
+	auditID    string
+	auditLimit uint32
+)
+
+// auditCmd returns the audit command.
+//
+// The audit command calls the ListHeartbeats RPC of the AdminService to print
+// the latest heartbeats received by a running instance, so it can be checked
+// whether an agent actually reported a service. The token has to be granted
+// the admin scope.
+//
+//nolint:exhaustruct
+func auditCmd() *cobra.Command {
+	// Create a new audit command.
+	return &cobra.Command{
+		Use:   "audit",
+		Short: "Prints the latest heartbeats received by a running instance",
+		Args:  cobra.NoArgs,
+		// RunE is the function that is called when the command is executed.
+		// It returns an error if the heartbeats cannot be listed.
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if auditToken != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+auditToken)
+			}
+
+			req := &way.ListHeartbeatsRequest{Limit: auditLimit}
+
+			// List the heartbeats of a single service if it is set.
+			if auditID != "" {
+				id, err := uuid.Parse(auditID)
+				if err != nil {
+					return err
+				}
+
+				high, low := uuidconv.UUID2DoubleInt(id)
+				req.Id = &apiv1.UUID{High: high, Low: low}
+			}
+
+			// Connect to the administrative gRPC service of the instance.
+			conn, err := dial(auditAddr)
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			resp, err := way.NewAdminServiceClient(conn).ListHeartbeats(ctx, req)
+			if err != nil {
+				return err
+			}
+
+			// Print the heartbeats as a table.
+			const padding = 2
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, padding, ' ', 0)
+			fmt.Fprintln(w, "TIME\tID\tPEER\tTOKEN\tAGENT\tRESULT")
+
+			for _, heartbeat := range resp.GetHeartbeats() {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+					heartbeat.GetTime().AsTime().Format(time.RFC3339),
+					uuidconv.DoubleInt2UUID(heartbeat.GetId().GetHigh(), heartbeat.GetId().GetLow()),
+					heartbeat.GetPeer(),
+					dash(heartbeat.GetToken()),
+					dash(heartbeat.GetAgent()),
+					heartbeat.GetResult(),
+				)
+			}
+
+			return w.Flush()
+		},
+	}
+}
+
+// dash returns the value, or a dash if it is empty.
+func dash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
+
+// init adds the audit command to the root command.
+func init() {
+	// Create the audit command.
+	auditCmd := auditCmd()
+
+	// Add the audit command to the root command.
+	rootCmd.AddCommand(auditCmd)
+
+	// Add flags that specify the instance and the heartbeats to print.
+	auditCmd.Flags().StringVar(
+		&auditAddr,
+		"addr",
+		"127.0.0.1:4643",
+		"Address of the instance gRPC server.",
+	)
+	auditCmd.Flags().StringVar(&auditToken, "token", os.Getenv("VAKEEL_TOKEN"), "API token. Defaults to $VAKEEL_TOKEN.")
+	auditCmd.Flags().StringVar(&auditID, "id", "", "ID of the service. All services if empty.")
+	auditCmd.Flags().Uint32Var(&auditLimit, "limit", 50, "Maximum number of the latest heartbeats.")
+
+	// Add flags that specify the TLS of the connection.
+	dialFlags(auditCmd)
+}
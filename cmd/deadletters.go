@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/bavix/apis/pkg/uuidconv"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/metadata"
+
+	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+)
+
+var (
+	deadLettersAddr  string
+	deadLettersToken string
+)
+
+// deadLettersCmd returns the dead-letters command.
+//
+// The dead-letters command groups the subcommands that call the AdminService
+// of a running instance to inspect, replay and discard the notifications that
+// could not be delivered. The token has to be granted the admin scope.
+//
+//nolint:exhaustruct
+func deadLettersCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dead-letters",
+		Short: "Manages the notifications that could not be delivered",
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "list",
+			Short: "Prints the dead letters of a running instance",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				conn, err := dial(deadLettersAddr)
+				if err != nil {
+					return err
+				}
+				defer conn.Close()
+
+				resp, err := way.NewAdminServiceClient(conn).ListDeadLetters(deadLettersContext(cmd.Context()), &way.ListDeadLettersRequest{})
+				if err != nil {
+					return err
+				}
+
+				// Print the dead letters as a table.
+				const padding = 2
+
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, padding, ' ', 0)
+				fmt.Fprintln(w, "ID\tFAILED\tSERVICE\tTARGET\tSTATUS\tATTEMPTS\tERROR")
+
+				for _, dead := range resp.GetLetters() {
+					fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+						dead.GetId(),
+						dead.GetFailedAt().AsTime().Format(time.RFC3339),
+						uuidconv.DoubleInt2UUID(dead.GetService().GetHigh(), dead.GetService().GetLow()),
+						dead.GetTarget(),
+						dead.GetStatus(),
+						strconv.FormatUint(uint64(dead.GetAttempts()), 10),
+						dash(dead.GetError()),
+					)
+				}
+
+				return w.Flush()
+			},
+		},
+		&cobra.Command{
+			Use:   "replay <id>",
+			Short: "Delivers a dead letter to its target again",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				conn, err := dial(deadLettersAddr)
+				if err != nil {
+					return err
+				}
+				defer conn.Close()
+
+				_, err = way.NewAdminServiceClient(conn).ReplayDeadLetter(deadLettersContext(cmd.Context()), &way.ReplayDeadLetterRequest{
+					Id: args[0],
+				})
+				if err != nil {
+					return err
+				}
+
+				_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s: delivered\n", args[0])
+
+				return err
+			},
+		},
+		&cobra.Command{
+			Use:   "discard <id>",
+			Short: "Removes a dead letter without delivering it",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				conn, err := dial(deadLettersAddr)
+				if err != nil {
+					return err
+				}
+				defer conn.Close()
+
+				_, err = way.NewAdminServiceClient(conn).DiscardDeadLetter(deadLettersContext(cmd.Context()), &way.DiscardDeadLetterRequest{
+					Id: args[0],
+				})
+				if err != nil {
+					return err
+				}
+
+				_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s: discarded\n", args[0])
+
+				return err
+			},
+		},
+	)
+
+	return cmd
+}
+
+// deadLettersContext returns the context of the calls, carrying the API token if it is set.
+//
+// Parameters:
+//   - ctx: The context.Context of the command.
+//
+// Returns:
+//   - The context.Context of the calls.
+func deadLettersContext(ctx context.Context) context.Context {
+	if deadLettersToken == "" {
+		return ctx
+	}
+
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+deadLettersToken)
+}
+
+// init adds the dead-letters command to the root command.
+func init() {
+	// Create the dead-letters command.
+	deadLettersCmd := deadLettersCmd()
+
+	// Add the dead-letters command to the root command.
+	rootCmd.AddCommand(deadLettersCmd)
+
+	// Add flags that specify the instance and the token, shared by the subcommands.
+	deadLettersCmd.PersistentFlags().StringVar(
+		&deadLettersAddr,
+		"addr",
+		"127.0.0.1:4643",
+		"Address of the instance gRPC server.",
+	)
+	deadLettersCmd.PersistentFlags().StringVar(
+		&deadLettersToken,
+		"token",
+		os.Getenv("VAKEEL_TOKEN"),
+		"API token. Defaults to $VAKEEL_TOKEN.",
+	)
+
+	// Add flags that specify the TLS of the connection.
+	dialFlags(deadLettersCmd)
+}
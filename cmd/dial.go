@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/bavix/vakeel-way/internal/infra/certs"
+)
+
+var (
+	dialTLS        bool
+	dialCA         string
+	dialServerName string
+)
+
+// dialFlags adds the flags of the TLS of the connection to the command.
+//
+// The flags are persistent, so they are shared by the subcommands.
+//
+// Parameters:
+//   - cmd: The command that connects to the gRPC server.
+func dialFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().BoolVar(&dialTLS, "tls", false, "Connect to the gRPC server over TLS.")
+	cmd.PersistentFlags().StringVar(
+		&dialCA,
+		"ca",
+		"",
+		"File with the PEM CA certificates of the gRPC server, trusted along with the system roots. Implies --tls.",
+	)
+	cmd.PersistentFlags().StringVar(
+		&dialServerName,
+		"server-name",
+		"",
+		"Name the certificate of the gRPC server is verified against. Defaults to the host of the address.",
+	)
+}
+
+// dial creates a connection to the gRPC server.
+//
+// The connection is made over TLS if the --tls or the --ca flag is set, and in
+// plaintext otherwise.
+//
+// Parameters:
+//   - addr: The address of the gRPC server.
+//
+// Returns:
+//   - A pointer to a grpc.ClientConn.
+//   - An error if the CA certificates cannot be loaded or the address is invalid.
+func dial(addr string) (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+
+	if dialTLS || dialCA != "" {
+		tlsConfig, err := certs.ClientTLS(dialCA, dialServerName)
+		if err != nil {
+			return nil, err
+		}
+
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	return grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+}
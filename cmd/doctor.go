@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+var doctorTimeout time.Duration
+
+// ErrDiagnosisFailed is an error that indicates that some checks of the doctor command failed.
+var ErrDiagnosisFailed = errors.New("doctor: some checks failed")
+
+// doctorCmd returns the doctor command.
+//
+// The doctor command checks the configuration and the environment it is run
+// in: the configuration is valid, the ports of the servers can be bound, the
+// targets of the webhooks are reachable and the Instatus webhooks exist. It
+// prints a pass/fail report and fails if any check fails, so a new setup can
+// be verified before the server is started.
+//
+//nolint:exhaustruct
+func doctorCmd() *cobra.Command {
+	// Create a new doctor command.
+	return &cobra.Command{
+		Use:   "doctor",
+		Short: "Checks the configuration and the environment of the server",
+		Args:  cobra.NoArgs,
+		// RunE is the function that is called when the command is executed.
+		// It returns an error if any check fails.
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			builder, err := newBuilder()
+
+			// The other checks need a valid configuration.
+			probes := []entities.Probe{{Name: "config " + cfgFile, Err: err}}
+			if err == nil {
+				probes = append(probes, builder.Diagnose(cmd.Context(), doctorTimeout)...)
+			}
+
+			failed, err := printProbes(cmd.OutOrStdout(), probes)
+			if err != nil {
+				return err
+			}
+
+			if failed > 0 {
+				return fmt.Errorf("%w: %d of %d", ErrDiagnosisFailed, failed, len(probes))
+			}
+
+			return nil
+		},
+	}
+}
+
+// printProbes prints the result of every check as a table.
+//
+// Parameters:
+//   - out: The writer of the table.
+//   - probes: The results of the checks.
+//
+// Returns:
+//   - The number of the failed checks.
+//   - An error if the table cannot be written.
+func printProbes(out io.Writer, probes []entities.Probe) (int, error) {
+	const padding = 2
+
+	w := tabwriter.NewWriter(out, 0, 0, padding, ' ', 0)
+	fmt.Fprintln(w, "RESULT\tCHECK\tERROR")
+
+	failed := 0
+
+	for _, probe := range probes {
+		if probe.Err == nil {
+			fmt.Fprintf(w, "PASS\t%s\t-\n", probe.Name)
+
+			continue
+		}
+
+		failed++
+
+		fmt.Fprintf(w, "FAIL\t%s\t%v\n", probe.Name, probe.Err)
+	}
+
+	return failed, w.Flush()
+}
+
+// init adds the doctor command to the root command.
+func init() {
+	// Create the doctor command.
+	doctorCmd := doctorCmd()
+
+	// Add the doctor command to the root command.
+	rootCmd.AddCommand(doctorCmd)
+
+	// Add a flag that specifies the location of the configuration file.
+	doctorCmd.Flags().StringVar(
+		&cfgFile,
+		"config",
+		"/etc/vakeel-way/config.yaml",
+		"Path to the configuration file.",
+	)
+
+	// Add a flag that limits the time of the network checks.
+	doctorCmd.Flags().DurationVar(&doctorTimeout, "timeout", 5*time.Second, "Maximum time of a single network check.")
+}
@@ -0,0 +1,4 @@
+package cmd
+
+// RootCmd exposes the root command to the tests.
+var RootCmd = rootCmd
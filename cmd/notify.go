@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/notifier"
+)
+
+var (
+	previewWebhook  string
+	previewStatus   string
+	previewDuration time.Duration
+)
+
+// notifyCmd returns the notify command.
+//
+// The notify command groups the subcommands that work with the notifications
+// of the configured webhooks.
+//
+//nolint:exhaustruct
+func notifyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "notify",
+		Short: "Works with the notifications",
+	}
+
+	preview := &cobra.Command{
+		Use:   "preview",
+		Short: "Renders the payloads each target of a webhook would receive without sending them",
+		Args:  cobra.NoArgs,
+		// RunE is the function that is called when the command is executed.
+		// It returns an error if the configuration or the flags are invalid.
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			id, err := uuid.Parse(previewWebhook)
+			if err != nil {
+				return err
+			}
+
+			status, err := entities.ParseStatus(previewStatus)
+			if err != nil {
+				return err
+			}
+
+			builder, err := newBuilder()
+			if err != nil {
+				return err
+			}
+
+			// Get the decrypted targets, exactly as the server does.
+			targets, err := builder.WebhookRepository().Get(cmd.Context(), id)
+			if err != nil {
+				return err
+			}
+
+			// The event of a transition that happens right now.
+			now := time.Now()
+			event := entities.Event{
+				ID:       id,
+				Status:   status,
+				Time:     now,
+				Since:    now.Add(-previewDuration),
+				LastSeen: now,
+				Message:  "",
+				Missed:   nil,
+			}
+
+			// Record the requests instead of sending them.
+			recorder := notifier.NewRecorder()
+			mux := builder.PreviewNotifier(recorder)
+
+			for _, target := range targets {
+				fmt.Fprintf(cmd.OutOrStdout(), "# %s (%s)\n", target.Name, target.Type)
+
+				if err := mux.Send(cmd.Context(), target, event); err != nil {
+					fmt.Fprintf(cmd.OutOrStdout(), "error: %v\n\n", err)
+
+					continue
+				}
+
+				for _, req := range recorder.Requests() {
+					printRequest(cmd.OutOrStdout(), req)
+				}
+			}
+
+			return nil
+		},
+	}
+
+	// Add flags that specify the webhook and the transition to preview.
+	preview.Flags().StringVar(&previewWebhook, "webhook", "", "ID of the webhook.")
+	preview.Flags().StringVar(&previewStatus, "status", entities.Down.String(), "Status to preview: up or down.")
+	preview.Flags().DurationVar(
+		&previewDuration,
+		"duration",
+		time.Hour,
+		"Duration of the previous status, e.g. the downtime reported on recovery.",
+	)
+
+	_ = preview.MarkFlagRequired("webhook")
+
+	cmd.AddCommand(preview)
+
+	return cmd
+}
+
+// printRequest writes the recorded request; JSON payloads are indented.
+func printRequest(w io.Writer, req notifier.Request) {
+	fmt.Fprintf(w, "%s %s\n", req.Method, req.URL)
+
+	if contentType := req.Header.Get("Content-Type"); contentType != "" {
+		fmt.Fprintf(w, "Content-Type: %s\n", contentType)
+	}
+
+	var body bytes.Buffer
+	if err := json.Indent(&body, req.Body, "", "  "); err != nil {
+		body.Reset()
+		body.Write(req.Body)
+	}
+
+	fmt.Fprintf(w, "\n%s\n\n", bytes.TrimSpace(body.Bytes()))
+}
+
+// init adds the notify command to the root command.
+func init() {
+	// Create the notify command.
+	notifyCmd := notifyCmd()
+
+	// Add the notify command to the root command.
+	rootCmd.AddCommand(notifyCmd)
+
+	// Add a flag that specifies the location of the configuration file.
+	notifyCmd.PersistentFlags().StringVar(
+		&cfgFile,
+		"config",
+		"/etc/vakeel-way/config.yaml",
+		"Path to the configuration file.",
+	)
+}
@@ -0,0 +1,81 @@
+package cmd_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/cmd"
+)
+
+// NotifyTestSuite represents the test suite for the notify command.
+type NotifyTestSuite struct {
+	suite.Suite
+}
+
+// preview runs the notify preview command with the configuration and returns its output.
+func (suite *NotifyTestSuite) preview(config string, args ...string) (string, error) {
+	path := filepath.Join(suite.T().TempDir(), "config.yaml")
+	suite.Require().NoError(os.WriteFile(path, []byte(config), 0o600))
+
+	var out bytes.Buffer
+
+	cmd.RootCmd.SetOut(&out)
+	cmd.RootCmd.SetErr(&out)
+	cmd.RootCmd.SetArgs(append([]string{"notify", "preview", "--config", path}, args...))
+
+	err := cmd.RootCmd.ExecuteContext(context.Background())
+
+	return out.String(), err
+}
+
+// TestNotify_Preview verifies that the payloads of every target are printed without being sent.
+func (suite *NotifyTestSuite) TestNotify_Preview() {
+	out, err := suite.preview(`
+webhooks:
+  - id: 224f8a59-6705-4f3e-b7de-177757932aad
+    targets:
+      - name: chat
+        type: slack
+        url: https://hooks.slack.test/services/x
+      - name: status
+        url: https://instatus.test/hook
+      - name: keep
+        type: capture
+`, "--webhook", "224f8a59-6705-4f3e-b7de-177757932aad", "--status", "up")
+	suite.Require().NoError(err)
+
+	suite.Contains(out, "# chat (slack)\nPOST https://hooks.slack.test/services/x\nContent-Type: application/json\n\n{\n  \"text\": ")
+	suite.Contains(out, "is up")
+	suite.Contains(out, "# status (instatus)\nPOST https://instatus.test/hook\n")
+	suite.Contains(out, "\"trigger\": \"up\"")
+
+	// The capture notifier sends no request.
+	suite.Contains(out, "# keep (capture)\n")
+	suite.NotContains(out, "error:")
+}
+
+// TestNotify_Invalid verifies that the unknown webhooks and statuses are rejected.
+func (suite *NotifyTestSuite) TestNotify_Invalid() {
+	const config = `
+webhooks:
+  - id: 224f8a59-6705-4f3e-b7de-177757932aad
+    target: https://instatus.test/hook
+`
+
+	_, err := suite.preview(config, "--webhook", "224f8a59-6705-4f3e-b7de-177757932aad", "--status", "sideways")
+	suite.Require().Error(err)
+
+	_, err = suite.preview(config, "--webhook", "not-a-uuid")
+	suite.Require().Error(err)
+}
+
+// TestNotifyTestSuite runs the test suite for the notify command.
+func TestNotifyTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(NotifyTestSuite))
+}
@@ -0,0 +1,256 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	apiv1 "github.com/bavix/apis/pkg/bavix/api/v1"
+	"github.com/bavix/apis/pkg/uuidconv"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+)
+
+var (
+	pingAddr    string
+	pingToken   string
+	pingFile    string
+	pingTimeout time.Duration
+)
+
+var (
+	// ErrNoIDs is an error that indicates that no UUID is given to the ping command.
+	ErrNoIDs = errors.New("ping: no UUID is given")
+
+	// ErrNotAccepted is an error that indicates that some heartbeats were not accepted.
+	ErrNotAccepted = errors.New("ping: some heartbeats were not accepted")
+)
+
+// pingCmd returns the ping command.
+//
+// The ping command calls UpdateStream on a running instance and sends a
+// single heartbeat for every given UUID, so a deployment can be smoke-tested,
+// or a cron job can report a service without a dedicated agent. The command
+// fails if any heartbeat is not accepted.
+//
+//nolint:exhaustruct
+func pingCmd() *cobra.Command {
+	// Create a new ping command.
+	return &cobra.Command{
+		Use:   "ping [UUID...]",
+		Short: "Sends a heartbeat for every UUID to a running instance",
+		// RunE is the function that is called when the command is executed.
+		// It returns an error if the heartbeats cannot be sent or are not accepted.
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ids, err := pingIDs(args, pingFile)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := context.WithTimeout(cmd.Context(), pingTimeout)
+			defer cancel()
+
+			// Connect to the gRPC service of the instance.
+			conn, err := dial(pingAddr)
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			results, err := ping(ctx, way.NewStateServiceClient(conn), ids)
+			if err != nil {
+				return err
+			}
+
+			// Print the result of every heartbeat as a table.
+			const padding = 2
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, padding, ' ', 0)
+			fmt.Fprintln(w, "ID\tRESULT")
+
+			accepted := true
+
+			for _, result := range results {
+				if result.GetResult() != way.UpdateResult_RESULT_ACCEPTED {
+					accepted = false
+				}
+
+				fmt.Fprintf(w, "%s\t%s\n",
+					uuidconv.DoubleInt2UUID(result.GetId().GetHigh(), result.GetId().GetLow()),
+					strings.ToLower(strings.TrimPrefix(result.GetResult().String(), "RESULT_")),
+				)
+			}
+
+			if err := w.Flush(); err != nil {
+				return err
+			}
+
+			if !accepted {
+				return ErrNotAccepted
+			}
+
+			return nil
+		},
+	}
+}
+
+// ping sends a heartbeat for every UUID over an UpdateStream call.
+//
+// The command introduces itself in the handshake, so the heartbeats can be
+// told apart from those of the agents.
+//
+// Parameters:
+//   - ctx: The context.Context of the stream.
+//   - client: The client of the StateService.
+//   - ids: The UUIDs of the services.
+//
+// Returns:
+//   - The result of every heartbeat, in the order of the UUIDs.
+//   - An error if the stream fails.
+func ping(ctx context.Context, client way.StateServiceClient, ids []*apiv1.UUID) ([]*way.UpdateResult, error) {
+	if pingToken != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+pingToken)
+	}
+
+	// Offer every supported protocol version, the server chooses the latest it supports.
+	ctx = metadata.AppendToOutgoingContext(ctx, entities.ProtocolMetadata, strings.Join(entities.Protocols(), ","))
+
+	stream, err := client.UpdateStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, _ := os.Hostname()
+
+	err = stream.Send(&way.UpdateRequest{
+		Ids:       ids,
+		Handshake: &way.Handshake{Hostname: hostname, Version: "ping/" + version},
+	})
+	if err != nil {
+		// The actual error of a failed send is returned by the server on the receive.
+		_, err = stream.Recv()
+
+		return nil, err
+	}
+
+	// Wait for the response to the request. The notices of the server are skipped.
+	var results []*way.UpdateResult
+
+	for results == nil {
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(resp.GetResults()) > 0 {
+			results = resp.GetResults()
+		}
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	// Wait for the server to close the stream.
+	for {
+		if _, err := stream.Recv(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return results, nil
+			}
+
+			return nil, err
+		}
+	}
+}
+
+// pingIDs collects the UUIDs of the arguments and the file.
+//
+// The file has a UUID per line. The empty lines and the lines starting with
+// "#" are skipped. The file "-" is the standard input.
+//
+// Parameters:
+//   - args: The UUIDs given as the arguments.
+//   - path: The path to the file of the UUIDs, or empty.
+//
+// Returns:
+//   - The UUIDs.
+//   - An error if a UUID is invalid, the file cannot be read, or no UUID is given.
+func pingIDs(args []string, path string) ([]*apiv1.UUID, error) {
+	values := args
+
+	if path != "" {
+		file := os.Stdin
+
+		if path != "-" {
+			var err error
+
+			if file, err = os.Open(path); err != nil {
+				return nil, err
+			}
+			defer file.Close()
+		}
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line != "" && !strings.HasPrefix(line, "#") {
+				values = append(values, line)
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(values) == 0 {
+		return nil, ErrNoIDs
+	}
+
+	ids := make([]*apiv1.UUID, 0, len(values))
+
+	for _, value := range values {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, value)
+		}
+
+		high, low := uuidconv.UUID2DoubleInt(id)
+		ids = append(ids, &apiv1.UUID{High: high, Low: low})
+	}
+
+	return ids, nil
+}
+
+// init adds the ping command to the root command.
+func init() {
+	// Create the ping command.
+	pingCmd := pingCmd()
+
+	// Add the ping command to the root command.
+	rootCmd.AddCommand(pingCmd)
+
+	// Add flags that specify the instance and the UUIDs.
+	pingCmd.Flags().StringVar(
+		&pingAddr,
+		"addr",
+		"127.0.0.1:4643",
+		"Address of the instance gRPC server.",
+	)
+	pingCmd.Flags().StringVar(&pingToken, "token", os.Getenv("VAKEEL_TOKEN"), "API token. Defaults to $VAKEEL_TOKEN.")
+	pingCmd.Flags().StringVar(&pingFile, "file", "", "File with a UUID per line, or - for the standard input.")
+	pingCmd.Flags().DurationVar(&pingTimeout, "timeout", 10*time.Second, "Maximum time to wait for the instance.")
+
+	// Add flags that specify the TLS of the connection.
+	dialFlags(pingCmd)
+}
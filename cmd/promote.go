@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/metadata"
+
+	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+)
+
+var (
+	promoteAddr   string
+	promoteToken  string
+	promoteReason string
+)
+
+// promoteCmd returns the promote command.
+//
+// The promote command calls the Promote RPC of the AdminService to switch a
+// standby replica into active notification-dispatching mode during failover.
+// The token has to be granted the admin scope.
+//
+//nolint:exhaustruct
+func promoteCmd() *cobra.Command {
+	// Create a new promote command.
+	return &cobra.Command{
+		Use:   "promote",
+		Short: "Promotes a standby replica to active mode",
+		// RunE is the function that is called when the command is executed.
+		// It returns an error if the replica cannot be promoted.
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			ctx := cmd.Context()
+			if promoteToken != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+promoteToken)
+			}
+
+			// Connect to the administrative gRPC service of the replica.
+			conn, err := dial(promoteAddr)
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			// Promote the replica.
+			resp, err := way.NewAdminServiceClient(conn).Promote(ctx, &way.PromoteRequest{
+				Reason: promoteReason,
+			})
+			if err != nil {
+				return err
+			}
+
+			// Print the transition.
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "%s: %s -> %s\n", promoteAddr, resp.GetPreviousMode(), resp.GetMode())
+
+			return err
+		},
+	}
+}
+
+// init adds the promote command to the root command.
+func init() {
+	// Create the promote command.
+	promoteCmd := promoteCmd()
+
+	// Add the promote command to the root command.
+	rootCmd.AddCommand(promoteCmd)
+
+	// Add flags that specify the replica to promote and the reason of the promotion.
+	promoteCmd.Flags().StringVar(
+		&promoteAddr,
+		"addr",
+		"127.0.0.1:4643",
+		"Address of the replica gRPC server.",
+	)
+	promoteCmd.Flags().StringVar(&promoteToken, "token", os.Getenv("VAKEEL_TOKEN"), "API token. Defaults to $VAKEEL_TOKEN.")
+	promoteCmd.Flags().StringVar(
+		&promoteReason,
+		"reason",
+		"manual promotion",
+		"Reason of the promotion, written to the replica log.",
+	)
+
+	// Add flags that specify the TLS of the connection.
+	dialFlags(promoteCmd)
+}
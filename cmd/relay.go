@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// relayCmd returns the relay command.
+//
+// The relay command starts the relay mode: the heartbeats of the local
+// processes are accepted over HTTP or a Unix socket and multiplexed over a
+// single gRPC stream to the upstream server.
+//
+//nolint:exhaustruct
+func relayCmd() *cobra.Command {
+	// Create a new relay command.
+	return &cobra.Command{
+		Use:   "relay",
+		Short: "Relays the heartbeats of the local processes to the server",
+		Args:  cobra.NoArgs,
+		// RunE is the function that is called when the command is executed.
+		// It returns an error if the relay cannot be started.
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			// Create a new context that listens for the interrupt signal.
+			ctx, cancel := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+			defer cancel()
+
+			builder, err := newBuilder()
+			if err != nil {
+				return err
+			}
+
+			// Attach the logger to the context and run the relay.
+			return builder.RunRelay(builder.Logger(ctx))
+		},
+	}
+}
+
+// init adds the relay command to the root command.
+func init() {
+	// Create the relay command.
+	relayCmd := relayCmd()
+
+	// Add the relay command to the root command.
+	rootCmd.AddCommand(relayCmd)
+
+	// Add a flag that specifies the location of the configuration file.
+	relayCmd.Flags().StringVar(
+		&cfgFile,
+		"config",
+		"/etc/vakeel-way/config.yaml",
+		"Path to the configuration file.",
+	)
+}
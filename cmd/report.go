@@ -0,0 +1,158 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	apiv1 "github.com/bavix/apis/pkg/bavix/api/v1"
+	"github.com/bavix/apis/pkg/uuidconv"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/export"
+	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+)
+
+var (
+	reportAddr   string
+	reportToken  string
+	reportFrom   string
+	reportTo     string
+	reportFormat string
+)
+
+// reportCmd returns the report command.
+//
+// The report command exports the downtimes of the services of a running
+// instance over a period as CSV or JSON, for the monthly SLA reports. The
+// incidents are listed with the ListIncidents RPC, and only their part within
+// the period is counted as downtime. The period defaults to the previous
+// calendar month in UTC.
+//
+//nolint:exhaustruct
+func reportCmd() *cobra.Command {
+	// Create a new report command.
+	return &cobra.Command{
+		Use:   "report [UUID...]",
+		Short: "Exports the downtimes of the services of a running instance",
+		// RunE is the function that is called when the command is executed.
+		// It returns an error if the period is invalid or the incidents cannot be listed.
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := export.ContentType(reportFormat); err != nil {
+				return err
+			}
+
+			now := time.Now()
+			from, to := export.PreviousMonth(now.UTC())
+
+			if reportFrom != "" || reportTo != "" {
+				from, to = time.Unix(0, 0), now
+			}
+
+			for _, bound := range []struct {
+				value string
+				field *time.Time
+			}{{reportFrom, &from}, {reportTo, &to}} {
+				if bound.value == "" {
+					continue
+				}
+
+				at, err := time.Parse(time.RFC3339, bound.value)
+				if err != nil {
+					return err
+				}
+
+				*bound.field = at
+			}
+
+			req := &way.ListIncidentsRequest{
+				Ids:      make([]*apiv1.UUID, 0, len(args)),
+				From:     timestamppb.New(from),
+				To:       timestamppb.New(to),
+				PageSize: 1000, //nolint:mnd
+			}
+
+			for _, arg := range args {
+				id, err := uuid.Parse(arg)
+				if err != nil {
+					return fmt.Errorf("%w: %s", err, arg)
+				}
+
+				high, low := uuidconv.UUID2DoubleInt(id)
+				req.Ids = append(req.Ids, &apiv1.UUID{High: high, Low: low})
+			}
+
+			ctx := cmd.Context()
+			if reportToken != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+reportToken)
+			}
+
+			// Connect to the gRPC service of the instance.
+			conn, err := dial(reportAddr)
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			client := way.NewStateServiceClient(conn)
+
+			var incidents []entities.Incident
+
+			// List the incidents page by page.
+			for {
+				resp, err := client.ListIncidents(ctx, req)
+				if err != nil {
+					return err
+				}
+
+				for _, msg := range resp.GetIncidents() {
+					incident := entities.Incident{
+						ID:    uuidconv.DoubleInt2UUID(msg.GetId().GetHigh(), msg.GetId().GetLow()),
+						Start: msg.GetStarted().AsTime(),
+						End:   time.Time{},
+					}
+
+					if !msg.GetOngoing() {
+						incident.End = msg.GetResolved().AsTime()
+					}
+
+					incidents = append(incidents, incident)
+				}
+
+				if req.PageToken = resp.GetNextPageToken(); req.PageToken == "" {
+					break
+				}
+			}
+
+			return export.Write(cmd.OutOrStdout(), reportFormat, export.Downtimes(incidents, from, to, now))
+		},
+	}
+}
+
+// init adds the report command to the root command.
+func init() {
+	// Create the report command.
+	reportCmd := reportCmd()
+
+	// Add the report command to the root command.
+	rootCmd.AddCommand(reportCmd)
+
+	// Add flags that specify the instance, the period and the format.
+	reportCmd.Flags().StringVar(
+		&reportAddr,
+		"addr",
+		"127.0.0.1:4643",
+		"Address of the instance gRPC server.",
+	)
+	reportCmd.Flags().StringVar(&reportToken, "token", os.Getenv("VAKEEL_TOKEN"), "API token. Defaults to $VAKEEL_TOKEN.")
+	reportCmd.Flags().StringVar(&reportFrom, "from", "", "Beginning of the period in RFC 3339 format. Defaults to the previous month.")
+	reportCmd.Flags().StringVar(&reportTo, "to", "", "End of the period in RFC 3339 format. Defaults to the previous month.")
+	reportCmd.Flags().StringVar(&reportFormat, "format", export.FormatCSV, "Report format: csv or json.")
+
+	// Add flags that specify the TLS of the connection.
+	dialFlags(reportCmd)
+}
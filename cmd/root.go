@@ -5,6 +5,9 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/bavix/vakeel-way/internal/build"
+	"github.com/bavix/vakeel-way/internal/config"
 )
 
 // version is the version of the application.
@@ -40,3 +43,16 @@ func Execute(ctx context.Context) {
 		os.Exit(1)
 	}
 }
+
+// newBuilder reads the configuration file and creates a new builder.
+//
+// It is used by the commands that work with the configuration without starting
+// the server.
+func newBuilder() (*build.Builder, error) {
+	cfg, err := config.New(cfgFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return build.NewBuilder(cfg, build.WithVersion(version))
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/bavix/vakeel-way/internal/infra/secrets"
+)
+
+// secretsCmd returns the secrets command.
+//
+// The secrets command groups the subcommands that manage the values encrypted
+// at rest: generating keys, encrypting values, and rotating keys.
+//
+//nolint:exhaustruct
+func secretsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secrets",
+		Short: "Manages encrypted secrets",
+	}
+
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "keygen",
+			Short: "Generates a new encryption key",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				key, err := secrets.GenerateKey()
+				if err != nil {
+					return err
+				}
+
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), key)
+
+				return err
+			},
+		},
+		&cobra.Command{
+			Use:   "encrypt <value>",
+			Short: "Encrypts a value with the primary key",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				builder, err := newBuilder()
+				if err != nil {
+					return err
+				}
+
+				value, err := builder.Keyring().Seal(args[0])
+				if err != nil {
+					return err
+				}
+
+				_, err = fmt.Fprintln(cmd.OutOrStdout(), value)
+
+				return err
+			},
+		},
+		&cobra.Command{
+			Use:   "rotate",
+			Short: "Re-encrypts the configuration file with the primary key",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, _ []string) error {
+				builder, err := newBuilder()
+				if err != nil {
+					return err
+				}
+
+				data, err := os.ReadFile(cfgFile)
+				if err != nil {
+					return err
+				}
+
+				// Re-encrypt the values sealed with the older keys.
+				text, count, err := builder.Keyring().Rotate(string(data))
+				if err != nil {
+					return err
+				}
+
+				stat, err := os.Stat(cfgFile)
+				if err != nil {
+					return err
+				}
+
+				if err := os.WriteFile(cfgFile, []byte(text), stat.Mode().Perm()); err != nil {
+					return err
+				}
+
+				_, err = fmt.Fprintf(cmd.OutOrStdout(), "%d value(s) re-encrypted\n", count)
+
+				return err
+			},
+		},
+	)
+
+	return cmd
+}
+
+// init adds the secrets command to the root command.
+func init() {
+	// Create the secrets command.
+	secretsCmd := secretsCmd()
+
+	// Add the secrets command to the root command.
+	rootCmd.AddCommand(secretsCmd)
+
+	// Add a flag that specifies the location of the configuration file.
+	secretsCmd.PersistentFlags().StringVar(
+		&cfgFile,
+		"config",
+		"/etc/vakeel-way/config.yaml",
+		"Path to the configuration file.",
+	)
+}
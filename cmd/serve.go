@@ -5,6 +5,7 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
 
@@ -35,20 +36,141 @@ func serveCmd() *cobra.Command {
 			// Read the configuration from the environment variables.
 			cfg, err := config.New(cfgFile)
 			if err != nil {
+				build.ReportError(cfg, version, err)
+
 				return err
 			}
 
 			// Create a new builder using the configuration.
-			builder, err := build.NewBuilder(cfg)
+			builder, err := build.NewBuilder(cfg, build.WithVersion(version), build.WithConfigPath(cfgFile))
 			if err != nil {
+				build.ReportError(cfg, version, err)
+
+				return err
+			}
+
+			// Attach the logger to the context.
+			ctx = builder.Logger(ctx)
+
+			// Tune the runtime to the limits of the container.
+			builder.ApplyResources(ctx)
+
+			// Build the services shared by the servers before they start.
+			if err := builder.Prepare(ctx); err != nil {
 				return err
 			}
 
+			// Run the HTTP server in the background. If it fails, the whole
+			// application is stopped.
+			go func() {
+				if err := builder.RunHTTPServer(ctx); err != nil {
+					zerolog.Ctx(ctx).Err(err).Msg("HTTP server failed")
+					cancel()
+				}
+			}()
+
+			// Run the status page in the background. If it fails, the whole
+			// application is stopped.
+			go func() {
+				if err := builder.RunStatusPage(ctx); err != nil {
+					zerolog.Ctx(ctx).Err(err).Msg("Status page failed")
+					cancel()
+				}
+			}()
+
+			// Run the admin REST API in the background. If it fails, the whole
+			// application is stopped.
+			go func() {
+				if err := builder.RunAdminHTTP(ctx); err != nil {
+					zerolog.Ctx(ctx).Err(err).Msg("Admin REST API failed")
+					cancel()
+				}
+			}()
+
+			// Answer the challenges of the ACME authority in the background. If
+			// it fails, the whole application is stopped.
+			go func() {
+				if err := builder.RunACMEChallenge(ctx); err != nil {
+					zerolog.Ctx(ctx).Err(err).Msg("ACME challenge listener failed")
+					cancel()
+				}
+			}()
+
+			// Run the debug listener in the background. If it fails, the whole
+			// application is stopped.
+			go func() {
+				if err := builder.RunDebugServer(ctx); err != nil {
+					zerolog.Ctx(ctx).Err(err).Msg("Debug listener failed")
+					cancel()
+				}
+			}()
+
+			// Consume the heartbeats from NATS in the background. If it fails,
+			// the whole application is stopped.
+			go func() {
+				if err := builder.RunNATS(ctx); err != nil {
+					zerolog.Ctx(ctx).Err(err).Msg("NATS consumer failed")
+					cancel()
+				}
+			}()
+
+			// Receive the heartbeats from MQTT in the background. If it fails,
+			// the whole application is stopped.
+			go func() {
+				if err := builder.RunMQTT(ctx); err != nil {
+					zerolog.Ctx(ctx).Err(err).Msg("MQTT listener failed")
+					cancel()
+				}
+			}()
+
+			// Receive the heartbeats over UDP in the background. If it fails,
+			// the whole application is stopped.
+			go func() {
+				if err := builder.RunUDP(ctx); err != nil {
+					zerolog.Ctx(ctx).Err(err).Msg("UDP listener failed")
+					cancel()
+				}
+			}()
+
+			// Join the cluster in the background. If it fails, the whole
+			// application is stopped.
+			go func() {
+				if err := builder.RunCluster(ctx); err != nil {
+					zerolog.Ctx(ctx).Err(err).Msg("Cluster failed")
+					cancel()
+				}
+			}()
+
+			// Renew the token of Vault in the background.
+			builder.StartVault(ctx)
+
+			// Export the events to Kafka through the REST Proxy in the background.
+			builder.StartExport(ctx)
+
+			// Emit the metrics to StatsD in the background.
+			builder.StartStatsD(ctx)
+
+			// Push the metrics to the Pushgateway in the background.
+			builder.StartPushgateway(ctx)
+
+			// Take part in the leader election in the background.
+			builder.StartElection(ctx)
+
+			// Sync the webhooks defined in Kubernetes in the background.
+			builder.StartOperator(ctx)
+
+			// Register the gRPC endpoint in Consul until the server stops.
+			builder.StartConsul(ctx)
+
 			// Run the gRPC server using the builder. The context is used to log
 			// messages related to the gRPC server.
-			if err := builder.RunGRPCServer(
-				builder.Logger(ctx),
-			); !errors.Is(err, grpc.ErrServerStopped) {
+			err = builder.RunGRPCServer(ctx)
+
+			// Stop the background goroutines and wait for them to finish.
+			cancel()
+			builder.Wait()
+
+			if !errors.Is(err, grpc.ErrServerStopped) {
 				return err
 			}
 
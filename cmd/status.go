@@ -0,0 +1,279 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	apiv1 "github.com/bavix/apis/pkg/bavix/api/v1"
+	"github.com/bavix/apis/pkg/uuidconv"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc/metadata"
+
+	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+)
+
+var (
+	statusAddr   string
+	statusToken  string
+	statusLabels map[string]string
+	statusOutput string
+)
+
+// ErrUnknownOutput is an error that indicates that the output format is unknown.
+var ErrUnknownOutput = errors.New("status: unknown output format")
+
+// statusRow is a service printed by the status command.
+type statusRow struct {
+	ID        uuid.UUID         `json:"id"`
+	Known     bool              `json:"known"`
+	Status    string            `json:"status,omitempty"`
+	Since     *time.Time        `json:"since,omitempty"`
+	LastSeen  *time.Time        `json:"last_seen,omitempty"`
+	Namespace string            `json:"namespace,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty"`
+	Agent     string            `json:"agent,omitempty"`
+}
+
+// statusCmd returns the status command.
+//
+// The status command prints the status of the services of a running instance.
+// The given UUIDs are queried with the GetStatus RPC, otherwise every service
+// matching the labels is listed with the ListServices RPC.
+//
+//nolint:exhaustruct
+func statusCmd() *cobra.Command {
+	// Create a new status command.
+	return &cobra.Command{
+		Use:   "status [UUID...]",
+		Short: "Prints the status of the services of a running instance",
+		// RunE is the function that is called when the command is executed.
+		// It returns an error if the status cannot be queried.
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if statusToken != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+statusToken)
+			}
+
+			// Connect to the gRPC service of the instance.
+			conn, err := dial(statusAddr)
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
+
+			client := way.NewStateServiceClient(conn)
+
+			var rows []statusRow
+			if len(args) > 0 {
+				rows, err = queryStatus(ctx, client, args)
+			} else {
+				rows, err = listStatus(ctx, client, statusLabels)
+			}
+
+			if err != nil {
+				return err
+			}
+
+			switch statusOutput {
+			case "json":
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+
+				return encoder.Encode(rows)
+			case "table":
+				return printStatus(cmd.OutOrStdout(), rows)
+			default:
+				return fmt.Errorf("%w: %q", ErrUnknownOutput, statusOutput)
+			}
+		},
+	}
+}
+
+// queryStatus queries the status of the given services.
+//
+// Parameters:
+//   - ctx: The context.Context of the call.
+//   - client: The client of the StateService.
+//   - args: The UUIDs of the services.
+//
+// Returns:
+//   - The services, in the order of the UUIDs.
+//   - An error if a UUID is invalid or the call fails.
+func queryStatus(ctx context.Context, client way.StateServiceClient, args []string) ([]statusRow, error) {
+	req := &way.GetStatusRequest{Ids: make([]*apiv1.UUID, 0, len(args))}
+
+	for _, arg := range args {
+		id, err := uuid.Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, arg)
+		}
+
+		high, low := uuidconv.UUID2DoubleInt(id)
+		req.Ids = append(req.Ids, &apiv1.UUID{High: high, Low: low})
+	}
+
+	resp, err := client.GetStatus(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]statusRow, 0, len(resp.GetStatuses()))
+	for _, st := range resp.GetStatuses() {
+		rows = append(rows, newStatusRow(st))
+	}
+
+	return rows, nil
+}
+
+// listStatus lists every service matching the labels, page by page.
+//
+// Parameters:
+//   - ctx: The context.Context of the calls.
+//   - client: The client of the StateService.
+//   - labels: The label selector.
+//
+// Returns:
+//   - The services.
+//   - An error if a call fails.
+func listStatus(ctx context.Context, client way.StateServiceClient, labels map[string]string) ([]statusRow, error) {
+	var (
+		rows  []statusRow
+		token string
+	)
+
+	for {
+		resp, err := client.ListServices(ctx, &way.ListServicesRequest{PageToken: token, Labels: labels})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, service := range resp.GetServices() {
+			row := newStatusRow(service.GetStatus())
+			row.ID = uuidconv.DoubleInt2UUID(service.GetId().GetHigh(), service.GetId().GetLow())
+			row.Namespace = service.GetNamespace()
+			row.Labels = service.GetLabels()
+			row.Agent = service.GetAgent().GetHostname()
+
+			rows = append(rows, row)
+		}
+
+		if token = resp.GetNextPageToken(); token == "" {
+			return rows, nil
+		}
+	}
+}
+
+// newStatusRow converts the status of a service.
+//
+// Parameters:
+//   - st: The ServiceStatus message.
+//
+// Returns:
+//   - The statusRow of the service.
+//
+//nolint:exhaustruct
+func newStatusRow(st *way.ServiceStatus) statusRow {
+	row := statusRow{
+		ID:     uuidconv.DoubleInt2UUID(st.GetId().GetHigh(), st.GetId().GetLow()),
+		Known:  st.GetKnown(),
+		Status: st.GetStatus(),
+	}
+
+	if st.GetSince() != nil {
+		since := st.GetSince().AsTime()
+		row.Since = &since
+	}
+
+	if st.GetLastSeen() != nil {
+		seen := st.GetLastSeen().AsTime()
+		row.LastSeen = &seen
+	}
+
+	return row
+}
+
+// printStatus prints the services as a table.
+//
+// Parameters:
+//   - out: The writer of the table.
+//   - rows: The services.
+//
+// Returns:
+//   - An error if the table cannot be written.
+func printStatus(out io.Writer, rows []statusRow) error {
+	const padding = 2
+
+	w := tabwriter.NewWriter(out, 0, 0, padding, ' ', 0)
+	fmt.Fprintln(w, "ID\tSTATUS\tSINCE\tLAST SEEN\tAGENT\tLABELS")
+
+	for _, row := range rows {
+		status := "unknown"
+		if row.Known {
+			status = row.Status
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			row.ID,
+			status,
+			formatTime(row.Since),
+			formatTime(row.LastSeen),
+			dash(row.Agent),
+			dash(formatLabels(row.Labels)),
+		)
+	}
+
+	return w.Flush()
+}
+
+// formatTime returns the time in the RFC 3339 format, or a dash if it is not set.
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+// formatLabels returns the labels as comma-separated key=value pairs, sorted by key.
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for key, value := range labels {
+		pairs = append(pairs, key+"="+value)
+	}
+
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ",")
+}
+
+// init adds the status command to the root command.
+func init() {
+	// Create the status command.
+	statusCmd := statusCmd()
+
+	// Add the status command to the root command.
+	rootCmd.AddCommand(statusCmd)
+
+	// Add flags that specify the instance, the services and the output.
+	statusCmd.Flags().StringVar(
+		&statusAddr,
+		"addr",
+		"127.0.0.1:4643",
+		"Address of the instance gRPC server.",
+	)
+	statusCmd.Flags().StringVar(&statusToken, "token", os.Getenv("VAKEEL_TOKEN"), "API token. Defaults to $VAKEEL_TOKEN.")
+	statusCmd.Flags().StringToStringVar(&statusLabels, "label", nil, "Label selector of the listed services, e.g. team=core.")
+	statusCmd.Flags().StringVarP(&statusOutput, "output", "o", "table", "Output format: table or json.")
+
+	// Add flags that specify the TLS of the connection.
+	dialFlags(statusCmd)
+}
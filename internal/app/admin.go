@@ -0,0 +1,454 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	apiv1 "github.com/bavix/apis/pkg/bavix/api/v1"
+	"github.com/bavix/apis/pkg/uuidconv"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/domain/services"
+	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+)
+
+var _ = way.AdminServiceServer(&AdminServer{}) //nolint:exhaustruct
+
+// CaptureLog represents an interface for retrieving the captured notifications.
+type CaptureLog interface {
+	// List returns the captured notifications, oldest first.
+	List() []entities.CapturedNotification
+}
+
+// StatusWriter represents an interface for forcing the status of the services.
+type StatusWriter interface {
+	StatusReader
+
+	// Send records the status of the service and notifies the targets of the transition.
+	//
+	// Parameters:
+	//   - ctx: The context.Context used to cancel the operation if needed.
+	//   - id: The UUID of the service.
+	//   - status: The new status of the service.
+	//
+	// Returns:
+	//   - An error if the targets cannot be retrieved or the status cannot be
+	//     delivered to some of them. The status is recorded anyway.
+	Send(ctx context.Context, id uuid.UUID, status entities.Status) error
+}
+
+// Reloader represents an interface for reloading the webhooks from the configuration.
+type Reloader interface {
+	// Reload reloads the webhooks from the configuration.
+	//
+	// Parameters:
+	//   - ctx: The context.Context holding the logger.
+	//
+	// Returns:
+	//   - The number of the reloaded webhooks.
+	//   - An error if the configuration cannot be read or is invalid.
+	Reload(ctx context.Context) (int, error)
+}
+
+// NewAdminServer creates a new instance of the AdminServer struct.
+//
+// Parameters:
+//   - replica: A *services.Replica holding the mode of the current instance.
+//   - silencer: A *services.Silencer holding the maintenance windows.
+//   - stale: A *services.StalePolicy flagging the stale webhooks, or nil if the policy is disabled.
+//   - captured: A CaptureLog holding the notifications of the capture targets.
+//   - heartbeats: An AuditLog holding the latest received heartbeats.
+//   - services: A ServiceRegistry of the configured services.
+//   - states: A StatusWriter used to force the status of the services.
+//   - reloader: A Reloader used to reload the webhooks.
+//   - letters: A *services.DeadLetters holding the undelivered notifications.
+//
+// Returns:
+//   - A pointer to an AdminServer struct.
+//
+//nolint:exhaustruct
+func NewAdminServer(
+	replica *services.Replica,
+	silencer *services.Silencer,
+	stale *services.StalePolicy,
+	captured CaptureLog,
+	heartbeats AuditLog,
+	services ServiceRegistry,
+	states StatusWriter,
+	reloader Reloader,
+	letters *services.DeadLetters,
+) *AdminServer {
+	return &AdminServer{
+		replica:    replica,
+		silencer:   silencer,
+		stale:      stale,
+		captured:   captured,
+		heartbeats: heartbeats,
+		services:   services,
+		states:     states,
+		reloader:   reloader,
+		letters:    letters,
+	}
+}
+
+// AdminServer is a gRPC server implementation that provides the AdminService
+// RPC service. It implements the way.AdminServiceServer interface.
+type AdminServer struct {
+	replica    *services.Replica
+	silencer   *services.Silencer
+	stale      *services.StalePolicy
+	captured   CaptureLog
+	heartbeats AuditLog
+	services   ServiceRegistry
+	states     StatusWriter
+	reloader   Reloader
+	letters    *services.DeadLetters
+
+	way.UnimplementedAdminServiceServer
+}
+
+// Promote handles the Promote RPC call.
+//
+// It switches the current instance into active mode. If the leader-election
+// subsystem refuses the promotion, a FailedPrecondition error is returned.
+func (s *AdminServer) Promote(ctx context.Context, req *way.PromoteRequest) (*way.PromoteResponse, error) {
+	// Promote the replica.
+	previous, err := s.replica.Promote(ctx, req.GetReason())
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	// Report the transition back to the caller.
+	return &way.PromoteResponse{
+		PreviousMode: previous.String(),
+		Mode:         s.replica.Mode().String(),
+	}, nil
+}
+
+// CreateSilence handles the CreateSilence RPC call.
+//
+// It creates a maintenance window. If the start time is omitted, the window
+// begins immediately. An InvalidArgument error is returned if the window has
+// no matchers or ends before it starts.
+func (s *AdminServer) CreateSilence(ctx context.Context, req *way.Silence) (*way.Silence, error) {
+	silence := entities.Silence{
+		ID:       uuid.Nil,
+		IDs:      make([]uuid.UUID, 0, len(req.GetIds())),
+		Labels:   req.GetLabels(),
+		StartsAt: time.Now(),
+		EndsAt:   req.GetEndsAt().AsTime(),
+		Comment:  req.GetComment(),
+	}
+
+	if req.GetStartsAt() != nil {
+		silence.StartsAt = req.GetStartsAt().AsTime()
+	}
+
+	for _, id := range req.GetIds() {
+		silence.IDs = append(silence.IDs, uuidconv.DoubleInt2UUID(id.GetHigh(), id.GetLow()))
+	}
+
+	silence, err := s.silencer.Add(silence)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("silence", silence.ID.String()).
+		Time("ends_at", silence.EndsAt).
+		Str("comment", silence.Comment).
+		Msg("Silence created")
+
+	return silenceMessage(silence), nil
+}
+
+// ListSilences handles the ListSilences RPC call.
+//
+// It returns the silences that have not expired yet.
+func (s *AdminServer) ListSilences(_ context.Context, _ *way.ListSilencesRequest) (*way.ListSilencesResponse, error) {
+	silences := s.silencer.List()
+
+	resp := &way.ListSilencesResponse{Silences: make([]*way.Silence, 0, len(silences))}
+	for _, silence := range silences {
+		resp.Silences = append(resp.Silences, silenceMessage(silence))
+	}
+
+	return resp, nil
+}
+
+// DeleteSilence handles the DeleteSilence RPC call.
+//
+// It removes the silence. A NotFound error is returned if there is no such silence.
+func (s *AdminServer) DeleteSilence(
+	ctx context.Context,
+	req *way.DeleteSilenceRequest,
+) (*way.DeleteSilenceResponse, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.silencer.Remove(id); err != nil {
+		if errors.Is(err, services.ErrSilenceNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().Str("silence", id.String()).Msg("Silence deleted")
+
+	return &way.DeleteSilenceResponse{}, nil
+}
+
+// ListStaleWebhooks handles the ListStaleWebhooks RPC call.
+//
+// It returns the stale webhooks, oldest first. A FailedPrecondition error is
+// returned if the stale webhook policy is disabled.
+func (s *AdminServer) ListStaleWebhooks(
+	_ context.Context,
+	_ *way.ListStaleWebhooksRequest,
+) (*way.ListStaleWebhooksResponse, error) {
+	if s.stale == nil {
+		return nil, status.Error(codes.FailedPrecondition, "stale webhook policy is disabled")
+	}
+
+	webhooks := s.stale.List()
+
+	resp := &way.ListStaleWebhooksResponse{Webhooks: make([]*way.StaleWebhook, 0, len(webhooks))}
+	for _, webhook := range webhooks {
+		high, low := uuidconv.UUID2DoubleInt(webhook.ID)
+		resp.Webhooks = append(resp.Webhooks, &way.StaleWebhook{
+			Id:       &apiv1.UUID{High: high, Low: low},
+			LastSeen: timestamppb.New(webhook.LastSeen),
+			Never:    webhook.Never,
+			Archived: webhook.Archived,
+		})
+	}
+
+	return resp, nil
+}
+
+// ListCapturedNotifications handles the ListCapturedNotifications RPC call.
+//
+// It returns the notifications kept by the capture targets, oldest first.
+func (s *AdminServer) ListCapturedNotifications(
+	_ context.Context,
+	_ *way.ListCapturedNotificationsRequest,
+) (*way.ListCapturedNotificationsResponse, error) {
+	notifications := s.captured.List()
+
+	resp := &way.ListCapturedNotificationsResponse{
+		Notifications: make([]*way.CapturedNotification, 0, len(notifications)),
+	}
+
+	for _, notification := range notifications {
+		high, low := uuidconv.UUID2DoubleInt(notification.Event.ID)
+		resp.Notifications = append(resp.Notifications, &way.CapturedNotification{
+			Time:    timestamppb.New(notification.At),
+			Target:  notification.Target,
+			Id:      &apiv1.UUID{High: high, Low: low},
+			Status:  notification.Event.Status.String(),
+			Message: notification.Event.Message,
+		})
+	}
+
+	return resp, nil
+}
+
+// ListHeartbeats handles the ListHeartbeats RPC call.
+//
+// It returns the latest heartbeats recorded by the audit log, oldest first,
+// optionally of a single service. A FailedPrecondition error is returned if
+// the audit log is disabled.
+func (s *AdminServer) ListHeartbeats(
+	_ context.Context,
+	req *way.ListHeartbeatsRequest,
+) (*way.ListHeartbeatsResponse, error) {
+	if !s.heartbeats.Enabled() {
+		return nil, status.Error(codes.FailedPrecondition, "audit log is disabled")
+	}
+
+	// The heartbeats of every service are listed if the UUID is not set.
+	id := uuid.Nil
+	if req.GetId() != nil {
+		id = uuidconv.DoubleInt2UUID(req.GetId().GetHigh(), req.GetId().GetLow())
+	}
+
+	records := s.heartbeats.List(id, int(req.GetLimit()))
+
+	resp := &way.ListHeartbeatsResponse{Heartbeats: make([]*way.HeartbeatRecord, 0, len(records))}
+	for _, record := range records {
+		high, low := uuidconv.UUID2DoubleInt(record.ID)
+		resp.Heartbeats = append(resp.Heartbeats, &way.HeartbeatRecord{
+			Id:     &apiv1.UUID{High: high, Low: low},
+			Time:   timestamppb.New(record.At),
+			Peer:   record.Peer,
+			Token:  record.Token,
+			Agent:  record.Agent,
+			Result: record.Result,
+		})
+	}
+
+	return resp, nil
+}
+
+// SetStatus handles the SetStatus RPC call.
+//
+// It forces the status of a configured service. An InvalidArgument error is
+// returned if the UUID or the status is invalid, and a NotFound error if the
+// service is not configured.
+func (s *AdminServer) SetStatus(ctx context.Context, req *way.SetStatusRequest) (*way.SetStatusResponse, error) {
+	ids, err := validate([]*apiv1.UUID{req.GetId()}, s.services, true)
+	if err != nil {
+		return nil, err
+	}
+
+	next, err := entities.ParseStatus(req.GetStatus())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	resp := &way.SetStatusResponse{Status: next.String()}
+	if previous, ok := s.states.Status(ids[0]); ok {
+		resp.PreviousStatus = previous.Status.String()
+	}
+
+	logger := zerolog.Ctx(ctx)
+
+	// The status is recorded even if a target cannot be notified, and the
+	// failed deliveries are retried, so the failure is not reported to the caller.
+	if err := s.states.Send(ctx, ids[0], next); err != nil {
+		logger.Warn().Err(err).Str("id", ids[0].String()).Msg("Failed to notify the targets of the forced status")
+	}
+
+	logger.Info().
+		Str("id", ids[0].String()).
+		Str("status", resp.GetStatus()).
+		Msg("Status forced")
+
+	return resp, nil
+}
+
+// Reload handles the Reload RPC call.
+//
+// It reloads the webhooks from the configuration file. A FailedPrecondition
+// error is returned if the configuration cannot be read or is invalid.
+func (s *AdminServer) Reload(ctx context.Context, _ *way.ReloadRequest) (*way.ReloadResponse, error) {
+	webhooks, err := s.reloader.Reload(ctx)
+	if err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	return &way.ReloadResponse{Webhooks: uint32(webhooks)}, nil //nolint:gosec
+}
+
+// ListDeadLetters handles the ListDeadLetters RPC call.
+//
+// It returns the notifications that could not be delivered, oldest first.
+func (s *AdminServer) ListDeadLetters(
+	_ context.Context,
+	_ *way.ListDeadLettersRequest,
+) (*way.ListDeadLettersResponse, error) {
+	letters := s.letters.List()
+
+	resp := &way.ListDeadLettersResponse{Letters: make([]*way.DeadLetter, 0, len(letters))}
+	for _, dead := range letters {
+		high, low := uuidconv.UUID2DoubleInt(dead.Event.ID)
+		resp.Letters = append(resp.Letters, &way.DeadLetter{
+			Id:       dead.ID.String(),
+			Service:  &apiv1.UUID{High: high, Low: low},
+			Target:   dead.Target,
+			Status:   dead.Event.Status.String(),
+			Time:     timestamppb.New(dead.Event.Time),
+			Error:    dead.Error,
+			Attempts: uint32(dead.Attempts), //nolint:gosec
+			FailedAt: timestamppb.New(dead.At),
+		})
+	}
+
+	return resp, nil
+}
+
+// ReplayDeadLetter handles the ReplayDeadLetter RPC call.
+//
+// It delivers the dead letter to its target again. An InvalidArgument error is
+// returned if the id is invalid, a NotFound error if there is no such dead
+// letter, a FailedPrecondition error if its target was removed, and an
+// Unavailable error if the target still fails.
+func (s *AdminServer) ReplayDeadLetter(
+	ctx context.Context,
+	req *way.ReplayDeadLetterRequest,
+) (*way.ReplayDeadLetterResponse, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.letters.Replay(ctx, id); err != nil {
+		switch {
+		case errors.Is(err, services.ErrDeadLetterNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, services.ErrTargetNotFound):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		default:
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+	}
+
+	zerolog.Ctx(ctx).Info().Str("letter", id.String()).Msg("Dead letter replayed")
+
+	return &way.ReplayDeadLetterResponse{}, nil
+}
+
+// DiscardDeadLetter handles the DiscardDeadLetter RPC call.
+//
+// It removes the dead letter without delivering it. An InvalidArgument error
+// is returned if the id is invalid, and a NotFound error if there is no such
+// dead letter.
+func (s *AdminServer) DiscardDeadLetter(
+	ctx context.Context,
+	req *way.DiscardDeadLetterRequest,
+) (*way.DiscardDeadLetterResponse, error) {
+	id, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.letters.Discard(id); err != nil {
+		if errors.Is(err, services.ErrDeadLetterNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().Str("letter", id.String()).Msg("Dead letter discarded")
+
+	return &way.DiscardDeadLetterResponse{}, nil
+}
+
+// silenceMessage converts the silence into its protobuf representation.
+func silenceMessage(silence entities.Silence) *way.Silence {
+	msg := &way.Silence{
+		Id:       silence.ID.String(),
+		Ids:      make([]*apiv1.UUID, 0, len(silence.IDs)),
+		Labels:   silence.Labels,
+		StartsAt: timestamppb.New(silence.StartsAt),
+		EndsAt:   timestamppb.New(silence.EndsAt),
+		Comment:  silence.Comment,
+	}
+
+	for _, id := range silence.IDs {
+		high, low := uuidconv.UUID2DoubleInt(id)
+		msg.Ids = append(msg.Ids, &apiv1.UUID{High: high, Low: low})
+	}
+
+	return msg
+}
@@ -0,0 +1,137 @@
+package app
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/auth"
+)
+
+// AlertReceiver represents an interface for mapping the alerts of the Alertmanager webhooks to the services.
+type AlertReceiver interface {
+	// Heartbeats decodes the webhook and returns the heartbeats of the services of its alerts.
+	//
+	// Returns:
+	//   - The heartbeats of the services.
+	//   - The number of the alerts not mapped to any service.
+	//   - An error if the body is not an Alertmanager webhook.
+	Heartbeats(body io.Reader) ([]entities.Heartbeat, int, error)
+}
+
+// HeartbeatSink represents an interface for feeding the heartbeats into the checker.
+type HeartbeatSink interface {
+	// Beat buffers the heartbeat and reports whether it was not dropped.
+	Beat(heartbeat entities.Heartbeat) bool
+}
+
+// NewAlertmanagerHandler creates the handler of the Alertmanager webhooks.
+//
+// Parameters:
+//   - token: The bearer token required by every request, or empty.
+//   - verifier: The TokenVerifier of the JWTs, or nil if they are not accepted.
+//   - receiver: The AlertReceiver mapping the alerts to the services.
+//   - sink: The HeartbeatSink the statuses of the services are fed into.
+//   - services: The ServiceRegistry of the configured services.
+//
+// Returns:
+//   - A pointer to an AlertmanagerHandler.
+//
+//nolint:exhaustruct
+func NewAlertmanagerHandler(
+	token string,
+	verifier TokenVerifier,
+	receiver AlertReceiver,
+	sink HeartbeatSink,
+	services ServiceRegistry,
+) *AlertmanagerHandler {
+	return &AlertmanagerHandler{
+		token:    []byte(token),
+		verifier: verifier,
+		receiver: receiver,
+		sink:     sink,
+		services: services,
+	}
+}
+
+// AlertmanagerHandler receives the Alertmanager webhooks, so the outages
+// detected by Prometheus flow through the notification pipeline.
+type AlertmanagerHandler struct {
+	token    []byte
+	verifier TokenVerifier
+	receiver AlertReceiver
+	sink     HeartbeatSink
+	services ServiceRegistry
+}
+
+// ServeHTTP authenticates the webhook and feeds the statuses of its alerts into the checker.
+//
+// The webhook has to carry the token or a JWT with the report scope, if any
+// of them is accepted. The JWT scoped to a namespace reports the services of
+// the namespace only. It responds with 400 Bad Request if the body is not an
+// Alertmanager webhook and with 503 Service Unavailable if a heartbeat was
+// dropped, so Alertmanager retries the webhook. The alerts of the unknown
+// services are skipped.
+func (h *AlertmanagerHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	services := h.services
+
+	if len(h.token) > 0 || h.verifier != nil {
+		authenticated, err := authenticateBearer(r, h.token, h.verifier)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+
+			return
+		}
+
+		if principal, ok := auth.FromContext(authenticated.Context()); ok {
+			if !principal.HasScope(auth.ScopeReport) {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+
+				return
+			}
+
+			if principal.Namespace != "" {
+				services = scopedRegistry{ServiceRegistry: h.services, namespace: principal.Namespace}
+			}
+		}
+	}
+
+	heartbeats, unmapped, err := h.receiver.Heartbeats(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	logger := zerolog.Ctx(r.Context())
+
+	if unmapped > 0 {
+		logger.Debug().Int("alerts", unmapped).Msg("Alerts not mapped to any service")
+	}
+
+	accepted := 0
+
+	for _, heartbeat := range heartbeats {
+		if !services.Exists(heartbeat.ID) {
+			logger.Debug().Str("id", heartbeat.ID.String()).Msg("Alert of an unknown service")
+
+			continue
+		}
+
+		if !h.sink.Beat(heartbeat) {
+			logger.Warn().Str("id", heartbeat.ID.String()).Msg("Alert dropped")
+			http.Error(w, "the buffer of the heartbeats is full", http.StatusServiceUnavailable)
+
+			return
+		}
+
+		accepted++
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]int{"accepted": accepted, "unmapped": unmapped})
+}
@@ -0,0 +1,103 @@
+package app
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/bavix/apis/pkg/uuidconv"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/peer"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/auth"
+	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+)
+
+// AuditSink represents an interface for recording the received heartbeats.
+type AuditSink interface {
+	// Record records the heartbeat.
+	//
+	// Parameters:
+	//   - record: The received heartbeat.
+	//
+	// Returns:
+	//   - An error if the heartbeat cannot be recorded.
+	Record(record entities.HeartbeatRecord) error
+}
+
+// AuditLog represents an interface for querying the recorded heartbeats.
+type AuditLog interface {
+	// Enabled reports whether the heartbeats are recorded.
+	Enabled() bool
+
+	// List returns the latest recorded heartbeats, oldest first.
+	//
+	// Parameters:
+	//   - id: The UUID of the service, or uuid.Nil for every service.
+	//   - limit: The maximum number of the heartbeats, or zero for all of them.
+	//
+	// Returns:
+	//   - The list of the heartbeats.
+	List(id uuid.UUID, limit int) []entities.HeartbeatRecord
+}
+
+// caller describes the agent of an Update stream in the audit log.
+type caller struct {
+	// peer is the network address of the agent.
+	peer string
+
+	// token is the name of the API token of the agent.
+	token string
+}
+
+// callerOf returns the caller of the stream.
+//
+// Parameters:
+//   - ctx: The context.Context of the stream.
+//
+// Returns:
+//   - The network address and the token name of the agent.
+func callerOf(ctx context.Context) caller {
+	var c caller
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		c.peer = p.Addr.String()
+	}
+
+	if principal, ok := auth.FromContext(ctx); ok {
+		c.token = principal.Name
+	}
+
+	return c
+}
+
+// audit records the heartbeats of a request in the audit log.
+//
+// A failure of the audit log is logged and does not fail the stream.
+//
+// Parameters:
+//   - ctx: The context.Context of the stream, holding the logger.
+//   - from: The caller of the stream.
+//   - agent: The agent introduced in the handshake of the stream.
+//   - results: The results of the heartbeats of the request.
+func (s *GRPCServer) audit(ctx context.Context, from caller, agent entities.Agent, results []*way.UpdateResult) {
+	now := time.Now()
+
+	for _, result := range results {
+		err := s.auditSink.Record(entities.HeartbeatRecord{
+			ID:     uuidconv.DoubleInt2UUID(result.GetId().GetHigh(), result.GetId().GetLow()),
+			At:     now,
+			Peer:   from.peer,
+			Token:  from.token,
+			Agent:  agent.Hostname,
+			Result: strings.ToLower(strings.TrimPrefix(result.GetResult().String(), "RESULT_")),
+		})
+		if err != nil {
+			zerolog.Ctx(ctx).Err(err).Msg("Failed to record the heartbeat in the audit log")
+
+			return
+		}
+	}
+}
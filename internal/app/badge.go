@@ -0,0 +1,223 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// BadgeRegistry represents an interface for retrieving the configuration of the badges.
+type BadgeRegistry interface {
+	// Badge returns the configuration of the uptime badge of the service.
+	//
+	// Parameters:
+	//   - id: The UUID of the service.
+	//
+	// Returns:
+	//   - The configuration of the badge.
+	//   - A boolean indicating whether the service exists.
+	Badge(id uuid.UUID) (entities.Badge, bool)
+}
+
+// UptimeSource represents an interface for retrieving the uptime of the services.
+type UptimeSource interface {
+	// Uptime returns the time the service was up and the time its status was
+	// known within the period.
+	//
+	// Parameters:
+	//   - id: The UUID of the service.
+	//   - from: The beginning of the period.
+	//   - to: The end of the period.
+	//
+	// Returns:
+	//   - The time the service was up.
+	//   - The time the status of the service was known.
+	Uptime(id uuid.UUID, from, to time.Time) (time.Duration, time.Duration)
+}
+
+// NewBadgeHandler creates a new instance of the BadgeHandler struct.
+//
+// Parameters:
+//   - badges: The BadgeRegistry holding the configuration of the badges.
+//   - uptime: The UptimeSource holding the status history.
+//
+// Returns:
+//   - A pointer to a BadgeHandler struct.
+//
+//nolint:exhaustruct
+func NewBadgeHandler(badges BadgeRegistry, uptime UptimeSource) *BadgeHandler {
+	return &BadgeHandler{
+		badges: badges,
+		uptime: uptime,
+	}
+}
+
+// BadgeHandler is an HTTP handler that renders the uptime badges of the services.
+//
+// It serves the GET /badge/{id} endpoint. The label and the theme configured
+// for the service can be overridden with the "label" and "theme" query parameters.
+type BadgeHandler struct {
+	badges BadgeRegistry
+	uptime UptimeSource
+}
+
+// ServeHTTP renders the uptime badge of the service as SVG.
+//
+// It responds with 400 if the UUID or the theme is invalid, and with 404 if
+// the service is unknown.
+func (h *BadgeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(strings.TrimSuffix(r.PathValue("id"), ".svg"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	badge, ok := h.badges.Badge(id)
+	if !ok {
+		http.Error(w, "unknown service", http.StatusNotFound)
+
+		return
+	}
+
+	// Apply the overrides of the query.
+	if label := r.URL.Query().Get("label"); label != "" {
+		badge.Label = label
+	}
+
+	if theme := r.URL.Query().Get("theme"); theme != "" {
+		badge.Theme = theme
+	}
+
+	if err := badge.Validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	// Calculate the uptime over the window.
+	now := time.Now()
+	value, color := "unknown", entities.ColorUnknown
+
+	if up, total := h.uptime.Uptime(id, now.Add(-badge.Window), now); total > 0 {
+		uptime := 100 * float64(up) / float64(total)
+		value, color = formatUptime(uptime), badge.Color(uptime)
+	}
+
+	body, err := renderBadge(badge.Theme, badge.Label, value, color)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+
+		return
+	}
+
+	// The badges are embedded in READMEs and status pages, which must not cache them for long.
+	w.Header().Set("Content-Type", "image/svg+xml")
+	w.Header().Set("Cache-Control", "max-age=60")
+
+	_, _ = w.Write(body)
+}
+
+// formatUptime formats the uptime percentage, keeping the precision of the
+// common SLO thresholds without rounding 99.95% up to 100%.
+func formatUptime(uptime float64) string {
+	const precision = 100
+
+	uptime = float64(int(uptime*precision)) / precision
+
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", uptime), "0"), ".") + "%"
+}
+
+// badgeTheme represents the geometry of a badge theme.
+type badgeTheme struct {
+	// height is the height of the badge in pixels.
+	height int
+
+	// radius is the radius of the corners in pixels.
+	radius int
+
+	// char is the approximate width of a character in pixels.
+	char int
+
+	// padding is the horizontal padding of each side of the badge in pixels.
+	padding int
+
+	// size is the font size in pixels.
+	size int
+
+	// upper defines whether the text is uppercase.
+	upper bool
+}
+
+// badgeThemes are the supported themes of the badges.
+//
+//nolint:gochecknoglobals,mnd
+var badgeThemes = map[string]badgeTheme{
+	entities.ThemeFlat:        {height: 20, radius: 3, char: 7, padding: 6, size: 11, upper: false},
+	entities.ThemeFlatSquare:  {height: 20, radius: 0, char: 7, padding: 6, size: 11, upper: false},
+	entities.ThemeForTheBadge: {height: 28, radius: 0, char: 9, padding: 12, size: 10, upper: true},
+}
+
+// errBadgeTheme is returned when the theme has no geometry.
+var errBadgeTheme = errors.New("badge theme has no geometry")
+
+// badgeTemplate is the SVG template of the badges.
+var badgeTemplate = template.Must(template.New("badge").Parse(
+	`<svg xmlns="http://www.w3.org/2000/svg" width="{{.Width}}" height="{{.Height}}" role="img" aria-label="{{.Label}}: {{.Value}}">` +
+		`<title>{{.Label}}: {{.Value}}</title>` +
+		`<clipPath id="r"><rect width="{{.Width}}" height="{{.Height}}" rx="{{.Radius}}" fill="#fff"/></clipPath>` +
+		`<g clip-path="url(#r)">` +
+		`<rect width="{{.LabelWidth}}" height="{{.Height}}" fill="#555"/>` +
+		`<rect x="{{.LabelWidth}}" width="{{.ValueWidth}}" height="{{.Height}}" fill="{{.Color}}"/>` +
+		`</g>` +
+		`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="{{.Size}}">` +
+		`<text x="{{.LabelX}}" y="{{.TextY}}">{{.Label}}</text>` +
+		`<text x="{{.ValueX}}" y="{{.TextY}}">{{.Value}}</text>` +
+		`</g></svg>`,
+))
+
+// renderBadge renders the badge as SVG.
+//
+// The text is escaped by html/template, so the labels from the query are safe.
+func renderBadge(theme, label, value, color string) ([]byte, error) {
+	geometry, ok := badgeThemes[theme]
+	if !ok {
+		return nil, errBadgeTheme
+	}
+
+	if geometry.upper {
+		label, value = strings.ToUpper(label), strings.ToUpper(value)
+	}
+
+	labelWidth := utf8.RuneCountInString(label)*geometry.char + 2*geometry.padding
+	valueWidth := utf8.RuneCountInString(value)*geometry.char + 2*geometry.padding
+
+	var buf bytes.Buffer
+
+	//nolint:mnd
+	err := badgeTemplate.Execute(&buf, map[string]any{
+		"Width":      labelWidth + valueWidth,
+		"Height":     geometry.height,
+		"Radius":     geometry.radius,
+		"Size":       geometry.size,
+		"LabelWidth": labelWidth,
+		"ValueWidth": valueWidth,
+		"LabelX":     labelWidth / 2,
+		"ValueX":     labelWidth + valueWidth/2,
+		"TextY":      geometry.height/2 + geometry.size/3,
+		"Label":      label,
+		"Value":      value,
+		"Color":      color,
+	})
+
+	return buf.Bytes(), err
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bavix/vakeel-way/internal/infra/auth"
+)
+
+// TokenVerifier represents an interface for verifying the signed tokens of the HTTP callers.
+type TokenVerifier interface {
+	// Verify checks the token and returns the caller.
+	//
+	// Returns:
+	//   - The principal identified by the token.
+	//   - An error if the token is not accepted.
+	Verify(ctx context.Context, token string) (auth.Principal, error)
+}
+
+// SessionAuthenticator represents an interface for authenticating the browser sessions of the team.
+type SessionAuthenticator interface {
+	// Principal returns the user signed in by the session cookie of the request.
+	//
+	// Returns:
+	//   - The principal of the user.
+	//   - Whether the request carries a valid session.
+	Principal(r *http.Request) (auth.Principal, bool)
+
+	// LoginURL returns the path starting a login that returns to the page.
+	LoginURL(next string) string
+}
+
+// authenticateBearer authenticates the bearer token of the HTTP request.
+//
+// The static token grants every scope and leaves the request anonymous. A
+// signed token is verified by the TokenVerifier, and the principal it
+// identifies is attached to the context of the request, so its namespace
+// scopes the calls.
+//
+// Parameters:
+//   - r: The HTTP request.
+//   - token: The static token, or empty if none is accepted.
+//   - verifier: The TokenVerifier of the signed tokens, or nil if none are accepted.
+//
+// Returns:
+//   - The request, with the principal attached to its context if any.
+//   - An Unauthenticated status error if the token is not accepted.
+func authenticateBearer(r *http.Request, token []byte, verifier TokenVerifier) (*http.Request, error) {
+	bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || bearer == "" {
+		return r, status.Error(codes.Unauthenticated, "invalid or missing bearer token")
+	}
+
+	if len(token) > 0 && subtle.ConstantTimeCompare([]byte(bearer), token) == 1 {
+		return r, nil
+	}
+
+	if verifier == nil {
+		return r, status.Error(codes.Unauthenticated, "invalid or missing bearer token")
+	}
+
+	principal, err := verifier.Verify(r.Context(), bearer)
+	if err != nil {
+		return r, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	return r.WithContext(auth.WithPrincipal(r.Context(), principal)), nil
+}
+
+// requireScope rejects the request if its caller was not granted the scope.
+//
+// Parameters:
+//   - scope: The scope required by the handler.
+//   - handler: The handler of the request.
+//
+// Returns:
+//   - The handler responding with 403 Forbidden to the callers without the scope.
+func requireScope(scope string, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if principal, ok := auth.FromContext(r.Context()); ok && !principal.HasScope(scope) {
+			writeError(w, status.Errorf(codes.PermissionDenied, "%s: the %s scope is required", auth.ErrMissingScope, scope))
+
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+// sameOrigin reports whether the request was sent by a page of the server itself.
+//
+// The browsers send the Origin header with every request changing the state,
+// so a request authenticated by the session cookie is rejected unless it comes
+// from the same origin, and another site cannot forge it.
+func sameOrigin(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+
+	origin, err := url.Parse(r.Header.Get("Origin"))
+
+	return err == nil && origin.Host != "" && origin.Host == r.Host
+}
+
+// wantsHTML reports whether the request was sent by a browser navigating to the page.
+func wantsHTML(r *http.Request) bool {
+	return r.Method == http.MethodGet && strings.Contains(r.Header.Get("Accept"), "text/html")
+}
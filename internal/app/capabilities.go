@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+)
+
+var _ = way.CapabilityServiceServer(&CapabilityServer{}) //nolint:exhaustruct
+
+// NewCapabilityServer creates a new instance of the CapabilityServer struct.
+//
+// Parameters:
+//   - caps: The entities.Capabilities of the instance.
+//
+// Returns:
+//   - A pointer to a CapabilityServer struct.
+//
+//nolint:exhaustruct
+func NewCapabilityServer(caps entities.Capabilities) *CapabilityServer {
+	return &CapabilityServer{
+		caps: caps,
+	}
+}
+
+// CapabilityServer is a gRPC server implementation that provides the
+// CapabilityService RPC service. It implements the way.CapabilityServiceServer
+// interface.
+type CapabilityServer struct {
+	caps entities.Capabilities
+
+	way.UnimplementedCapabilityServiceServer
+}
+
+// GetCapabilities handles the GetCapabilities RPC call.
+//
+// It returns the features enabled on the instance.
+func (s *CapabilityServer) GetCapabilities(
+	_ context.Context,
+	_ *way.GetCapabilitiesRequest,
+) (*way.Capabilities, error) {
+	return &way.Capabilities{
+		Version:          s.caps.Version,
+		ProtocolVersions: s.caps.Protocols,
+		Notifiers:        s.caps.Notifiers,
+		Storage:          s.caps.Storage,
+		AuthMode:         s.caps.Auth,
+		Features:         s.caps.Features,
+	}, nil
+}
+
+// ServeHTTP returns the features enabled on the instance as JSON.
+func (s *CapabilityServer) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	_ = json.NewEncoder(w).Encode(s.caps)
+}
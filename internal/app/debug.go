@@ -0,0 +1,44 @@
+package app
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	rpprof "runtime/pprof"
+	"strconv"
+)
+
+// NewDebugHandler creates the handler of the debug listener.
+//
+// The handler serves the profiles of net/http/pprof under /debug/pprof/, the
+// variables of expvar on /debug/vars, and a dump of the stacks of every
+// goroutine on /debug/goroutines.
+//
+// Returns:
+//   - The http.Handler of the debug listener.
+func NewDebugHandler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("GET /debug/vars", expvar.Handler())
+	mux.HandleFunc("GET /debug/goroutines", dumpGoroutines)
+
+	return mux
+}
+
+// dumpGoroutines writes the stacks of every goroutine as plain text, in the
+// format of an unrecovered panic.
+func dumpGoroutines(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Goroutines", strconv.Itoa(runtime.NumGoroutine()))
+
+	// The debug level 2 prints the stacks like an unrecovered panic.
+	const debugLevel = 2
+
+	_ = rpprof.Lookup("goroutine").WriteTo(w, debugLevel)
+}
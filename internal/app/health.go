@@ -0,0 +1,99 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// HealthChecker represents an interface for checking the readiness of the instance.
+type HealthChecker interface {
+	// Check runs the readiness probes.
+	//
+	// Returns:
+	//   - The results of the probes.
+	//   - A boolean indicating whether every probe succeeded.
+	Check() ([]entities.Probe, bool)
+}
+
+// NewHealthHandler creates the handler of the liveness and readiness probes.
+//
+// Parameters:
+//   - checker: The HealthChecker of the readiness probes.
+//
+// Returns:
+//   - A pointer to a HealthHandler.
+//
+//nolint:exhaustruct
+func NewHealthHandler(checker HealthChecker) *HealthHandler {
+	return &HealthHandler{checker: checker}
+}
+
+// HealthHandler serves the liveness and readiness probes, so the orchestrators
+// can probe the instance over HTTP.
+type HealthHandler struct {
+	checker HealthChecker
+}
+
+// health is the JSON document of the probes.
+type health struct {
+	Status string        `json:"status"`
+	Checks []healthCheck `json:"checks,omitempty"`
+}
+
+// healthCheck is the result of a readiness probe in the JSON document.
+type healthCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Live handles the liveness probe.
+//
+// The instance is alive as long as it answers, so the probe always succeeds.
+func (h *HealthHandler) Live(w http.ResponseWriter, _ *http.Request) {
+	writeHealth(w, http.StatusOK, health{Status: "ok", Checks: nil})
+}
+
+// Ready handles the readiness probe.
+//
+// The probe fails with 503 Service Unavailable if any dependency is not
+// ready, so the instance is taken out of the load balancing until it recovers.
+func (h *HealthHandler) Ready(w http.ResponseWriter, _ *http.Request) {
+	probes, ready := h.checker.Check()
+
+	doc := health{Status: "ok", Checks: make([]healthCheck, 0, len(probes))}
+	code := http.StatusOK
+
+	if !ready {
+		doc.Status = "unavailable"
+		code = http.StatusServiceUnavailable
+	}
+
+	for _, probe := range probes {
+		check := healthCheck{Name: probe.Name, Status: "ok", Error: ""}
+		if probe.Err != nil {
+			check.Status = "unavailable"
+			check.Error = probe.Err.Error()
+		}
+
+		doc.Checks = append(doc.Checks, check)
+	}
+
+	writeHealth(w, code, doc)
+}
+
+// writeHealth writes the JSON document of the probes.
+//
+// Parameters:
+//   - w: The http.ResponseWriter.
+//   - code: The HTTP status code.
+//   - doc: The document to write.
+func writeHealth(w http.ResponseWriter, code int, doc health) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+
+	_ = json.NewEncoder(w).Encode(doc)
+}
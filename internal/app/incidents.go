@@ -0,0 +1,187 @@
+package app
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	apiv1 "github.com/bavix/apis/pkg/bavix/api/v1"
+	"github.com/bavix/apis/pkg/uuidconv"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+)
+
+// IncidentSource represents an interface for retrieving the recorded incidents.
+type IncidentSource interface {
+	// Incidents returns the incidents matching the filter, most recent first.
+	//
+	// Parameters:
+	//   - filter: The criteria of the incidents.
+	//
+	// Returns:
+	//   - The matching incidents.
+	Incidents(filter entities.IncidentFilter) []entities.Incident
+}
+
+// ListIncidents handles the ListIncidents RPC call.
+//
+// It returns the incidents of the services matching the filters, most recent
+// first. If the caller is scoped to a namespace, only the incidents of the
+// services of the namespace are returned. The incidents are returned page by
+// page: the token of the next page is the start and the UUID of the last
+// incident of the page, so the pages stay consistent when new incidents are
+// recorded in between.
+//
+// Parameters:
+//   - ctx: The context.Context of the call.
+//   - req: The ListIncidentsRequest with the filters and the page.
+//
+// Returns:
+//   - A ListIncidentsResponse with the incidents of the page.
+//   - An InvalidArgument error if a UUID, the page size or the page token is invalid.
+func (s *GRPCServer) ListIncidents(
+	ctx context.Context,
+	req *way.ListIncidentsRequest,
+) (*way.ListIncidentsResponse, error) {
+	size := int(req.GetPageSize())
+
+	switch {
+	case size < 0:
+		return nil, status.Error(codes.InvalidArgument, "the page size must not be negative")
+	case size == 0:
+		size = defaultPageSize
+	case size > maxPageSize:
+		size = maxPageSize
+	}
+
+	after, afterID, err := parseIncidentToken(req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+
+	registry, scoped := s.registry(ctx)
+
+	ids, err := validate(req.GetIds(), registry, false)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := entities.IncidentFilter{
+		IDs:         ids,
+		From:        time.Time{},
+		To:          time.Time{},
+		MinDuration: req.GetMinDuration().AsDuration(),
+	}
+
+	if req.GetFrom() != nil {
+		filter.From = req.GetFrom().AsTime()
+	}
+
+	if req.GetTo() != nil {
+		filter.To = req.GetTo().AsTime()
+	}
+
+	now := time.Now()
+	resp := &way.ListIncidentsResponse{}
+
+	for _, incident := range s.incidents.Incidents(filter) {
+		// Skip the incidents of the previous pages.
+		if !after.IsZero() && (incident.Start.After(after) ||
+			incident.Start.Equal(after) && incident.ID.String() <= afterID.String()) {
+			continue
+		}
+
+		// Hide the incidents of the services of the other namespaces.
+		if scoped && !registry.Exists(incident.ID) {
+			continue
+		}
+
+		// Stop once the page is full and there is another matching incident.
+		if len(resp.Incidents) == size {
+			previous := resp.Incidents[size-1]
+			resp.NextPageToken = incidentToken(previous.GetStarted().AsTime(),
+				uuidconv.DoubleInt2UUID(previous.GetId().GetHigh(), previous.GetId().GetLow()))
+
+			break
+		}
+
+		resp.Incidents = append(resp.Incidents, newIncident(incident, now))
+	}
+
+	return resp, nil
+}
+
+// newIncident returns the Incident message of the incident.
+func newIncident(incident entities.Incident, now time.Time) *way.Incident {
+	high, low := uuidconv.UUID2DoubleInt(incident.ID)
+	msg := &way.Incident{
+		Id:       &apiv1.UUID{High: high, Low: low},
+		Started:  timestamppb.New(incident.Start),
+		Ongoing:  incident.Ongoing(),
+		Duration: durationpb.New(incident.Duration(now)),
+	}
+
+	if !incident.Ongoing() {
+		msg.Resolved = timestamppb.New(incident.End)
+	}
+
+	return msg
+}
+
+// incidentEntity converts the Incident message back into the incident.
+func incidentEntity(msg *way.Incident) entities.Incident {
+	incident := entities.Incident{
+		ID:    uuidconv.DoubleInt2UUID(msg.GetId().GetHigh(), msg.GetId().GetLow()),
+		Start: msg.GetStarted().AsTime(),
+		End:   time.Time{},
+	}
+
+	if !msg.GetOngoing() {
+		incident.End = msg.GetResolved().AsTime()
+	}
+
+	return incident
+}
+
+// incidentToken returns the page token following the incident.
+func incidentToken(start time.Time, id uuid.UUID) string {
+	return strconv.FormatInt(start.UnixNano(), 10) + "_" + id.String()
+}
+
+// parseIncidentToken parses the page token of the incidents.
+//
+// Returns:
+//   - The start and the UUID of the last incident of the previous page, or
+//     the zero values for the first page.
+//   - An InvalidArgument error if the token is malformed.
+func parseIncidentToken(token string) (time.Time, uuid.UUID, error) {
+	if token == "" {
+		return time.Time{}, uuid.Nil, nil
+	}
+
+	invalid := status.Error(codes.InvalidArgument, "the page token is invalid")
+
+	nanos, rawID, ok := strings.Cut(token, "_")
+	if !ok {
+		return time.Time{}, uuid.Nil, invalid
+	}
+
+	value, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return time.Time{}, uuid.Nil, invalid
+	}
+
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return time.Time{}, uuid.Nil, invalid
+	}
+
+	return time.Unix(0, value), id, nil
+}
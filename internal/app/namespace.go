@@ -0,0 +1,87 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/auth"
+)
+
+// ErrOtherNamespace is an error that indicates that the service belongs to another namespace.
+var ErrOtherNamespace = errors.New("app: the service belongs to another namespace")
+
+// scopedRegistry is a ServiceRegistry that sees the services of a single namespace.
+//
+// The services of the other namespaces are reported as not configured, so a
+// team can neither report, nor query, nor watch the services of another team.
+type scopedRegistry struct {
+	ServiceRegistry
+
+	// namespace is the namespace of the caller.
+	namespace string
+}
+
+// Exists reports whether the service is configured in the namespace.
+func (r scopedRegistry) Exists(id uuid.UUID) bool {
+	return r.ServiceRegistry.Exists(id) && r.visible(id)
+}
+
+// All returns the UUIDs of the services configured in the namespace.
+func (r scopedRegistry) All() []uuid.UUID {
+	all := r.ServiceRegistry.All()
+	ids := all[:0]
+
+	for _, id := range all {
+		if r.visible(id) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
+
+// Labels returns the labels of the service, or nil if it belongs to another namespace.
+func (r scopedRegistry) Labels(id uuid.UUID) map[string]string {
+	if !r.visible(id) {
+		return nil
+	}
+
+	return r.ServiceRegistry.Labels(id)
+}
+
+// Get returns the targets of the service, or ErrOtherNamespace if it belongs to another namespace.
+func (r scopedRegistry) Get(ctx context.Context, id uuid.UUID) ([]entities.Target, error) {
+	if !r.visible(id) {
+		return nil, ErrOtherNamespace
+	}
+
+	return r.ServiceRegistry.Get(ctx, id)
+}
+
+// visible reports whether the service belongs to the namespace.
+func (r scopedRegistry) visible(id uuid.UUID) bool {
+	return r.ServiceRegistry.Namespace(id) == r.namespace
+}
+
+// registry returns the ServiceRegistry of the services visible to the caller.
+//
+// The callers authenticated with a token scoped to a namespace see the
+// services of the namespace only. The other callers see every service.
+//
+// Parameters:
+//   - ctx: The context.Context of the call, carrying the principal.
+//
+// Returns:
+//   - The ServiceRegistry of the caller.
+//   - A boolean indicating whether the caller is scoped to a namespace.
+func (s *GRPCServer) registry(ctx context.Context) (ServiceRegistry, bool) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || principal.Namespace == "" {
+		return s.services, false
+	}
+
+	return scopedRegistry{ServiceRegistry: s.services, namespace: principal.Namespace}, true
+}
@@ -0,0 +1,179 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/domain/usecases"
+)
+
+// ErrInvalidMessage is an error that indicates that a heartbeat message consumed from NATS is invalid.
+var ErrInvalidMessage = errors.New("nats: invalid heartbeat message")
+
+// natsMessage is a heartbeat message consumed from NATS.
+//
+// The message is either a bare UUID, or a JSON object with the UUIDs of the
+// services and optionally the status, the message, the TTL and the RFC 3339
+// time the heartbeat was sent at, e.g.
+// {"ids": ["..."], "status": "degraded", "message": "slow disk", "ttl": "5m"}.
+type natsMessage struct {
+	ID       uuid.UUID   `json:"id"`
+	IDs      []uuid.UUID `json:"ids"`
+	Status   string      `json:"status"`
+	Message  string      `json:"message"`
+	TTL      string      `json:"ttl"`
+	Hostname string      `json:"hostname"`
+	Version  string      `json:"version"`
+	Time     time.Time   `json:"time"`
+}
+
+// natsResult is the result of a heartbeat sent back to the publisher.
+type natsResult struct {
+	ID     uuid.UUID `json:"id"`
+	Result string    `json:"result"`
+}
+
+// natsReply is the reply sent to the publishers using the request-reply pattern.
+type natsReply struct {
+	Results []natsResult `json:"results,omitempty"`
+	Error   string       `json:"error,omitempty"`
+}
+
+// NewNATSConsumer creates a new instance of the NATSConsumer struct.
+//
+// Parameters:
+//   - checker: A *usecases.Checker used to send the heartbeats to the checker.
+//   - services: A ServiceRegistry used to tell the unknown UUIDs.
+//   - directory: An AgentDirectory used to keep the publishers introduced in the messages.
+//
+// Returns:
+//   - A pointer to a NATSConsumer.
+func NewNATSConsumer(checker *usecases.Checker, services ServiceRegistry, directory AgentDirectory) *NATSConsumer {
+	return &NATSConsumer{
+		checker:   checker,
+		services:  services,
+		directory: directory,
+	}
+}
+
+// NATSConsumer feeds the heartbeat messages consumed from NATS into the
+// checker, for the agents publishing to NATS instead of opening an Update stream.
+type NATSConsumer struct {
+	checker   *usecases.Checker
+	services  ServiceRegistry
+	directory AgentDirectory
+}
+
+// Handle records the heartbeats of the message.
+//
+// The heartbeats of the unknown services are not recorded, and an invalid
+// message is rejected as a whole.
+//
+// Parameters:
+//   - data: The payload of the message.
+//
+// Returns:
+//   - The JSON reply for the publishers using the request-reply pattern.
+//   - An error if the message is invalid.
+func (c *NATSConsumer) Handle(data []byte) ([]byte, error) {
+	beats, agent, err := decodeNATS(data)
+	if err != nil {
+		reply, _ := json.Marshal(natsReply{Error: err.Error()}) //nolint:exhaustruct,errchkjson
+
+		return reply, err
+	}
+
+	results := make([]natsResult, 0, len(beats))
+
+	for _, beat := range beats {
+		result := "accepted"
+
+		switch {
+		case !c.services.Exists(beat.ID):
+			// The heartbeats of the unknown services are not recorded.
+			result = "unknown"
+		case !c.checker.Beat(beat):
+			result = "throttled"
+		case !agent.Empty():
+			c.directory.Report(beat.ID, agent)
+		}
+
+		results = append(results, natsResult{ID: beat.ID, Result: result})
+	}
+
+	reply, err := json.Marshal(natsReply{Results: results}) //nolint:exhaustruct
+
+	return reply, err
+}
+
+// decodeNATS decodes the heartbeats of the message.
+//
+// Parameters:
+//   - data: The payload of the message.
+//
+// Returns:
+//   - The heartbeats of the message.
+//   - The agent introduced in the message, if any.
+//   - An error if the message is invalid.
+//
+//nolint:exhaustruct
+func decodeNATS(data []byte) ([]entities.Heartbeat, entities.Agent, error) {
+	payload := strings.TrimSpace(string(data))
+
+	// A bare UUID is a heartbeat with the status Up.
+	if !strings.HasPrefix(payload, "{") {
+		id, err := uuid.Parse(payload)
+		if err != nil {
+			return nil, entities.Agent{}, fmt.Errorf("%w: the UUID %q is invalid", ErrInvalidMessage, payload)
+		}
+
+		return []entities.Heartbeat{{ID: id, Status: entities.Up}}, entities.Agent{}, nil
+	}
+
+	var msg natsMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, entities.Agent{}, fmt.Errorf("%w: %w", ErrInvalidMessage, err)
+	}
+
+	ids := msg.IDs
+	if msg.ID != uuid.Nil {
+		ids = append([]uuid.UUID{msg.ID}, ids...)
+	}
+
+	if len(ids) == 0 {
+		return nil, entities.Agent{}, fmt.Errorf("%w: no UUID is given", ErrInvalidMessage)
+	}
+
+	status := entities.Up
+
+	switch msg.Status {
+	case "", entities.Up.String():
+	case entities.Degraded.String():
+		status = entities.Degraded
+	default:
+		return nil, entities.Agent{}, fmt.Errorf("%w: the status %q is unknown", ErrInvalidMessage, msg.Status)
+	}
+
+	var ttl time.Duration
+
+	if msg.TTL != "" {
+		var err error
+
+		if ttl, err = time.ParseDuration(msg.TTL); err != nil || ttl < 0 {
+			return nil, entities.Agent{}, fmt.Errorf("%w: the TTL %q is invalid", ErrInvalidMessage, msg.TTL)
+		}
+	}
+
+	beats := make([]entities.Heartbeat, 0, len(ids))
+	for _, id := range ids {
+		beats = append(beats, entities.Heartbeat{ID: id, Status: status, Message: msg.Message, TTL: ttl, RequestID: "", Sent: msg.Time})
+	}
+
+	return beats, entities.Agent{Hostname: msg.Hostname, Version: msg.Version}, nil
+}
@@ -0,0 +1,75 @@
+package app
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+)
+
+// throttleDelay is the time the agents are asked to hold back their
+// heartbeats for when the server drops them.
+const throttleDelay = time.Second
+
+// NoticeSource represents an interface for subscribing to the notices of the server.
+type NoticeSource interface {
+	// Subscribe registers a connected agent.
+	//
+	// Returns:
+	//   - The channel of the notices.
+	//   - The function that unsubscribes the agent.
+	Subscribe() (<-chan entities.Notice, func())
+}
+
+// announce sends the notices to the agent until the stream ends.
+//
+// Parameters:
+//   - ctx: The context.Context of the stream.
+//   - notices: The channel of the notices.
+//   - send: The function that sends a response on the stream.
+func announce(ctx context.Context, notices <-chan entities.Notice, send func(*way.UpdateResponse) error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case notice := <-notices:
+			if err := send(&way.UpdateResponse{Notice: noticeMessage(notice)}); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// throttled reports whether any heartbeat was dropped because the server is overloaded.
+//
+// Parameters:
+//   - results: The results of the heartbeats of a request.
+func throttled(results []*way.UpdateResult) bool {
+	return slices.ContainsFunc(results, func(result *way.UpdateResult) bool {
+		return result.GetResult() == way.UpdateResult_RESULT_THROTTLED
+	})
+}
+
+// noticeMessage converts the notice into its Notice message.
+//
+// Parameters:
+//   - notice: The notice of the server.
+//
+// Returns:
+//   - The Notice message.
+func noticeMessage(notice entities.Notice) *way.Notice {
+	msg := &way.Notice{Reason: notice.Reason}
+
+	switch notice.Kind {
+	case entities.NoticeThrottle:
+		msg.Kind = &way.Notice_Throttle{Throttle: &way.Throttle{Delay: durationpb.New(notice.Delay)}}
+	case entities.NoticeShutdown:
+		msg.Kind = &way.Notice_Shutdown{Shutdown: &way.Shutdown{ReconnectAfter: durationpb.New(notice.Delay)}}
+	}
+
+	return msg
+}
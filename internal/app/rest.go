@@ -0,0 +1,616 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	apiv1 "github.com/bavix/apis/pkg/bavix/api/v1"
+	"github.com/bavix/apis/pkg/uuidconv"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/auth"
+	"github.com/bavix/vakeel-way/internal/infra/export"
+	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+)
+
+// maxBodySize is the maximum size of the body of an admin REST request.
+const maxBodySize = 1 << 20
+
+// NewAdminHandler creates the handler of the admin REST API.
+//
+// The admin REST API mirrors the admin gRPC service, so the instance can be
+// operated with curl. Every request has to carry the token or, if the JWTs are
+// accepted, a JWT as a bearer token. The status queries require the read
+// scope of the JWT, and the other calls the admin scope. The bodies and the responses are the JSON mapping of the gRPC messages, and
+// the gRPC status codes are mapped to the HTTP status codes.
+//
+// If the OIDC login is enabled, a request without a bearer token may be
+// authenticated by the session cookie of a member of the team instead, who is
+// granted every scope. The browsers navigating to the API without a session
+// are redirected to the login.
+//
+// Parameters:
+//   - token: The bearer token required by every request, or empty if only the JWTs are accepted.
+//   - verifier: The TokenVerifier of the JWTs, or nil if they are not accepted.
+//   - sessions: The SessionAuthenticator of the OIDC logins, or nil if they are disabled.
+//   - state: The GRPCServer answering the status queries.
+//   - admin: The AdminServer handling the administrative calls.
+//
+// Returns:
+//   - A pointer to an AdminHandler.
+//
+//nolint:exhaustruct
+func NewAdminHandler(
+	token string,
+	verifier TokenVerifier,
+	sessions SessionAuthenticator,
+	state *GRPCServer,
+	admin *AdminServer,
+) *AdminHandler {
+	h := &AdminHandler{
+		token:    []byte(token),
+		verifier: verifier,
+		sessions: sessions,
+		state:    state,
+		admin:    admin,
+		mux:      http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("GET /api/v1/services", requireScope(auth.ScopeRead, h.listServices))
+	h.mux.HandleFunc("GET /api/v1/services/{id}", requireScope(auth.ScopeRead, h.getStatus))
+	h.mux.HandleFunc("PUT /api/v1/services/{id}/status", requireScope(auth.ScopeAdmin, h.setStatus))
+	h.mux.HandleFunc("GET /api/v1/services/{id}/uptime", requireScope(auth.ScopeRead, h.getUptime))
+	h.mux.HandleFunc("GET /api/v1/incidents", requireScope(auth.ScopeRead, h.listIncidents))
+	h.mux.HandleFunc("GET /api/v1/report", requireScope(auth.ScopeRead, h.exportDowntimes))
+	h.mux.HandleFunc("GET /api/v1/silences", requireScope(auth.ScopeAdmin, h.listSilences))
+	h.mux.HandleFunc("POST /api/v1/silences", requireScope(auth.ScopeAdmin, h.createSilence))
+	h.mux.HandleFunc("DELETE /api/v1/silences/{id}", requireScope(auth.ScopeAdmin, h.deleteSilence))
+	h.mux.HandleFunc("GET /api/v1/stale", requireScope(auth.ScopeAdmin, h.listStale))
+	h.mux.HandleFunc("GET /api/v1/captured", requireScope(auth.ScopeAdmin, h.listCaptured))
+	h.mux.HandleFunc("GET /api/v1/heartbeats", requireScope(auth.ScopeAdmin, h.listHeartbeats))
+	h.mux.HandleFunc("GET /api/v1/dead-letters", requireScope(auth.ScopeAdmin, h.listDeadLetters))
+	h.mux.HandleFunc("POST /api/v1/dead-letters/{id}/replay", requireScope(auth.ScopeAdmin, h.replayDeadLetter))
+	h.mux.HandleFunc("DELETE /api/v1/dead-letters/{id}", requireScope(auth.ScopeAdmin, h.discardDeadLetter))
+	h.mux.HandleFunc("POST /api/v1/promote", requireScope(auth.ScopeAdmin, h.promote))
+	h.mux.HandleFunc("POST /api/v1/reload", requireScope(auth.ScopeAdmin, h.reload))
+
+	return h
+}
+
+// AdminHandler is the HTTP handler of the admin REST API.
+type AdminHandler struct {
+	token    []byte
+	verifier TokenVerifier
+	sessions SessionAuthenticator
+	state    *GRPCServer
+	admin    *AdminServer
+	mux      *http.ServeMux
+}
+
+// ServeHTTP authenticates the request and routes it to the admin call.
+func (h *AdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.sessions != nil && r.Header.Get("Authorization") == "" {
+		if principal, ok := h.sessions.Principal(r); ok {
+			if !sameOrigin(r) {
+				writeError(w, status.Error(codes.PermissionDenied, "cross-origin requests are not allowed"))
+
+				return
+			}
+
+			h.mux.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), principal)))
+
+			return
+		}
+
+		if wantsHTML(r) {
+			http.Redirect(w, r, h.sessions.LoginURL(r.URL.RequestURI()), http.StatusFound)
+
+			return
+		}
+	}
+
+	r, err := authenticateBearer(r, h.token, h.verifier)
+	if err != nil {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, err)
+
+		return
+	}
+
+	h.mux.ServeHTTP(w, r)
+}
+
+// listServices handles GET /api/v1/services.
+//
+// The query accepts page_size, page_token and label=key=value parameters.
+func (h *AdminHandler) listServices(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &way.ListServicesRequest{PageToken: query.Get("page_token")}
+
+	if size := query.Get("page_size"); size != "" {
+		value, err := strconv.ParseInt(size, 10, 32)
+		if err != nil {
+			writeError(w, status.Error(codes.InvalidArgument, "page_size is not a number"))
+
+			return
+		}
+
+		req.PageSize = int32(value)
+	}
+
+	for _, label := range query["label"] {
+		key, value, ok := strings.Cut(label, "=")
+		if !ok {
+			writeError(w, status.Errorf(codes.InvalidArgument, "the label %q is not key=value", label))
+
+			return
+		}
+
+		if req.Labels == nil {
+			req.Labels = make(map[string]string)
+		}
+
+		req.Labels[key] = value
+	}
+
+	respond(w, r, req, h.state.ListServices)
+}
+
+// getStatus handles GET /api/v1/services/{id}.
+func (h *AdminHandler) getStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := pathUUID(r)
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	resp, err := h.state.GetStatus(r.Context(), &way.GetStatusRequest{Ids: []*apiv1.UUID{id}})
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	writeMessage(w, resp.GetStatuses()[0])
+}
+
+// getUptime handles GET /api/v1/services/{id}/uptime.
+func (h *AdminHandler) getUptime(w http.ResponseWriter, r *http.Request) {
+	id, err := pathUUID(r)
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	resp, err := h.state.GetUptime(r.Context(), &way.GetUptimeRequest{Ids: []*apiv1.UUID{id}})
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	writeMessage(w, resp.GetUptimes()[0])
+}
+
+// setStatus handles PUT /api/v1/services/{id}/status.
+//
+// The body is {"status": "up"} or {"status": "down"}.
+func (h *AdminHandler) setStatus(w http.ResponseWriter, r *http.Request) {
+	id, err := pathUUID(r)
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	req := &way.SetStatusRequest{}
+	if err := readMessage(r, req); err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	req.Id = id
+
+	respond(w, r, req, h.admin.SetStatus)
+}
+
+// listIncidents handles GET /api/v1/incidents.
+//
+// The query accepts id parameters, from and to in RFC 3339 format,
+// min_duration as a Go duration, page_size and page_token.
+func (h *AdminHandler) listIncidents(w http.ResponseWriter, r *http.Request) {
+	req, err := incidentsRequest(r.URL.Query())
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	respond(w, r, req, h.state.ListIncidents)
+}
+
+// exportDowntimes handles GET /api/v1/report.
+//
+// The query accepts id parameters, from and to in RFC 3339 format,
+// min_duration as a Go duration and format, csv (the default) or json. The
+// period defaults to the previous calendar month in UTC, so the monthly SLA
+// report can be downloaded without parameters.
+func (h *AdminHandler) exportDowntimes(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		format = export.FormatCSV
+	}
+
+	contentType, err := export.ContentType(format)
+	if err != nil {
+		writeError(w, status.Error(codes.InvalidArgument, err.Error()))
+
+		return
+	}
+
+	req, err := incidentsRequest(query)
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	now := time.Now()
+	from, to := export.PreviousMonth(now.UTC())
+
+	if req.GetFrom() == nil && req.GetTo() == nil {
+		req.From, req.To = timestamppb.New(from), timestamppb.New(to)
+	}
+
+	if req.GetFrom() == nil {
+		req.From = timestamppb.New(time.Unix(0, 0))
+	}
+
+	if req.GetTo() == nil {
+		req.To = timestamppb.New(now)
+	}
+
+	req.PageSize, req.PageToken = maxPageSize, ""
+
+	var incidents []entities.Incident
+
+	for {
+		resp, err := h.state.ListIncidents(r.Context(), req)
+		if err != nil {
+			writeError(w, err)
+
+			return
+		}
+
+		for _, incident := range resp.GetIncidents() {
+			incidents = append(incidents, incidentEntity(incident))
+		}
+
+		if req.PageToken = resp.GetNextPageToken(); req.PageToken == "" {
+			break
+		}
+	}
+
+	rows := export.Downtimes(incidents, req.GetFrom().AsTime(), req.GetTo().AsTime(), now)
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="downtimes-%s-%s.%s"`,
+		req.GetFrom().AsTime().Format(time.DateOnly), req.GetTo().AsTime().Format(time.DateOnly), format))
+
+	_ = export.Write(w, format, rows)
+}
+
+// incidentsRequest converts the query of the incidents.
+//
+// Parameters:
+//   - query: The query with the id, from, to, min_duration, page_size and
+//     page_token parameters.
+//
+// Returns:
+//   - The ListIncidentsRequest.
+//   - An InvalidArgument status error if a parameter is invalid.
+func incidentsRequest(query url.Values) (*way.ListIncidentsRequest, error) {
+	req := &way.ListIncidentsRequest{PageToken: query.Get("page_token")}
+
+	for _, value := range query["id"] {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "the UUID %q is invalid", value)
+		}
+
+		high, low := uuidconv.UUID2DoubleInt(id)
+		req.Ids = append(req.Ids, &apiv1.UUID{High: high, Low: low})
+	}
+
+	for name, field := range map[string]**timestamppb.Timestamp{"from": &req.From, "to": &req.To} {
+		if value := query.Get(name); value != "" {
+			at, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "%s is not an RFC 3339 time", name)
+			}
+
+			*field = timestamppb.New(at)
+		}
+	}
+
+	if value := query.Get("min_duration"); value != "" {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "min_duration is not a duration")
+		}
+
+		req.MinDuration = durationpb.New(duration)
+	}
+
+	if size := query.Get("page_size"); size != "" {
+		value, err := strconv.ParseInt(size, 10, 32)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "page_size is not a number")
+		}
+
+		req.PageSize = int32(value)
+	}
+
+	return req, nil
+}
+
+// listSilences handles GET /api/v1/silences.
+func (h *AdminHandler) listSilences(w http.ResponseWriter, r *http.Request) {
+	respond(w, r, &way.ListSilencesRequest{}, h.admin.ListSilences)
+}
+
+// createSilence handles POST /api/v1/silences.
+//
+// The body is the JSON mapping of the Silence message.
+func (h *AdminHandler) createSilence(w http.ResponseWriter, r *http.Request) {
+	req := &way.Silence{}
+	if err := readMessage(r, req); err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	respond(w, r, req, h.admin.CreateSilence)
+}
+
+// deleteSilence handles DELETE /api/v1/silences/{id}.
+func (h *AdminHandler) deleteSilence(w http.ResponseWriter, r *http.Request) {
+	respond(w, r, &way.DeleteSilenceRequest{Id: r.PathValue("id")}, h.admin.DeleteSilence)
+}
+
+// listStale handles GET /api/v1/stale.
+func (h *AdminHandler) listStale(w http.ResponseWriter, r *http.Request) {
+	respond(w, r, &way.ListStaleWebhooksRequest{}, h.admin.ListStaleWebhooks)
+}
+
+// listCaptured handles GET /api/v1/captured.
+func (h *AdminHandler) listCaptured(w http.ResponseWriter, r *http.Request) {
+	respond(w, r, &way.ListCapturedNotificationsRequest{}, h.admin.ListCapturedNotifications)
+}
+
+// listHeartbeats handles GET /api/v1/heartbeats.
+//
+// The query accepts the id and limit parameters.
+func (h *AdminHandler) listHeartbeats(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	req := &way.ListHeartbeatsRequest{}
+
+	if value := query.Get("id"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			writeError(w, status.Errorf(codes.InvalidArgument, "the UUID %q is invalid", value))
+
+			return
+		}
+
+		high, low := uuidconv.UUID2DoubleInt(id)
+		req.Id = &apiv1.UUID{High: high, Low: low}
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			writeError(w, status.Error(codes.InvalidArgument, "limit is not a number"))
+
+			return
+		}
+
+		req.Limit = uint32(limit)
+	}
+
+	respond(w, r, req, h.admin.ListHeartbeats)
+}
+
+// listDeadLetters handles GET /api/v1/dead-letters.
+func (h *AdminHandler) listDeadLetters(w http.ResponseWriter, r *http.Request) {
+	respond(w, r, &way.ListDeadLettersRequest{}, h.admin.ListDeadLetters)
+}
+
+// replayDeadLetter handles POST /api/v1/dead-letters/{id}/replay.
+func (h *AdminHandler) replayDeadLetter(w http.ResponseWriter, r *http.Request) {
+	respond(w, r, &way.ReplayDeadLetterRequest{Id: r.PathValue("id")}, h.admin.ReplayDeadLetter)
+}
+
+// discardDeadLetter handles DELETE /api/v1/dead-letters/{id}.
+func (h *AdminHandler) discardDeadLetter(w http.ResponseWriter, r *http.Request) {
+	respond(w, r, &way.DiscardDeadLetterRequest{Id: r.PathValue("id")}, h.admin.DiscardDeadLetter)
+}
+
+// promote handles POST /api/v1/promote.
+//
+// The body is {"reason": "..."}, and may be empty.
+func (h *AdminHandler) promote(w http.ResponseWriter, r *http.Request) {
+	req := &way.PromoteRequest{}
+	if err := readMessage(r, req); err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	respond(w, r, req, h.admin.Promote)
+}
+
+// reload handles POST /api/v1/reload.
+func (h *AdminHandler) reload(w http.ResponseWriter, r *http.Request) {
+	respond(w, r, &way.ReloadRequest{}, h.admin.Reload)
+}
+
+// respond calls the gRPC implementation and writes its response.
+//
+// Parameters:
+//   - w: The http.ResponseWriter.
+//   - r: The http.Request, carrying the context of the call.
+//   - req: The request message.
+//   - call: The gRPC implementation.
+func respond[Req, Resp proto.Message](
+	w http.ResponseWriter,
+	r *http.Request,
+	req Req,
+	call func(context.Context, Req) (Resp, error),
+) {
+	resp, err := call(r.Context(), req)
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	writeMessage(w, resp)
+}
+
+// pathUUID converts the {id} path parameter of the request.
+//
+// Parameters:
+//   - r: The http.Request.
+//
+// Returns:
+//   - The UUID message.
+//   - An InvalidArgument status error if the UUID is invalid.
+func pathUUID(r *http.Request) (*apiv1.UUID, error) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "the UUID %q is invalid", r.PathValue("id"))
+	}
+
+	high, low := uuidconv.UUID2DoubleInt(id)
+
+	return &apiv1.UUID{High: high, Low: low}, nil
+}
+
+// readMessage decodes the JSON body of the request into the message.
+//
+// An empty body leaves the message empty.
+//
+// Parameters:
+//   - r: The http.Request.
+//   - msg: The message to decode into.
+//
+// Returns:
+//   - An InvalidArgument status error if the body cannot be decoded.
+func readMessage(r *http.Request, msg proto.Message) error {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if len(body) == 0 {
+		return nil
+	}
+
+	if err := protojson.Unmarshal(body, msg); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return nil
+}
+
+// writeMessage writes the message as JSON.
+//
+// Parameters:
+//   - w: The http.ResponseWriter.
+//   - msg: The message to write.
+func writeMessage(w http.ResponseWriter, msg proto.Message) {
+	body, err := protojson.Marshal(msg)
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
+
+// writeError writes the error as JSON with the HTTP status code matching its gRPC code.
+//
+// Parameters:
+//   - w: The http.ResponseWriter.
+//   - err: The error, usually a gRPC status error.
+func writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus(st.Code()))
+
+	_ = json.NewEncoder(w).Encode(struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}{
+		Code:    st.Code().String(),
+		Message: st.Message(),
+	})
+}
+
+// httpStatus maps the gRPC status code to the HTTP status code.
+//
+// Parameters:
+//   - code: The gRPC status code.
+//
+// Returns:
+//   - The HTTP status code.
+func httpStatus(code codes.Code) int {
+	//nolint:exhaustive
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
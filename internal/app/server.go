@@ -1,21 +1,99 @@
 package app
 
 import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	apiv1 "github.com/bavix/apis/pkg/bavix/api/v1"
 	"github.com/bavix/apis/pkg/uuidconv"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
 	"github.com/bavix/vakeel-way/internal/domain/usecases"
+	"github.com/bavix/vakeel-way/internal/infra/auth"
 	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+	wayv2 "github.com/bavix/vakeel-way/pkg/api/vakeel_way/v2"
+	"github.com/bavix/vakeel-way/pkg/requestid"
 )
 
 var _ = way.StateServiceServer(&GRPCServer{}) //nolint:exhaustruct
 
+// AgentRegistry represents an interface for retrieving the configuration of the agents of the services.
+type AgentRegistry interface {
+	// AgentConfig returns the configuration of the agents of the service.
+	//
+	// Parameters:
+	//   - id: The UUID of the service.
+	//
+	// Returns:
+	//   - The configuration of the agents of the service.
+	//   - A boolean indicating whether the configuration is set.
+	AgentConfig(id uuid.UUID) (entities.AgentConfig, bool)
+}
+
+// StatusReader represents an interface for retrieving the current status of the services.
+type StatusReader interface {
+	// Status returns the current status of the service.
+	//
+	// Parameters:
+	//   - id: The UUID of the service.
+	//
+	// Returns:
+	//   - The current status of the service.
+	//   - A boolean indicating whether the status is known.
+	Status(id uuid.UUID) (entities.ServiceStatus, bool)
+}
+
+// AgentDirectory represents an interface for keeping the agents reporting the services.
+type AgentDirectory interface {
+	// Report records that the agent reported a heartbeat of the service.
+	//
+	// Parameters:
+	//   - id: The UUID of the service.
+	//   - agent: The agent that reported the heartbeat.
+	Report(id uuid.UUID, agent entities.Agent)
+
+	// Agent returns the agent that reported the last heartbeat of the service.
+	//
+	// Parameters:
+	//   - id: The UUID of the service.
+	//
+	// Returns:
+	//   - The agent of the service.
+	//   - A boolean indicating whether the agent is known.
+	Agent(id uuid.UUID) (entities.Agent, bool)
+}
+
 // NewGRPCServer creates a new instance of the GRPCServer struct.
 //
-// It takes a *usecases.Checker as a parameter and returns a pointer to a GRPCServer struct.
+// It takes a *usecases.Checker and an *auth.Limiter as parameters and returns a pointer to a GRPCServer struct.
 // The GRPCServer struct implements the way.StateServiceServer interface and is used to provide the StateService
 // RPC service. The checker parameter is used to send events to the checker.
 //
 // Parameters:
 //   - checker: A *usecases.Checker used to send events to the checker.
+//   - limiter: An *auth.Limiter used to enforce the quotas of the authenticated callers.
+//   - agents: An AgentRegistry holding the configuration pushed to the agents.
+//   - services: A ServiceRegistry used to tell the unknown UUIDs.
+//   - strict: Whether the stream is closed when an unknown UUID is received.
+//   - statuses: A StatusReader used to answer the status queries.
+//   - transitions: A TransitionSource used to stream the status transitions.
+//   - notices: A NoticeSource used to send the notices of the server to the agents.
+//   - directory: An AgentDirectory used to keep the agents introduced in the handshakes.
+//   - auditSink: An AuditSink used to record every received heartbeat.
+//   - uptime: An UptimeSource used to answer the uptime queries.
+//   - incidents: An IncidentSource used to answer the incident queries.
 //
 // Returns:
 //   - A pointer to a GRPCServer struct.
@@ -23,6 +101,17 @@ var _ = way.StateServiceServer(&GRPCServer{}) //nolint:exhaustruct
 //nolint:exhaustruct
 func NewGRPCServer(
 	checker *usecases.Checker,
+	limiter *auth.Limiter,
+	agents AgentRegistry,
+	services ServiceRegistry,
+	strict bool,
+	statuses StatusReader,
+	transitions TransitionSource,
+	notices NoticeSource,
+	directory AgentDirectory,
+	auditSink AuditSink,
+	uptime UptimeSource,
+	incidents IncidentSource,
 ) *GRPCServer {
 	// Create a new instance of the GRPCServer struct.
 	// The GRPCServer struct implements the way.StateServiceServer interface and is used to provide the StateService
@@ -30,19 +119,70 @@ func NewGRPCServer(
 	return &GRPCServer{
 		// The checker field is used to send events to the checker.
 		checker: checker,
+		// The limiter field is used to enforce the quotas of the callers.
+		limiter: limiter,
+		// The agents field is used to push the configuration to the agents.
+		agents: agents,
+		// The services field is used to tell the unknown UUIDs.
+		services: services,
+		// The strict field defines whether the unknown UUIDs close the stream.
+		strict: strict,
+		// The statuses field is used to answer the status queries.
+		statuses: statuses,
+		// The transitions field is used to stream the status transitions.
+		transitions: transitions,
+		// The notices field is used to send the notices to the agents.
+		notices: notices,
+		// The directory field is used to keep the agents of the services.
+		directory: directory,
+		// The auditSink field is used to record the heartbeats.
+		auditSink: auditSink,
+		// The uptime field is used to answer the uptime queries.
+		uptime: uptime,
+		// The incidents field is used to answer the incident queries.
+		incidents: incidents,
 	}
 }
 
 // GRPCServer is a gRPC server implementation that provides the StateService
 // RPC service. It implements the way.StateServiceServer interface.
 type GRPCServer struct {
-	checker *usecases.Checker
+	checker     *usecases.Checker
+	limiter     *auth.Limiter
+	agents      AgentRegistry
+	services    ServiceRegistry
+	strict      bool
+	statuses    StatusReader
+	transitions TransitionSource
+	notices     NoticeSource
+	directory   AgentDirectory
+	auditSink   AuditSink
+	uptime      UptimeSource
+	incidents   IncidentSource
 
 	way.UnimplementedStateServiceServer
 }
 
 // Update handles the Update RPC call.
 //
+// It receives a stream of UpdateRequest messages from the client and records
+// their heartbeats like UpdateStream. Once the client closes the stream, it
+// responds with a single empty UpdateResponse message. The results of the
+// heartbeats, the configuration of the agents and the notices of the server
+// are not sent, since the method answers only once.
+//
+// If there is a problem with receiving or sending messages, or a request is
+// rejected, an error is returned.
+func (s *GRPCServer) Update(stream way.StateService_UpdateServer) error {
+	if err := s.update(upgradeStream{closingStream{stream}}); err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(&way.UpdateResponse{})
+}
+
+// UpdateStream handles the UpdateStream RPC call.
+//
 // It receives a stream of UpdateRequest messages from the client and responds
 // with an UpdateResponse message for each request. It continues to receive
 // requests until the client closes the stream.
@@ -51,30 +191,497 @@ type GRPCServer struct {
 // These UUIDs are used to uniquely identify the request and can be used to track
 // the request throughout the system.
 //
-// For each UpdateRequest message, the server sends an empty UpdateResponse
-// message to indicate that the update operation was successful.
+// For each UpdateRequest message, the server sends an UpdateResponse message
+// to indicate that the update operation was successful. The response carries
+// the result of every heartbeat of the request: accepted, unknown if the
+// service is not configured, or throttled if the server dropped it because it
+// is overloaded, so the agent can resend it. The response also carries
+// the configuration of the agents of the services of the request that has not
+// been sent on the stream yet or has changed since.
+//
+// The agent may introduce itself with a handshake in any request, usually the
+// first one. The agent is stored alongside the UUIDs of the heartbeats it
+// reports from then on.
+//
+// The delayed heartbeats buffered by a relay during an outage of the server
+// are accounted before the live ones, so the recovery of the services that
+// kept running is flagged as caused by the network.
+//
+// The protocol version is negotiated at the start of the stream: the client
+// sends the versions it supports in the metadata, and the server replies with
+// the chosen version in the header. The configuration of the agents is sent
+// only to the clients speaking ProtocolV2 or later.
+//
+// The clients speaking ProtocolV2 or later also receive the notices of the
+// server: a throttle notice is attached to the responses with throttled
+// heartbeats, and the notices announced by the server, e.g. before it shuts
+// down, are sent on its own initiative in responses that answer no request.
+//
+// The UUIDs of every request are validated: the missing and nil UUIDs close
+// the stream with the InvalidArgument code, and in strict mode the UUIDs of
+// the services that are not configured close it with the NotFound code. The
+// status lists the rejected UUIDs, and none of the heartbeats of the rejected
+// request are recorded.
+//
+// Every heartbeat is recorded in the audit log with the address of the agent.
+//
+// If the caller is authenticated with a token, the heartbeats are checked
+// against the quota of the token one by one, and the heartbeats over the
+// quota are reported as throttled without closing the stream. If the token is scoped to
+// a namespace, the services of the other namespaces are treated as not
+// configured.
 //
 // If there is a problem with receiving or sending messages, an error is returned.
-func (s *GRPCServer) Update(stream way.StateService_UpdateServer) error {
+func (s *GRPCServer) UpdateStream(stream way.StateService_UpdateStreamServer) error {
+	return s.update(upgradeStream{stream})
+}
+
+// update handles the Update stream of any version of the StateService.
+//
+// Parameters:
+//   - stream: The Update stream, with the requests in the form of the second version.
+//
+// Returns:
+//   - An error if there is a problem with receiving or sending messages, or
+//     a gRPC status error if a request is rejected.
+func (s *GRPCServer) update(stream updateStream) error {
+	// Negotiate the protocol version.
+	version, err := negotiate(stream)
+	if err != nil {
+		return err
+	}
+
+	// The configuration of the agents is pushed since ProtocolV2.
+	push := version != entities.ProtocolV1
+
+	// The configuration of the agents sent on the stream.
+	sent := make(map[uuid.UUID]entities.AgentConfig)
+
+	// The agent introduced in the handshake of the stream.
+	var agent entities.Agent
+
+	// The services visible to the caller.
+	registry, scoped := s.registry(stream.Context())
+
+	// The caller recorded in the audit log.
+	from := callerOf(stream.Context())
+
+	// The request ID passed to the notifications caused by the heartbeats.
+	requestID, _ := requestid.FromContext(stream.Context())
+
+	// The responses are sent by this loop and the notices by another goroutine.
+	var (
+		mu     sync.Mutex
+		closed bool
+	)
+
+	send := func(resp *way.UpdateResponse) error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// The stream must not be used once the handler returns.
+		if closed {
+			return nil
+		}
+
+		return stream.Send(resp)
+	}
+
+	defer func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		closed = true
+	}()
+
+	// Send the notices of the server since ProtocolV2.
+	if push {
+		notices, unsubscribe := s.notices.Subscribe()
+		defer unsubscribe()
+
+		go announce(stream.Context(), notices, send)
+	}
+
 	// Process requests from the client stream.
 	for {
 		// Receive the next request from the client.
+		// The stream ends gracefully when the client closes it.
 		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		// Remember the agent introduced in the handshake.
+		if handshake := req.GetHandshake(); handshake != nil {
+			agent = entities.Agent{
+				Hostname: handshake.GetHostname(),
+				Version:  handshake.GetVersion(),
+				Labels:   handshake.GetLabels(),
+			}
+		}
+
+		// Get the list of UUIDs from the request and validate them.
+		ids, err := validate(heartbeatIDs(req.GetHeartbeats()), registry, s.strict)
+		if err != nil {
+			return err
+		}
+
+		// Validate the status and the TTL of the heartbeats.
+		beats, err := heartbeats(req.GetHeartbeats(), ids, requestID)
 		if err != nil {
 			return err
 		}
 
-		// Get the list of UUIDs from the request.
-		for _, id := range req.GetIds() {
-			// Convert the UUID to a string.
-			sid := uuidconv.DoubleInt2UUID(id.GetHigh(), id.GetLow())
-			// Send the UUID to the checker.
-			s.checker.Send(sid)
+		// Check the heartbeats against the ACL of the caller.
+		if err := authorize(stream.Context(), ids, registry); err != nil {
+			return err
+		}
+
+		// Check the heartbeats against the quota of the caller. The heartbeats
+		// over the quota are rejected one by one, so the stream stays open.
+		var quota []error
+		if principal, ok := auth.FromContext(stream.Context()); ok {
+			quota = s.limiter.Allow(principal, ids)
+		}
+
+		// Account the delayed heartbeats before the live ones.
+		for _, heartbeat := range req.GetDelayed() {
+			id := uuidconv.DoubleInt2UUID(heartbeat.GetId().GetHigh(), heartbeat.GetId().GetLow())
+
+			// The heartbeats of the other namespaces and of the services the
+			// caller may not report are not accounted.
+			if scoped && !registry.Exists(id) || !allowed(stream.Context(), id, registry) {
+				continue
+			}
+
+			s.checker.Delayed(stream.Context(), id, heartbeat.GetTime().AsTime())
+		}
+
+		// Send the UUIDs to the checker and report the result of every heartbeat.
+		resp := &way.UpdateResponse{Results: s.record(registry, beats, quota, agent)}
+
+		// Record the heartbeats in the audit log.
+		s.audit(stream.Context(), from, agent, resp.GetResults())
+
+		// Ask the agent to slow down if the server is overloaded or the quota
+		// of the caller is exceeded.
+		if push && throttled(resp.GetResults()) {
+			reason := "the server is overloaded"
+			if slices.ContainsFunc(quota, func(err error) bool { return err != nil }) {
+				reason = "the quota of the token is exceeded"
+			}
+
+			resp.Notice = noticeMessage(entities.Notice{
+				Kind:   entities.NoticeThrottle,
+				Delay:  throttleDelay,
+				Reason: reason,
+			})
 		}
 
-		// Send an empty UpdateResponse message to the client.
-		if err := stream.SendMsg(&way.UpdateResponse{}); err != nil {
+		// Attach the new configuration of the agents.
+		if push {
+			resp.Configs = s.configs(registry, ids, sent)
+		}
+
+		if err := send(resp); err != nil {
 			return err
 		}
 	}
 }
+
+// GetStatus handles the GetStatus RPC call.
+//
+// It returns the current status of every requested service, in the order of
+// the request. The services without a known status are reported with the
+// known flag unset. The missing and nil UUIDs are rejected with the
+// InvalidArgument code. If the caller is scoped to a namespace, the services
+// of the other namespaces are reported as unknown.
+//
+// Parameters:
+//   - ctx: The context.Context of the call.
+//   - req: The GetStatusRequest with the UUIDs of the services.
+//
+// Returns:
+//   - A GetStatusResponse with the status of the services.
+//   - An InvalidArgument error if any UUID is malformed.
+func (s *GRPCServer) GetStatus(ctx context.Context, req *way.GetStatusRequest) (*way.GetStatusResponse, error) {
+	registry, scoped := s.registry(ctx)
+
+	// The unknown services are reported, not rejected.
+	ids, err := validate(req.GetIds(), registry, false)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &way.GetStatusResponse{Statuses: make([]*way.ServiceStatus, 0, len(ids))}
+
+	for _, id := range ids {
+		// Hide the status of the services of the other namespaces.
+		if scoped && !registry.Exists(id) {
+			high, low := uuidconv.UUID2DoubleInt(id)
+			resp.Statuses = append(resp.Statuses, &way.ServiceStatus{Id: &apiv1.UUID{High: high, Low: low}})
+
+			continue
+		}
+
+		resp.Statuses = append(resp.Statuses, s.status(id))
+	}
+
+	return resp, nil
+}
+
+// GetUptime handles the GetUptime RPC call.
+//
+// It returns the uptime of every requested service over the rolling windows,
+// in the order of the request. The windows in which the status of a service
+// was never known are reported with the known flag unset. The missing and nil
+// UUIDs are rejected with the InvalidArgument code. If the caller is scoped
+// to a namespace, the uptime of the services of the other namespaces is
+// reported as unknown.
+//
+// Parameters:
+//   - ctx: The context.Context of the call.
+//   - req: The GetUptimeRequest with the UUIDs of the services.
+//
+// Returns:
+//   - A GetUptimeResponse with the uptime of the services.
+//   - An InvalidArgument error if any UUID is malformed.
+func (s *GRPCServer) GetUptime(ctx context.Context, req *way.GetUptimeRequest) (*way.GetUptimeResponse, error) {
+	registry, scoped := s.registry(ctx)
+
+	// The unknown services are reported, not rejected.
+	ids, err := validate(req.GetIds(), registry, false)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	resp := &way.GetUptimeResponse{Uptimes: make([]*way.ServiceUptime, 0, len(ids))}
+
+	for _, id := range ids {
+		high, low := uuidconv.UUID2DoubleInt(id)
+		msg := &way.ServiceUptime{
+			Id:      &apiv1.UUID{High: high, Low: low},
+			Windows: make([]*way.Uptime, 0, len(entities.UptimeWindows)),
+		}
+
+		for _, window := range entities.UptimeWindows {
+			uptime := &way.Uptime{Window: window.Name, Duration: durationpb.New(window.Duration)}
+
+			// Hide the uptime of the services of the other namespaces.
+			if !scoped || registry.Exists(id) {
+				up, total := s.uptime.Uptime(id, now.Add(-window.Duration), now)
+				if total > 0 {
+					uptime.Known = true
+					uptime.Percent = 100 * float64(up) / float64(total)
+					uptime.Up, uptime.Total = durationpb.New(up), durationpb.New(total)
+				}
+			}
+
+			msg.Windows = append(msg.Windows, uptime)
+		}
+
+		resp.Uptimes = append(resp.Uptimes, msg)
+	}
+
+	return resp, nil
+}
+
+// status returns the ServiceStatus message of the service.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//
+// Returns:
+//   - The ServiceStatus message, with the known flag unset if the status is unknown.
+func (s *GRPCServer) status(id uuid.UUID) *way.ServiceStatus {
+	high, low := uuidconv.UUID2DoubleInt(id)
+	msg := &way.ServiceStatus{Id: &apiv1.UUID{High: high, Low: low}}
+
+	if current, ok := s.statuses.Status(id); ok {
+		msg.Known = true
+		msg.Status = current.Status.String()
+		msg.Since = timestamppb.New(current.Since)
+		msg.LastSeen = timestamppb.New(current.LastSeen)
+		msg.Attempt = current.Attempt
+	}
+
+	return msg
+}
+
+// negotiate chooses the protocol version of the stream and sends it to the client in the header.
+//
+// Parameters:
+//   - stream: The Update stream.
+//
+// Returns:
+//   - The chosen protocol version.
+//   - A FailedPrecondition error if the client supports none of the server versions.
+func negotiate(stream grpc.ServerStream) (string, error) {
+	md, _ := metadata.FromIncomingContext(stream.Context())
+
+	version, err := entities.NegotiateProtocol(md.Get(entities.ProtocolMetadata))
+	if err != nil {
+		return "", status.Errorf(codes.FailedPrecondition, "%s: supported versions are %s",
+			err, strings.Join(entities.Protocols(), ", "))
+	}
+
+	if err := stream.SendHeader(metadata.Pairs(entities.ProtocolMetadata, version)); err != nil {
+		return "", err
+	}
+
+	return version, nil
+}
+
+// record sends the heartbeats of the configured services to the checker and
+// stores the agent that reported them.
+//
+// Parameters:
+//   - registry: The ServiceRegistry of the services visible to the caller.
+//   - beats: The heartbeats of the request.
+//   - quota: The errors of the quota of the caller, in the order of the
+//     heartbeats. The heartbeats with an error are rejected as throttled.
+//   - agent: The agent introduced in the handshake of the stream, if any.
+//
+// Returns:
+//   - The UpdateResult messages in the order of the request.
+func (s *GRPCServer) record(
+	registry ServiceRegistry,
+	beats []entities.Heartbeat,
+	quota []error,
+	agent entities.Agent,
+) []*way.UpdateResult {
+	results := make([]*way.UpdateResult, 0, len(beats))
+
+	for i, beat := range beats {
+		id := beat.ID
+		high, low := uuidconv.UUID2DoubleInt(id)
+		result := way.UpdateResult_RESULT_ACCEPTED
+
+		switch {
+		case !registry.Exists(id):
+			// The heartbeats of the unknown services are not recorded.
+			result = way.UpdateResult_RESULT_UNKNOWN
+		case i < len(quota) && quota[i] != nil:
+			// The heartbeats over the quota of the caller are not recorded.
+			result = way.UpdateResult_RESULT_THROTTLED
+		case !s.checker.Beat(beat):
+			result = way.UpdateResult_RESULT_THROTTLED
+		case !agent.Empty():
+			s.directory.Report(id, agent)
+		}
+
+		results = append(results, &way.UpdateResult{
+			Id:     &apiv1.UUID{High: high, Low: low},
+			Result: result,
+		})
+	}
+
+	return results
+}
+
+// configs returns the configuration of the agents of the services that has
+// not been sent on the stream yet or has changed since, and marks it as sent.
+//
+// Parameters:
+//   - registry: The ServiceRegistry of the services visible to the caller.
+//   - ids: The UUIDs of the services of the request.
+//   - sent: The configuration of the agents sent on the stream.
+//
+// Returns:
+//   - The AgentConfig messages to push to the client.
+func (s *GRPCServer) configs(
+	registry ServiceRegistry,
+	ids []uuid.UUID,
+	sent map[uuid.UUID]entities.AgentConfig,
+) []*way.AgentConfig {
+	var configs []*way.AgentConfig
+
+	for _, id := range ids {
+		agent, ok := s.agents.AgentConfig(id)
+		if !ok || !registry.Exists(id) {
+			continue
+		}
+
+		if previous, ok := sent[id]; ok && previous.Equal(agent) {
+			continue
+		}
+
+		sent[id] = agent
+		high, low := uuidconv.UUID2DoubleInt(id)
+
+		configs = append(configs, &way.AgentConfig{
+			Id:       &apiv1.UUID{High: high, Low: low},
+			Interval: durationpb.New(agent.Interval),
+			Features: agent.Features,
+		})
+	}
+
+	return configs
+}
+
+// updateStream is the Update stream of any version of the StateService.
+//
+// The responses are the same in every version, and the requests are received
+// in the form of the latest version.
+type updateStream interface {
+	grpc.ServerStream
+
+	// Send sends the response to the client.
+	Send(resp *way.UpdateResponse) error
+
+	// Recv receives the next request of the client.
+	Recv() (*wayv2.UpdateRequest, error)
+}
+
+// upgradeStream is the Update stream of the first version of the StateService,
+// whose requests are converted to the second version.
+type upgradeStream struct {
+	way.StateService_UpdateStreamServer
+}
+
+// Recv receives the next request of the client and converts it: every UUID is
+// a heartbeat with the status Up, no message and the default TTL.
+func (s upgradeStream) Recv() (*wayv2.UpdateRequest, error) {
+	req, err := s.StateService_UpdateStreamServer.Recv()
+	if err != nil {
+		return nil, err
+	}
+
+	beats := make([]*wayv2.Heartbeat, 0, len(req.GetIds()))
+	for _, id := range req.GetIds() {
+		beats = append(beats, &wayv2.Heartbeat{Id: id, Status: wayv2.Heartbeat_STATUS_UP})
+	}
+
+	return &wayv2.UpdateRequest{
+		Heartbeats: beats,
+		Delayed:    req.GetDelayed(),
+		Handshake:  req.GetHandshake(),
+	}, nil
+}
+
+// closingStream is the client-streaming Update stream of the first version of
+// the StateService. It answers only once the client closes the stream, so the
+// responses to the requests are dropped.
+type closingStream struct {
+	way.StateService_UpdateServer
+}
+
+// Send drops the response.
+func (closingStream) Send(*way.UpdateResponse) error {
+	return nil
+}
+
+// heartbeatIDs returns the UUIDs of the heartbeats, in the order of the heartbeats.
+func heartbeatIDs(beats []*wayv2.Heartbeat) []*apiv1.UUID {
+	ids := make([]*apiv1.UUID, 0, len(beats))
+	for _, beat := range beats {
+		ids = append(ids, beat.GetId())
+	}
+
+	return ids
+}
@@ -0,0 +1,281 @@
+package app_test
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	apiv1 "github.com/bavix/apis/pkg/bavix/api/v1"
+	"github.com/bavix/apis/pkg/uuidconv"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/bavix/vakeel-way/internal/app"
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/domain/services"
+	"github.com/bavix/vakeel-way/internal/domain/usecases"
+	"github.com/bavix/vakeel-way/internal/infra/audit"
+	"github.com/bavix/vakeel-way/internal/infra/auth"
+	"github.com/bavix/vakeel-way/internal/infra/repositories"
+	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+)
+
+// agentConfigs is an AgentRegistry whose configuration can be changed by the test.
+type agentConfigs struct {
+	mu      sync.Mutex
+	configs map[uuid.UUID]entities.AgentConfig
+}
+
+// AgentConfig returns the configuration of the agents of the service.
+func (a *agentConfigs) AgentConfig(id uuid.UUID) (entities.AgentConfig, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	config, ok := a.configs[id]
+
+	return config, ok
+}
+
+// set changes the configuration of the agents of the service.
+func (a *agentConfigs) set(id uuid.UUID, config entities.AgentConfig) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.configs[id] = config
+}
+
+// serverStream is a fake Update stream of the server controlled by the test.
+type serverStream struct {
+	grpc.ServerStream
+
+	ctx       context.Context //nolint:containedctx
+	requests  chan *way.UpdateRequest
+	responses chan *way.UpdateResponse
+}
+
+// Context returns the context of the stream.
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// SendHeader does nothing.
+func (s *serverStream) SendHeader(metadata.MD) error {
+	return nil
+}
+
+// Recv returns the requests of the test, then io.EOF once they are closed.
+func (s *serverStream) Recv() (*way.UpdateRequest, error) {
+	req, ok := <-s.requests
+	if !ok {
+		return nil, io.EOF
+	}
+
+	return req, nil
+}
+
+// Send passes the response to the test.
+func (s *serverStream) Send(resp *way.UpdateResponse) error {
+	s.responses <- resp
+
+	return nil
+}
+
+// SendAndClose passes the single response of the client-streaming call to the test.
+func (s *serverStream) SendAndClose(resp *way.UpdateResponse) error {
+	return s.Send(resp)
+}
+
+// ServerTestSuite represents the test suite for the Update streams of the StateService.
+type ServerTestSuite struct {
+	suite.Suite
+
+	first, second, third uuid.UUID
+
+	agents  *agentConfigs
+	checker *usecases.Checker
+	server  *app.GRPCServer
+}
+
+// SetupTest configures three services with the configuration of the agents
+// of the first two, and the third one in another namespace.
+func (suite *ServerTestSuite) SetupTest() {
+	suite.first, suite.second, suite.third = uuid.New(), uuid.New(), uuid.New()
+
+	suite.agents = &agentConfigs{configs: map[uuid.UUID]entities.AgentConfig{
+		suite.first: {Interval: time.Minute},
+		suite.third: {Features: []string{"trace"}},
+	}}
+
+	registry := repositories.NewWebhookRepository(
+		map[uuid.UUID][]entities.Target{suite.first: nil, suite.second: nil, suite.third: nil},
+		repositories.WithNamespaces(map[uuid.UUID]string{suite.first: "team", suite.second: "team", suite.third: "other"}),
+	)
+
+	suite.checker = usecases.NewChecker(nil)
+	suite.server = app.NewGRPCServer(
+		suite.checker,
+		auth.NewLimiter(prometheus.NewRegistry()),
+		suite.agents,
+		registry,
+		false,
+		nil,
+		nil,
+		services.NewAnnouncer(),
+		services.NewAgentDirectory(),
+		audit.NewLog(),
+		nil,
+		nil,
+	)
+}
+
+// open starts the UpdateStream call speaking the protocol versions and returns its stream.
+func (suite *ServerTestSuite) open(ctx context.Context, versions ...string) *serverStream {
+	if len(versions) > 0 {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(entities.ProtocolMetadata, versions[0]))
+	}
+
+	stream := &serverStream{
+		ctx:       ctx,
+		requests:  make(chan *way.UpdateRequest),
+		responses: make(chan *way.UpdateResponse, 4),
+	}
+
+	done := make(chan error, 1)
+
+	go func() { done <- suite.server.UpdateStream(stream) }()
+
+	t := suite.T()
+	t.Cleanup(func() {
+		close(stream.requests)
+
+		if err := <-done; err != nil {
+			t.Error(err)
+		}
+	})
+
+	return stream
+}
+
+// update sends a request with the heartbeats of the services and returns the
+// configuration of the agents of the response.
+func (suite *ServerTestSuite) update(stream *serverStream, ids ...uuid.UUID) map[uuid.UUID]*way.AgentConfig {
+	stream.requests <- &way.UpdateRequest{Ids: uuids(ids...)}
+
+	select {
+	case resp := <-stream.responses:
+		suite.Require().Len(resp.GetResults(), len(ids))
+
+		configs := make(map[uuid.UUID]*way.AgentConfig, len(resp.GetConfigs()))
+		for _, config := range resp.GetConfigs() {
+			configs[uuidconv.DoubleInt2UUID(config.GetId().GetHigh(), config.GetId().GetLow())] = config
+		}
+
+		return configs
+	case <-time.After(5 * time.Second):
+		suite.FailNow("the request is not answered")
+
+		return nil
+	}
+}
+
+// uuids converts the UUIDs to the messages of the API.
+func uuids(ids ...uuid.UUID) []*apiv1.UUID {
+	msgs := make([]*apiv1.UUID, 0, len(ids))
+	for _, id := range ids {
+		high, low := uuidconv.UUID2DoubleInt(id)
+		msgs = append(msgs, &apiv1.UUID{High: high, Low: low})
+	}
+
+	return msgs
+}
+
+// TestUpdateStream_Configs verifies that the configuration of the agents is
+// sent once per stream, and again only once it changes.
+func (suite *ServerTestSuite) TestUpdateStream_Configs() {
+	stream := suite.open(context.Background(), entities.ProtocolV2)
+
+	// The services without a configuration get none.
+	configs := suite.update(stream, suite.first, suite.second)
+	suite.Require().Len(configs, 1)
+	suite.Equal(time.Minute, configs[suite.first].GetInterval().AsDuration())
+
+	// The configuration already sent on the stream is not sent again.
+	suite.Empty(suite.update(stream, suite.first, suite.second))
+
+	// The changed configuration is sent again.
+	suite.agents.set(suite.first, entities.AgentConfig{Interval: time.Minute, Features: []string{"trace"}})
+
+	configs = suite.update(stream, suite.first)
+	suite.Require().Len(configs, 1)
+	suite.Equal([]string{"trace"}, configs[suite.first].GetFeatures())
+
+	suite.Empty(suite.update(stream, suite.first))
+
+	// Another stream gets the configuration again.
+	suite.Len(suite.update(suite.open(context.Background(), entities.ProtocolV2), suite.first), 1)
+}
+
+// TestUpdateStream_Unknown verifies that the configuration of the services
+// that are not configured is not sent.
+func (suite *ServerTestSuite) TestUpdateStream_Unknown() {
+	unknown := uuid.New()
+	suite.agents.set(unknown, entities.AgentConfig{Interval: time.Hour})
+
+	stream := suite.open(context.Background(), entities.ProtocolV2)
+
+	suite.Empty(suite.update(stream, unknown))
+}
+
+// TestUpdateStream_Namespace verifies that the configuration of the services
+// of the other namespaces is not sent to the tokens scoped to a namespace.
+func (suite *ServerTestSuite) TestUpdateStream_Namespace() {
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{Name: "team", Namespace: "team"})
+	stream := suite.open(ctx, entities.ProtocolV2)
+
+	configs := suite.update(stream, suite.first, suite.third)
+	suite.Len(configs, 1)
+	suite.Contains(configs, suite.first)
+
+	suite.Len(suite.update(suite.open(context.Background(), entities.ProtocolV2), suite.first, suite.third), 2)
+}
+
+// TestUpdateStream_ProtocolV1 verifies that the configuration of the agents is
+// not sent to the clients speaking the first version of the protocol.
+func (suite *ServerTestSuite) TestUpdateStream_ProtocolV1() {
+	suite.Empty(suite.update(suite.open(context.Background()), suite.first, suite.third))
+	suite.Empty(suite.update(suite.open(context.Background(), entities.ProtocolV1), suite.first, suite.third))
+}
+
+// TestUpdate_Single verifies that the client-streaming Update call records the
+// heartbeats and answers once the client closes the stream.
+func (suite *ServerTestSuite) TestUpdate_Single() {
+	stream := &serverStream{
+		ctx:       context.Background(),
+		requests:  make(chan *way.UpdateRequest, 2),
+		responses: make(chan *way.UpdateResponse, 4),
+	}
+
+	stream.requests <- &way.UpdateRequest{Ids: uuids(suite.first)}
+	stream.requests <- &way.UpdateRequest{Ids: uuids(suite.second, suite.third)}
+	close(stream.requests)
+
+	suite.Require().NoError(suite.server.Update(stream))
+
+	suite.Require().Len(stream.responses, 1)
+
+	resp := <-stream.responses
+	suite.Empty(resp.GetResults())
+	suite.Empty(resp.GetConfigs())
+	suite.Len(suite.checker.Events, 3)
+}
+
+// TestServerTestSuite runs the test suite for the Update streams of the StateService.
+func TestServerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ServerTestSuite))
+}
@@ -0,0 +1,46 @@
+package app
+
+import (
+	wayv2 "github.com/bavix/vakeel-way/pkg/api/vakeel_way/v2"
+)
+
+var _ = wayv2.StateServiceServer(&StateServerV2{}) //nolint:exhaustruct
+
+// NewStateServerV2 creates the server of the second version of the StateService.
+//
+// The second version shares the state of the first one: the heartbeats of
+// both versions are recorded by the same checker, and the agents, quotas and
+// audit log are the same.
+//
+// Parameters:
+//   - state: The GRPCServer serving the first version of the StateService.
+//
+// Returns:
+//   - A pointer to a StateServerV2.
+//
+//nolint:exhaustruct
+func NewStateServerV2(state *GRPCServer) *StateServerV2 {
+	return &StateServerV2{state: state}
+}
+
+// StateServerV2 is a gRPC server implementation that provides the second
+// version of the StateService RPC service. It implements the
+// wayv2.StateServiceServer interface.
+type StateServerV2 struct {
+	state *GRPCServer
+
+	wayv2.UnimplementedStateServiceServer
+}
+
+// Update handles the Update RPC call of the second version.
+//
+// It behaves like the Update method of the first version, except that every
+// heartbeat carries the status reported by the agent, up or degraded, an
+// optional message attached to the notifications, and the TTL the agent
+// proposes for the status. The heartbeats with an unknown status or a
+// negative TTL close the stream with the InvalidArgument code.
+//
+// If there is a problem with receiving or sending messages, an error is returned.
+func (s *StateServerV2) Update(stream wayv2.StateService_UpdateServer) error {
+	return s.state.update(stream)
+}
@@ -0,0 +1,179 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"slices"
+
+	apiv1 "github.com/bavix/apis/pkg/bavix/api/v1"
+	"github.com/bavix/apis/pkg/uuidconv"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+)
+
+const (
+	// defaultPageSize is the number of the services returned when the page size is not set.
+	defaultPageSize = 100
+
+	// maxPageSize is the maximum number of the services returned at once.
+	maxPageSize = 1000
+)
+
+// ServiceRegistry represents an interface for retrieving the configured services.
+type ServiceRegistry interface {
+	// Exists reports whether the service is configured.
+	//
+	// Parameters:
+	//   - id: The UUID of the service.
+	//
+	// Returns:
+	//   - true if the service is configured, false otherwise.
+	Exists(id uuid.UUID) bool
+
+	// All returns the UUIDs of the configured services.
+	All() []uuid.UUID
+
+	// Namespace returns the namespace of the service.
+	//
+	// Parameters:
+	//   - id: The UUID of the service.
+	//
+	// Returns:
+	//   - The namespace of the service, or an empty string for the default namespace.
+	Namespace(id uuid.UUID) string
+
+	// Labels returns the labels of the service.
+	//
+	// Parameters:
+	//   - id: The UUID of the service.
+	//
+	// Returns:
+	//   - The labels of the service, or nil if it has none.
+	Labels(id uuid.UUID) map[string]string
+
+	// Get returns the targets of the service.
+	//
+	// Parameters:
+	//   - ctx: The context.Context used to cancel the operation if needed.
+	//   - id: The UUID of the service.
+	//
+	// Returns:
+	//   - The targets of the service.
+	//   - An error if the service is not found or its targets cannot be read.
+	Get(ctx context.Context, id uuid.UUID) ([]entities.Target, error)
+}
+
+// ListServices handles the ListServices RPC call.
+//
+// It returns the configured services matching the label selector, ordered by
+// their UUIDs, with the types of their targets, their current status and the
+// agent that reported them. If the caller is scoped to a namespace, only the
+// services of the namespace are returned. The
+// services are returned page by page: the token of the next page is the UUID
+// of the last service of the page, so the pages stay consistent when the
+// services are added or archived in between.
+//
+// Parameters:
+//   - ctx: The context.Context of the call.
+//   - req: The ListServicesRequest with the page and the label selector.
+//
+// Returns:
+//   - A ListServicesResponse with the services of the page.
+//   - An InvalidArgument error if the page size or the page token is invalid.
+func (s *GRPCServer) ListServices(ctx context.Context, req *way.ListServicesRequest) (*way.ListServicesResponse, error) {
+	size := int(req.GetPageSize())
+
+	switch {
+	case size < 0:
+		return nil, status.Error(codes.InvalidArgument, "the page size must not be negative")
+	case size == 0:
+		size = defaultPageSize
+	case size > maxPageSize:
+		size = maxPageSize
+	}
+
+	// The page starts after the UUID of the token.
+	var after uuid.UUID
+
+	if token := req.GetPageToken(); token != "" {
+		var err error
+		if after, err = uuid.Parse(token); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "the page token is invalid")
+		}
+	}
+
+	// The callers scoped to a namespace see its services only.
+	registry, _ := s.registry(ctx)
+
+	ids := registry.All()
+	slices.SortFunc(ids, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+
+	resp := &way.ListServicesResponse{}
+
+	for _, id := range ids {
+		if bytes.Compare(id[:], after[:]) <= 0 {
+			continue
+		}
+
+		labels := registry.Labels(id)
+		if !entities.MatchLabels(req.GetLabels(), labels) {
+			continue
+		}
+
+		// Stop once the page is full and there is another matching service.
+		if len(resp.Services) == size {
+			resp.NextPageToken = last(resp.Services).String()
+
+			break
+		}
+
+		targets, err := registry.Get(ctx, id)
+		if err != nil {
+			// The service is archived in the meantime.
+			continue
+		}
+
+		types := make([]string, 0, len(targets))
+		for _, target := range targets {
+			if !slices.Contains(types, target.Type) {
+				types = append(types, target.Type)
+			}
+		}
+
+		high, low := uuidconv.UUID2DoubleInt(id)
+
+		service := &way.Service{
+			Id:          &apiv1.UUID{High: high, Low: low},
+			TargetTypes: types,
+			Labels:      labels,
+			Status:      s.status(id),
+			Namespace:   registry.Namespace(id),
+		}
+
+		// Attach the agent that reported the service.
+		if agent, ok := s.directory.Agent(id); ok {
+			service.Agent = &way.Handshake{Hostname: agent.Hostname, Version: agent.Version, Labels: agent.Labels}
+		}
+
+		resp.Services = append(resp.Services, service)
+	}
+
+	return resp, nil
+}
+
+// last returns the UUID of the last service of the page.
+//
+// Parameters:
+//   - services: The services of the page. It must not be empty.
+//
+// Returns:
+//   - The UUID of the last service.
+func last(services []*way.Service) uuid.UUID {
+	id := services[len(services)-1].GetId()
+
+	return uuidconv.DoubleInt2UUID(id.GetHigh(), id.GetLow())
+}
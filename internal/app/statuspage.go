@@ -0,0 +1,325 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// NewStatusPageHandler creates a new instance of the StatusPageHandler struct.
+//
+// Parameters:
+//   - title: The title of the status page.
+//   - components: The components of the status page, in the order they are shown.
+//   - days: The number of the days of the uptime history.
+//   - cache: The time the rendered page is reused for.
+//   - services: The ServiceRegistry of the configured services.
+//   - statuses: The StatusReader holding the current status of the services.
+//   - uptime: The UptimeSource holding the status history.
+//
+// Returns:
+//   - A pointer to a StatusPageHandler struct.
+//
+//nolint:exhaustruct
+func NewStatusPageHandler(
+	title string,
+	components []entities.Component,
+	days int,
+	cache time.Duration,
+	services ServiceRegistry,
+	statuses StatusReader,
+	uptime UptimeSource,
+) *StatusPageHandler {
+	return &StatusPageHandler{
+		title:      title,
+		components: components,
+		days:       max(days, 1),
+		cache:      cache,
+		services:   services,
+		statuses:   statuses,
+		uptime:     uptime,
+		now:        time.Now,
+	}
+}
+
+// StatusPageHandler is an HTTP handler that serves the public status page.
+//
+// It serves the page as HTML on GET / and as JSON on GET /status.json. The
+// page is read-only and is rebuilt at most once per cache period, so it can
+// be exposed to many readers and put behind a caching proxy.
+type StatusPageHandler struct {
+	title      string
+	components []entities.Component
+	days       int
+	cache      time.Duration
+	services   ServiceRegistry
+	statuses   StatusReader
+	uptime     UptimeSource
+
+	// mu protects the cached page.
+	mu sync.Mutex
+
+	// page is the cached page, or nil if it is not built yet.
+	page *statusPage
+
+	// now returns the current time.
+	now func() time.Time
+}
+
+// statusPage is the status page as rendered to HTML and JSON.
+type statusPage struct {
+	Title      string                   `json:"title"`
+	Status     entities.ComponentStatus `json:"status"`
+	Updated    time.Time                `json:"updated"`
+	Components []componentPage          `json:"components"`
+}
+
+// componentPage is a component of the status page.
+type componentPage struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description,omitempty"`
+	Status      entities.ComponentStatus `json:"status"`
+	Services    int                      `json:"services"`
+	Down        int                      `json:"down"`
+	Uptime      *float64                 `json:"uptime"`
+	Windows     []windowUptime           `json:"windows"`
+	History     []dayUptime              `json:"history"`
+}
+
+// windowUptime is the uptime of a component over a rolling window.
+type windowUptime struct {
+	Window string   `json:"window"`
+	Uptime *float64 `json:"uptime"`
+}
+
+// dayUptime is the uptime of a component over a day.
+type dayUptime struct {
+	Date   string   `json:"date"`
+	Uptime *float64 `json:"uptime"`
+}
+
+// ServeHTTP serves the status page as JSON on /status.json and as HTML otherwise.
+func (h *StatusPageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	page := h.build()
+
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.cache.Seconds())))
+
+	if r.URL.Path == "/status.json" {
+		w.Header().Set("Content-Type", "application/json")
+
+		_ = json.NewEncoder(w).Encode(page)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := statusPageTemplate.Execute(w, page); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// build returns the status page, rebuilding it if the cached one is older
+// than the cache period.
+func (h *StatusPageHandler) build() *statusPage {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := h.now()
+	if h.page != nil && now.Sub(h.page.Updated) < h.cache {
+		return h.page
+	}
+
+	page := &statusPage{
+		Title:      h.title,
+		Status:     entities.ComponentUnknown,
+		Updated:    now,
+		Components: make([]componentPage, 0, len(h.components)),
+	}
+
+	// The services of every component.
+	members := make([][]uuid.UUID, len(h.components))
+
+	for _, id := range h.services.All() {
+		labels := h.services.Labels(id)
+
+		for i, component := range h.components {
+			if component.Matches(id, labels) {
+				members[i] = append(members[i], id)
+			}
+		}
+	}
+
+	var up, down int
+
+	for i, component := range h.components {
+		c := h.component(component, members[i], now)
+		page.Components = append(page.Components, c)
+
+		// The page is operational if every component with a known status is.
+		// The components with issues count as down.
+		switch c.Status {
+		case entities.ComponentOperational:
+			up++
+		case entities.ComponentDegraded, entities.ComponentOutage:
+			down++
+		case entities.ComponentUnknown:
+		}
+	}
+
+	page.Status = entities.Aggregate(up, down)
+
+	h.page = page
+
+	return page
+}
+
+// component builds a component of the status page.
+//
+// Parameters:
+//   - component: The component.
+//   - members: The UUIDs of the services of the component.
+//   - now: The time the page is built at.
+//
+// Returns:
+//   - The component of the status page.
+func (h *StatusPageHandler) component(
+	component entities.Component,
+	members []uuid.UUID,
+	now time.Time,
+) componentPage {
+	const day = 24 * time.Hour
+
+	var up, down int
+
+	for _, id := range members {
+		// The services without a known status are not counted.
+		if current, ok := h.statuses.Status(id); ok {
+			if current.Status == entities.Down {
+				down++
+			} else {
+				up++
+			}
+		}
+	}
+
+	c := componentPage{
+		Name:        component.Name,
+		Description: component.Description,
+		Status:      entities.Aggregate(up, down),
+		Services:    len(members),
+		Down:        down,
+		Uptime:      nil,
+		Windows:     make([]windowUptime, 0, len(entities.UptimeWindows)),
+		History:     make([]dayUptime, 0, h.days),
+	}
+
+	// The uptime of the services of the component over the rolling windows.
+	for _, window := range entities.UptimeWindows {
+		var upWindow, knownWindow time.Duration
+
+		for _, id := range members {
+			u, k := h.uptime.Uptime(id, now.Add(-window.Duration), now)
+			upWindow += u
+			knownWindow += k
+		}
+
+		c.Windows = append(c.Windows, windowUptime{Window: window.Name, Uptime: percent(upWindow, knownWindow)})
+	}
+
+	// The uptime of the services of the component, summed per day.
+	today := now.UTC().Truncate(day)
+
+	var upTotal, knownTotal time.Duration
+
+	for i := h.days - 1; i >= 0; i-- {
+		from := today.Add(-time.Duration(i) * day)
+		to := from.Add(day)
+		if to.After(now) {
+			to = now
+		}
+
+		var upDay, knownDay time.Duration
+
+		for _, id := range members {
+			u, k := h.uptime.Uptime(id, from, to)
+			upDay += u
+			knownDay += k
+		}
+
+		c.History = append(c.History, dayUptime{Date: from.Format(time.DateOnly), Uptime: percent(upDay, knownDay)})
+		upTotal += upDay
+		knownTotal += knownDay
+	}
+
+	c.Uptime = percent(upTotal, knownTotal)
+
+	return c
+}
+
+// percent returns the uptime percentage, or nil if the status was never known.
+func percent(up, known time.Duration) *float64 {
+	if known <= 0 {
+		return nil
+	}
+
+	value := 100 * float64(up) / float64(known)
+
+	return &value
+}
+
+// statusPageTemplate is the HTML template of the status page.
+//
+//nolint:lll
+var statusPageTemplate = template.Must(template.New("status").Funcs(template.FuncMap{
+	"uptime": func(value *float64) string {
+		if value == nil {
+			return "no data"
+		}
+
+		return formatUptime(*value)
+	},
+	"color": func(value *float64) string {
+		if value == nil {
+			return entities.ColorUnknown
+		}
+
+		// The thresholds of the default badge apply.
+		return entities.DefaultBadge().Color(*value)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body{font-family:-apple-system,Segoe UI,Helvetica,Arial,sans-serif;max-width:860px;margin:2rem auto;padding:0 1rem;color:#222}
+.banner{padding:1rem;border-radius:6px;color:#fff;font-weight:600;margin-bottom:2rem}
+.operational{background:#3ba55c}.degraded{background:#e6a700}.outage{background:#d9534f}.unknown{background:#9f9f9f}
+.component{border:1px solid #ddd;border-radius:6px;padding:1rem;margin-bottom:1rem}
+.head{display:flex;justify-content:space-between}.state{font-size:.9rem;padding:0 .5rem;border-radius:4px;color:#fff}
+.bars{display:flex;gap:1px;margin-top:.75rem;height:28px}.bars span{flex:1;border-radius:1px}
+.meta{color:#777;font-size:.85rem;margin-top:.5rem}
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<div class="banner {{.Status}}">{{if eq .Status "operational"}}All systems operational{{else if eq .Status "unknown"}}Status unknown{{else}}Some systems are experiencing issues{{end}}</div>
+{{range .Components}}<div class="component">
+<div class="head"><strong>{{.Name}}</strong><span class="state {{.Status}}">{{.Status}}</span></div>
+{{with .Description}}<div class="meta">{{.}}</div>{{end}}
+<div class="bars">{{range .History}}<span title="{{.Date}}: {{uptime .Uptime}}" style="background:{{color .Uptime}}"></span>{{end}}</div>
+<div class="meta">{{with .Uptime}}{{uptime .}} uptime{{else}}No data{{end}} over {{len .History}} days{{range .Windows}} · {{.Window}}: {{uptime .Uptime}}{{end}}</div>
+</div>
+{{end}}<div class="meta">Updated {{.Updated.UTC.Format "2006-01-02 15:04:05 MST"}}</div>
+</body>
+</html>
+`))
@@ -0,0 +1,195 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	apiv1 "github.com/bavix/apis/pkg/bavix/api/v1"
+	"github.com/bavix/apis/pkg/uuidconv"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/auth"
+	wayv2 "github.com/bavix/vakeel-way/pkg/api/vakeel_way/v2"
+)
+
+// validate converts the UUIDs of a request and checks them.
+//
+// The missing UUIDs and the nil UUID are rejected with the InvalidArgument
+// code. In strict mode, the UUIDs of the services that are not configured are
+// rejected with the NotFound code. The status carries a BadRequest detail with
+// a violation per rejected UUID, so the agent can tell which of its UUIDs are
+// misconfigured.
+//
+// Parameters:
+//   - ids: The UUIDs of the request.
+//   - registry: The ServiceRegistry of the configured services.
+//   - strict: Whether the UUIDs of the services that are not configured are rejected.
+//
+// Returns:
+//   - The converted UUIDs.
+//   - A gRPC status error if any UUID is rejected.
+func validate(ids []*apiv1.UUID, registry ServiceRegistry, strict bool) ([]uuid.UUID, error) {
+	var (
+		violations []*errdetails.BadRequest_FieldViolation
+		code       = codes.NotFound
+	)
+
+	reject := func(index int, c codes.Code, description string) {
+		// The malformed UUIDs take precedence over the unknown ones.
+		if c == codes.InvalidArgument {
+			code = c
+		}
+
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       fmt.Sprintf("ids[%d]", index),
+			Description: description,
+		})
+	}
+
+	converted := make([]uuid.UUID, 0, len(ids))
+
+	for i, id := range ids {
+		if id == nil {
+			reject(i, codes.InvalidArgument, "the UUID is missing")
+
+			continue
+		}
+
+		value := uuidconv.DoubleInt2UUID(id.GetHigh(), id.GetLow())
+
+		switch {
+		case value == uuid.Nil:
+			reject(i, codes.InvalidArgument, "the nil UUID is not a service")
+		case strict && !registry.Exists(value):
+			reject(i, codes.NotFound, fmt.Sprintf("the service %s is not configured", value))
+		default:
+			converted = append(converted, value)
+		}
+	}
+
+	if len(violations) == 0 {
+		return converted, nil
+	}
+
+	st := status.Newf(code, "%d of %d UUIDs are rejected", len(violations), len(ids))
+
+	// Attach the violations. The status is returned without them if they cannot be marshaled.
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		st = detailed
+	}
+
+	return nil, st.Err()
+}
+
+// authorize checks the UUIDs of a request against the ACL of the caller.
+//
+// The UUIDs of the services the token of the caller may not report are
+// rejected with the PermissionDenied code and logged with the name of the
+// token. The status carries a BadRequest detail with a violation per rejected
+// UUID. The anonymous callers and the tokens without an ACL may report every
+// service.
+//
+// Parameters:
+//   - ctx: The context.Context of the call, carrying the principal and the logger.
+//   - ids: The UUIDs of the request, as converted by validate.
+//   - registry: The ServiceRegistry of the configured services.
+//
+// Returns:
+//   - A gRPC status error if any UUID is rejected.
+func authorize(ctx context.Context, ids []uuid.UUID, registry ServiceRegistry) error {
+	principal, ok := auth.FromContext(ctx)
+	if !ok || !principal.ACL.Restricted() {
+		return nil
+	}
+
+	var (
+		violations []*errdetails.BadRequest_FieldViolation
+		denied     []string
+	)
+
+	for i, id := range ids {
+		if allowed(ctx, id, registry) {
+			continue
+		}
+
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       fmt.Sprintf("ids[%d]", i),
+			Description: fmt.Sprintf("the token may not report the service %s", id),
+		})
+		denied = append(denied, id.String())
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	zerolog.Ctx(ctx).Warn().
+		Str("token", principal.Name).
+		Strs("ids", denied).
+		Msg("Heartbeats of unauthorized services rejected")
+
+	st := status.Newf(codes.PermissionDenied, "%s: %d of %d UUIDs are rejected", auth.ErrForbidden, len(violations), len(ids))
+
+	// Attach the violations. The status is returned without them if they cannot be marshaled.
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+		st = detailed
+	}
+
+	return st.Err()
+}
+
+// allowed reports whether the ACL of the caller allows it to report the service.
+func allowed(ctx context.Context, id uuid.UUID, registry ServiceRegistry) bool {
+	principal, ok := auth.FromContext(ctx)
+
+	return !ok || principal.ACL.Allows(id, registry.Namespace(id))
+}
+
+// heartbeats converts the heartbeats of a request and checks them.
+//
+// The unknown statuses and the negative TTLs are rejected with the
+// InvalidArgument code.
+//
+// Parameters:
+//   - msgs: The heartbeats of the request.
+//   - ids: The UUIDs of the heartbeats, as converted by validate.
+//   - requestID: The ID of the request carrying the heartbeats, or empty.
+//
+// Returns:
+//   - The converted heartbeats, in the order of the request.
+//   - A gRPC status error if any heartbeat is rejected.
+func heartbeats(msgs []*wayv2.Heartbeat, ids []uuid.UUID, requestID string) ([]entities.Heartbeat, error) {
+	converted := make([]entities.Heartbeat, 0, len(msgs))
+
+	for i, msg := range msgs {
+		heartbeat := entities.Heartbeat{
+			ID:      ids[i],
+			Status:  entities.Up,
+			Message: msg.GetMessage(),
+			TTL:     msg.GetTtl().AsDuration(),
+
+			RequestID: requestID,
+		}
+
+		switch msg.GetStatus() {
+		case wayv2.Heartbeat_STATUS_UNSPECIFIED, wayv2.Heartbeat_STATUS_UP:
+		case wayv2.Heartbeat_STATUS_DEGRADED:
+			heartbeat.Status = entities.Degraded
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "heartbeats[%d]: the status %d is unknown", i, msg.GetStatus())
+		}
+
+		if heartbeat.TTL < 0 {
+			return nil, status.Errorf(codes.InvalidArgument, "heartbeats[%d]: the TTL is negative", i)
+		}
+
+		converted = append(converted, heartbeat)
+	}
+
+	return converted, nil
+}
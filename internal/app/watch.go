@@ -0,0 +1,95 @@
+package app
+
+import (
+	apiv1 "github.com/bavix/apis/pkg/bavix/api/v1"
+	"github.com/bavix/apis/pkg/uuidconv"
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+)
+
+// watchBuffer is the number of the transitions buffered per subscriber.
+const watchBuffer = 256
+
+// TransitionSource represents an interface for subscribing to the status transitions.
+type TransitionSource interface {
+	// Subscribe registers a subscriber of the status transitions.
+	//
+	// Parameters:
+	//   - buffer: The number of the transitions buffered for the subscriber.
+	//
+	// Returns:
+	//   - The channel of the transitions, closed when the subscriber is dropped.
+	//   - The function that unsubscribes the subscriber.
+	Subscribe(buffer int) (<-chan entities.Transition, func())
+}
+
+// WatchStatus handles the WatchStatus RPC call.
+//
+// It streams the status transitions of the selected services until the client
+// cancels the call. The services are selected by their UUIDs and labels; an
+// empty selector selects every service visible to the caller. If the client does not keep up with
+// the transitions, the stream is closed with the ResourceExhausted code.
+//
+// Parameters:
+//   - req: The WatchStatusRequest with the selector of the services.
+//   - stream: The stream of the StatusEvent messages.
+//
+// Returns:
+//   - An InvalidArgument error if any UUID is malformed.
+//   - A ResourceExhausted error if the client is too slow.
+//   - nil once the client cancels the call.
+func (s *GRPCServer) WatchStatus(req *way.WatchStatusRequest, stream way.StateService_WatchStatusServer) error {
+	registry, scoped := s.registry(stream.Context())
+
+	ids, err := validate(req.GetIds(), registry, false)
+	if err != nil {
+		return err
+	}
+
+	selected := make(map[uuid.UUID]struct{}, len(ids))
+	for _, id := range ids {
+		selected[id] = struct{}{}
+	}
+
+	events, unsubscribe := s.transitions.Subscribe(watchBuffer)
+	defer unsubscribe()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case transition, ok := <-events:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "the client does not keep up with the transitions")
+			}
+
+			if _, ok := selected[transition.ID]; len(selected) > 0 && !ok {
+				continue
+			}
+
+			// The callers scoped to a namespace see its transitions only.
+			if scoped && !registry.Exists(transition.ID) {
+				continue
+			}
+
+			if !entities.MatchLabels(req.GetLabels(), registry.Labels(transition.ID)) {
+				continue
+			}
+
+			high, low := uuidconv.UUID2DoubleInt(transition.ID)
+
+			if err := stream.Send(&way.StatusEvent{
+				Id:     &apiv1.UUID{High: high, Low: low},
+				Status: transition.Status.String(),
+				Time:   timestamppb.New(transition.At),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package build
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/app"
+)
+
+// ErrNoAdminToken is an error that indicates that the admin REST API is enabled without a token, JWTs or OIDC login.
+var ErrNoAdminToken = errors.New("admin http: the token is required")
+
+// RunAdminHTTP starts the admin REST API on the address specified by the
+// `AdminHTTP` field of the configuration. The function blocks until the
+// context is closed or an error occurs.
+//
+// The admin REST API is served on its own listener, so it can be bound to a
+// private interface while the metrics and the status page are public. If it is
+// disabled in the configuration, the function returns immediately.
+//
+// If a certificate is configured or the automatic certificates are enabled,
+// the API is served over HTTPS, and the certificate is reloaded when its files
+// change. If the OIDC login is enabled, the login, its callback and the logout
+// are served on the same listener.
+//
+// ctx - The context.Context used to stop the server.
+// Returns an error if the token cannot be decrypted or the server cannot
+// listen on the configured address.
+func (b *Builder) RunAdminHTTP(ctx context.Context) error {
+	cfg := b.config.AdminHTTP
+
+	// Do nothing if the admin REST API is disabled or the server is shutting down.
+	if !cfg.Enabled || !b.ingress.enter() {
+		return nil
+	}
+	defer b.ingress.leave()
+
+	// Get the logger from the context.
+	logger := zerolog.Ctx(ctx)
+
+	// Decrypt the token if it is stored encrypted.
+	token, err := b.Keyring().Open(cfg.Token)
+	if err != nil {
+		return err
+	}
+
+	verifier, err := b.tokenVerifier()
+	if err != nil {
+		return err
+	}
+
+	sessions, err := b.sessionAuthenticator()
+	if err != nil {
+		return err
+	}
+
+	stateServer, err := b.stateService(ctx)
+	if err != nil {
+		return err
+	}
+
+	adminServer, err := b.adminService(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The time allowed to read the request headers.
+	const readHeaderTimeout = 10 * time.Second
+
+	var handler http.Handler = app.NewAdminHandler(token, verifier, sessions, stateServer, adminServer)
+
+	// Mount the OIDC login next to the API, so the team signs in on the same origin.
+	provider, err := b.oidcProvider()
+	if err != nil {
+		return err
+	}
+
+	allowlist, err := b.networkAllowlist()
+	if err != nil {
+		return err
+	}
+
+	if provider != nil {
+		mux := http.NewServeMux()
+		mux.Handle("/", handler)
+		mux.HandleFunc("GET /auth/login", provider.Login)
+		mux.HandleFunc("GET "+provider.CallbackPath(), provider.Callback)
+		mux.HandleFunc("POST /auth/logout", provider.Logout)
+
+		handler = mux
+	}
+
+	// Restrict the API and the login to the allowed networks.
+	handler = allowlist.Middleware(handler)
+
+	// Get the TLS configuration, reloading the certificate when it is renewed.
+	tlsConfig, err := b.serverTLS(ctx, cfg.TLS)
+	if err != nil {
+		return err
+	}
+
+	//nolint:exhaustruct
+	server := &http.Server{
+		Addr:              cfg.Addr(),
+		Handler:           handler,
+		TLSConfig:         tlsConfig,
+		ReadHeaderTimeout: readHeaderTimeout,
+		BaseContext:       func(_ net.Listener) context.Context { return ctx },
+	}
+
+	// Shut the server down when the context is closed.
+	shutdown := shutdownHTTP(ctx, server)
+
+	// Log the address of the server.
+	logger.Info().Str("addr", cfg.Addr()).Msg("Starting admin REST API")
+
+	// Start serving requests, over TLS if a certificate is configured.
+	if tlsConfig != nil {
+		err = server.ListenAndServeTLS("", "")
+	} else {
+		err = server.ListenAndServe()
+	}
+
+	if !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	// Wait for the heartbeats in progress, which feed the checker.
+	<-shutdown
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package build
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/app"
+	"github.com/bavix/vakeel-way/internal/config"
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/alertmanager"
+)
+
+// ErrInvalidAlertRule is an error that indicates that a rule of the Alertmanager receiver is invalid.
+var ErrInvalidAlertRule = errors.New("alertmanager: invalid rule")
+
+// alertRules converts the rules of the Alertmanager receiver from the configuration.
+//
+// A rule has to report a service, and the firing alerts can only report it
+// down, which is the default, or degraded.
+//
+// Parameters:
+//   - cfg: The configuration of the Alertmanager receiver.
+//
+// Returns:
+//   - The rules of the receiver.
+//   - ErrInvalidAlertRule if a rule has no service or an unsupported status.
+func alertRules(cfg config.AlertmanagerConfig) ([]alertmanager.Rule, error) {
+	rules := make([]alertmanager.Rule, 0, len(cfg.Rules))
+
+	for i, rule := range cfg.Rules {
+		if rule.ID == uuid.Nil {
+			return nil, fmt.Errorf("%w: rule %d has no id", ErrInvalidAlertRule, i)
+		}
+
+		status := entities.Down
+
+		if rule.Status != "" {
+			parsed, err := entities.ParseStatus(rule.Status)
+			if err != nil || parsed == entities.Up {
+				return nil, fmt.Errorf("%w: rule %d has status %q", ErrInvalidAlertRule, i, rule.Status)
+			}
+
+			status = parsed
+		}
+
+		rules = append(rules, alertmanager.Rule{
+			Alertname: rule.Alertname,
+			Labels:    rule.Labels,
+			ID:        rule.ID,
+			Status:    status,
+		})
+	}
+
+	return rules, nil
+}
+
+// alertmanagerHandler returns the handler of the Alertmanager webhooks, which
+// feeds the statuses of the alerts into the checker.
+//
+// ctx - The context.Context of the checker.
+// Returns an error if the token cannot be decrypted or a rule is invalid.
+func (b *Builder) alertmanagerHandler(ctx context.Context) (*app.AlertmanagerHandler, error) {
+	cfg := b.config.Alertmanager
+
+	// Decrypt the token if it is stored encrypted.
+	token, err := b.Keyring().Open(cfg.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	verifier, err := b.tokenVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := alertRules(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	receiver := alertmanager.NewReceiver(cfg.Label, cfg.TTL, rules)
+
+	return app.NewAlertmanagerHandler(token, verifier, receiver, b.checkerUsecase(ctx), b.WebhookRepository()), nil
+}
@@ -0,0 +1,36 @@
+package build
+
+import (
+	"github.com/bavix/vakeel-way/internal/infra/audit"
+)
+
+// auditLog returns the audit log of the received heartbeats.
+// If the Builder instance already has a Log instance, it will be returned.
+//
+// The latest heartbeats are kept in memory, and every heartbeat is appended
+// to the configured file, if any.
+//
+// Returns:
+//   - A pointer to a Log.
+//   - An error if the file of the audit log cannot be opened.
+func (b *Builder) auditLog() (*audit.Log, error) {
+	// Check if the Builder instance already has a Log instance.
+	if b.audit != nil {
+		return b.audit, nil
+	}
+
+	options := []audit.Option{audit.WithSize(b.config.Audit.Size)}
+
+	if b.config.Audit.File != "" {
+		sink, err := audit.OpenFileSink(b.config.Audit.File)
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, audit.WithSink(sink))
+	}
+
+	b.audit = audit.NewLog(options...)
+
+	return b.audit, nil
+}
@@ -0,0 +1,217 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bavix/vakeel-way/internal/app"
+	"github.com/bavix/vakeel-way/internal/config"
+	"github.com/bavix/vakeel-way/internal/infra/auth"
+)
+
+// ErrNoJWTKey is an error that indicates that the JWTs are accepted without a secret or a JWKS URL.
+var ErrNoJWTKey = errors.New("auth: jwt requires a secret or a jwks url")
+
+var (
+	// ErrTokenName is an error that indicates that a token has no name or shares it with another token.
+	ErrTokenName = errors.New("auth: every token requires a unique name")
+
+	// ErrTokenScope is an error that indicates that a token is granted an unknown scope.
+	ErrTokenScope = errors.New("auth: unknown token scope")
+)
+
+// defaultScopes are the scopes of the tokens configured without any.
+//
+//nolint:gochecknoglobals
+var defaultScopes = []string{auth.ScopeReport, auth.ScopeRead}
+
+// validateTokens makes sure every token has its own name and known scopes.
+//
+// The quotas and the metrics are kept by the name of the token, so the tokens
+// without a name, or sharing one, would share a quota.
+//
+// Parameters:
+//   - tokens: The tokens of the configuration.
+//
+// Returns:
+//   - ErrTokenName if a name is empty or repeated.
+//   - ErrTokenScope if a scope is unknown.
+func validateTokens(tokens []config.TokenConfig) error {
+	names := make(map[string]struct{}, len(tokens))
+
+	for i, token := range tokens {
+		if token.Name == "" {
+			return fmt.Errorf("%w: the token %d has no name", ErrTokenName, i)
+		}
+
+		if _, ok := names[token.Name]; ok {
+			return fmt.Errorf("%w: %q is repeated", ErrTokenName, token.Name)
+		}
+
+		names[token.Name] = struct{}{}
+
+		for _, scope := range token.Scopes {
+			if scope != auth.ScopeReport && scope != auth.ScopeRead && scope != auth.ScopeAdmin {
+				return fmt.Errorf("%w: %q of the token %q", ErrTokenScope, scope, token.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// authenticator returns the Authenticator of the gRPC server.
+// If the Builder instance already has an Authenticator instance, it will be returned.
+//
+// The tokens are loaded from the configuration and decrypted with the keyring.
+//
+// Returns:
+//   - A pointer to an Authenticator.
+//   - An error if a token cannot be decrypted.
+func (b *Builder) authenticator() (*auth.Authenticator, error) {
+	// Check if the Builder instance already has an Authenticator instance.
+	if b.auth != nil {
+		return b.auth, nil
+	}
+
+	tokens := make([]auth.Token, 0, len(b.config.Auth.Tokens))
+
+	for _, token := range b.config.Auth.Tokens {
+		// Decrypt the token if it is stored encrypted.
+		secret, err := b.Keyring().Open(token.Token)
+		if err != nil {
+			return nil, err
+		}
+
+		// The tokens call the AdminService only if they are granted the admin scope.
+		scopes := token.Scopes
+		if len(scopes) == 0 {
+			scopes = defaultScopes
+		}
+
+		tokens = append(tokens, auth.Token{
+			Secret: secret,
+			Principal: auth.Principal{
+				Name:      token.Name,
+				Namespace: token.Namespace,
+				Quota: auth.Quota{
+					MaxIDs:              token.Quota.MaxIDs,
+					HeartbeatsPerMinute: token.Quota.HeartbeatsPerMinute,
+				},
+				ACL: auth.ACL{
+					IDs:        token.Allow.IDs,
+					Namespaces: token.Allow.Namespaces,
+				},
+				Scopes: scopes,
+			},
+		})
+	}
+
+	verifier, err := b.jwtVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	b.auth = auth.NewAuthenticator(tokens, verifier)
+
+	return b.auth, nil
+}
+
+// jwtVerifier returns the JWTVerifier of the signed tokens, or nil if they are not accepted.
+// If the Builder instance already has a JWTVerifier instance, it will be returned.
+//
+// The shared secret is decrypted with the keyring.
+//
+// Returns:
+//   - A pointer to a JWTVerifier, or nil.
+//   - An error if the secret cannot be decrypted.
+func (b *Builder) jwtVerifier() (*auth.JWTVerifier, error) {
+	cfg := b.config.Auth.JWT
+
+	// Check if the JWTs are accepted and the Builder instance already has a JWTVerifier instance.
+	if !cfg.Enabled || b.jwt != nil {
+		return b.jwt, nil
+	}
+
+	// The time allowed to fetch the JWKS.
+	const jwksTimeout = 10 * time.Second
+
+	opts := []auth.JWTOption{
+		auth.WithIssuer(cfg.Issuer),
+		auth.WithAudience(cfg.Audience),
+		auth.WithClaims(cfg.TenantClaim, cfg.ScopeClaim),
+		auth.WithLeeway(cfg.Leeway),
+	}
+
+	if cfg.Secret != "" {
+		// Decrypt the secret if it is stored encrypted.
+		secret, err := b.Keyring().Open(cfg.Secret)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, auth.WithHMAC(secret))
+	}
+
+	if cfg.JWKSURL != "" {
+		opts = append(opts, auth.WithJWKS(cfg.JWKSURL, &http.Client{Timeout: jwksTimeout})) //nolint:exhaustruct
+	}
+
+	b.jwt = auth.NewJWTVerifier(opts...)
+
+	return b.jwt, nil
+}
+
+// tokenVerifier returns the TokenVerifier of the JWTs sent to the HTTP endpoints.
+//
+// Returns:
+//   - The TokenVerifier, or nil if the JWTs are not accepted.
+//   - An error if the secret cannot be decrypted.
+func (b *Builder) tokenVerifier() (app.TokenVerifier, error) {
+	verifier, err := b.jwtVerifier()
+	if err != nil || verifier == nil {
+		return nil, err
+	}
+
+	return verifier, nil
+}
+
+// quotaLimiter returns the Limiter enforcing the quotas of the tokens.
+// If the Builder instance already has a Limiter instance, it will be returned.
+//
+// Returns:
+//   - A pointer to a Limiter.
+func (b *Builder) quotaLimiter() *auth.Limiter {
+	// Check if the Builder instance already has a Limiter instance.
+	if b.limiter != nil {
+		return b.limiter
+	}
+
+	b.limiter = auth.NewLimiter(b.metricsRegistry())
+
+	return b.limiter
+}
+
+// networkAllowlist returns the Allowlist of the networks the clients may connect from.
+// If the Builder instance already has an Allowlist instance, it will be returned.
+//
+// Returns:
+//   - A pointer to an Allowlist.
+//   - An error if a network cannot be parsed.
+func (b *Builder) networkAllowlist() (*auth.Allowlist, error) {
+	// Check if the Builder instance already has an Allowlist instance.
+	if b.allowlist != nil {
+		return b.allowlist, nil
+	}
+
+	allowlist, err := auth.NewAllowlist(b.config.Auth.Allowlist)
+	if err != nil {
+		return nil, err
+	}
+
+	b.allowlist = allowlist
+
+	return b.allowlist, nil
+}
@@ -0,0 +1,25 @@
+package build
+
+import (
+	"fmt"
+
+	"github.com/bavix/vakeel-way/internal/config"
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// validateBadges checks that the themes of the uptime badges are supported.
+//
+// Parameters:
+//   - webhooks: The configured webhooks.
+//
+// Returns:
+//   - An error naming the webhook with an unknown theme.
+func (b *Builder) validateBadges(webhooks config.Webhooks) error {
+	for id, badge := range webhooks.Badges() {
+		if err := badge.Merge(entities.DefaultBadge()).Validate(); err != nil {
+			return fmt.Errorf("%w: %s (webhook %s)", err, badge.Theme, id)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package build
+
+import (
+	"github.com/bavix/vakeel-way/internal/infra/breaker"
+	"github.com/bavix/vakeel-way/internal/infra/statsd"
+)
+
+// notifierBreaker returns the notifier wrapped with the circuit breaker of the targets.
+// If the Builder instance already has a Breaker instance, it will be returned.
+//
+// Returns:
+//   - A pointer to a Breaker.
+func (b *Builder) notifierBreaker() *breaker.Breaker {
+	// Check if the Builder instance already has a Breaker instance.
+	if b.breaker != nil {
+		return b.breaker
+	}
+
+	// Count and time the deliveries that reach the targets.
+	var sender breaker.Sender = b.notifierMux()
+	if client := b.statsdClient(); client != nil {
+		sender = statsd.NewTimedSender(sender, client)
+	}
+
+	b.breaker = breaker.NewBreaker(
+		sender,
+		b.config.Delivery.Breaker.Threshold,
+		b.config.Delivery.Breaker.Cooldown,
+		b.metricsRegistry(),
+	)
+
+	return b.breaker
+}
@@ -1,8 +1,42 @@
 package build
 
 import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/bavix/vakeel-way/internal/app"
 	"github.com/bavix/vakeel-way/internal/config"
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/domain/services"
 	"github.com/bavix/vakeel-way/internal/domain/usecases"
+	"github.com/bavix/vakeel-way/internal/infra/audit"
+	"github.com/bavix/vakeel-way/internal/infra/auth"
+	"github.com/bavix/vakeel-way/internal/infra/breaker"
+	"github.com/bavix/vakeel-way/internal/infra/cache"
+	"github.com/bavix/vakeel-way/internal/infra/capture"
+	"github.com/bavix/vakeel-way/internal/infra/cluster"
+	"github.com/bavix/vakeel-way/internal/infra/compression"
+	"github.com/bavix/vakeel-way/internal/infra/consul"
+	"github.com/bavix/vakeel-way/internal/infra/deadletter"
+	"github.com/bavix/vakeel-way/internal/infra/history"
+	"github.com/bavix/vakeel-way/internal/infra/kubernetes"
+	"github.com/bavix/vakeel-way/internal/infra/message"
+	"github.com/bavix/vakeel-way/internal/infra/mqtt"
+	"github.com/bavix/vakeel-way/internal/infra/oidc"
+	"github.com/bavix/vakeel-way/internal/infra/outbox"
+	"github.com/bavix/vakeel-way/internal/infra/reporting"
+	"github.com/bavix/vakeel-way/internal/infra/repositories"
+	"github.com/bavix/vakeel-way/internal/infra/resources"
+	"github.com/bavix/vakeel-way/internal/infra/restproxy"
+	"github.com/bavix/vakeel-way/internal/infra/secrets"
+	"github.com/bavix/vakeel-way/internal/infra/spool"
+	"github.com/bavix/vakeel-way/internal/infra/srv"
+	"github.com/bavix/vakeel-way/internal/infra/statsd"
 )
 
 // Builder is a struct that holds the configuration for building the application.
@@ -11,6 +45,144 @@ type Builder struct {
 	config config.Config
 
 	checker *usecases.Checker
+
+	stateManager *services.StateManager
+
+	stale *services.StalePolicy
+
+	repo *repositories.WebhookStubRepository
+
+	replica *services.Replica
+
+	cluster *cluster.Node
+
+	lease *kubernetes.Lease
+
+	operator *kubernetes.Operator
+
+	consul *consul.Registry
+
+	exporter *restproxy.Exporter
+
+	statsd *statsd.Client
+
+	pusher *push.Pusher
+
+	keyring *secrets.Keyring
+
+	registry *prometheus.Registry
+
+	auth *auth.Authenticator
+
+	jwt *auth.JWTVerifier
+
+	oidc *oidc.Provider
+
+	limiter *auth.Limiter
+
+	allowlist *auth.Allowlist
+
+	acme *autocert.Manager
+
+	renderer *message.Renderer
+
+	incidents *cache.Cache[string, string]
+
+	breaker *breaker.Breaker
+
+	transport *srv.Transport
+
+	capture *capture.API
+
+	silencer *services.Silencer
+
+	groups *services.Groups
+
+	intervals *services.Intervals
+
+	spool *spool.Spool
+
+	outbox *outbox.Outbox
+
+	deadLetters *deadletter.Store
+
+	letters *services.DeadLetters
+
+	history *history.Store
+
+	watcher *services.Watcher
+
+	announcer *services.Announcer
+
+	agents *services.AgentDirectory
+
+	audit *audit.Log
+
+	health *services.Health
+
+	reporter *reporting.Reporter
+
+	// serving reports whether the gRPC server accepts the heartbeats.
+	serving atomic.Bool
+
+	sizing *resources.Sizing
+
+	version string
+
+	// path is the path to the configuration file the webhooks are reloaded from.
+	path string
+
+	// reload serializes the reloads of the webhooks.
+	reload sync.Mutex
+
+	// webhooks are the webhooks of the configuration file, replaced on reload.
+	webhooks config.Webhooks
+
+	// operated are the webhooks synced from Kubernetes.
+	operated config.Webhooks
+
+	stateServer *app.GRPCServer
+
+	adminServer *app.AdminServer
+
+	// background tracks the goroutines that have to finish before the process exits.
+	background sync.WaitGroup
+
+	// ingress tracks the sources of the heartbeats, which have to return before the checker is closed.
+	ingress ingressGroup
+}
+
+// Option is a function that can be used to configure a Builder instance.
+type Option func(*Builder)
+
+// WithVersion returns an Option that sets the version of the application.
+//
+// The version is reported by the capability endpoints.
+//
+// Parameters:
+//   - version: The version of the application.
+//
+// Returns:
+//   - An Option that sets the version of the Builder.
+func WithVersion(version string) Option {
+	return func(b *Builder) {
+		b.version = version
+	}
+}
+
+// WithConfigPath returns an Option that sets the path to the configuration file.
+//
+// The webhooks are reloaded from the file by the Reload RPC.
+//
+// Parameters:
+//   - path: The path to the configuration file.
+//
+// Returns:
+//   - An Option that sets the configuration path of the Builder.
+func WithConfigPath(path string) Option {
+	return func(b *Builder) {
+		b.path = path
+	}
 }
 
 // NewBuilder creates a new instance of the Builder struct.
@@ -23,7 +195,240 @@ type Builder struct {
 // was an error reading the configuration.
 //
 //nolint:exhaustruct
-func NewBuilder(config config.Config) (*Builder, error) {
+func NewBuilder(config config.Config, options ...Option) (*Builder, error) {
+	// Read the values referenced in the AWS secret stores before they are validated.
+	if err := resolveAWS(&config); err != nil {
+		return nil, err
+	}
+
+	// Validate the replica mode before anything is started.
+	if _, err := entities.ParseMode(config.Replica.Mode); err != nil {
+		return nil, err
+	}
+
+	// Validate the overflow policy of the checker.
+	if _, err := entities.ParseOverflow(config.Checker.Overflow); err != nil {
+		return nil, err
+	}
+
+	// Validate the cascade policy of the notifications.
+	if _, err := entities.ParseCascade(config.Notifications.Cascade); err != nil {
+		return nil, err
+	}
+
+	// Load the encryption keys, so that misconfigured keys are reported on startup.
+	keyring, err := newKeyring(config.Secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse the global message template.
+	renderer, err := message.NewRenderer(config.Notifications.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the pattern of the MQTT topics.
+	if config.MQTT.Enabled {
+		if _, err := mqtt.NewTopics(config.MQTT.Topic, config.MQTT.Aliases); err != nil {
+			return nil, err
+		}
+	}
+
+	// Validate the rules of the Alertmanager receiver.
+	if config.Alertmanager.Enabled {
+		if _, err := alertRules(config.Alertmanager); err != nil {
+			return nil, err
+		}
+	}
+
+	// Require a token for the admin REST API, so it is never served
+	// unauthenticated, unless the JWTs or the OIDC logins are accepted.
+	if config.AdminHTTP.Enabled && config.AdminHTTP.Token == "" && !config.Auth.JWT.Enabled && !config.AdminHTTP.OIDC.Enabled {
+		return nil, ErrNoAdminToken
+	}
+
+	// Load the certificates of the listeners, so that a missing file is reported on startup.
+	if err := validateTLS(config.GRPC.TLS); err != nil {
+		return nil, err
+	}
+
+	if err := validateTLS(config.AdminHTTP.TLS); err != nil {
+		return nil, err
+	}
+
+	// Validate the additional listeners of the gRPC server.
+	if err := validateListeners(config.GRPC); err != nil {
+		return nil, err
+	}
+
+	// Validate the automatic certificates.
+	if err := validateACME(config.ACME); err != nil {
+		return nil, err
+	}
+
+	// Validate the networks of the allowlist.
+	if _, err := auth.NewAllowlist(config.Auth.Allowlist); err != nil {
+		return nil, err
+	}
+
+	// Validate the OIDC login, so no user of the provider signs in unrestricted.
+	if config.AdminHTTP.OIDC.Enabled {
+		if err := validateOIDC(config.AdminHTTP.OIDC); err != nil {
+			return nil, err
+		}
+	}
+
+	// Require a unique name for every token, so no tokens share a quota.
+	if err := validateTokens(config.Auth.Tokens); err != nil {
+		return nil, err
+	}
+
+	// Require a key for the JWTs, so no token is accepted unverified.
+	if config.Auth.JWT.Enabled && config.Auth.JWT.Secret == "" && config.Auth.JWT.JWKSURL == "" {
+		return nil, ErrNoJWTKey
+	}
+
+	// Refuse the UDP heartbeats that would bypass the authentication.
+	if err := validateUDP(config.UDP, config.Auth, keyring); err != nil {
+		return nil, err
+	}
+
 	// Create a new instance of the Builder struct with the configuration.
-	return &Builder{config: config}, nil
+	builder := &Builder{config: config, webhooks: config.Webhooks, keyring: keyring, renderer: renderer, version: "dev"}
+
+	// Apply any optional configurations provided through the options parameter.
+	for _, option := range options {
+		option(builder)
+	}
+
+	// Create the reporter of the errors, so that an invalid DSN is reported on startup.
+	if _, err := builder.errorReporter(); err != nil {
+		return nil, err
+	}
+
+	// Join the cluster, so that a misconfigured password is reported on startup.
+	if _, err := builder.clusterNode(); err != nil {
+		return nil, err
+	}
+
+	// Create the exporter of the events, so that a misconfigured password is reported on startup.
+	if _, err := builder.restProxyExporter(); err != nil {
+		return nil, err
+	}
+
+	// Create the pusher of the metrics, so that a misconfigured password is reported on startup.
+	if _, err := builder.metricsPusher(); err != nil {
+		return nil, err
+	}
+
+	// Make sure the address of the StatsD server has a port.
+	if err := validateStatsD(config.StatsD); err != nil {
+		return nil, err
+	}
+
+	// Find the API server, so that the leader election is not started outside of a cluster.
+	if _, err := builder.kubernetesLease(); err != nil {
+		return nil, err
+	}
+
+	// Find the API server, so that the webhooks are not watched outside of a cluster.
+	if _, err := builder.kubernetesOperator(); err != nil {
+		return nil, err
+	}
+
+	// Make sure the gRPC endpoint can be registered in Consul.
+	if config.Consul.Enabled {
+		if _, err := builder.consulRegistry(); err != nil {
+			return nil, err
+		}
+
+		if _, err := builder.consulService(); err != nil {
+			return nil, err
+		}
+	}
+
+	// Make sure every target can be delivered to.
+	if err := builder.validateTargets(config.Webhooks); err != nil {
+		return nil, err
+	}
+
+	// Make sure every badge can be rendered.
+	if err := builder.validateBadges(config.Webhooks); err != nil {
+		return nil, err
+	}
+
+	// Register the compressors of the gRPC messages before the server starts.
+	if err := compression.Register(config.GRPC.Compression.Algorithms, config.GRPC.Compression.Level); err != nil {
+		return nil, err
+	}
+
+	// Make sure every schedule can be followed.
+	if err := builder.validateSchedules(config.Webhooks); err != nil {
+		return nil, err
+	}
+
+	// Make sure the dependencies of the webhooks form no cycle.
+	if err := builder.validateDependencies(config.Webhooks); err != nil {
+		return nil, err
+	}
+
+	// Make sure every group aggregates configured webhooks.
+	if err := builder.validateGroups(config.Groups, config.Webhooks); err != nil {
+		return nil, err
+	}
+
+	// Load the dead letters, so that a corrupted store is reported on startup.
+	if _, err := builder.deadLetterStore(); err != nil {
+		return nil, err
+	}
+
+	// Load the queued notifications, so that a corrupted queue is reported on startup.
+	if _, err := builder.deliverySpool(); err != nil {
+		return nil, err
+	}
+
+	// Open the outbox, so that a locked or corrupted queue is reported on startup.
+	if _, err := builder.deliveryOutbox(); err != nil {
+		return nil, err
+	}
+
+	// Load the status history, so that a corrupted history is reported on startup.
+	if _, err := builder.historyStore(); err != nil {
+		return nil, err
+	}
+
+	// Load the configured maintenance windows.
+	if builder.silencer, err = builder.newSilencer(); err != nil {
+		return nil, err
+	}
+
+	return builder, nil
+}
+
+// Prepare builds the services shared by the servers.
+//
+// The servers are started in their own goroutines, so the shared services
+// are built once, before any of them starts, instead of concurrently.
+//
+// ctx - The context.Context holding the logger.
+// Returns an error if a shared service cannot be built.
+func (b *Builder) Prepare(ctx context.Context) error {
+	b.stateManagerService(ctx)
+
+	if _, err := b.stateService(ctx); err != nil {
+		return err
+	}
+
+	_, err := b.adminService(ctx)
+
+	return err
+}
+
+// Wait blocks until the background goroutines finish, e.g. the status history
+// is persisted, and the reported errors are sent. It is called after the
+// context of the servers is canceled.
+func (b *Builder) Wait() {
+	b.background.Wait()
+	b.reporter.Flush(flushTimeout)
 }
@@ -0,0 +1,177 @@
+package build
+
+import (
+	"slices"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// capabilities describes the features enabled on the instance.
+//
+// Returns:
+//   - The entities.Capabilities of the instance.
+func (b *Builder) capabilities() entities.Capabilities {
+	caps := entities.Capabilities{
+		Version:   b.version,
+		Protocols: entities.Protocols(),
+		Notifiers: b.notifierMux().Types(),
+		Storage:   "config",
+		Auth:      "none",
+		Features:  []string{"agent-config", "agent-handshake", "dead-letters", "degraded-status", "escalation", "history", "incidents", "replica", "reports", "silences", "status-query", "status-watch", "uptime-query"},
+	}
+
+	if len(b.config.Auth.Tokens) > 0 {
+		caps.Auth = "token"
+		caps.Features = append(caps.Features, "quotas", "acl")
+	}
+
+	if b.config.Auth.JWT.Enabled {
+		if caps.Auth == "none" {
+			caps.Auth = "jwt"
+		}
+
+		caps.Features = append(caps.Features, "jwt")
+	}
+
+	// Any listener of the gRPC server may accept the TLS connections.
+	tls := b.config.GRPC.TLS.Enabled() || b.config.ACME.Enabled
+	for _, listener := range b.config.GRPC.Listeners {
+		tls = tls || listener.TLS.Enabled()
+	}
+
+	if tls {
+		caps.Features = append(caps.Features, "tls")
+	}
+
+	if len(b.config.GRPC.Listeners) > 0 {
+		caps.Features = append(caps.Features, "listeners")
+	}
+
+	if b.config.Secrets.Vault.Address != "" {
+		caps.Features = append(caps.Features, "vault")
+	}
+
+	if b.config.ACME.Enabled {
+		caps.Features = append(caps.Features, "acme")
+	}
+
+	if len(b.config.Auth.Allowlist) > 0 {
+		caps.Features = append(caps.Features, "allowlist")
+	}
+
+	if b.config.AdminHTTP.Enabled && b.config.AdminHTTP.OIDC.Enabled {
+		caps.Features = append(caps.Features, "oidc")
+	}
+
+	if b.config.Consul.Enabled {
+		caps.Features = append(caps.Features, "consul")
+	}
+
+	if b.config.Operator.Enabled {
+		caps.Features = append(caps.Features, "operator")
+	}
+
+	if len(b.config.Namespaces) > 0 {
+		caps.Features = append(caps.Features, "namespaces")
+	}
+
+	if b.config.Audit.Size > 0 || b.config.Audit.File != "" {
+		caps.Features = append(caps.Features, "audit")
+	}
+
+	if len(b.config.Groups) > 0 {
+		caps.Features = append(caps.Features, "groups")
+	}
+
+	if len(b.config.Webhooks.Dependencies()) > 0 {
+		caps.Features = append(caps.Features, "dependencies")
+	}
+
+	for _, algorithm := range b.config.GRPC.Compression.Algorithms {
+		caps.Features = append(caps.Features, "compression-"+algorithm)
+	}
+
+	if b.config.Anomaly.Enabled {
+		caps.Features = append(caps.Features, "anomaly-detection")
+	}
+
+	if b.config.Cluster.Enabled {
+		caps.Features = append(caps.Features, "cluster")
+	}
+
+	if b.config.LeaderElection.Enabled {
+		caps.Features = append(caps.Features, "leader-election")
+	}
+
+	if b.config.KafkaRESTProxy.Enabled {
+		caps.Features = append(caps.Features, "kafka-rest-proxy-export")
+	}
+
+	if b.config.MQTT.Enabled {
+		caps.Features = append(caps.Features, "mqtt")
+	}
+
+	if b.config.Pushgateway.Enabled {
+		caps.Features = append(caps.Features, "pushgateway")
+	}
+
+	if b.config.StatsD.Enabled {
+		caps.Features = append(caps.Features, "statsd")
+	}
+
+	if b.config.UDP.Enabled {
+		caps.Features = append(caps.Features, "udp")
+	}
+
+	if b.config.Alertmanager.Enabled {
+		caps.Features = append(caps.Features, "alertmanager")
+	}
+
+	if b.config.NATS.Enabled {
+		caps.Features = append(caps.Features, "nats")
+	}
+
+	if b.config.Notifications.Throttle > 0 {
+		caps.Features = append(caps.Features, "throttling")
+	}
+
+	if b.config.Delivery.Spool != "" {
+		caps.Features = append(caps.Features, "durable-spool")
+	}
+
+	if b.config.Delivery.Outbox.Path != "" {
+		caps.Features = append(caps.Features, "outbox")
+	}
+
+	if b.config.Delivery.Breaker.Threshold > 0 {
+		caps.Features = append(caps.Features, "circuit-breaker")
+	}
+
+	if b.config.Stale.After > 0 {
+		caps.Features = append(caps.Features, "stale-cleanup")
+	}
+
+	if b.config.StatusPage.Enabled {
+		caps.Features = append(caps.Features, "status-page")
+	}
+
+	if b.config.AdminHTTP.Enabled {
+		caps.Features = append(caps.Features, "admin-rest")
+	}
+
+	if b.reporter != nil && b.reporter.Enabled() {
+		caps.Features = append(caps.Features, "error-reporting")
+	}
+
+	if b.config.Debug.Enabled {
+		caps.Features = append(caps.Features, "debug")
+	}
+
+	if b.config.HTTP.Enabled {
+		caps.Features = append(caps.Features, "badges", "health-probes", "metrics")
+	}
+
+	slices.Sort(caps.Features)
+
+	return caps
+}
@@ -0,0 +1,48 @@
+package build
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bavix/vakeel-way/internal/domain/usecases"
+)
+
+// registerCheckerMetrics registers the metrics of the buffer of the heartbeats.
+//
+// The metrics let the operators size the buffer for the bursty agents: the
+// depth and the high watermark are compared to the capacity, and the dropped
+// heartbeats show that the buffer is too small.
+//
+// Parameters:
+//   - checker: The Checker whose buffer is reported.
+func (b *Builder) registerCheckerMetrics(checker *usecases.Checker) {
+	b.metricsRegistry().MustRegister(
+		prometheus.NewCounterFunc(prometheus.CounterOpts{ //nolint:exhaustruct
+			Namespace: "vakeel",
+			Name:      "checker_dropped_total",
+			Help:      "Number of heartbeats dropped by the overflow policy because the buffer was full.",
+		}, func() float64 {
+			return float64(checker.Dropped())
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{ //nolint:exhaustruct
+			Namespace: "vakeel",
+			Name:      "checker_queue_depth",
+			Help:      "Number of heartbeats buffered and waiting to be processed.",
+		}, func() float64 {
+			return float64(checker.Depth())
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{ //nolint:exhaustruct
+			Namespace: "vakeel",
+			Name:      "checker_queue_high_watermark",
+			Help:      "Highest number of heartbeats buffered at once since the start.",
+		}, func() float64 {
+			return float64(checker.HighWatermark())
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{ //nolint:exhaustruct
+			Namespace: "vakeel",
+			Name:      "checker_queue_capacity",
+			Help:      "Size of the buffer of the heartbeats, set with resources.event_buffer.",
+		}, func() float64 {
+			return float64(checker.Capacity())
+		}),
+	)
+}
@@ -0,0 +1,129 @@
+package build
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/infra/cluster"
+)
+
+// clusterNode returns the Node joining the instance to the cluster.
+// If the Builder instance already has a Node instance, it will be returned.
+//
+// Returns:
+//   - A pointer to a Node, or nil if the clustered mode is disabled.
+//   - An error if the password of the Redis server cannot be decrypted.
+func (b *Builder) clusterNode() (*cluster.Node, error) {
+	cfg := b.config.Cluster
+
+	// Check if the Builder instance already has a Node instance.
+	if b.cluster != nil || !cfg.Enabled {
+		return b.cluster, nil
+	}
+
+	// Decrypt the password if it is stored encrypted.
+	password, err := b.Keyring().Open(cfg.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	// The name of the node has to be unique in the cluster.
+	node := cfg.Node
+	if node == "" {
+		node = uuid.NewString()
+	}
+
+	//nolint:exhaustruct
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Address,
+		Password: password,
+		DB:       cfg.DB,
+	})
+
+	b.cluster = cluster.NewNode(
+		client,
+		node,
+		cluster.WithPrefix(cfg.Prefix),
+		cluster.WithLease(cfg.Lease),
+		cluster.WithBufferSize(b.resourceSizing().EventBuffer),
+	)
+
+	return b.cluster, nil
+}
+
+// RunCluster joins the instance to the cluster specified by the `Cluster`
+// field of the configuration. The heartbeats received by the instance, and its
+// status transitions while it is active, are shared with the other instances.
+// The leader is elected by StartElection. The function blocks until the
+// context is closed.
+//
+// If the clustered mode is disabled in the configuration, the function returns
+// immediately.
+//
+// ctx - The context.Context used to leave the cluster.
+// Returns an error if the Redis server cannot be reached on startup.
+func (b *Builder) RunCluster(ctx context.Context) error {
+	// The Node is created by NewBuilder.
+	node := b.cluster
+
+	// Do nothing if the clustered mode is disabled or the server is shutting down.
+	if node == nil || !b.ingress.enter() {
+		return nil
+	}
+	defer b.ingress.leave()
+
+	// The time allowed to reach the Redis server on startup.
+	const pingTimeout = 5 * time.Second
+
+	pingCtx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+
+	if err := node.Ping(pingCtx); err != nil {
+		return err
+	}
+
+	logger := zerolog.Ctx(ctx)
+	replica := b.replicaService(ctx)
+	watcher := b.statusWatcher()
+
+	// The number of the transitions buffered before the node is resubscribed.
+	const buffer = 1024
+
+	// Share the status transitions of the active node with the standby nodes.
+	go func() {
+		transitions, unsubscribe := watcher.Subscribe(buffer)
+		defer func() { unsubscribe() }()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case transition, ok := <-transitions:
+				if !ok {
+					logger.Warn().Msg("Cluster missed status transitions, resubscribing")
+
+					transitions, unsubscribe = watcher.Subscribe(buffer)
+
+					continue
+				}
+
+				if replica.Active() {
+					node.Transition(transition)
+				}
+			}
+		}
+	}()
+
+	logger.Info().
+		Str("node", node.ID()).
+		Str("addr", b.config.Cluster.Address).
+		Msg("Joining cluster")
+
+	node.Run(ctx, b.checkerUsecase(ctx).Remote)
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package build
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/infra/consul"
+)
+
+// ErrConsulPort is an error that indicates that the gRPC endpoint cannot be registered in Consul.
+var ErrConsulPort = errors.New("consul: the gRPC server does not listen on a TCP port")
+
+// consulRegistry returns the Registry of the Consul agent.
+// If the Builder instance already has a Registry instance, it will be returned.
+//
+// Returns:
+//   - A pointer to a Registry, or nil if the registration is disabled.
+//   - An error if the token cannot be decrypted.
+func (b *Builder) consulRegistry() (*consul.Registry, error) {
+	cfg := b.config.Consul
+
+	// Check if the Builder instance already has a Registry instance.
+	if b.consul != nil || !cfg.Enabled {
+		return b.consul, nil
+	}
+
+	// Decrypt the token if it is stored encrypted.
+	token, err := b.Keyring().Open(cfg.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	// The time allowed to call the agent.
+	const timeout = 10 * time.Second
+
+	b.consul = consul.NewRegistry(
+		cfg.Address,
+		consul.WithToken(token),
+		consul.WithHTTPClient(&http.Client{Timeout: timeout}), //nolint:exhaustruct
+	)
+
+	return b.consul, nil
+}
+
+// consulService returns the service of the gRPC endpoint registered in Consul.
+//
+// The readiness probe of the HTTP server is the health check if the HTTP
+// server is enabled, otherwise the TCP port of the gRPC server is checked.
+//
+// Returns:
+//   - The Service of the instance.
+//   - ErrConsulPort if the gRPC server does not listen on a TCP port.
+//   - An error if the hostname cannot be read.
+//
+//nolint:exhaustruct
+func (b *Builder) consulService() (consul.Service, error) {
+	cfg := b.config.Consul
+
+	port, err := strconv.Atoi(b.config.GRPC.Port)
+	if err != nil || b.config.GRPC.Network == "unix" {
+		return consul.Service{}, fmt.Errorf("%w: %s", ErrConsulPort, b.config.GRPC.Addr())
+	}
+
+	// The address of the node of the agent is used for the servers listening on all interfaces.
+	address := cfg.Advertise
+	if address == "" && !unspecified(b.config.GRPC.Host) {
+		address = b.config.GRPC.Host
+	}
+
+	// The checks are run by the local agent.
+	checked := address
+	if checked == "" {
+		checked = "127.0.0.1"
+	}
+
+	id := cfg.ID
+	if id == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return consul.Service{}, err
+		}
+
+		id = cfg.Name + "-" + hostname + "-" + b.config.GRPC.Port
+	}
+
+	check := consul.Check{
+		TCP:             net.JoinHostPort(checked, b.config.GRPC.Port),
+		Interval:        cfg.Check.Interval,
+		Timeout:         cfg.Check.Timeout,
+		DeregisterAfter: cfg.Check.DeregisterAfter,
+	}
+
+	if b.config.HTTP.Enabled {
+		host := b.config.HTTP.Host
+		if cfg.Advertise != "" || unspecified(host) {
+			host = checked
+		}
+
+		check.HTTP = "http://" + net.JoinHostPort(host, b.config.HTTP.Port) + "/readyz"
+	}
+
+	return consul.Service{
+		ID:      id,
+		Name:    cfg.Name,
+		Address: address,
+		Port:    port,
+		Tags:    cfg.Tags,
+		Meta:    map[string]string{"version": b.version},
+		Check:   check,
+	}, nil
+}
+
+// unspecified reports whether the host listens on all interfaces.
+func unspecified(host string) bool {
+	ip := net.ParseIP(host)
+
+	return host == "" || (ip != nil && ip.IsUnspecified())
+}
+
+// StartConsul registers the gRPC endpoint in Consul, as specified by the
+// `Consul` field of the configuration, in the background. The registration is
+// retried until the agent accepts it, and the endpoint is deregistered when
+// the context is closed, before Wait returns.
+//
+// If the registration is disabled in the configuration, the function does nothing.
+//
+// ctx - The context.Context holding the logger and deregistering the endpoint.
+func (b *Builder) StartConsul(ctx context.Context) {
+	// The registry and the service are validated in NewBuilder, so the errors are ignored here.
+	registry, _ := b.consulRegistry()
+	if registry == nil {
+		return
+	}
+
+	service, _ := b.consulService()
+	logger := zerolog.Ctx(ctx).With().Str("id", service.ID).Logger()
+
+	const (
+		// retry is the time waited before the rejected registration is retried.
+		retry = 10 * time.Second
+
+		// timeout is the time allowed to deregister the endpoint on shutdown.
+		timeout = 5 * time.Second
+	)
+
+	b.background.Add(1)
+
+	go func() {
+		defer b.background.Done()
+
+		for {
+			err := registry.Register(ctx, service)
+			if err == nil {
+				logger.Info().Str("name", service.Name).Msg("Registered in Consul")
+
+				break
+			}
+
+			logger.Warn().Err(err).Msg("Failed to register in Consul")
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retry):
+			}
+		}
+
+		<-ctx.Done()
+
+		// The context is closed, so the endpoint is deregistered with a fresh one.
+		deregister, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+		defer cancel()
+
+		if err := registry.Deregister(deregister, service.ID); err != nil {
+			logger.Warn().Err(err).Msg("Failed to deregister from Consul")
+
+			return
+		}
+
+		logger.Info().Msg("Deregistered from Consul")
+	}()
+}
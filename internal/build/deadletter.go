@@ -0,0 +1,79 @@
+package build
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bavix/vakeel-way/internal/domain/services"
+	"github.com/bavix/vakeel-way/internal/infra/deadletter"
+)
+
+// deadLetterStore returns the store of the notifications that could not be delivered.
+// If the Builder instance already has a Store instance, it will be returned.
+//
+// The dead letters are loaded from the file configured in the delivery
+// section, so the dead letters kept before a restart can be replayed as well.
+//
+// Returns:
+//   - A pointer to a Store.
+//   - An error if the file cannot be read.
+func (b *Builder) deadLetterStore() (*deadletter.Store, error) {
+	// Check if the Builder instance already has a Store instance.
+	if b.deadLetters != nil {
+		return b.deadLetters, nil
+	}
+
+	store, err := deadletter.Open(b.config.Delivery.DeadLetters.File, b.config.Delivery.DeadLetters.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	b.metricsRegistry().MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{ //nolint:exhaustruct
+			Namespace: "vakeel",
+			Name:      "dead_letters",
+			Help:      "Number of undelivered notifications kept as dead letters.",
+		}, func() float64 {
+			return float64(store.Len())
+		}),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{ //nolint:exhaustruct
+			Namespace: "vakeel",
+			Name:      "dead_letters_total",
+			Help:      "Number of notifications moved to the dead letters since the start.",
+		}, func() float64 {
+			return float64(store.Added())
+		}),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{ //nolint:exhaustruct
+			Namespace: "vakeel",
+			Name:      "dead_letters_dropped_total",
+			Help:      "Number of dead letters dropped because the store was full.",
+		}, func() float64 {
+			return float64(store.Dropped())
+		}),
+	)
+
+	b.deadLetters = store
+
+	return b.deadLetters, nil
+}
+
+// deadLetterService returns the DeadLetters service.
+// If the Builder instance already has a DeadLetters instance, it will be returned.
+//
+// The dead letters are replayed without the outbox, so the operator learns
+// right away whether the target accepts them.
+//
+// Returns:
+//   - A pointer to a DeadLetters service.
+func (b *Builder) deadLetterService() *services.DeadLetters {
+	// Check if the Builder instance already has a DeadLetters instance.
+	if b.letters != nil {
+		return b.letters
+	}
+
+	// The store is loaded in NewBuilder, so the error can be ignored here.
+	store, _ := b.deadLetterStore()
+
+	b.letters = services.NewDeadLetters(store, b.notifierBreaker(), b.WebhookRepository())
+
+	return b.letters
+}
@@ -0,0 +1,93 @@
+package build
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"net"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/app"
+)
+
+// RunDebugServer starts the debug listener on the address specified by the
+// `Debug` field of the configuration. The listener exposes net/http/pprof on
+// /debug/pprof/, expvar on /debug/vars and a dump of the goroutines on
+// /debug/goroutines. The function blocks until the context is closed or an
+// error occurs.
+//
+// If the debug listener is disabled in the configuration, the function returns
+// immediately.
+//
+// ctx - The context.Context used to stop the server.
+// Returns an error if the server cannot listen on the configured address.
+func (b *Builder) RunDebugServer(ctx context.Context) error {
+	cfg := b.config.Debug
+
+	// Do nothing if the debug listener is disabled.
+	if !cfg.Enabled {
+		return nil
+	}
+
+	// Get the logger from the context.
+	logger := zerolog.Ctx(ctx)
+
+	b.publishVars(ctx)
+
+	// The time allowed to read the request headers.
+	const readHeaderTimeout = 10 * time.Second
+
+	//nolint:exhaustruct
+	server := &http.Server{
+		Addr:              cfg.Addr(),
+		Handler:           app.NewDebugHandler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+		BaseContext:       func(_ net.Listener) context.Context { return ctx },
+	}
+
+	// Shut the server down when the context is closed.
+	go func() {
+		<-ctx.Done()
+
+		//nolint:contextcheck
+		_ = server.Shutdown(context.Background())
+	}()
+
+	// Log the address of the server.
+	logger.Warn().Str("addr", cfg.Addr()).Msg("Starting debug listener")
+
+	// Start serving requests.
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// publishVars publishes the runtime variables of the application to expvar,
+// next to the memory statistics and the command line published by expvar.
+//
+// Parameters:
+//   - ctx: The context.Context holding the logger.
+func (b *Builder) publishVars(ctx context.Context) {
+	checker := b.checkerUsecase(ctx)
+	manager := b.stateManagerService(ctx)
+	repo := b.WebhookRepository()
+
+	expvar.Publish("vakeel", expvar.Func(func() any {
+		return map[string]any{
+			"version":                b.version,
+			"goroutines":             runtime.NumGoroutine(),
+			"webhooks":               len(repo.All()),
+			"tracked_services":       manager.Tracked(),
+			"checker_depth":          checker.Depth(),
+			"checker_capacity":       checker.Capacity(),
+			"checker_high_watermark": checker.HighWatermark(),
+			"checker_dropped":        checker.Dropped(),
+		}
+	}))
+}
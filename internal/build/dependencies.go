@@ -0,0 +1,78 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/config"
+)
+
+var (
+	// ErrUnknownDependency is an error that indicates that a webhook depends on a webhook that is not configured.
+	ErrUnknownDependency = errors.New("dependencies: the webhook is not configured")
+
+	// ErrDependencyCycle is an error that indicates that the webhooks depend on each other.
+	ErrDependencyCycle = errors.New("dependencies: the webhooks depend on each other")
+)
+
+// validateDependencies checks that the webhooks depend on the configured
+// webhooks, and that no webhook depends on itself, even indirectly.
+//
+// Parameters:
+//   - webhooks: The configured webhooks.
+//
+// Returns:
+//   - An error naming the webhook with an invalid dependency.
+func (b *Builder) validateDependencies(webhooks config.Webhooks) error {
+	dependencies := webhooks.Dependencies()
+	configured := webhooks.AsMap()
+
+	for id, parents := range dependencies {
+		for _, parent := range parents {
+			if _, ok := configured[parent]; !ok {
+				return fmt.Errorf("%w: %s (webhook %s)", ErrUnknownDependency, parent, id)
+			}
+		}
+	}
+
+	// The webhooks whose dependencies are checked, and the ones being checked.
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	marks := make(map[uuid.UUID]int, len(dependencies))
+
+	var visit func(id uuid.UUID) error
+
+	visit = func(id uuid.UUID) error {
+		switch marks[id] {
+		case visiting:
+			return fmt.Errorf("%w: %s", ErrDependencyCycle, id)
+		case visited:
+			return nil
+		}
+
+		marks[id] = visiting
+
+		for _, parent := range dependencies[id] {
+			if err := visit(parent); err != nil {
+				return err
+			}
+		}
+
+		marks[id] = visited
+
+		return nil
+	}
+
+	for id := range dependencies {
+		if err := visit(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
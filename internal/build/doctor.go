@@ -0,0 +1,122 @@
+package build
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/bavix/vakeel-way/internal/config"
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/diagnostics"
+	"github.com/bavix/vakeel-way/internal/infra/instatus"
+)
+
+// Diagnose checks the environment the configuration is run in without starting
+// the servers.
+//
+// It checks that the addresses of the enabled listeners can be bound, that the
+// hosts of the targets resolve and accept connections, and that Instatus knows
+// the webhooks of the Instatus targets. The checks are independent, so every
+// problem is reported at once.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the checks.
+//   - timeout: The maximum time of a single network check.
+//
+// Returns:
+//   - The result of every check, in a stable order.
+func (b *Builder) Diagnose(ctx context.Context, timeout time.Duration) []entities.Probe {
+	listeners := []struct {
+		name    string
+		enabled bool
+		network string
+		address string
+	}{
+		{"grpc", true, b.config.GRPC.Network, b.config.GRPC.Addr()},
+		{"http", b.config.HTTP.Enabled, "tcp", b.config.HTTP.Addr()},
+		{"status_page", b.config.StatusPage.Enabled, "tcp", b.config.StatusPage.Addr()},
+		{"admin_http", b.config.AdminHTTP.Enabled, "tcp", b.config.AdminHTTP.Addr()},
+		{"debug", b.config.Debug.Enabled, "tcp", b.config.Debug.Addr()},
+		{"udp", b.config.UDP.Enabled, "udp", b.config.UDP.Addr()},
+	}
+
+	// The additional listeners of the gRPC server follow the main one.
+	for _, listener := range b.grpcListeners()[1:] {
+		listeners = append(listeners, struct {
+			name    string
+			enabled bool
+			network string
+			address string
+		}{"grpc " + listener.Name, true, listener.Network, listener.Address})
+	}
+
+	probes := make([]entities.Probe, 0, len(listeners))
+
+	for _, listener := range listeners {
+		if listener.enabled {
+			probes = append(probes, entities.Probe{
+				Name: "listen " + listener.name + " " + listener.address,
+				Err:  diagnostics.Bindable(listener.network, listener.address),
+			})
+		}
+	}
+
+	repo := b.WebhookRepository()
+
+	ids := repo.All()
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+
+	for _, id := range ids {
+		// Decrypt the targets, exactly as the server does.
+		targets, err := repo.Get(ctx, id)
+		if err != nil {
+			probes = append(probes, entities.Probe{Name: "webhook " + id.String(), Err: err})
+
+			continue
+		}
+
+		for _, target := range targets {
+			endpoint := target.URL
+			if endpoint == "" && target.Type == config.TargetInstatus && instatus.UsesAPI(target) {
+				endpoint = instatus.APIURL
+			}
+
+			if endpoint == "" {
+				continue
+			}
+
+			name := id.String() + "/" + target.Name
+
+			reach := probe(ctx, timeout, func(ctx context.Context) error { return diagnostics.Reachable(ctx, endpoint) })
+			probes = append(probes, entities.Probe{Name: "reach " + name, Err: reach})
+
+			// The credentials can only be verified on a reachable host.
+			if reach == nil && target.Type == config.TargetInstatus {
+				probes = append(probes, entities.Probe{
+					Name: "instatus " + name,
+					Err: probe(ctx, timeout, func(ctx context.Context) error {
+						return b.inStatusClient().Verify(ctx, target)
+					}),
+				})
+			}
+		}
+	}
+
+	return probes
+}
+
+// probe runs the check with the timeout.
+//
+// Parameters:
+//   - ctx: The parent context.Context.
+//   - timeout: The maximum time of the check.
+//   - check: The check.
+//
+// Returns:
+//   - The error of the check.
+func probe(ctx context.Context, timeout time.Duration, check func(context.Context) error) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return check(ctx)
+}
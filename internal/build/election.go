@@ -0,0 +1,126 @@
+package build
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/domain/services"
+	"github.com/bavix/vakeel-way/internal/infra/kubernetes"
+)
+
+// kubernetesLease returns the Lease electing the leader among the replicas.
+// If the Builder instance already has a Lease instance, it will be returned.
+//
+// Returns:
+//   - A pointer to a Lease, or nil if the leader election is disabled.
+//   - An error if the API server, the namespace or the CA bundle cannot be found.
+func (b *Builder) kubernetesLease() (*kubernetes.Lease, error) {
+	cfg := b.config.LeaderElection
+
+	// Check if the Builder instance already has a Lease instance.
+	if b.lease != nil || !cfg.Enabled {
+		return b.lease, nil
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		var err error
+
+		if endpoint, err = kubernetes.Endpoint(); err != nil {
+			return nil, err
+		}
+	}
+
+	namespace := cfg.Namespace
+	if namespace == "" {
+		var err error
+
+		if namespace, err = kubernetes.Namespace(); err != nil {
+			return nil, err
+		}
+	}
+
+	// The name of the pod is unique in the deployment.
+	identity := cfg.Identity
+	if identity == "" {
+		var err error
+
+		if identity, err = os.Hostname(); err != nil {
+			return nil, err
+		}
+	}
+
+	client, err := kubernetes.NewHTTPClient(cfg.CAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	b.lease = kubernetes.NewLease(
+		namespace,
+		cfg.Name,
+		identity,
+		kubernetes.WithEndpoint(endpoint),
+		kubernetes.WithTokenFile(cfg.TokenFile),
+		kubernetes.WithHTTPClient(client),
+		kubernetes.WithDuration(cfg.Lease),
+	)
+
+	return b.lease, nil
+}
+
+// coordinator returns the Coordinator of the replica with the duration of its lease.
+//
+// The Kubernetes Lease takes precedence over the lease of the clustered mode.
+// Both are validated in NewBuilder, so the errors are ignored here.
+//
+// Returns:
+//   - The Coordinator, or nil if the instance takes part in no election.
+//   - The duration of the lease of the Coordinator.
+func (b *Builder) coordinator() (services.Coordinator, time.Duration) {
+	if lease, _ := b.kubernetesLease(); lease != nil {
+		return lease, lease.Duration()
+	}
+
+	if node := b.cluster; node != nil {
+		return node, node.Lease()
+	}
+
+	return nil, 0
+}
+
+// StartElection takes part in the leader election specified by the
+// `LeaderElection` or the `Cluster` field of the configuration in the
+// background. The instance is promoted once it is elected, and demoted once it
+// loses the lease. The lease is released when the context is closed, before
+// Wait returns.
+//
+// If neither is enabled in the configuration, the function does nothing.
+//
+// ctx - The context.Context used to leave the election.
+func (b *Builder) StartElection(ctx context.Context) {
+	coordinator, lease := b.coordinator()
+
+	// Do nothing if the instance takes part in no election.
+	if coordinator == nil {
+		return
+	}
+
+	// The leader renews the lease three times per lease, so a renewal may fail
+	// without losing the leadership.
+	const renewals = 3
+
+	zerolog.Ctx(ctx).Info().Dur("lease", lease).Msg("Joining leader election")
+
+	replica := b.replicaService(ctx)
+
+	b.background.Add(1)
+
+	go func() {
+		defer b.background.Done()
+
+		replica.Elect(ctx, lease/renewals)
+	}()
+}
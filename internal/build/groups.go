@@ -0,0 +1,88 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/config"
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/domain/services"
+)
+
+// ErrInvalidGroup is an error that indicates that a service group is invalid.
+var ErrInvalidGroup = errors.New("groups: invalid group")
+
+// validateGroups checks that the groups and their members are configured
+// webhooks, that the quorum can be reached and that no group is a member of
+// a group.
+//
+// Parameters:
+//   - groups: The configured groups.
+//   - webhooks: The configured webhooks.
+//
+// Returns:
+//   - An error naming the invalid group.
+func (b *Builder) validateGroups(groups []config.GroupConfig, webhooks config.Webhooks) error {
+	configured := webhooks.AsMap()
+	ids := make(map[uuid.UUID]struct{}, len(groups))
+
+	for _, group := range groups {
+		if _, ok := configured[group.ID]; !ok {
+			return fmt.Errorf("%w: %s: the webhook %s is not configured", ErrInvalidGroup, group.Name, group.ID)
+		}
+
+		if _, ok := ids[group.ID]; ok {
+			return fmt.Errorf("%w: %s: the webhook %s belongs to several groups", ErrInvalidGroup, group.Name, group.ID)
+		}
+
+		ids[group.ID] = struct{}{}
+
+		if len(group.Members) == 0 {
+			return fmt.Errorf("%w: %s: no members", ErrInvalidGroup, group.Name)
+		}
+
+		if group.Quorum < 0 || group.Quorum > len(group.Members) {
+			return fmt.Errorf("%w: %s: the quorum %d cannot be reached", ErrInvalidGroup, group.Name, group.Quorum)
+		}
+
+		for _, member := range group.Members {
+			if _, ok := configured[member]; !ok {
+				return fmt.Errorf("%w: %s: the member %s is not configured", ErrInvalidGroup, group.Name, member)
+			}
+		}
+	}
+
+	// The status of a group is aggregated from heartbeats, so groups cannot be nested.
+	for _, group := range groups {
+		for _, member := range group.Members {
+			if _, ok := ids[member]; ok {
+				return fmt.Errorf("%w: %s: the member %s is a group", ErrInvalidGroup, group.Name, member)
+			}
+		}
+	}
+
+	return nil
+}
+
+// serviceGroups returns the service groups loaded from the configuration.
+// If the Builder instance already has the groups, they will be returned.
+//
+// Returns:
+//   - A pointer to a services.Groups.
+func (b *Builder) serviceGroups() *services.Groups {
+	// Check if the Builder instance already has the groups.
+	if b.groups != nil {
+		return b.groups
+	}
+
+	groups := make([]entities.Group, 0, len(b.config.Groups))
+	for _, group := range b.config.Groups {
+		groups = append(groups, group.Entity())
+	}
+
+	b.groups = services.NewGroups(groups...)
+
+	return b.groups
+}
@@ -2,84 +2,364 @@ package build
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"net"
+	"os"
+	"slices"
+	"time"
 
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/reflection"
 
 	"github.com/bavix/vakeel-way/internal/app"
+	"github.com/bavix/vakeel-way/internal/config"
+	"github.com/bavix/vakeel-way/internal/domain/entities"
 	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+	wayv2 "github.com/bavix/vakeel-way/pkg/api/vakeel_way/v2"
+	"github.com/bavix/vakeel-way/pkg/requestid"
 	"github.com/bavix/vakeel-way/pkg/zerolog/interceptor"
 )
 
+// ErrInvalidListener is an error that indicates that an additional listener of the gRPC server is misconfigured.
+var ErrInvalidListener = errors.New("grpc: invalid listener")
+
+// listenerNetworks are the networks the additional listeners of the gRPC server may listen on.
+var listenerNetworks = []string{"tcp", "tcp4", "tcp6", "unix"}
+
+const (
+	// reconnectAfter is the time the agents are asked to wait before
+	// reconnecting when the server shuts down.
+	reconnectAfter = 5 * time.Second
+
+	// shutdownGrace is the time the shutdown notice is given to reach the
+	// agents before the streams are closed.
+	shutdownGrace = 500 * time.Millisecond
+)
+
 // RunGRPCServer starts a gRPC server on the TCP port specified by the `GRPCAddr`
-// field of the `config` field of the `Builder` receiver. It listens on the TCP
-// port specified by the `GRPCAddr` field of the `config` field of the `Builder`
-// receiver. If the port is already in use, this function returns an error. It
-// registers the gRPC service implementation with the gRPC server. Then it
-// starts serving requests in a separate goroutine. The function blocks until the
-// server is stopped or an error occurs.
+// field of the `config` field of the `Builder` receiver, and on every
+// additional listener of the configuration. If a port is already in use, this
+// function returns an error. It registers the gRPC service implementations
+// with the gRPC server of every listener. Then it starts serving requests in
+// separate goroutines. The function blocks until the servers are stopped or
+// one of them fails.
+//
+// If a certificate is configured, the listener accepts TLS connections only,
+// and the certificate is reloaded when its files change. Otherwise, the
+// automatic certificates are served if they are enabled.
 //
 // ctx - The context.Context used to stop the server.
-// Returns an error if there is a problem with listening on the TCP port.
+// Returns an error if there is a problem with listening on a port.
 func (b *Builder) RunGRPCServer(ctx context.Context) error {
-	// Listen on the TCP port specified by the `GRPCAddr` field of the `config`
-	// field of the `Builder` receiver. If the port is already in use, an error
-	// is returned.
-	listen, err := net.Listen(b.config.GRPC.Network, b.config.GRPC.Addr())
-	if err != nil {
-		return err
+	// Do nothing if the server is shutting down.
+	if !b.ingress.enter() {
+		return nil
+	}
+	defer b.ingress.leave()
+
+	listeners := b.grpcListeners()
+
+	// Listen on every address before anything is served, so an address that
+	// is already in use fails the whole server.
+	listens := make([]net.Listener, 0, len(listeners))
+	closeAll := func() {
+		for _, listen := range listens {
+			_ = listen.Close()
+		}
+	}
+
+	for _, listener := range listeners {
+		listen, err := listenGRPC(listener.Network, listener.Address)
+		if err != nil {
+			closeAll()
+
+			return err
+		}
+
+		listens = append(listens, listen)
 	}
 
+	// The listeners are closed by the servers once they are served, so they
+	// are only left open when the function returns early.
+	defer closeAll()
+
 	// Get the logger from the context.
 	logger := zerolog.Ctx(ctx)
 
-	// Create a new gRPC server.
-	server := grpc.NewServer(
-		// Set the stream interceptor to add a logger to the context.
-		grpc.StreamInterceptor(
-			interceptor.StreamInterceptor(logger), // Add a logger to the context.
+	// Get the audit log of the heartbeats.
+	auditLog, err := b.auditLog()
+	if err != nil {
+		return err
+	}
+
+	// Get the implementations of the services, shared with the admin REST API.
+	stateServer, err := b.stateService(ctx)
+	if err != nil {
+		return err
+	}
+
+	adminServer, err := b.adminService(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Get the authenticator of the API tokens.
+	authenticator, err := b.authenticator()
+	if err != nil {
+		return err
+	}
+
+	// Get the allowlist of the networks.
+	allowlist, err := b.networkAllowlist()
+	if err != nil {
+		return err
+	}
+
+	opts := []grpc.ServerOption{
+		// Set the stream interceptors to assign a request ID, add a logger to the context, recover the panics,
+		// reject the unknown networks and authenticate the caller.
+		grpc.ChainStreamInterceptor(
+			requestid.StreamInterceptor(),         // Assign a request ID to the stream.
+			interceptor.StreamInterceptor(logger), // Add a logger to the context and log the streams.
+			b.reporter.StreamInterceptor(),        // Recover and report the panics.
+			allowlist.StreamInterceptor(),         // Reject the unknown networks.
+			authenticator.StreamInterceptor(),     // Authenticate the caller.
 		),
-		// Set the unary interceptor to add a logger to the context.
-		grpc.UnaryInterceptor(
-			interceptor.UnaryInterceptor(logger), // Add a logger to the context.
+		// Set the unary interceptors to assign a request ID, add a logger to the context, recover the panics,
+		// reject the unknown networks and authenticate the caller.
+		grpc.ChainUnaryInterceptor(
+			requestid.UnaryInterceptor(),         // Assign a request ID to the call.
+			interceptor.UnaryInterceptor(logger), // Add a logger to the context and log the calls.
+			b.reporter.UnaryInterceptor(),        // Recover and report the panics.
+			allowlist.UnaryInterceptor(),         // Reject the unknown networks.
+			authenticator.UnaryInterceptor(),     // Authenticate the caller.
 		),
-	)
+		// Wait for the handlers on Stop, so no heartbeat is received once the server is stopped.
+		grpc.WaitForHandlers(true),
+	}
+
+	servers := make([]*grpc.Server, 0, len(listeners))
+
+	for _, listener := range listeners {
+		// Get the TLS configuration, reloading the certificate when it is renewed.
+		tlsConfig, err := b.listenerTLS(ctx, listener)
+		if err != nil {
+			return err
+		}
+
+		// Serve the connections over TLS if a certificate is configured.
+		serverOpts := slices.Clone(opts)
+		if tlsConfig != nil {
+			serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+		}
+
+		// Create a new gRPC server serving the same services as the others.
+		server := grpc.NewServer(serverOpts...)
+		b.registerServices(server, stateServer, adminServer)
+
+		servers = append(servers, server)
+	}
 
 	// Start a goroutine that listens for the context to be closed. When the
-	// context is closed, it closes the listener. This ensures that the server
-	// is stopped when the context is closed.
-	//
-	// This goroutine is needed to ensure that the server is stopped when the
-	// context is closed. The server is stopped by calling the Stop method on
-	// the gRPC server. This method blocks until all active RPCs are finished.
-	//
-	// The goroutine is started after the gRPC server is started. This ensures
-	// that the server is stopped after all active RPCs are finished.
+	// context is closed, it stops the servers, which closes the listeners.
+	// This ensures that the servers are stopped when the context is closed.
+	stopped := make(chan struct{})
+
 	go func() {
+		defer close(stopped)
+
 		// Wait for the context to be closed.
 		<-ctx.Done()
 
-		// Stop the server after all active RPCs are finished. The server is
-		// stopped by calling the Stop method on the gRPC server. This method
-		// blocks until all active RPCs are finished.
-		server.Stop()
+		// Report the instance as not ready, so no new agents are routed to it.
+		b.serving.Store(false)
+
+		// Tell the connected agents to reconnect later, and give the notice
+		// a moment to reach them before the streams are closed.
+		if b.noticeAnnouncer().Announce(entities.Notice{
+			Kind:   entities.NoticeShutdown,
+			Delay:  reconnectAfter,
+			Reason: "the server is shutting down",
+		}) > 0 {
+			time.Sleep(shutdownGrace)
+		}
+
+		// Stop the servers. The Stop method closes the listeners and the
+		// connections of the server.
+		for _, server := range servers {
+			server.Stop()
+		}
+
+		// Close the sinks of the audit log once no heartbeats are received.
+		if err := auditLog.Close(); err != nil {
+			logger.Err(err).Msg("Failed to close the audit log")
+		}
 	}()
 
+	// Report the instance as ready once the listeners accept the connections.
+	b.serving.Store(true)
+
+	// Start serving requests on every listener in a separate goroutine.
+	errs := make(chan error, len(servers))
+
+	for i, server := range servers {
+		// Log the address of the server.
+		logger.Info().Str("listener", listeners[i].Name).Str("addr", listeners[i].Address).Msg("Starting gRPC server")
+
+		go func() {
+			errs <- server.Serve(listens[i])
+		}()
+	}
+
+	// A failing listener stops the others, so the whole server fails.
+	var failed error
+
+	for range servers {
+		if serveErr := <-errs; serveErr != nil && failed == nil {
+			failed = serveErr
+
+			for _, server := range servers {
+				server.Stop()
+			}
+		}
+	}
+
+	// Wait for the handlers of the streams to return, as they feed the checker.
+	if ctx.Err() != nil {
+		<-stopped
+	}
+
+	return failed
+}
+
+// registerServices registers the gRPC service implementations with the gRPC server.
+//
+// Parameters:
+//   - server: The gRPC server of a listener.
+//   - stateServer: The implementation of the state service.
+//   - adminServer: The implementation of the administrative service.
+func (b *Builder) registerServices(server *grpc.Server, stateServer *app.GRPCServer, adminServer *app.AdminServer) {
 	// Register the gRPC service implementation with the gRPC server.
-	way.RegisterStateServiceServer(server, app.NewGRPCServer(b.checkerUsecase(ctx)))
+	way.RegisterStateServiceServer(server, stateServer)
+
+	// Register the second version of the service alongside the first one.
+	wayv2.RegisterStateServiceServer(server, app.NewStateServerV2(stateServer))
+
+	// Register the administrative gRPC service implementation with the gRPC server.
+	way.RegisterAdminServiceServer(server, adminServer)
+
+	// Register the capability gRPC service implementation with the gRPC server.
+	way.RegisterCapabilityServiceServer(server, app.NewCapabilityServer(b.capabilities()))
 
-	// Register reflection service on gRPC server. This allows clients to
-	// discover the services and methods offered by the server.
-	reflection.Register(server)
+	// Register reflection service on gRPC server, unless it is disabled. This
+	// allows clients to discover the services and methods offered by the server.
+	if b.config.GRPC.Reflection {
+		reflection.Register(server)
+	}
+}
+
+// grpcListeners returns the listeners of the gRPC server, the main one first.
+//
+// Returns:
+//   - The configuration of every listener, with the defaults applied.
+func (b *Builder) grpcListeners() []config.GRPCListenerConfig {
+	listeners := []config.GRPCListenerConfig{{
+		Name:      "main",
+		Network:   b.config.GRPC.Network,
+		Address:   b.config.GRPC.Addr(),
+		TLS:       b.config.GRPC.TLS,
+		Plaintext: false,
+	}}
+
+	for _, listener := range b.config.GRPC.Listeners {
+		if listener.Network == "" {
+			listener.Network = "tcp"
+		}
 
-	// Start serving requests in a separate goroutine. This method blocks until
-	// the server is stopped or an error occurs.
+		if listener.Name == "" {
+			listener.Name = listener.Address
+		}
 
-	// Log the address of the server.
-	logger.Info().Str("addr", b.config.GRPC.Addr()).Msg("Starting gRPC server")
+		listeners = append(listeners, listener)
+	}
+
+	return listeners
+}
+
+// validateListeners checks that every additional listener of the gRPC server can be served.
+//
+// Parameters:
+//   - cfg: The configuration of the gRPC server.
+//
+// Returns:
+//   - An error wrapping ErrInvalidListener for the first listener without an
+//     address, with an unsupported network, or both plaintext and with a certificate.
+//   - An error wrapping ErrIncompleteTLS for the first incomplete certificate.
+func validateListeners(cfg config.GRPCConfig) error {
+	for _, listener := range cfg.Listeners {
+		if listener.Address == "" {
+			return fmt.Errorf("%w: %q has no address", ErrInvalidListener, listener.Name)
+		}
+
+		if listener.Network != "" && !slices.Contains(listenerNetworks, listener.Network) {
+			return fmt.Errorf("%w: %s: unsupported network %q", ErrInvalidListener, listener.Address, listener.Network)
+		}
+
+		if listener.Plaintext && listener.TLS.Enabled() {
+			return fmt.Errorf("%w: %s: a plaintext listener cannot have a certificate", ErrInvalidListener, listener.Address)
+		}
+
+		if err := validateTLS(listener.TLS); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listenerTLS returns the TLS configuration of the listener.
+//
+// The plaintext listeners and the unix sockets without a certificate are
+// served without TLS, even if the automatic certificates are enabled.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the reloads, holding the logger.
+//   - listener: The configuration of the listener.
+//
+// Returns:
+//   - A pointer to a tls.Config, or nil if the listener is served without TLS.
+//   - An error if the key pair cannot be loaded.
+func (b *Builder) listenerTLS(ctx context.Context, listener config.GRPCListenerConfig) (*tls.Config, error) {
+	if listener.Plaintext || (listener.Network == "unix" && !listener.TLS.Enabled()) {
+		return nil, nil //nolint:nilnil
+	}
+
+	return b.serverTLS(ctx, listener.TLS)
+}
+
+// listenGRPC listens on the address of the network.
+//
+// A unix socket left behind by a process that did not stop cleanly is removed first.
+//
+// Parameters:
+//   - network: The network of the listener.
+//   - address: The address of the listener, or the path to the unix socket.
+//
+// Returns:
+//   - The net.Listener.
+//   - An error if the address cannot be listened on.
+func listenGRPC(network, address string) (net.Listener, error) {
+	if network == "unix" {
+		if info, err := os.Lstat(address); err == nil && info.Mode()&os.ModeSocket != 0 {
+			if err := os.Remove(address); err != nil {
+				return nil, err
+			}
+		}
+	}
 
-	// Start serving requests.
-	return server.Serve(listen)
+	return net.Listen(network, address)
 }
@@ -0,0 +1,66 @@
+package build
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bavix/vakeel-way/internal/domain/services"
+)
+
+var (
+	// ErrNotServing is an error that indicates that the gRPC server is not accepting the heartbeats.
+	ErrNotServing = errors.New("health: the gRPC server is not serving")
+
+	// ErrNoWebhooks is an error that indicates that no webhook is configured.
+	ErrNoWebhooks = errors.New("health: no webhook is configured")
+
+	// ErrCheckerFull is an error that indicates that the buffer of the heartbeats is full.
+	ErrCheckerFull = errors.New("health: the buffer of the heartbeats is full")
+)
+
+// healthChecker returns the Health probing the readiness of the instance.
+// If the Builder instance already has a Health instance, it will be returned.
+//
+// The instance is ready when the gRPC server accepts the heartbeats, the
+// webhooks are loaded and the buffer of the heartbeats is not full.
+//
+// Parameters:
+//   - ctx: The context.Context holding the logger.
+//
+// Returns:
+//   - A pointer to a Health.
+func (b *Builder) healthChecker(ctx context.Context) *services.Health {
+	// Check if the Builder instance already has a Health instance.
+	if b.health != nil {
+		return b.health
+	}
+
+	checker := b.checkerUsecase(ctx)
+	repo := b.WebhookRepository()
+
+	b.health = services.NewHealth(
+		services.WithProbe("grpc", func() error {
+			if !b.serving.Load() {
+				return ErrNotServing
+			}
+
+			return nil
+		}),
+		services.WithProbe("repository", func() error {
+			if len(repo.All()) == 0 {
+				return ErrNoWebhooks
+			}
+
+			return nil
+		}),
+		services.WithProbe("checker", func() error {
+			if checker.Depth() >= checker.Capacity() {
+				return ErrCheckerFull
+			}
+
+			return nil
+		}),
+	)
+
+	return b.health
+}
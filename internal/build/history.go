@@ -0,0 +1,49 @@
+package build
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/infra/history"
+)
+
+// historyStore returns the status history of the services.
+// If the Builder instance already has a Store instance, it will be returned.
+//
+// Returns:
+//   - A pointer to a history Store.
+//   - An error if the retention is invalid or the history file cannot be read.
+func (b *Builder) historyStore() (*history.Store, error) {
+	// Check if the Builder instance already has a Store instance.
+	if b.history != nil {
+		return b.history, nil
+	}
+
+	store, err := history.Open(b.config.History.Path, history.Retention{
+		Raw:    b.config.History.RawRetention,
+		Hourly: b.config.History.HourlyRetention,
+		Daily:  b.config.History.DailyRetention,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	b.history = store
+
+	return b.history, nil
+}
+
+// runHistory downsamples and persists the status history until the context is canceled.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the loop.
+func (b *Builder) runHistory(ctx context.Context) {
+	// The interval between the downsampling runs. It bounds the transitions lost on a crash.
+	const interval = time.Minute
+
+	b.history.Run(ctx, interval, func(err error) {
+		zerolog.Ctx(ctx).Err(err).Msg("Failed to persist the status history")
+	})
+}
@@ -0,0 +1,100 @@
+package build
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/app"
+)
+
+// RunHTTPServer starts the HTTP server on the address specified by the `HTTP`
+// field of the configuration. The server exposes the application metrics on
+// the /metrics endpoint, the enabled features on the /capabilities endpoint and
+// the uptime badges on the /badge/{id} endpoint, and the liveness and readiness
+// probes on the /healthz and /readyz endpoints. If the Alertmanager receiver is
+// enabled, the server accepts its webhooks on the /api/v1/alertmanager
+// endpoint. The function blocks until the context is closed or an error occurs.
+//
+// If the allowlist of the networks is configured, only the probes are served
+// to the clients from the other networks.
+//
+// If the HTTP server is disabled in the configuration, the function returns
+// immediately.
+//
+// ctx - The context.Context used to stop the server.
+// Returns an error if the Alertmanager receiver cannot be built or the server
+// cannot listen on the configured address.
+func (b *Builder) RunHTTPServer(ctx context.Context) error {
+	// Do nothing if the HTTP server is disabled or the server is shutting down.
+	if !b.config.HTTP.Enabled || !b.ingress.enter() {
+		return nil
+	}
+	defer b.ingress.leave()
+
+	// Get the logger from the context.
+	logger := zerolog.Ctx(ctx)
+
+	allowlist, err := b.networkAllowlist()
+	if err != nil {
+		return err
+	}
+
+	// Register the HTTP handlers. Every handler but the probes is restricted to the allowed networks.
+	mux := http.NewServeMux()
+	// The OpenMetrics format is served to the scrapers that accept it.
+	mux.Handle("/metrics", promhttp.HandlerFor(b.metricsRegistry(), promhttp.HandlerOpts{EnableOpenMetrics: true})) //nolint:exhaustruct
+	mux.Handle("GET /capabilities", app.NewCapabilityServer(b.capabilities()))
+	mux.Handle("GET /badge/{id}", app.NewBadgeHandler(b.WebhookRepository(), b.history))
+
+	// Create the probes of the orchestrators.
+	health := app.NewHealthHandler(b.healthChecker(ctx))
+
+	// Receive the Alertmanager webhooks as a source of the statuses.
+	if b.config.Alertmanager.Enabled {
+		handler, err := b.alertmanagerHandler(ctx)
+		if err != nil {
+			return err
+		}
+
+		mux.Handle("POST /api/v1/alertmanager", handler)
+	}
+
+	// The probes of the orchestrators are sent from the nodes, so they are not restricted.
+	root := http.NewServeMux()
+	root.Handle("/", allowlist.Middleware(mux))
+	root.HandleFunc("GET /healthz", health.Live)
+	root.HandleFunc("GET /readyz", health.Ready)
+
+	// The time allowed to read the request headers.
+	const readHeaderTimeout = 10 * time.Second
+
+	//nolint:exhaustruct
+	server := &http.Server{
+		Addr:              b.config.HTTP.Addr(),
+		Handler:           root,
+		ReadHeaderTimeout: readHeaderTimeout,
+		BaseContext:       func(_ net.Listener) context.Context { return ctx },
+	}
+
+	// Shut the server down when the context is closed.
+	shutdown := shutdownHTTP(ctx, server)
+
+	// Log the address of the server.
+	logger.Info().Str("addr", b.config.HTTP.Addr()).Msg("Starting HTTP server")
+
+	// Start serving requests.
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	// Wait for the Alertmanager webhooks in progress, which feed the checker.
+	<-shutdown
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package build
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// shutdownTimeout is the time allowed to the HTTP servers to finish the requests in progress on shutdown.
+const shutdownTimeout = 5 * time.Second
+
+// ingressGroup tracks the servers and the consumers feeding the heartbeats into the checker.
+//
+// The checker is closed once every ingress has returned, so no heartbeat is
+// buffered into a closed checker during a shutdown. An ingress entering after
+// the group is closed is not started.
+type ingressGroup struct {
+	mu      sync.Mutex
+	running sync.WaitGroup
+	closed  bool
+}
+
+// enter registers an ingress that is starting.
+//
+// Returns:
+//   - false if the group is closed, so the ingress must not start.
+func (g *ingressGroup) enter() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		return false
+	}
+
+	g.running.Add(1)
+
+	return true
+}
+
+// leave unregisters an ingress that has returned.
+func (g *ingressGroup) leave() {
+	g.running.Done()
+}
+
+// wait closes the group and blocks until every ingress has returned.
+func (g *ingressGroup) wait() {
+	g.mu.Lock()
+	g.closed = true
+	g.mu.Unlock()
+
+	g.running.Wait()
+}
+
+// shutdownHTTP shuts the HTTP server down when the context is closed.
+//
+// The requests in progress are given shutdownTimeout to finish, then the
+// remaining connections, e.g. the streams, are closed.
+//
+// Parameters:
+//   - ctx: The context.Context whose closing shuts the server down.
+//   - server: The HTTP server.
+//
+// Returns:
+//   - A channel closed once the server is shut down.
+func shutdownHTTP(ctx context.Context, server *http.Server) <-chan struct{} {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		<-ctx.Done()
+
+		shutdown, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+		defer cancel()
+
+		if err := server.Shutdown(shutdown); err != nil {
+			_ = server.Close()
+		}
+	}()
+
+	return done
+}
@@ -1,6 +1,11 @@
 package build
 
-import "github.com/bavix/vakeel-way/internal/infra/instatus"
+import (
+	"github.com/rs/zerolog/log"
+
+	"github.com/bavix/vakeel-way/internal/infra/cache"
+	"github.com/bavix/vakeel-way/internal/infra/instatus"
+)
 
 // inStatusClient returns a new instance of the instatus.Api struct.
 //
@@ -10,6 +15,28 @@ import "github.com/bavix/vakeel-way/internal/infra/instatus"
 // The function returns a pointer to an instatus.Api struct.
 func (b *Builder) inStatusClient() *instatus.API {
 	// Create a new instance of the instatus.Api struct.
-	// The struct is created with default settings.
-	return instatus.NewAPI()
+	// The open incidents are tracked in the state.
+	return instatus.NewAPI(instatus.WithIncidents(b.incidentStore()), instatus.WithClient(b.targetClient()))
+}
+
+// incidentStore returns the store of the open Instatus incidents.
+// If the Builder instance already has a store, it will be returned.
+//
+// The incidents are persisted to the file of the state configuration, if any.
+//
+// Returns:
+//   - A pointer to a cache.Cache mapping the targets to the open incidents.
+func (b *Builder) incidentStore() *cache.Cache[string, string] {
+	if b.incidents != nil {
+		return b.incidents
+	}
+
+	b.incidents = cache.NewCache[string, string](0,
+		cache.WithPersistence[string, string](b.config.State.Incidents, b.config.State.Interval),
+		cache.WithOnError[string, string](func(err error) {
+			log.Error().Err(err).Str("file", b.config.State.Incidents).Msg("failed to persist the open incidents")
+		}),
+	)
+
+	return b.incidents
 }
@@ -0,0 +1,91 @@
+package build
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bavix/vakeel-way/internal/domain/services"
+)
+
+// heartbeatIntervals returns the tracker of the intervals between the heartbeats.
+// If the Builder instance already has a tracker, it will be returned.
+//
+// The statistics of the intervals are registered as metrics on creation.
+//
+// Returns:
+//   - A pointer to a services.Intervals.
+func (b *Builder) heartbeatIntervals() *services.Intervals {
+	// Check if the Builder instance already has a tracker.
+	if b.intervals != nil {
+		return b.intervals
+	}
+
+	b.intervals = services.NewIntervals(b.config.Checker.IntervalWindow)
+	b.metricsRegistry().MustRegister(newIntervalCollector(b.intervals))
+
+	return b.intervals
+}
+
+// cadenceDetector returns the detector of the late heartbeats.
+//
+// Returns:
+//   - A pointer to a services.CadenceDetector, or nil if the anomalies are not detected.
+func (b *Builder) cadenceDetector() *services.CadenceDetector {
+	cfg := b.config.Anomaly
+	if !cfg.Enabled {
+		return nil
+	}
+
+	return services.NewCadenceDetector(cfg.Sensitivity, cfg.Ratio, cfg.MinSamples)
+}
+
+// intervalCollector is a prometheus.Collector of the statistics of the
+// intervals between the heartbeats.
+//
+// The metrics are labeled with the UUIDs of the services, so a service whose
+// 99th percentile approaches its TTL can be alerted on before it goes down.
+type intervalCollector struct {
+	intervals *services.Intervals
+	interval  *prometheus.Desc
+	ttl       *prometheus.Desc
+}
+
+// newIntervalCollector creates a new instance of the intervalCollector struct.
+//
+// Parameters:
+//   - intervals: The Intervals tracking the intervals.
+//
+// Returns:
+//   - A pointer to the initialized intervalCollector.
+func newIntervalCollector(intervals *services.Intervals) *intervalCollector {
+	return &intervalCollector{
+		intervals: intervals,
+		interval: prometheus.NewDesc(
+			"vakeel_heartbeat_interval_seconds",
+			"Interval between the consecutive heartbeats of the service over the latest heartbeats, by statistic.",
+			[]string{"id", "stat"}, nil,
+		),
+		ttl: prometheus.NewDesc(
+			"vakeel_heartbeat_ttl_seconds",
+			"TTL of the status of the service at its last heartbeat.",
+			[]string{"id"}, nil,
+		),
+	}
+}
+
+// Describe sends the descriptors of the metrics.
+func (c *intervalCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.interval
+	ch <- c.ttl
+}
+
+// Collect sends the statistics of the intervals of every service.
+func (c *intervalCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, stats := range c.intervals.Stats() {
+		id := stats.ID.String()
+
+		ch <- prometheus.MustNewConstMetric(c.interval, prometheus.GaugeValue, stats.Min.Seconds(), id, "min")
+		ch <- prometheus.MustNewConstMetric(c.interval, prometheus.GaugeValue, stats.Avg.Seconds(), id, "avg")
+		ch <- prometheus.MustNewConstMetric(c.interval, prometheus.GaugeValue, stats.P99.Seconds(), id, "p99")
+		ch <- prometheus.MustNewConstMetric(c.ttl, prometheus.GaugeValue, stats.TTL.Seconds(), id)
+	}
+}
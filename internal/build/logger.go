@@ -2,16 +2,31 @@ package build
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log"
-	"time"
+	"os"
 
 	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/config"
+	"github.com/bavix/vakeel-way/internal/infra/logsink"
+)
+
+var (
+	// ErrUnknownLogFormat is an error that indicates that the output format of the logs is unknown.
+	ErrUnknownLogFormat = errors.New("log: unknown format")
+
+	// ErrUnknownLogOutput is an error that indicates that the destination of the logs is unknown.
+	ErrUnknownLogOutput = errors.New("log: unknown output")
 )
 
 // Logger creates a new context with a logger attached to it.
 //
-// It creates a logger with the log level specified in the configuration file.
-// The logger is then attached to the given context.
+// It creates a logger with the log level, the output format, the time format
+// and the sampling specified in the configuration file. The logger is then
+// attached to the given context.
 //
 // Parameters:
 //   - ctx: The context to attach the logger to.
@@ -19,23 +34,110 @@ import (
 // Returns:
 //   - The context with the logger attached.
 func (b *Builder) Logger(ctx context.Context) context.Context {
+	cfg := b.config.Log
+
 	// Parse the log level from the configuration file.
-	level, err := zerolog.ParseLevel(b.config.Log.Level)
+	level, err := zerolog.ParseLevel(cfg.Level)
 	if err != nil {
 		// If the log level is invalid, log the error and stop the application.
 		log.Fatal(err)
 	}
 
-	// Create a new logger with the specified log level and time format.
-	// The time format is set to RFC3339Nano, which is the most precise time format.
-	logger := zerolog.New(zerolog.NewConsoleWriter(func(w *zerolog.ConsoleWriter) {
-		w.TimeFormat = time.RFC3339Nano
-	})).
+	// Create the writer of the configured output and format.
+	writer, err := logWriter(cfg)
+	if err != nil {
+		// If the output cannot be used, log the error and stop the application.
+		log.Fatal(err)
+	}
+
+	// The timestamps of the JSON output are formatted by zerolog itself.
+	zerolog.TimeFieldFormat = cfg.TimeFormat
+
+	// Create a new logger with the specified log level.
+	fields := zerolog.New(writer).
 		Level(level).
 		With().
-		Timestamp().
-		Logger()
+		Timestamp()
+
+	// Add the file and the line of the call if requested.
+	if cfg.Caller {
+		fields = fields.Caller()
+	}
+
+	logger := fields.Logger()
+
+	// Sample the high-volume levels if requested.
+	if sampled(cfg.Sampling) {
+		logger = logger.Sample(zerolog.LevelSampler{
+			TraceSampler: sampler(cfg.Sampling.Trace),
+			DebugSampler: sampler(cfg.Sampling.Debug),
+			InfoSampler:  sampler(cfg.Sampling.Info),
+			WarnSampler:  nil,
+			ErrorSampler: nil,
+		})
+	}
 
 	// Attach the logger to the given context and return the new context.
 	return logger.WithContext(ctx)
 }
+
+// logWriter creates the writer of the logs to the configured output.
+//
+// The format applies to the standard output only: syslog receives the JSON
+// events, and journald receives their fields.
+//
+// Parameters:
+//   - cfg: The configuration of the logger.
+//
+// Returns:
+//   - The writer of the logs.
+//   - An error if the output or the format is unknown, or the output cannot be reached.
+func logWriter(cfg config.LogConfig) (io.Writer, error) {
+	switch cfg.Output {
+	case "", "stdout":
+	case "syslog":
+		return logsink.DialSyslog(cfg.Syslog.Network, cfg.Syslog.Address, cfg.Tag)
+	case "journald":
+		return logsink.DialJournald(cfg.Journald, cfg.Tag)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownLogOutput, cfg.Output)
+	}
+
+	switch cfg.Format {
+	case "", "console":
+		return zerolog.NewConsoleWriter(func(w *zerolog.ConsoleWriter) {
+			w.TimeFormat = cfg.TimeFormat
+		}), nil
+	case "json":
+		// Write to the standard output like the console writer.
+		return os.Stdout, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownLogFormat, cfg.Format)
+	}
+}
+
+// sampled reports whether any level is sampled.
+//
+// Parameters:
+//   - cfg: The sampling of the levels.
+//
+// Returns:
+//   - A boolean indicating whether any level is sampled.
+func sampled(cfg config.LogSamplingConfig) bool {
+	return cfg.Trace > 1 || cfg.Debug > 1 || cfg.Info > 1
+}
+
+// sampler creates the sampler logging every Nth message.
+//
+// Parameters:
+//   - n: The sampling rate.
+//
+// Returns:
+//   - The sampler, or nil if every message is logged.
+func sampler(n uint32) zerolog.Sampler {
+	if n <= 1 {
+		return nil
+	}
+
+	return &zerolog.BasicSampler{N: n}
+}
@@ -0,0 +1,30 @@
+package build
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// metricsRegistry returns the registry of the application metrics.
+// If the Builder instance already has a registry, it will be returned.
+//
+// The registry is created with the Go runtime and process collectors and is
+// exposed by the HTTP server on the /metrics endpoint.
+//
+// Returns:
+//   - A pointer to a prometheus.Registry.
+func (b *Builder) metricsRegistry() *prometheus.Registry {
+	// Check if the Builder instance already has a registry.
+	if b.registry != nil {
+		return b.registry
+	}
+
+	// Create a new registry with the default collectors.
+	b.registry = prometheus.NewRegistry()
+	b.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}), //nolint:exhaustruct
+	)
+
+	return b.registry
+}
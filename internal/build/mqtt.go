@@ -0,0 +1,70 @@
+package build
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/mqtt"
+)
+
+// RunMQTT subscribes to the heartbeats the devices publish to the MQTT broker
+// specified by the `MQTT` field of the configuration, and feeds them into the
+// checker. The function blocks until the context is closed.
+//
+// If the MQTT listener is disabled in the configuration, the function returns
+// immediately.
+//
+// ctx - The context.Context used to stop the listener.
+// Returns an error if the broker cannot be reached on startup.
+func (b *Builder) RunMQTT(ctx context.Context) error {
+	cfg := b.config.MQTT
+
+	// Do nothing if the MQTT listener is disabled or the server is shutting down.
+	if !cfg.Enabled || !b.ingress.enter() {
+		return nil
+	}
+	defer b.ingress.leave()
+
+	// Get the logger from the context.
+	logger := zerolog.Ctx(ctx)
+
+	// Decrypt the password if it is stored encrypted.
+	password, err := b.Keyring().Open(cfg.Password)
+	if err != nil {
+		return err
+	}
+
+	// The pattern is validated in NewBuilder.
+	topics, err := mqtt.NewTopics(cfg.Topic, cfg.Aliases)
+	if err != nil {
+		return err
+	}
+
+	options := []mqtt.Option{
+		mqtt.WithCredentials(cfg.Username, password),
+		mqtt.WithQoS(cfg.QoS),
+	}
+
+	if cfg.ClientID != "" {
+		options = append(options, mqtt.WithClientID(cfg.ClientID))
+	}
+
+	checker := b.checkerUsecase(ctx)
+	repo := b.WebhookRepository()
+
+	logger.Info().Str("broker", cfg.Broker).Str("filter", topics.Filter()).Msg("Starting MQTT listener")
+
+	return mqtt.NewListener(cfg.Broker, topics, options...).Run(ctx, func(id uuid.UUID) {
+		switch {
+		case !repo.Exists(id):
+			// The heartbeats of the unknown services are not recorded.
+			logger.Debug().Str("id", id.String()).Msg("MQTT heartbeat of an unknown service")
+		case !checker.Beat(entities.Heartbeat{ID: id, Status: entities.Up, Message: "", TTL: 0, RequestID: "", Sent: time.Time{}}):
+			logger.Warn().Str("id", id.String()).Msg("MQTT heartbeat dropped")
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package build
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/app"
+)
+
+// RunNATS consumes the heartbeats from the NATS subject specified by the
+// `NATS` field of the configuration and feeds them into the checker. The
+// publishers using the request-reply pattern receive the result of every
+// heartbeat. The function blocks until the context is closed, and then drains
+// the subscription.
+//
+// If the NATS ingestion is disabled in the configuration, the function returns
+// immediately.
+//
+// ctx - The context.Context used to stop the consumption.
+// Returns an error if the NATS server cannot be reached on startup.
+func (b *Builder) RunNATS(ctx context.Context) error {
+	cfg := b.config.NATS
+
+	// Do nothing if the NATS ingestion is disabled or the server is shutting down.
+	if !cfg.Enabled || !b.ingress.enter() {
+		return nil
+	}
+	defer b.ingress.leave()
+
+	// Get the logger from the context.
+	logger := zerolog.Ctx(ctx)
+
+	// Decrypt the token if it is stored encrypted.
+	token, err := b.Keyring().Open(cfg.Token)
+	if err != nil {
+		return err
+	}
+
+	// The connection is closed once it is drained.
+	closed := make(chan struct{})
+
+	options := []nats.Option{
+		nats.Name("vakeel-way"),
+		nats.ClosedHandler(func(_ *nats.Conn) { close(closed) }),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			logger.Warn().Err(err).Msg("NATS disconnected")
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			logger.Info().Str("url", conn.ConnectedUrl()).Msg("NATS reconnected")
+		}),
+	}
+
+	if token != "" {
+		options = append(options, nats.Token(token))
+	}
+
+	conn, err := nats.Connect(cfg.URL, options...)
+	if err != nil {
+		return err
+	}
+
+	consumer := app.NewNATSConsumer(b.checkerUsecase(ctx), b.WebhookRepository(), b.agentDirectory())
+
+	_, err = conn.QueueSubscribe(cfg.Subject, cfg.Queue, func(msg *nats.Msg) {
+		reply, err := consumer.Handle(msg.Data)
+		if err != nil {
+			logger.Debug().Err(err).Str("subject", msg.Subject).Msg("NATS heartbeat rejected")
+		}
+
+		if msg.Reply != "" {
+			if err := msg.Respond(reply); err != nil {
+				logger.Err(err).Str("subject", msg.Subject).Msg("NATS reply failed")
+			}
+		}
+	})
+	if err != nil {
+		conn.Close()
+
+		return err
+	}
+
+	logger.Info().
+		Str("url", conn.ConnectedUrl()).
+		Str("subject", cfg.Subject).
+		Str("queue", cfg.Queue).
+		Msg("Consuming heartbeats from NATS")
+
+	<-ctx.Done()
+
+	// Handle the messages already received before the connection is closed.
+	if err := conn.Drain(); err != nil {
+		return err
+	}
+
+	<-closed
+
+	return nil
+}
@@ -0,0 +1,261 @@
+package build
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/bavix/vakeel-way/internal/config"
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/alertmanager"
+	"github.com/bavix/vakeel-way/internal/infra/capture"
+	"github.com/bavix/vakeel-way/internal/infra/instatus"
+	"github.com/bavix/vakeel-way/internal/infra/kuma"
+	"github.com/bavix/vakeel-way/internal/infra/notifier"
+	"github.com/bavix/vakeel-way/internal/infra/oncall"
+	"github.com/bavix/vakeel-way/internal/infra/pagerduty"
+	"github.com/bavix/vakeel-way/internal/infra/slack"
+	"github.com/bavix/vakeel-way/internal/infra/srv"
+)
+
+// Target types supported by the notifier.
+const (
+	targetSlack     = "slack"
+	targetPagerDuty = "pagerduty"
+	targetCapture   = "capture"
+	targetKuma      = "kuma"
+	targetAlerts    = "alertmanager"
+	targetOnCall    = "oncall"
+)
+
+// ErrDuplicateTarget is an error that indicates that two targets of a webhook share a name.
+//
+// The failed deliveries are retried and queued by the name of the target, so
+// the names have to be unique within the webhook.
+var ErrDuplicateTarget = errors.New("notifier: the target name is not unique")
+
+// ErrUnsupportedMethod is an error that indicates that the HTTP method of a target is not supported.
+var ErrUnsupportedMethod = errors.New("notifier: unsupported HTTP method")
+
+// ErrIncompleteComponent is an error that indicates that an Instatus component or incident lacks its page or token.
+var ErrIncompleteComponent = errors.New("notifier: the Instatus component requires a page and a token")
+
+// ErrUnknownFormat is an error that indicates that the format of a target is unknown.
+var ErrUnknownFormat = errors.New("notifier: unknown format")
+
+// ErrFormatWithBody is an error that indicates that a target has both a format and a body template.
+var ErrFormatWithBody = errors.New("notifier: the format cannot be combined with a body template")
+
+// targetMethods are the HTTP methods the targets may override POST with.
+var targetMethods = []string{http.MethodPost, http.MethodPut, http.MethodPatch}
+
+// notifierMux returns a new instance of the notifier.Mux struct.
+//
+// The notifier.Mux routes the status updates to the client registered for the
+// type of the target: Instatus, Slack, PagerDuty, Uptime Kuma, Alertmanager,
+// Grafana OnCall or the capture notifier.
+//
+// The function returns a pointer to a notifier.Mux struct.
+func (b *Builder) notifierMux() *notifier.Mux {
+	// The hosts of the targets are resolved again as they change, and the
+	// open Instatus incidents and the captured notifications are kept.
+	return b.newNotifierMux(b.targetClient(), b.inStatusClient(), b.captureNotifier())
+}
+
+// newNotifierMux creates a notifier.Mux with a client registered for every
+// supported target type.
+//
+// The server and the preview share it, so a new target type is registered
+// for both.
+//
+// Parameters:
+//   - client: The http.Client of the clients of the targets.
+//   - inStatus: The client of the Instatus targets.
+//   - captured: The notifier of the capture targets.
+//
+// Returns:
+//   - A pointer to a notifier.Mux struct.
+func (b *Builder) newNotifierMux(client http.Client, inStatus *instatus.API, captured *capture.API) *notifier.Mux {
+	mux := notifier.NewMux(notifier.WithRenderer(b.renderer))
+
+	// Register the clients for the supported target types.
+	mux.Handle(config.TargetInstatus, inStatus)
+	mux.Handle(targetSlack, slack.NewAPI(slack.WithClient(client)))
+	mux.Handle(targetPagerDuty, pagerduty.NewAPI(pagerduty.WithClient(client)))
+	mux.Handle(targetKuma, kuma.NewAPI(kuma.WithClient(client)))
+	mux.Handle(targetAlerts, alertmanager.NewAPI(alertmanager.WithClient(client)))
+	mux.Handle(targetOnCall, oncall.NewAPI(oncall.WithClient(client)))
+	mux.Handle(targetCapture, captured)
+
+	return mux
+}
+
+// targetClient returns the HTTP client of the targets.
+//
+// The client resolves the hosts of the "http+srv" and "https+srv" URLs with
+// SRV records, and the other hosts again every refresh interval. Its
+// transport is shared by the clients of all target types, so the records are
+// resolved once.
+//
+// Returns:
+//   - The http.Client of the targets.
+//
+//nolint:exhaustruct
+func (b *Builder) targetClient() http.Client {
+	// Check if the Builder instance already has a transport.
+	if b.transport == nil {
+		b.transport = srv.NewTransport(
+			http.DefaultTransport.(*http.Transport).Clone(), //nolint:forcetypeassert
+			srv.WithRefresh(b.config.Delivery.DNS.Refresh),
+		)
+	}
+
+	return http.Client{Transport: b.transport}
+}
+
+// captureNotifier returns the notifier of the targets of the "capture" type.
+// If the Builder instance already has a capture notifier, it will be returned,
+// so the captured notifications can be listed with the administrative API.
+//
+// Returns:
+//   - A pointer to a capture.API.
+func (b *Builder) captureNotifier() *capture.API {
+	// Check if the Builder instance already has a capture notifier.
+	if b.capture != nil {
+		return b.capture
+	}
+
+	b.capture = capture.NewAPI(
+		capture.WithSize(b.config.Delivery.Capture.Size),
+		capture.WithFile(b.config.Delivery.Capture.File),
+	)
+
+	return b.capture
+}
+
+// PreviewNotifier returns a notifier.Mux whose clients send the requests
+// through the given transport.
+//
+// It is used by the `vakeel-way notify preview` command with a notifier.Recorder
+// to render the exact payloads without delivering them.
+//
+// Parameters:
+//   - transport: The http.RoundTripper used by every client.
+//
+// Returns:
+//   - A pointer to a notifier.Mux struct.
+//
+//nolint:exhaustruct
+func (b *Builder) PreviewNotifier(transport http.RoundTripper) *notifier.Mux {
+	client := http.Client{Transport: transport}
+
+	// The preview keeps neither the open incidents nor the captured notifications.
+	return b.newNotifierMux(client, instatus.NewAPI(instatus.WithClient(client)), capture.NewAPI())
+}
+
+// validateTargets checks that every configured target has a supported type,
+// a supported HTTP method and format, valid message, body and incident templates and,
+// for the Instatus components, a page, a token and a valid mapping of the statuses.
+//
+// Parameters:
+//   - webhooks: The configured webhooks.
+//
+// Returns:
+//   - An error wrapping notifier.ErrUnknownType for the first unsupported target.
+//   - An error wrapping ErrDuplicateTarget for the first name shared by two targets of a webhook.
+//   - An error wrapping ErrUnsupportedMethod for the first unsupported method.
+//   - An error wrapping ErrUnknownFormat or ErrFormatWithBody for the first invalid format.
+//   - An error wrapping ErrIncompleteComponent or instatus.ErrUnknownComponentStatus
+//     for the first invalid Instatus component.
+//   - An error if a template cannot be parsed.
+func (b *Builder) validateTargets(webhooks config.Webhooks) error {
+	mux := b.notifierMux()
+
+	for _, webhook := range webhooks {
+		names := make(map[string]struct{})
+
+		for _, target := range webhook.Entities() {
+			if !mux.Supports(target.Type) {
+				return fmt.Errorf("%w: %s (webhook %s)", notifier.ErrUnknownType, target.Type, webhook.ID)
+			}
+
+			if _, ok := names[target.Name]; ok {
+				return fmt.Errorf("%w: %s (webhook %s)", ErrDuplicateTarget, target.Name, webhook.ID)
+			}
+
+			names[target.Name] = struct{}{}
+
+			if target.Method != "" && !slices.Contains(targetMethods, strings.ToUpper(target.Method)) {
+				return fmt.Errorf("%w: %s (webhook %s, target %s)", ErrUnsupportedMethod, target.Method, webhook.ID, target.Name)
+			}
+
+			if err := validateFormat(target); err != nil {
+				return fmt.Errorf("%w (webhook %s, target %s)", err, webhook.ID, target.Name)
+			}
+
+			if err := b.renderer.Compile(target.Template); err != nil {
+				return fmt.Errorf("%w (webhook %s, target %s)", err, webhook.ID, target.Name)
+			}
+
+			if target.Body != "" {
+				if err := b.renderer.Compile(target.Body); err != nil {
+					return fmt.Errorf("%w (webhook %s, target %s body)", err, webhook.ID, target.Name)
+				}
+			}
+
+			if err := validateComponent(target); err != nil {
+				return fmt.Errorf("%w (webhook %s, target %s)", err, webhook.ID, target.Name)
+			}
+
+			if target.Title != "" {
+				if err := b.renderer.Compile(target.Title); err != nil {
+					return fmt.Errorf("%w (webhook %s, target %s incident)", err, webhook.ID, target.Name)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateComponent checks that the Instatus component or incidents of the
+// target have a page, a token and a valid mapping of the statuses.
+func validateComponent(target entities.Target) error {
+	if target.Type != config.TargetInstatus || !instatus.UsesAPI(target) {
+		return nil
+	}
+
+	if target.Page == "" || target.Token == "" {
+		return ErrIncompleteComponent
+	}
+
+	for key := range target.Statuses {
+		status, err := entities.ParseStatus(key)
+		if err != nil {
+			return err
+		}
+
+		if _, err := instatus.ComponentStatus(target, status); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateFormat checks that the format of the target is known and is not
+// combined with a body template.
+func validateFormat(target entities.Target) error {
+	switch {
+	case target.Format == "":
+		return nil
+	case target.Format != instatus.FormatCloudEvents:
+		return fmt.Errorf("%w: %s", ErrUnknownFormat, target.Format)
+	case target.Body != "":
+		return ErrFormatWithBody
+	default:
+		return nil
+	}
+}
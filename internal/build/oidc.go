@@ -0,0 +1,99 @@
+package build
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/bavix/vakeel-way/internal/app"
+	"github.com/bavix/vakeel-way/internal/config"
+	"github.com/bavix/vakeel-way/internal/infra/oidc"
+)
+
+var (
+	// ErrNoOIDCClient is an error that indicates that the OIDC login is enabled without an issuer, a client ID or a redirect URL.
+	ErrNoOIDCClient = errors.New("admin http: oidc requires an issuer, a client_id and a redirect_url")
+
+	// ErrNoOIDCAllowList is an error that indicates that the OIDC login is enabled without the allowed emails or domains.
+	ErrNoOIDCAllowList = errors.New("admin http: oidc requires the allowed emails or domains")
+)
+
+// validateOIDC validates the configuration of the OIDC login.
+//
+// Parameters:
+//   - cfg: The configuration of the OIDC login.
+//
+// Returns:
+//   - ErrNoOIDCClient if the client is not fully configured.
+//   - ErrNoOIDCAllowList if every user of the provider would be allowed to sign in.
+func validateOIDC(cfg config.OIDCConfig) error {
+	if cfg.Issuer == "" || cfg.ClientID == "" || cfg.RedirectURL == "" {
+		return ErrNoOIDCClient
+	}
+
+	if u, err := url.Parse(cfg.RedirectURL); err != nil || !u.IsAbs() {
+		return ErrNoOIDCClient
+	}
+
+	if len(cfg.Emails) == 0 && len(cfg.Domains) == 0 {
+		return ErrNoOIDCAllowList
+	}
+
+	return nil
+}
+
+// oidcProvider returns the Provider of the OIDC login, or nil if it is disabled.
+// If the Builder instance already has a Provider instance, it will be returned.
+//
+// The client secret is decrypted with the keyring.
+//
+// Returns:
+//   - A pointer to a Provider, or nil.
+//   - An error if the secret cannot be decrypted.
+func (b *Builder) oidcProvider() (*oidc.Provider, error) {
+	cfg := b.config.AdminHTTP.OIDC
+
+	// Check if the OIDC login is enabled and the Builder instance already has a Provider instance.
+	if !cfg.Enabled || b.oidc != nil {
+		return b.oidc, nil
+	}
+
+	// Decrypt the secret if it is stored encrypted.
+	secret, err := b.Keyring().Open(cfg.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	// The time allowed to call the provider.
+	const providerTimeout = 10 * time.Second
+
+	provider, err := oidc.NewProvider(cfg.Issuer, cfg.ClientID, secret, cfg.RedirectURL,
+		oidc.WithScopes(cfg.Scopes),
+		oidc.WithAllowedEmails(cfg.Emails),
+		oidc.WithAllowedDomains(cfg.Domains),
+		oidc.WithSessionTTL(cfg.SessionTTL),
+		oidc.WithHTTPClient(&http.Client{Timeout: providerTimeout}), //nolint:exhaustruct
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	b.oidc = provider
+
+	return b.oidc, nil
+}
+
+// sessionAuthenticator returns the SessionAuthenticator of the admin REST API.
+//
+// Returns:
+//   - The SessionAuthenticator, or nil if the OIDC login is disabled.
+//   - An error if the secret cannot be decrypted.
+func (b *Builder) sessionAuthenticator() (app.SessionAuthenticator, error) {
+	provider, err := b.oidcProvider()
+	if err != nil || provider == nil {
+		return nil, err
+	}
+
+	return provider, nil
+}
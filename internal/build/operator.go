@@ -0,0 +1,207 @@
+package build
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/config"
+	"github.com/bavix/vakeel-way/internal/infra/kubernetes"
+)
+
+// kubernetesOperator returns the Operator watching the webhooks in Kubernetes.
+// If the Builder instance already has an Operator instance, it will be returned.
+//
+// Returns:
+//   - A pointer to an Operator, or nil if the operator mode is disabled.
+//   - An error if the API server, the namespace or the CA bundle cannot be found.
+func (b *Builder) kubernetesOperator() (*kubernetes.Operator, error) {
+	cfg := b.config.Operator
+
+	// Check if the Builder instance already has an Operator instance.
+	if b.operator != nil || !cfg.Enabled {
+		return b.operator, nil
+	}
+
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		var err error
+
+		if endpoint, err = kubernetes.Endpoint(); err != nil {
+			return nil, err
+		}
+	}
+
+	namespace := cfg.Namespace
+	if namespace == "" {
+		var err error
+
+		if namespace, err = kubernetes.Namespace(); err != nil {
+			return nil, err
+		}
+	}
+
+	client, err := kubernetes.NewHTTPClient(cfg.CAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	b.operator = kubernetes.NewOperator(
+		namespace,
+		kubernetes.WithOperatorEndpoint(endpoint),
+		kubernetes.WithOperatorTokenFile(cfg.TokenFile),
+		kubernetes.WithOperatorHTTPClient(client),
+		kubernetes.WithResync(cfg.Resync),
+	)
+
+	return b.operator, nil
+}
+
+// StartOperator syncs the webhooks defined in Kubernetes, as specified by the
+// `Operator` field of the configuration, in the background until the context
+// is closed.
+//
+// If the operator mode is disabled in the configuration, the function does nothing.
+//
+// ctx - The context.Context holding the logger and stopping the sync.
+func (b *Builder) StartOperator(ctx context.Context) {
+	// The Operator is validated in NewBuilder, so the error is ignored here.
+	operator, _ := b.kubernetesOperator()
+	if operator == nil {
+		return
+	}
+
+	zerolog.Ctx(ctx).Info().Str("namespace", operator.Namespace()).Msg("Watching webhooks in Kubernetes")
+
+	b.background.Add(1)
+
+	go func() {
+		defer b.background.Done()
+
+		operator.Run(ctx, b.syncWebhooks)
+	}()
+}
+
+// syncWebhooks replaces the webhooks defined in Kubernetes with the resources.
+//
+// Every resource is validated like the configuration file: the invalid ones
+// are logged and skipped, so a broken manifest does not remove the other
+// webhooks. The webhooks of the configuration file take precedence over the
+// ones with the same UUID.
+//
+// Parameters:
+//   - ctx: The context.Context holding the logger.
+//   - resources: The resources listed by the Operator.
+func (b *Builder) syncWebhooks(ctx context.Context, resources []kubernetes.Resource) {
+	logger := zerolog.Ctx(ctx)
+
+	var synced config.Webhooks
+
+	for _, resource := range resources {
+		webhooks, err := b.parseResource(resource)
+		if err != nil {
+			err = fmt.Errorf("%s: %w", resource.Source, err)
+			b.reporter.ConfigError(err)
+			logger.Error().Err(err).Msg("Invalid webhooks in Kubernetes skipped")
+
+			continue
+		}
+
+		synced = append(synced, webhooks...)
+	}
+
+	b.reload.Lock()
+	defer b.reload.Unlock()
+
+	merged := mergeWebhooks(b.webhooks, synced)
+
+	// A dependency may only be resolved once all the resources are listed.
+	if err := b.validateDependencies(merged); err != nil {
+		b.reporter.ConfigError(err)
+		logger.Error().Err(err).Msg("Webhooks in Kubernetes not synced")
+
+		return
+	}
+
+	b.operated = synced
+	b.replaceWebhooks(merged)
+
+	logger.Info().Int("webhooks", len(synced)).Msg("Webhooks synced from Kubernetes")
+}
+
+// parseResource parses and validates the webhooks of the resource.
+//
+// Parameters:
+//   - resource: The resource listed by the Operator.
+//
+// Returns:
+//   - The webhooks of the resource.
+//   - An error if the resource is invalid.
+func (b *Builder) parseResource(resource kubernetes.Resource) (config.Webhooks, error) {
+	var webhooks config.Webhooks
+
+	if resource.List {
+		parsed, err := config.ParseWebhooks(resource.Spec)
+		if err != nil {
+			return nil, err
+		}
+
+		webhooks = parsed
+	} else {
+		parsed, err := config.ParseWebhook(resource.Spec)
+		if err != nil {
+			return nil, err
+		}
+
+		webhooks = config.Webhooks{parsed}
+	}
+
+	// Make sure every target can be delivered to, every badge can be rendered
+	// and every schedule can be followed.
+	if err := b.validateTargets(webhooks); err != nil {
+		return nil, err
+	}
+
+	if err := b.validateBadges(webhooks); err != nil {
+		return nil, err
+	}
+
+	if err := b.validateSchedules(webhooks); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// mergeWebhooks returns the webhooks of the configuration file followed by
+// the webhooks defined in Kubernetes whose UUID is not taken yet.
+//
+// Parameters:
+//   - file: The webhooks of the configuration file.
+//   - operated: The webhooks defined in Kubernetes.
+//
+// Returns:
+//   - The merged webhooks.
+func mergeWebhooks(file, operated config.Webhooks) config.Webhooks {
+	merged := make(config.Webhooks, 0, len(file)+len(operated))
+	merged = append(merged, file...)
+
+	seen := make(map[uuid.UUID]struct{}, len(file))
+	for _, webhook := range file {
+		seen[webhook.ID] = struct{}{}
+	}
+
+	// The first of the resources defining a UUID wins, as the resources are sorted.
+	for _, webhook := range operated {
+		if _, ok := seen[webhook.ID]; ok {
+			continue
+		}
+
+		seen[webhook.ID] = struct{}{}
+		merged = append(merged, webhook)
+	}
+
+	return merged
+}
@@ -0,0 +1,87 @@
+package build
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/domain/services"
+	"github.com/bavix/vakeel-way/internal/infra/outbox"
+)
+
+// deliveryOutbox returns the durable queue of the deliveries.
+// If the Builder instance already has an Outbox instance, it will be returned.
+//
+// The deliveries exhausting their attempts are moved to the dead letters.
+//
+// Returns:
+//   - A pointer to an Outbox, or nil if the outbox is not configured.
+//   - An error if the queue file cannot be opened.
+func (b *Builder) deliveryOutbox() (*outbox.Outbox, error) {
+	// Check if the Builder instance already has an Outbox instance.
+	if b.outbox != nil || b.config.Delivery.Outbox.Path == "" {
+		return b.outbox, nil
+	}
+
+	store, err := b.deadLetterStore()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := b.config.Delivery.Outbox
+
+	box, err := outbox.Open(
+		cfg.Path,
+		b.notifierBreaker(),
+		b.WebhookRepository(),
+		outbox.WithBackoff(cfg.Backoff, cfg.MaxBackoff),
+		outbox.WithAttempts(cfg.Attempts),
+		outbox.WithDeadLetter(store),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	b.metricsRegistry().MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{ //nolint:exhaustruct
+			Namespace: "vakeel",
+			Name:      "outbox_pending",
+			Help:      "Number of notifications queued in the outbox and waiting to be delivered.",
+		}, func() float64 {
+			return float64(box.Len())
+		}),
+	)
+
+	b.outbox = box
+
+	return b.outbox, nil
+}
+
+// deliverySender returns the notifier the StateManager delivers the notifications with.
+//
+// Returns:
+//   - The outbox wrapping the notifier if it is configured, the notifier otherwise.
+func (b *Builder) deliverySender() services.API {
+	// The queue file is opened in NewBuilder, so the error can be ignored here.
+	if box, _ := b.deliveryOutbox(); box != nil {
+		return box
+	}
+
+	return b.notifierBreaker()
+}
+
+// closeOutbox closes the queue file of the outbox, once the StateManager no
+// longer delivers the notifications.
+//
+// Parameters:
+//   - ctx: The context.Context holding the logger.
+func (b *Builder) closeOutbox(ctx context.Context) {
+	if b.outbox == nil {
+		return
+	}
+
+	if err := b.outbox.Close(); err != nil {
+		zerolog.Ctx(ctx).Err(err).Msg("outbox: failed to close the queue")
+	}
+}
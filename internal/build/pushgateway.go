@@ -0,0 +1,135 @@
+package build
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/push"
+	"github.com/rs/zerolog"
+)
+
+// ErrPushInterval is an error that indicates that the interval of the pushes to the Pushgateway is not positive.
+var ErrPushInterval = errors.New("pushgateway: the interval must be positive")
+
+// metricsPusher returns the Pusher of the metrics to the Pushgateway.
+// If the Builder instance already has a Pusher instance, it will be returned.
+//
+// The Pusher pushes the metrics of the registry exposed on the /metrics endpoint.
+//
+// Returns:
+//   - A pointer to a push.Pusher, or nil if the push is disabled.
+//   - ErrPushInterval if the interval is not positive.
+//   - An error if the password cannot be decrypted or the hostname cannot be read.
+func (b *Builder) metricsPusher() (*push.Pusher, error) {
+	cfg := b.config.Pushgateway
+
+	// Check if the Builder instance already has a Pusher instance.
+	if b.pusher != nil || !cfg.Enabled {
+		return b.pusher, nil
+	}
+
+	if cfg.Interval <= 0 {
+		return nil, ErrPushInterval
+	}
+
+	// Decrypt the password if it is stored encrypted.
+	password, err := b.Keyring().Open(cfg.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	// The instances of a cluster push their own groups.
+	instance := cfg.Instance
+	if instance == "" {
+		if instance, err = os.Hostname(); err != nil {
+			return nil, err
+		}
+	}
+
+	// The time allowed to push the metrics.
+	const timeout = 10 * time.Second
+
+	pusher := push.New(cfg.URL, cfg.Job).
+		Gatherer(b.metricsRegistry()).
+		Client(&http.Client{Timeout: timeout}). //nolint:exhaustruct
+		Grouping("instance", instance)
+
+	for name, value := range cfg.Grouping {
+		pusher = pusher.Grouping(name, value)
+	}
+
+	if cfg.Username != "" {
+		pusher = pusher.BasicAuth(cfg.Username, password)
+	}
+
+	b.pusher = pusher
+
+	return b.pusher, nil
+}
+
+// StartPushgateway pushes the metrics to the Pushgateway specified by the
+// `Pushgateway` field of the configuration every interval in the background.
+// Every push replaces the group of the instance. The metrics are pushed once
+// more when the context is closed, or their group is deleted if it is
+// configured so, before Wait returns.
+//
+// If the push is disabled in the configuration, the function does nothing.
+//
+// ctx - The context.Context used to stop the push.
+func (b *Builder) StartPushgateway(ctx context.Context) {
+	// The Pusher is created by NewBuilder.
+	pusher := b.pusher
+
+	// Do nothing if the push is disabled.
+	if pusher == nil {
+		return
+	}
+
+	cfg := b.config.Pushgateway
+	logger := zerolog.Ctx(ctx)
+
+	// The time allowed to push the metrics on shutdown.
+	const timeout = 5 * time.Second
+
+	logger.Info().Str("url", cfg.URL).Str("job", cfg.Job).Dur("interval", cfg.Interval).Msg("Pushing metrics to the Pushgateway")
+
+	b.background.Add(1)
+
+	go func() {
+		defer b.background.Done()
+
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+
+		for {
+			if err := pusher.PushContext(ctx); err != nil && ctx.Err() == nil {
+				logger.Warn().Err(err).Msg("Failed to push the metrics to the Pushgateway")
+			}
+
+			select {
+			case <-ctx.Done():
+				if cfg.DeleteOnShutdown {
+					if err := pusher.Delete(); err != nil {
+						logger.Warn().Err(err).Msg("Failed to delete the metrics from the Pushgateway")
+					}
+
+					return
+				}
+
+				// The context is closed, so the last push uses a fresh one.
+				last, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+				defer cancel()
+
+				if err := pusher.PushContext(last); err != nil {
+					logger.Warn().Err(err).Msg("Failed to push the last metrics to the Pushgateway")
+				}
+
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
@@ -0,0 +1,184 @@
+package build
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/relay"
+	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+)
+
+// ErrNoRelayListener is an error that indicates that the relay has nothing to listen on.
+var ErrNoRelayListener = errors.New("relay: neither listen address nor socket is configured")
+
+// RunRelay starts the relay mode.
+//
+// The relay accepts the heartbeats of the local processes on the HTTP address
+// and the Unix socket specified by the `Relay` field of the configuration, and
+// multiplexes them over a single gRPC stream to the upstream server. The
+// function blocks until the context is closed or a listener fails.
+//
+// ctx - The context.Context used to stop the relay.
+// Returns an error if the relay cannot listen on the configured addresses.
+func (b *Builder) RunRelay(ctx context.Context) error {
+	cfg := b.config.Relay
+
+	// Stop the relay if a listener fails.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Get the logger from the context.
+	logger := zerolog.Ctx(ctx)
+
+	// Decrypt the token if it is stored encrypted.
+	token, err := b.Keyring().Open(cfg.Token)
+	if err != nil {
+		return err
+	}
+
+	creds, err := clientCredentials(cfg.TLS)
+	if err != nil {
+		return err
+	}
+
+	// Connect to the upstream server. The connection is established lazily and
+	// reestablished by the relay if the server is unreachable.
+	conn, err := grpc.NewClient(cfg.Upstream, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// The resolution of the heartbeats buffered during an outage.
+	const resolution = time.Minute
+
+	// Load the heartbeats buffered during the previous outage.
+	offline, err := relay.OpenOffline(cfg.Offline, resolution)
+	if err != nil {
+		return err
+	}
+
+	// The hostname is optional in the handshake.
+	hostname, _ := os.Hostname()
+
+	relayer := relay.NewRelay(
+		way.NewStateServiceClient(conn),
+		relay.WithAgent(entities.Agent{Hostname: hostname, Version: b.version, Labels: cfg.Labels}),
+		relay.WithOffline(offline),
+		relay.WithToken(token),
+		relay.WithInterval(cfg.Interval),
+		relay.WithBufferSize(cfg.Buffer),
+	)
+
+	listeners, err := b.relayListeners()
+	if err != nil {
+		return err
+	}
+
+	// The time allowed to read the request headers.
+	const readHeaderTimeout = 10 * time.Second
+
+	//nolint:exhaustruct
+	server := &http.Server{
+		Handler:           relayer.Handler(),
+		ReadHeaderTimeout: readHeaderTimeout,
+		BaseContext:       func(_ net.Listener) context.Context { return ctx },
+	}
+
+	var (
+		wg      sync.WaitGroup
+		errOnce sync.Once
+		srvErr  error
+	)
+
+	// Serve the heartbeats on every listener. If a listener fails, the relay is stopped.
+	for _, listener := range listeners {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			logger.Info().Str("addr", listener.Addr().String()).Msg("Starting relay listener")
+
+			if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+				errOnce.Do(func() { srvErr = err })
+				cancel()
+			}
+		}()
+	}
+
+	// Shut the server down when the context is closed.
+	go func() {
+		<-ctx.Done()
+
+		//nolint:contextcheck
+		_ = server.Shutdown(context.Background())
+	}()
+
+	logger.Info().Str("upstream", cfg.Upstream).Msg("Starting relay")
+
+	// Flush the heartbeats until the context is closed, including the last ones.
+	relayer.Run(ctx)
+	wg.Wait()
+
+	return srvErr
+}
+
+// relayListeners opens the listeners of the relay.
+//
+// Returns:
+//   - The TCP listener and the Unix socket listener, whichever are configured.
+//   - An error if a listener cannot be opened.
+func (b *Builder) relayListeners() ([]net.Listener, error) {
+	cfg := b.config.Relay
+	listeners := make([]net.Listener, 0, 2) //nolint:mnd
+
+	if cfg.Listen != "" {
+		listener, err := net.Listen("tcp", cfg.Listen)
+		if err != nil {
+			return nil, err
+		}
+
+		listeners = append(listeners, listener)
+	}
+
+	if cfg.Socket != "" {
+		// Remove the socket left behind by the previous run.
+		if err := os.Remove(cfg.Socket); err != nil && !os.IsNotExist(err) {
+			return nil, errors.Join(err, closeAll(listeners))
+		}
+
+		listener, err := net.Listen("unix", cfg.Socket)
+		if err != nil {
+			return nil, errors.Join(err, closeAll(listeners))
+		}
+
+		listeners = append(listeners, listener)
+	}
+
+	if len(listeners) == 0 {
+		return nil, ErrNoRelayListener
+	}
+
+	return listeners, nil
+}
+
+// closeAll closes the listeners.
+func closeAll(listeners []net.Listener) error {
+	errs := make([]error, 0, len(listeners))
+
+	for _, listener := range listeners {
+		errs = append(errs, listener.Close())
+	}
+
+	return errors.Join(errs...)
+}
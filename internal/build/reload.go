@@ -0,0 +1,113 @@
+package build
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/config"
+	"github.com/bavix/vakeel-way/internal/infra/repositories"
+)
+
+// ErrNoConfigPath is an error that indicates that the configuration was not loaded from a file.
+var ErrNoConfigPath = errors.New("reload: the configuration file is unknown")
+
+// Reload reloads the webhooks from the configuration file.
+//
+// The webhooks of the file are validated like on startup, and the running
+// webhooks are kept if they are invalid. The webhooks archived as stale are
+// restored if they are still configured. The webhooks synced from Kubernetes
+// are kept. The other sections of the
+// configuration take effect on restart only.
+//
+// Parameters:
+//   - ctx: The context.Context holding the logger.
+//
+// Returns:
+//   - The number of the reloaded webhooks.
+//   - An error if the configuration cannot be read or is invalid.
+func (b *Builder) Reload(ctx context.Context) (int, error) {
+	if b.path == "" {
+		return 0, ErrNoConfigPath
+	}
+
+	b.reload.Lock()
+	defer b.reload.Unlock()
+
+	cfg, err := b.loadWebhooks()
+	if err != nil {
+		b.reporter.ConfigError(err)
+
+		return 0, err
+	}
+
+	b.webhooks = cfg.Webhooks
+	count := b.replaceWebhooks(mergeWebhooks(b.webhooks, b.operated))
+
+	zerolog.Ctx(ctx).Info().Int("webhooks", count).Msg("Webhooks reloaded")
+
+	return count, nil
+}
+
+// replaceWebhooks replaces the webhooks of the repository.
+//
+// The caller holds the reload lock.
+//
+// Parameters:
+//   - webhooks: The webhooks of the configuration file and of Kubernetes.
+//
+// Returns:
+//   - The number of the webhooks.
+func (b *Builder) replaceWebhooks(webhooks config.Webhooks) int {
+	targets := webhooks.AsMap()
+
+	b.WebhookRepository().Replace(
+		targets,
+		repositories.WithLabels(webhooks.Labels()),
+		repositories.WithNamespaces(webhooks.Namespaces()),
+		repositories.WithTolerances(webhooks.Tolerances()),
+		repositories.WithAgentConfigs(webhooks.AgentConfigs()),
+		repositories.WithBadges(webhooks.Badges()),
+		repositories.WithDependencies(webhooks.Dependencies()),
+	)
+
+	return len(targets)
+}
+
+// loadWebhooks reads the configuration file and validates its webhooks like on startup.
+//
+// Returns:
+//   - The configuration read from the file.
+//   - An error if the configuration cannot be read or is invalid.
+func (b *Builder) loadWebhooks() (config.Config, error) {
+	cfg, err := config.New(b.path)
+	if err != nil {
+		return cfg, err
+	}
+
+	// Read the values referenced in the AWS secret stores.
+	if err := resolveAWS(&cfg); err != nil {
+		return cfg, err
+	}
+
+	// Make sure every target can be delivered to, every badge can be rendered,
+	// every schedule can be followed and the dependencies form no cycle.
+	if err := b.validateTargets(cfg.Webhooks); err != nil {
+		return cfg, err
+	}
+
+	if err := b.validateBadges(cfg.Webhooks); err != nil {
+		return cfg, err
+	}
+
+	if err := b.validateSchedules(cfg.Webhooks); err != nil {
+		return cfg, err
+	}
+
+	if err := b.validateDependencies(cfg.Webhooks); err != nil {
+		return cfg, err
+	}
+
+	return cfg, nil
+}
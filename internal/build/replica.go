@@ -0,0 +1,42 @@
+package build
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/domain/services"
+)
+
+// replicaService returns the Replica holding the mode of the current instance.
+// If the Builder instance already has a Replica instance, it will be returned.
+//
+// Parameters:
+//   - ctx: The context.Context carrying the logger.
+//
+// Returns:
+//   - A pointer to a Replica.
+func (b *Builder) replicaService(ctx context.Context) *services.Replica {
+	// Check if the Builder instance already has a Replica instance.
+	if b.replica != nil {
+		return b.replica
+	}
+
+	// The mode is validated in NewBuilder, so the error can be ignored here.
+	mode, _ := entities.ParseMode(b.config.Replica.Mode)
+
+	// In the leader election, the instance starts in standby mode and the
+	// promotion is coordinated with the other instances.
+	if coordinator, _ := b.coordinator(); coordinator != nil {
+		b.replica = services.NewReplica(entities.Standby, coordinator, zerolog.Ctx(ctx))
+
+		return b.replica
+	}
+
+	// Create a new Replica instance. The promotion is not coordinated with
+	// other instances outside of the leader election.
+	b.replica = services.NewReplica(mode, nil, zerolog.Ctx(ctx))
+
+	return b.replica
+}
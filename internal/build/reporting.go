@@ -0,0 +1,87 @@
+package build
+
+import (
+	"time"
+
+	"github.com/bavix/vakeel-way/internal/config"
+	"github.com/bavix/vakeel-way/internal/infra/reporting"
+)
+
+// flushTimeout is the time the reported errors are given to reach Sentry on exit.
+const flushTimeout = 2 * time.Second
+
+// errorReporter returns the Reporter of the errors.
+// If the Builder instance already has a Reporter instance, it will be returned.
+//
+// The DSN is decrypted with the keyring. The Reporter is disabled if no DSN
+// is configured.
+//
+// Returns:
+//   - A pointer to a Reporter.
+//   - An error if the DSN cannot be decrypted or is invalid.
+func (b *Builder) errorReporter() (*reporting.Reporter, error) {
+	// Check if the Builder instance already has a Reporter instance.
+	if b.reporter != nil {
+		return b.reporter, nil
+	}
+
+	reporter, err := newReporter(b.config.Sentry, b.Keyring().Open, b.version)
+	if err != nil {
+		return nil, err
+	}
+
+	b.reporter = reporter
+
+	return b.reporter, nil
+}
+
+// ReportError reports an error preventing the application from starting,
+// e.g. an invalid configuration, if the error reporting is configured.
+//
+// It is called before the Builder exists, so the DSN is read from the given
+// configuration, or from the environment if the configuration cannot be read.
+//
+// Parameters:
+//   - cfg: The configuration, possibly partially loaded.
+//   - version: The version of the application.
+//   - err: The error to report.
+func ReportError(cfg config.Config, version string, err error) {
+	open := func(value string) (string, error) { return value, nil }
+
+	// Decrypt the DSN if the keys can be loaded.
+	if keyring, kerr := newKeyring(cfg.Secrets); kerr == nil {
+		open = keyring.Open
+	}
+
+	reporter, rerr := newReporter(cfg.Sentry, open, version)
+	if rerr != nil {
+		return
+	}
+
+	reporter.ConfigError(err)
+	reporter.Flush(flushTimeout)
+}
+
+// newReporter creates the Reporter of the configuration.
+//
+// Parameters:
+//   - cfg: The configuration of the error reporting.
+//   - open: The function decrypting the DSN.
+//   - version: The version of the application, reported as the release.
+//
+// Returns:
+//   - A pointer to a Reporter.
+//   - An error if the DSN cannot be decrypted or is invalid.
+func newReporter(cfg config.SentryConfig, open func(string) (string, error), version string) (*reporting.Reporter, error) {
+	dsn, err := open(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	return reporting.New(reporting.Options{
+		DSN:         dsn,
+		Environment: cfg.Environment,
+		Release:     "vakeel-way@" + version,
+		SampleRate:  cfg.SampleRate,
+	})
+}
@@ -2,8 +2,10 @@ package build
 
 import "github.com/bavix/vakeel-way/internal/infra/repositories"
 
-// WebhookRepository returns a new instance of the WebhookStubRepository with
-// the webhook data loaded from the configuration.
+// WebhookRepository returns the WebhookStubRepository with the webhook data
+// loaded from the configuration.
+// If the Builder instance already has a repository instance, it will be returned,
+// so the webhooks archived at runtime are removed everywhere.
 //
 // It uses the webhook data from the configuration to create a new instance of
 // WebhookStubRepository. The webhook data is loaded from the configuration and
@@ -13,12 +15,35 @@ import "github.com/bavix/vakeel-way/internal/infra/repositories"
 //   - None
 //
 // Returns:
-//   - *repositories.WebhookStubRepository: A new instance of WebhookStubRepository
-//     with the webhook data loaded from the configuration.
+//   - *repositories.WebhookStubRepository: The WebhookStubRepository with the
+//     webhook data loaded from the configuration.
 func (b *Builder) WebhookRepository() *repositories.WebhookStubRepository {
+	// Check if the Builder instance already has a repository instance.
+	if b.repo != nil {
+		return b.repo
+	}
+
 	// Load the webhook data from the configuration.
 	webhookData := b.config.Webhooks.AsMap()
 
 	// Create a new instance of WebhookStubRepository with the webhook data.
-	return repositories.NewWebhookRepository(webhookData)
+	// The targets are kept encrypted and are decrypted by the keyring on retrieval.
+	// The labels are used by the label selectors of the silences.
+	// The namespaces scope the webhooks to the API tokens of their teams.
+	// The tolerances extend the TTL of the services with irregular heartbeats.
+	// The configuration of the agents is pushed to them over their UpdateStream calls.
+	// The configuration of the badges is used by the uptime badge endpoint.
+	// The dependencies hold back the notifications of the cascading failures.
+	b.repo = repositories.NewWebhookRepository(
+		webhookData,
+		repositories.WithOpener(b.Keyring()),
+		repositories.WithLabels(b.config.Webhooks.Labels()),
+		repositories.WithNamespaces(b.config.Webhooks.Namespaces()),
+		repositories.WithTolerances(b.config.Webhooks.Tolerances()),
+		repositories.WithAgentConfigs(b.config.Webhooks.AgentConfigs()),
+		repositories.WithBadges(b.config.Webhooks.Badges()),
+		repositories.WithDependencies(b.config.Webhooks.Dependencies()),
+	)
+
+	return b.repo
 }
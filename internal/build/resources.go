@@ -0,0 +1,74 @@
+package build
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"runtime/debug"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/infra/resources"
+)
+
+// resourceSizing returns the sizes of the internal buffers.
+// If the Builder instance already has the sizes, they will be returned.
+//
+// The limits of the container are detected from the cgroup file system and
+// overridden by the configuration.
+//
+// Returns:
+//   - The sizes of the internal buffers.
+func (b *Builder) resourceSizing() resources.Sizing {
+	// Check if the Builder instance already has the sizes.
+	if b.sizing != nil {
+		return *b.sizing
+	}
+
+	limits := resources.DetectHost()
+
+	// Apply the overrides of the configuration.
+	if b.config.Resources.CPUs > 0 {
+		limits.CPUs = b.config.Resources.CPUs
+	}
+
+	if b.config.Resources.Memory > 0 {
+		limits.Memory = b.config.Resources.Memory
+	}
+
+	sizing := limits.Sizing(runtime.NumCPU())
+
+	if b.config.Resources.EventBuffer > 0 {
+		sizing.EventBuffer = b.config.Resources.EventBuffer
+	}
+
+	b.sizing = &sizing
+
+	return sizing
+}
+
+// ApplyResources tunes the Go runtime to the resource limits of the container.
+//
+// GOMAXPROCS and the soft memory limit are set from the detected limits,
+// unless they are set explicitly with the GOMAXPROCS and GOMEMLIMIT
+// environment variables.
+//
+// Parameters:
+//   - ctx: The context.Context holding the logger.
+func (b *Builder) ApplyResources(ctx context.Context) {
+	sizing := b.resourceSizing()
+
+	if _, ok := os.LookupEnv("GOMAXPROCS"); !ok {
+		runtime.GOMAXPROCS(sizing.Procs)
+	}
+
+	if _, ok := os.LookupEnv("GOMEMLIMIT"); !ok && sizing.MemoryLimit > 0 {
+		debug.SetMemoryLimit(sizing.MemoryLimit)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Int("procs", runtime.GOMAXPROCS(0)).
+		Int64("memory_limit", debug.SetMemoryLimit(-1)).
+		Int("event_buffer", sizing.EventBuffer).
+		Msg("Resources applied")
+}
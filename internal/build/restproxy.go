@@ -0,0 +1,110 @@
+package build
+
+import (
+	"context"
+	"os"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/infra/restproxy"
+)
+
+// restProxyExporter returns the Exporter of the events to Kafka through the REST Proxy.
+// If the Builder instance already has an Exporter instance, it will be returned.
+//
+// Returns:
+//   - A pointer to an Exporter, or nil if the export is disabled.
+//   - An error if the password of the REST Proxy cannot be decrypted.
+func (b *Builder) restProxyExporter() (*restproxy.Exporter, error) {
+	cfg := b.config.KafkaRESTProxy
+
+	// Check if the Builder instance already has an Exporter instance.
+	if b.exporter != nil || !cfg.Enabled {
+		return b.exporter, nil
+	}
+
+	// Decrypt the password if it is stored encrypted.
+	password, err := b.Keyring().Open(cfg.Password)
+	if err != nil {
+		return nil, err
+	}
+
+	// The hostname tells the instances of a cluster apart.
+	hostname, _ := os.Hostname()
+
+	b.exporter = restproxy.NewExporter(
+		cfg.URL,
+		cfg.Topic,
+		restproxy.WithCredentials(cfg.Username, password),
+		restproxy.WithSource(hostname),
+		restproxy.WithInterval(cfg.Interval),
+		restproxy.WithBufferSize(cfg.Buffer),
+	)
+
+	return b.exporter, nil
+}
+
+// StartExport exports the events to the Kafka topic through the REST Proxy
+// specified by the `KafkaRESTProxy` field of the configuration in the
+// background. The status transitions are
+// exported by the active replica only, so a cluster exports them once, and the
+// heartbeats are exported by the instance that received them. The remaining
+// events are produced when the context is closed, before Wait returns.
+//
+// If the export is disabled in the configuration, the function does nothing.
+//
+// ctx - The context.Context used to stop the export.
+func (b *Builder) StartExport(ctx context.Context) {
+	// The Exporter is created by NewBuilder.
+	exporter := b.exporter
+
+	// Do nothing if the export is disabled.
+	if exporter == nil {
+		return
+	}
+
+	logger := zerolog.Ctx(ctx)
+	replica := b.replicaService(ctx)
+	watcher := b.statusWatcher()
+
+	// The number of the transitions buffered before the exporter is resubscribed.
+	const buffer = 1024
+
+	go func() {
+		transitions, unsubscribe := watcher.Subscribe(buffer)
+		defer func() { unsubscribe() }()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case transition, ok := <-transitions:
+				if !ok {
+					logger.Warn().Msg("Kafka REST Proxy export missed status transitions, resubscribing")
+
+					transitions, unsubscribe = watcher.Subscribe(buffer)
+
+					continue
+				}
+
+				if replica.Active() {
+					exporter.Transition(transition)
+				}
+			}
+		}
+	}()
+
+	logger.Info().
+		Str("proxy", b.config.KafkaRESTProxy.URL).
+		Str("topic", b.config.KafkaRESTProxy.Topic).
+		Bool("heartbeats", b.config.KafkaRESTProxy.Heartbeats).
+		Msg("Exporting events to Kafka through the REST Proxy")
+
+	b.background.Add(1)
+
+	go func() {
+		defer b.background.Done()
+
+		exporter.Run(ctx)
+	}()
+}
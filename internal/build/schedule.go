@@ -0,0 +1,24 @@
+package build
+
+import (
+	"fmt"
+
+	"github.com/bavix/vakeel-way/internal/config"
+)
+
+// validateSchedules checks that the cron expressions of the webhooks are valid.
+//
+// Parameters:
+//   - webhooks: The configured webhooks.
+//
+// Returns:
+//   - An error naming the webhook with an invalid cron expression.
+func (b *Builder) validateSchedules(webhooks config.Webhooks) error {
+	for _, webhook := range webhooks {
+		if _, err := webhook.ParseSchedule(); err != nil {
+			return fmt.Errorf("%w: %q (webhook %s)", err, webhook.Schedule, webhook.ID)
+		}
+	}
+
+	return nil
+}
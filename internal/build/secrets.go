@@ -0,0 +1,117 @@
+package build
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/bavix/vakeel-way/internal/config"
+	"github.com/bavix/vakeel-way/internal/infra/awssecrets"
+	"github.com/bavix/vakeel-way/internal/infra/secrets"
+)
+
+// Keyring returns the Keyring used to encrypt and decrypt sensitive values at rest.
+//
+// The Keyring is created from the configuration in NewBuilder.
+func (b *Builder) Keyring() *secrets.Keyring {
+	return b.keyring
+}
+
+// newKeyring creates a new Keyring from the secrets configuration.
+//
+// If Vault is configured, the Keyring reads the values referencing it from
+// Vault. The token of Vault may be sealed with the keys of the Keyring.
+//
+// Parameters:
+//   - cfg: The configuration of the encryption keys.
+//
+// Returns:
+//   - A pointer to the Keyring.
+//   - An error if the key material cannot be loaded or the token of Vault cannot be decrypted.
+func newKeyring(cfg config.SecretsConfig) (*secrets.Keyring, error) {
+	// Load the key material from the configured sources.
+	keys, err := cfg.Material()
+	if err != nil {
+		return nil, err
+	}
+
+	keyring, err := secrets.NewKeyring(cfg.Primary, keys)
+	if err != nil || cfg.Vault.Address == "" {
+		return keyring, err
+	}
+
+	// Decrypt the token if it is stored encrypted.
+	token, err := keyring.Open(cfg.Vault.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+
+	// The time allowed to call Vault.
+	const vaultTimeout = 10 * time.Second
+
+	opts := []secrets.VaultOption{
+		secrets.WithVaultToken(token),
+		secrets.WithVaultNamespace(cfg.Vault.Namespace),
+		secrets.WithVaultTTL(cfg.Vault.CacheTTL),
+		secrets.WithVaultClient(&http.Client{Timeout: vaultTimeout}), //nolint:exhaustruct
+	}
+
+	if cfg.Vault.TokenFile != "" {
+		opts = append(opts, secrets.WithVaultTokenFile(cfg.Vault.TokenFile))
+	}
+
+	keyring.SetVault(secrets.NewVault(cfg.Vault.Address, opts...))
+
+	return keyring, nil
+}
+
+// StartVault renews the token of Vault in the background until the context is closed.
+//
+// If Vault is not configured, the function returns immediately.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the renewals, holding the logger.
+func (b *Builder) StartVault(ctx context.Context) {
+	vault := b.Keyring().Vault()
+
+	// Do nothing if Vault is not configured.
+	if vault == nil {
+		return
+	}
+
+	b.background.Add(1)
+
+	go func() {
+		defer b.background.Done()
+
+		vault.Run(ctx)
+	}()
+}
+
+// resolveAWS replaces the references to AWS Secrets Manager and the SSM
+// Parameter Store in the configuration with their values.
+//
+// Parameters:
+//   - cfg: The configuration to resolve the references of.
+//
+// Returns:
+//   - An error if a reference cannot be resolved.
+func resolveAWS(cfg *config.Config) error {
+	// The time allowed to call AWS.
+	const awsTimeout = 10 * time.Second
+
+	resolver := awssecrets.NewResolver(
+		awssecrets.WithRegion(cfg.Secrets.AWS.Region),
+		awssecrets.WithEndpoint(cfg.Secrets.AWS.Endpoint),
+		awssecrets.WithHTTPClient(&http.Client{Timeout: awsTimeout}), //nolint:exhaustruct
+	)
+
+	return cfg.Expand(func(value string) (string, error) {
+		return resolver.Resolve(context.Background(), value)
+	})
+}
@@ -0,0 +1,84 @@
+package build
+
+import (
+	"context"
+
+	"github.com/bavix/vakeel-way/internal/app"
+)
+
+// stateService returns the implementation of the StateService.
+// If the Builder instance already has a GRPCServer instance, it will be returned.
+//
+// The implementation is shared by the gRPC server and the admin REST API.
+//
+// Parameters:
+//   - ctx: The context.Context holding the logger.
+//
+// Returns:
+//   - A pointer to a GRPCServer.
+//   - An error if the audit log cannot be opened.
+func (b *Builder) stateService(ctx context.Context) (*app.GRPCServer, error) {
+	// Check if the Builder instance already has a GRPCServer instance.
+	if b.stateServer != nil {
+		return b.stateServer, nil
+	}
+
+	auditLog, err := b.auditLog()
+	if err != nil {
+		return nil, err
+	}
+
+	b.stateServer = app.NewGRPCServer(
+		b.checkerUsecase(ctx),
+		b.quotaLimiter(),
+		b.WebhookRepository(),
+		b.WebhookRepository(),
+		b.config.GRPC.Strict,
+		b.stateManagerService(ctx),
+		b.statusWatcher(),
+		b.noticeAnnouncer(),
+		b.agentDirectory(),
+		auditLog,
+		b.history,
+		b.history,
+	)
+
+	return b.stateServer, nil
+}
+
+// adminService returns the implementation of the AdminService.
+// If the Builder instance already has an AdminServer instance, it will be returned.
+//
+// The implementation is shared by the gRPC server and the admin REST API.
+//
+// Parameters:
+//   - ctx: The context.Context holding the logger.
+//
+// Returns:
+//   - A pointer to an AdminServer.
+//   - An error if the audit log cannot be opened.
+func (b *Builder) adminService(ctx context.Context) (*app.AdminServer, error) {
+	// Check if the Builder instance already has an AdminServer instance.
+	if b.adminServer != nil {
+		return b.adminServer, nil
+	}
+
+	auditLog, err := b.auditLog()
+	if err != nil {
+		return nil, err
+	}
+
+	b.adminServer = app.NewAdminServer(
+		b.replicaService(ctx),
+		b.silencer,
+		b.stalePolicy(ctx),
+		b.captureNotifier(),
+		auditLog,
+		b.WebhookRepository(),
+		b.stateManagerService(ctx),
+		b,
+		b.deadLetterService(),
+	)
+
+	return b.adminServer, nil
+}
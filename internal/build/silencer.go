@@ -0,0 +1,24 @@
+package build
+
+import (
+	"fmt"
+
+	"github.com/bavix/vakeel-way/internal/domain/services"
+)
+
+// newSilencer creates the Silencer with the maintenance windows loaded from the configuration.
+//
+// Returns:
+//   - A pointer to a Silencer.
+//   - An error if a configured window is invalid.
+func (b *Builder) newSilencer() (*services.Silencer, error) {
+	silencer := services.NewSilencer(b.WebhookRepository())
+
+	for i, silence := range b.config.Silences {
+		if _, err := silencer.Add(silence.Entity()); err != nil {
+			return nil, fmt.Errorf("silences[%d]: %w", i, err)
+		}
+	}
+
+	return silencer, nil
+}
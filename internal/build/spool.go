@@ -0,0 +1,36 @@
+package build
+
+import (
+	"github.com/bavix/vakeel-way/internal/infra/spool"
+)
+
+// deliverySpool returns the queue of the notifications for the unavailable targets.
+// If the Builder instance already has a Spool instance, it will be returned.
+//
+// The queue is loaded from the file configured in the delivery section, so the
+// notifications queued before a restart are delivered as well.
+//
+// Returns:
+//   - A pointer to a Spool.
+//   - An error if the queue file cannot be read.
+func (b *Builder) deliverySpool() (*spool.Spool, error) {
+	// Check if the Builder instance already has a Spool instance.
+	if b.spool != nil {
+		return b.spool, nil
+	}
+
+	store, err := b.deadLetterStore()
+	if err != nil {
+		return nil, err
+	}
+
+	// The events discarded from the full queues are kept as dead letters.
+	queue, err := spool.Open(b.config.Delivery.Spool, spool.WithDeadLetter(store))
+	if err != nil {
+		return nil, err
+	}
+
+	b.spool = queue
+
+	return b.spool, nil
+}
@@ -0,0 +1,36 @@
+package build
+
+import (
+	"context"
+
+	"github.com/bavix/vakeel-way/internal/domain/services"
+)
+
+// stalePolicy returns the StalePolicy instance.
+// If the Builder instance already has a StalePolicy instance, it will be returned.
+// Otherwise, a new StalePolicy instance will be created and stored in the Builder instance.
+//
+// Parameters:
+//   - ctx: The context.Context holding the logger.
+//
+// Returns:
+//   - A pointer to a StalePolicy service, or nil if the policy is disabled.
+func (b *Builder) stalePolicy(ctx context.Context) *services.StalePolicy {
+	// Check if the Builder instance already has a StalePolicy instance,
+	// or the policy is disabled.
+	if b.stale != nil || b.config.Stale.After <= 0 {
+		return b.stale
+	}
+
+	options := make([]services.StaleOption, 0, 1)
+
+	// Remove the stale webhooks from the repository and the state manager if
+	// archiving is enabled.
+	if b.config.Stale.Archive {
+		options = append(options, services.WithArchive(b.WebhookRepository(), b.stateManagerService(ctx)))
+	}
+
+	b.stale = services.NewStalePolicy(b.WebhookRepository(), b.history, b.config.Stale.After, options...)
+
+	return b.stale
+}
@@ -0,0 +1,117 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/config"
+	"github.com/bavix/vakeel-way/internal/infra/statsd"
+)
+
+// validateStatsD checks that the address of the StatsD server is in the format "host:port".
+//
+// Parameters:
+//   - cfg: The configuration of the emission of the metrics.
+//
+// Returns:
+//   - An error if the emission is enabled and the address has no port.
+func validateStatsD(cfg config.StatsDConfig) error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if _, _, err := net.SplitHostPort(cfg.Address); err != nil {
+		return fmt.Errorf("statsd: %w", err)
+	}
+
+	return nil
+}
+
+// statsdClient returns the Client of the StatsD server.
+// If the Builder instance already has a Client instance, it will be returned.
+//
+// Returns:
+//   - A pointer to a Client, or nil if the emission of the metrics is disabled.
+func (b *Builder) statsdClient() *statsd.Client {
+	cfg := b.config.StatsD
+
+	// Check if the Builder instance already has a Client instance.
+	if b.statsd != nil || !cfg.Enabled {
+		return b.statsd
+	}
+
+	b.statsd = statsd.NewClient(
+		cfg.Address,
+		statsd.WithPrefix(cfg.Prefix),
+		statsd.WithTags(cfg.Tags...),
+		statsd.WithDogStatsD(cfg.DogStatsD),
+		statsd.WithInterval(cfg.Interval),
+	)
+
+	return b.statsd
+}
+
+// StartStatsD emits the metrics to the StatsD server specified by the `StatsD`
+// field of the configuration in the background. The status transitions are
+// counted by the active replica only, so a cluster counts them once, and the
+// heartbeats and the deliveries are counted by the instance that handled
+// them. The last metrics are sent when the context is closed, before Wait returns.
+//
+// If the emission of the metrics is disabled in the configuration, the function does nothing.
+//
+// ctx - The context.Context used to stop the emission.
+func (b *Builder) StartStatsD(ctx context.Context) {
+	client := b.statsdClient()
+
+	// Do nothing if the emission of the metrics is disabled.
+	if client == nil {
+		return
+	}
+
+	logger := zerolog.Ctx(ctx)
+	replica := b.replicaService(ctx)
+	watcher := b.statusWatcher()
+
+	// The number of the transitions buffered before the client is resubscribed.
+	const buffer = 1024
+
+	go func() {
+		transitions, unsubscribe := watcher.Subscribe(buffer)
+		defer func() { unsubscribe() }()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case transition, ok := <-transitions:
+				if !ok {
+					logger.Warn().Msg("StatsD missed status transitions, resubscribing")
+
+					transitions, unsubscribe = watcher.Subscribe(buffer)
+
+					continue
+				}
+
+				if replica.Active() {
+					client.Transition(transition)
+				}
+			}
+		}
+	}()
+
+	logger.Info().
+		Str("address", b.config.StatsD.Address).
+		Bool("dogstatsd", b.config.StatsD.DogStatsD).
+		Msg("Emitting metrics to StatsD")
+
+	b.background.Add(1)
+
+	go func() {
+		defer b.background.Done()
+
+		client.Run(ctx)
+	}()
+}
@@ -0,0 +1,87 @@
+package build
+
+import (
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// nameLabel is the label of the webhooks naming the services in the metrics.
+const nameLabel = "name"
+
+// statusRegistry provides the current statuses of the services.
+type statusRegistry interface {
+	Status(id uuid.UUID) (entities.ServiceStatus, bool)
+}
+
+// serviceRegistry lists the configured services with their labels.
+type serviceRegistry interface {
+	All() []uuid.UUID
+	Labels(id uuid.UUID) map[string]string
+}
+
+// statusCollector is a prometheus.Collector of the statuses of the services.
+//
+// Every configured service has an up gauge, so Prometheus can alert on the
+// services considered down without querying the server. The services are
+// named by their "name" label, if they have one.
+type statusCollector struct {
+	statuses statusRegistry
+	services serviceRegistry
+	up       *prometheus.Desc
+	lastSeen *prometheus.Desc
+}
+
+// newStatusCollector creates a new instance of the statusCollector struct.
+//
+// Parameters:
+//   - statuses: The registry of the current statuses.
+//   - services: The registry of the configured services.
+//
+// Returns:
+//   - A pointer to the initialized statusCollector.
+func newStatusCollector(statuses statusRegistry, services serviceRegistry) *statusCollector {
+	return &statusCollector{
+		statuses: statuses,
+		services: services,
+		up: prometheus.NewDesc(
+			"vakeel_service_up",
+			"Whether the service is up: 1 if it is up or degraded, 0 if it is down or has no known status.",
+			[]string{"id", nameLabel}, nil,
+		),
+		lastSeen: prometheus.NewDesc(
+			"vakeel_service_last_seen_timestamp_seconds",
+			"Unix time of the last heartbeat of the service, if its status is known.",
+			[]string{"id", nameLabel}, nil,
+		),
+	}
+}
+
+// Describe sends the descriptors of the metrics.
+func (c *statusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.up
+	ch <- c.lastSeen
+}
+
+// Collect sends the status of every configured service.
+func (c *statusCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, id := range c.services.All() {
+		labels := []string{id.String(), c.services.Labels(id)[nameLabel]}
+
+		current, ok := c.statuses.Status(id)
+
+		up := 0.0
+		if ok && current.Status != entities.Down {
+			up = 1
+		}
+
+		ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, up, labels...)
+
+		if ok && !current.LastSeen.IsZero() {
+			ch <- prometheus.MustNewConstMetric(
+				c.lastSeen, prometheus.GaugeValue, float64(current.LastSeen.UnixMilli())/1e3, labels...,
+			)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package build
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/app"
+)
+
+// RunStatusPage starts the public status page on the address specified by the
+// `StatusPage` field of the configuration. The page is served as HTML on / and
+// as JSON on /status.json. The function blocks until the context is closed or
+// an error occurs.
+//
+// The status page is served on its own listener, so it can be exposed without
+// the metrics and the badges of the HTTP server. If it is disabled in the
+// configuration, the function returns immediately.
+//
+// ctx - The context.Context used to stop the server.
+// Returns an error if the server cannot listen on the configured address.
+func (b *Builder) RunStatusPage(ctx context.Context) error {
+	cfg := b.config.StatusPage
+
+	// Do nothing if the status page is disabled.
+	if !cfg.Enabled {
+		return nil
+	}
+
+	// Get the logger from the context.
+	logger := zerolog.Ctx(ctx)
+
+	handler := app.NewStatusPageHandler(
+		cfg.Title,
+		cfg.Entities(),
+		cfg.Days,
+		cfg.Cache,
+		b.WebhookRepository(),
+		b.stateManagerService(ctx),
+		b.history,
+	)
+
+	// Register the HTTP handlers. The page is read-only.
+	mux := http.NewServeMux()
+	mux.Handle("GET /{$}", handler)
+	mux.Handle("GET /status.json", handler)
+
+	// The time allowed to read the request headers.
+	const readHeaderTimeout = 10 * time.Second
+
+	//nolint:exhaustruct
+	server := &http.Server{
+		Addr:              cfg.Addr(),
+		Handler:           mux,
+		ReadHeaderTimeout: readHeaderTimeout,
+		BaseContext:       func(_ net.Listener) context.Context { return ctx },
+	}
+
+	// Shut the server down when the context is closed.
+	go func() {
+		<-ctx.Done()
+
+		//nolint:contextcheck
+		_ = server.Shutdown(context.Background())
+	}()
+
+	// Log the address of the server.
+	logger.Info().Str("addr", cfg.Addr()).Msg("Starting status page")
+
+	// Start serving requests.
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
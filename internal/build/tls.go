@@ -0,0 +1,193 @@
+package build
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"golang.org/x/crypto/acme/autocert"
+
+	"github.com/bavix/vakeel-way/internal/config"
+	"github.com/bavix/vakeel-way/internal/infra/certs"
+)
+
+var (
+	// ErrIncompleteTLS is an error that indicates that a listener is configured with a certificate without a key or vice versa.
+	ErrIncompleteTLS = errors.New("tls: both cert_file and key_file are required")
+
+	// ErrNoACMEDomains is an error that indicates that the automatic certificates are enabled without domains or a cache directory.
+	ErrNoACMEDomains = errors.New("acme: the domains and the cache_dir are required")
+)
+
+// validateTLS validates the configuration of the TLS of a listener.
+//
+// Parameters:
+//   - cfg: The configuration of the TLS.
+//
+// Returns:
+//   - ErrIncompleteTLS if only one of the files is set.
+//   - An error if the key pair cannot be loaded.
+func validateTLS(cfg config.TLSConfig) error {
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	if cfg.CertFile == "" || cfg.KeyFile == "" {
+		return ErrIncompleteTLS
+	}
+
+	_, err := certs.NewReloader(cfg.CertFile, cfg.KeyFile)
+
+	return err
+}
+
+// validateACME validates the configuration of the automatic certificates.
+//
+// Parameters:
+//   - cfg: The configuration of the automatic certificates.
+//
+// Returns:
+//   - ErrNoACMEDomains if no domain or no cache directory is set.
+func validateACME(cfg config.ACMEConfig) error {
+	if cfg.Enabled && (len(cfg.Domains) == 0 || cfg.CacheDir == "") {
+		return ErrNoACMEDomains
+	}
+
+	return nil
+}
+
+// acmeManager returns the manager of the automatic certificates, or nil if they are disabled.
+// If the Builder instance already has an autocert.Manager instance, it will be returned.
+//
+// Returns:
+//   - A pointer to an autocert.Manager, or nil.
+func (b *Builder) acmeManager() *autocert.Manager {
+	cfg := b.config.ACME
+
+	// Check if the automatic certificates are enabled and the Builder instance already has a Manager instance.
+	if !cfg.Enabled || b.acme != nil {
+		return b.acme
+	}
+
+	b.acme = certs.NewACMEManager(cfg.Domains, cfg.CacheDir, cfg.Email, cfg.DirectoryURL)
+
+	return b.acme
+}
+
+// serverTLS returns the TLS configuration of a listener, reloading its
+// certificate until the context is closed.
+//
+// A listener without its own certificate files is served with the automatic
+// certificates if they are enabled.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the reloads, holding the logger.
+//   - cfg: The configuration of the TLS.
+//
+// Returns:
+//   - A pointer to a tls.Config, or nil if the listener is served without TLS.
+//   - An error if the key pair cannot be loaded.
+func (b *Builder) serverTLS(ctx context.Context, cfg config.TLSConfig) (*tls.Config, error) {
+	if !cfg.Enabled() {
+		manager := b.acmeManager()
+		if manager == nil {
+			return nil, nil //nolint:nilnil
+		}
+
+		tlsConfig := manager.TLSConfig()
+		tlsConfig.MinVersion = tls.VersionTLS12
+
+		return tlsConfig, nil
+	}
+
+	reloader, err := certs.NewReloader(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	go reloader.Watch(ctx)
+
+	return reloader.TLSConfig(), nil
+}
+
+// clientCredentials returns the transport credentials of a connection to a server.
+//
+// Parameters:
+//   - cfg: The configuration of the TLS of the connection.
+//
+// Returns:
+//   - The TLS credentials if the TLS is enabled, the insecure credentials otherwise.
+//   - An error if the CA certificates cannot be loaded.
+func clientCredentials(cfg config.ClientTLSConfig) (credentials.TransportCredentials, error) {
+	if !cfg.Enabled {
+		return insecure.NewCredentials(), nil
+	}
+
+	tlsConfig, err := certs.ClientTLS(cfg.CAFile, cfg.ServerName)
+	if err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(tlsConfig), nil
+}
+
+// RunACMEChallenge starts the listener answering the HTTP-01 challenges of the
+// ACME authority on the address specified by the `ACME.ChallengeAddr` field of
+// the configuration. The other requests are redirected to HTTPS. The function
+// blocks until the context is closed or an error occurs.
+//
+// If the automatic certificates are disabled or no address is set, the
+// function returns immediately.
+//
+// ctx - The context.Context used to stop the server.
+// Returns an error if the server cannot listen on the configured address.
+func (b *Builder) RunACMEChallenge(ctx context.Context) error {
+	manager := b.acmeManager()
+
+	// Do nothing if the automatic certificates are disabled or the HTTP-01 challenges are not answered.
+	if manager == nil || b.config.ACME.ChallengeAddr == "" {
+		return nil
+	}
+
+	// Get the logger from the context.
+	logger := zerolog.Ctx(ctx)
+
+	// The time allowed to read the request headers.
+	const readHeaderTimeout = 10 * time.Second
+
+	//nolint:exhaustruct
+	server := &http.Server{
+		Addr:              b.config.ACME.ChallengeAddr,
+		Handler:           manager.HTTPHandler(nil),
+		ReadHeaderTimeout: readHeaderTimeout,
+		BaseContext:       func(_ net.Listener) context.Context { return ctx },
+	}
+
+	// Shut the server down when the context is closed.
+	go func() {
+		<-ctx.Done()
+
+		//nolint:contextcheck
+		_ = server.Shutdown(context.Background())
+	}()
+
+	// Log the address of the server.
+	logger.Info().
+		Str("addr", b.config.ACME.ChallengeAddr).
+		Strs("domains", b.config.ACME.Domains).
+		Msg("Starting ACME challenge listener")
+
+	// Start serving requests.
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
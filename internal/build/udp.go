@@ -0,0 +1,124 @@
+package build
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/config"
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/secrets"
+	"github.com/bavix/vakeel-way/internal/infra/udp"
+)
+
+// ErrUDPUnauthenticated is an error that indicates that the UDP listener is
+// enabled with the authentication, but without a secret or an allowlist.
+var ErrUDPUnauthenticated = errors.New("udp: the secret or the allowlist is required when the authentication is enabled")
+
+// validateUDP makes sure the UDP listener does not bypass the authentication.
+//
+// The datagrams carry no API token, so anyone reaching the port could report
+// the heartbeats of any service. If the authentication is enabled, the
+// datagrams must be signed with the secret, or the senders restricted to the
+// networks of the allowlist.
+//
+// Parameters:
+//   - cfg: The configuration of the UDP listener.
+//   - auth: The configuration of the authentication.
+//   - keyring: The keyring opening the secret.
+//
+// Returns:
+//   - ErrUDPUnauthenticated if the listener is not protected, or an error if the secret cannot be opened.
+func validateUDP(cfg config.UDPConfig, auth config.AuthConfig, keyring *secrets.Keyring) error {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	if cfg.Secret != "" {
+		_, err := keyring.Open(cfg.Secret)
+
+		return err
+	}
+
+	if (len(auth.Tokens) > 0 || auth.JWT.Enabled) && len(auth.Allowlist) == 0 {
+		return ErrUDPUnauthenticated
+	}
+
+	return nil
+}
+
+// RunUDP receives the heartbeats sent as UDP datagrams to the address
+// specified by the `UDP` field of the configuration, and feeds them into the
+// checker. The function blocks until the context is closed.
+//
+// The datagrams are accepted from the networks of the allowlist only, as they
+// carry no API token. If the secret is set, only the datagrams signed with it
+// are accepted.
+//
+// If the UDP listener is disabled in the configuration, the function returns
+// immediately.
+//
+// ctx - The context.Context used to stop the listener.
+// Returns an error if the port cannot be listened on.
+func (b *Builder) RunUDP(ctx context.Context) error {
+	cfg := b.config.UDP
+
+	// Do nothing if the UDP listener is disabled or the server is shutting down.
+	if !cfg.Enabled || !b.ingress.enter() {
+		return nil
+	}
+	defer b.ingress.leave()
+
+	// Get the logger from the context.
+	logger := zerolog.Ctx(ctx)
+
+	// The networks are validated in NewBuilder.
+	allowlist, err := b.networkAllowlist()
+	if err != nil {
+		return err
+	}
+
+	options := []udp.Option{udp.WithAllow(allowlist.Allows)}
+
+	// The secret is validated in NewBuilder.
+	if cfg.Secret != "" {
+		secret, err := b.Keyring().Open(cfg.Secret)
+		if err != nil {
+			return err
+		}
+
+		options = append(options, udp.WithSecret([]byte(secret), cfg.Window))
+	}
+
+	conn, err := net.ListenPacket("udp", cfg.Addr())
+	if err != nil {
+		return err
+	}
+
+	// Enlarge the receive buffer, so the bursts of heartbeats are not dropped.
+	if udpConn, ok := conn.(*net.UDPConn); ok && cfg.ReadBuffer > 0 {
+		if err := udpConn.SetReadBuffer(cfg.ReadBuffer); err != nil {
+			logger.Warn().Err(err).Int("size", cfg.ReadBuffer).Msg("Failed to set the UDP read buffer")
+		}
+	}
+
+	checker := b.checkerUsecase(ctx)
+	repo := b.WebhookRepository()
+
+	logger.Info().Str("addr", cfg.Addr()).Msg("Starting UDP listener")
+
+	return udp.NewListener(conn, options...).Run(ctx, func(id uuid.UUID) {
+		switch {
+		case !repo.Exists(id):
+			// The heartbeats of the unknown services are not recorded.
+			logger.Debug().Str("id", id.String()).Msg("UDP heartbeat of an unknown service")
+		case !checker.Beat(entities.Heartbeat{ID: id, Status: entities.Up, Message: "", TTL: 0, RequestID: "", Sent: time.Time{}}):
+			// The heartbeats are loss-tolerant, so the dropped ones are not worth a warning.
+			logger.Debug().Str("id", id.String()).Msg("UDP heartbeat dropped")
+		}
+	})
+}
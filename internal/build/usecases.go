@@ -5,6 +5,7 @@ import (
 
 	"github.com/rs/zerolog"
 
+	"github.com/bavix/vakeel-way/internal/domain/entities"
 	"github.com/bavix/vakeel-way/internal/domain/services"
 	"github.com/bavix/vakeel-way/internal/domain/usecases"
 )
@@ -24,29 +25,101 @@ func (b *Builder) checkerUsecase(ctx context.Context) *usecases.Checker {
 		return b.checker
 	}
 
-	// Create a new StateManager instance.
-	// The StateManager instance is responsible for sending status updates to the state service.
-	// It takes a context.Context used to cancel the operation if needed,
-	// a WebhookRepository instance used to retrieve webhooks by their UUIDs,
-	// and an InStatusClient instance that is used to send status updates to the state service.
-	stateManager := services.NewStateManager(
-		b.inStatusClient(),    // The InStatusClient instance used to send status updates.
-		b.WebhookRepository(), // The WebhookRepository instance used to retrieve webhooks.
-		zerolog.Ctx(ctx),      // The logger used to log any errors or information.
-	)
+	// Get the StateManager instance.
+	stateManager := b.stateManagerService(ctx)
+
+	// Start a goroutine to notify the targets once the silences end.
+	go stateManager.CatchUp(ctx)
+
+	// Start a goroutine to deliver the queued notifications once the targets recover.
+	go stateManager.Redeliver(ctx)
+
+	// Start a goroutine to retry the deliveries queued in the outbox.
+	// The queue file is opened in NewBuilder, so the error can be ignored here.
+	if box, _ := b.deliveryOutbox(); box != nil {
+		go box.Run(ctx)
+	}
+
+	// Start a goroutine to notify the deferred targets of the escalation policies.
+	go stateManager.Escalate(ctx)
+
+	// Start a goroutine to deliver the notifications held back by the throttling.
+	go stateManager.Throttle(ctx)
+
+	// Start a goroutine to warn about the late heartbeats.
+	go stateManager.Forewarn(ctx)
+
+	// Start a goroutine to downsample the status history.
+	b.background.Add(1)
+
+	go func() {
+		defer b.background.Done()
+
+		b.runHistory(ctx)
+	}()
+
+	// Start a goroutine to report the stale webhooks.
+	if policy := b.stalePolicy(ctx); policy != nil && b.config.Stale.Interval > 0 {
+		go policy.Run(ctx, b.config.Stale.Interval)
+	}
 
 	// Create a new Checker instance using the StateManager instance.
 	// The Checker instance is responsible for sending status updates to the state service.
 	// It takes a StateManager instance as a parameter.
-	b.checker = usecases.NewChecker(stateManager)
+	// The heartbeats are buffered according to the available CPUs, coalesced
+	// within the window and processed by the configured number of the workers.
+	// The overflow policy is validated by NewBuilder.
+	overflow, _ := entities.ParseOverflow(b.config.Checker.Overflow)
+
+	options := []usecases.CheckerOption{
+		usecases.WithBufferSize(b.resourceSizing().EventBuffer),
+		usecases.WithWorkers(b.config.Checker.Workers),
+		usecases.WithCoalescing(b.config.Checker.Window),
+		usecases.WithOverflow(overflow),
+	}
+
+	// Share the heartbeats with the other instances of the cluster.
+	if b.cluster != nil {
+		options = append(options, usecases.WithBroadcaster(b.cluster))
+	}
+
+	// Export the heartbeats along with the status transitions.
+	if b.exporter != nil && b.config.KafkaRESTProxy.Heartbeats {
+		options = append(options, usecases.WithBroadcaster(b.exporter))
+	}
+
+	// Count the heartbeats received by the instance.
+	if client := b.statsdClient(); client != nil {
+		options = append(options, usecases.WithBroadcaster(client))
+	}
+
+	b.checker = usecases.NewChecker(stateManager, options...)
+
+	// Report the state of the buffer of the heartbeats.
+	b.registerCheckerMetrics(b.checker)
 
 	// Start a goroutine to close the Checker instance when the context is canceled.
 	// This ensures that the Checker goroutine is stopped when the context is canceled.
+	// The process waits for the statuses to be persisted before it exits.
+	b.background.Add(1)
+
 	go func() {
+		defer b.background.Done()
+
 		// Wait for the context to be canceled.
 		<-ctx.Done()
+		// Stop buffering the heartbeats, as the Handler no longer receives them,
+		// and wait for their sources to stop, so none is sent to the closed Checker.
+		b.checker.Stop()
+		b.ingress.wait()
 		// Close the Checker instance to stop the goroutine.
 		b.checker.Close()
+		// Stop the expiration of the statuses and persist them.
+		stateManager.Close()
+		// Close the queue of the deliveries.
+		b.closeOutbox(ctx)
+		// Persist the open incidents.
+		b.incidentStore().Close()
 	}()
 
 	// Start a goroutine to process events from the Checker's Events channel.
@@ -57,3 +130,53 @@ func (b *Builder) checkerUsecase(ctx context.Context) *usecases.Checker {
 	// Return the Checker instance.
 	return b.checker
 }
+
+// stateManagerService returns the StateManager instance.
+// If the Builder instance already has a StateManager instance, it will be returned.
+// Otherwise, a new StateManager instance will be created and stored in the Builder instance.
+//
+// Parameters:
+//   - ctx: The context.Context holding the logger.
+//
+// Returns:
+//   - A pointer to a StateManager service.
+func (b *Builder) stateManagerService(ctx context.Context) *services.StateManager {
+	// Check if the Builder instance already has a StateManager instance.
+	if b.stateManager != nil {
+		return b.stateManager
+	}
+
+	// The policy is validated when the Builder is created.
+	cascade, _ := entities.ParseCascade(b.config.Notifications.Cascade)
+
+	// Create a new StateManager instance.
+	// The StateManager instance is responsible for sending status updates to the state service.
+	// It takes a context.Context used to cancel the operation if needed,
+	// a WebhookRepository instance used to retrieve webhooks by their UUIDs,
+	// and a notifier that is used to send status updates to the webhook targets.
+	b.stateManager = services.NewStateManager(
+		b.deliverySender(),                                                  // The notifier used to send status updates.
+		b.WebhookRepository(),                                               // The WebhookRepository instance used to retrieve webhooks.
+		zerolog.Ctx(ctx),                                                    // The logger used to log any errors or information.
+		services.WithReplica(b.replicaService(ctx)),                         // The Replica gating the notifications.
+		services.WithSilencer(b.silencer),                                   // The Silencer holding the maintenance windows.
+		services.WithBacklog(b.spool),                                       // The queue of the unavailable targets.
+		services.WithDeadLetters(b.deadLetters),                             // The events rejected by the targets.
+		services.WithThrottle(b.config.Notifications.Throttle),              // The default throttle window.
+		services.WithHistory(b.history),                                     // The history of the status transitions.
+		services.WithWatcher(b.statusWatcher()),                             // The subscribers of the status transitions.
+		services.WithAgents(b.agentDirectory()),                             // The agents reporting the services.
+		services.WithTolerances(b.WebhookRepository()),                      // The timing tolerances of the services.
+		services.WithSnapshot(b.config.State.File, b.config.State.Interval), // The snapshot of the states.
+		services.WithReporter(b.reporter),                                   // The reporter of the failed deliveries.
+		services.WithDependencies(b.WebhookRepository(), cascade),           // The dependencies of the services.
+		services.WithGroups(b.serviceGroups()),                              // The service groups.
+		services.WithIntervals(b.heartbeatIntervals()),                      // The intervals between the heartbeats.
+		services.WithCadence(b.cadenceDetector()),                           // The detector of the late heartbeats.
+	)
+
+	// Report the statuses of the services.
+	b.metricsRegistry().MustRegister(newStatusCollector(b.stateManager, b.WebhookRepository()))
+
+	return b.stateManager
+}
@@ -0,0 +1,54 @@
+package build
+
+import "github.com/bavix/vakeel-way/internal/domain/services"
+
+// statusWatcher returns the Watcher of the status transitions.
+// If the Builder instance already has a Watcher instance, it will be returned.
+//
+// The status transitions are published by the StateManager and streamed to
+// the subscribers of the WatchStatus RPC.
+//
+// Returns:
+//   - A pointer to a Watcher service.
+func (b *Builder) statusWatcher() *services.Watcher {
+	// Check if the Builder instance already has a Watcher instance.
+	if b.watcher != nil {
+		return b.watcher
+	}
+
+	b.watcher = services.NewWatcher()
+
+	return b.watcher
+}
+
+// noticeAnnouncer returns the Announcer of the notices sent to the agents.
+// If the Builder instance already has an Announcer instance, it will be returned.
+//
+// Returns:
+//   - A pointer to an Announcer service.
+func (b *Builder) noticeAnnouncer() *services.Announcer {
+	// Check if the Builder instance already has an Announcer instance.
+	if b.announcer != nil {
+		return b.announcer
+	}
+
+	b.announcer = services.NewAnnouncer()
+
+	return b.announcer
+}
+
+// agentDirectory returns the AgentDirectory of the agents reporting the services.
+// If the Builder instance already has an AgentDirectory instance, it will be returned.
+//
+// Returns:
+//   - A pointer to an AgentDirectory service.
+func (b *Builder) agentDirectory() *services.AgentDirectory {
+	// Check if the Builder instance already has an AgentDirectory instance.
+	if b.agents != nil {
+		return b.agents
+	}
+
+	b.agents = services.NewAgentDirectory()
+
+	return b.agents
+}
@@ -0,0 +1,37 @@
+package config
+
+// ACMEConfig represents the configuration of the automatic certificates.
+//
+// The certificates of the domains are obtained from an ACME certificate
+// authority, Let's Encrypt by default, on the first TLS handshake, stored in
+// the cache directory and renewed before they expire. They are served by the
+// gRPC server and the admin REST API unless these listeners have their own
+// certificate files. The clients, i.e. the CLI with --tls and the relay with
+// the TLS of the upstream enabled, verify them with the system roots, so no CA
+// file is needed.
+type ACMEConfig struct {
+	// Enabled defines whether the certificates are obtained automatically.
+	Enabled bool `yaml:"enabled"`
+
+	// Domains are the domains the certificates are obtained for. The
+	// handshakes for the other server names are rejected.
+	Domains []string `yaml:"domains"`
+
+	// CacheDir is the directory the certificates and the account key are stored in.
+	// It should be persistent, so the rate limits of the authority are not hit on restarts.
+	CacheDir string `yaml:"cache_dir"`
+
+	// Email is the contact email of the account, notified about the problems
+	// of the certificates. It is optional.
+	Email string `yaml:"email"`
+
+	// DirectoryURL is the directory of the ACME authority, or empty for the
+	// production directory of Let's Encrypt.
+	DirectoryURL string `yaml:"directory_url"`
+
+	// ChallengeAddr is the address of the listener answering the HTTP-01
+	// challenges and redirecting the other requests to HTTPS, e.g. ":80". If
+	// it is empty, only the TLS-ALPN-01 challenges are answered, which
+	// requires a TLS listener on the port 443.
+	ChallengeAddr string `yaml:"challenge_addr"`
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"net"
+	"time"
+)
+
+// AdminHTTPConfig represents the configuration of the admin REST API.
+//
+// The admin REST API mirrors the admin gRPC service on its own HTTP listener,
+// so the instance can be operated with curl and simple automation.
+type AdminHTTPConfig struct {
+	// Enabled defines whether the admin REST API is served.
+	Enabled bool `yaml:"enabled"`
+
+	// Host is the host address to use for the admin REST API.
+	Host string `yaml:"host"`
+
+	// Port is the port number to use for the admin REST API.
+	Port string `yaml:"port"`
+
+	// Token is the bearer token required by every request. It is required when
+	// the admin REST API is enabled without the JWTs or the OIDC login, and may
+	// be stored encrypted.
+	Token string `yaml:"token"`
+
+	// TLS is the configuration of the TLS of the admin REST API. If no
+	// certificate is set, the API is served over plain HTTP unless the
+	// automatic certificates are enabled.
+	TLS TLSConfig `yaml:"tls"`
+
+	// OIDC is the configuration of the login of the team with an OpenID Connect provider.
+	OIDC OIDCConfig `yaml:"oidc"`
+}
+
+// OIDCConfig represents the configuration of the OIDC login to the admin REST API.
+//
+// The members of the team sign in with the provider in the browser, and the
+// session cookie authenticates their requests instead of the bearer token.
+// Only the users whose verified email or its domain is listed may sign in.
+type OIDCConfig struct {
+	// Enabled defines whether the users may sign in with the provider.
+	Enabled bool `yaml:"enabled"`
+
+	// Issuer is the issuer of the provider, e.g. https://accounts.google.com.
+	// Its configuration is discovered from /.well-known/openid-configuration.
+	Issuer string `yaml:"issuer"`
+
+	// ClientID is the client ID registered at the provider.
+	ClientID string `yaml:"client_id"`
+
+	// ClientSecret is the client secret registered at the provider, or empty
+	// for a public client. It may be stored encrypted, and signs the sessions.
+	ClientSecret string `yaml:"client_secret"`
+
+	// RedirectURL is the URL of the callback registered at the provider,
+	// e.g. https://way.example.com/auth/callback.
+	RedirectURL string `yaml:"redirect_url"`
+
+	// Scopes are the scopes requested from the provider.
+	Scopes []string `yaml:"scopes"`
+
+	// Emails are the emails of the users allowed to sign in.
+	Emails []string `yaml:"emails"`
+
+	// Domains are the domains of the emails of the users allowed to sign in.
+	Domains []string `yaml:"domains"`
+
+	// SessionTTL is the lifetime of the sessions.
+	SessionTTL time.Duration `yaml:"session_ttl"`
+}
+
+// Addr returns the address of the admin REST API in the format "host:port".
+func (c AdminHTTPConfig) Addr() string {
+	return net.JoinHostPort(c.Host, c.Port)
+}
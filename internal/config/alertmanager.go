@@ -0,0 +1,64 @@
+package config
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AlertmanagerConfig represents the configuration of the receiver of the
+// Alertmanager webhooks.
+//
+// The alerts detected by Prometheus are mapped to the services and flow
+// through the same notification pipeline as the heartbeats: a firing alert
+// reports the service down, a resolved alert reports it up.
+type AlertmanagerConfig struct {
+	// Enabled specifies whether the Alertmanager webhooks are received.
+	//
+	// The receiver is served by the HTTP server on POST /api/v1/alertmanager.
+	Enabled bool `yaml:"enabled"`
+
+	// Token is the bearer token Alertmanager has to send, e.g. with the
+	// authorization of the http_config of the webhook_config.
+	//
+	// If it is empty, the webhooks are not authenticated. It can be stored encrypted.
+	Token string `yaml:"token"`
+
+	// Label is the label of the alerts carrying the UUID of the service.
+	//
+	// The alerts with the label are mapped to the service directly, before the rules.
+	//
+	// Example: "vakeel_way_id"
+	Label string `yaml:"label"`
+
+	// TTL is the time the status reported by a resolved alert is kept without
+	// a heartbeat. It is capped at a day.
+	//
+	// Alertmanager sends a resolved alert once, so the services reported by
+	// Alertmanager only need a TTL as long as possible, or heartbeats.
+	//
+	// Example: "24h"
+	TTL time.Duration `yaml:"ttl"`
+
+	// Rules map the alerts without the label to the services, first match wins.
+	Rules []AlertRuleConfig `yaml:"rules"`
+}
+
+// AlertRuleConfig represents a rule mapping the alerts to a service.
+type AlertRuleConfig struct {
+	// Alertname is the name of the alerts matched by the rule.
+	//
+	// If it is empty, the alerts of any name are matched.
+	Alertname string `yaml:"alertname"`
+
+	// Labels are the labels the alerts matched by the rule must have.
+	//
+	// Example: {"job": "api", "env": "production"}
+	Labels map[string]string `yaml:"labels"`
+
+	// ID is the UUID of the service reported by the matched alerts.
+	ID uuid.UUID `yaml:"id"`
+
+	// Status is the status reported by the firing alerts: "down" (default) or "degraded".
+	Status string `yaml:"status"`
+}
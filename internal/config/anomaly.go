@@ -0,0 +1,24 @@
+package config
+
+// AnomalyConfig represents the configuration of the detection of the anomalies
+// of the cadence of the heartbeats.
+type AnomalyConfig struct {
+	// Enabled is a flag that indicates whether the anomalies are detected.
+	Enabled bool `yaml:"enabled"`
+
+	// Sensitivity is the number of the standard deviations above the usual
+	// interval a heartbeat may be late by before a warning is sent.
+	//
+	// The lower it is, the earlier and the more frequent the warnings are.
+	// If it is zero, 4 is used.
+	Sensitivity float64 `yaml:"sensitivity"`
+
+	// Ratio is the minimum ratio of the delay to the usual interval before a
+	// warning is sent, so the services with a very regular cadence are not
+	// warned about every small delay. If it is zero, 2 is used.
+	Ratio float64 `yaml:"ratio"`
+
+	// MinSamples is the number of the intervals learned before the warnings
+	// of the service are sent. If it is zero, 20 is used.
+	MinSamples int `yaml:"min_samples"`
+}
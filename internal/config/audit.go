@@ -0,0 +1,20 @@
+package config
+
+// AuditConfig represents the configuration of the audit log of the heartbeats.
+//
+// The audit log records every received heartbeat with the address of the
+// agent, so "did the agent actually report?" can be answered with
+// `vakeel-way audit` instead of packet captures.
+type AuditConfig struct {
+	// Size is the number of the latest heartbeats kept in memory.
+	//
+	// If it is zero and no file is set, the audit log is disabled.
+	Size int `yaml:"size"`
+
+	// File is the path to the file every heartbeat is appended to as a JSON line.
+	//
+	// If the path is empty, the heartbeats are kept in memory only.
+	//
+	// Example: "/var/log/vakeel-way/heartbeats.jsonl"
+	File string `yaml:"file"`
+}
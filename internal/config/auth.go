@@ -0,0 +1,120 @@
+package config
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuthConfig represents the configuration of the authentication.
+//
+// If no tokens are configured and the JWTs are not accepted, the
+// authentication is disabled and every client is accepted anonymously.
+type AuthConfig struct {
+	// Tokens is the list of the API tokens accepted by the gRPC server.
+	Tokens []TokenConfig `yaml:"tokens"`
+
+	// JWT is the configuration of the signed tokens accepted alongside the API tokens.
+	JWT JWTConfig `yaml:"jwt"`
+
+	// Allowlist is the list of the networks the clients may connect from, in
+	// the CIDR notation or as single IP addresses. It is enforced on the gRPC
+	// server, the HTTP server and the admin REST API, except for the liveness
+	// and readiness probes. If it is empty, every network is allowed.
+	Allowlist []string `yaml:"allowlist"`
+}
+
+// JWTConfig represents the configuration of the JSON Web Tokens.
+//
+// The JWTs are accepted by the gRPC server, the admin REST API and the
+// Alertmanager webhooks. The tenant claim scopes the caller to a namespace,
+// and the scope claim grants the caller the "report", "read" and "admin"
+// scopes: reporting heartbeats, querying the statuses and calling the
+// administrative API. The tokens are signed either with the shared secret or
+// with the keys published at the JWKS URL.
+type JWTConfig struct {
+	// Enabled defines whether the JWTs are accepted.
+	Enabled bool `yaml:"enabled"`
+
+	// Secret is the shared secret of the tokens signed with HS256, HS384 or HS512.
+	//
+	// It can be stored encrypted.
+	Secret string `yaml:"secret"`
+
+	// JWKSURL is the URL of the JSON Web Key Set of the tokens signed with
+	// RSA or ECDSA keys.
+	//
+	// Example: "https://idp.example.com/.well-known/jwks.json"
+	JWKSURL string `yaml:"jwks_url"`
+
+	// Issuer is the required iss claim of the tokens, or empty.
+	Issuer string `yaml:"issuer"`
+
+	// Audience is the audience the aud claim of the tokens must contain, or empty.
+	Audience string `yaml:"audience"`
+
+	// TenantClaim is the claim carrying the namespace the caller is scoped to.
+	TenantClaim string `yaml:"tenant_claim"`
+
+	// ScopeClaim is the claim carrying the scopes of the caller, a string of
+	// space-separated scopes or a list of strings.
+	ScopeClaim string `yaml:"scope_claim"`
+
+	// Leeway is the tolerated clock skew when the expiration of the tokens is checked.
+	Leeway time.Duration `yaml:"leeway"`
+}
+
+// TokenConfig represents the configuration of a single API token.
+type TokenConfig struct {
+	// Name is the unique name of the token, used in the logs, the metrics and
+	// the quotas. It is required.
+	Name string `yaml:"name"`
+
+	// Token is the value the clients send in the "authorization" metadata.
+	//
+	// It can be stored encrypted.
+	Token string `yaml:"token"`
+
+	// Namespace is the namespace the token is scoped to.
+	//
+	// The token may report, query and watch the webhooks of its namespace
+	// only. If it is empty, the token is not scoped and sees every webhook.
+	Namespace string `yaml:"namespace"`
+
+	// Quota is the quota of the token.
+	Quota QuotaConfig `yaml:"quota"`
+
+	// Allow restricts the services the token may report heartbeats for.
+	//
+	// The heartbeats of the other services are rejected with the
+	// PermissionDenied code. If it is empty, the token may report every
+	// service it sees.
+	Allow AllowConfig `yaml:"allow"`
+
+	// Scopes are the scopes granted to the token: "report", "read" and
+	// "admin". If it is empty, the token may report the heartbeats and query
+	// the statuses, but not call the AdminService.
+	Scopes []string `yaml:"scopes"`
+}
+
+// AllowConfig represents the services a single API token may report.
+//
+// A service is allowed if its UUID is listed or it belongs to a listed namespace.
+type AllowConfig struct {
+	// IDs is the list of the UUIDs of the services the token may report.
+	IDs []uuid.UUID `yaml:"ids"`
+
+	// Namespaces is the list of the namespaces whose services the token may report.
+	Namespaces []string `yaml:"namespaces"`
+}
+
+// QuotaConfig represents the limits of a single API token.
+//
+// A zero value means that the limit is not enforced.
+type QuotaConfig struct {
+	// MaxIDs is the maximum number of distinct UUIDs the token may report.
+	MaxIDs int `yaml:"max_ids"`
+
+	// HeartbeatsPerMinute is the maximum number of heartbeats the token may send per minute.
+	HeartbeatsPerMinute int `yaml:"heartbeats_per_minute"`
+}
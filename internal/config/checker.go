@@ -0,0 +1,45 @@
+package config
+
+import "time"
+
+// CheckerConfig represents the configuration of the processing of the heartbeats.
+//
+// The heartbeats are received by the gRPC server and processed by the checker,
+// which updates the statuses of the services and notifies their targets.
+type CheckerConfig struct {
+	// Workers is the number of the heartbeats processed concurrently.
+	//
+	// The heartbeats of the same service are always processed by the same
+	// worker, in the order they were received. A slow delivery to the targets
+	// of a service therefore only delays the services sharing its worker.
+	// If it is 1, the heartbeats are processed one by one.
+	Workers int `yaml:"workers"`
+
+	// Window is the time the heartbeats are collected for before they are processed.
+	//
+	// The duplicate heartbeats of a service received within the window are
+	// processed once. If it is zero, every heartbeat is processed immediately.
+	//
+	// Example: "1s"
+	Window time.Duration `yaml:"window"`
+
+	// Overflow is the policy applied when the buffer of the heartbeats is full.
+	//
+	// The possible values are:
+	// - "block" to wait until the buffer has room, which slows down the agents
+	// - "drop-oldest" to drop the oldest buffered heartbeat
+	// - "drop-newest" to drop the received heartbeat
+	//
+	// The dropped heartbeats are counted by the vakeel_checker_dropped_total
+	// metric and reported in the log. If it is empty, "block" is used.
+	Overflow string `yaml:"overflow"`
+
+	// IntervalWindow is the number of the latest intervals between the
+	// heartbeats of a service the interval metrics are computed over.
+	//
+	// The vakeel_heartbeat_interval_seconds metric reports the shortest, the
+	// mean and the 99th percentile of the intervals of every service, next to
+	// its TTL in vakeel_heartbeat_ttl_seconds, so the services drifting toward
+	// their TTL can be alerted on before they go down. If it is zero, 128 is used.
+	IntervalWindow int `yaml:"interval_window"`
+}
@@ -0,0 +1,43 @@
+package config
+
+import "time"
+
+// ClusterConfig represents the configuration of the clustered mode.
+//
+// In the clustered mode, the instances share the heartbeats over Redis, so an
+// agent can be connected to any of them, and elect a leader: only the leader
+// dispatches the notifications, the other instances are standby.
+type ClusterConfig struct {
+	// Enabled specifies whether the instance joins the cluster.
+	//
+	// The replica mode is ignored in the clustered mode: the instance starts
+	// in standby mode and is promoted once it is elected.
+	Enabled bool `yaml:"enabled"`
+
+	// Address is the address of the Redis server.
+	//
+	// Example: "127.0.0.1:6379"
+	Address string `yaml:"address"`
+
+	// Password is the password of the Redis server. It can be stored encrypted.
+	Password string `yaml:"password"`
+
+	// DB is the number of the Redis database.
+	DB int `yaml:"db"`
+
+	// Prefix is the prefix of the Redis channel and key of the cluster.
+	//
+	// The instances sharing the prefix form a cluster, so several clusters can
+	// share a Redis server.
+	Prefix string `yaml:"prefix"`
+
+	// Node is the unique name of the instance in the cluster.
+	//
+	// If it is empty, a random name is generated on startup.
+	Node string `yaml:"node"`
+
+	// Lease is the time the leadership is held without a renewal.
+	//
+	// Once the leader is gone, another instance takes over within the lease.
+	Lease time.Duration `yaml:"lease"`
+}
@@ -3,39 +3,11 @@ package config
 import (
 	"net"
 	"os"
+	"time"
 
 	"github.com/goccy/go-yaml"
-	"github.com/google/uuid"
 )
 
-// Webhooks is a slice of WebhookConfig.
-type Webhooks []WebhookConfig
-
-// AsMap converts the slice of WebhookConfig into a map.
-//
-// The function takes the slice of WebhookConfig as input and returns a map
-// with the ID of the WebhookConfig as the key and the target URL as the value.
-// The map is created with preallocated capacity to avoid resizing during iteration.
-//
-// Returns:
-// - A map[uuid.UUID]string containing the converted data.
-func (w Webhooks) AsMap() map[uuid.UUID]string {
-	// Create a map with preallocated capacity for the length of the slice.
-	// This is done to avoid resizing the map during the iteration.
-	m := make(map[uuid.UUID]string, len(w))
-
-	// Iterate over each WebhookConfig in the slice.
-	// The range keyword is used to iterate over the slice and get the index and value.
-	for i := range w {
-		// Use the ID of the WebhookConfig as the key in the map,
-		// and the target of the WebhookConfig as the value.
-		m[w[i].ID] = w[i].Target
-	}
-
-	// Return the WebhooksMap containing the converted data.
-	return m
-}
-
 // Config represents the configuration of the application.
 //
 // It contains the configuration for the logger and the gRPC server.
@@ -54,30 +26,205 @@ type Config struct {
 	//
 	// The webhook configuration contains the unique identifier and the target URL of the webhook.
 	Webhooks Webhooks `yaml:"webhooks"`
-}
 
-// WebhookConfig represents the configuration for the webhook.
-//
-// It contains the unique identifier and the target URL of the webhook.
-type WebhookConfig struct {
-	// ID is the unique identifier for the webhook.
+	// Namespaces is the configuration of the namespaces.
+	//
+	// The webhooks of a namespace are visible to the API tokens scoped to it
+	// only. They are merged into the Webhooks on load.
+	Namespaces []NamespaceConfig `yaml:"namespaces"`
+
+	// Checker is the configuration of the processing of the heartbeats.
+	//
+	// The checker configuration defines how many heartbeats are processed concurrently.
+	Checker CheckerConfig `yaml:"checker"`
+
+	// Notifications is the configuration of the notifications.
+	//
+	// The notifications configuration contains the global message template.
+	Notifications NotificationsConfig `yaml:"notifications"`
+
+	// Delivery is the configuration of the delivery of the notifications.
+	//
+	// The delivery configuration defines where the notifications for the
+	// unavailable targets are queued.
+	Delivery DeliveryConfig `yaml:"delivery"`
+
+	// History is the configuration of the status history.
+	//
+	// The history configuration defines where the history is kept and how long
+	// each resolution of it is retained.
+	History HistoryConfig `yaml:"history"`
+
+	// State is the configuration of the persistence of the statuses of the services.
+	//
+	// The state configuration defines where the statuses are persisted between restarts.
+	State StateConfig `yaml:"state"`
+
+	// Stale is the configuration of the stale webhook policy.
+	//
+	// The stale webhook policy flags the webhooks that have not received a
+	// heartbeat for a long time.
+	Stale StaleConfig `yaml:"stale"`
+
+	// Silences is the list of the configured maintenance windows.
+	//
+	// The status transitions of the silenced services are recorded, but not notified.
+	Silences []SilenceConfig `yaml:"silences"`
+
+	// Groups is the list of the service groups.
+	//
+	// The status of a group is aggregated from its members, and one
+	// notification is sent per transition of the group.
+	Groups []GroupConfig `yaml:"groups"`
+
+	// Anomaly is the configuration of the detection of the anomalies of the
+	// cadence of the heartbeats.
+	//
+	// The detector learns the usual interval between the heartbeats of every
+	// service and warns the targets opting in to the warnings when the
+	// heartbeats are late, before the status of the service expires.
+	Anomaly AnomalyConfig `yaml:"anomaly"`
+
+	// Replica is the configuration of the replica mode.
+	//
+	// The replica configuration defines whether the instance dispatches notifications.
+	Replica ReplicaConfig `yaml:"replica"`
+
+	// NATS is the configuration of the NATS ingestion.
+	//
+	// The heartbeats published by the agents to NATS are fed into the checker.
+	NATS NATSConfig `yaml:"nats"`
+
+	// MQTT is the configuration of the MQTT listener.
+	//
+	// The heartbeats published by the devices to an MQTT broker are fed into the checker.
+	MQTT MQTTConfig `yaml:"mqtt"`
+
+	// UDP is the configuration of the UDP listener.
+	//
+	// The heartbeats sent as UDP datagrams are fed into the checker.
+	UDP UDPConfig `yaml:"udp"`
+
+	// Alertmanager is the configuration of the receiver of the Alertmanager webhooks.
+	//
+	// The alerts detected by Prometheus are mapped to the services and reported
+	// like the heartbeats.
+	Alertmanager AlertmanagerConfig `yaml:"alertmanager"`
+
+	// KafkaRESTProxy is the configuration of the export of the events to Kafka
+	// through the REST Proxy.
 	//
-	// The ID is a string that uniquely identifies the webhook.
-	// It is used to distinguish between different webhooks.
-	ID uuid.UUID `yaml:"id"`
+	// The status transitions and the heartbeats are produced to a Kafka topic.
+	KafkaRESTProxy KafkaRESTProxyConfig `yaml:"kafka_rest_proxy"`
 
-	// Target is the target URL of the webhook.
+	// Cluster is the configuration of the clustered mode.
 	//
-	// The target URL is the URL that will be notified when an event is triggered.
-	// It should be a valid URL that the webhook can reach.
+	// The instances of a cluster share the heartbeats and elect the one
+	// dispatching the notifications.
+	Cluster ClusterConfig `yaml:"cluster"`
+
+	// LeaderElection is the configuration of the leader election with a Kubernetes Lease.
+	//
+	// The replicas of a deployment elect the one dispatching the notifications.
+	LeaderElection LeaderElectionConfig `yaml:"leader_election"`
+
+	// Consul is the configuration of the registration in Consul.
+	//
+	// The gRPC endpoint is registered in the service discovery of Consul.
+	Consul ConsulConfig `yaml:"consul"`
+
+	// Operator is the configuration of the operator mode.
+	//
+	// The webhooks defined by the custom resources and the ConfigMaps of a
+	// Kubernetes namespace are synced along with the configured ones.
+	Operator OperatorConfig `yaml:"operator"`
+
+	// Secrets is the configuration of the encryption keys.
+	//
+	// The keys are used to encrypt sensitive values (tokens, webhook URLs with
+	// embedded credentials) at rest.
+	Secrets SecretsConfig `yaml:"secrets"`
+
+	// Auth is the configuration of the authentication.
+	//
+	// The authentication configuration contains the API tokens and their quotas.
+	Auth AuthConfig `yaml:"auth"`
+
+	// HTTP is the configuration of the HTTP server.
+	//
+	// The HTTP server exposes the metrics of the application.
+	HTTP HTTPConfig `yaml:"http"`
+
+	// StatsD is the configuration of the emission of the metrics to StatsD.
+	//
+	// The metrics are pushed to a StatsD server or a Datadog agent.
+	StatsD StatsDConfig `yaml:"statsd"`
+
+	// Pushgateway is the configuration of the push of the metrics to a Prometheus Pushgateway.
+	//
+	// The metrics are pushed on an interval instead of being scraped.
+	Pushgateway PushgatewayConfig `yaml:"pushgateway"`
+
+	// StatusPage is the configuration of the public status page.
+	//
+	// The status page shows the components of the services with their uptime history.
+	StatusPage StatusPageConfig `yaml:"status_page"`
+
+	// AdminHTTP is the configuration of the admin REST API.
+	//
+	// The admin REST API mirrors the admin gRPC service for curl and automation.
+	AdminHTTP AdminHTTPConfig `yaml:"admin_http"`
+
+	// ACME is the configuration of the automatic certificates.
+	//
+	// The certificates of the public listeners are obtained from Let's Encrypt and renewed automatically.
+	ACME ACMEConfig `yaml:"acme"`
+
+	// Sentry is the configuration of the error reporting.
+	//
+	// The failed deliveries, the panics and the configuration errors are reported to Sentry.
+	Sentry SentryConfig `yaml:"sentry"`
+
+	// Debug is the configuration of the debug listener.
+	//
+	// The debug listener exposes the profiles and the runtime variables of the process.
+	Debug DebugConfig `yaml:"debug"`
+
+	// Resources is the configuration of the resource limits.
+	//
+	// The resource limits are detected from the container and can be overridden.
+	Resources ResourcesConfig `yaml:"resources"`
+
+	// Audit is the configuration of the audit log of the heartbeats.
+	//
+	// The audit log keeps the latest heartbeats with the addresses of the agents.
+	Audit AuditConfig `yaml:"audit"`
+
+	// Relay is the configuration of the relay mode.
+	//
+	// It is used by the `vakeel-way relay` command only.
+	Relay RelayConfig `yaml:"relay"`
+}
+
+// ReplicaConfig represents the configuration of the replica mode.
+//
+// It contains the mode the instance starts in.
+type ReplicaConfig struct {
+	// Mode is the mode the instance starts in.
 	//
-	// Example: "https://example.com/webhook"
-	Target string `yaml:"target"`
+	// The possible values are:
+	// - "active" for an instance that dispatches notifications
+	// - "standby" for a read-only instance that only tracks heartbeats
+	//
+	// A standby instance can be switched into active mode at runtime with the
+	// `vakeel-way promote` command.
+	Mode string `yaml:"mode"`
 }
 
 // LogConfig represents the configuration for the logger.
 //
-// It contains the log level.
+// It contains the log level, the output format and the sampling of the
+// high-volume levels.
 type LogConfig struct {
 	// Level is the log level.
 	//
@@ -89,6 +236,72 @@ type LogConfig struct {
 	// - "error" for errors that should be addressed
 	// - "fatal" for critical errors that cause the application to exit
 	Level string `yaml:"level"`
+
+	// Format is the output format of the logs.
+	//
+	// The possible values are:
+	// - "console" for the human-readable colored output
+	// - "json" for one JSON object per line, for the log collectors
+	Format string `yaml:"format"`
+
+	// TimeFormat is the layout of the timestamps, in the format of the time package.
+	//
+	// Example: "2006-01-02T15:04:05Z07:00"
+	TimeFormat string `yaml:"time_format"`
+
+	// Caller defines whether the file and the line of the call are added to the logs.
+	Caller bool `yaml:"caller"`
+
+	// Sampling is the sampling of the high-volume levels.
+	Sampling LogSamplingConfig `yaml:"sampling"`
+
+	// Output is the destination of the logs.
+	//
+	// The possible values are:
+	// - "stdout" for the standard output, in the configured format
+	// - "syslog" for a syslog daemon, as JSON with the levels mapped to the priorities
+	// - "journald" for the journal of systemd, with the fields of the logs as the fields of the entries
+	Output string `yaml:"output"`
+
+	// Tag is the syslog tag and the journald identifier of the logs.
+	Tag string `yaml:"tag"`
+
+	// Syslog is the address of the syslog daemon.
+	Syslog LogSyslogConfig `yaml:"syslog"`
+
+	// Journald is the socket of journald.
+	//
+	// Example: "/run/systemd/journal/socket"
+	Journald string `yaml:"journald"`
+}
+
+// LogSyslogConfig represents the address of the syslog daemon.
+//
+// If both fields are empty, the local daemon is used.
+type LogSyslogConfig struct {
+	// Network is the network of the daemon.
+	//
+	// Example: "udp"
+	Network string `yaml:"network"`
+
+	// Address is the address of the daemon.
+	//
+	// Example: "logs.example.com:514"
+	Address string `yaml:"address"`
+}
+
+// LogSamplingConfig represents the sampling of the logs per level.
+//
+// Every Nth message of the level is logged. Zero and one log every message.
+type LogSamplingConfig struct {
+	// Trace is the sampling rate of the trace messages.
+	Trace uint32 `yaml:"trace"`
+
+	// Debug is the sampling rate of the debug messages.
+	Debug uint32 `yaml:"debug"`
+
+	// Info is the sampling rate of the informational messages, e.g. the access log.
+	Info uint32 `yaml:"info"`
 }
 
 // GRPCConfig represents the configuration of the gRPC server.
@@ -109,6 +322,85 @@ type GRPCConfig struct {
 	// Port is the port number to use for the gRPC server.
 	// It is the port number where the gRPC server will listen for incoming connections.
 	Port string `yaml:"port"`
+
+	// Strict defines whether the heartbeats of the services that are not
+	// configured are rejected.
+	//
+	// In strict mode, the Update stream is closed with the NotFound code when
+	// an agent sends an unknown UUID, so the misconfigured agents are noticed.
+	// Otherwise, the heartbeats of the unknown UUIDs are ignored and reported
+	// as unknown in the response.
+	Strict bool `yaml:"strict"`
+
+	// Reflection defines whether the reflection service is registered.
+	//
+	// The reflection service lets clients such as grpcurl discover the
+	// services and methods of the server. It is handy in development, while
+	// the production deployments may disable it to hide the service discovery.
+	Reflection bool `yaml:"reflection"`
+
+	// Compression is the configuration of the compression of the gRPC messages.
+	Compression GRPCCompressionConfig `yaml:"compression"`
+
+	// TLS is the configuration of the TLS of the gRPC server. If no
+	// certificate is set, the server accepts plaintext connections unless the
+	// automatic certificates are enabled.
+	TLS TLSConfig `yaml:"tls"`
+
+	// Listeners is the list of the additional listeners of the gRPC server.
+	//
+	// Every listener serves the same services as the main one, e.g. a unix
+	// socket for the local agents along with the TCP port, or an internal
+	// address in plaintext along with an external one served over TLS.
+	Listeners []GRPCListenerConfig `yaml:"listeners"`
+}
+
+// GRPCListenerConfig represents the configuration of an additional listener of the gRPC server.
+type GRPCListenerConfig struct {
+	// Name is the name of the listener in the logs.
+	Name string `yaml:"name"`
+
+	// Network is the network of the listener.
+	//
+	// The possible values are:
+	// - "tcp" for IPv4 or IPv6 (default)
+	// - "unix" for a unix socket, accessible to the local agents only
+	Network string `yaml:"network"`
+
+	// Address is the address of the listener, or the path to the unix socket.
+	//
+	// Example: "10.0.0.5:4643" or "/run/vakeel-way/grpc.sock"
+	Address string `yaml:"address"`
+
+	// TLS is the configuration of the TLS of the listener. If no certificate
+	// is set, the automatic certificates are served over TCP if they are
+	// enabled, unless the listener is plaintext.
+	TLS TLSConfig `yaml:"tls"`
+
+	// Plaintext defines whether the listener accepts plaintext connections
+	// even if the automatic certificates are enabled, e.g. on an internal network.
+	Plaintext bool `yaml:"plaintext"`
+}
+
+// GRPCCompressionConfig represents the configuration of the compression of the gRPC messages.
+//
+// The compression is negotiated per call: the agents compress their messages
+// with an algorithm the server accepts, and the server compresses its
+// responses with the same algorithm. It reduces the bandwidth of the agents
+// sending large batches of UUIDs. The algorithm of every call is written to
+// the access log.
+type GRPCCompressionConfig struct {
+	// Algorithms is the list of the compression algorithms the server accepts.
+	//
+	// The possible values are "gzip" and "zstd". If it is empty, the messages
+	// are not compressed.
+	//
+	// Example: ["gzip", "zstd"]
+	Algorithms []string `yaml:"algorithms"`
+
+	// Level is the gzip compression level from 1 (fastest) to 9 (smallest).
+	// If it is zero, the default level is used.
+	Level int `yaml:"level"`
 }
 
 // Addr returns the address of the gRPC server as a string.
@@ -137,20 +429,193 @@ func (c GRPCConfig) Addr() string {
 func New(path string) (Config, error) {
 	// Create a new Config instance with default values
 	// The default values are:
-	// - log level: info
+	// - log level: info, console output to stdout with RFC3339Nano timestamps, not sampled
 	// - network: tcp
 	// - host: 0.0.0.0
 	// - port: 4643
+	// - gRPC reflection: enabled
+	// - gRPC compression: gzip accepted at the default level
+	// - replica mode: active
+	// - http: disabled, 0.0.0.0:8080
+	// - status page: disabled, 0.0.0.0:8081, 90 days of history cached for a minute
+	// - admin REST API: disabled, 127.0.0.1:8082, OIDC login disabled with 12-hour sessions
+	// - vault: not configured, the secrets cached for 5 minutes
+	// - acme: disabled, cached in ./acme, HTTP-01 challenges answered on :80
+	// - debug listener: disabled, 127.0.0.1:6060
+	// - checker: 4 workers, duplicate heartbeats coalesced within 1 second
+	// - delivery breaker: opens after 5 failures for 30 seconds
+	// - delivery dns: the targets resolved again every 30 seconds
+	// - history: raw for 7 days, hourly for 90 days, daily for 2 years
+	// - state: in memory, persisted every 30 seconds if a file is set
+	// - stale webhooks: flagged after 30 days, summarized daily, not archived
+	// - audit: the latest 1000 heartbeats kept in memory
+	// - relay: 127.0.0.1:4644 to 127.0.0.1:4643, flushed every second, up to 10000 services buffered
+	// - alertmanager: disabled, the UUID in the vakeel_way_id label, resolved alerts kept for a day
+	// - jwt: disabled, the tenant and scope claims, a minute of clock skew
+	// - consul: disabled, the local agent, checked every 10 seconds, deregistered after a minute of failures
+	// - operator: disabled, fully resynced every 5 minutes
 	cfg := Config{
 		Log: LogConfig{
-			Level: "info",
+			Level:      "info",
+			Format:     "console",
+			TimeFormat: time.RFC3339Nano,
+			Output:     "stdout",
+			Tag:        "vakeel-way",
 		},
 		GRPC: GRPCConfig{
-			Network: "tcp",
-			Host:    "0.0.0.0",
-			Port:    "4643",
+			Network:    "tcp",
+			Host:       "0.0.0.0",
+			Port:       "4643",
+			Reflection: true,
+			Compression: GRPCCompressionConfig{
+				Algorithms: []string{"gzip"},
+			},
 		},
 		Webhooks: Webhooks{},
+		Checker: CheckerConfig{
+			Workers: 4,
+			Window:  time.Second,
+		},
+		Replica: ReplicaConfig{
+			Mode: "active",
+		},
+		NATS: NATSConfig{
+			URL:     "nats://127.0.0.1:4222",
+			Subject: "vakeel-way.heartbeats",
+			Queue:   "vakeel-way",
+		},
+		MQTT: MQTTConfig{
+			Broker: "tcp://127.0.0.1:1883",
+			Topic:  "vakeel-way/{id}/heartbeat",
+		},
+		UDP: UDPConfig{
+			Host: "0.0.0.0",
+			Port: "4644",
+		},
+		Alertmanager: AlertmanagerConfig{
+			Label: "vakeel_way_id",
+			TTL:   24 * time.Hour,
+		},
+		KafkaRESTProxy: KafkaRESTProxyConfig{
+			URL:      "http://127.0.0.1:8082",
+			Topic:    "vakeel-way.events",
+			Interval: time.Second,
+			Buffer:   10000,
+		},
+		Cluster: ClusterConfig{
+			Address: "127.0.0.1:6379",
+			Prefix:  "vakeel-way",
+			Lease:   15 * time.Second,
+		},
+		LeaderElection: LeaderElectionConfig{
+			Name:      "vakeel-way",
+			Lease:     15 * time.Second,
+			TokenFile: "/var/run/secrets/kubernetes.io/serviceaccount/token",
+			CAFile:    "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt",
+		},
+		Consul: ConsulConfig{
+			Address: "http://127.0.0.1:8500",
+			Name:    "vakeel-way",
+			Check: ConsulCheckConfig{
+				Interval:        10 * time.Second,
+				Timeout:         5 * time.Second,
+				DeregisterAfter: time.Minute,
+			},
+		},
+		Operator: OperatorConfig{
+			Resync:    5 * time.Minute,
+			TokenFile: "/var/run/secrets/kubernetes.io/serviceaccount/token",
+			CAFile:    "/var/run/secrets/kubernetes.io/serviceaccount/ca.crt",
+		},
+		Secrets: SecretsConfig{
+			Vault: VaultConfig{
+				CacheTTL: 5 * time.Minute,
+			},
+		},
+		HTTP: HTTPConfig{
+			Enabled: false,
+			Host:    "0.0.0.0",
+			Port:    "8080",
+		},
+		StatsD: StatsDConfig{
+			Address:  "127.0.0.1:8125",
+			Prefix:   "vakeel_way.",
+			Interval: time.Second,
+		},
+		Pushgateway: PushgatewayConfig{
+			URL:      "http://127.0.0.1:9091",
+			Job:      "vakeel-way",
+			Interval: 15 * time.Second,
+		},
+		StatusPage: StatusPageConfig{
+			Enabled: false,
+			Host:    "0.0.0.0",
+			Port:    "8081",
+			Title:   "Status",
+			Days:    90,
+			Cache:   time.Minute,
+		},
+		AdminHTTP: AdminHTTPConfig{
+			Enabled: false,
+			Host:    "127.0.0.1",
+			Port:    "8082",
+			OIDC: OIDCConfig{
+				Scopes:     []string{"openid", "email", "profile"},
+				SessionTTL: 12 * time.Hour,
+			},
+		},
+		ACME: ACMEConfig{
+			Enabled:       false,
+			CacheDir:      "acme",
+			ChallengeAddr: ":80",
+		},
+		Debug: DebugConfig{
+			Enabled: false,
+			Host:    "127.0.0.1",
+			Port:    "6060",
+		},
+		Delivery: DeliveryConfig{
+			Outbox: OutboxConfig{
+				Backoff:    5 * time.Second,
+				MaxBackoff: 10 * time.Minute,
+				Attempts:   20,
+			},
+			Breaker: BreakerConfig{
+				Threshold: 5,
+				Cooldown:  30 * time.Second,
+			},
+			DNS: DNSConfig{
+				Refresh: 30 * time.Second,
+			},
+		},
+		History: HistoryConfig{
+			RawRetention:    7 * 24 * time.Hour,
+			HourlyRetention: 90 * 24 * time.Hour,
+			DailyRetention:  2 * 365 * 24 * time.Hour,
+		},
+		State: StateConfig{
+			Interval: 30 * time.Second,
+		},
+		Stale: StaleConfig{
+			After:    30 * 24 * time.Hour,
+			Interval: 24 * time.Hour,
+		},
+		Audit: AuditConfig{
+			Size: 1000,
+		},
+		Relay: RelayConfig{
+			Upstream: "127.0.0.1:4643",
+			Listen:   "127.0.0.1:4644",
+			Interval: time.Second,
+			Buffer:   10000,
+		},
+		Auth: AuthConfig{
+			JWT: JWTConfig{
+				TenantClaim: "tenant",
+				ScopeClaim:  "scope",
+				Leeway:      time.Minute,
+			},
+		},
 	}
 
 	// Check if the file exists
@@ -176,6 +641,11 @@ func New(path string) (Config, error) {
 		return cfg, err
 	}
 
+	// Merge the webhooks of the namespaces into the webhooks.
+	if err := cfg.flatten(); err != nil {
+		return cfg, err
+	}
+
 	// Return the Config instance and nil (indicating success)
 	return cfg, nil
 }
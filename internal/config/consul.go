@@ -0,0 +1,59 @@
+package config
+
+import "time"
+
+// ConsulConfig represents the configuration of the registration in Consul.
+//
+// The gRPC endpoint is registered in the local Consul agent on startup and
+// deregistered on shutdown, so the agents discover vakeel-way through the
+// service discovery instead of a static address.
+type ConsulConfig struct {
+	// Enabled specifies whether the gRPC endpoint is registered in Consul.
+	Enabled bool `yaml:"enabled"`
+
+	// Address is the URL of the HTTP API of the Consul agent.
+	//
+	// Example: "http://127.0.0.1:8500"
+	Address string `yaml:"address"`
+
+	// Token is the ACL token with the service:write permission. It can be
+	// stored encrypted.
+	Token string `yaml:"token"`
+
+	// Name is the name of the service the agents discover.
+	Name string `yaml:"name"`
+
+	// ID is the unique identifier of the instance in Consul.
+	//
+	// If it is empty, the name of the service, the hostname and the port are used.
+	ID string `yaml:"id"`
+
+	// Advertise is the address the agents reach the gRPC endpoint at.
+	//
+	// If it is empty, the host of the gRPC server is used, or the address of
+	// the node of the Consul agent if the server listens on all interfaces.
+	Advertise string `yaml:"advertise"`
+
+	// Tags are the tags of the service in Consul.
+	Tags []string `yaml:"tags"`
+
+	// Check is the configuration of the health check run by the Consul agent.
+	//
+	// The readiness probe of the HTTP server is checked if the HTTP server is
+	// enabled, otherwise the TCP port of the gRPC server.
+	Check ConsulCheckConfig `yaml:"check"`
+}
+
+// ConsulCheckConfig represents the configuration of the health check of the service in Consul.
+type ConsulCheckConfig struct {
+	// Interval is the interval between the checks.
+	Interval time.Duration `yaml:"interval"`
+
+	// Timeout is the time allowed for a check.
+	Timeout time.Duration `yaml:"timeout"`
+
+	// DeregisterAfter is the time after which an instance failing its check
+	// is deregistered, e.g. once it crashed without deregistering itself.
+	// Zero keeps the failing instances.
+	DeregisterAfter time.Duration `yaml:"deregister_after"`
+}
@@ -0,0 +1,25 @@
+package config
+
+import "net"
+
+// DebugConfig represents the configuration of the debug listener.
+//
+// The debug listener exposes net/http/pprof, expvar and a dump of the
+// goroutines, so the process can be profiled in production. It is bound to
+// the loopback interface by default, because the profiles leak the internals
+// of the process.
+type DebugConfig struct {
+	// Enabled defines whether the debug listener is started.
+	Enabled bool `yaml:"enabled"`
+
+	// Host is the host address to use for the debug listener.
+	Host string `yaml:"host"`
+
+	// Port is the port number to use for the debug listener.
+	Port string `yaml:"port"`
+}
+
+// Addr returns the address of the debug listener in the format "host:port".
+func (c DebugConfig) Addr() string {
+	return net.JoinHostPort(c.Host, c.Port)
+}
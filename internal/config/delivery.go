@@ -0,0 +1,138 @@
+package config
+
+import "time"
+
+// DeliveryConfig represents the configuration of the delivery of the notifications.
+type DeliveryConfig struct {
+	// Spool is the path to the file the notifications for the unavailable targets
+	// are queued in.
+	//
+	// A target that keeps failing after all retry attempts is considered
+	// unavailable: the following transitions are queued instead of being sent,
+	// and a single catch-up notification is delivered once the target recovers.
+	// If the path is empty, the queue is kept in memory and is lost on restart.
+	//
+	// Example: "/var/lib/vakeel-way/spool.json"
+	Spool string `yaml:"spool"`
+
+	// Outbox is the configuration of the durable queue of the deliveries.
+	Outbox OutboxConfig `yaml:"outbox"`
+
+	// DeadLetters is the configuration of the store of the notifications that
+	// could not be delivered.
+	DeadLetters DeadLettersConfig `yaml:"dead_letters"`
+
+	// Breaker is the configuration of the circuit breaker of the targets.
+	Breaker BreakerConfig `yaml:"breaker"`
+
+	// Capture is the configuration of the targets of the "capture" type.
+	Capture CaptureConfig `yaml:"capture"`
+
+	// DNS is the configuration of the resolution of the hosts of the targets.
+	DNS DNSConfig `yaml:"dns"`
+}
+
+// DNSConfig represents the configuration of the resolution of the hosts of the targets.
+//
+// The URLs of the targets with the "http+srv" or "https+srv" scheme are
+// resolved with the SRV records of their host, and every attempt of a
+// notification is sent to a target selected by the priority and the weight of
+// the records. The other hosts are resolved again once their idle connections
+// are closed, so the notifications follow the backends whose addresses change.
+type DNSConfig struct {
+	// Refresh is the time the SRV records are cached for and the idle
+	// connections to the targets are kept for.
+	//
+	// Example: "30s"
+	Refresh time.Duration `yaml:"refresh"`
+}
+
+// OutboxConfig represents the configuration of the durable queue of the deliveries.
+//
+// Every notification is written to a BoltDB file before it is sent, and
+// removed once the target accepts it. The notifications that fail are retried
+// with an exponential backoff, and survive a restart of the process. The
+// notifications that exhaust their attempts are moved to the dead letters.
+type OutboxConfig struct {
+	// Path is the path to the BoltDB file of the queue.
+	//
+	// If the path is empty, the outbox is disabled and the failed notifications
+	// are retried in memory only.
+	//
+	// Example: "/var/lib/vakeel-way/outbox.db"
+	Path string `yaml:"path"`
+
+	// Backoff is the delay before the first retry, doubled on every attempt.
+	//
+	// Example: "5s"
+	Backoff time.Duration `yaml:"backoff"`
+
+	// MaxBackoff is the maximum delay between two attempts.
+	//
+	// Example: "10m"
+	MaxBackoff time.Duration `yaml:"max_backoff"`
+
+	// Attempts is the number of attempts of a notification before it is moved
+	// to the dead letters.
+	Attempts int `yaml:"attempts"`
+}
+
+// DeadLettersConfig represents the configuration of the dead letters.
+//
+// The notifications whose retries are exhausted in the outbox, or that are
+// discarded from the full queue of an unavailable target, are kept as dead
+// letters. They are listed, replayed and discarded with the administrative API
+// and the dead-letters command.
+type DeadLettersConfig struct {
+	// File is the path to the file the dead letters are kept in.
+	//
+	// If the path is empty, the dead letters are kept in memory only.
+	//
+	// Example: "/var/lib/vakeel-way/dead-letters.json"
+	File string `yaml:"file"`
+
+	// Size is the maximum number of the dead letters kept. The oldest ones are
+	// dropped once the limit is reached.
+	//
+	// If it is zero, 1000 dead letters are kept.
+	Size int `yaml:"size"`
+}
+
+// CaptureConfig represents the configuration of the capture notifier.
+//
+// The targets of the "capture" type keep the rendered notifications instead of
+// delivering them, so the staging environments can exercise the full pipeline
+// without any external calls. The captured notifications are listed with the
+// administrative API.
+type CaptureConfig struct {
+	// File is the path to the file the captured notifications are appended to
+	// as JSON lines.
+	//
+	// If the path is empty, the notifications are kept in memory only.
+	//
+	// Example: "/var/lib/vakeel-way/captured.jsonl"
+	File string `yaml:"file"`
+
+	// Size is the number of the latest captured notifications kept in memory.
+	//
+	// If it is zero, the 100 latest notifications are kept.
+	Size int `yaml:"size"`
+}
+
+// BreakerConfig represents the configuration of the circuit breaker wrapping
+// the delivery to each target URL.
+//
+// A target that keeps failing is considered hard-down: its circuit opens and the
+// notifications are queued immediately instead of waiting for the timeout of
+// every delivery. After the cooldown a single notification probes the target.
+type BreakerConfig struct {
+	// Threshold is the number of consecutive failures that open the circuit.
+	//
+	// If it is zero, the circuit breaker is disabled.
+	Threshold int `yaml:"threshold"`
+
+	// Cooldown is the time the circuit stays open before the target is probed.
+	//
+	// Example: "30s"
+	Cooldown time.Duration `yaml:"cooldown"`
+}
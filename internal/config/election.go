@@ -0,0 +1,49 @@
+package config
+
+import "time"
+
+// LeaderElectionConfig represents the configuration of the leader election
+// with a Kubernetes Lease.
+//
+// The replicas of a deployment elect the one dispatching the notifications,
+// so the webhooks are not delivered twice. The other replicas are standby and
+// take over once the lease of the leader expires.
+type LeaderElectionConfig struct {
+	// Enabled specifies whether the instance takes part in the leader election.
+	//
+	// The replica mode is ignored in the election: the instance starts in
+	// standby mode and is promoted once it is elected. The election takes
+	// precedence over the lease of the clustered mode.
+	Enabled bool `yaml:"enabled"`
+
+	// Namespace is the namespace of the Lease object.
+	//
+	// If it is empty, the namespace of the pod is used.
+	Namespace string `yaml:"namespace"`
+
+	// Name is the name of the Lease object.
+	Name string `yaml:"name"`
+
+	// Identity is the unique name of the instance in the election.
+	//
+	// If it is empty, the hostname, i.e. the name of the pod, is used.
+	Identity string `yaml:"identity"`
+
+	// Lease is the time the leadership is held without a renewal. It is rounded down to seconds.
+	//
+	// Once the leader is gone, another instance takes over within the lease.
+	Lease time.Duration `yaml:"lease"`
+
+	// Endpoint is the URL of the API server.
+	//
+	// If it is empty, the API server of the cluster the pod runs in is used.
+	//
+	// Example: "https://kubernetes.default.svc"
+	Endpoint string `yaml:"endpoint"`
+
+	// TokenFile is the path to the bearer token of the service account.
+	TokenFile string `yaml:"token_file"`
+
+	// CAFile is the path to the CA bundle of the API server.
+	CAFile string `yaml:"ca_file"`
+}
@@ -0,0 +1,65 @@
+package config
+
+import "reflect"
+
+// Expand replaces every string value of the configuration with the result of the function.
+//
+// It is used to resolve the references to the external secret stores once
+// the configuration is loaded. The strings of the nested structs, slices and
+// maps are replaced too.
+//
+// Parameters:
+//   - expand: The function returning the replacement of a string, e.g. the
+//     string itself if it references nothing.
+//
+// Returns:
+//   - The first error returned by the function.
+func (c *Config) Expand(expand func(value string) (string, error)) error {
+	return expandValue(reflect.ValueOf(c).Elem(), expand)
+}
+
+// expandValue replaces the strings of the settable value.
+func expandValue(value reflect.Value, expand func(string) (string, error)) error {
+	switch value.Kind() { //nolint:exhaustive
+	case reflect.String:
+		expanded, err := expand(value.String())
+		if err != nil {
+			return err
+		}
+
+		value.SetString(expanded)
+	case reflect.Pointer, reflect.Interface:
+		if !value.IsNil() {
+			return expandValue(value.Elem(), expand)
+		}
+	case reflect.Struct:
+		for i := range value.NumField() {
+			if field := value.Field(i); field.CanSet() {
+				if err := expandValue(field, expand); err != nil {
+					return err
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range value.Len() {
+			if err := expandValue(value.Index(i), expand); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		// The values of a map are not addressable, so they are expanded in a copy.
+		iter := value.MapRange()
+		for iter.Next() {
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+
+			if err := expandValue(elem, expand); err != nil {
+				return err
+			}
+
+			value.SetMapIndex(iter.Key(), elem)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// GroupConfig represents the configuration of a service group.
+//
+// A group aggregates the statuses of its members into the status of a named
+// component. The targets of the webhook of the group are notified once per
+// transition of the group, and the members are not notified on their own.
+type GroupConfig struct {
+	// Name is the name of the component.
+	//
+	// Example: "payments"
+	Name string `yaml:"name"`
+
+	// ID is the UUID of the webhook notified about the group.
+	//
+	// The webhook has to be configured, and it does not receive heartbeats:
+	// its status is aggregated from the members.
+	ID uuid.UUID `yaml:"id"`
+
+	// Members is the list of the UUIDs of the webhooks of the group.
+	Members []uuid.UUID `yaml:"members"`
+
+	// Quorum is the number of the members that have to be down for the group
+	// to be down. Fewer members down make the group degraded.
+	//
+	// If it is zero, any member that is down brings the group down.
+	Quorum int `yaml:"quorum"`
+}
+
+// Entity converts the configuration into a group.
+//
+// Returns:
+// - The entities.Group described by the configuration.
+func (c GroupConfig) Entity() entities.Group {
+	return entities.Group{
+		ID:      c.ID,
+		Name:    c.Name,
+		Members: c.Members,
+		Quorum:  c.Quorum,
+	}
+}
@@ -0,0 +1,25 @@
+package config
+
+import "time"
+
+// HistoryConfig represents the configuration of the status history.
+//
+// The raw status transitions are downsampled in the background into hourly and
+// daily uptime aggregates, each kept for its own retention period.
+type HistoryConfig struct {
+	// Path is the path to the file the history is kept in.
+	//
+	// If it is empty, the history is kept in memory and is lost on restart.
+	//
+	// Example: "/var/lib/vakeel-way/history.json"
+	Path string `yaml:"path"`
+
+	// RawRetention is the retention of the raw status transitions. At least 2h.
+	RawRetention time.Duration `yaml:"raw_retention"`
+
+	// HourlyRetention is the retention of the hourly aggregates. At least 48h.
+	HourlyRetention time.Duration `yaml:"hourly_retention"`
+
+	// DailyRetention is the retention of the daily aggregates.
+	DailyRetention time.Duration `yaml:"daily_retention"`
+}
@@ -0,0 +1,22 @@
+package config
+
+import "net"
+
+// HTTPConfig represents the configuration of the HTTP server.
+//
+// It contains the host address and port number to use for the HTTP server.
+type HTTPConfig struct {
+	// Enabled defines whether the HTTP server is started.
+	Enabled bool `yaml:"enabled"`
+
+	// Host is the host address to use for the HTTP server.
+	Host string `yaml:"host"`
+
+	// Port is the port number to use for the HTTP server.
+	Port string `yaml:"port"`
+}
+
+// Addr returns the address of the HTTP server in the format "host:port".
+func (c HTTPConfig) Addr() string {
+	return net.JoinHostPort(c.Host, c.Port)
+}
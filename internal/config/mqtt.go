@@ -0,0 +1,45 @@
+package config
+
+import "github.com/google/uuid"
+
+// MQTTConfig represents the configuration of the MQTT listener.
+//
+// The embedded and IoT devices report their liveness by publishing to a topic
+// of an MQTT broker instead of opening an Update stream.
+type MQTTConfig struct {
+	// Enabled specifies whether the heartbeats are received from MQTT.
+	Enabled bool `yaml:"enabled"`
+
+	// Broker is the URL of the MQTT broker.
+	//
+	// Example: "tcp://127.0.0.1:1883"
+	Broker string `yaml:"broker"`
+
+	// ClientID is the client ID of the listener. It has to be unique per broker.
+	//
+	// If it is empty, a random client ID is generated on startup.
+	ClientID string `yaml:"client_id"`
+
+	// Username is the username of the broker.
+	Username string `yaml:"username"`
+
+	// Password is the password of the broker. It can be stored encrypted.
+	Password string `yaml:"password"`
+
+	// Topic is the pattern of the topics of the heartbeats.
+	//
+	// The {id} segment is the UUID of the service or one of the aliases. Every
+	// message published to a matching topic is a heartbeat, whatever its payload.
+	//
+	// Example: "devices/{id}/alive"
+	Topic string `yaml:"topic"`
+
+	// QoS is the quality of service of the subscription: 0, 1 or 2.
+	QoS byte `yaml:"qos"`
+
+	// Aliases maps the segments of the topics to the UUIDs of the services, so
+	// the devices can publish to the topics named after them.
+	//
+	// Example: {"boiler-1": "224f8a59-6705-4f3e-b7de-177757932aad"}
+	Aliases map[string]uuid.UUID `yaml:"aliases"`
+}
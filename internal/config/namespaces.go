@@ -0,0 +1,53 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// ErrNamespaceConflict is an error that indicates that a webhook is configured in several namespaces.
+var ErrNamespaceConflict = errors.New("config: webhook is configured in several namespaces")
+
+// NamespaceConfig represents the configuration of a namespace.
+//
+// A namespace groups the webhooks of a team. The API tokens scoped to the
+// namespace see its webhooks only, so one instance can serve several teams
+// without them reporting or watching each other's services.
+type NamespaceConfig struct {
+	// Name is the name of the namespace, referenced by the API tokens.
+	//
+	// Example: "payments"
+	Name string `yaml:"name"`
+
+	// Webhooks is the configuration of the webhooks of the namespace.
+	Webhooks Webhooks `yaml:"webhooks"`
+}
+
+// flatten moves the webhooks of the namespaces into the list of the webhooks,
+// with their namespace set.
+//
+// Returns:
+//   - ErrNamespaceConflict if a UUID is configured in several namespaces.
+func (c *Config) flatten() error {
+	for _, namespace := range c.Namespaces {
+		for _, webhook := range namespace.Webhooks {
+			webhook.Namespace = namespace.Name
+			c.Webhooks = append(c.Webhooks, webhook)
+		}
+	}
+
+	// The UUIDs are scoped per namespace, so they must not leak into another one.
+	seen := make(map[uuid.UUID]string, len(c.Webhooks))
+
+	for _, webhook := range c.Webhooks {
+		if namespace, ok := seen[webhook.ID]; ok && namespace != webhook.Namespace {
+			return fmt.Errorf("%w: %s in %q and %q", ErrNamespaceConflict, webhook.ID, namespace, webhook.Namespace)
+		}
+
+		seen[webhook.ID] = webhook.Namespace
+	}
+
+	return nil
+}
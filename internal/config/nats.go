@@ -0,0 +1,29 @@
+package config
+
+// NATSConfig represents the configuration of the NATS ingestion.
+//
+// The instance consumes the heartbeat messages the agents publish to a NATS
+// subject, in addition to the Update streams of the gRPC server.
+type NATSConfig struct {
+	// Enabled specifies whether the heartbeats are consumed from NATS.
+	Enabled bool `yaml:"enabled"`
+
+	// URL is the URL of the NATS server. Several URLs can be separated by commas.
+	//
+	// Example: "nats://127.0.0.1:4222"
+	URL string `yaml:"url"`
+
+	// Token is the authentication token of the NATS server. It can be stored encrypted.
+	Token string `yaml:"token"`
+
+	// Subject is the subject the heartbeats are consumed from. It may contain wildcards.
+	//
+	// Example: "vakeel-way.heartbeats.>"
+	Subject string `yaml:"subject"`
+
+	// Queue is the queue group of the subscription.
+	//
+	// The instances sharing the queue group receive every message once between
+	// them. If it is empty, every instance receives every message.
+	Queue string `yaml:"queue"`
+}
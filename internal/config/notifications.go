@@ -0,0 +1,34 @@
+package config
+
+import "time"
+
+// NotificationsConfig represents the configuration of the notifications.
+type NotificationsConfig struct {
+	// Template is the global text/template source of the notification messages.
+	//
+	// The template is executed with the status transition as data and is used by
+	// the targets that send human-readable messages (Slack, PagerDuty). It can be
+	// overridden per webhook and per target. If it is empty, a built-in template
+	// is used.
+	//
+	// Example: "{{ .ID }} is {{ .Status | upper }} since {{ timestamp .Time }}"
+	Template string `yaml:"template"`
+
+	// Throttle is the minimum time between two notifications with the same status
+	// per service and target.
+	//
+	// The notifications within the window, e.g. of a flapping service, are held
+	// back and attached as a digest to the next notification. If it is zero, the
+	// notifications are not throttled. It can be overridden per target.
+	//
+	// Example: "10m"
+	Throttle time.Duration `yaml:"throttle"`
+
+	// Cascade is the policy applied to a service that goes down while a
+	// service it depends on is down: "suppress" holds the notification back
+	// until the dependency recovers, "tag" sends it with the dependency as
+	// its cause. It defaults to "suppress".
+	//
+	// Example: "tag"
+	Cascade string `yaml:"cascade"`
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"errors"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/google/uuid"
+)
+
+// ErrNoWebhookID is an error that indicates that a webhook defined outside of the configuration file has no UUID.
+var ErrNoWebhookID = errors.New("config: the webhook has no id")
+
+// OperatorConfig represents the configuration of the operator mode.
+//
+// In the operator mode, the webhooks are also read from the Webhook custom
+// resources of the vakeel-way.bavix.dev/v1alpha1 API and from the ConfigMaps
+// annotated with "vakeel-way.bavix.dev/webhooks: true" in a namespace of
+// Kubernetes, so they are managed by GitOps like the rest of the cluster.
+// The spec of a Webhook is a webhook in the format of the configuration
+// file, and every key of such a ConfigMap holds a list of them. The webhooks
+// of the configuration file take precedence over the ones with the same UUID.
+type OperatorConfig struct {
+	// Enabled specifies whether the webhooks are watched in Kubernetes.
+	Enabled bool `yaml:"enabled"`
+
+	// Namespace is the namespace the webhooks are watched in.
+	//
+	// If it is empty, the namespace of the pod is used.
+	Namespace string `yaml:"namespace"`
+
+	// Resync is the longest time between two full syncs of the webhooks,
+	// even if the API server reports no change.
+	Resync time.Duration `yaml:"resync"`
+
+	// Endpoint is the URL of the API server.
+	//
+	// If it is empty, the API server of the cluster the pod runs in is used.
+	//
+	// Example: "https://kubernetes.default.svc"
+	Endpoint string `yaml:"endpoint"`
+
+	// TokenFile is the path to the bearer token of the service account.
+	TokenFile string `yaml:"token_file"`
+
+	// CAFile is the path to the CA bundle of the API server.
+	CAFile string `yaml:"ca_file"`
+}
+
+// ParseWebhook parses a webhook in the format of the configuration file, e.g.
+// the spec of a Webhook custom resource.
+//
+// Parameters:
+//   - data: The YAML or JSON document of the webhook.
+//
+// Returns:
+//   - The parsed webhook.
+//   - An error if the document is invalid or the webhook has no UUID.
+//
+//nolint:exhaustruct
+func ParseWebhook(data []byte) (WebhookConfig, error) {
+	var webhook WebhookConfig
+	if err := yaml.Unmarshal(data, &webhook); err != nil {
+		return WebhookConfig{}, err
+	}
+
+	if webhook.ID == uuid.Nil {
+		return WebhookConfig{}, ErrNoWebhookID
+	}
+
+	return webhook, nil
+}
+
+// ParseWebhooks parses a list of webhooks in the format of the configuration
+// file, e.g. a key of a ConfigMap.
+//
+// Parameters:
+//   - data: The YAML or JSON document of the list of the webhooks.
+//
+// Returns:
+//   - The parsed webhooks.
+//   - An error if the document is invalid or a webhook has no UUID.
+func ParseWebhooks(data []byte) (Webhooks, error) {
+	var webhooks Webhooks
+	if err := yaml.Unmarshal(data, &webhooks); err != nil {
+		return nil, err
+	}
+
+	for _, webhook := range webhooks {
+		if webhook.ID == uuid.Nil {
+			return nil, ErrNoWebhookID
+		}
+	}
+
+	return webhooks, nil
+}
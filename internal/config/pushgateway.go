@@ -0,0 +1,44 @@
+package config
+
+import "time"
+
+// PushgatewayConfig represents the configuration of the push of the metrics to a Prometheus Pushgateway.
+//
+// The metrics exposed on the /metrics endpoint are pushed on an interval
+// instead, for the environments where Prometheus cannot scrape the instances.
+type PushgatewayConfig struct {
+	// Enabled specifies whether the metrics are pushed to the Pushgateway.
+	Enabled bool `yaml:"enabled"`
+
+	// URL is the URL of the Pushgateway.
+	//
+	// Example: "http://pushgateway:9091"
+	URL string `yaml:"url"`
+
+	// Job is the value of the job label of the pushed metrics.
+	Job string `yaml:"job"`
+
+	// Instance is the value of the instance label of the pushed metrics.
+	//
+	// Every instance replaces its own group of metrics only. If it is empty,
+	// the hostname is used, so the replicas do not overwrite each other.
+	Instance string `yaml:"instance"`
+
+	// Grouping are the additional labels of the group of the pushed metrics.
+	//
+	// Example: {"region": "eu-west-1"}
+	Grouping map[string]string `yaml:"grouping"`
+
+	// Interval is the time between the pushes.
+	Interval time.Duration `yaml:"interval"`
+
+	// Username is the username of the basic authentication of the Pushgateway.
+	Username string `yaml:"username"`
+
+	// Password is the password of the basic authentication. It can be stored encrypted.
+	Password string `yaml:"password"`
+
+	// DeleteOnShutdown specifies whether the group of the metrics is deleted
+	// on shutdown, so the metrics of a stopped instance do not linger.
+	DeleteOnShutdown bool `yaml:"delete_on_shutdown"`
+}
@@ -0,0 +1,61 @@
+package config
+
+import "time"
+
+// RelayConfig represents the configuration of the relay mode.
+//
+// The relay accepts the heartbeats of the local processes over HTTP or a Unix
+// socket and multiplexes them over a single gRPC stream to the upstream server.
+type RelayConfig struct {
+	// Upstream is the address of the gRPC server the heartbeats are relayed to.
+	//
+	// Example: "vakeel-way.example.com:4643"
+	Upstream string `yaml:"upstream"`
+
+	// TLS is the configuration of the TLS of the connection to the upstream server.
+	//
+	// If it is not enabled, the heartbeats are relayed in plaintext.
+	TLS ClientTLSConfig `yaml:"tls"`
+
+	// Token is the API token sent to the upstream server. It can be stored encrypted.
+	Token string `yaml:"token"`
+
+	// Listen is the address the HTTP endpoint of the relay listens on.
+	//
+	// If it is empty, the relay accepts the heartbeats on the Unix socket only.
+	//
+	// Example: "127.0.0.1:4644"
+	Listen string `yaml:"listen"`
+
+	// Socket is the path to the Unix socket the relay listens on.
+	//
+	// The socket serves the same HTTP endpoint as the Listen address. If it is
+	// empty, the socket is not created.
+	//
+	// Example: "/run/vakeel-way/relay.sock"
+	Socket string `yaml:"socket"`
+
+	// Offline is the path to the file the heartbeats are buffered in while the
+	// upstream server is unreachable.
+	//
+	// The buffered heartbeats keep the time they were received and are flushed
+	// as delayed heartbeats on reconnect, so the server can tell a network
+	// outage from an outage of the services. If the path is empty, the buffer
+	// is kept in memory and is lost on restart.
+	//
+	// Example: "/var/lib/vakeel-way/relay.json"
+	Offline string `yaml:"offline"`
+
+	// Labels are the labels of the relay introduced to the upstream server in
+	// the handshake, along with its hostname and version.
+	//
+	// Example: {"region": "eu-west-1"}
+	Labels map[string]string `yaml:"labels"`
+
+	// Interval is the time between two flushes of the heartbeats upstream.
+	Interval time.Duration `yaml:"interval"`
+
+	// Buffer is the maximum number of the distinct services buffered while the
+	// upstream server is unreachable.
+	Buffer int `yaml:"buffer"`
+}
@@ -0,0 +1,25 @@
+package config
+
+// ResourcesConfig represents the overrides of the resource limits.
+//
+// By default, the limits of the container are detected from the cgroup file
+// system, and the internal buffers are sized accordingly. The overrides take
+// precedence over the detected limits.
+type ResourcesConfig struct {
+	// CPUs is the number of the CPUs available to the server, e.g. 0.5.
+	//
+	// If it is zero, the CPU limit of the container is used.
+	CPUs float64 `yaml:"cpus"`
+
+	// Memory is the memory available to the server in bytes.
+	//
+	// If it is zero, the memory limit of the container is used.
+	//
+	// Example: 268435456
+	Memory int64 `yaml:"memory"`
+
+	// EventBuffer is the number of the heartbeats buffered before they are processed.
+	//
+	// If it is zero, 64 heartbeats are buffered per available CPU.
+	EventBuffer int `yaml:"event_buffer"`
+}
@@ -0,0 +1,41 @@
+package config
+
+import "time"
+
+// KafkaRESTProxyConfig represents the configuration of the export of the
+// events to Kafka through the REST Proxy.
+//
+// The status transitions, and optionally the heartbeats, are produced as
+// structured JSON events to a Kafka topic, so the analytics and SIEM systems
+// can consume them. The events are produced with the REST Proxy API v2 of
+// Confluent: the REST Proxy, or a compatible one such as the HTTP Proxy of
+// Redpanda, is required, as the brokers are never reached directly.
+type KafkaRESTProxyConfig struct {
+	// Enabled specifies whether the events are exported.
+	Enabled bool `yaml:"enabled"`
+
+	// URL is the URL of the Kafka REST Proxy the events are produced through.
+	//
+	// Example: "http://kafka-rest:8082"
+	URL string `yaml:"url"`
+
+	// Username is the username of the REST Proxy, if it requires basic authentication.
+	Username string `yaml:"username"`
+
+	// Password is the password of the REST Proxy. It can be stored encrypted.
+	Password string `yaml:"password"`
+
+	// Topic is the topic the events are produced to.
+	Topic string `yaml:"topic"`
+
+	// Heartbeats specifies whether every heartbeat is exported, in addition to
+	// the status transitions.
+	Heartbeats bool `yaml:"heartbeats"`
+
+	// Interval is the time between two batches of the events.
+	Interval time.Duration `yaml:"interval"`
+
+	// Buffer is the maximum number of the events buffered while the REST Proxy
+	// is unreachable. The newer events are dropped.
+	Buffer int `yaml:"buffer"`
+}
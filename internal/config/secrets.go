@@ -0,0 +1,141 @@
+package config
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrSecretKeySource is an error that indicates that a secret key has no (or more than one) source.
+var ErrSecretKeySource = errors.New("secret key must have exactly one of key, env or file")
+
+// SecretsConfig represents the configuration of the encryption keys.
+//
+// It contains the identifier of the primary key and the list of all keys.
+// Values are encrypted with the primary key and can be decrypted with any key,
+// which allows keys to be rotated.
+type SecretsConfig struct {
+	// Primary is the identifier of the key used to encrypt new values.
+	Primary string `yaml:"primary"`
+
+	// Keys is the list of the encryption keys.
+	Keys []SecretKeyConfig `yaml:"keys"`
+
+	// Vault is the configuration of HashiCorp Vault. The values of the
+	// configuration in the format "vault:<path>#<field>" are read from it.
+	Vault VaultConfig `yaml:"vault"`
+
+	// AWS is the configuration of AWS Secrets Manager and the SSM Parameter
+	// Store. The values of the configuration in the format "aws-sm://<name>"
+	// or "ssm://<name>" are read from them on startup.
+	AWS AWSConfig `yaml:"aws"`
+}
+
+// AWSConfig represents the configuration of the AWS secret stores.
+//
+// The credentials are looked up like the AWS SDKs do: the environment
+// variables, the web identity token of EKS, the credentials endpoint of ECS
+// and the instance metadata service of EC2.
+type AWSConfig struct {
+	// Region is the region of the secret stores. If it is empty, the
+	// AWS_REGION environment variable is used. The secrets referenced by an
+	// ARN are read from the region of the ARN.
+	Region string `yaml:"region"`
+
+	// Endpoint is the endpoint of the secret stores, e.g. of LocalStack, or
+	// empty for the endpoints of AWS.
+	Endpoint string `yaml:"endpoint"`
+}
+
+// VaultConfig represents the configuration of HashiCorp Vault.
+//
+// The secrets are read from the KV secrets engine when they are used and
+// cached, so the values rotated in Vault take effect without a restart for the
+// webhooks, and on restart for the other sections. Vault is used if the
+// address is set.
+type VaultConfig struct {
+	// Address is the address of Vault, e.g. https://vault.example.com:8200.
+	Address string `yaml:"address"`
+
+	// Token is the token of the requests. If it is empty and no token file
+	// is set, the VAULT_TOKEN environment variable is used. It is renewed
+	// while the server runs, and may be stored encrypted.
+	Token string `yaml:"token"`
+
+	// TokenFile is the path of the file holding the token, e.g. the sink of
+	// a Vault Agent. It is read on every request and not renewed.
+	TokenFile string `yaml:"token_file"`
+
+	// Namespace is the namespace of the requests (Vault Enterprise).
+	Namespace string `yaml:"namespace"`
+
+	// CacheTTL is the time the secrets are cached for.
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+}
+
+// SecretKeyConfig represents the configuration of a single encryption key.
+//
+// The key material is a base64-encoded 32-byte key. It can be stored inline,
+// in an environment variable, or in a file (e.g. mounted from a KMS-backed
+// secret store). Exactly one of Key, Env, and File must be set.
+type SecretKeyConfig struct {
+	// ID is the identifier of the key. It is stored alongside every encrypted value.
+	//
+	// It consists of letters, digits, "_", "." and "-".
+	ID string `yaml:"id"`
+
+	// Key is the base64-encoded key material.
+	Key string `yaml:"key"`
+
+	// Env is the name of the environment variable holding the key material.
+	Env string `yaml:"env"`
+
+	// File is the path of the file holding the key material.
+	File string `yaml:"file"`
+}
+
+// Material returns the decoded key material.
+//
+// Returns:
+//   - The decoded key material.
+//   - An error if the key has no source or the material cannot be read.
+func (c SecretKeyConfig) Material() ([]byte, error) {
+	var encoded string
+
+	switch {
+	case c.Key != "" && c.Env == "" && c.File == "":
+		encoded = c.Key
+	case c.Env != "" && c.Key == "" && c.File == "":
+		encoded = os.Getenv(c.Env)
+	case c.File != "" && c.Key == "" && c.Env == "":
+		data, err := os.ReadFile(c.File)
+		if err != nil {
+			return nil, err
+		}
+
+		encoded = string(data)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrSecretKeySource, c.ID)
+	}
+
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+}
+
+// Material returns the decoded key material of all keys indexed by the key identifier.
+func (c SecretsConfig) Material() (map[string][]byte, error) {
+	keys := make(map[string][]byte, len(c.Keys))
+
+	for _, key := range c.Keys {
+		material, err := key.Material()
+		if err != nil {
+			return nil, err
+		}
+
+		keys[key.ID] = material
+	}
+
+	return keys, nil
+}
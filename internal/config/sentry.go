@@ -0,0 +1,22 @@
+package config
+
+// SentryConfig represents the configuration of the error reporting to Sentry.
+//
+// The failed deliveries, the panics and the configuration errors are reported
+// with the UUIDs of the services as tags. The reporting is disabled unless a
+// DSN is configured here or in the SENTRY_DSN environment variable.
+type SentryConfig struct {
+	// DSN is the Data Source Name of the Sentry project. It may be stored encrypted.
+	//
+	// Example: "https://key@o0.ingest.sentry.io/0"
+	DSN string `yaml:"dsn"`
+
+	// Environment is the environment of the reported errors.
+	//
+	// Example: "production"
+	Environment string `yaml:"environment"`
+
+	// SampleRate is the share of the reported errors that are sent, from 0 to 1.
+	// Zero sends every error.
+	SampleRate float64 `yaml:"sample_rate"`
+}
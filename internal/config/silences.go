@@ -0,0 +1,50 @@
+package config
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// SilenceConfig represents the configuration of a maintenance window.
+//
+// During the window the status transitions of the matching services are
+// recorded, but not notified. If a service is still down when the window ends,
+// the targets are notified then.
+type SilenceConfig struct {
+	// IDs is the list of the UUIDs of the silenced services.
+	IDs []uuid.UUID `yaml:"ids"`
+
+	// Labels is the label selector of the silenced services.
+	//
+	// A service matches the selector if it has all the labels with the same values.
+	//
+	// Example: {"env": "staging"}
+	Labels map[string]string `yaml:"labels"`
+
+	// StartsAt is the time the window begins, in RFC 3339 format.
+	StartsAt time.Time `yaml:"starts_at"`
+
+	// EndsAt is the time the window ends, in RFC 3339 format.
+	EndsAt time.Time `yaml:"ends_at"`
+
+	// Comment is a free-form description of the window.
+	Comment string `yaml:"comment"`
+}
+
+// Entity converts the configuration into a silence.
+//
+// Returns:
+// - The entities.Silence described by the configuration.
+func (c SilenceConfig) Entity() entities.Silence {
+	return entities.Silence{
+		ID:       uuid.New(),
+		IDs:      c.IDs,
+		Labels:   c.Labels,
+		StartsAt: c.StartsAt,
+		EndsAt:   c.EndsAt,
+		Comment:  c.Comment,
+	}
+}
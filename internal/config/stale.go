@@ -0,0 +1,26 @@
+package config
+
+import "time"
+
+// StaleConfig represents the configuration of the stale webhook policy.
+//
+// The webhooks that have not received a single heartbeat for the configured
+// time are reported in a periodic summary and in the administrative API.
+type StaleConfig struct {
+	// After is the time without heartbeats after which a webhook is stale.
+	//
+	// If it is zero, the policy is disabled.
+	//
+	// Example: "720h"
+	After time.Duration `yaml:"after"`
+
+	// Interval is the interval between two summaries of the stale webhooks.
+	Interval time.Duration `yaml:"interval"`
+
+	// Archive defines whether the stale webhooks are archived.
+	//
+	// The archived webhooks are removed from the running instance: they are
+	// neither tracked nor notified until they are removed from the configuration
+	// or the instance is restarted. They are still reported as stale.
+	Archive bool `yaml:"archive"`
+}
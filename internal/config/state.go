@@ -0,0 +1,30 @@
+package config
+
+import "time"
+
+// StateConfig represents the configuration of the persistence of the statuses
+// of the services.
+type StateConfig struct {
+	// File is the path to the file the statuses of the services are persisted to.
+	//
+	// The statuses survive restarts, so the services are not reported as
+	// recovered after every deployment, and the services that stop reporting
+	// while the server is down are reported once their status expires.
+	// If the path is empty, the statuses are kept in memory and are lost on restart.
+	//
+	// Example: "/var/lib/vakeel-way/state.json"
+	File string `yaml:"file"`
+
+	// Interval is the interval between the snapshots of the statuses.
+	//
+	// The statuses are also persisted on shutdown.
+	Interval time.Duration `yaml:"interval"`
+
+	// Incidents is the path to the file the open Instatus incidents are persisted to.
+	//
+	// The incidents opened before a restart are resolved once the services
+	// recover. If the path is empty, the incidents are kept in memory.
+	//
+	// Example: "/var/lib/vakeel-way/incidents.json"
+	Incidents string `yaml:"incidents"`
+}
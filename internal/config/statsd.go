@@ -0,0 +1,37 @@
+package config
+
+import "time"
+
+// StatsDConfig represents the configuration of the emission of the metrics to StatsD.
+//
+// The counters of the heartbeats, the status transitions and the deliveries,
+// and the timings of the deliveries, are pushed to a StatsD server or a
+// Datadog agent, as an alternative to scraping the Prometheus metrics.
+type StatsDConfig struct {
+	// Enabled specifies whether the metrics are emitted to StatsD.
+	Enabled bool `yaml:"enabled"`
+
+	// Address is the address of the server in the format "host:port".
+	//
+	// Example: "127.0.0.1:8125"
+	Address string `yaml:"address"`
+
+	// Prefix is prepended to the names of the metrics.
+	Prefix string `yaml:"prefix"`
+
+	// DogStatsD specifies whether the metrics are tagged with the DogStatsD
+	// extension, e.g. "vakeel_way.deliveries:1|c|#type:slack,result:success".
+	//
+	// Otherwise, the values of the tags are appended to the names of the
+	// metrics, e.g. "vakeel_way.deliveries.slack.success:1|c".
+	DogStatsD bool `yaml:"dogstatsd"`
+
+	// Tags are the tags of every metric in the format "key:value", sent with
+	// the DogStatsD extension only.
+	//
+	// Example: ["env:production"]
+	Tags []string `yaml:"tags"`
+
+	// Interval is the time between the batches of the metrics.
+	Interval time.Duration `yaml:"interval"`
+}
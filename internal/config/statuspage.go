@@ -0,0 +1,85 @@
+package config
+
+import (
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// StatusPageConfig represents the configuration of the public status page.
+//
+// The status page is a read-only HTML page and JSON document served on its
+// own listener. It groups the services into components and shows their
+// current status and uptime history, so the instance can replace a hosted
+// status page for internal use.
+type StatusPageConfig struct {
+	// Enabled defines whether the status page is served.
+	Enabled bool `yaml:"enabled"`
+
+	// Host is the host address to use for the status page.
+	Host string `yaml:"host"`
+
+	// Port is the port number to use for the status page.
+	Port string `yaml:"port"`
+
+	// Title is the title of the status page.
+	//
+	// Example: "ACME Status"
+	Title string `yaml:"title"`
+
+	// Days is the number of the days of the uptime history shown per component.
+	Days int `yaml:"days"`
+
+	// Cache is the time the rendered page is reused for, and the max-age of
+	// the Cache-Control header.
+	//
+	// Example: "1m"
+	Cache time.Duration `yaml:"cache"`
+
+	// Components is the list of the components of the status page, in the
+	// order they are shown.
+	Components []ComponentConfig `yaml:"components"`
+}
+
+// Addr returns the address of the status page in the format "host:port".
+func (c StatusPageConfig) Addr() string {
+	return net.JoinHostPort(c.Host, c.Port)
+}
+
+// Entities returns the components of the status page.
+//
+// Returns:
+//   - A slice of entities.Component.
+func (c StatusPageConfig) Entities() []entities.Component {
+	components := make([]entities.Component, 0, len(c.Components))
+
+	for _, component := range c.Components {
+		components = append(components, entities.Component(component))
+	}
+
+	return components
+}
+
+// ComponentConfig represents the configuration of a component of the status page.
+//
+// The services of the component are selected by their UUIDs and labels.
+type ComponentConfig struct {
+	// Name is the name of the component.
+	//
+	// Example: "API"
+	Name string `yaml:"name"`
+
+	// Description is the optional description of the component.
+	Description string `yaml:"description"`
+
+	// IDs are the UUIDs of the services of the component.
+	IDs []uuid.UUID `yaml:"ids"`
+
+	// Labels is the label selector of the services of the component.
+	//
+	// Example: {"tier": "api"}
+	Labels map[string]string `yaml:"labels"`
+}
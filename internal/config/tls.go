@@ -0,0 +1,35 @@
+package config
+
+// TLSConfig represents the configuration of the TLS of a listener.
+//
+// The certificate and the key are reloaded when their files change, so the
+// renewals of cert-manager or certbot take effect without a restart.
+type TLSConfig struct {
+	// CertFile is the path to the PEM certificate chain.
+	CertFile string `yaml:"cert_file"`
+
+	// KeyFile is the path to the PEM private key.
+	KeyFile string `yaml:"key_file"`
+}
+
+// Enabled reports whether the listener is served over TLS.
+func (c TLSConfig) Enabled() bool {
+	return c.CertFile != "" || c.KeyFile != ""
+}
+
+// ClientTLSConfig represents the configuration of the TLS of a connection to a server.
+type ClientTLSConfig struct {
+	// Enabled specifies whether the connection is made over TLS.
+	Enabled bool `yaml:"enabled"`
+
+	// CAFile is the path to the PEM CA certificates the certificate of the
+	// server is verified with, along with the system roots. It is not needed
+	// for a server with a certificate of a public CA, e.g. issued by ACME.
+	//
+	// Example: "/etc/vakeel-way/ca.crt"
+	CAFile string `yaml:"ca_file"`
+
+	// ServerName is the name the certificate of the server is verified
+	// against. If it is empty, the host of the address is used.
+	ServerName string `yaml:"server_name"`
+}
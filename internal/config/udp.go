@@ -0,0 +1,48 @@
+package config
+
+import (
+	"net"
+	"time"
+)
+
+// UDPConfig represents the configuration of the UDP listener.
+//
+// The reporters sending heartbeats at a very high rate, which can afford to
+// lose a few of them, send a single datagram holding the UUID of the service
+// per heartbeat instead of opening an Update stream.
+type UDPConfig struct {
+	// Enabled defines whether the heartbeats are received over UDP.
+	Enabled bool `yaml:"enabled"`
+
+	// Host is the host address to use for the UDP listener.
+	Host string `yaml:"host"`
+
+	// Port is the port number to use for the UDP listener.
+	Port string `yaml:"port"`
+
+	// ReadBuffer is the size in bytes of the receive buffer of the socket.
+	//
+	// The datagrams arriving while the buffer is full are dropped by the
+	// kernel, so the bursts of heartbeats need a larger buffer. If it is
+	// zero, the default of the operating system is used.
+	ReadBuffer int `yaml:"read_buffer"`
+
+	// Secret is the secret the datagrams are signed with, so only the
+	// reporters knowing it can report a heartbeat. It can be encrypted with
+	// the keyring.
+	//
+	// The datagrams carry no API token, so the ACLs, the namespaces and the
+	// quotas of the tokens do not apply to them. If the authentication is
+	// enabled, either the secret or the allowlist of the networks is required.
+	Secret string `yaml:"secret"`
+
+	// Window is the greatest difference between the time a datagram is
+	// signed at and the time it is received at, which bounds its replays.
+	// If it is zero, the default of a minute is used.
+	Window time.Duration `yaml:"window"`
+}
+
+// Addr returns the address of the UDP listener in the format "host:port".
+func (c UDPConfig) Addr() string {
+	return net.JoinHostPort(c.Host, c.Port)
+}
@@ -0,0 +1,621 @@
+package config
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// Webhooks is a slice of WebhookConfig.
+type Webhooks []WebhookConfig
+
+// AsMap converts the slice of WebhookConfig into a map.
+//
+// The function takes the slice of WebhookConfig as input and returns a map
+// with the ID of the WebhookConfig as the key and the list of its targets as the value.
+// The map is created with preallocated capacity to avoid resizing during iteration.
+//
+// Returns:
+// - A map[uuid.UUID][]entities.Target containing the converted data.
+func (w Webhooks) AsMap() map[uuid.UUID][]entities.Target {
+	// Create a map with preallocated capacity for the length of the slice.
+	// This is done to avoid resizing the map during the iteration.
+	m := make(map[uuid.UUID][]entities.Target, len(w))
+
+	// Iterate over each WebhookConfig in the slice.
+	// The range keyword is used to iterate over the slice and get the index and value.
+	for i := range w {
+		// Use the ID of the WebhookConfig as the key in the map,
+		// and the targets of the WebhookConfig as the value.
+		m[w[i].ID] = w[i].Entities()
+	}
+
+	// Return the WebhooksMap containing the converted data.
+	return m
+}
+
+// Labels returns the labels of the webhooks indexed by their IDs.
+//
+// The webhooks without labels are omitted.
+//
+// Returns:
+// - A map[uuid.UUID]map[string]string containing the labels of the webhooks.
+func (w Webhooks) Labels() map[uuid.UUID]map[string]string {
+	m := make(map[uuid.UUID]map[string]string, len(w))
+
+	for i := range w {
+		if len(w[i].Labels) > 0 {
+			m[w[i].ID] = w[i].Labels
+		}
+	}
+
+	return m
+}
+
+// Namespaces returns the namespaces of the webhooks indexed by their IDs.
+//
+// The webhooks of the default namespace are omitted.
+//
+// Returns:
+// - A map[uuid.UUID]string containing the namespaces of the webhooks.
+func (w Webhooks) Namespaces() map[uuid.UUID]string {
+	m := make(map[uuid.UUID]string, len(w))
+
+	for i := range w {
+		if w[i].Namespace != "" {
+			m[w[i].ID] = w[i].Namespace
+		}
+	}
+
+	return m
+}
+
+// Tolerances returns the timing tolerances of the webhooks indexed by their IDs.
+//
+// The webhooks with the default tolerance are omitted.
+//
+// Returns:
+// - A map[uuid.UUID]entities.Tolerance containing the tolerances of the webhooks.
+func (w Webhooks) Tolerances() map[uuid.UUID]entities.Tolerance {
+	m := make(map[uuid.UUID]entities.Tolerance, len(w))
+
+	for i := range w {
+		// The schedules are validated when the configuration is loaded.
+		schedule, _ := w[i].ParseSchedule()
+
+		if w[i].Jitter > 0 || w[i].Skew > 0 || schedule != nil || w[i].Missed > 1 {
+			m[w[i].ID] = entities.Tolerance{
+				Jitter:   w[i].Jitter,
+				Skew:     w[i].Skew,
+				Schedule: schedule,
+				Grace:    w[i].Grace,
+				Missed:   w[i].Missed,
+			}
+		}
+	}
+
+	return m
+}
+
+// ParseSchedule parses the cron expression of the webhook.
+//
+// Returns:
+// - The schedule of the webhook, or nil if it has none.
+// - An error if the cron expression is invalid.
+func (c WebhookConfig) ParseSchedule() (entities.Schedule, error) {
+	if c.Schedule == "" {
+		return nil, nil //nolint:nilnil
+	}
+
+	return cron.ParseStandard(c.Schedule)
+}
+
+// Dependencies returns the IDs of the dependencies of the webhooks indexed by their IDs.
+//
+// The webhooks without dependencies are omitted.
+//
+// Returns:
+// - A map[uuid.UUID][]uuid.UUID containing the dependencies of the webhooks.
+func (w Webhooks) Dependencies() map[uuid.UUID][]uuid.UUID {
+	m := make(map[uuid.UUID][]uuid.UUID, len(w))
+
+	for i := range w {
+		if len(w[i].DependsOn) > 0 {
+			m[w[i].ID] = w[i].DependsOn
+		}
+	}
+
+	return m
+}
+
+// AgentConfigs returns the configuration of the agents of the webhooks indexed by their IDs.
+//
+// The webhooks without the configuration of the agents are omitted.
+//
+// Returns:
+// - A map[uuid.UUID]entities.AgentConfig containing the configuration of the agents.
+func (w Webhooks) AgentConfigs() map[uuid.UUID]entities.AgentConfig {
+	m := make(map[uuid.UUID]entities.AgentConfig, len(w))
+
+	for i := range w {
+		agent := entities.AgentConfig{Interval: w[i].Interval, Features: w[i].Features}
+		if !agent.Empty() {
+			m[w[i].ID] = agent
+		}
+	}
+
+	return m
+}
+
+// Badges returns the configuration of the uptime badges of the webhooks indexed by their IDs.
+//
+// The webhooks without the configuration of the badge are omitted.
+//
+// Returns:
+// - A map[uuid.UUID]entities.Badge containing the configuration of the badges.
+func (w Webhooks) Badges() map[uuid.UUID]entities.Badge {
+	m := make(map[uuid.UUID]entities.Badge, len(w))
+
+	for i := range w {
+		if w[i].Badge != (BadgeConfig{}) {
+			m[w[i].ID] = entities.Badge(w[i].Badge)
+		}
+	}
+
+	return m
+}
+
+// WebhookConfig represents the configuration for the webhook.
+//
+// It contains the unique identifier and the target URL of the webhook.
+type WebhookConfig struct {
+	// ID is the unique identifier for the webhook.
+	//
+	// The ID is a string that uniquely identifies the webhook.
+	// It is used to distinguish between different webhooks.
+	ID uuid.UUID `yaml:"id"`
+
+	// Namespace is the namespace the webhook belongs to.
+	//
+	// The UUID of the webhook is visible only to the API tokens of the same
+	// namespace. The webhooks of the namespaces section get it set
+	// automatically. If it is empty, the webhook belongs to the default
+	// namespace, visible to the tokens without a namespace only.
+	Namespace string `yaml:"namespace"`
+
+	// Target is the target URL of the webhook.
+	//
+	// The target URL is the URL that will be notified when an event is triggered.
+	// It should be a valid URL that the webhook can reach. URLs with embedded
+	// credentials can be stored encrypted (see `vakeel-way secrets encrypt`).
+	// The hosts of the "http+srv" and "https+srv" URLs are resolved with SRV records.
+	//
+	// Example: "https://example.com/webhook"
+	Target string `yaml:"target"`
+
+	// Targets is the list of the notification targets of the webhook.
+	//
+	// Every target is notified independently, so a failing Slack webhook does
+	// not prevent the Instatus status from being updated. The Target field is
+	// a shorthand for a single Instatus target.
+	Targets []TargetConfig `yaml:"targets"`
+
+	// Component is the Instatus component whose status is updated.
+	//
+	// If it is set, the Instatus targets of the webhook update the status of
+	// the component or open incidents through the Instatus API instead of
+	// triggering the automation webhook, unless the target has its own component.
+	// The Target URL is not needed then and is ignored.
+	Component InstatusComponentConfig `yaml:"component"`
+
+	// Secret is the key the notifications of the webhook are signed with.
+	//
+	// If it is set, the generic webhook notifier adds the X-Signature header
+	// with the HMAC-SHA256 of the request body, so the receiver can verify that
+	// the notification comes from vakeel-way. It can be stored encrypted.
+	Secret string `yaml:"secret"`
+
+	// Method is the HTTP method of the requests of the generic webhook notifier.
+	//
+	// It overrides the POST request for all targets of the webhook, unless the
+	// target has its own method, e.g. for receivers that expect PUT.
+	//
+	// Example: "PUT"
+	Method string `yaml:"method"`
+
+	// Body is the text/template source of the request body of the generic
+	// webhook notifier.
+	//
+	// It overrides the {"trigger": "<status>"} payload for all targets of the
+	// webhook, unless the target has its own body. The template is executed
+	// with the event as data, like the message templates; the rendered message
+	// is available as .Message.
+	//
+	// Example: "status={{ .Status }}&message={{ urlquery .Message }}"
+	Body string `yaml:"body"`
+
+	// Format is the format of the request body of the generic webhook notifier.
+	//
+	// The possible values are:
+	// - "" for the {"trigger": "<status>"} payload of Instatus (default)
+	// - "cloudevents" for the CloudEvents 1.0 structured JSON envelope, sent
+	//   as application/cloudevents+json, so the notifications can be routed
+	//   through the Knative or EventBridge style brokers
+	//
+	// It applies to all targets of the webhook, unless the target has its own
+	// format. It cannot be combined with a body template.
+	Format string `yaml:"format"`
+
+	// ContentType is the media type of the request body of the generic webhook notifier.
+	//
+	// It overrides application/json for all targets of the webhook, unless the
+	// target has its own media type.
+	//
+	// Example: "application/x-www-form-urlencoded"
+	ContentType string `yaml:"content_type"`
+
+	// Template is the text/template source of the notification messages of the webhook.
+	//
+	// It overrides the global template for all targets of the webhook, unless the
+	// target has its own template.
+	Template string `yaml:"template"`
+
+	// Labels is the set of the labels of the webhook.
+	//
+	// The labels are used by the label selectors of the silences, e.g. to
+	// silence every service labeled "env: staging" during a maintenance window.
+	// The "name" label names the service in the metrics of its status.
+	Labels map[string]string `yaml:"labels"`
+
+	// Jitter is the extra time a heartbeat of the service may be late before the
+	// service is considered down.
+	//
+	// It is useful for the agents with an irregular heartbeat interval, e.g. cron
+	// jobs that take a variable time to start.
+	//
+	// Example: "30s"
+	Jitter time.Duration `yaml:"jitter"`
+
+	// Skew is the clock-skew tolerance of the agents of the service.
+	//
+	// The heartbeats timestamped by the agent within the skew from the server
+	// clock are accounted as received on time, so the agents on hosts with
+	// drifting clocks are not misclassified.
+	//
+	// Example: "5s"
+	Skew time.Duration `yaml:"skew"`
+
+	// Schedule is the cron expression of the times the service checks in at.
+	//
+	// The service is expected to send a heartbeat at every scheduled time and
+	// is considered down only when it misses one by more than the grace time.
+	// It is useful for the batch jobs that report once per run. The standard
+	// five-field expressions, the descriptors such as "@hourly" and the
+	// "CRON_TZ=" prefix are supported.
+	//
+	// Example: "*/5 * * * *"
+	Schedule string `yaml:"schedule"`
+
+	// DependsOn is the list of the IDs of the webhooks the service depends on.
+	//
+	// When the service goes down while one of its dependencies is down, the
+	// notification follows the cascade policy of the notifications, so a
+	// failing database does not page about every application using it.
+	//
+	// Example: ["7b1f6a0e-3c2d-4e5f-8a9b-0c1d2e3f4a5b"]
+	DependsOn []uuid.UUID `yaml:"depends_on"`
+
+	// Grace is the time a scheduled heartbeat may be late before the service
+	// is considered down. It defaults to a minute.
+	//
+	// Example: "5m"
+	Grace time.Duration `yaml:"grace"`
+
+	// Missed is the number of the consecutive intervals the service may miss
+	// before it is considered down.
+	//
+	// Every interval lasts the TTL of the status, or runs until the next
+	// scheduled time plus the grace time for the scheduled services. It reduces
+	// the false positives caused by a single lost heartbeat or a short pause of
+	// the agent. If it is zero or one, the service is down once its status expires.
+	//
+	// Example: 3
+	Missed int `yaml:"missed"`
+
+	// Interval is the desired interval between two heartbeats of the service.
+	//
+	// It is pushed to the connected agents over their UpdateStream calls, so the
+	// interval can be changed without redeploying them.
+	//
+	// Example: "30s"
+	Interval time.Duration `yaml:"interval"`
+
+	// Features is the list of the feature flags pushed to the connected agents.
+	Features []string `yaml:"features"`
+
+	// Badge is the configuration of the uptime badge of the webhook.
+	//
+	// The unset fields default to the "uptime" label, the "flat" theme and the
+	// 99.9% and 99% thresholds over 30 days.
+	Badge BadgeConfig `yaml:"badge"`
+}
+
+// BadgeConfig represents the configuration of the uptime badge of a webhook.
+type BadgeConfig struct {
+	// Label is the text on the left side of the badge.
+	Label string `yaml:"label"`
+
+	// Theme is the theme of the badge.
+	//
+	// The possible values are:
+	// - "flat" (default)
+	// - "flat-square"
+	// - "for-the-badge"
+	Theme string `yaml:"theme"`
+
+	// Good is the uptime percentage at or above which the badge is green.
+	//
+	// Example: 99.9
+	Good float64 `yaml:"good"`
+
+	// Warn is the uptime percentage at or above which the badge is yellow.
+	// The badge is red below it.
+	//
+	// Example: 99
+	Warn float64 `yaml:"warn"`
+
+	// Window is the period the uptime is calculated over.
+	//
+	// Example: "720h"
+	Window time.Duration `yaml:"window"`
+}
+
+// Entities returns the notification targets of the webhook.
+//
+// The legacy Target field, or the Instatus component of the webhook without
+// other targets, is converted into an Instatus target named "instatus".
+// Targets without a name are named after their type and position.
+//
+// Returns:
+// - A slice of entities.Target.
+func (c WebhookConfig) Entities() []entities.Target {
+	targets := make([]entities.Target, 0, len(c.Targets)+1)
+
+	// The component of the webhook is updated through the Instatus API, so the
+	// legacy target does not need the URL of the automation webhook.
+	component := c.Component.ID != "" || c.Component.Incident != ""
+
+	// Convert the legacy single target.
+	if c.Target != "" || (component && len(c.Targets) == 0) {
+		url := c.Target
+		if component {
+			url = ""
+		}
+
+		targets = append(targets, entities.Target{
+			Name:        TargetInstatus,
+			Type:        TargetInstatus,
+			URL:         url,
+			RoutingKey:  "",
+			Token:       c.Component.Token,
+			Page:        c.Component.Page,
+			Component:   c.Component.ID,
+			Statuses:    c.Component.Statuses,
+			Title:       c.Component.Incident,
+			Secret:      c.Secret,
+			Template:    c.Template,
+			Method:      c.Method,
+			Body:        c.Body,
+			Format:      c.Format,
+			ContentType: c.ContentType,
+			After:       0,
+			Throttle:    0,
+			Warnings:    false,
+		})
+	}
+
+	// Convert the list of targets.
+	for i, target := range c.Targets {
+		if target.Type == "" {
+			target.Type = TargetInstatus
+		}
+
+		if target.Template == "" {
+			target.Template = c.Template
+		}
+
+		if target.Name == "" {
+			target.Name = fmt.Sprintf("%s-%d", target.Type, i)
+		}
+
+		if target.Type == TargetInstatus && target.Component.ID == "" && target.Component.Incident == "" {
+			target.Component = c.Component
+		}
+
+		if target.Secret == "" {
+			target.Secret = c.Secret
+		}
+
+		if target.Method == "" {
+			target.Method = c.Method
+		}
+
+		if target.Body == "" {
+			target.Body = c.Body
+		}
+
+		if target.Format == "" {
+			target.Format = c.Format
+		}
+
+		if target.ContentType == "" {
+			target.ContentType = c.ContentType
+		}
+
+		targets = append(targets, entities.Target{
+			Name:        target.Name,
+			Type:        target.Type,
+			URL:         target.URL,
+			RoutingKey:  target.RoutingKey,
+			Token:       target.Component.Token,
+			Page:        target.Component.Page,
+			Component:   target.Component.ID,
+			Statuses:    target.Component.Statuses,
+			Title:       target.Component.Incident,
+			Secret:      target.Secret,
+			Template:    target.Template,
+			Method:      target.Method,
+			Body:        target.Body,
+			Format:      target.Format,
+			ContentType: target.ContentType,
+			After:       target.After,
+			Throttle:    target.Throttle,
+			Warnings:    target.Warnings,
+		})
+	}
+
+	return targets
+}
+
+// TargetInstatus is the type of the Instatus notification target.
+const TargetInstatus = "instatus"
+
+// InstatusComponentConfig represents the configuration of an Instatus component.
+type InstatusComponentConfig struct {
+	// Page is the identifier of the Instatus page of the component.
+	Page string `yaml:"page"`
+
+	// ID is the identifier of the component.
+	ID string `yaml:"id"`
+
+	// Token is the Instatus API key.
+	//
+	// It can be stored encrypted.
+	Token string `yaml:"token"`
+
+	// Statuses maps the statuses of the service to the statuses of the component.
+	//
+	// The keys are "up", "down" and "degraded"; the values are the Instatus
+	// component statuses: "OPERATIONAL", "UNDERMAINTENANCE",
+	// "DEGRADEDPERFORMANCE", "PARTIALOUTAGE" and "MAJOROUTAGE". By default the
+	// services that are up are operational, the degraded ones have degraded
+	// performance and the ones that are down have a major outage.
+	//
+	// Example: {"down": "PARTIALOUTAGE"}
+	Statuses map[string]string `yaml:"statuses"`
+
+	// Incident is the text/template source of the title of the incidents.
+	//
+	// If it is set, an incident is opened on the page when the service goes
+	// down and resolved when it recovers; the component, if any, is attached
+	// to the incident. The message of the notification is the message of the
+	// incident. The identifiers of the open incidents are tracked in the state
+	// (see state.incidents).
+	//
+	// Example: "{{ .Agent.Hostname }} is down"
+	Incident string `yaml:"incident"`
+}
+
+// TargetConfig represents the configuration of a single notification target.
+type TargetConfig struct {
+	// Name is the name of the target, unique within the webhook.
+	//
+	// It is optional; by default the target is named after its type and position.
+	Name string `yaml:"name"`
+
+	// Type is the type of the target.
+	//
+	// The possible values are:
+	// - "instatus" for Instatus automation webhooks (default)
+	// - "slack" for Slack incoming webhooks
+	// - "pagerduty" for PagerDuty Events API v2
+	// - "kuma" for Uptime Kuma push monitors; the URL is the push URL of the
+	//   monitor. The status is pushed on every transition only, so the
+	//   heartbeat interval of the monitor has to be longer than the expected
+	//   time between the transitions, e.g. a day
+	// - "alertmanager" for Prometheus Alertmanager; the URL is the base URL of
+	//   Alertmanager, e.g. "http://alertmanager:9093". The alerts fire until
+	//   the service recovers
+	// - "oncall" for Grafana OnCall; the URL is the URL of a formatted webhook
+	//   integration. The alerts of a service share the alert_uid, so they
+	//   form one alert group, which is resolved when the service recovers
+	// - "capture" for the built-in capture notifier, which keeps the rendered
+	//   notifications instead of delivering them (see delivery.capture)
+	Type string `yaml:"type"`
+
+	// URL is the URL the notification is delivered to.
+	//
+	// It is optional for PagerDuty, which uses the public Events API endpoint by
+	// default, and for the Instatus components, which use the public Instatus
+	// API. It can be stored encrypted. With the "http+srv" or "https+srv"
+	// scheme, the host is the name of the SRV records of the receivers, e.g.
+	// "https+srv://_hooks._tcp.example.com/notify".
+	URL string `yaml:"url"`
+
+	// RoutingKey is the integration key of the PagerDuty service.
+	//
+	// It can be stored encrypted.
+	RoutingKey string `yaml:"routing_key"`
+
+	// Component is the Instatus component whose status is updated by the target.
+	//
+	// It overrides the component of the webhook for the target. It is used to
+	// update the status of the component or to open incidents.
+	Component InstatusComponentConfig `yaml:"component"`
+
+	// Secret is the key the notifications of the target are signed with.
+	//
+	// It overrides the secret of the webhook for the target. It can be stored encrypted.
+	Secret string `yaml:"secret"`
+
+	// Template is the text/template source of the notification messages of the target.
+	Template string `yaml:"template"`
+
+	// Method is the HTTP method of the requests of the target.
+	//
+	// It is supported by the "instatus" (generic webhook) targets only.
+	Method string `yaml:"method"`
+
+	// Body is the text/template source of the request body of the target.
+	//
+	// It is supported by the "instatus" (generic webhook) targets only.
+	Body string `yaml:"body"`
+
+	// Format is the format of the request body of the target, e.g. "cloudevents".
+	//
+	// It is supported by the "instatus" (generic webhook) targets only.
+	Format string `yaml:"format"`
+
+	// ContentType is the media type of the request body of the target.
+	//
+	// It is supported by the "instatus" (generic webhook) targets only.
+	ContentType string `yaml:"content_type"`
+
+	// After is the time the service has to be down before the target is notified.
+	//
+	// It is used to build escalation policies: e.g. Slack is notified immediately,
+	// PagerDuty only if the service is still down after 10 minutes. The escalation
+	// is canceled if the service recovers before the delay.
+	//
+	// Example: "10m"
+	After time.Duration `yaml:"after"`
+
+	// Throttle is the minimum time between two notifications with the same status.
+	//
+	// It overrides the global throttle window of the notifications for the target.
+	//
+	// Example: "10m"
+	Throttle time.Duration `yaml:"throttle"`
+
+	// Warnings enables the early warnings of the anomaly detector for the target.
+	//
+	// The warnings are sent when the heartbeats of the service are late
+	// compared to its usual cadence, before its status expires. They carry the
+	// current status of the service, so they should only be enabled for the
+	// targets relaying the message, e.g. "slack" or "webhook".
+	Warnings bool `yaml:"warnings"`
+}
@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"slices"
+	"time"
+)
+
+// AgentConfig represents the configuration pushed to the agents of a service.
+//
+// It lets the operators change the behavior of the agents in the configuration
+// of the server instead of redeploying them.
+type AgentConfig struct {
+	// Interval is the desired interval between two heartbeats of the service.
+	//
+	// If it is zero, the agents keep their own interval.
+	Interval time.Duration
+
+	// Features is the list of the feature flags of the agents of the service.
+	Features []string
+}
+
+// Empty reports whether nothing is configured for the agents.
+func (c AgentConfig) Empty() bool {
+	return c.Interval == 0 && len(c.Features) == 0
+}
+
+// Equal reports whether the configurations are the same.
+func (c AgentConfig) Equal(other AgentConfig) bool {
+	return c.Interval == other.Interval && slices.Equal(c.Features, other.Features)
+}
+
+// Agent represents the agent reporting the heartbeats of a service, as
+// introduced in the handshake of its Update stream.
+type Agent struct {
+	// Hostname is the hostname of the machine the agent runs on.
+	Hostname string
+
+	// Version is the version of the agent.
+	Version string
+
+	// Labels are the free-form labels of the agent, e.g. its region.
+	Labels map[string]string
+}
+
+// Empty reports whether the agent has not introduced itself.
+func (a Agent) Empty() bool {
+	return a.Hostname == "" && a.Version == "" && len(a.Labels) == 0
+}
@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HeartbeatRecord represents a heartbeat received by the server, as kept by
+// the audit log.
+type HeartbeatRecord struct {
+	// ID is the UUID of the service.
+	ID uuid.UUID
+
+	// At is the time the heartbeat was received.
+	At time.Time
+
+	// Peer is the network address of the agent, e.g. "10.0.0.7:51234".
+	Peer string
+
+	// Token is the name of the API token of the agent, or empty if it is anonymous.
+	Token string
+
+	// Agent is the hostname introduced in the handshake of the agent, or empty.
+	Agent string
+
+	// Result is the result of the heartbeat, e.g. "accepted" or "unknown".
+	Result string
+}
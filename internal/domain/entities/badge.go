@@ -0,0 +1,134 @@
+package entities
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrUnknownTheme is returned when the theme of the badge is not supported.
+var ErrUnknownTheme = errors.New("unknown badge theme")
+
+// The themes of the uptime badges.
+const (
+	// ThemeFlat is the default theme with rounded corners.
+	ThemeFlat = "flat"
+
+	// ThemeFlatSquare is the flat theme with square corners.
+	ThemeFlatSquare = "flat-square"
+
+	// ThemeForTheBadge is the large theme with the uppercase text.
+	ThemeForTheBadge = "for-the-badge"
+)
+
+// The colors of the uptime badges.
+const (
+	// ColorGood is the color of the uptime that complies with the SLO.
+	ColorGood = "#4c1"
+
+	// ColorWarn is the color of the uptime that is close to breaking the SLO.
+	ColorWarn = "#dfb317"
+
+	// ColorBad is the color of the uptime that breaks the SLO.
+	ColorBad = "#e05d44"
+
+	// ColorUnknown is the color of the service without the status history.
+	ColorUnknown = "#9f9f9f"
+)
+
+// Badge represents the configuration of the uptime badge of a service.
+//
+// The badge shows the uptime of the service over the window, colored by the
+// thresholds, so it communicates the SLO compliance, not just the current status.
+type Badge struct {
+	// Label is the text on the left side of the badge.
+	Label string
+
+	// Theme is the theme of the badge.
+	Theme string
+
+	// Good is the uptime percentage at or above which the badge is green.
+	Good float64
+
+	// Warn is the uptime percentage at or above which the badge is yellow.
+	// The badge is red below it.
+	Warn float64
+
+	// Window is the period the uptime is calculated over.
+	Window time.Duration
+}
+
+// DefaultBadge returns the configuration of the badges without their own settings.
+//
+// Returns:
+//   - The badge labeled "uptime" over 30 days, green at 99.9% and yellow at 99%.
+func DefaultBadge() Badge {
+	return Badge{
+		Label:  "uptime",
+		Theme:  ThemeFlat,
+		Good:   99.9,
+		Warn:   99,
+		Window: 30 * 24 * time.Hour,
+	}
+}
+
+// Merge returns the badge with the unset fields taken from the defaults.
+//
+// Parameters:
+//   - defaults: The badge the unset fields are taken from.
+//
+// Returns:
+//   - The merged badge.
+func (b Badge) Merge(defaults Badge) Badge {
+	if b.Label == "" {
+		b.Label = defaults.Label
+	}
+
+	if b.Theme == "" {
+		b.Theme = defaults.Theme
+	}
+
+	if b.Good == 0 {
+		b.Good = defaults.Good
+	}
+
+	if b.Warn == 0 {
+		b.Warn = defaults.Warn
+	}
+
+	if b.Window == 0 {
+		b.Window = defaults.Window
+	}
+
+	return b
+}
+
+// Validate checks that the theme is supported.
+//
+// Returns:
+//   - ErrUnknownTheme if the theme is not supported.
+func (b Badge) Validate() error {
+	switch b.Theme {
+	case ThemeFlat, ThemeFlatSquare, ThemeForTheBadge:
+		return nil
+	default:
+		return ErrUnknownTheme
+	}
+}
+
+// Color returns the color of the uptime.
+//
+// Parameters:
+//   - uptime: The uptime percentage.
+//
+// Returns:
+//   - ColorGood, ColorWarn or ColorBad depending on the thresholds.
+func (b Badge) Color(uptime float64) string {
+	switch {
+	case uptime >= b.Good:
+		return ColorGood
+	case uptime >= b.Warn:
+		return ColorWarn
+	default:
+		return ColorBad
+	}
+}
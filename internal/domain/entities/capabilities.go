@@ -0,0 +1,25 @@
+package entities
+
+// Capabilities describes the features enabled on the instance.
+//
+// It is reported to the agents, SDKs, and dashboards, so they can adapt their
+// behavior to the instance.
+type Capabilities struct {
+	// Version is the version of the server.
+	Version string `json:"version"`
+
+	// Protocols is the list of the supported protocol versions, e.g. "v1".
+	Protocols []string `json:"protocol_versions"`
+
+	// Notifiers is the list of the supported target types, e.g. "slack".
+	Notifiers []string `json:"notifiers"`
+
+	// Storage is the storage backend of the webhooks, e.g. "config".
+	Storage string `json:"storage"`
+
+	// Auth is the authentication mode: "none" or "token".
+	Auth string `json:"auth_mode"`
+
+	// Features is the list of the optional features enabled on the instance.
+	Features []string `json:"features"`
+}
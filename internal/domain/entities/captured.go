@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// CapturedNotification represents a notification kept by the capture notifier
+// instead of being delivered.
+type CapturedNotification struct {
+	// At is the time the notification was captured.
+	At time.Time
+
+	// Target is the name of the target the notification was addressed to.
+	Target string
+
+	// Event is the event with the rendered message.
+	Event Event
+}
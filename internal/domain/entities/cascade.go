@@ -0,0 +1,58 @@
+package entities
+
+import "errors"
+
+// ErrUnknownCascade is an error that indicates that the cascade policy is not recognized.
+var ErrUnknownCascade = errors.New("unknown cascade policy")
+
+// Cascade represents what happens to the notification of a service that goes
+// down while a service it depends on is down, e.g. an application whose
+// database is down.
+type Cascade uint8
+
+// Cascade constants represent different cascade policies.
+const (
+	// Suppress represents a policy that holds the notification back until the
+	// dependency recovers. If the service is still down then, it is notified.
+	Suppress Cascade = iota
+	// Tag represents a policy that sends the notification with the dependency
+	// that is down as its cause.
+	Tag
+)
+
+// String returns the string representation of the cascade policy.
+//
+// It returns "suppress" or "tag" for the known policies, and "Undefined" for
+// any other value.
+func (c Cascade) String() string {
+	// Check the policy and return the corresponding string.
+	switch c {
+	case Suppress:
+		return "suppress"
+	case Tag:
+		return "tag"
+	default:
+		return "Undefined"
+	}
+}
+
+// ParseCascade converts the string representation of a cascade policy into a Cascade.
+//
+// An empty string is treated as Suppress.
+//
+// Parameters:
+//   - s: The string representation of the cascade policy.
+//
+// Returns:
+//   - The parsed Cascade.
+//   - ErrUnknownCascade if the string does not represent a known policy.
+func ParseCascade(s string) (Cascade, error) {
+	switch s {
+	case "", Suppress.String():
+		return Suppress, nil
+	case Tag.String():
+		return Tag, nil
+	default:
+		return Suppress, ErrUnknownCascade
+	}
+}
@@ -0,0 +1,79 @@
+package entities
+
+import (
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// ComponentStatus represents the aggregated status of a component of the status page.
+type ComponentStatus string
+
+const (
+	// ComponentOperational means that every service of the component is up.
+	ComponentOperational ComponentStatus = "operational"
+
+	// ComponentDegraded means that some services of the component are down.
+	ComponentDegraded ComponentStatus = "degraded"
+
+	// ComponentOutage means that every service of the component with a known status is down.
+	ComponentOutage ComponentStatus = "outage"
+
+	// ComponentUnknown means that the status of no service of the component is known.
+	ComponentUnknown ComponentStatus = "unknown"
+)
+
+// Component represents a group of services shown as a single entry of the status page.
+type Component struct {
+	// Name is the name of the component, e.g. "API".
+	Name string
+
+	// Description is the optional description of the component.
+	Description string
+
+	// IDs are the UUIDs of the services of the component.
+	IDs []uuid.UUID
+
+	// Labels is the label selector of the services of the component.
+	Labels map[string]string
+}
+
+// Matches reports whether the service belongs to the component.
+//
+// The service belongs to the component if it is listed by its UUID, or if the
+// component has a label selector and the service has every label of it.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//   - labels: The labels of the service.
+//
+// Returns:
+//   - true if the service belongs to the component.
+func (c Component) Matches(id uuid.UUID, labels map[string]string) bool {
+	if slices.Contains(c.IDs, id) {
+		return true
+	}
+
+	return len(c.Labels) > 0 && MatchLabels(c.Labels, labels)
+}
+
+// Aggregate returns the status of a component from the number of its services.
+//
+// Parameters:
+//   - up: The number of the services that are up.
+//   - down: The number of the services that are down.
+//
+// Returns:
+//   - The aggregated status of the component.
+func Aggregate(up, down int) ComponentStatus {
+	switch {
+	case up == 0 && down == 0:
+		return ComponentUnknown
+	case down == 0:
+		return ComponentOperational
+	case up == 0:
+		return ComponentOutage
+	default:
+		return ComponentDegraded
+	}
+}
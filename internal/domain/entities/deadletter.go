@@ -0,0 +1,32 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DeadLetter represents a notification that could not be delivered.
+//
+// The notifications whose retries are exhausted, or that were discarded from
+// a full queue, are kept as dead letters until an operator replays or
+// discards them, so they are never lost silently.
+type DeadLetter struct {
+	// ID is the unique identifier of the dead letter.
+	ID uuid.UUID
+
+	// Target is the name of the target the notification was addressed to.
+	Target string
+
+	// Event is the undelivered event.
+	Event Event
+
+	// Error is the description of the last failure.
+	Error string
+
+	// Attempts is the number of the delivery attempts made.
+	Attempts int
+
+	// At is the time the notification was given up on.
+	At time.Time
+}
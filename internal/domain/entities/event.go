@@ -0,0 +1,110 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventKind represents the kind of a notification.
+type EventKind uint8
+
+// EventKind constants represent different kinds of the notifications.
+const (
+	// KindTransition is a status transition of the service.
+	KindTransition EventKind = iota
+	// KindWarning is an early warning that the heartbeats of the service are
+	// late compared to its usual cadence, while its status has not expired yet.
+	KindWarning
+)
+
+// String returns the name of the kind, "transition" or "warning".
+func (k EventKind) String() string {
+	if k == KindWarning {
+		return "warning"
+	}
+
+	return "transition"
+}
+
+// Event represents a status transition of a service that is delivered to the targets.
+type Event struct {
+	// Kind is the kind of the notification.
+	//
+	// The early warnings carry the current status of the service and are
+	// only delivered to the targets that opt in to them.
+	Kind EventKind
+
+	// ID is the UUID of the service.
+	ID uuid.UUID
+
+	// Status is the new status of the service.
+	Status Status
+
+	// Time is the time of the transition.
+	Time time.Time
+
+	// Since is the time the previous status began, or zero if it is unknown.
+	//
+	// For an Up event it is the time the service went down.
+	Since time.Time
+
+	// LastSeen is the time of the last heartbeat of the service.
+	LastSeen time.Time
+
+	// Message is the human-readable description of the event rendered from the
+	// template of the target. It is empty if no template is rendered.
+	Message string
+
+	// Reason is the explanation of the status reported by the agent with the
+	// heartbeat, e.g. why the service is degraded. It is empty if the agent
+	// has not reported any.
+	Reason string
+
+	// Cause is the UUID of the service the service depends on that was down
+	// when the service went down, or uuid.Nil if the downtime has no known cause.
+	Cause uuid.UUID
+
+	// Missed is the list of the earlier transitions that were queued while the
+	// target was unavailable, oldest first. It is empty for regular deliveries.
+	//
+	// When the target recovers, it receives a single catch-up event with the
+	// latest transition and the missed ones.
+	Missed []Event
+
+	// Unreachable reports whether the service kept sending heartbeats while it
+	// was down, but they were delayed by the network, e.g. buffered by a relay.
+	//
+	// It is set for an Up event only: the downtime was caused by the network
+	// between the service and the server, not by the service itself.
+	Unreachable bool
+
+	// Agent is the agent that reported the last heartbeat of the service, or
+	// the zero Agent if it has not introduced itself.
+	Agent Agent
+
+	// RequestID is the ID of the request whose heartbeat caused the
+	// transition, so the notification can be traced back to the agent. It is
+	// empty for the transitions without a heartbeat, e.g. when the status expires.
+	RequestID string
+}
+
+// Warning reports whether the event is an early warning rather than a status transition.
+func (e Event) Warning() bool {
+	return e.Kind == KindWarning
+}
+
+// Caused reports whether the downtime is caused by a dependency of the service.
+func (e Event) Caused() bool {
+	return e.Cause != uuid.Nil
+}
+
+// Duration returns the time the service spent in the previous status,
+// or zero if it is unknown.
+func (e Event) Duration() time.Duration {
+	if e.Since.IsZero() {
+		return 0
+	}
+
+	return e.Time.Sub(e.Since)
+}
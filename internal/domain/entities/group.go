@@ -0,0 +1,62 @@
+package entities
+
+import (
+	"github.com/google/uuid"
+)
+
+// Group represents a named component made of several services.
+//
+// The status of the group is aggregated from the statuses of its members, and
+// the targets of the group are notified once per transition of the group
+// instead of once per member.
+type Group struct {
+	// ID is the UUID of the webhook notified about the group.
+	ID uuid.UUID
+
+	// Name is the name of the component, e.g. "payments".
+	Name string
+
+	// Members is the list of the UUIDs of the services of the group.
+	Members []uuid.UUID
+
+	// Quorum is the number of the members that have to be down for the group
+	// to be down. If it is zero, any member that is down brings the group down.
+	Quorum int
+}
+
+// Status aggregates the statuses of the members into the status of the group.
+//
+// The group is down when at least the quorum of its members is down, degraded
+// when a member is down or degraded below the quorum, and up otherwise. The
+// members whose status is unknown are not counted.
+//
+// Parameters:
+//   - statuses: The known statuses of the members.
+//
+// Returns:
+//   - The status of the group.
+//   - The number of the members that are down.
+func (g Group) Status(statuses []Status) (Status, int) {
+	quorum := max(g.Quorum, 1)
+
+	var down, degraded int
+
+	for _, status := range statuses {
+		switch status {
+		case Down:
+			down++
+		case Degraded:
+			degraded++
+		case Up:
+		}
+	}
+
+	switch {
+	case down >= quorum:
+		return Down, down
+	case down > 0 || degraded > 0:
+		return Degraded, down
+	default:
+		return Up, down
+	}
+}
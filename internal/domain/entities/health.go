@@ -0,0 +1,10 @@
+package entities
+
+// Probe represents the result of a readiness probe of a dependency of the instance.
+type Probe struct {
+	// Name is the name of the dependency, e.g. "grpc".
+	Name string
+
+	// Err is the reason the dependency is not ready, or nil if it is ready.
+	Err error
+}
@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Heartbeat represents a heartbeat reported by the agent of a service.
+//
+// The agents speaking the first version of the API only report that the
+// service exists, which is a heartbeat with the status Up, no message and
+// the default TTL.
+type Heartbeat struct {
+	// ID is the UUID of the service.
+	ID uuid.UUID
+
+	// Status is the status reported by the agent, Up or Degraded.
+	Status Status
+
+	// Message is the human-readable explanation of the status reported by the
+	// agent, e.g. why the service is degraded. It may be empty.
+	Message string
+
+	// TTL is the time the agent proposes to keep the status without a heartbeat.
+	//
+	// If it is zero, the TTL configured on the server is used.
+	TTL time.Duration
+
+	// RequestID is the ID of the request that carried the heartbeat, or empty
+	// if the heartbeat was not received through the gRPC API.
+	RequestID string
+
+	// Sent is the time the agent sent the heartbeat at, by its own clock, or
+	// zero if the transport does not carry it. It is reconciled with the
+	// server clock by the Skew of the Tolerance of the service.
+	Sent time.Time
+}
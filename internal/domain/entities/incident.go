@@ -0,0 +1,72 @@
+package entities
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Incident represents a downtime of a service: the interval between the time
+// the service went down and the time it stopped being down.
+type Incident struct {
+	// ID is the UUID of the service.
+	ID uuid.UUID
+
+	// Start is the time the service went down.
+	Start time.Time
+
+	// End is the time the service recovered, or zero if it is still down.
+	End time.Time
+}
+
+// Ongoing reports whether the service is still down.
+func (i Incident) Ongoing() bool {
+	return i.End.IsZero()
+}
+
+// Duration returns the length of the incident, up to the given time if it is ongoing.
+func (i Incident) Duration(now time.Time) time.Duration {
+	if i.Ongoing() {
+		return now.Sub(i.Start)
+	}
+
+	return i.End.Sub(i.Start)
+}
+
+// IncidentFilter represents the criteria of a query of the incidents.
+type IncidentFilter struct {
+	// IDs is the list of the UUIDs of the services, or empty for every service.
+	IDs []uuid.UUID
+
+	// From is the beginning of the period the incidents overlap, or zero.
+	From time.Time
+
+	// To is the end of the period the incidents overlap, or zero.
+	To time.Time
+
+	// MinDuration is the minimum length of the incidents, or zero.
+	MinDuration time.Duration
+}
+
+// Matches reports whether the incident matches the filter.
+//
+// Parameters:
+//   - incident: The incident.
+//   - now: The current time, which ends the ongoing incidents.
+func (f IncidentFilter) Matches(incident Incident, now time.Time) bool {
+	if len(f.IDs) > 0 && !slices.Contains(f.IDs, incident.ID) {
+		return false
+	}
+
+	// The incident has to overlap the period.
+	if !f.To.IsZero() && !incident.Start.Before(f.To) {
+		return false
+	}
+
+	if !f.From.IsZero() && !incident.Ongoing() && incident.End.Before(f.From) {
+		return false
+	}
+
+	return incident.Duration(now) >= f.MinDuration
+}
@@ -0,0 +1,32 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IntervalStats are the statistics of the intervals between the consecutive
+// heartbeats of a service.
+//
+// A service whose intervals approach its TTL is drifting toward going down:
+// a single late heartbeat lets its status expire.
+type IntervalStats struct {
+	// ID is the UUID of the service.
+	ID uuid.UUID
+
+	// Samples is the number of the intervals the statistics are computed over.
+	Samples int
+
+	// Min is the shortest interval.
+	Min time.Duration
+
+	// Avg is the mean interval.
+	Avg time.Duration
+
+	// P99 is the 99th percentile of the intervals.
+	P99 time.Duration
+
+	// TTL is the TTL of the status of the service at the last heartbeat.
+	TTL time.Duration
+}
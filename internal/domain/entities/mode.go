@@ -0,0 +1,63 @@
+package entities
+
+import "errors"
+
+var (
+	// ErrUnknownMode is an error that indicates that the replica mode is not recognized.
+	ErrUnknownMode = errors.New("unknown replica mode")
+
+	// ErrNotLeader is an error that indicates that the leadership is held by another instance.
+	ErrNotLeader = errors.New("leadership is held by another instance")
+)
+
+// Mode represents the mode of a vakeel-way replica.
+//
+// An active replica dispatches notifications to the configured webhooks,
+// while a standby replica only tracks heartbeats and stays silent.
+type Mode uint8
+
+// Mode constants represent different replica modes.
+const (
+	// Active represents a replica that dispatches notifications.
+	Active Mode = iota
+	// Standby represents a read-only replica that does not dispatch notifications.
+	Standby
+)
+
+// String returns the string representation of the mode.
+//
+// It returns "active" if the mode is Active, "standby" if the mode is Standby,
+// and "Undefined" for any other value.
+func (m Mode) String() string {
+	// Check the mode and return the corresponding string.
+	switch m {
+	case Active:
+		return "active"
+	case Standby:
+		return "standby"
+	default:
+		return "Undefined"
+	}
+}
+
+// ParseMode converts the string representation of a mode into a Mode.
+//
+// An empty string is treated as Active to keep single-instance deployments
+// working without any additional configuration.
+//
+// Parameters:
+//   - s: The string representation of the mode.
+//
+// Returns:
+//   - The parsed Mode.
+//   - ErrUnknownMode if the string does not represent a known mode.
+func ParseMode(s string) (Mode, error) {
+	switch s {
+	case "", Active.String():
+		return Active, nil
+	case Standby.String():
+		return Standby, nil
+	default:
+		return Active, ErrUnknownMode
+	}
+}
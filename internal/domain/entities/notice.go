@@ -0,0 +1,30 @@
+package entities
+
+import "time"
+
+// NoticeKind represents the kind of a notice sent by the server to the agents.
+type NoticeKind uint8
+
+// NoticeKind constants represent different kinds of the notices.
+const (
+	// NoticeThrottle asks the agents to slow down, because the server is overloaded.
+	NoticeThrottle NoticeKind = iota + 1
+	// NoticeShutdown tells the agents that the server is going away, so they
+	// reconnect after a while instead of treating it as an outage.
+	NoticeShutdown
+)
+
+// Notice represents a message sent by the server to the agents on its own
+// initiative, so they can adapt without restarts.
+type Notice struct {
+	// Kind is the kind of the notice.
+	Kind NoticeKind
+
+	// Delay is the time the agents should wait: the time to hold back the
+	// heartbeats for NoticeThrottle, the time to wait before reconnecting for
+	// NoticeShutdown.
+	Delay time.Duration
+
+	// Reason is a human-readable explanation of the notice.
+	Reason string
+}
@@ -0,0 +1,63 @@
+package entities
+
+import "errors"
+
+// ErrUnknownOverflow is an error that indicates that the overflow policy is not recognized.
+var ErrUnknownOverflow = errors.New("unknown overflow policy")
+
+// Overflow represents what happens to a heartbeat when the buffer of the
+// heartbeats waiting to be processed is full.
+type Overflow uint8
+
+// Overflow constants represent different overflow policies.
+const (
+	// Block represents a policy that waits until the buffer has room, which
+	// slows down the agents sending the heartbeats.
+	Block Overflow = iota
+	// DropOldest represents a policy that drops the oldest buffered heartbeat
+	// to make room for the new one.
+	DropOldest
+	// DropNewest represents a policy that drops the new heartbeat.
+	DropNewest
+)
+
+// String returns the string representation of the overflow policy.
+//
+// It returns "block", "drop-oldest" or "drop-newest" for the known policies,
+// and "Undefined" for any other value.
+func (o Overflow) String() string {
+	// Check the policy and return the corresponding string.
+	switch o {
+	case Block:
+		return "block"
+	case DropOldest:
+		return "drop-oldest"
+	case DropNewest:
+		return "drop-newest"
+	default:
+		return "Undefined"
+	}
+}
+
+// ParseOverflow converts the string representation of an overflow policy into an Overflow.
+//
+// An empty string is treated as Block, which is the behavior of the previous versions.
+//
+// Parameters:
+//   - s: The string representation of the overflow policy.
+//
+// Returns:
+//   - The parsed Overflow.
+//   - ErrUnknownOverflow if the string does not represent a known policy.
+func ParseOverflow(s string) (Overflow, error) {
+	switch s {
+	case "", Block.String():
+		return Block, nil
+	case DropOldest.String():
+		return DropOldest, nil
+	case DropNewest.String():
+		return DropNewest, nil
+	default:
+		return Block, ErrUnknownOverflow
+	}
+}
@@ -0,0 +1,69 @@
+package entities
+
+import (
+	"errors"
+	"slices"
+	"strings"
+)
+
+// ErrUnsupportedProtocol is returned when the client and the server have no
+// protocol version in common.
+var ErrUnsupportedProtocol = errors.New("unsupported protocol version")
+
+// ProtocolMetadata is the gRPC metadata key of the protocol version of the Update stream.
+//
+// The client sends the versions it supports, either as several values or as a
+// comma-separated list, and the server replies with the chosen version in the
+// header of the stream.
+const ProtocolMetadata = "vakeel-protocol"
+
+// The versions of the protocol of the Update stream.
+const (
+	// ProtocolV1 is the original protocol: the agents send the heartbeats and
+	// receive empty responses.
+	ProtocolV1 = "v1"
+
+	// ProtocolV2 adds the configuration of the agents to the responses.
+	ProtocolV2 = "v2"
+)
+
+// Protocols returns the supported protocol versions, oldest first.
+//
+// Returns:
+//   - The list of the supported protocol versions.
+func Protocols() []string {
+	return []string{ProtocolV1, ProtocolV2}
+}
+
+// NegotiateProtocol chooses the latest protocol version supported by both sides.
+//
+// The agents that do not send their versions are assumed to speak ProtocolV1,
+// so they keep working after the new versions are rolled out.
+//
+// Parameters:
+//   - offered: The versions sent by the client, possibly comma-separated.
+//
+// Returns:
+//   - The chosen protocol version.
+//   - ErrUnsupportedProtocol if no offered version is supported.
+func NegotiateProtocol(offered []string) (string, error) {
+	if len(offered) == 0 {
+		return ProtocolV1, nil
+	}
+
+	versions := make([]string, 0, len(offered))
+	for _, value := range offered {
+		for _, version := range strings.Split(value, ",") {
+			versions = append(versions, strings.TrimSpace(version))
+		}
+	}
+
+	supported := Protocols()
+	for i := len(supported) - 1; i >= 0; i-- {
+		if slices.Contains(versions, supported[i]) {
+			return supported[i], nil
+		}
+	}
+
+	return "", ErrUnsupportedProtocol
+}
@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ServiceStatus represents the current status of a service as tracked by the server.
+type ServiceStatus struct {
+	// ID is the UUID of the service.
+	ID uuid.UUID
+
+	// Status is the current status of the service.
+	Status Status
+
+	// Since is the time the current status began.
+	Since time.Time
+
+	// LastSeen is the time of the last heartbeat of the service.
+	LastSeen time.Time
+
+	// Expires is the time the status expires if no heartbeat arrives, or zero
+	// if it is unknown.
+	Expires time.Time
+
+	// Attempt is the number of the retries of the delivery of the current
+	// status to the failing targets.
+	Attempt uint32
+}
@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Silence represents a time window during which the status transitions of the
+// matching services are recorded but not notified.
+type Silence struct {
+	// ID is the unique identifier of the silence.
+	ID uuid.UUID
+
+	// IDs is the list of the UUIDs of the silenced services.
+	IDs []uuid.UUID
+
+	// Labels is the label selector of the silenced services.
+	//
+	// A service matches the selector if it has all the labels with the same values.
+	Labels map[string]string
+
+	// StartsAt is the time the silence begins.
+	StartsAt time.Time
+
+	// EndsAt is the time the silence ends.
+	EndsAt time.Time
+
+	// Comment is a free-form description of the silence, e.g. the reason of the maintenance.
+	Comment string
+}
+
+// Active reports whether the silence is in effect at the given time.
+func (s Silence) Active(t time.Time) bool {
+	return !t.Before(s.StartsAt) && t.Before(s.EndsAt)
+}
+
+// Expired reports whether the silence has ended at the given time.
+func (s Silence) Expired(t time.Time) bool {
+	return !t.Before(s.EndsAt)
+}
+
+// Matches reports whether the silence applies to the service.
+//
+// A silence without IDs and labels matches nothing.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//   - labels: The labels of the service.
+func (s Silence) Matches(id uuid.UUID, labels map[string]string) bool {
+	if len(s.IDs) == 0 && len(s.Labels) == 0 {
+		return false
+	}
+
+	if len(s.IDs) > 0 && !slices.Contains(s.IDs, id) {
+		return false
+	}
+
+	return MatchLabels(s.Labels, labels)
+}
+
+// MatchLabels reports whether the labels have every label of the selector.
+//
+// An empty selector matches any labels.
+//
+// Parameters:
+//   - selector: The labels to look for.
+//   - labels: The labels of the service.
+func MatchLabels(selector, labels map[string]string) bool {
+	for key, value := range selector {
+		if actual, ok := labels[key]; !ok || actual != value {
+			return false
+		}
+	}
+
+	return true
+}
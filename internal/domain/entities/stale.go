@@ -0,0 +1,23 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StaleWebhook represents a webhook that has not received a heartbeat for a long time.
+type StaleWebhook struct {
+	// ID is the UUID of the webhook.
+	ID uuid.UUID
+
+	// LastSeen is the time of the last heartbeat of the webhook, or the time it
+	// was first watched if it has never received a heartbeat.
+	LastSeen time.Time
+
+	// Never reports whether the webhook has never received a heartbeat.
+	Never bool
+
+	// Archived reports whether the webhook has been archived by the policy.
+	Archived bool
+}
@@ -1,12 +1,20 @@
 package entities
 
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownStatus is an error that indicates that the status is not recognized.
+var ErrUnknownStatus = errors.New("unknown status")
+
 // Status represents the status of a service.
 type Status uint8
 
 // String returns the string representation of the status.
 //
 // It returns "up" if the status is Up, "down" if the status is Down,
-// and "Undefined" for any other value.
+// "degraded" if the status is Degraded, and "Undefined" for any other value.
 //
 // Parameters:
 //   - s: The Status value to convert to a string.
@@ -22,6 +30,9 @@ func (s Status) String() string {
 	case Down:
 		// The status is Down, so return "down".
 		return "down"
+	case Degraded:
+		// The status is Degraded, so return "degraded".
+		return "degraded"
 	default:
 		// The status is undefined, so return "Undefined".
 		return "Undefined"
@@ -34,4 +45,28 @@ const (
 	Up Status = iota
 	// Down represents a "down" status.
 	Down
+	// Degraded represents a "degraded" status: the service is running, but
+	// reports that it does not work properly.
+	Degraded
 )
+
+// ParseStatus converts the string representation of a status into a Status.
+//
+// Parameters:
+//   - s: The string representation of the status, "up", "down" or "degraded".
+//
+// Returns:
+//   - The parsed Status.
+//   - ErrUnknownStatus if the string does not represent a known status.
+func ParseStatus(s string) (Status, error) {
+	switch s {
+	case Up.String():
+		return Up, nil
+	case Down.String():
+		return Down, nil
+	case Degraded.String():
+		return Degraded, nil
+	default:
+		return Down, fmt.Errorf("%w: %q", ErrUnknownStatus, s)
+	}
+}
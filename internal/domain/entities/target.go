@@ -0,0 +1,104 @@
+package entities
+
+import "time"
+
+// Target represents a single notification target of a webhook.
+//
+// A webhook may have several targets (e.g. Instatus, Slack and PagerDuty) that
+// are notified independently of each other.
+type Target struct {
+	// Name is the name of the target, unique within the webhook.
+	//
+	// It is used to track the delivery to each target and in the logs.
+	Name string
+
+	// Type is the type of the notifier used to deliver to the target.
+	//
+	// Example: "instatus", "slack", "pagerduty", "kuma", "alertmanager", "oncall"
+	Type string
+
+	// URL is the URL the notification is delivered to.
+	URL string
+
+	// RoutingKey is the integration key of the target, if the notifier requires one.
+	RoutingKey string
+
+	// Token is the API token of the target, if the notifier requires one.
+	Token string
+
+	// Page is the identifier of the Instatus page of the component.
+	Page string
+
+	// Component is the identifier of the Instatus component updated by the target.
+	//
+	// If it is set, the Instatus notifier updates the status of the component
+	// through the Instatus API instead of triggering the automation webhook.
+	Component string
+
+	// Title is the text/template source of the title of the incidents opened
+	// for the target, if the notifier opens incidents.
+	//
+	// The notifier.Mux renders it before the target is passed to the sender,
+	// like the Body.
+	Title string
+
+	// Statuses maps the statuses of the service ("up", "down", "degraded") to
+	// the statuses of the component, e.g. "MAJOROUTAGE".
+	//
+	// The unmapped statuses use the default mapping of the notifier.
+	Statuses map[string]string
+
+	// Secret is the key the notifications are signed with, if the notifier signs them.
+	//
+	// It lets the receiver verify that the notification comes from vakeel-way.
+	Secret string
+
+	// Template is the text/template source of the notification message.
+	//
+	// If it is empty, the global template is used.
+	Template string
+
+	// Method is the HTTP method of the request, if the notifier lets it be changed.
+	//
+	// If it is empty, the notifier uses its default method, e.g. POST.
+	Method string
+
+	// Body is the text/template source of the request body, if the notifier
+	// lets it be changed.
+	//
+	// The notifier.Mux renders it before the target is passed to the sender,
+	// so the senders receive the rendered body. If it is empty, the notifier
+	// uses its default payload.
+	Body string
+
+	// Format is the format of the request body, if the notifier supports several.
+	//
+	// Example: "cloudevents"
+	Format string
+
+	// ContentType is the media type of the request body.
+	//
+	// If it is empty, the notifier uses its default media type, e.g. application/json.
+	ContentType string
+
+	// After is the time the service has to be down before the target is notified.
+	//
+	// It defines the escalation policy of the webhook: the targets with zero
+	// delay are notified immediately, the others only if the service is still
+	// down after the delay. The targets that were never notified about the
+	// downtime are not notified about the recovery either.
+	After time.Duration
+
+	// Throttle is the minimum time between two notifications with the same status.
+	//
+	// The notifications within the window are held back and attached as a digest
+	// to the next notification. If it is zero, the default window is used.
+	Throttle time.Duration
+
+	// Warnings reports whether the target receives the early warnings of the
+	// services whose heartbeats are late compared to their usual cadence.
+	//
+	// The warnings carry the current status of the service, so they only suit
+	// the targets relaying the message, e.g. Slack or a generic webhook.
+	Warnings bool
+}
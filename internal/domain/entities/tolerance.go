@@ -0,0 +1,112 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrClockSkew is an error that indicates that the heartbeat is timestamped too far in the future.
+var ErrClockSkew = errors.New("heartbeat timestamp is ahead of the server clock")
+
+// Schedule represents the times the heartbeats of a service are expected at,
+// e.g. a cron expression.
+type Schedule interface {
+	// Next returns the first expected time after the given time.
+	Next(after time.Time) time.Time
+}
+
+// Tolerance represents the timing tolerance of a service.
+//
+// It accommodates the agents running on hosts with drifting clocks or with an
+// irregular heartbeat interval.
+type Tolerance struct {
+	// Jitter is the extra time a heartbeat may be late before the service is
+	// considered down. It is added to the default TTL of the status.
+	Jitter time.Duration
+
+	// Skew is the maximum difference between the clock of the agent and the
+	// clock of the server that is still considered to be in sync.
+	Skew time.Duration
+
+	// Schedule is the schedule of the heartbeats of the service, or nil if the
+	// service reports continuously.
+	Schedule Schedule
+
+	// Grace is the time a scheduled heartbeat may be late before the service
+	// is considered down. If it is zero, the default TTL is used.
+	Grace time.Duration
+
+	// Missed is the number of the consecutive intervals the service may miss
+	// before it is considered down. Every interval lasts the TTL of the status,
+	// so one lost heartbeat or a short pause of the agent is not reported.
+	// If it is zero or one, the service is down once its status expires.
+	Missed int
+}
+
+// TTL returns the time the status Up of the service is kept without heartbeats.
+//
+// If the service has a schedule, the status is kept until the scheduled time
+// after the one the heartbeat satisfies plus the grace time, so the service is
+// considered down only when it misses a scheduled heartbeat. A heartbeat
+// satisfies the previous scheduled time, or the next one if it is early.
+//
+// Parameters:
+//   - base: The default TTL of the status.
+//   - now: The time of the heartbeat.
+//
+// Returns:
+//   - The TTL extended by the jitter of the service.
+func (t Tolerance) TTL(base time.Duration, now time.Time) time.Duration {
+	if t.Schedule == nil {
+		return base + t.Jitter
+	}
+
+	grace := t.Grace
+	if grace == 0 {
+		grace = base
+	}
+
+	// The heartbeat sent shortly before a scheduled time satisfies it, so the
+	// status is kept until the grace time of the scheduled time after it.
+	// Early means within the grace time and the first half of the interval.
+	next := t.Schedule.Next(now)
+	if after := t.Schedule.Next(next); next.Sub(now) <= min(grace, after.Sub(next)/2) {
+		next = after
+	}
+
+	return next.Sub(now) + grace + t.Jitter
+}
+
+// Normalize returns the time the heartbeat is accounted at.
+//
+// The heartbeats whose client timestamp is within the skew from the server
+// clock are accounted at the time they are received, so a drifting clock does
+// not make them look late or early. The heartbeats timestamped further in the
+// past keep their timestamp, e.g. the ones buffered by the agent while the
+// server was unreachable.
+//
+// Parameters:
+//   - sent: The client timestamp of the heartbeat, or zero if it is unknown.
+//   - received: The time the heartbeat is received by the server.
+//
+// Returns:
+//   - The time the heartbeat is accounted at.
+//   - An error wrapping ErrClockSkew if the heartbeat is timestamped further in
+//     the future than the skew allows.
+func (t Tolerance) Normalize(sent, received time.Time) (time.Time, error) {
+	if sent.IsZero() {
+		return received, nil
+	}
+
+	offset := sent.Sub(received)
+
+	switch {
+	case offset > t.Skew:
+		return time.Time{}, fmt.Errorf("%w by %s", ErrClockSkew, offset)
+	case offset < -t.Skew:
+		return sent, nil
+	default:
+		return received, nil
+	}
+}
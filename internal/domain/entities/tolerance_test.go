@@ -0,0 +1,70 @@
+package entities_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// hourly is an entities.Schedule expecting a heartbeat at the top of every hour.
+type hourly struct{}
+
+// Next returns the top of the hour after the given time.
+func (hourly) Next(after time.Time) time.Time {
+	return after.Truncate(time.Hour).Add(time.Hour)
+}
+
+// ToleranceTestSuite represents the test suite for the timing tolerance of the services.
+type ToleranceTestSuite struct {
+	suite.Suite
+}
+
+// deadline returns the time the status of a heartbeat received at the time expires.
+func deadline(tolerance entities.Tolerance, at time.Time) time.Time {
+	return at.Add(tolerance.TTL(time.Minute, at))
+}
+
+// TestTolerance_Schedule verifies that the status of a scheduled service is
+// kept until the grace time of the scheduled time after the one the heartbeat satisfies.
+func (suite *ToleranceTestSuite) TestTolerance_Schedule() {
+	tolerance := entities.Tolerance{Schedule: hourly{}, Grace: 5 * time.Minute}
+	slot := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	expected := slot.Add(time.Hour + 5*time.Minute)
+
+	// The heartbeat on time, late within the grace time, or early satisfies
+	// the slot of 10:00, so the next heartbeat is expected at 11:00.
+	for _, at := range []time.Time{
+		slot,
+		slot.Add(3 * time.Minute),
+		slot.Add(-time.Minute),
+		slot.Add(-5 * time.Minute),
+	} {
+		suite.Equal(expected, deadline(tolerance, at), at.String())
+	}
+
+	// The heartbeat sent long before the slot does not satisfy it.
+	suite.Equal(slot.Add(5*time.Minute), deadline(tolerance, slot.Add(-10*time.Minute)))
+}
+
+// TestTolerance_ScheduleDefaultGrace verifies that the default TTL is the grace time of the scheduled services.
+func (suite *ToleranceTestSuite) TestTolerance_ScheduleDefaultGrace() {
+	tolerance := entities.Tolerance{Schedule: hourly{}, Jitter: time.Second}
+	slot := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	suite.Equal(slot.Add(time.Hour+time.Minute+time.Second), deadline(tolerance, slot.Add(-30*time.Second)))
+	suite.Equal(slot.Add(time.Hour+time.Minute+time.Second), deadline(tolerance, slot.Add(30*time.Second)))
+}
+
+// TestTolerance_Continuous verifies that the status of a continuous service is kept for the TTL and the jitter.
+func (suite *ToleranceTestSuite) TestTolerance_Continuous() {
+	suite.Equal(time.Minute+time.Second, entities.Tolerance{Jitter: time.Second}.TTL(time.Minute, time.Now()))
+}
+
+// TestToleranceTestSuite runs the test suite for the timing tolerance of the services.
+func TestToleranceTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ToleranceTestSuite))
+}
@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Transition represents a change of the status of a service.
+type Transition struct {
+	// ID is the UUID of the service.
+	ID uuid.UUID
+
+	// Status is the new status of the service.
+	Status Status
+
+	// At is the time of the transition.
+	At time.Time
+}
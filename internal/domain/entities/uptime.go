@@ -0,0 +1,24 @@
+package entities
+
+import (
+	"time"
+)
+
+// UptimeWindow represents a rolling window the uptime of the services is reported over.
+type UptimeWindow struct {
+	// Name is the short name of the window, e.g. "7d".
+	Name string
+
+	// Duration is the length of the window.
+	Duration time.Duration
+}
+
+// UptimeWindows are the rolling windows the uptime of the services is
+// reported over, shortest first: the usual periods of the SLAs.
+//
+//nolint:gochecknoglobals,mnd
+var UptimeWindows = []UptimeWindow{
+	{Name: "24h", Duration: 24 * time.Hour},
+	{Name: "7d", Duration: 7 * 24 * time.Hour},
+	{Name: "30d", Duration: 30 * 24 * time.Hour},
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// AgentRegistry represents an interface for retrieving the agents reporting the services.
+type AgentRegistry interface {
+	// Agent returns the agent that reported the last heartbeat of the service.
+	//
+	// Parameters:
+	//   - id: The UUID of the service.
+	//
+	// Returns:
+	//   - The agent of the service.
+	//   - A boolean indicating whether the agent is known.
+	Agent(id uuid.UUID) (entities.Agent, bool)
+}
+
+// AgentDirectory keeps the agent that reported the last heartbeat of every service.
+//
+// The agents introduce themselves in the handshake of their Update stream, so
+// the operators can see which agent reports which service, and the
+// notifications can include the details of the agent.
+type AgentDirectory struct {
+	// mu protects the agents.
+	mu sync.RWMutex
+
+	// agents maps the UUIDs of the services to their agents.
+	agents map[uuid.UUID]entities.Agent
+}
+
+// NewAgentDirectory creates an empty AgentDirectory.
+//
+// Returns:
+//   - A pointer to the initialized AgentDirectory.
+func NewAgentDirectory() *AgentDirectory {
+	return &AgentDirectory{
+		mu:     sync.RWMutex{},
+		agents: make(map[uuid.UUID]entities.Agent),
+	}
+}
+
+// Report records that the agent reported a heartbeat of the service.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//   - agent: The agent that reported the heartbeat.
+func (d *AgentDirectory) Report(id uuid.UUID, agent entities.Agent) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.agents[id] = agent
+}
+
+// Agent returns the agent that reported the last heartbeat of the service.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//
+// Returns:
+//   - The agent of the service.
+//   - A boolean indicating whether the agent is known.
+func (d *AgentDirectory) Agent(id uuid.UUID) (entities.Agent, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	agent, ok := d.agents[id]
+
+	return agent, ok
+}
+
+// Forget drops the agent of the service.
+//
+// Parameters:
+//   - id: The UUID of the service.
+func (d *AgentDirectory) Forget(id uuid.UUID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.agents, id)
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// Announcer broadcasts the notices of the server to the connected agents.
+//
+// The notices are rare and advisory, so they are announced without blocking:
+// the notice is skipped for an agent whose buffer is full.
+type Announcer struct {
+	// mu protects the subscribers.
+	mu sync.Mutex
+
+	// subscribers are the channels of the connected agents.
+	subscribers map[chan entities.Notice]struct{}
+}
+
+// NewAnnouncer creates an Announcer without subscribers.
+//
+// Returns:
+//   - A pointer to the initialized Announcer.
+func NewAnnouncer() *Announcer {
+	return &Announcer{
+		mu:          sync.Mutex{},
+		subscribers: make(map[chan entities.Notice]struct{}),
+	}
+}
+
+// Subscribe registers a connected agent.
+//
+// Returns:
+//   - The channel of the notices.
+//   - The function that unsubscribes the agent. It may be called more than once.
+func (a *Announcer) Subscribe() (<-chan entities.Notice, func()) {
+	// The number of the notices buffered per agent.
+	const buffer = 4
+
+	notices := make(chan entities.Notice, buffer)
+
+	a.mu.Lock()
+	a.subscribers[notices] = struct{}{}
+	a.mu.Unlock()
+
+	return notices, func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+
+		delete(a.subscribers, notices)
+	}
+}
+
+// Announce sends the notice to the connected agents.
+//
+// Parameters:
+//   - notice: The notice to send.
+//
+// Returns:
+//   - The number of the agents the notice is sent to.
+func (a *Announcer) Announce(notice entities.Notice) int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	sent := 0
+
+	for notices := range a.subscribers {
+		select {
+		case notices <- notice:
+			sent++
+		default:
+		}
+	}
+
+	return sent
+}
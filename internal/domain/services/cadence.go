@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// Defaults of the CadenceDetector.
+const (
+	// defaultSensitivity is the default number of the standard deviations a heartbeat may be late by.
+	defaultSensitivity = 4
+
+	// defaultRatio is the default minimum ratio of the delay to the usual interval.
+	defaultRatio = 2
+
+	// defaultMinSamples is the default number of the intervals learned before the warnings are sent.
+	defaultMinSamples = 20
+
+	// cadenceSmoothing is the weight of the latest interval in the usual cadence.
+	cadenceSmoothing = 0.1
+)
+
+// cadence is the learned cadence of the heartbeats of a service.
+type cadence struct {
+	// last is the time of the last heartbeat.
+	last time.Time
+
+	// ttl is the TTL of the status of the service at the last heartbeat.
+	ttl time.Duration
+
+	// mean is the exponentially weighted mean of the intervals in seconds.
+	mean float64
+
+	// variance is the exponentially weighted variance of the intervals.
+	variance float64
+
+	// samples is the number of the intervals learned.
+	samples int
+
+	// warned reports whether the current delay has been warned about.
+	warned bool
+}
+
+// threshold returns the delay after which the heartbeat is late.
+func (c *cadence) threshold(sensitivity, ratio float64) time.Duration {
+	seconds := math.Max(c.mean+sensitivity*math.Sqrt(c.variance), ratio*c.mean)
+
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// learn updates the cadence with the interval.
+func (c *cadence) learn(interval time.Duration) {
+	seconds := interval.Seconds()
+
+	if c.samples == 0 {
+		c.mean, c.variance, c.samples = seconds, 0, 1
+
+		return
+	}
+
+	delta := seconds - c.mean
+	c.mean += cadenceSmoothing * delta
+	c.variance = (1 - cadenceSmoothing) * (c.variance + cadenceSmoothing*delta*delta)
+	c.samples++
+}
+
+// CadenceWarning is an early warning about a late heartbeat.
+type CadenceWarning struct {
+	// ID is the UUID of the service.
+	ID uuid.UUID
+
+	// Delay is the time since the previous heartbeat.
+	Delay time.Duration
+
+	// Usual is the usual interval between the heartbeats.
+	Usual time.Duration
+
+	// TTL is the TTL of the status of the service.
+	TTL time.Duration
+}
+
+// Reason returns the explanation of the warning attached to the notification.
+func (w CadenceWarning) Reason() string {
+	return fmt.Sprintf("no heartbeat for %s, usually every %s, the status expires after %s",
+		w.Delay.Round(time.Second), w.Usual.Round(time.Second), w.TTL.Round(time.Second))
+}
+
+// CadenceDetector learns the usual cadence of the heartbeats of every service
+// and detects the heartbeats that are late.
+//
+// The usual interval and its spread are exponentially weighted, so the
+// cadence adapts to the services that change their interval. A heartbeat is
+// late once the delay exceeds both the usual interval by the sensitivity in
+// standard deviations and the usual interval multiplied by the ratio. The
+// delay is warned about once, and only if it is shorter than the TTL: the
+// services whose status expires are reported down anyway.
+type CadenceDetector struct {
+	// sensitivity is the number of the standard deviations a heartbeat may be late by.
+	sensitivity float64
+
+	// ratio is the minimum ratio of the delay to the usual interval.
+	ratio float64
+
+	// minSamples is the number of the intervals learned before the warnings are sent.
+	minSamples int
+
+	// cadences maps the UUIDs of the services to their cadence.
+	cadences map[uuid.UUID]*cadence
+
+	// mu is the mutex used to synchronize access to the cadences.
+	mu sync.Mutex
+}
+
+// NewCadenceDetector creates a new instance of the CadenceDetector struct.
+//
+// Parameters:
+//   - sensitivity: The number of the standard deviations a heartbeat may be
+//     late by. If it is not positive, 4 is used.
+//   - ratio: The minimum ratio of the delay to the usual interval. If it is
+//     not positive, 2 is used.
+//   - minSamples: The number of the intervals learned before the warnings are
+//     sent. If it is not positive, 20 is used.
+//
+// Returns:
+//   - A pointer to the initialized CadenceDetector.
+func NewCadenceDetector(sensitivity, ratio float64, minSamples int) *CadenceDetector {
+	if sensitivity <= 0 {
+		sensitivity = defaultSensitivity
+	}
+
+	if ratio <= 0 {
+		ratio = defaultRatio
+	}
+
+	if minSamples <= 0 {
+		minSamples = defaultMinSamples
+	}
+
+	return &CadenceDetector{
+		sensitivity: sensitivity,
+		ratio:       ratio,
+		minSamples:  minSamples,
+		cadences:    make(map[uuid.UUID]*cadence),
+		mu:          sync.Mutex{},
+	}
+}
+
+// Observe learns the interval before the heartbeat of the service.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//   - at: The time of the heartbeat.
+//   - ttl: The TTL of the status of the service.
+//
+// Returns:
+//   - The warning about the heartbeat, if it arrived late and the delay has
+//     not been warned about yet.
+//   - A boolean indicating whether there is a warning.
+func (d *CadenceDetector) Observe(id uuid.UUID, at time.Time, ttl time.Duration) (CadenceWarning, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	c := d.cadences[id]
+	if c == nil {
+		d.cadences[id] = &cadence{last: at, ttl: ttl, mean: 0, variance: 0, samples: 0, warned: false}
+
+		return CadenceWarning{}, false
+	}
+
+	// The heartbeats out of order are ignored.
+	if !at.After(c.last) {
+		return CadenceWarning{}, false
+	}
+
+	interval := at.Sub(c.last)
+	warning, late := d.late(id, c, interval)
+
+	c.learn(interval)
+	c.last, c.ttl, c.warned = at, ttl, false
+
+	return warning, late
+}
+
+// Scan returns the warnings about the services whose next heartbeat is late.
+//
+// Parameters:
+//   - now: The current time.
+//
+// Returns:
+//   - The warnings about the delays that have not been warned about yet.
+func (d *CadenceDetector) Scan(now time.Time) []CadenceWarning {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var warnings []CadenceWarning
+
+	for id, c := range d.cadences {
+		if warning, late := d.late(id, c, now.Sub(c.last)); late {
+			c.warned = true
+			warnings = append(warnings, warning)
+		}
+	}
+
+	return warnings
+}
+
+// Forget removes the cadence of the service.
+//
+// Parameters:
+//   - id: The UUID of the service.
+func (d *CadenceDetector) Forget(id uuid.UUID) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.cadences, id)
+}
+
+// late reports whether the delay since the last heartbeat of the service is
+// an anomaly that has not been warned about yet.
+func (d *CadenceDetector) late(id uuid.UUID, c *cadence, delay time.Duration) (CadenceWarning, bool) {
+	if c.warned || c.samples < d.minSamples {
+		return CadenceWarning{}, false
+	}
+
+	if delay <= c.threshold(d.sensitivity, d.ratio) || delay >= c.ttl {
+		return CadenceWarning{}, false
+	}
+
+	usual := time.Duration(c.mean * float64(time.Second))
+
+	return CadenceWarning{ID: id, Delay: delay, Usual: usual, TTL: c.ttl}, true
+}
+
+// Forewarn warns the targets about the services whose heartbeats are late
+// compared to their usual cadence, before their status expires.
+//
+// Forewarn checks the cadences periodically and blocks until the context is
+// canceled. It does nothing without a CadenceDetector.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the loop.
+func (s *StateManager) Forewarn(ctx context.Context) {
+	// The interval between the checks of the cadences.
+	const interval = 5 * time.Second
+
+	if s.cadence == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, warning := range s.cadence.Scan(now) {
+				s.warn(ctx, warning)
+			}
+		}
+	}
+}
+
+// observe learns the cadence of the heartbeat and warns the targets if it arrived late.
+//
+// The heartbeats reporting a service down and the statuses of the groups are not learned.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - heartbeat: The entities.Heartbeat of the service.
+func (s *StateManager) observe(ctx context.Context, heartbeat entities.Heartbeat) {
+	if s.cadence == nil || heartbeat.Status == entities.Down {
+		return
+	}
+
+	warning, late := s.cadence.Observe(heartbeat.ID, time.Now(), s.ttl(heartbeat.ID, heartbeat.TTL))
+	if late {
+		s.warn(ctx, warning)
+	}
+}
+
+// warn delivers the early warning to the targets of the service opting in to the warnings.
+//
+// The warnings are advisory: they are neither retried nor queued, and they
+// are not sent while the service is down, silenced or notified through its group.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - warning: The warning about the late heartbeat.
+func (s *StateManager) warn(ctx context.Context, warning CadenceWarning) {
+	// Set a timeout for the delivery.
+	const timeout = 15 * time.Second
+
+	id := warning.ID
+	if !s.active() || s.grouped(id) || s.silenced(ctx, id) {
+		return
+	}
+
+	current, ok := s.cache.Get(id)
+	if !ok || current.status == entities.Down {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	targets, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return
+	}
+
+	// The warning is not a transition, so it has no previous status.
+	event := current.event(id)
+	event.Kind, event.Time, event.Since, event.Reason = entities.KindWarning, time.Now(), time.Time{}, warning.Reason()
+
+	if s.agents != nil {
+		event.Agent, _ = s.agents.Agent(id)
+	}
+
+	s.log.Warn().
+		Str("id", id.String()).
+		Dur("delay", warning.Delay).
+		Dur("usual", warning.Usual).
+		Msg("Heartbeat late")
+
+	for _, target := range targets {
+		if !target.Warnings {
+			continue
+		}
+
+		if err := s.api.Send(ctx, target, event); err != nil {
+			s.log.Err(err).
+				Str("id", id.String()).
+				Str("target", target.Name).
+				Msg("Failed to deliver early warning")
+		}
+	}
+}
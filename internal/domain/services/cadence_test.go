@@ -0,0 +1,84 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/services"
+)
+
+// CadenceDetectorTestSuite represents the test suite for the detection of the late heartbeats.
+type CadenceDetectorTestSuite struct {
+	suite.Suite
+}
+
+// train sends the heartbeats of the service every interval and returns the time of the last one.
+func (suite *CadenceDetectorTestSuite) train(
+	detector *services.CadenceDetector,
+	id uuid.UUID,
+	at time.Time,
+	interval time.Duration,
+	count int,
+) time.Time {
+	for range count {
+		_, late := detector.Observe(id, at, time.Minute)
+		suite.Require().False(late)
+
+		at = at.Add(interval)
+	}
+
+	return at.Add(-interval)
+}
+
+// TestCadenceDetector_Scan verifies that a late heartbeat is warned about once, before the TTL.
+func (suite *CadenceDetectorTestSuite) TestCadenceDetector_Scan() {
+	id := uuid.New()
+	detector := services.NewCadenceDetector(0, 0, 5)
+	last := suite.train(detector, id, time.Now(), 10*time.Second, 10)
+
+	suite.Empty(detector.Scan(last.Add(15 * time.Second)))
+
+	warnings := detector.Scan(last.Add(25 * time.Second))
+	suite.Require().Len(warnings, 1)
+	suite.Equal(id, warnings[0].ID)
+	suite.Equal(25*time.Second, warnings[0].Delay)
+	suite.Equal(10*time.Second, warnings[0].Usual)
+	suite.Equal(time.Minute, warnings[0].TTL)
+
+	// The delay is warned about once.
+	suite.Empty(detector.Scan(last.Add(30 * time.Second)))
+
+	// The heartbeat resets the warning.
+	_, late := detector.Observe(id, last.Add(35*time.Second), time.Minute)
+	suite.False(late)
+	suite.Empty(detector.Scan(last.Add(40 * time.Second)))
+}
+
+// TestCadenceDetector_Observe verifies that a heartbeat arriving late is warned about.
+func (suite *CadenceDetectorTestSuite) TestCadenceDetector_Observe() {
+	id, fresh := uuid.New(), uuid.New()
+	detector := services.NewCadenceDetector(0, 0, 5)
+	last := suite.train(detector, id, time.Now(), 10*time.Second, 10)
+
+	warning, late := detector.Observe(id, last.Add(30*time.Second), time.Minute)
+	suite.Require().True(late)
+	suite.Equal(30*time.Second, warning.Delay)
+
+	// The services still learning their cadence are not warned about.
+	suite.train(detector, fresh, last, 10*time.Second, 3)
+	suite.Empty(detector.Scan(last.Add(time.Hour)))
+
+	// The delays past the TTL are left to the expiry of the status.
+	detector.Forget(id)
+	last = suite.train(detector, id, last, 10*time.Second, 10)
+	suite.Empty(detector.Scan(last.Add(time.Minute)))
+}
+
+// TestCadenceDetectorTestSuite runs the test suite for the detection of the late heartbeats.
+func TestCadenceDetectorTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CadenceDetectorTestSuite))
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+var (
+	// ErrDeadLetterNotFound is an error that indicates that the requested dead letter was not found.
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+
+	// ErrTargetNotFound is an error that indicates that the target of a dead letter was removed.
+	ErrTargetNotFound = errors.New("target not found")
+)
+
+// DeadLetterStore represents an interface for keeping the notifications that could not be delivered.
+type DeadLetterStore interface {
+	// List returns the dead letters, oldest first.
+	List() []entities.DeadLetter
+
+	// Get returns the dead letter and whether it exists.
+	Get(id uuid.UUID) (entities.DeadLetter, bool)
+
+	// Remove removes the dead letter.
+	//
+	// Returns:
+	//   - Whether the dead letter existed.
+	//   - An error if the store cannot be persisted.
+	Remove(id uuid.UUID) (bool, error)
+}
+
+// DeadLetters lets the operators inspect, replay and discard the
+// notifications that could not be delivered.
+type DeadLetters struct {
+	// store keeps the dead letters.
+	store DeadLetterStore
+
+	// api is used to deliver the replayed notifications.
+	api API
+
+	// repo is used to resolve the targets of the dead letters.
+	repo WebhookRegistry
+}
+
+// NewDeadLetters creates a new instance of the DeadLetters struct.
+//
+// Parameters:
+//   - store: The DeadLetterStore keeping the dead letters.
+//   - api: The API used to deliver the replayed notifications.
+//   - repo: The WebhookRegistry used to resolve the targets of the dead letters.
+//
+// Returns:
+//   - A pointer to the initialized DeadLetters.
+func NewDeadLetters(store DeadLetterStore, api API, repo WebhookRegistry) *DeadLetters {
+	return &DeadLetters{
+		store: store,
+		api:   api,
+		repo:  repo,
+	}
+}
+
+// List returns the dead letters, oldest first.
+func (d *DeadLetters) List() []entities.DeadLetter {
+	return d.store.List()
+}
+
+// Replay delivers the dead letter to its target again.
+//
+// The dead letter is removed once the target accepts it, and kept otherwise.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the delivery.
+//   - id: The identifier of the dead letter.
+//
+// Returns:
+//   - ErrDeadLetterNotFound if there is no such dead letter.
+//   - ErrTargetNotFound if the webhook or the target was removed.
+//   - An error if the target still fails.
+func (d *DeadLetters) Replay(ctx context.Context, id uuid.UUID) error {
+	dead, ok := d.store.Get(id)
+	if !ok {
+		return ErrDeadLetterNotFound
+	}
+
+	if !slices.Contains(d.repo.All(), dead.Event.ID) {
+		return fmt.Errorf("%w: the webhook %s was removed", ErrTargetNotFound, dead.Event.ID)
+	}
+
+	targets, err := d.repo.Get(ctx, dead.Event.ID)
+	if err != nil {
+		return err
+	}
+
+	idx := slices.IndexFunc(targets, func(target entities.Target) bool {
+		return target.Name == dead.Target
+	})
+	if idx < 0 {
+		return fmt.Errorf("%w: the target %q was removed", ErrTargetNotFound, dead.Target)
+	}
+
+	if err := d.api.Send(ctx, targets[idx], dead.Event); err != nil {
+		return err
+	}
+
+	_, err = d.store.Remove(id)
+
+	return err
+}
+
+// Discard removes the dead letter without delivering it.
+//
+// Parameters:
+//   - id: The identifier of the dead letter.
+//
+// Returns:
+//   - ErrDeadLetterNotFound if there is no such dead letter.
+//   - An error if the store cannot be persisted.
+func (d *DeadLetters) Discard(id uuid.UUID) error {
+	removed, err := d.store.Remove(id)
+	if err != nil {
+		return err
+	}
+
+	if !removed {
+		return ErrDeadLetterNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package services_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/domain/services"
+)
+
+// letterStore is an in-memory services.DeadLetterStore.
+type letterStore struct {
+	letters []entities.DeadLetter
+}
+
+// List returns the dead letters.
+func (s *letterStore) List() []entities.DeadLetter {
+	return slices.Clone(s.letters)
+}
+
+// Get returns the dead letter.
+func (s *letterStore) Get(id uuid.UUID) (entities.DeadLetter, bool) {
+	idx := slices.IndexFunc(s.letters, func(dead entities.DeadLetter) bool { return dead.ID == id })
+	if idx < 0 {
+		return entities.DeadLetter{}, false
+	}
+
+	return s.letters[idx], true
+}
+
+// Remove removes the dead letter.
+func (s *letterStore) Remove(id uuid.UUID) (bool, error) {
+	n := len(s.letters)
+	s.letters = slices.DeleteFunc(s.letters, func(dead entities.DeadLetter) bool { return dead.ID == id })
+
+	return len(s.letters) < n, nil
+}
+
+// DeadLettersTestSuite represents the test suite for the dead letters functionality.
+type DeadLettersTestSuite struct {
+	suite.Suite
+}
+
+// TestDeadLetters_Replay verifies that a replayed dead letter is removed once
+// delivered, and kept while the target fails.
+func (suite *DeadLettersTestSuite) TestDeadLetters_Replay() {
+	id := uuid.New()
+	dead := entities.DeadLetter{ID: uuid.New(), Target: "ops", Event: entities.Event{ID: id, Status: entities.Down}}
+	store := &letterStore{letters: []entities.DeadLetter{dead}}
+	registry := staticRegistry{id: {{Name: "ops", Type: "webhook"}}}
+
+	// The target still fails: the dead letter is kept.
+	letters := services.NewDeadLetters(store, failingAPI{}, registry)
+	suite.Require().ErrorIs(letters.Replay(context.Background(), dead.ID), errUnreachable)
+	suite.Len(letters.List(), 1)
+
+	api := &recordingAPI{}
+	letters = services.NewDeadLetters(store, api, registry)
+	suite.Require().NoError(letters.Replay(context.Background(), dead.ID))
+	suite.Empty(letters.List())
+	suite.Require().Len(api.events, 1)
+	suite.Equal(entities.Down, api.events[0].Status)
+
+	suite.Require().ErrorIs(letters.Replay(context.Background(), dead.ID), services.ErrDeadLetterNotFound)
+}
+
+// TestDeadLetters_RemovedTarget verifies that the dead letters of a removed target cannot be replayed.
+func (suite *DeadLettersTestSuite) TestDeadLetters_RemovedTarget() {
+	id := uuid.New()
+	dead := entities.DeadLetter{ID: uuid.New(), Target: "ops", Event: entities.Event{ID: id, Status: entities.Down}}
+	store := &letterStore{letters: []entities.DeadLetter{dead}}
+
+	letters := services.NewDeadLetters(store, &recordingAPI{}, staticRegistry{id: {{Name: "slack"}}})
+	suite.Require().ErrorIs(letters.Replay(context.Background(), dead.ID), services.ErrTargetNotFound)
+
+	letters = services.NewDeadLetters(store, &recordingAPI{}, staticRegistry{})
+	suite.Require().ErrorIs(letters.Replay(context.Background(), dead.ID), services.ErrTargetNotFound)
+	suite.Len(letters.List(), 1)
+}
+
+// TestDeadLetters_Discard verifies that a discarded dead letter is removed.
+func (suite *DeadLettersTestSuite) TestDeadLetters_Discard() {
+	dead := entities.DeadLetter{ID: uuid.New(), Target: "ops"}
+	letters := services.NewDeadLetters(&letterStore{letters: []entities.DeadLetter{dead}}, &recordingAPI{}, staticRegistry{})
+
+	suite.Require().NoError(letters.Discard(dead.ID))
+	suite.Empty(letters.List())
+	suite.Require().ErrorIs(letters.Discard(dead.ID), services.ErrDeadLetterNotFound)
+}
+
+// TestDeadLettersTestSuite runs the dead letters test suite.
+func TestDeadLettersTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(DeadLettersTestSuite))
+}
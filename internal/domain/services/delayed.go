@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// delayedSet holds the latest delayed heartbeat of each service.
+//
+// It is used by the StateManager to tell the downtime caused by the network
+// from the downtime of the service itself.
+type delayedSet struct {
+	// latest maps the UUIDs of the services to their latest delayed heartbeat.
+	latest map[uuid.UUID]time.Time
+
+	// mu is the mutex used to synchronize access to the set.
+	mu sync.Mutex
+}
+
+// newDelayedSet creates a new empty delayedSet.
+func newDelayedSet() *delayedSet {
+	return &delayedSet{latest: make(map[uuid.UUID]time.Time), mu: sync.Mutex{}}
+}
+
+// add remembers the delayed heartbeat if it is the latest one of the service.
+func (d *delayedSet) add(id uuid.UUID, at time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if at.After(d.latest[id]) {
+		d.latest[id] = at
+	}
+}
+
+// take returns and forgets the latest delayed heartbeat of the service, or
+// zero if there is none.
+func (d *delayedSet) take(id uuid.UUID) time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	at := d.latest[id]
+	delete(d.latest, id)
+
+	return at
+}
+
+// Delayed accounts the heartbeats of the service that were delayed by the
+// network, e.g. buffered by a relay while the server was unreachable.
+//
+// The delayed heartbeats never mark the service as up. If they were sent while
+// the service was considered down, the recovery event is flagged as
+// Unreachable: the service kept running, the network was down. The times are
+// reconciled with the server clock by the skew of the service, and the ones
+// too far in the future are ignored.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - id: The UUID of the service.
+//   - times: The times the heartbeats were sent.
+func (s *StateManager) Delayed(_ context.Context, id uuid.UUID, times ...time.Time) {
+	// Ignore the services that are not tracked.
+	current, ok := s.cache.Get(id)
+	if !ok {
+		return
+	}
+
+	tolerance, now := s.tolerance(id), time.Now()
+
+	for _, at := range times {
+		at, err := tolerance.Normalize(at, now)
+		if err != nil {
+			s.log.Debug().Err(err).Str("id", id.String()).Msg("Delayed heartbeat ignored")
+
+			continue
+		}
+
+		s.delayed.add(id, at)
+	}
+
+	s.log.Debug().
+		Str("id", id.String()).
+		Str("status", current.status.String()).
+		Int("heartbeats", len(times)).
+		Msg("Delayed heartbeats received")
+}
+
+// unreachable reports whether the delayed heartbeats of the service show that
+// it kept running during the downtime.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//   - down: The state of the service while it was down.
+func (s *StateManager) unreachable(id uuid.UUID, down state) bool {
+	return s.delayed.take(id).After(down.seen)
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// Escalate notifies the deferred targets once their escalation delay passes.
+//
+// The targets with an escalation delay are not notified when the service goes
+// down. Escalate checks the escalating services periodically and notifies the
+// targets if the service is still down after their delay. Escalate blocks until
+// the context is canceled.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the escalation loop.
+func (s *StateManager) Escalate(ctx context.Context) {
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, id := range s.escalating.list() {
+				s.escalateDue(ctx, id, time.Now())
+			}
+		}
+	}
+}
+
+// escalateDue notifies the deferred targets of the service whose delay has passed.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - id: The UUID of the service.
+//   - now: The current time.
+func (s *StateManager) escalateDue(ctx context.Context, id uuid.UUID, now time.Time) {
+	// The time the status Down is remembered after every target is notified.
+	const downTTL = 24 * time.Hour
+
+	// Set a timeout for the operation.
+	const timeout = 15 * time.Second
+
+	if !s.active() {
+		return
+	}
+
+	// Lock the mutex to serialize with the garbage collector.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The escalation is over if the service recovered or every target is notified.
+	current, ok := s.cache.Get(id)
+	if !ok || current.status != entities.Down || len(current.deferred) == 0 {
+		s.escalating.remove(id)
+
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	targets, err := s.repo.Get(ctx, id)
+	if err != nil {
+		// Retry on the next tick.
+		return
+	}
+
+	// Select the deferred targets whose delay has passed.
+	next := *current
+	next.deferred = maps.Clone(current.deferred)
+
+	due := make([]entities.Target, 0, len(next.deferred))
+
+	for _, target := range s.filter(targets, current.deferred) {
+		if !now.Before(current.since.Add(target.After)) {
+			due = append(due, target)
+			delete(next.deferred, target.Name)
+		}
+	}
+
+	if len(due) == 0 {
+		return
+	}
+
+	s.log.Info().
+		Str("id", id.String()).
+		Int("targets", len(due)).
+		Msg("Escalating status update")
+
+	// The targets that fail are retried like any other delivery.
+	pending, _ := s.deliver(ctx, due, next.event(id))
+	for name := range pending {
+		if next.pending == nil {
+			next.pending = make(map[string]struct{})
+		}
+
+		next.pending[name] = struct{}{}
+	}
+
+	if len(next.deferred) == 0 {
+		s.escalating.remove(id)
+	}
+
+	if len(next.pending) > 0 {
+		s.cache.Add(id, next, timeout)
+
+		return
+	}
+
+	s.cache.Add(id, next, downTTL)
+}
+
+// escalate applies the escalation policy to the transition of the service.
+//
+// When the service goes down, only the targets without an escalation delay are
+// notified immediately; the others are deferred. When the service recovers, the
+// targets that were still deferred are skipped, so they never hear about the
+// downtime at all.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//   - targets: The targets of the webhook.
+//   - next: The state after the transition.
+//   - current: The state before the transition, or nil if it is unknown.
+//
+// Returns:
+//   - The targets to notify now.
+//   - The names of the deferred targets, or nil.
+func (s *StateManager) escalate(
+	id uuid.UUID,
+	targets []entities.Target,
+	next state,
+	current *state,
+) ([]entities.Target, map[string]struct{}) {
+	now := time.Now()
+	due := make([]entities.Target, 0, len(targets))
+
+	// The service recovered, even if degraded: cancel the escalation.
+	if next.status != entities.Down {
+		for _, target := range targets {
+			if current != nil && current.status == entities.Down {
+				if _, ok := current.deferred[target.Name]; ok {
+					continue
+				}
+			}
+
+			due = append(due, target)
+		}
+
+		if len(due) < len(targets) {
+			s.log.Info().
+				Str("id", id.String()).
+				Int("targets", len(targets)-len(due)).
+				Msg("Escalation canceled, service recovered")
+		}
+
+		return due, nil
+	}
+
+	// The service went down: defer the targets with an escalation delay.
+	var deferred map[string]struct{}
+
+	for _, target := range targets {
+		if now.Before(next.since.Add(target.After)) {
+			if deferred == nil {
+				deferred = make(map[string]struct{})
+			}
+
+			deferred[target.Name] = struct{}{}
+
+			continue
+		}
+
+		due = append(due, target)
+	}
+
+	if deferred != nil {
+		s.escalating.add(id)
+	}
+
+	return due, deferred
+}
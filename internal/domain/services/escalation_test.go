@@ -0,0 +1,138 @@
+package services_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/domain/services"
+)
+
+// EscalationTestSuite represents the test suite for the escalation of the downtimes.
+type EscalationTestSuite struct {
+	suite.Suite
+}
+
+// escalating starts the escalation loop of a new StateManager with the targets.
+func (suite *EscalationTestSuite) escalating(id uuid.UUID, targets ...entities.Target) (*services.StateManager, *flakyAPI) {
+	log := zerolog.Nop()
+	api := &flakyAPI{down: map[string]bool{}}
+
+	manager := services.NewStateManager(
+		api,
+		staticRegistry{id: targets},
+		&log,
+		services.WithCheckInterval(5*time.Millisecond),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	suite.T().Cleanup(func() {
+		cancel()
+		manager.Close()
+	})
+
+	go manager.Escalate(ctx)
+
+	return manager, api
+}
+
+// notified returns the names of the targets the status was delivered to, in order.
+func notified(api *flakyAPI, status entities.Status) []string {
+	var names []string
+
+	for _, delivery := range api.received() {
+		if delivery.status == status {
+			names = append(names, delivery.target)
+		}
+	}
+
+	return names
+}
+
+// TestEscalation_Due verifies that a deferred target is notified only once its delay passes.
+func (suite *EscalationTestSuite) TestEscalation_Due() {
+	id := uuid.New()
+	manager, api := suite.escalating(id,
+		entities.Target{Name: "slack", Type: "slack"},
+		entities.Target{Name: "pagerduty", Type: "pagerduty", After: 200 * time.Millisecond},
+	)
+
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Up))
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Down))
+
+	// The delay of the deferred target has not passed yet.
+	time.Sleep(50 * time.Millisecond)
+	suite.Equal([]string{"slack"}, notified(api, entities.Down))
+
+	suite.Eventually(func() bool {
+		return slices.Equal([]string{"slack", "pagerduty"}, notified(api, entities.Down))
+	}, time.Second, 5*time.Millisecond)
+
+	// Every target is notified once.
+	time.Sleep(50 * time.Millisecond)
+	suite.Equal([]string{"slack", "pagerduty"}, notified(api, entities.Down))
+}
+
+// TestEscalation_Levels verifies that the deferred targets are notified in the order of their delays.
+func (suite *EscalationTestSuite) TestEscalation_Levels() {
+	id := uuid.New()
+	manager, api := suite.escalating(id,
+		entities.Target{Name: "email", Type: "email", After: 300 * time.Millisecond},
+		entities.Target{Name: "pagerduty", Type: "pagerduty", After: 100 * time.Millisecond},
+		entities.Target{Name: "slack", Type: "slack"},
+	)
+
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Up))
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Down))
+	suite.Equal([]string{"slack"}, notified(api, entities.Down))
+
+	suite.Eventually(func() bool {
+		return slices.Equal([]string{"slack", "pagerduty"}, notified(api, entities.Down))
+	}, time.Second, 5*time.Millisecond)
+
+	suite.Eventually(func() bool {
+		return slices.Equal([]string{"slack", "pagerduty", "email"}, notified(api, entities.Down))
+	}, time.Second, 5*time.Millisecond)
+}
+
+// TestEscalation_Recovery verifies that the escalation is reset once the service recovers.
+func (suite *EscalationTestSuite) TestEscalation_Recovery() {
+	id := uuid.New()
+	manager, api := suite.escalating(id,
+		entities.Target{Name: "slack", Type: "slack"},
+		entities.Target{Name: "pagerduty", Type: "pagerduty", After: 150 * time.Millisecond},
+	)
+
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Up))
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Down))
+
+	// The service recovers before the delay: the deferred target hears about
+	// neither the downtime nor the recovery.
+	time.Sleep(50 * time.Millisecond)
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Up))
+
+	time.Sleep(200 * time.Millisecond)
+	suite.Equal([]string{"slack"}, notified(api, entities.Down))
+	suite.ElementsMatch([]string{"slack", "pagerduty", "slack"}, notified(api, entities.Up))
+
+	// The next downtime is escalated from its own start.
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Down))
+	suite.Equal([]string{"slack", "slack"}, notified(api, entities.Down))
+
+	suite.Eventually(func() bool {
+		return slices.Equal([]string{"slack", "slack", "pagerduty"}, notified(api, entities.Down))
+	}, time.Second, 5*time.Millisecond)
+}
+
+// TestEscalationTestSuite runs the test suite for the escalation of the downtimes.
+func TestEscalationTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(EscalationTestSuite))
+}
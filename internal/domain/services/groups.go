@@ -0,0 +1,83 @@
+package services
+
+import (
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// Groups holds the service groups loaded from the configuration.
+//
+// The groups do not change at runtime, so Groups is safe for concurrent use
+// without locking.
+type Groups struct {
+	// groups are the groups indexed by their UUIDs.
+	groups map[uuid.UUID]entities.Group
+
+	// members are the UUIDs of the groups indexed by the UUIDs of their members.
+	members map[uuid.UUID][]uuid.UUID
+}
+
+// NewGroups creates a new instance of the Groups struct.
+//
+// Parameters:
+//   - groups: The groups loaded from the configuration.
+//
+// Returns:
+//   - A pointer to the initialized Groups.
+func NewGroups(groups ...entities.Group) *Groups {
+	g := &Groups{
+		groups:  make(map[uuid.UUID]entities.Group, len(groups)),
+		members: make(map[uuid.UUID][]uuid.UUID),
+	}
+
+	for _, group := range groups {
+		g.groups[group.ID] = group
+
+		for _, member := range group.Members {
+			g.members[member] = append(g.members[member], group.ID)
+		}
+	}
+
+	return g
+}
+
+// Group returns the group with the UUID.
+//
+// Parameters:
+//   - id: The UUID of the group.
+//
+// Returns:
+//   - The group.
+//   - false if the UUID is not the UUID of a group.
+func (g *Groups) Group(id uuid.UUID) (entities.Group, bool) {
+	group, ok := g.groups[id]
+
+	return group, ok
+}
+
+// Of returns the groups the service is a member of.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//
+// Returns:
+//   - The groups of the service, or nil if it is not a member of any group.
+func (g *Groups) Of(id uuid.UUID) []entities.Group {
+	ids := g.members[id]
+	if len(ids) == 0 {
+		return nil
+	}
+
+	result := make([]entities.Group, 0, len(ids))
+	for _, groupID := range ids {
+		result = append(result, g.groups[groupID])
+	}
+
+	return result
+}
+
+// Len returns the number of the groups.
+func (g *Groups) Len() int {
+	return len(g.groups)
+}
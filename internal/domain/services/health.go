@@ -0,0 +1,75 @@
+package services
+
+import (
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// HealthOption is a function that can be used to configure a Health instance.
+type HealthOption func(*Health)
+
+// WithProbe returns a HealthOption that adds a readiness probe.
+//
+// Parameters:
+//   - name: The name of the probed dependency.
+//   - probe: The function returning the reason the dependency is not ready, or nil.
+//
+// Returns:
+//   - A HealthOption that adds the probe to the Health.
+func WithProbe(name string, probe func() error) HealthOption {
+	return func(h *Health) {
+		h.names = append(h.names, name)
+		h.probes = append(h.probes, probe)
+	}
+}
+
+// Health reports whether the instance is ready to receive the heartbeats.
+//
+// The instance is ready if every probe of its dependencies succeeds. The
+// probes are run on every check, so they have to be cheap.
+type Health struct {
+	// names are the names of the probed dependencies, in the order they are checked.
+	names []string
+
+	// probes are the probes of the dependencies.
+	probes []func() error
+}
+
+// NewHealth creates a Health with the given probes.
+//
+// Parameters:
+//   - options: The HealthOptions adding the probes.
+//
+// Returns:
+//   - A pointer to the initialized Health.
+//
+//nolint:exhaustruct
+func NewHealth(options ...HealthOption) *Health {
+	h := &Health{}
+
+	for _, option := range options {
+		option(h)
+	}
+
+	return h
+}
+
+// Check runs the probes.
+//
+// Returns:
+//   - The results of the probes, in the order they were added.
+//   - A boolean indicating whether every probe succeeded.
+func (h *Health) Check() ([]entities.Probe, bool) {
+	results := make([]entities.Probe, 0, len(h.probes))
+	ready := true
+
+	for i, probe := range h.probes {
+		err := probe()
+		if err != nil {
+			ready = false
+		}
+
+		results = append(results, entities.Probe{Name: h.names[i], Err: err})
+	}
+
+	return results, ready
+}
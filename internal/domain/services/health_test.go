@@ -0,0 +1,59 @@
+package services_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/services"
+)
+
+// HealthTestSuite represents the test suite for the readiness of the instance.
+type HealthTestSuite struct {
+	suite.Suite
+}
+
+// TestHealth_Check verifies that the instance is ready only if every probe
+// succeeds, and that the probes are reported in the order they were added.
+func (suite *HealthTestSuite) TestHealth_Check() {
+	errFull := errors.New("the buffer is full")
+	full := false
+
+	health := services.NewHealth(
+		services.WithProbe("grpc", func() error { return nil }),
+		services.WithProbe("checker", func() error {
+			if full {
+				return errFull
+			}
+
+			return nil
+		}),
+	)
+
+	probes, ready := health.Check()
+	suite.True(ready)
+	suite.Require().Len(probes, 2)
+	suite.Equal("grpc", probes[0].Name)
+	suite.Equal("checker", probes[1].Name)
+
+	full = true
+
+	probes, ready = health.Check()
+	suite.False(ready)
+	suite.NoError(probes[0].Err)
+	suite.ErrorIs(probes[1].Err, errFull)
+}
+
+// TestHealth_NoProbes verifies that an instance without probes is ready.
+func (suite *HealthTestSuite) TestHealth_NoProbes() {
+	probes, ready := services.NewHealth().Check()
+	suite.True(ready)
+	suite.Empty(probes)
+}
+
+// TestHealthTestSuite runs the test suite for the readiness of the instance.
+func TestHealthTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(HealthTestSuite))
+}
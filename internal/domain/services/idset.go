@@ -0,0 +1,53 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// idSet is a set of the UUIDs of the services safe for concurrent use.
+//
+// It is used by the StateManager to track the services that need attention of
+// a background loop, e.g. the services whose notifications were silenced.
+type idSet struct {
+	// ids is the set of the UUIDs.
+	ids map[uuid.UUID]struct{}
+
+	// mu is the mutex used to synchronize access to the set.
+	mu sync.Mutex
+}
+
+// newIDSet creates a new empty idSet.
+func newIDSet() *idSet {
+	return &idSet{ids: make(map[uuid.UUID]struct{}), mu: sync.Mutex{}}
+}
+
+// add adds the UUID to the set.
+func (s *idSet) add(id uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ids[id] = struct{}{}
+}
+
+// remove removes the UUID from the set.
+func (s *idSet) remove(id uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.ids, id)
+}
+
+// list returns the UUIDs of the set.
+func (s *idSet) list() []uuid.UUID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]uuid.UUID, 0, len(s.ids))
+	for id := range s.ids {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
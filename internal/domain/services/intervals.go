@@ -0,0 +1,154 @@
+package services
+
+import (
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// defaultIntervalWindow is the default number of the latest intervals the statistics are computed over.
+const defaultIntervalWindow = 128
+
+// p99 is the percentile of the intervals reported by the statistics.
+const p99 = 0.99
+
+// intervalSeries holds the latest intervals between the heartbeats of a service.
+type intervalSeries struct {
+	// last is the time of the last heartbeat.
+	last time.Time
+
+	// ttl is the TTL of the status of the service at the last heartbeat.
+	ttl time.Duration
+
+	// samples is the ring buffer of the latest intervals.
+	samples []time.Duration
+
+	// next is the position of the next interval in the ring buffer.
+	next int
+}
+
+// Intervals tracks the intervals between the consecutive heartbeats of the services.
+//
+// The statistics are computed over a sliding window of the latest intervals,
+// so they follow the services that slow down over time rather than being
+// diluted by their history.
+type Intervals struct {
+	// window is the number of the latest intervals kept per service.
+	window int
+
+	// series maps the UUIDs of the services to their intervals.
+	series map[uuid.UUID]*intervalSeries
+
+	// mu is the mutex used to synchronize access to the series.
+	mu sync.Mutex
+}
+
+// NewIntervals creates a new instance of the Intervals struct.
+//
+// Parameters:
+//   - window: The number of the latest intervals the statistics are computed
+//     over. If it is not positive, the default of 128 is used.
+//
+// Returns:
+//   - A pointer to the initialized Intervals.
+func NewIntervals(window int) *Intervals {
+	if window <= 0 {
+		window = defaultIntervalWindow
+	}
+
+	return &Intervals{window: window, series: make(map[uuid.UUID]*intervalSeries), mu: sync.Mutex{}}
+}
+
+// Observe records a heartbeat of the service.
+//
+// The first heartbeat of a service only starts its series. The heartbeats
+// out of order are ignored.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//   - at: The time of the heartbeat.
+//   - ttl: The TTL of the status of the service.
+func (i *Intervals) Observe(id uuid.UUID, at time.Time, ttl time.Duration) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	ser := i.series[id]
+	if ser == nil {
+		i.series[id] = &intervalSeries{last: at, ttl: ttl, samples: nil, next: 0}
+
+		return
+	}
+
+	if !at.After(ser.last) {
+		return
+	}
+
+	interval := at.Sub(ser.last)
+	ser.last, ser.ttl = at, ttl
+
+	if len(ser.samples) < i.window {
+		ser.samples = append(ser.samples, interval)
+
+		return
+	}
+
+	ser.samples[ser.next] = interval
+	ser.next = (ser.next + 1) % i.window
+}
+
+// Forget removes the intervals of the service.
+//
+// Parameters:
+//   - id: The UUID of the service.
+func (i *Intervals) Forget(id uuid.UUID) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	delete(i.series, id)
+}
+
+// Stats returns the statistics of the intervals of the services.
+//
+// The services that have sent a single heartbeat so far are skipped.
+//
+// Returns:
+//   - The statistics of the services, in no particular order.
+func (i *Intervals) Stats() []entities.IntervalStats {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	stats := make([]entities.IntervalStats, 0, len(i.series))
+
+	for id, ser := range i.series {
+		if len(ser.samples) == 0 {
+			continue
+		}
+
+		sorted := slices.Clone(ser.samples)
+		slices.Sort(sorted)
+
+		var sum time.Duration
+		for _, sample := range sorted {
+			sum += sample
+		}
+
+		// The nearest-rank percentile.
+		rank := max(0, int(math.Ceil(float64(len(sorted))*p99))-1)
+
+		stats = append(stats, entities.IntervalStats{
+			ID:      id,
+			Samples: len(sorted),
+			Min:     sorted[0],
+			Avg:     sum / time.Duration(len(sorted)),
+			P99:     sorted[rank],
+			TTL:     ser.ttl,
+		})
+	}
+
+	return stats
+}
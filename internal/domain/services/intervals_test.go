@@ -0,0 +1,72 @@
+package services_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/services"
+)
+
+// IntervalsTestSuite represents the test suite for the intervals between the heartbeats.
+type IntervalsTestSuite struct {
+	suite.Suite
+}
+
+// TestIntervals_Stats verifies the statistics of the intervals.
+func (suite *IntervalsTestSuite) TestIntervals_Stats() {
+	id, single := uuid.New(), uuid.New()
+	intervals := services.NewIntervals(0)
+	at := time.Now()
+
+	intervals.Observe(single, at, time.Minute)
+	intervals.Observe(id, at, time.Minute)
+
+	for i := 1; i <= 100; i++ {
+		at = at.Add(time.Duration(i) * time.Second)
+		intervals.Observe(id, at, 2*time.Minute)
+	}
+
+	// The heartbeats out of order are ignored.
+	intervals.Observe(id, at.Add(-time.Hour), time.Minute)
+
+	stats := intervals.Stats()
+	suite.Require().Len(stats, 1)
+	suite.Equal(id, stats[0].ID)
+	suite.Equal(100, stats[0].Samples)
+	suite.Equal(time.Second, stats[0].Min)
+	suite.Equal(50500*time.Millisecond, stats[0].Avg)
+	suite.Equal(99*time.Second, stats[0].P99)
+	suite.Equal(2*time.Minute, stats[0].TTL)
+}
+
+// TestIntervals_Window verifies that only the latest intervals are kept.
+func (suite *IntervalsTestSuite) TestIntervals_Window() {
+	id := uuid.New()
+	intervals := services.NewIntervals(2)
+	at := time.Now()
+
+	intervals.Observe(id, at, time.Minute)
+
+	for _, interval := range []time.Duration{time.Second, 10 * time.Second, 20 * time.Second, 30 * time.Second} {
+		at = at.Add(interval)
+		intervals.Observe(id, at, time.Minute)
+	}
+
+	stats := intervals.Stats()
+	suite.Require().Len(stats, 1)
+	suite.Equal(2, stats[0].Samples)
+	suite.Equal(20*time.Second, stats[0].Min)
+	suite.Equal(30*time.Second, stats[0].P99)
+
+	intervals.Forget(id)
+	suite.Empty(intervals.Stats())
+}
+
+// TestIntervalsTestSuite runs the test suite for the intervals between the heartbeats.
+func TestIntervalsTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(IntervalsTestSuite))
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// Coordinator represents an interface for coordinating the replica mode
+// with the other instances of vakeel-way.
+//
+// A Coordinator is consulted before a standby replica is promoted, so that
+// the leader-election subsystem can grant (or refuse) the leadership. It
+// holds the leadership for a lease, which is renewed by Elect.
+type Coordinator interface {
+	// Acquire tries to take over or renew the leadership for the current instance.
+	//
+	// Parameters:
+	//   - ctx: The context.Context used to cancel the operation if needed.
+	//
+	// Returns:
+	//   - entities.ErrNotLeader if another instance holds the leadership.
+	//   - An error if the leadership cannot be acquired.
+	Acquire(ctx context.Context) error
+
+	// Release gives the leadership up, if the current instance holds it, so
+	// another instance takes over without waiting for the lease to expire.
+	//
+	// Parameters:
+	//   - ctx: The context.Context used to cancel the operation if needed.
+	//
+	// Returns:
+	//   - An error if the leadership cannot be released.
+	Release(ctx context.Context) error
+}
+
+// Replica holds the mode of the current vakeel-way instance.
+//
+// The StateManager consults the Replica before dispatching notifications:
+// only an active replica sends status updates to the webhooks.
+type Replica struct {
+	// mode is the current mode of the replica.
+	mode entities.Mode
+
+	// coordinator is consulted before the replica is promoted. It is optional.
+	coordinator Coordinator
+
+	// mu is the mutex used to synchronize access to the mode.
+	mu sync.RWMutex
+
+	// log is the logger used to log mode transitions.
+	log *zerolog.Logger
+
+	// promoted are the functions called once the replica is promoted.
+	promoted []func()
+}
+
+// NewReplica creates a new instance of the Replica struct.
+//
+// Parameters:
+//   - mode: The initial mode of the replica.
+//   - coordinator: The Coordinator consulted on promotion, or nil.
+//   - log: The logger used to log mode transitions.
+//
+// Returns:
+//   - A pointer to the initialized Replica.
+//
+//nolint:exhaustruct
+func NewReplica(mode entities.Mode, coordinator Coordinator, log *zerolog.Logger) *Replica {
+	return &Replica{
+		mode:        mode,
+		coordinator: coordinator,
+		log:         log,
+	}
+}
+
+// OnPromote registers a function called once the replica is promoted.
+//
+// The function is called in its own goroutine, so it may consult the replica.
+//
+// Parameters:
+//   - fn: The function called on every promotion.
+func (r *Replica) OnPromote(fn func()) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.promoted = append(r.promoted, fn)
+}
+
+// Mode returns the current mode of the replica.
+func (r *Replica) Mode() entities.Mode {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.mode
+}
+
+// Active reports whether the replica dispatches notifications.
+func (r *Replica) Active() bool {
+	return r.Mode() == entities.Active
+}
+
+// Promote switches the replica into active mode.
+//
+// If the replica is already active, nothing happens. Otherwise the coordinator
+// (if any) is asked to acquire the leadership first, and the transition is
+// logged at warn level so that it stands out during a failover.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - reason: The reason of the promotion, written to the log.
+//
+// Returns:
+//   - The mode of the replica before the promotion.
+//   - An error if the coordinator refused the leadership.
+func (r *Replica) Promote(ctx context.Context, reason string) (entities.Mode, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Nothing to do if the replica is already active.
+	previous := r.mode
+	if previous == entities.Active {
+		r.log.Info().Str("reason", reason).Msg("replica: promotion requested, already active")
+
+		return previous, nil
+	}
+
+	// Ask the leader-election subsystem for the leadership.
+	if r.coordinator != nil {
+		if err := r.coordinator.Acquire(ctx); err != nil {
+			r.log.Err(err).Str("reason", reason).Msg("replica: promotion refused by coordinator")
+
+			return previous, err
+		}
+	}
+
+	r.switchTo(entities.Active, reason)
+
+	return previous, nil
+}
+
+// Demote switches the replica into standby mode.
+//
+// If the replica is already standby, nothing happens. Otherwise the transition
+// is logged at warn level, like a promotion.
+//
+// Parameters:
+//   - reason: The reason of the demotion, written to the log.
+//
+// Returns:
+//   - The mode of the replica before the demotion.
+func (r *Replica) Demote(reason string) entities.Mode {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	previous := r.mode
+	if previous != entities.Standby {
+		r.switchTo(entities.Standby, reason)
+	}
+
+	return previous
+}
+
+// Elect takes part in the leader election until the context is canceled.
+//
+// The coordinator is asked to acquire or renew the leadership every interval:
+// the replica is promoted once the leadership is acquired, and demoted once it
+// is lost or cannot be renewed, as another instance may take over when the
+// lease expires. On return, the replica is demoted and the leadership is
+// released. If there is no coordinator, Elect returns immediately.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the election.
+//   - interval: The time between two renewals, shorter than the lease.
+func (r *Replica) Elect(ctx context.Context, interval time.Duration) {
+	if r.coordinator == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		r.campaign(ctx)
+
+		select {
+		case <-ctx.Done():
+			// Stop dispatching before another instance can take over.
+			r.Demote("election: stopped")
+
+			// The context is canceled, the leadership is released without it.
+			if err := r.coordinator.Release(context.WithoutCancel(ctx)); err != nil {
+				r.log.Err(err).Msg("replica: failed to release the leadership")
+			}
+
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// campaign acquires or renews the leadership once and switches the mode.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+func (r *Replica) campaign(ctx context.Context) {
+	err := r.coordinator.Acquire(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	switch {
+	case err == nil && r.mode != entities.Active:
+		r.switchTo(entities.Active, "election: leadership acquired")
+	case err != nil && r.mode == entities.Active:
+		r.switchTo(entities.Standby, "election: leadership lost: "+err.Error())
+	case err != nil && !errors.Is(err, entities.ErrNotLeader):
+		r.log.Err(err).Msg("replica: failed to acquire the leadership")
+	}
+}
+
+// switchTo switches the replica into the mode and logs the transition
+// prominently, so that it stands out during a failover. The mutex must be held.
+//
+// Parameters:
+//   - mode: The new mode of the replica.
+//   - reason: The reason of the transition, written to the log.
+func (r *Replica) switchTo(mode entities.Mode, reason string) {
+	previous := r.mode
+	r.mode = mode
+
+	msg := "replica: PROMOTED, notifications are now dispatched by this instance"
+	if mode == entities.Standby {
+		msg = "replica: DEMOTED, notifications are no longer dispatched by this instance"
+	}
+
+	r.log.Warn().
+		Str("from", previous.String()).
+		Str("to", mode.String()).
+		Str("reason", reason).
+		Msg(msg)
+
+	if mode == entities.Active {
+		for _, fn := range r.promoted {
+			go fn()
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package services_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/domain/services"
+)
+
+// coordinator is a Coordinator granting the leadership while it is free.
+type coordinator struct {
+	mu       sync.Mutex
+	free     bool
+	released bool
+}
+
+// Acquire grants the leadership if it is free.
+func (c *coordinator) Acquire(_ context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.free {
+		return entities.ErrNotLeader
+	}
+
+	return nil
+}
+
+// Release records that the leadership was released.
+func (c *coordinator) Release(_ context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.released = true
+
+	return nil
+}
+
+// set frees or takes the leadership.
+func (c *coordinator) set(free bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.free = free
+}
+
+// ReplicaTestSuite represents the test suite for the replica functionality.
+type ReplicaTestSuite struct {
+	suite.Suite
+}
+
+// TestReplica_Elect verifies that the replica follows the leadership granted
+// by the coordinator, and releases it once the election stops.
+//
+//nolint:exhaustruct
+func (suite *ReplicaTestSuite) TestReplica_Elect() {
+	ctx, cancel := context.WithCancel(context.Background())
+	log := zerolog.Nop()
+	leadership := &coordinator{}
+	replica := services.NewReplica(entities.Standby, leadership, &log)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		replica.Elect(ctx, 10*time.Millisecond)
+	}()
+
+	// A manual promotion is refused while another instance is the leader.
+	_, err := replica.Promote(ctx, "test")
+	suite.Require().ErrorIs(err, entities.ErrNotLeader)
+	suite.False(replica.Active())
+
+	leadership.set(true)
+	suite.Eventually(replica.Active, time.Second, 5*time.Millisecond)
+
+	leadership.set(false)
+	suite.Eventually(func() bool { return !replica.Active() }, time.Second, 5*time.Millisecond)
+
+	leadership.set(true)
+	suite.Eventually(replica.Active, time.Second, 5*time.Millisecond)
+
+	cancel()
+	<-done
+
+	suite.False(replica.Active())
+	suite.True(leadership.released)
+}
+
+// TestReplica_Demote verifies that a demoted replica stops dispatching.
+func (suite *ReplicaTestSuite) TestReplica_Demote() {
+	log := zerolog.Nop()
+	replica := services.NewReplica(entities.Active, nil, &log)
+
+	suite.Equal(entities.Active, replica.Demote("test"))
+	suite.Equal(entities.Standby, replica.Demote("test"))
+	suite.False(replica.Active())
+}
+
+// TestReplica_StandbyExpiry verifies that the services that stop reporting
+// while the replica is on standby are notified once it is promoted.
+func (suite *ReplicaTestSuite) TestReplica_StandbyExpiry() {
+	log := zerolog.Nop()
+	replica := services.NewReplica(entities.Standby, nil, &log)
+	api := &recordingAPI{}
+	stopped, reporting := uuid.New(), uuid.New()
+
+	manager := services.NewStateManager(
+		api,
+		staticRegistry{stopped: {{Name: "slack", Type: "slack"}}, reporting: {{Name: "slack", Type: "slack"}}},
+		&log,
+		services.WithReplica(replica),
+		services.WithExpiryInterval(5*time.Millisecond),
+	)
+	defer manager.Close()
+
+	for _, id := range []uuid.UUID{stopped, reporting} {
+		suite.Require().NoError(manager.Beat(context.Background(), entities.Heartbeat{ID: id, Status: entities.Up, TTL: 20 * time.Millisecond}))
+	}
+
+	// Both services expire on standby, nobody is notified.
+	suite.Eventually(func() bool { return manager.Tracked() == 0 }, time.Second, 5*time.Millisecond)
+	suite.Empty(api.statuses())
+
+	// One of them reports again before the promotion.
+	suite.Require().NoError(manager.Beat(context.Background(), entities.Heartbeat{ID: reporting, Status: entities.Up, TTL: time.Hour}))
+
+	_, err := replica.Promote(context.Background(), "test")
+	suite.Require().NoError(err)
+
+	// Only the service that stopped reporting is notified as down.
+	suite.Eventually(func() bool { return len(api.statuses()) == 1 }, time.Second, 5*time.Millisecond)
+	suite.Equal([]entities.Status{entities.Down}, api.statuses())
+
+	current, ok := manager.Status(stopped)
+	suite.Require().True(ok)
+	suite.Equal(entities.Down, current.Status)
+
+	current, ok = manager.Status(reporting)
+	suite.Require().True(ok)
+	suite.Equal(entities.Up, current.Status)
+}
+
+// TestReplica_ActiveExpiry verifies that an active replica notifies the
+// services that stop reporting right away.
+func (suite *ReplicaTestSuite) TestReplica_ActiveExpiry() {
+	log := zerolog.Nop()
+	replica := services.NewReplica(entities.Active, nil, &log)
+	api := &recordingAPI{}
+	id := uuid.New()
+
+	manager := services.NewStateManager(
+		api,
+		staticRegistry{id: {{Name: "slack", Type: "slack"}}},
+		&log,
+		services.WithReplica(replica),
+		services.WithExpiryInterval(5*time.Millisecond),
+	)
+	defer manager.Close()
+
+	suite.Require().NoError(manager.Beat(context.Background(), entities.Heartbeat{ID: id, Status: entities.Up, TTL: 20 * time.Millisecond}))
+
+	suite.Eventually(func() bool { return len(api.statuses()) == 2 }, time.Second, 5*time.Millisecond)
+	suite.Equal([]entities.Status{entities.Up, entities.Down}, api.statuses())
+
+	// A promotion of the active replica notifies nobody again.
+	_, err := replica.Promote(context.Background(), "test")
+	suite.Require().NoError(err)
+
+	time.Sleep(50 * time.Millisecond)
+	suite.Len(api.statuses(), 2)
+}
+
+// TestReplicaTestSuite runs the test suite for the replica functionality.
+func TestReplicaTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ReplicaTestSuite))
+}
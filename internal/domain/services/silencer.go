@@ -0,0 +1,164 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+var (
+	// ErrSilenceNotFound is an error that indicates that the requested silence was not found.
+	ErrSilenceNotFound = errors.New("silence not found")
+
+	// ErrInvalidSilence is an error that indicates that the silence has no matchers or an empty window.
+	ErrInvalidSilence = errors.New("silence must match ids or labels and end after it starts")
+)
+
+// LabelRegistry represents an interface for retrieving the labels of the services.
+type LabelRegistry interface {
+	// Labels returns the labels of the service.
+	//
+	// Parameters:
+	//   - id: The UUID of the service.
+	//
+	// Returns:
+	//   - The labels of the service, or nil if it has none.
+	Labels(id uuid.UUID) map[string]string
+}
+
+// Silencer holds the maintenance windows and silences.
+//
+// The silences are either loaded from the configuration or created at runtime
+// through the AdminService. Expired silences are removed automatically.
+type Silencer struct {
+	// silences is the list of the known silences.
+	silences []entities.Silence
+
+	// labels is used to resolve the labels of the services for the label selectors.
+	labels LabelRegistry
+
+	// mu is the mutex used to synchronize access to the silences.
+	mu sync.Mutex
+
+	// now returns the current time.
+	now func() time.Time
+}
+
+// NewSilencer creates a new instance of the Silencer struct.
+//
+// Parameters:
+//   - labels: The LabelRegistry used to resolve the labels of the services.
+//   - silences: The silences loaded from the configuration.
+//
+// Returns:
+//   - A pointer to the initialized Silencer.
+func NewSilencer(labels LabelRegistry, silences ...entities.Silence) *Silencer {
+	return &Silencer{
+		silences: silences,
+		labels:   labels,
+		mu:       sync.Mutex{},
+		now:      time.Now,
+	}
+}
+
+// Add adds a new silence.
+//
+// A random identifier is assigned to the silence if it has none.
+//
+// Parameters:
+//   - silence: The silence to add.
+//
+// Returns:
+//   - The added silence.
+//   - ErrInvalidSilence if the silence has no matchers or an empty window.
+func (s *Silencer) Add(silence entities.Silence) (entities.Silence, error) {
+	if (len(silence.IDs) == 0 && len(silence.Labels) == 0) || !silence.EndsAt.After(silence.StartsAt) {
+		return silence, ErrInvalidSilence
+	}
+
+	if silence.ID == uuid.Nil {
+		silence.ID = uuid.New()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.silences = append(s.silences, silence)
+
+	return silence, nil
+}
+
+// Remove removes the silence.
+//
+// Parameters:
+//   - id: The identifier of the silence.
+//
+// Returns:
+//   - ErrSilenceNotFound if there is no such silence.
+func (s *Silencer) Remove(id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := slices.IndexFunc(s.silences, func(silence entities.Silence) bool {
+		return silence.ID == id
+	})
+	if idx < 0 {
+		return ErrSilenceNotFound
+	}
+
+	s.silences = slices.Delete(s.silences, idx, idx+1)
+
+	return nil
+}
+
+// List returns the silences that have not expired yet.
+func (s *Silencer) List() []entities.Silence {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune()
+
+	return slices.Clone(s.silences)
+}
+
+// Silenced reports whether the notifications of the service are silenced right now.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - id: The UUID of the service.
+func (s *Silencer) Silenced(_ context.Context, id uuid.UUID) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune()
+
+	now := s.now()
+
+	var labels map[string]string
+	if s.labels != nil {
+		labels = s.labels.Labels(id)
+	}
+
+	for _, silence := range s.silences {
+		if silence.Active(now) && silence.Matches(id, labels) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// prune removes the expired silences. The caller must hold the mutex.
+func (s *Silencer) prune() {
+	now := s.now()
+
+	s.silences = slices.DeleteFunc(s.silences, func(silence entities.Silence) bool {
+		return silence.Expired(now)
+	})
+}
@@ -0,0 +1,79 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/domain/services"
+)
+
+// labelRegistry is a static services.LabelRegistry.
+type labelRegistry map[uuid.UUID]map[string]string
+
+// Labels returns the labels of the service.
+func (r labelRegistry) Labels(id uuid.UUID) map[string]string {
+	return r[id]
+}
+
+// SilencerTestSuite represents the test suite for the silencer functionality.
+type SilencerTestSuite struct {
+	suite.Suite
+}
+
+// TestSilencer_Silenced verifies the matching of the silences by ids and labels.
+func (suite *SilencerTestSuite) TestSilencer_Silenced() {
+	staging, production := uuid.New(), uuid.New()
+	now := time.Now()
+
+	silencer := services.NewSilencer(labelRegistry{
+		staging:    {"env": "staging"},
+		production: {"env": "production"},
+	})
+
+	_, err := silencer.Add(entities.Silence{
+		Labels:   map[string]string{"env": "staging"},
+		StartsAt: now.Add(-time.Minute),
+		EndsAt:   now.Add(time.Minute),
+	})
+	suite.Require().NoError(err)
+
+	suite.True(silencer.Silenced(context.Background(), staging))
+	suite.False(silencer.Silenced(context.Background(), production))
+
+	// A window in the future does not silence anything yet.
+	_, err = silencer.Add(entities.Silence{
+		IDs:      []uuid.UUID{production},
+		StartsAt: now.Add(time.Hour),
+		EndsAt:   now.Add(2 * time.Hour),
+	})
+	suite.Require().NoError(err)
+	suite.False(silencer.Silenced(context.Background(), production))
+	suite.Len(silencer.List(), 2)
+}
+
+// TestSilencer_Invalid verifies that silences without matchers are rejected
+// and that the silences can be removed.
+func (suite *SilencerTestSuite) TestSilencer_Invalid() {
+	silencer := services.NewSilencer(labelRegistry{})
+	now := time.Now()
+
+	_, err := silencer.Add(entities.Silence{StartsAt: now, EndsAt: now.Add(time.Hour)})
+	suite.Require().ErrorIs(err, services.ErrInvalidSilence)
+
+	silence, err := silencer.Add(entities.Silence{IDs: []uuid.UUID{uuid.New()}, StartsAt: now, EndsAt: now.Add(time.Hour)})
+	suite.Require().NoError(err)
+
+	suite.Require().NoError(silencer.Remove(silence.ID))
+	suite.Require().ErrorIs(silencer.Remove(silence.ID), services.ErrSilenceNotFound)
+}
+
+// TestSilencerTestSuite runs the silencer test suite.
+func TestSilencerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(SilencerTestSuite))
+}
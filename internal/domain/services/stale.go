@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// Activity represents an interface for retrieving the last heartbeats of the services.
+type Activity interface {
+	// Watch records the time the service is first watched, if it is unknown.
+	//
+	// Parameters:
+	//   - id: The UUID of the service.
+	//   - at: The current time.
+	Watch(id uuid.UUID, at time.Time)
+
+	// LastSeen returns the time of the last heartbeat of the service.
+	//
+	// Parameters:
+	//   - id: The UUID of the service.
+	//
+	// Returns:
+	//   - The time of the last heartbeat, or the time the service was first watched.
+	//   - A boolean indicating whether the service has ever sent a heartbeat.
+	LastSeen(id uuid.UUID) (time.Time, bool)
+}
+
+// Forgetter represents an interface for removing the services at runtime.
+type Forgetter interface {
+	// Forget removes the service.
+	//
+	// Parameters:
+	//   - id: The UUID of the service.
+	Forget(id uuid.UUID)
+}
+
+// StalePolicy flags the webhooks that have not received a heartbeat for a long time.
+//
+// The stale webhooks are reported in a periodic summary and can be listed by
+// the operators. If archiving is enabled, they are also removed from the
+// running instance, which keeps the configuration of long-lived installations
+// honest: the archived webhooks are reported until they are removed from the
+// configuration.
+type StalePolicy struct {
+	// repo is the repository of the webhooks.
+	repo WebhookRegistry
+
+	// activity holds the last heartbeats of the webhooks.
+	activity Activity
+
+	// after is the time without heartbeats after which a webhook is stale.
+	after time.Duration
+
+	// forgetters remove the stale webhooks if archiving is enabled.
+	forgetters []Forgetter
+
+	// archived is the set of the archived webhooks with their last heartbeat.
+	archived map[uuid.UUID]entities.StaleWebhook
+
+	// mu is the mutex used to synchronize access to the archived webhooks.
+	mu sync.Mutex
+
+	// now returns the current time.
+	now func() time.Time
+}
+
+// StaleOption is a function that can be used to configure a StalePolicy instance.
+type StaleOption func(*StalePolicy)
+
+// WithArchive returns a StaleOption that enables archiving of the stale webhooks.
+//
+// Parameters:
+//   - forgetters: The components the archived webhooks are removed from.
+//
+// Returns:
+//   - A StaleOption that enables archiving.
+func WithArchive(forgetters ...Forgetter) StaleOption {
+	return func(p *StalePolicy) {
+		p.forgetters = forgetters
+	}
+}
+
+// NewStalePolicy creates a new instance of the StalePolicy struct.
+//
+// Parameters:
+//   - repo: The repository of the webhooks.
+//   - activity: The Activity holding the last heartbeats of the webhooks.
+//   - after: The time without heartbeats after which a webhook is stale.
+//   - options: Optional configurations for the StalePolicy.
+//
+// Returns:
+//   - A pointer to the initialized StalePolicy.
+func NewStalePolicy(repo WebhookRegistry, activity Activity, after time.Duration, options ...StaleOption) *StalePolicy {
+	policy := &StalePolicy{
+		repo:       repo,
+		activity:   activity,
+		after:      after,
+		forgetters: nil,
+		archived:   make(map[uuid.UUID]entities.StaleWebhook),
+		mu:         sync.Mutex{},
+		now:        time.Now,
+	}
+
+	// Apply any optional configurations provided through the options parameter.
+	for _, option := range options {
+		option(policy)
+	}
+
+	// Start watching the webhooks, so the ones that never receive a heartbeat
+	// are flagged too.
+	now := policy.now()
+	for _, id := range repo.All() {
+		activity.Watch(id, now)
+	}
+
+	return policy
+}
+
+// List returns the stale webhooks, including the archived ones, oldest first.
+//
+// Returns:
+//   - The list of the stale webhooks.
+func (p *StalePolicy) List() []entities.StaleWebhook {
+	cutoff := p.now().Add(-p.after)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stale := make([]entities.StaleWebhook, 0, len(p.archived))
+	for _, webhook := range p.archived {
+		stale = append(stale, webhook)
+	}
+
+	for _, id := range p.repo.All() {
+		seen, ok := p.activity.LastSeen(id)
+		if seen.IsZero() || seen.After(cutoff) {
+			continue
+		}
+
+		stale = append(stale, entities.StaleWebhook{ID: id, LastSeen: seen, Never: !ok, Archived: false})
+	}
+
+	slices.SortFunc(stale, func(a, b entities.StaleWebhook) int {
+		return a.LastSeen.Compare(b.LastSeen)
+	})
+
+	return stale
+}
+
+// Check flags the stale webhooks, archives them if archiving is enabled, and
+// logs the summary.
+//
+// Parameters:
+//   - ctx: The context.Context holding the logger.
+func (p *StalePolicy) Check(ctx context.Context) {
+	logger := zerolog.Ctx(ctx)
+	stale := p.List()
+
+	if len(stale) == 0 {
+		return
+	}
+
+	for _, webhook := range stale {
+		if !webhook.Archived && len(p.forgetters) > 0 {
+			webhook.Archived = true
+			p.archive(webhook)
+		}
+
+		logger.Warn().
+			Str("id", webhook.ID.String()).
+			Time("last_seen", webhook.LastSeen).
+			Bool("never", webhook.Never).
+			Bool("archived", webhook.Archived).
+			Msg("Stale webhook")
+	}
+
+	logger.Warn().
+		Int("stale", len(stale)).
+		Dur("after", p.after).
+		Msg("Stale webhooks found, consider removing them from the configuration")
+}
+
+// Run checks the webhooks periodically until the context is canceled.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the loop.
+//   - interval: The interval between the summaries.
+func (p *StalePolicy) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		p.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// archive removes the webhook from the running instance.
+func (p *StalePolicy) archive(webhook entities.StaleWebhook) {
+	p.mu.Lock()
+	p.archived[webhook.ID] = webhook
+	p.mu.Unlock()
+
+	for _, forgetter := range p.forgetters {
+		forgetter.Forget(webhook.ID)
+	}
+}
@@ -0,0 +1,105 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/domain/services"
+)
+
+// fixedActivity is a services.Activity with fixed last heartbeats.
+type fixedActivity struct {
+	seen    map[uuid.UUID]time.Time
+	watched map[uuid.UUID]time.Time
+}
+
+// Watch records the time the service is first watched.
+func (a *fixedActivity) Watch(id uuid.UUID, at time.Time) {
+	if _, ok := a.watched[id]; !ok {
+		a.watched[id] = at
+	}
+}
+
+// LastSeen returns the time of the last heartbeat of the service.
+func (a *fixedActivity) LastSeen(id uuid.UUID) (time.Time, bool) {
+	if seen, ok := a.seen[id]; ok {
+		return seen, true
+	}
+
+	return a.watched[id], false
+}
+
+// forgettingRegistry is a staticRegistry that removes the forgotten webhooks.
+type forgettingRegistry struct {
+	staticRegistry
+}
+
+// Forget removes the webhook.
+func (r forgettingRegistry) Forget(id uuid.UUID) {
+	delete(r.staticRegistry, id)
+}
+
+// StalePolicyTestSuite represents the test suite for the stale webhook policy.
+type StalePolicyTestSuite struct {
+	suite.Suite
+}
+
+// TestStalePolicy_List verifies that only the webhooks without recent
+// heartbeats are flagged, oldest first.
+func (suite *StalePolicyTestSuite) TestStalePolicy_List() {
+	fresh, stale, never := uuid.New(), uuid.New(), uuid.New()
+	activity := &fixedActivity{
+		seen: map[uuid.UUID]time.Time{
+			fresh: time.Now().Add(-time.Hour),
+			stale: time.Now().Add(-72 * time.Hour),
+		},
+		watched: map[uuid.UUID]time.Time{never: time.Now().Add(-96 * time.Hour)},
+	}
+
+	policy := services.NewStalePolicy(staticRegistry{fresh: nil, stale: nil, never: nil}, activity, 48*time.Hour)
+
+	list := policy.List()
+	suite.Require().Len(list, 2)
+	suite.Equal(never, list[0].ID)
+	suite.True(list[0].Never)
+	suite.Equal(stale, list[1].ID)
+	suite.False(list[1].Never)
+	suite.False(list[1].Archived)
+}
+
+// TestStalePolicy_Archive verifies that the stale webhooks are removed from the
+// running instance and still reported as archived.
+func (suite *StalePolicyTestSuite) TestStalePolicy_Archive() {
+	fresh, stale := uuid.New(), uuid.New()
+	activity := &fixedActivity{
+		seen: map[uuid.UUID]time.Time{
+			fresh: time.Now(),
+			stale: time.Now().Add(-72 * time.Hour),
+		},
+		watched: map[uuid.UUID]time.Time{},
+	}
+	repo := forgettingRegistry{staticRegistry{fresh: nil, stale: nil}}
+
+	policy := services.NewStalePolicy(repo, activity, 48*time.Hour, services.WithArchive(repo))
+	policy.Check(context.Background())
+
+	suite.Len(repo.All(), 1)
+	suite.Equal([]entities.StaleWebhook{
+		{ID: stale, LastSeen: activity.seen[stale], Never: false, Archived: true},
+	}, policy.List())
+
+	// The archived webhook is not archived twice.
+	policy.Check(context.Background())
+	suite.Len(policy.List(), 1)
+}
+
+// TestStalePolicyTestSuite runs the stale webhook policy test suite.
+func TestStalePolicyTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(StalePolicyTestSuite))
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// overdueSet holds the states evicted while the replica was on standby.
+//
+// A standby replica does not dispatch notifications, so the services that stop
+// reporting meanwhile are kept here until the replica is promoted, instead of
+// being forgotten without anyone being told they are down.
+type overdueSet struct {
+	// states maps the UUIDs of the services to their evicted states.
+	states map[uuid.UUID]state
+
+	// mu is the mutex used to synchronize access to the states.
+	mu sync.Mutex
+}
+
+// newOverdueSet creates a new empty overdueSet.
+func newOverdueSet() *overdueSet {
+	return &overdueSet{states: make(map[uuid.UUID]state), mu: sync.Mutex{}}
+}
+
+// add keeps the evicted state of the service.
+func (o *overdueSet) add(id uuid.UUID, current state) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.states[id] = current
+}
+
+// take returns the evicted states and empties the set.
+func (o *overdueSet) take() map[uuid.UUID]state {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	states := o.states
+	o.states = make(map[uuid.UUID]state, len(states))
+
+	return states
+}
+
+// resume re-evaluates the states evicted while the replica was on standby.
+//
+// It is called once the replica is promoted. The services that reported again
+// in the meantime are skipped, the others are notified as if their state had
+// just expired. The states that expired while the replica was on standby, but
+// are still in the cache, e.g. the failing deliveries of the status Down, are
+// retried by the cache on their next expiration.
+func (s *StateManager) resume() {
+	overdue := s.overdue.take()
+	if len(overdue) == 0 {
+		return
+	}
+
+	s.log.Info().Int("services", len(overdue)).Msg("Notifying the services expired on standby")
+
+	for id, current := range overdue {
+		// The service reported again, there is nothing to notify.
+		if s.cache.Contains(id) {
+			continue
+		}
+
+		s.expire(id, current)
+	}
+}
@@ -2,8 +2,11 @@ package services
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,22 +19,22 @@ import (
 // WebhookRegistry represents an interface for managing webhooks.
 //
 // A WebhookRegistry is responsible for retrieving webhooks by their IDs.
-// It provides a Get method for retrieving a webhook by its UUID.
+// It provides a Get method for retrieving the targets of a webhook by its UUID.
 // The Get method takes a context.Context used to cancel the operation if needed
 // and a UUID representing the ID of the webhook to retrieve.
-// It returns a string representing the webhook data and an error if the webhook
+// It returns the targets of the webhook and an error if the webhook
 // is not found or if there is an issue retrieving it.
 type WebhookRegistry interface {
-	// Get retrieves a webhook by its ID.
+	// Get retrieves the targets of a webhook by its ID.
 	//
 	// Parameters:
 	//   - ctx: The context.Context used to cancel the operation if needed.
 	//   - id: The UUID of the webhook to retrieve.
 	//
 	// Returns:
-	//   - webhookData: The webhook data.
+	//   - targets: The notification targets of the webhook.
 	//   - err: An error if the webhook is not found or if there is an issue retrieving it.
-	Get(ctx context.Context, id uuid.UUID) (webhookData string, err error)
+	Get(ctx context.Context, id uuid.UUID) (targets []entities.Target, err error)
 
 	// All returns all webhook IDs.
 	//
@@ -39,28 +42,91 @@ type WebhookRegistry interface {
 	All() []uuid.UUID
 }
 
-// Api represents an interface for sending status updates.
-
 // API represents an interface for sending status updates.
 type API interface {
-	// Send sends a status update to the specified URL.
+	// Send sends a status update to the specified target.
 	//
 	// Parameters:
 	//   - ctx: The context.Context used to cancel the operation if needed.
-	//   - url: The URL to send the status update to.
-	//   - status: The entities.Status to send.
+	//   - target: The entities.Target to send the status update to.
+	//   - event: The entities.Event describing the status update.
 	//
 	// Returns:
-	//   - An error if the status update cannot be sent to the URL.
+	//   - An error if the status update cannot be sent to the target.
 	//   - nil if the status update was sent successfully.
+	Send(ctx context.Context, target entities.Target, event entities.Event) error
+}
+
+// Backlog represents an interface for queuing the events of the unavailable targets.
+//
+// A target that keeps failing after all retry attempts is unavailable: its
+// events are queued in the Backlog instead of being dropped, and are delivered
+// as a single catch-up event once the target recovers.
+type Backlog interface {
+	// Push appends the event to the queue of the target.
+	Push(id uuid.UUID, target string, event entities.Event) error
+
+	// Queued returns the events queued for the target, oldest first.
+	Queued(id uuid.UUID, target string) []entities.Event
+
+	// Targets returns the names of the targets with queued events per webhook.
+	Targets() map[uuid.UUID][]string
+
+	// Ack removes the n oldest events from the queue of the target.
+	Ack(id uuid.UUID, target string, n int) error
+}
+
+// DeadLetterSink represents an interface for keeping the notifications that
+// could not be delivered.
+//
+// The events rejected permanently by a target, e.g. with a 4xx response, are
+// kept as dead letters instead of being retried, so an operator can fix the
+// target and replay them.
+type DeadLetterSink interface {
+	// Add keeps the undelivered notification.
+	Add(dead entities.DeadLetter) error
+}
+
+// History represents an interface for recording the status history of the services.
+type History interface {
+	// Record appends the status transition of the service.
+	//
+	// Parameters:
+	//   - id: The UUID of the service.
+	//   - status: The new status of the service.
+	//   - at: The time of the transition.
+	Record(id uuid.UUID, status entities.Status, at time.Time)
+
+	// Seen records the last heartbeat of the service.
+	//
+	// Parameters:
+	//   - id: The UUID of the service.
+	//   - at: The time of the heartbeat.
+	Seen(id uuid.UUID, at time.Time)
+}
+
+// DependencyRegistry represents an interface for retrieving the dependencies of the services.
+type DependencyRegistry interface {
+	// Dependencies returns the UUIDs of the services the service depends on.
+	//
+	// Parameters:
+	//   - id: The UUID of the service.
+	//
+	// Returns:
+	//   - The UUIDs of the dependencies of the service, or nil if it has none.
+	Dependencies(id uuid.UUID) []uuid.UUID
+}
+
+// ToleranceRegistry represents an interface for retrieving the timing tolerances of the services.
+type ToleranceRegistry interface {
+	// Tolerance returns the timing tolerance of the service.
+	//
+	// Parameters:
+	//   - id: The UUID of the service.
 	//
-	// Send sends a status update to the specified URL.
-	// It takes a context.Context used to cancel the operation if needed,
-	// a string representing the URL to send the status update to,
-	// and an entities.Status representing the status to send.
-	// It returns an error if the status update cannot be sent to the URL,
-	// and nil if the status update was sent successfully.
-	Send(ctx context.Context, url string, status entities.Status) error
+	// Returns:
+	//   - The timing tolerance of the service, or the zero tolerance if it has none.
+	Tolerance(id uuid.UUID) entities.Tolerance
 }
 
 // state represents the current status of a webhook.
@@ -68,31 +134,176 @@ type API interface {
 // The state struct holds the current status of a webhook. It has the following fields:
 //   - status: The current status of the webhook.
 //   - attempt: The number of attempts made to send a status update to the webhook.
+//   - pending: The names of the targets that have not received the status yet.
+//   - since: The time the status began.
+//   - seen: The time of the last heartbeat.
+//   - previous: The time the previous status began.
+//   - muted: Whether the targets have not been told about the status because of a silence.
+//   - deferred: The names of the targets whose escalation delay has not passed yet.
+//   - unreachable: Whether the service kept running while it was considered down.
+//   - reason: The explanation of the status reported by the agent.
+//   - ttl: The TTL of the status proposed by the agent.
+//   - cause: The dependency that was down when the service went down.
 type state struct {
 	// status is the current status of the webhook.
 	status entities.Status
 
 	// attempt is the number of attempts made to send a status update to the webhook.
 	attempt uint32
+
+	// pending is the set of the names of the targets that have not received the
+	// current status yet. The delivery to these targets is retried independently
+	// of the targets that have already received the status.
+	pending map[string]struct{}
+
+	// since is the time the current status began.
+	since time.Time
+
+	// seen is the time of the last heartbeat of the service.
+	seen time.Time
+
+	// previous is the time the previous status began, or zero if it is unknown.
+	previous time.Time
+
+	// muted reports whether the transition into the status was silenced, so the
+	// targets still hold the previous status. The targets are caught up when the
+	// silence ends.
+	muted bool
+
+	// deferred is the set of the names of the targets that have not been notified
+	// about the downtime yet because of their escalation delay. They are notified
+	// by Escalate if the service is still down after the delay, and are skipped
+	// when the service recovers.
+	deferred map[string]struct{}
+
+	// unreachable reports whether the delayed heartbeats of the service show that
+	// it kept running during the downtime the status recovers from.
+	unreachable bool
+
+	// reason is the explanation of the status reported by the agent with the
+	// latest heartbeat, or empty.
+	reason string
+
+	// ttl is the TTL proposed by the agent with the latest heartbeat, or zero
+	// if the TTL configured on the server is used.
+	ttl time.Duration
+
+	// cause is the UUID of the dependency that was down when the service went
+	// down, or uuid.Nil.
+	cause uuid.UUID
+
+	// missed is the number of the consecutive intervals the service has missed
+	// without being considered down yet.
+	missed int
+
+	// request is the ID of the request whose heartbeat began the status, or
+	// empty if the status began without a heartbeat.
+	request string
+}
+
+// stateJSON is the representation of the state in the snapshot of the cache.
+type stateJSON struct {
+	Status      entities.Status     `json:"status"`
+	Attempt     uint32              `json:"attempt,omitempty"`
+	Pending     map[string]struct{} `json:"pending,omitempty"`
+	Since       time.Time           `json:"since"`
+	Seen        time.Time           `json:"seen"`
+	Previous    time.Time           `json:"previous"`
+	Muted       bool                `json:"muted,omitempty"`
+	Deferred    map[string]struct{} `json:"deferred,omitempty"`
+	Unreachable bool                `json:"unreachable,omitempty"`
+	Reason      string              `json:"reason,omitempty"`
+	TTL         time.Duration       `json:"ttl,omitempty"`
+	Cause       uuid.UUID           `json:"cause"`
+	Missed      int                 `json:"missed,omitempty"`
+	Request     string              `json:"request,omitempty"`
+}
+
+// MarshalJSON encodes the state, so the cache of the states can be persisted.
+func (st state) MarshalJSON() ([]byte, error) {
+	return json.Marshal(stateJSON{
+		Status:      st.status,
+		Attempt:     st.attempt,
+		Pending:     st.pending,
+		Since:       st.since,
+		Seen:        st.seen,
+		Previous:    st.previous,
+		Muted:       st.muted,
+		Deferred:    st.deferred,
+		Unreachable: st.unreachable,
+		Reason:      st.reason,
+		TTL:         st.ttl,
+		Cause:       st.cause,
+		Missed:      st.missed,
+		Request:     st.request,
+	})
+}
+
+// UnmarshalJSON decodes the state restored from the snapshot of the cache.
+func (st *state) UnmarshalJSON(data []byte) error {
+	var decoded stateJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	*st = state{
+		status:      decoded.Status,
+		attempt:     decoded.Attempt,
+		pending:     decoded.Pending,
+		since:       decoded.Since,
+		seen:        decoded.Seen,
+		previous:    decoded.Previous,
+		muted:       decoded.Muted,
+		deferred:    decoded.Deferred,
+		unreachable: decoded.Unreachable,
+		reason:      decoded.Reason,
+		ttl:         decoded.TTL,
+		cause:       decoded.Cause,
+		missed:      decoded.Missed,
+		request:     decoded.Request,
+	}
+
+	return nil
+}
+
+// event returns the event describing the transition into the state.
+func (st state) event(id uuid.UUID) entities.Event {
+	return entities.Event{
+		ID:       id,
+		Status:   st.status,
+		Time:     st.since,
+		Since:    st.previous,
+		LastSeen: st.seen,
+		Message:  "",
+		Reason:   st.reason,
+		Cause:    st.cause,
+		Missed:   nil,
+
+		Unreachable: st.unreachable,
+		Agent:       entities.Agent{},
+		RequestID:   st.request,
+	}
 }
 
 // StateManager manages the sending of status updates to webhooks.
 //
 // The StateManager struct holds the necessary dependencies to manage the sending of status updates to webhooks.
+// Every webhook may have several targets: the status updates are fanned out to all of
+// them concurrently, and the delivery to each target is tracked independently.
 // It has the following fields:
 //   - api: The API used to send status updates.
-//   - repo: The repository used to get webhook URLs.
+//   - repo: The repository used to get webhook targets.
 //   - cache: The cache used to store the current status of webhooks.
 //   - mu: The mutex used to synchronize access to the cache.
 type StateManager struct {
 	// api is the API used to send status updates.
 	//
-	// This field holds the API used to send status updates. It is of type Api.
+	// This field holds the API used to send status updates. It is of type API.
 	api API
 
-	// repo is the repository used to get webhook URLs.
+	// repo is the repository used to get webhook targets.
 	//
-	// This field holds the repository used to get webhook URLs. It is of type WebhookRegistry.
+	// This field holds the repository used to get webhook targets. It is of type WebhookRegistry.
 	repo WebhookRegistry
 
 	// cache is the cache used to store the current status of webhooks.
@@ -112,6 +323,373 @@ type StateManager struct {
 	// This field holds the logger used to log messages related to the StateManager.
 	// It is of type *zerolog.Logger.
 	log *zerolog.Logger
+
+	// replica is the mode of the current instance.
+	//
+	// Notifications are dispatched only while the replica is active. If it is nil,
+	// the instance is always considered active.
+	replica *Replica
+
+	// silencer holds the maintenance windows and silences.
+	//
+	// The status transitions of the silenced services are recorded, but not
+	// notified. If it is nil, nothing is silenced.
+	silencer *Silencer
+
+	// muted is the set of the services whose current status was silenced.
+	//
+	// The set is checked periodically by CatchUp to notify the targets once the
+	// silence ends.
+	muted *idSet
+
+	// escalating is the set of the services with deferred targets.
+	//
+	// The set is checked periodically by Escalate to notify the targets once
+	// their escalation delay passes.
+	escalating *idSet
+
+	// overdue holds the states evicted while the replica was on standby.
+	//
+	// They are notified once the replica is promoted.
+	overdue *overdueSet
+
+	// backlog queues the events of the unavailable targets.
+	//
+	// If it is nil, the events are dropped once the retry attempts are exhausted.
+	backlog Backlog
+
+	// deadLetters keeps the events rejected permanently by the targets.
+	//
+	// If it is nil, the rejected events are retried like the failed ones.
+	deadLetters DeadLetterSink
+
+	// throttling limits the rate of the notifications per target.
+	throttling *throttle
+
+	// history records the status transitions of the services. It is optional.
+	history History
+
+	// tolerances holds the timing tolerances of the services. It is optional.
+	tolerances ToleranceRegistry
+
+	// dependencies holds the dependencies of the services. It is optional.
+	dependencies DependencyRegistry
+
+	// cascade is the policy applied to the services that go down while a
+	// dependency is down.
+	cascade entities.Cascade
+
+	// watcher broadcasts the status transitions to the subscribers. It is optional.
+	watcher *Watcher
+
+	// groups holds the service groups. It is optional.
+	groups *Groups
+
+	// intervals tracks the intervals between the heartbeats. It is optional.
+	intervals *Intervals
+
+	// cadence detects the late heartbeats. It is optional.
+	cadence *CadenceDetector
+
+	// agents holds the agents reporting the services. It is optional.
+	agents AgentRegistry
+
+	// reporter reports the failed deliveries. It is optional.
+	reporter FailureReporter
+
+	// delayed holds the latest delayed heartbeats of the services.
+	delayed *delayedSet
+
+	// snapshot is the path to the file the states are persisted to. It is optional.
+	snapshot string
+
+	// snapshotInterval is the interval between the snapshots of the states.
+	snapshotInterval time.Duration
+
+	// expiryInterval is the interval between the checks of the expired
+	// states. If it is zero, the default of the cache is used.
+	expiryInterval time.Duration
+
+	// checkInterval is the interval between the checks of the escalating
+	// services and of the held notifications.
+	checkInterval time.Duration
+}
+
+// Option is a function that can be used to configure a StateManager instance.
+type Option func(*StateManager)
+
+// FailureReporter represents an interface for reporting the failed deliveries
+// to an error tracker.
+type FailureReporter interface {
+	// DeliveryFailed reports a status update that cannot be delivered to a target.
+	//
+	// Parameters:
+	//   - event: The undelivered event.
+	//   - target: The target that failed.
+	//   - err: The error of the delivery.
+	DeliveryFailed(event entities.Event, target entities.Target, err error)
+}
+
+// WithReporter returns an Option that sets the FailureReporter the failed
+// deliveries are reported to.
+//
+// Parameters:
+//   - reporter: The FailureReporter of the failed deliveries.
+//
+// Returns:
+//   - An Option that sets the FailureReporter of the StateManager.
+func WithReporter(reporter FailureReporter) Option {
+	return func(s *StateManager) {
+		s.reporter = reporter
+	}
+}
+
+// WithReplica returns an Option that sets the Replica consulted before
+// dispatching notifications.
+//
+// Parameters:
+//   - replica: The Replica holding the mode of the current instance.
+//
+// Returns:
+//   - An Option that sets the Replica of the StateManager.
+func WithReplica(replica *Replica) Option {
+	return func(s *StateManager) {
+		s.replica = replica
+
+		// Notify the services that stopped reporting while the replica was on standby.
+		replica.OnPromote(s.resume)
+	}
+}
+
+// WithSilencer returns an Option that sets the Silencer consulted before
+// dispatching notifications.
+//
+// Parameters:
+//   - silencer: The Silencer holding the maintenance windows and silences.
+//
+// Returns:
+//   - An Option that sets the Silencer of the StateManager.
+func WithSilencer(silencer *Silencer) Option {
+	return func(s *StateManager) {
+		s.silencer = silencer
+	}
+}
+
+// WithBacklog returns an Option that sets the Backlog of the unavailable targets.
+//
+// Parameters:
+//   - backlog: The Backlog the events of the unavailable targets are queued in.
+//
+// Returns:
+//   - An Option that sets the Backlog of the StateManager.
+func WithBacklog(backlog Backlog) Option {
+	return func(s *StateManager) {
+		s.backlog = backlog
+	}
+}
+
+// WithDeadLetters returns an Option that sets the DeadLetterSink of the rejected events.
+//
+// Parameters:
+//   - sink: The DeadLetterSink the events rejected permanently by the targets are kept in.
+//
+// Returns:
+//   - An Option that sets the DeadLetterSink of the StateManager.
+func WithDeadLetters(sink DeadLetterSink) Option {
+	return func(s *StateManager) {
+		s.deadLetters = sink
+	}
+}
+
+// WithThrottle returns an Option that sets the default throttle window of the notifications.
+//
+// Parameters:
+//   - window: The minimum time between two notifications with the same status
+//     per service and target. Zero disables the throttling of the targets
+//     without their own window.
+//
+// Returns:
+//   - An Option that sets the throttle window of the StateManager.
+func WithThrottle(window time.Duration) Option {
+	return func(s *StateManager) {
+		s.throttling = newThrottle(window)
+	}
+}
+
+// WithHistory returns an Option that sets the History the status transitions are recorded in.
+//
+// Parameters:
+//   - history: The History of the status transitions.
+//
+// Returns:
+//   - An Option that sets the History of the StateManager.
+func WithHistory(history History) Option {
+	return func(s *StateManager) {
+		s.history = history
+	}
+}
+
+// WithWatcher returns an Option that sets the Watcher the status transitions are published to.
+//
+// Parameters:
+//   - watcher: The Watcher of the status transitions.
+//
+// Returns:
+//   - An Option that sets the Watcher of the StateManager.
+func WithWatcher(watcher *Watcher) Option {
+	return func(s *StateManager) {
+		s.watcher = watcher
+	}
+}
+
+// WithAgents returns an Option that sets the registry of the agents reporting
+// the services, so the notifications include the details of the agent.
+//
+// Parameters:
+//   - agents: The AgentRegistry of the agents.
+//
+// Returns:
+//   - An Option that sets the agents of the StateManager.
+func WithAgents(agents AgentRegistry) Option {
+	return func(s *StateManager) {
+		s.agents = agents
+	}
+}
+
+// WithTolerances returns an Option that sets the timing tolerances of the services.
+//
+// Parameters:
+//   - tolerances: The ToleranceRegistry holding the timing tolerances of the services.
+//
+// Returns:
+//   - An Option that sets the tolerances of the StateManager.
+func WithTolerances(tolerances ToleranceRegistry) Option {
+	return func(s *StateManager) {
+		s.tolerances = tolerances
+	}
+}
+
+// WithDependencies returns an Option that sets the dependencies of the services.
+//
+// A service that goes down while a service it depends on is down is not
+// notified with the Suppress policy, until the dependency recovers, so a
+// failing database does not page about every application using it. With the
+// Tag policy, the notification is sent with the dependency as its cause.
+//
+// Parameters:
+//   - dependencies: The DependencyRegistry holding the dependencies of the services.
+//   - cascade: The policy applied to the services whose dependency is down.
+//
+// Returns:
+//   - An Option that sets the dependencies of the StateManager.
+func WithDependencies(dependencies DependencyRegistry, cascade entities.Cascade) Option {
+	return func(s *StateManager) {
+		s.dependencies = dependencies
+		s.cascade = cascade
+	}
+}
+
+// WithGroups returns an Option that sets the service groups.
+//
+// The status of a group is aggregated from the statuses of its members and
+// delivered to the targets of the group, once per transition of the group.
+// The members are not notified on their own.
+//
+// Parameters:
+//   - groups: The Groups holding the service groups.
+//
+// Returns:
+//   - An Option that sets the groups of the StateManager.
+func WithGroups(groups *Groups) Option {
+	return func(s *StateManager) {
+		s.groups = groups
+	}
+}
+
+// WithIntervals returns an Option that tracks the intervals between the
+// heartbeats of the services.
+//
+// The heartbeats reporting a service down and the aggregated statuses of the
+// groups are not tracked.
+//
+// Parameters:
+//   - intervals: The Intervals tracking the intervals.
+//
+// Returns:
+//   - An Option that sets the Intervals of the StateManager.
+func WithIntervals(intervals *Intervals) Option {
+	return func(s *StateManager) {
+		s.intervals = intervals
+	}
+}
+
+// WithCadence returns an Option that warns the targets about the late heartbeats.
+//
+// The early warnings are delivered to the targets opting in to them, see
+// Forewarn.
+//
+// Parameters:
+//   - detector: The CadenceDetector learning the cadences of the services.
+//
+// Returns:
+//   - An Option that sets the CadenceDetector of the StateManager.
+func WithCadence(detector *CadenceDetector) Option {
+	return func(s *StateManager) {
+		s.cadence = detector
+	}
+}
+
+// WithSnapshot returns an Option that persists the states of the services, so
+// they survive restarts.
+//
+// The services that stop reporting while the process is down are reported as
+// down once their restored status expires.
+//
+// Parameters:
+//   - path: The path to the snapshot file.
+//   - interval: The interval between the snapshots. The states are also
+//     persisted when the StateManager is closed.
+//
+// Returns:
+//   - An Option that sets the snapshot file of the StateManager.
+func WithSnapshot(path string, interval time.Duration) Option {
+	return func(s *StateManager) {
+		s.snapshot, s.snapshotInterval = path, interval
+	}
+}
+
+// WithExpiryInterval returns an Option that sets the interval between the
+// checks of the expired states.
+//
+// The services that stop reporting are reported as down up to the interval
+// after their status expires.
+//
+// Parameters:
+//   - interval: The interval between the checks. Zero keeps the default of a minute.
+//
+// Returns:
+//   - An Option that sets the expiry interval of the StateManager.
+func WithExpiryInterval(interval time.Duration) Option {
+	return func(s *StateManager) {
+		s.expiryInterval = interval
+	}
+}
+
+// WithCheckInterval returns an Option that sets the interval between the
+// checks of the escalating services and of the notifications held back by
+// the throttling.
+//
+// Parameters:
+//   - interval: The interval between the checks. Zero keeps the default of 15 seconds.
+//
+// Returns:
+//   - An Option that sets the check interval of the StateManager.
+func WithCheckInterval(interval time.Duration) Option {
+	return func(s *StateManager) {
+		if interval > 0 {
+			s.checkInterval = interval
+		}
+	}
 }
 
 // NewStateManager creates a new instance of the StateManager struct.
@@ -121,95 +699,340 @@ type StateManager struct {
 //
 // Parameters:
 //   - api: The API used to send status updates.
-//   - repo: The repository used to get webhook URLs.
+//   - repo: The repository used to get webhook targets.
 //   - log: The logger used to log messages.
+//   - options: Optional configurations for the StateManager.
 //
 // Returns:
 //   - A pointer to the initialized StateManager.
 //
 //nolint:exhaustruct
-func NewStateManager(api API, repo WebhookRegistry, log *zerolog.Logger) *StateManager {
+func NewStateManager(api API, repo WebhookRegistry, log *zerolog.Logger, options ...Option) *StateManager {
 	// Create a new StateManager instance.
 	stateManager := &StateManager{
 		api:  api,  // Set the API used to send status updates.
-		repo: repo, // Set the repository used to get webhook URLs.
+		repo: repo, // Set the repository used to get webhook targets.
 		log:  log,  // Set the logger used to log messages.
+
+		muted:      newIDSet(),      // Initialize the set of the silenced services.
+		escalating: newIDSet(),      // Initialize the set of the escalating services.
+		overdue:    newOverdueSet(), // Initialize the states evicted on standby.
+		throttling: newThrottle(0),  // Throttle only the targets with their own window by default.
+		delayed:    newDelayedSet(), // Initialize the delayed heartbeats.
+
+		checkInterval: 15 * time.Second, // Check the escalations and the held notifications every 15 seconds.
+	}
+
+	// Apply any optional configurations provided through the options parameter.
+	for _, option := range options {
+		option(stateManager)
 	}
 
 	// Create a new cache with a length based on the number of webhooks.
 	// The cache is initialized with the garbage collector function set to
 	// garbageCollector.
+	cacheOptions := []cache.Option[uuid.UUID, state]{
+		cache.WithOnEvict(stateManager.garbageCollector), // Set the garbage collector function.
+	}
+
+	// Check the expired states at the configured interval.
+	if stateManager.expiryInterval > 0 {
+		cacheOptions = append(cacheOptions, cache.WithEvictDuration[uuid.UUID, state](stateManager.expiryInterval))
+	}
+
+	// Restore the states persisted before the restart.
+	if stateManager.snapshot != "" {
+		cacheOptions = append(cacheOptions,
+			cache.WithPersistence[uuid.UUID, state](stateManager.snapshot, stateManager.snapshotInterval),
+			cache.WithOnError[uuid.UUID, state](func(err error) {
+				stateManager.log.Err(err).Str("path", stateManager.snapshot).Msg("Failed to persist the states")
+			}),
+		)
+	}
+
 	cache := cache.NewCache(
 		len(repo.All()), // Initialize the cache size.
-		cache.WithOnEvict(stateManager.garbageCollector), // Set the garbage collector function.
+		cacheOptions...,
 	)
 
 	// Assign the cache to the StateManager instance.
 	stateManager.cache = cache
 
+	// Resume catching up and escalating the restored states.
+	cache.Range(func(id uuid.UUID, current state) bool {
+		if current.muted {
+			stateManager.muted.add(id)
+		}
+
+		if len(current.deferred) > 0 {
+			stateManager.escalating.add(id)
+		}
+
+		return true
+	})
+
 	// Return the initialized StateManager.
 	return stateManager
 }
 
 // garbageCollector is a function that is called when an item is evicted from the cache.
-// It sends a status update to the specified webhook URL if the status is different
-// from the current status in the cache.
+// It runs on a worker of the cache outside of its lock, so the slow deliveries do not
+// block the heartbeats.
+//
+// If the service stopped reporting (the evicted status is Up or Degraded), it sends the status
+// Down to all targets of the webhook. If the evicted status is Down and some targets
+// have failed before, the delivery is retried for these targets only.
+// The targets that still fail are put back into the cache to be retried later.
+// Once every target is notified, the status Down is remembered for a day, so that
+// the downtime can be reported when the service is up again. A standby replica
+// keeps the evicted state and notifies it once it is promoted.
 //
 // Parameters:
 //   - id: The UUID of the webhook.
 //   - current: The current state of the webhook in the cache.
 func (s *StateManager) garbageCollector(id uuid.UUID, current state) {
-	// Maximum number of attempts to send a status update.
-	const maxAttempts = 5
+	// The time the status Down is remembered after every target is notified.
+	const downTTL = 24 * time.Hour
+
+	// The status of a group changes with its members only, so it never expires.
+	// The targets that failed to receive it are retried on the next heartbeat
+	// of a member, or below if the group is down.
+	if s.group(id) && (current.status != entities.Down || len(current.pending) == 0) {
+		s.cache.Add(id, current, downTTL)
 
-	// Check if the maximum number of attempts has been reached.
-	if current.attempt >= maxAttempts {
 		return
 	}
 
-	// Lock the mutex to ensure exclusive access to the cache.
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	// Set a timeout for the operation.
-	const timeout = 15 * time.Second
-
-	// Create a context with the timeout.
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
+	// The service may miss a few intervals before it is considered down, so a
+	// lost heartbeat or a pause of the agent is not reported.
+	if current.status != entities.Down && current.missed+1 < s.misses(id) {
+		current.missed++
+		s.log.Debug().Str("id", id.String()).Int("missed", current.missed).Msg("Heartbeat missed")
 
-	// Get the URL of the webhook from the repository.
-	target, err := s.repo.Get(ctx, id)
-	if err != nil {
-		// Increment the number of attempts.
-		atomic.AddUint32(&current.attempt, 1)
+		// No heartbeat retries the targets that have not received the status
+		// while the service is missing, so they are retried here.
+		if len(current.pending) > 0 && s.active() && !s.grouped(id) {
+			current = s.retry(id, current)
+		}
 
-		// If an error occurs, add the status 'Down' to the cache.
-		s.cache.Add(id, current, timeout)
+		s.cache.Add(id, current, s.ttl(id, current.ttl))
 
 		return
 	}
 
-	// Inform the webhook about the status update.
-	s.inform(id, entities.Down)
+	// The service stopped reporting: record the downtime, even if nobody is notified.
+	if current.status != entities.Down {
+		s.record(id, entities.Down, time.Now())
+	}
 
-	// Send a status update to the URL.
-	err = s.api.Send(ctx, target, entities.Down)
-	if err != nil {
-		// Increment the number of attempts.
-		atomic.AddUint32(&current.attempt, 1)
+	// The members of a group are notified through the group only. A member that
+	// stopped reporting is remembered as down, so the group stays down until
+	// the member recovers.
+	if s.grouped(id) {
+		if current.status != entities.Down {
+			current = state{
+				status:   entities.Down,
+				attempt:  0,
+				pending:  nil,
+				since:    time.Now(),
+				seen:     current.seen,
+				previous: current.since,
+				muted:    false,
+				deferred: nil,
 
-		// If an error occurs, add the status 'Down' to the cache.
-		s.cache.Add(id, current, timeout)
+				unreachable: false,
+				reason:      "",
+				ttl:         0,
+				cause:       uuid.Nil,
+				missed:      0,
+				request:     "",
+			}
+		}
+
+		s.cache.Add(id, current, downTTL)
+		s.aggregate(context.Background(), id)
 
 		return
 	}
-}
 
-// Send sends a status update to the specified webhook ID.
+	// The downtime was silenced: keep it until the targets are caught up.
+	if current.status == entities.Down && current.muted {
+		s.cache.Add(id, current, downTTL)
+
+		return
+	}
+
+	// Every target already knows that the service is down, forget it.
+	if current.status == entities.Down && len(current.pending) == 0 {
+		s.throttling.forget(id)
+
+		return
+	}
+
+	// A standby replica does not dispatch notifications: keep the state until
+	// the replica is promoted.
+	if !s.active() {
+		s.overdue.add(id, current)
+
+		return
+	}
+
+	s.expire(id, current)
+}
+
+// expire notifies the targets that the service stopped reporting, or retries
+// the delivery of the status Down to the targets that failed before.
 //
-// If the status is the same as the current status in the cache,
-// the status update is not sent and the status is added to the cache.
+// Parameters:
+//   - id: The UUID of the webhook.
+//   - current: The state of the webhook evicted from the cache.
+func (s *StateManager) expire(id uuid.UUID, current state) {
+	// Maximum number of attempts to send a status update.
+	const maxAttempts = 5
+
+	// The time the status Down is remembered after every target is notified.
+	const downTTL = 24 * time.Hour
+
+	// Check if the maximum number of attempts has been reached.
+	if current.attempt >= maxAttempts {
+		// Queue the event for the failing targets until they recover.
+		s.postpone(id, current)
+		current.attempt, current.pending = 0, nil
+
+		// Give up on the failing targets, but remember that the service is down.
+		if current.status == entities.Down {
+			s.cache.Add(id, current, downTTL)
+
+			return
+		}
+
+		// The service stopped reporting after its status Up was given up on:
+		// every target is told it is down below.
+	}
+
+	// Lock the mutex to ensure exclusive access to the cache.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Set a timeout for the operation.
+	const timeout = 15 * time.Second
+
+	// Create a context with the timeout.
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Get the targets of the webhook from the repository.
+	targets, err := s.repo.Get(ctx, id)
+	if err != nil {
+		// If an error occurs, retry the whole delivery later.
+		current.attempt++
+		s.cache.Add(id, current, timeout)
+
+		return
+	}
+
+	// The service went down: all targets have to be notified.
+	// Otherwise only the targets that failed before are retried.
+	next := current
+	if current.status == entities.Down {
+		targets = s.filter(targets, current.pending)
+		next.attempt++
+	} else {
+		next = state{
+			status:   entities.Down,
+			attempt:  0,
+			pending:  nil,
+			since:    time.Now(),
+			seen:     current.seen,
+			previous: current.since,
+			muted:    false,
+			deferred: nil,
+
+			unreachable: false,
+			reason:      "",
+			ttl:         0,
+			cause:       s.cause(id),
+			missed:      0,
+			request:     "",
+		}
+
+		// Record the downtime without notifying anyone during a silence, or
+		// while a dependency is down.
+		if s.silenced(ctx, id) || s.suppressed(next) {
+			next.muted = s.mute(id, &current)
+			s.cache.Add(id, next, downTTL)
+
+			return
+		}
+
+		// Notify only the targets whose escalation delay has passed.
+		targets, next.deferred = s.escalate(id, targets, next, &current)
+	}
+
+	// Send the status update to the targets.
+	next.pending, _ = s.deliver(ctx, targets, next.event(id))
+
+	// Retry the targets that failed.
+	if len(next.pending) > 0 {
+		s.cache.Add(id, next, timeout)
+
+		return
+	}
+
+	// Remember that the service is down.
+	next.attempt = 0
+	s.cache.Add(id, next, downTTL)
+}
+
+// retry delivers the current status of a service that missed an interval to
+// the targets that have not received it yet.
+//
+// Parameters:
+//   - id: The UUID of the webhook.
+//   - current: The state of the webhook evicted from the cache.
+//
+// Returns:
+//   - The state with the targets that still have not received the status.
+func (s *StateManager) retry(id uuid.UUID, current state) state {
+	// Maximum number of attempts to deliver the status to a failing target.
+	const maxAttempts = 5
+
+	// Give up on the failing targets and queue the event until they recover.
+	if current.attempt >= maxAttempts {
+		s.postpone(id, current)
+		current.attempt, current.pending = 0, nil
+
+		return current
+	}
+
+	// Lock the mutex to ensure exclusive access to the cache.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Set a timeout for the operation.
+	const timeout = 15 * time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	current.attempt++
+
+	// Get the targets of the webhook from the repository, or retry them on
+	// the next interval.
+	targets, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return current
+	}
+
+	current.pending, _ = s.deliver(ctx, s.filter(targets, current.pending), current.event(id))
+
+	return current
+}
+
+// Send sends a status update to the specified webhook ID.
+//
+// It is a heartbeat with the status, no message and the TTL configured on
+// the server. See Beat.
 //
 // Parameters:
 //   - ctx: The context.Context used to cancel the operation if needed.
@@ -217,64 +1040,895 @@ func (s *StateManager) garbageCollector(id uuid.UUID, current state) {
 //   - status: The entities.Status to send.
 //
 // Returns:
-//   - An error if the webhook URL cannot be retrieved from the repository,
-//     or if the status update cannot be sent to the webhook.
+//   - An error if the status update cannot be sent to some of the targets.
+func (s *StateManager) Send(ctx context.Context, id uuid.UUID, status entities.Status) error {
+	return s.Beat(ctx, entities.Heartbeat{ID: id, Status: status, Message: "", TTL: 0, RequestID: "", Sent: time.Time{}})
+}
+
+// Beat sends the status of the heartbeat to the webhook of the service.
+//
+// The status is kept in the cache for the TTL proposed by the agent, or the
+// TTL configured on the server. The message of the heartbeat is attached to
+// the notifications as the reason of the status.
+//
+// The heartbeats of a member of a group update the status of the group
+// instead of notifying the targets of the member. The heartbeats sent to a
+// group are ignored, as its status is aggregated from the members.
+//
+// If the status is the same as the current status in the cache,
+// the status update is not sent again, except for the targets that have
+// not received it yet, and the status is prolonged in the cache.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - heartbeat: The entities.Heartbeat with the status of the service.
+//
+// Returns:
+//   - An error if the webhook targets cannot be retrieved from the repository,
+//     or if the status update cannot be sent to some of the targets.
 //   - nil if the status update was sent successfully or if the status is the
 //     same as the current status in the cache.
-func (s *StateManager) Send(ctx context.Context, id uuid.UUID, status entities.Status) error {
+func (s *StateManager) Beat(ctx context.Context, heartbeat entities.Heartbeat) error {
+	if s.group(heartbeat.ID) {
+		s.log.Debug().Str("id", heartbeat.ID.String()).Msg("Heartbeat of a group ignored")
+
+		return nil
+	}
+
+	err := s.beat(ctx, heartbeat)
+
+	// Learn the cadence of the heartbeats of the service.
+	s.observe(ctx, heartbeat)
+
+	// Update the status of the groups of the service.
+	s.aggregate(ctx, heartbeat.ID)
+
+	return err
+}
+
+// beat sends the status of the heartbeat to the webhook of the service or the group. See Beat.
+func (s *StateManager) beat(ctx context.Context, heartbeat entities.Heartbeat) error {
+	id, status := heartbeat.ID, heartbeat.Status
+
 	// The TTL (Time to Live) of the status in the cache.
-	const ttl = time.Minute
+	ttl := s.ttl(id, heartbeat.TTL)
+
+	// Maximum number of attempts to deliver the status to a failing target.
+	const maxAttempts = 5
 
 	// Get the current status from the cache.
 	currentStatus, _ := s.cache.Get(id)
+	now := time.Now()
+
+	// Account the heartbeat at the time the agent sent it, unless the clocks
+	// are within the skew, so the status expires on time.
+	seen, err := s.tolerance(id).Normalize(heartbeat.Sent, now)
+	if err != nil {
+		return err
+	}
+
+	if ttl -= now.Sub(seen); ttl <= 0 {
+		s.log.Debug().Str("id", id.String()).Time("sent", heartbeat.Sent).Msg("Heartbeat expired on arrival")
+
+		return nil
+	}
+
+	// The state after the transition.
+	next := state{
+		status:   status,
+		attempt:  0,
+		pending:  nil,
+		since:    now,
+		seen:     seen,
+		previous: time.Time{},
+		muted:    false,
+		deferred: nil,
+
+		unreachable: false,
+		reason:      heartbeat.Message,
+		ttl:         heartbeat.TTL,
+		request:     heartbeat.RequestID,
+	}
+	if currentStatus != nil {
+		next.previous = currentStatus.since
+	}
+
+	// Record the transition, even if nobody is notified.
+	if currentStatus == nil || currentStatus.status != status {
+		s.record(id, status, now)
+	}
+
+	// Record the heartbeat.
+	if status != entities.Down && s.history != nil {
+		s.history.Seen(id, now)
+	}
+
+	if status != entities.Down && s.intervals != nil && !s.group(id) {
+		s.intervals.Observe(id, now, ttl)
+	}
 
-	// If the status is the same as the current status in the cache,
-	// add it to the cache and return nil.
+	// If the status is the same as the current status in the cache, keep the
+	// time the status began.
 	if currentStatus != nil && currentStatus.status == status {
+		next.since, next.previous, next.muted = currentStatus.since, currentStatus.previous, currentStatus.muted
+		next.deferred, next.unreachable = currentStatus.deferred, currentStatus.unreachable
+		next.cause, next.request = currentStatus.cause, currentStatus.request
+	}
+
+	// Tell whether a dependency of the service caused the downtime.
+	if status == entities.Down && (currentStatus == nil || currentStatus.status != entities.Down) {
+		next.cause = s.cause(id)
+	}
+
+	// Tell whether the service recovers from a network outage.
+	if currentStatus != nil && currentStatus.status == entities.Down && status != entities.Down {
+		next.unreachable = s.unreachable(id, *currentStatus)
+	}
+
+	// If the status is the same as the current status in the cache and
+	// every target has received it, add it to the cache and return nil.
+	if currentStatus != nil && currentStatus.status == status &&
+		(len(currentStatus.pending) == 0 || currentStatus.attempt >= maxAttempts) {
+		// Queue the event for the failing targets until they recover.
+		if len(currentStatus.pending) > 0 {
+			s.postpone(id, *currentStatus)
+		}
+
 		// Prolong the life of the status in the cache.
-		s.cache.Add(id, state{status: status, attempt: 0}, ttl)
+		s.cache.Add(id, next, ttl)
+
+		return nil
+	}
+
+	// A standby replica only tracks the status without notifying anyone, and
+	// the members of a group are notified through the group.
+	if !s.active() || s.grouped(id) {
+		s.cache.Add(id, next, ttl)
+
+		return nil
+	}
+
+	// The targets were never told about the downtime caused by a dependency,
+	// so they are not told about the recovery either.
+	if currentStatus != nil && currentStatus.muted && currentStatus.cause != uuid.Nil && status != entities.Down {
+		s.unmute(id)
+		s.cache.Add(id, next, ttl)
 
 		return nil
 	}
 
-	// Get the webhook URL from the repository.
-	// This is the URL of the webhook that will receive the status update.
-	target, err := s.repo.Get(ctx, id)
+	// Record the transition without notifying anyone during a silence, or
+	// while a dependency is down.
+	if s.silenced(ctx, id) || s.suppressed(next) {
+		next.muted = s.mute(id, currentStatus)
+		s.cache.Add(id, next, ttl)
+
+		return nil
+	}
+
+	// Get the webhook targets from the repository.
+	// These are the targets that will receive the status update.
+	targets, err := s.repo.Get(ctx, id)
 	if err != nil {
 		return err
 	}
 
+	// Retry only the targets that have not received the status yet.
+	// Otherwise follow the escalation policy of the webhook.
+	if currentStatus != nil && currentStatus.status == status {
+		targets = s.filter(targets, currentStatus.pending)
+		next.attempt = currentStatus.attempt + 1
+	} else {
+		targets, next.deferred = s.escalate(id, targets, next, currentStatus)
+	}
+
+	// Send the status update to the targets concurrently.
+	next.pending, err = s.deliver(ctx, targets, next.event(id))
+
+	// Add the status to the cache.
+	// The targets that failed are retried on the next status update.
+	s.cache.Add(id, next, ttl)
+
+	// The escalation loop may have seen the previous status and dropped the
+	// service before the status was added, so register the escalation again.
+	if len(next.deferred) > 0 {
+		s.escalating.add(id)
+	}
+
+	return err
+}
+
+// Redeliver delivers the queued events to the unavailable targets once they recover.
+//
+// Every target with queued events is probed periodically with a single catch-up
+// event: the latest transition with the earlier ones attached as missed. The
+// queue of the target is cleared once the event is delivered. Redeliver blocks
+// until the context is canceled.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the redelivery loop.
+func (s *StateManager) Redeliver(ctx context.Context) {
+	// The interval between the probes of the unavailable targets.
+	const interval = 30 * time.Second
+
+	if s.backlog == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !s.active() {
+				continue
+			}
+
+			for id, names := range s.backlog.Targets() {
+				s.redeliver(ctx, id, names)
+			}
+		}
+	}
+}
+
+// redeliver sends the catch-up events to the unavailable targets of the webhook.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - id: The UUID of the webhook.
+//   - names: The names of the targets with queued events.
+func (s *StateManager) redeliver(ctx context.Context, id uuid.UUID, names []string) {
+	// Set a timeout for the operation.
+	const timeout = 15 * time.Second
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Forget the queued events of the removed webhooks.
+	if !slices.Contains(s.repo.All(), id) {
+		for _, name := range names {
+			_ = s.backlog.Ack(id, name, len(s.backlog.Queued(id, name)))
+		}
+
+		return
+	}
+
+	targets, err := s.repo.Get(ctx, id)
+	if err != nil {
+		// Retry on the next tick.
+		return
+	}
+
+	for _, name := range names {
+		events := s.backlog.Queued(id, name)
+		if len(events) == 0 {
+			continue
+		}
+
+		// Condense the queued events into a single catch-up event.
+		event := events[len(events)-1]
+		event.Missed = events[:len(events)-1]
+
+		idx := slices.IndexFunc(targets, func(target entities.Target) bool {
+			return target.Name == name
+		})
+
+		// The target was removed from the webhook.
+		if idx < 0 {
+			_ = s.backlog.Ack(id, name, len(events))
+
+			continue
+		}
+
+		if err := s.api.Send(ctx, targets[idx], event); err != nil {
+			s.log.Debug().Err(err).
+				Str("id", id.String()).
+				Str("target", name).
+				Msg("Target is still unavailable")
+
+			continue
+		}
+
+		if err := s.backlog.Ack(id, name, len(events)); err != nil {
+			s.log.Err(err).Str("id", id.String()).Str("target", name).Msg("Failed to update the delivery queue")
+		}
+
+		s.log.Info().
+			Str("id", id.String()).
+			Str("target", name).
+			Str("status", event.Status.String()).
+			Int("missed", len(event.Missed)).
+			Msg("Target recovered, delivered queued status updates")
+	}
+}
+
+// postpone queues the event of the state for the targets that failed to receive it.
+//
+// Parameters:
+//   - id: The UUID of the webhook.
+//   - st: The state whose pending targets are unavailable.
+func (s *StateManager) postpone(id uuid.UUID, st state) {
+	if s.backlog == nil {
+		return
+	}
+
+	for name := range st.pending {
+		if err := s.backlog.Push(id, name, st.event(id)); err != nil {
+			s.log.Err(err).Str("id", id.String()).Str("target", name).Msg("Failed to queue status update")
+
+			continue
+		}
+
+		s.log.Warn().
+			Str("id", id.String()).
+			Str("target", name).
+			Str("status", st.status.String()).
+			Msg("Target is unavailable, queuing status updates until it recovers")
+	}
+}
+
+// temporary is implemented by the errors that tell whether a retry may succeed,
+// e.g. the errors of the HTTP responses of the targets.
+type temporary interface {
+	Temporary() bool
+}
+
+// rejected keeps the event as a dead letter if the target rejected it
+// permanently, e.g. with a 4xx response, instead of retrying it.
+//
+// The target is not treated as unavailable: a rejected event says nothing
+// about the next ones, so they are delivered as usual.
+//
+// Returns:
+//   - Whether the event is kept as a dead letter.
+func (s *StateManager) rejected(target entities.Target, event entities.Event, err error) bool {
+	var tmp temporary
+	if s.deadLetters == nil || !errors.As(err, &tmp) || tmp.Temporary() {
+		return false
+	}
+
+	dead := entities.DeadLetter{
+		ID:       uuid.Nil,
+		Target:   target.Name,
+		Event:    event,
+		Error:    err.Error(),
+		Attempts: 1,
+		At:       time.Now(),
+	}
+
+	if err := s.deadLetters.Add(dead); err != nil {
+		s.log.Err(err).
+			Str("id", event.ID.String()).
+			Str("target", target.Name).
+			Msg("Failed to keep the rejected status update as a dead letter")
+
+		return false
+	}
+
+	s.log.Warn().
+		Str("id", event.ID.String()).
+		Str("target", target.Name).
+		Str("status", event.Status.String()).
+		Msg("Target rejected status update, keeping it as a dead letter")
+
+	return true
+}
+
+// queue queues the event if the target is unavailable.
+//
+// Returns:
+//   - Whether the target is unavailable and the event is queued.
+//   - An error if the event cannot be queued.
+func (s *StateManager) queue(target entities.Target, event entities.Event) (bool, error) {
+	if s.backlog == nil || len(s.backlog.Queued(event.ID, target.Name)) == 0 {
+		return false, nil
+	}
+
+	return true, s.backlog.Push(event.ID, target.Name, event)
+}
+
+// CatchUp notifies the targets of the services whose silence has ended.
+//
+// A transition that happened during a silence is only recorded. Once the silence
+// ends, the targets receive the current status of the service, e.g. the service
+// is still down after the maintenance window. The downtime held back because a
+// dependency is down is caught up the same way once the dependency recovers. CatchUp blocks until the context
+// is canceled.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the catch-up loop.
+func (s *StateManager) CatchUp(ctx context.Context) {
+	// The interval between the checks of the silenced services.
+	const interval = 15 * time.Second
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, id := range s.muted.list() {
+				s.catchUp(ctx, id)
+			}
+		}
+	}
+}
+
+// catchUp delivers the current status of the service if its silence has ended
+// and its dependencies are up.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - id: The UUID of the service.
+func (s *StateManager) catchUp(ctx context.Context, id uuid.UUID) {
+	// The time the status Down is remembered after every target is notified.
+	const downTTL = 24 * time.Hour
+
+	if !s.active() || s.silenced(ctx, id) {
+		return
+	}
+
+	// Lock the mutex to serialize with the garbage collector.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// The service may have been caught up already, e.g. it recovered within the silence.
+	current, ok := s.cache.Get(id)
+	if !ok || !current.muted {
+		s.unmute(id)
+
+		return
+	}
+
+	next := *current
+
+	// Keep holding the downtime back while the dependency is down.
+	if next.status == entities.Down && next.cause != uuid.Nil {
+		if next.cause = s.cause(id); s.suppressed(next) {
+			return
+		}
+	}
+
+	targets, err := s.repo.Get(ctx, id)
+	if err != nil {
+		// Retry on the next tick.
+		return
+	}
+
+	s.log.Info().
+		Str("id", id.String()).
+		Str("status", current.status.String()).
+		Msg("Silence ended, catching up")
+
+	next.muted = false
+	targets, next.deferred = s.escalate(id, targets, next, nil)
+	next.pending, _ = s.deliver(ctx, targets, next.event(id))
+	s.unmute(id)
+
+	// The targets that failed are retried like any other delivery.
+	if next.status == entities.Down && len(next.pending) == 0 {
+		s.cache.Add(id, next, downTTL)
+
+		return
+	}
+
+	// The TTL of the status in the cache, as in Beat.
+	s.cache.Add(id, next, s.ttl(id, next.ttl))
+}
+
+// ttl returns the time the status of the service is kept in the cache without
+// heartbeats: a minute, or the TTL proposed by the agent capped at a day,
+// extended by the jitter of the service. If the service has a schedule, the
+// status is kept until its next scheduled heartbeat is late.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//   - proposed: The TTL proposed by the agent, or zero.
+func (s *StateManager) ttl(id uuid.UUID, proposed time.Duration) time.Duration {
+	// The default TTL (Time to Live) of the status in the cache.
+	ttl := time.Minute
+
+	// The longest TTL the agents may propose.
+	const maxTTL = 24 * time.Hour
+
+	if proposed > 0 {
+		ttl = min(proposed, maxTTL)
+	}
+
+	if s.tolerances == nil {
+		return ttl
+	}
+
+	return s.tolerances.Tolerance(id).TTL(ttl, time.Now())
+}
+
+// tolerance returns the timing tolerance of the service.
+//
+// Parameters:
+//   - id: The UUID of the service.
+func (s *StateManager) tolerance(id uuid.UUID) entities.Tolerance {
+	if s.tolerances == nil {
+		return entities.Tolerance{} //nolint:exhaustruct
+	}
+
+	return s.tolerances.Tolerance(id)
+}
+
+// misses returns the number of the consecutive intervals the service has to
+// miss before it is considered down.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//
+// Returns:
+//   - The number of the intervals, at least one.
+func (s *StateManager) misses(id uuid.UUID) int {
+	return max(1, s.tolerance(id).Missed)
+}
+
+// Forget drops the state of the service.
+//
+// It is used when the service is removed at runtime, e.g. archived by the
+// stale webhook policy: the service is neither caught up nor escalated, and
+// no downtime is reported once its status expires.
+//
+// Parameters:
+//   - id: The UUID of the service.
+func (s *StateManager) Forget(id uuid.UUID) {
+	// Lock the mutex to serialize with the garbage collector.
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cache.Delete(id)
+	s.unmute(id)
+	s.escalating.remove(id)
+	s.delayed.take(id)
+	s.throttling.forget(id)
+
+	if s.intervals != nil {
+		s.intervals.Forget(id)
+	}
+
+	if s.cadence != nil {
+		s.cadence.Forget(id)
+	}
+}
+
+// Status returns the current status of the service.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//
+// Returns:
+//   - The current status of the service.
+//   - false if the status of the service is unknown, e.g. it has never sent a
+//     heartbeat or has been down for long.
+func (s *StateManager) Status(id uuid.UUID) (entities.ServiceStatus, bool) {
+	current, ok := s.cache.Get(id)
+	if !ok {
+		return entities.ServiceStatus{ID: id}, false //nolint:exhaustruct
+	}
+
+	expires, _ := s.cache.Expiry(id)
+
+	return entities.ServiceStatus{
+		ID:       id,
+		Status:   current.status,
+		Since:    current.since,
+		LastSeen: current.seen,
+		Expires:  expires,
+		Attempt:  current.attempt,
+	}, true
+}
+
+// Tracked returns the number of the services whose status is known.
+func (s *StateManager) Tracked() int {
+	return s.cache.Len()
+}
+
+// Close stops the expiration of the statuses.
+//
+// It is called on shutdown, so that no downtime is reported for the services
+// whose heartbeats stopped because the server is going away. The states are
+// persisted if the snapshot file is set.
+func (s *StateManager) Close() {
+	s.cache.Close()
+}
+
+// record records the status transition in the history and publishes it to the watchers, if any.
+func (s *StateManager) record(id uuid.UUID, status entities.Status, at time.Time) {
+	if s.history != nil {
+		s.history.Record(id, status, at)
+	}
+
+	if s.watcher != nil {
+		s.watcher.Publish(entities.Transition{ID: id, Status: status, At: at})
+	}
+}
+
+// group reports whether the UUID is the UUID of a group.
+func (s *StateManager) group(id uuid.UUID) bool {
+	if s.groups == nil {
+		return false
+	}
+
+	_, ok := s.groups.Group(id)
+
+	return ok
+}
+
+// grouped reports whether the service is a member of a group.
+func (s *StateManager) grouped(id uuid.UUID) bool {
+	return s.groups != nil && len(s.groups.Of(id)) > 0
+}
+
+// aggregate updates the statuses of the groups of the service.
+//
+// The status of every group is aggregated from the known statuses of its
+// members and sent like a heartbeat, so the targets of the group are notified
+// when it changes, and the TTL of the status of the group is prolonged
+// otherwise. The groups without a known member are left unchanged.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - id: The UUID of the member whose status changed.
+func (s *StateManager) aggregate(ctx context.Context, id uuid.UUID) {
+	if s.groups == nil {
+		return
+	}
+
+	for _, group := range s.groups.Of(id) {
+		statuses := make([]entities.Status, 0, len(group.Members))
+
+		for _, member := range group.Members {
+			if current, ok := s.cache.Get(member); ok {
+				statuses = append(statuses, current.status)
+			}
+		}
+
+		if len(statuses) == 0 {
+			continue
+		}
+
+		// Tell how many members are down, so the notification explains the status of the group.
+		status, down := group.Status(statuses)
+
+		reason := ""
+		if status != entities.Up {
+			reason = fmt.Sprintf("%s: %d of %d members are down", group.Name, down, len(group.Members))
+		}
+
+		err := s.beat(ctx, entities.Heartbeat{ID: group.ID, Status: status, Message: reason, TTL: 0, RequestID: "", Sent: time.Time{}})
+		if err != nil {
+			s.log.Err(err).Str("id", group.ID.String()).Str("group", group.Name).Msg("Failed to update the group")
+		}
+	}
+}
+
+// silenced reports whether the notifications of the service are silenced.
+func (s *StateManager) silenced(ctx context.Context, id uuid.UUID) bool {
+	return s.silencer != nil && s.silencer.Silenced(ctx, id)
+}
+
+// cause returns the first dependency of the service that is down.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//
+// Returns:
+//   - The UUID of the dependency, or uuid.Nil if every dependency is up or unknown.
+func (s *StateManager) cause(id uuid.UUID) uuid.UUID {
+	if s.dependencies == nil {
+		return uuid.Nil
+	}
+
+	for _, dependency := range s.dependencies.Dependencies(id) {
+		if current, ok := s.cache.Get(dependency); ok && current.status == entities.Down {
+			return dependency
+		}
+	}
+
+	return uuid.Nil
+}
+
+// suppressed reports whether the notification of the transition into the
+// state is held back, because a dependency of the service is down.
+func (s *StateManager) suppressed(st state) bool {
+	return s.cascade == entities.Suppress && st.status == entities.Down && st.cause != uuid.Nil
+}
+
+// mute records a silenced transition of the service.
+//
+// There are only two statuses, so if the previous transition was silenced as
+// well, the targets still hold the new status and there is nothing to catch up.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//   - current: The state before the transition, or nil if it is unknown.
+//
+// Returns:
+//   - Whether the targets have to be caught up when the silence ends.
+func (s *StateManager) mute(id uuid.UUID, current *state) bool {
+	muted := current == nil || !current.muted
+
+	s.log.Info().
+		Str("id", id.String()).
+		Bool("muted", muted).
+		Msg("Status update silenced")
+
+	if !muted {
+		s.unmute(id)
+
+		return false
+	}
+
+	s.muted.add(id)
+
+	return true
+}
+
+// unmute removes the service from the muted set.
+func (s *StateManager) unmute(id uuid.UUID) {
+	s.muted.remove(id)
+}
+
+// deliver sends the event to the targets concurrently.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - targets: The targets to send the event to.
+//   - event: The entities.Event to send.
+//
+// Returns:
+//   - The names of the targets that failed to receive the event, or nil.
+//   - An error joining the errors of all failed targets, or nil.
+func (s *StateManager) deliver(
+	ctx context.Context,
+	targets []entities.Target,
+	event entities.Event,
+) (map[string]struct{}, error) {
 	// Inform the logger that a status update is being sent.
 	// This logs the ID and status of the service being updated.
-	s.inform(id, status)
+	s.inform(event)
 
-	// Send the status update to the webhook.
-	// This sends a POST request to the webhook URL with the status as the request body.
-	if err := s.api.Send(ctx, target, status); err != nil {
-		return err
+	// Attach the agent that reported the service.
+	if s.agents != nil {
+		event.Agent, _ = s.agents.Agent(event.ID)
 	}
 
-	// Add the status to the cache.
-	// This adds the status to the cache so that it can be retrieved later.
-	s.cache.Add(id, state{status: status, attempt: 0}, ttl)
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		errs    []error
+		pending map[string]struct{}
+	)
 
-	return nil
+	// Fan out the event to all targets.
+	for _, target := range targets {
+		// Hold back the repeated notifications and attach the digest of the held ones.
+		event, held := s.throttling.hold(target, event, time.Now())
+		if held {
+			s.log.Debug().
+				Str("id", event.ID.String()).
+				Str("target", target.Name).
+				Str("status", event.Status.String()).
+				Msg("Status update throttled")
+
+			continue
+		}
+
+		// The events of the unavailable targets are queued until they recover.
+		if queued, err := s.queue(target, event); queued {
+			if err != nil {
+				mu.Lock()
+
+				if pending == nil {
+					pending = make(map[string]struct{})
+				}
+
+				pending[target.Name] = struct{}{}
+				errs = append(errs, err)
+
+				mu.Unlock()
+			}
+
+			continue
+		}
+
+		wg.Add(1)
+
+		go func(target entities.Target, event entities.Event) {
+			defer wg.Done()
+
+			err := s.api.Send(ctx, target, event)
+			if err == nil {
+				s.throttling.delivered(target, event, time.Now())
+
+				return
+			}
+
+			// Log the failed delivery to the target.
+			s.log.Err(err).
+				Str("id", event.ID.String()).
+				Str("target", target.Name).
+				Str("status", event.Status.String()).
+				Str("request_id", event.RequestID).
+				Msg("Failed to deliver status update")
+
+			if s.reporter != nil {
+				s.reporter.DeliveryFailed(event, target, err)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			// Retrying a rejected event is pointless: keep it as a dead letter.
+			if s.rejected(target, event, err) {
+				errs = append(errs, err)
+
+				return
+			}
+
+			if pending == nil {
+				pending = make(map[string]struct{})
+			}
+
+			pending[target.Name] = struct{}{}
+			errs = append(errs, err)
+		}(target, event)
+	}
+
+	// Wait for all deliveries to finish.
+	wg.Wait()
+
+	return pending, errors.Join(errs...)
+}
+
+// filter returns the targets whose names are in the set.
+//
+// If the set is nil, all targets are returned.
+func (s *StateManager) filter(targets []entities.Target, names map[string]struct{}) []entities.Target {
+	if names == nil {
+		return targets
+	}
+
+	result := make([]entities.Target, 0, len(names))
+
+	for _, target := range targets {
+		if _, ok := names[target.Name]; ok {
+			result = append(result, target)
+		}
+	}
+
+	return result
+}
+
+// active reports whether the StateManager is allowed to dispatch notifications.
+func (s *StateManager) active() bool {
+	return s.replica == nil || s.replica.Active()
 }
 
 // inform logs the sending of a status update.
 //
-// It logs the ID and status of the service being updated.
-// It takes the ID of the service and its status as parameters.
-func (s *StateManager) inform(id uuid.UUID, status entities.Status) {
+// It logs the ID and status of the service being updated, and the ID of the
+// request that caused the transition, if any.
+// It takes the event being sent as a parameter.
+func (s *StateManager) inform(event entities.Event) {
 	// Log the sending of a status update.
 	//
 	// The log message includes the ID and status of the service being updated.
-	// It takes the ID of the service and its status as parameters.
 	s.log.Info().
 		// The ID of the service.
-		Str("id", id.String()).
+		Str("id", event.ID.String()).
 		// The status of the service.
-		Str("status", status.String()).
+		Str("status", event.Status.String()).
+		// The ID of the request that caused the transition.
+		Str("request_id", event.RequestID).
 		// The message to log.
 		Msg("Sending status update")
 }
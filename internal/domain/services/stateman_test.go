@@ -0,0 +1,832 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/domain/services"
+)
+
+// recordingAPI is a services.API that records the delivered events.
+type recordingAPI struct {
+	mu     sync.Mutex
+	events []entities.Event
+}
+
+// Send records the event.
+func (a *recordingAPI) Send(_ context.Context, _ entities.Target, event entities.Event) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.events = append(a.events, event)
+
+	return nil
+}
+
+// statuses returns the statuses of the events delivered so far.
+func (a *recordingAPI) statuses() []entities.Status {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	statuses := make([]entities.Status, 0, len(a.events))
+	for _, event := range a.events {
+		statuses = append(statuses, event.Status)
+	}
+
+	return statuses
+}
+
+// delivered returns the events delivered so far.
+func (a *recordingAPI) delivered() []entities.Event {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return append([]entities.Event(nil), a.events...)
+}
+
+// staticRegistry is a services.WebhookRegistry with a fixed set of targets.
+type staticRegistry map[uuid.UUID][]entities.Target
+
+// Get returns the targets of the webhook.
+func (r staticRegistry) Get(_ context.Context, id uuid.UUID) ([]entities.Target, error) {
+	return r[id], nil
+}
+
+// All returns all webhook IDs.
+func (r staticRegistry) All() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(r))
+	for id := range r {
+		ids = append(ids, id)
+	}
+
+	return ids
+}
+
+// StateManagerTestSuite represents the test suite for the state manager functionality.
+type StateManagerTestSuite struct {
+	suite.Suite
+}
+
+// TestStateManager_Throttle verifies that the repeated notifications within the
+// throttle window are held back and attached as a digest to the next one.
+func (suite *StateManagerTestSuite) TestStateManager_Throttle() {
+	const window = 100 * time.Millisecond
+
+	id := uuid.New()
+	api := &recordingAPI{}
+	log := zerolog.Nop()
+
+	manager := services.NewStateManager(
+		api,
+		staticRegistry{id: {{Name: "slack", Type: "slack"}}},
+		&log,
+		services.WithThrottle(window),
+		services.WithCheckInterval(10*time.Millisecond),
+	)
+
+	// The service flaps: up, down, up, down, up.
+	for _, status := range []entities.Status{entities.Up, entities.Down, entities.Up, entities.Down, entities.Up} {
+		suite.Require().NoError(manager.Send(context.Background(), id, status))
+	}
+
+	// Only the first up and down are delivered, the rest are held back.
+	suite.Require().Equal([]entities.Status{entities.Up, entities.Down}, api.statuses())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go manager.Throttle(ctx)
+
+	// Once the window passes, the latest status is delivered with the digest
+	// of the events held before it.
+	suite.Eventually(func() bool { return len(api.statuses()) == 3 }, time.Second, 5*time.Millisecond)
+
+	event := api.delivered()[2]
+	suite.Equal(entities.Up, event.Status)
+	suite.Require().Len(event.Missed, 2)
+	suite.Equal(entities.Up, event.Missed[0].Status)
+	suite.Equal(entities.Down, event.Missed[1].Status)
+
+	// The digest is delivered once.
+	time.Sleep(2 * window)
+	suite.Len(api.statuses(), 3)
+}
+
+// TestStateManager_ThrottleForget verifies that the notification history of a
+// service is dropped together with its state.
+func (suite *StateManagerTestSuite) TestStateManager_ThrottleForget() {
+	id := uuid.New()
+	api := &recordingAPI{}
+	log := zerolog.Nop()
+
+	manager := services.NewStateManager(
+		api,
+		staticRegistry{id: {{Name: "slack", Type: "slack"}}},
+		&log,
+		services.WithThrottle(time.Hour),
+	)
+
+	for _, status := range []entities.Status{entities.Up, entities.Down, entities.Up} {
+		suite.Require().NoError(manager.Send(context.Background(), id, status))
+	}
+
+	suite.Require().Equal([]entities.Status{entities.Up, entities.Down}, api.statuses())
+
+	// The service is removed and added again: nothing is held back or digested.
+	manager.Forget(id)
+
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Up))
+	suite.Require().Equal([]entities.Status{entities.Up, entities.Down, entities.Up}, api.statuses())
+	suite.Empty(api.delivered()[2].Missed)
+}
+
+// TestStateManager_Delayed verifies that the recovery is flagged as unreachable
+// only if the delayed heartbeats show that the service kept running.
+func (suite *StateManagerTestSuite) TestStateManager_Delayed() {
+	id := uuid.New()
+	api := &recordingAPI{}
+	log := zerolog.Nop()
+
+	manager := services.NewStateManager(api, staticRegistry{id: {{Name: "slack", Type: "slack"}}}, &log)
+
+	// The service goes down for real.
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Up))
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Down))
+	manager.Delayed(context.Background(), id, time.Now().Add(-time.Hour))
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Up))
+
+	// The network goes down, the service keeps running.
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Down))
+	manager.Delayed(context.Background(), id, time.Now())
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Up))
+
+	suite.Require().Len(api.events, 5)
+	suite.False(api.events[2].Unreachable)
+	suite.True(api.events[4].Unreachable)
+}
+
+// TestStateManager_Degraded verifies that the degraded status is notified with
+// the reason reported by the agent, and a change of the reason alone is not.
+func (suite *StateManagerTestSuite) TestStateManager_Degraded() {
+	id := uuid.New()
+	api := &recordingAPI{}
+	log := zerolog.Nop()
+
+	manager := services.NewStateManager(api, staticRegistry{id: {{Name: "slack", Type: "slack"}}}, &log)
+
+	for _, heartbeat := range []entities.Heartbeat{
+		{ID: id, Status: entities.Up},
+		{ID: id, Status: entities.Degraded, Message: "replica lag", TTL: time.Hour},
+		{ID: id, Status: entities.Degraded, Message: "replica lag is growing", TTL: time.Hour},
+		{ID: id, Status: entities.Up},
+	} {
+		suite.Require().NoError(manager.Beat(context.Background(), heartbeat))
+	}
+
+	suite.Require().Len(api.events, 3)
+	suite.Equal(entities.Degraded, api.events[1].Status)
+	suite.Equal("replica lag", api.events[1].Reason)
+	suite.Equal(entities.Up, api.events[2].Status)
+	suite.Empty(api.events[2].Reason)
+}
+
+// TestStateManager_RequestID verifies that the event carries the request ID of
+// the heartbeat that caused the transition, rather than of the latest one.
+func (suite *StateManagerTestSuite) TestStateManager_RequestID() {
+	id := uuid.New()
+	api := &recordingAPI{}
+	log := zerolog.Nop()
+
+	manager := services.NewStateManager(api, staticRegistry{id: {{Name: "slack", Type: "slack"}}}, &log)
+
+	for _, heartbeat := range []entities.Heartbeat{
+		{ID: id, Status: entities.Up, RequestID: "req-1"},
+		{ID: id, Status: entities.Up, RequestID: "req-2"},
+		{ID: id, Status: entities.Degraded, RequestID: "req-3"},
+	} {
+		suite.Require().NoError(manager.Beat(context.Background(), heartbeat))
+	}
+
+	suite.Require().Len(api.events, 2)
+	suite.Equal("req-1", api.events[0].RequestID)
+	suite.Equal("req-3", api.events[1].RequestID)
+}
+
+// dependencyRegistry is a services.DependencyRegistry with fixed dependencies.
+type dependencyRegistry map[uuid.UUID][]uuid.UUID
+
+// Dependencies returns the dependencies of the service.
+func (r dependencyRegistry) Dependencies(id uuid.UUID) []uuid.UUID {
+	return r[id]
+}
+
+// TestStateManager_Dependencies verifies that the downtime of a service whose
+// dependency is down is held back with the suppress policy, and tagged with
+// the dependency with the tag policy.
+func (suite *StateManagerTestSuite) TestStateManager_Dependencies() {
+	database, app := uuid.New(), uuid.New()
+	log := zerolog.Nop()
+	registry := staticRegistry{
+		database: {{Name: "slack", Type: "slack"}},
+		app:      {{Name: "slack", Type: "slack"}},
+	}
+
+	for _, cascade := range []entities.Cascade{entities.Suppress, entities.Tag} {
+		api := &recordingAPI{}
+		manager := services.NewStateManager(api, registry, &log,
+			services.WithDependencies(dependencyRegistry{app: {database}}, cascade))
+
+		// The database fails, then the application, then both recover.
+		for _, step := range []struct {
+			id     uuid.UUID
+			status entities.Status
+		}{
+			{database, entities.Up},
+			{app, entities.Up},
+			{database, entities.Down},
+			{app, entities.Down},
+			{database, entities.Up},
+			{app, entities.Up},
+		} {
+			suite.Require().NoError(manager.Send(context.Background(), step.id, step.status))
+		}
+
+		if cascade == entities.Suppress {
+			// Neither the downtime of the application nor its recovery is notified.
+			suite.Require().Len(api.events, 4)
+			suite.Equal(database, api.events[3].ID)
+
+			continue
+		}
+
+		suite.Require().Len(api.events, 6)
+		suite.Equal(app, api.events[3].ID)
+		suite.Equal(entities.Down, api.events[3].Status)
+		suite.Equal(database, api.events[3].Cause)
+		suite.True(api.events[3].Caused())
+	}
+}
+
+// TestStateManager_Group verifies that the members of a group are notified
+// through the group once per transition of the group, following its quorum.
+func (suite *StateManagerTestSuite) TestStateManager_Group() {
+	group, first, second, third := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	api := &recordingAPI{}
+	log := zerolog.Nop()
+
+	target := []entities.Target{{Name: "slack", Type: "slack"}}
+	manager := services.NewStateManager(api,
+		staticRegistry{group: target, first: target, second: target, third: target},
+		&log,
+		services.WithGroups(services.NewGroups(entities.Group{
+			ID:      group,
+			Name:    "payments",
+			Members: []uuid.UUID{first, second, third},
+			Quorum:  2,
+		})),
+	)
+
+	for _, heartbeat := range []entities.Heartbeat{
+		{ID: first, Status: entities.Up},
+		{ID: second, Status: entities.Up},
+		{ID: third, Status: entities.Up},
+		{ID: first, Status: entities.Down},
+		{ID: second, Status: entities.Down},
+		{ID: third, Status: entities.Down},
+		{ID: group, Status: entities.Up},
+		{ID: first, Status: entities.Up},
+		{ID: second, Status: entities.Up},
+		{ID: third, Status: entities.Up},
+	} {
+		suite.Require().NoError(manager.Beat(context.Background(), heartbeat))
+	}
+
+	// Up, degraded below the quorum, down, degraded again and up.
+	suite.Require().Len(api.events, 5)
+
+	for _, event := range api.events {
+		suite.Equal(group, event.ID)
+	}
+
+	suite.Equal(entities.Up, api.events[0].Status)
+	suite.Equal(entities.Degraded, api.events[1].Status)
+	suite.Equal(entities.Down, api.events[2].Status)
+	suite.Equal("payments: 2 of 3 members are down", api.events[2].Reason)
+	suite.Equal(entities.Degraded, api.events[3].Status)
+	suite.Equal(entities.Up, api.events[4].Status)
+}
+
+// TestStateManager_Snapshot verifies that the restored states are not notified again.
+func (suite *StateManagerTestSuite) TestStateManager_Snapshot() {
+	id := uuid.New()
+	api := &recordingAPI{}
+	log := zerolog.Nop()
+	registry := staticRegistry{id: {{Name: "slack", Type: "slack"}}}
+	path := filepath.Join(suite.T().TempDir(), "state.json")
+
+	first := services.NewStateManager(api, registry, &log, services.WithSnapshot(path, time.Hour))
+	suite.Require().NoError(first.Send(context.Background(), id, entities.Up))
+	first.Close()
+
+	// The restarted manager knows that the service is up.
+	second := services.NewStateManager(api, registry, &log, services.WithSnapshot(path, time.Hour))
+	defer second.Close()
+
+	suite.Require().NoError(second.Send(context.Background(), id, entities.Up))
+	suite.Len(api.events, 1)
+}
+
+// TestStateManager_Status verifies that the current status of a service is reported.
+func (suite *StateManagerTestSuite) TestStateManager_Status() {
+	id := uuid.New()
+	log := zerolog.Nop()
+
+	manager := services.NewStateManager(&recordingAPI{}, staticRegistry{id: {{Name: "slack", Type: "slack"}}}, &log)
+	defer manager.Close()
+
+	// The status is unknown before the first heartbeat.
+	_, ok := manager.Status(id)
+	suite.False(ok)
+
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Up))
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Down))
+
+	current, ok := manager.Status(id)
+	suite.Require().True(ok)
+	suite.Equal(id, current.ID)
+	suite.Equal(entities.Down, current.Status)
+	suite.False(current.Since.IsZero())
+	suite.Zero(current.Attempt)
+}
+
+// TestStateManager_Agent verifies that the events carry the agent reporting the service.
+func (suite *StateManagerTestSuite) TestStateManager_Agent() {
+	id := uuid.New()
+	api := &recordingAPI{}
+	log := zerolog.Nop()
+	agents := services.NewAgentDirectory()
+
+	manager := services.NewStateManager(
+		api,
+		staticRegistry{id: {{Name: "slack", Type: "slack"}}},
+		&log,
+		services.WithAgents(agents),
+	)
+	defer manager.Close()
+
+	agents.Report(id, entities.Agent{Hostname: "web-1", Version: "1.2.3", Labels: nil})
+
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Up))
+	suite.Require().Len(api.events, 1)
+	suite.Equal("web-1", api.events[0].Agent.Hostname)
+	suite.Equal("1.2.3", api.events[0].Agent.Version)
+}
+
+// failingAPI is a services.API that fails every delivery.
+type failingAPI struct{}
+
+// errUnreachable is the error of the failingAPI.
+var errUnreachable = errors.New("the target is unreachable")
+
+// Send fails the delivery.
+func (failingAPI) Send(_ context.Context, _ entities.Target, _ entities.Event) error {
+	return errUnreachable
+}
+
+// recordingReporter is a services.FailureReporter that records the failed deliveries.
+type recordingReporter struct {
+	mu      sync.Mutex
+	targets []string
+}
+
+// DeliveryFailed records the target of the failed delivery.
+func (r *recordingReporter) DeliveryFailed(_ entities.Event, target entities.Target, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if errors.Is(err, errUnreachable) {
+		r.targets = append(r.targets, target.Name)
+	}
+}
+
+// TestStateManager_ReportsFailures verifies that the failed deliveries are
+// reported with their targets.
+func (suite *StateManagerTestSuite) TestStateManager_ReportsFailures() {
+	id := uuid.New()
+	log := zerolog.Nop()
+	reporter := &recordingReporter{}
+
+	manager := services.NewStateManager(
+		failingAPI{},
+		staticRegistry{id: {{Name: "slack", Type: "slack"}}},
+		&log,
+		services.WithReporter(reporter),
+	)
+	defer manager.Close()
+
+	suite.Require().ErrorIs(manager.Send(context.Background(), id, entities.Down), errUnreachable)
+	suite.Equal([]string{"slack"}, reporter.targets)
+}
+
+// staticTolerances is a services.ToleranceRegistry with the same tolerance for every service.
+type staticTolerances entities.Tolerance
+
+// Tolerance returns the tolerance of the service.
+func (t staticTolerances) Tolerance(uuid.UUID) entities.Tolerance {
+	return entities.Tolerance(t)
+}
+
+// TestStateManager_Skew verifies that the heartbeats are accounted at the time
+// the agent sent them, unless the clocks are within the skew of the service.
+func (suite *StateManagerTestSuite) TestStateManager_Skew() {
+	const ttl = 10 * time.Second
+
+	log := zerolog.Nop()
+	expires := func(skew time.Duration, sent time.Time) (entities.ServiceStatus, error) {
+		id := uuid.New()
+		manager := services.NewStateManager(
+			&recordingAPI{},
+			staticRegistry{id: {{Name: "slack", Type: "slack"}}},
+			&log,
+			services.WithTolerances(staticTolerances{Skew: skew}),
+		)
+		defer manager.Close()
+
+		err := manager.Beat(context.Background(), entities.Heartbeat{ID: id, Status: entities.Up, TTL: ttl, Sent: sent})
+		current, _ := manager.Status(id)
+
+		return current, err
+	}
+
+	sent := time.Now().Add(-3 * time.Second)
+
+	// Without the skew, the status expires the TTL after the heartbeat was sent.
+	strict, err := expires(0, sent)
+	suite.Require().NoError(err)
+	suite.True(strict.LastSeen.Equal(sent))
+	suite.WithinDuration(sent.Add(ttl), strict.Expires, time.Second)
+
+	// Within the skew, the heartbeat is accounted as received, so the status expires later.
+	tolerant, err := expires(5*time.Second, sent)
+	suite.Require().NoError(err)
+	suite.WithinDuration(time.Now(), tolerant.LastSeen, time.Second)
+	suite.WithinDuration(time.Now().Add(ttl), tolerant.Expires, time.Second)
+	suite.Greater(tolerant.Expires.Sub(strict.Expires), 2*time.Second)
+
+	// The heartbeats from the future beyond the skew are rejected.
+	_, err = expires(5*time.Second, time.Now().Add(time.Minute))
+	suite.Require().ErrorIs(err, entities.ErrClockSkew)
+
+	// The heartbeats that expired on the way are ignored.
+	expired, err := expires(0, time.Now().Add(-time.Minute))
+	suite.Require().NoError(err)
+	suite.True(expired.LastSeen.IsZero())
+	suite.True(expired.Expires.IsZero())
+}
+
+// delivery is a delivery recorded by the flakyAPI.
+type delivery struct {
+	target string
+	status entities.Status
+}
+
+// flakyAPI is a services.API failing the deliveries to the targets named in down.
+type flakyAPI struct {
+	mu         sync.Mutex
+	down       map[string]bool
+	reject     map[string]bool
+	deliveries []delivery
+}
+
+// rejection is the error of a target rejecting the event permanently, e.g. with a 4xx response.
+type rejection struct{}
+
+// Error returns the description of the rejection.
+func (rejection) Error() string {
+	return "the target rejected the event"
+}
+
+// Temporary reports that a retry cannot succeed.
+func (rejection) Temporary() bool {
+	return false
+}
+
+// Send records the delivery and fails it if the target is down or rejects the events.
+func (a *flakyAPI) Send(_ context.Context, target entities.Target, event entities.Event) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.deliveries = append(a.deliveries, delivery{target: target.Name, status: event.Status})
+
+	if a.down[target.Name] {
+		return errUnreachable
+	}
+
+	if a.reject[target.Name] {
+		return rejection{}
+	}
+
+	return nil
+}
+
+// received returns the deliveries recorded so far.
+func (a *flakyAPI) received() []delivery {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return append([]delivery(nil), a.deliveries...)
+}
+
+// recover makes the deliveries to the target succeed.
+func (a *flakyAPI) recover(name string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.down, name)
+	delete(a.reject, name)
+}
+
+// deadLetterList is a services.DeadLetterSink keeping the dead letters in memory.
+type deadLetterList struct {
+	mu      sync.Mutex
+	letters []entities.DeadLetter
+}
+
+// Add keeps the dead letter.
+func (l *deadLetterList) Add(dead entities.DeadLetter) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.letters = append(l.letters, dead)
+
+	return nil
+}
+
+// memoryBacklog is a services.Backlog keeping the queues in memory.
+type memoryBacklog struct {
+	mu     sync.Mutex
+	queues map[uuid.UUID]map[string][]entities.Event
+}
+
+// Push appends the event to the queue of the target.
+func (b *memoryBacklog) Push(id uuid.UUID, target string, event entities.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.queues[id] == nil {
+		b.queues[id] = make(map[string][]entities.Event)
+	}
+
+	b.queues[id][target] = append(b.queues[id][target], event)
+
+	return nil
+}
+
+// Queued returns the events queued for the target.
+func (b *memoryBacklog) Queued(id uuid.UUID, target string) []entities.Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]entities.Event(nil), b.queues[id][target]...)
+}
+
+// Targets returns the names of the targets with queued events per webhook.
+func (b *memoryBacklog) Targets() map[uuid.UUID][]string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	targets := make(map[uuid.UUID][]string)
+
+	for id, queues := range b.queues {
+		for name, events := range queues {
+			if len(events) > 0 {
+				targets[id] = append(targets[id], name)
+			}
+		}
+	}
+
+	return targets
+}
+
+// Ack removes the n oldest events from the queue of the target.
+func (b *memoryBacklog) Ack(id uuid.UUID, target string, n int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.queues[id][target] = b.queues[id][target][n:]
+
+	return nil
+}
+
+// TestStateManager_Rejected verifies that the events rejected by a target are
+// kept as dead letters, neither retried nor holding back the next events.
+func (suite *StateManagerTestSuite) TestStateManager_Rejected() {
+	id := uuid.New()
+	log := zerolog.Nop()
+	api := &flakyAPI{down: map[string]bool{}, reject: map[string]bool{"slack": true}}
+	letters := &deadLetterList{}
+	backlog := &memoryBacklog{queues: make(map[uuid.UUID]map[string][]entities.Event)}
+
+	manager := services.NewStateManager(
+		api,
+		staticRegistry{id: {{Name: "slack", Type: "slack"}, {Name: "pagerduty", Type: "pagerduty"}}},
+		&log,
+		services.WithBacklog(backlog),
+		services.WithDeadLetters(letters),
+	)
+	defer manager.Close()
+
+	suite.Require().ErrorIs(manager.Send(context.Background(), id, entities.Up), rejection{})
+	suite.ElementsMatch([]delivery{{"slack", entities.Up}, {"pagerduty", entities.Up}}, api.received())
+
+	suite.Require().Len(letters.letters, 1)
+	suite.Equal("slack", letters.letters[0].Target)
+	suite.Equal(entities.Up, letters.letters[0].Event.Status)
+	suite.Equal(1, letters.letters[0].Attempts)
+	suite.Empty(backlog.Targets())
+
+	// The rejected event is not retried.
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Up))
+	suite.Len(api.received(), 2)
+
+	// The next event is delivered to the target as usual.
+	api.recover("slack")
+
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Down))
+	suite.ElementsMatch([]delivery{{"slack", entities.Down}, {"pagerduty", entities.Down}}, api.received()[2:])
+	suite.Empty(backlog.Targets())
+	suite.Len(letters.letters, 1)
+}
+
+// TestStateManager_RejectedRetried verifies that the events rejected by a
+// target are retried if no dead letters are kept.
+func (suite *StateManagerTestSuite) TestStateManager_RejectedRetried() {
+	id := uuid.New()
+	log := zerolog.Nop()
+	api := &flakyAPI{down: map[string]bool{}, reject: map[string]bool{"slack": true}}
+
+	manager := services.NewStateManager(api, staticRegistry{id: {{Name: "slack", Type: "slack"}}}, &log)
+	defer manager.Close()
+
+	suite.Require().ErrorIs(manager.Send(context.Background(), id, entities.Up), rejection{})
+
+	api.recover("slack")
+
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Up))
+	suite.Equal([]delivery{{"slack", entities.Up}, {"slack", entities.Up}}, api.received())
+}
+
+// TestStateManager_RetriesFailedTargets verifies that the repeated heartbeats
+// retry only the targets that failed to receive the status.
+func (suite *StateManagerTestSuite) TestStateManager_RetriesFailedTargets() {
+	id := uuid.New()
+	log := zerolog.Nop()
+	api := &flakyAPI{down: map[string]bool{"pagerduty": true}}
+
+	manager := services.NewStateManager(
+		api,
+		staticRegistry{id: {{Name: "slack", Type: "slack"}, {Name: "pagerduty", Type: "pagerduty"}}},
+		&log,
+	)
+	defer manager.Close()
+
+	suite.Require().ErrorIs(manager.Send(context.Background(), id, entities.Up), errUnreachable)
+	suite.ElementsMatch([]delivery{{"slack", entities.Up}, {"pagerduty", entities.Up}}, api.received())
+
+	// The next heartbeat retries the failed target only.
+	api.down["pagerduty"] = false
+
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Up))
+	suite.Equal(delivery{"pagerduty", entities.Up}, api.received()[2])
+
+	// Once every target received the status, the heartbeats deliver nothing.
+	suite.Require().NoError(manager.Send(context.Background(), id, entities.Up))
+	suite.Len(api.received(), 3)
+}
+
+// TestStateManager_GivesUpThenDown verifies that a service that stops
+// reporting is reported as down, even once the delivery of its status Up was
+// given up on.
+func (suite *StateManagerTestSuite) TestStateManager_GivesUpThenDown() {
+	id := uuid.New()
+	log := zerolog.Nop()
+	api := &flakyAPI{down: map[string]bool{"pagerduty": true}}
+
+	manager := services.NewStateManager(
+		api,
+		staticRegistry{id: {{Name: "slack", Type: "slack"}, {Name: "pagerduty", Type: "pagerduty"}}},
+		&log,
+		services.WithExpiryInterval(5*time.Millisecond),
+	)
+	defer manager.Close()
+
+	// The delivery to the failing target is retried until the attempts are
+	// exhausted, then the service stops reporting.
+	heartbeat := entities.Heartbeat{ID: id, Status: entities.Up, TTL: time.Hour}
+	for range 5 {
+		_ = manager.Beat(context.Background(), heartbeat)
+	}
+
+	heartbeat.TTL = 50 * time.Millisecond
+	_ = manager.Beat(context.Background(), heartbeat)
+
+	current, ok := manager.Status(id)
+	suite.Require().True(ok)
+	suite.Equal(uint32(5), current.Attempt)
+
+	suite.Eventually(func() bool {
+		current, ok := manager.Status(id)
+
+		return ok && current.Status == entities.Down
+	}, time.Second, 5*time.Millisecond)
+
+	suite.Contains(api.received(), delivery{"slack", entities.Down})
+}
+
+// TestStateManager_Missed verifies that a service is reported as down only
+// once it missed the configured number of intervals in a row.
+func (suite *StateManagerTestSuite) TestStateManager_Missed() {
+	const ttl = 200 * time.Millisecond
+
+	id := uuid.New()
+	log := zerolog.Nop()
+	api := &flakyAPI{down: map[string]bool{}}
+
+	manager := services.NewStateManager(
+		api,
+		staticRegistry{id: {{Name: "slack", Type: "slack"}}},
+		&log,
+		services.WithTolerances(staticTolerances{Missed: 3}),
+		services.WithExpiryInterval(5*time.Millisecond),
+	)
+	defer manager.Close()
+
+	suite.Require().NoError(manager.Beat(context.Background(), entities.Heartbeat{ID: id, Status: entities.Up, TTL: ttl}))
+
+	// Two intervals are missed: the service is still up.
+	time.Sleep(5 * ttl / 2)
+
+	current, ok := manager.Status(id)
+	suite.Require().True(ok)
+	suite.Equal(entities.Up, current.Status)
+	suite.Equal([]delivery{{"slack", entities.Up}}, api.received())
+
+	// The third interval is missed: the service is down.
+	suite.Eventually(func() bool {
+		current, ok := manager.Status(id)
+
+		return ok && current.Status == entities.Down
+	}, time.Second, 5*time.Millisecond)
+
+	suite.Equal([]delivery{{"slack", entities.Up}, {"slack", entities.Down}}, api.received())
+}
+
+// TestStateManager_MissedRetries verifies that the targets that failed to
+// receive the status are retried while the service misses its intervals.
+func (suite *StateManagerTestSuite) TestStateManager_MissedRetries() {
+	const ttl = 100 * time.Millisecond
+
+	id := uuid.New()
+	log := zerolog.Nop()
+	api := &flakyAPI{down: map[string]bool{"pagerduty": true}}
+
+	manager := services.NewStateManager(
+		api,
+		staticRegistry{id: {{Name: "slack", Type: "slack"}, {Name: "pagerduty", Type: "pagerduty"}}},
+		&log,
+		services.WithTolerances(staticTolerances{Missed: 3}),
+		services.WithExpiryInterval(5*time.Millisecond),
+	)
+	defer manager.Close()
+
+	suite.Require().ErrorIs(manager.Beat(context.Background(), entities.Heartbeat{ID: id, Status: entities.Up, TTL: ttl}), errUnreachable)
+
+	api.recover("pagerduty")
+
+	// The status Up reaches the recovered target before the service is down.
+	suite.Eventually(func() bool {
+		current, ok := manager.Status(id)
+
+		return ok && current.Status == entities.Down
+	}, 2*time.Second, 5*time.Millisecond)
+
+	received := api.received()
+	suite.Require().Len(received, 5)
+	suite.Equal(delivery{"pagerduty", entities.Up}, received[2])
+	suite.ElementsMatch([]delivery{{"slack", entities.Down}, {"pagerduty", entities.Down}}, received[3:])
+}
+
+// TestStateManagerTestSuite runs the state manager test suite.
+func TestStateManagerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(StateManagerTestSuite))
+}
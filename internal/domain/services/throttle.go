@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// maxHeld is the maximum number of the held events kept per target for the digest.
+const maxHeld = 20
+
+// throttleKey identifies a target of a webhook.
+type throttleKey struct {
+	// id is the UUID of the webhook.
+	id uuid.UUID
+
+	// target is the name of the target.
+	target string
+}
+
+// throttleEntry holds the notification history of a target.
+type throttleEntry struct {
+	// last maps the statuses to the time they were last delivered to the target.
+	last map[entities.Status]time.Time
+
+	// delivered is the status last delivered to the target.
+	delivered entities.Status
+
+	// window is the throttle window of the target.
+	window time.Duration
+
+	// held is the list of the events held back within the window, oldest first.
+	held []entities.Event
+}
+
+// throttle limits the rate of the notifications per target.
+//
+// A status is delivered to a target at most once per window: the repeated
+// notifications (e.g. of a flapping service) are held back and attached as a
+// digest to the next notification delivered to the target.
+type throttle struct {
+	// window is the default throttle window, zero disables the throttling.
+	window time.Duration
+
+	// entries maps the targets to their notification history.
+	entries map[throttleKey]*throttleEntry
+
+	// mu is the mutex used to synchronize access to the entries.
+	mu sync.Mutex
+}
+
+// newThrottle creates a new throttle with the default window.
+func newThrottle(window time.Duration) *throttle {
+	return &throttle{window: window, entries: make(map[throttleKey]*throttleEntry), mu: sync.Mutex{}}
+}
+
+// hold reports whether the event has to be held back for the target.
+//
+// If it does, the event is added to the digest of the target. Otherwise the
+// held events are attached to the returned event as missed.
+//
+// Parameters:
+//   - target: The target the event is delivered to.
+//   - event: The event to deliver.
+//   - now: The current time.
+//
+// Returns:
+//   - The event to deliver with the digest attached.
+//   - Whether the event is held back.
+func (t *throttle) hold(target entities.Target, event entities.Event, now time.Time) (entities.Event, bool) {
+	window := t.window
+	if target.Throttle > 0 {
+		window = target.Throttle
+	}
+
+	if window <= 0 {
+		return event, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry := t.entries[throttleKey{id: event.ID, target: target.Name}]
+	if entry == nil {
+		return event, false
+	}
+
+	// The status was delivered recently: hold the event back.
+	if last, ok := entry.last[event.Status]; ok && now.Sub(last) < window {
+		entry.window = window
+		entry.held = append(entry.held, event)
+
+		if len(entry.held) > maxHeld {
+			entry.held = entry.held[len(entry.held)-maxHeld:]
+		}
+
+		return event, true
+	}
+
+	// Attach the digest of the held events. The latest held event is the one
+	// delivered once its window passes, so it is not repeated in the digest.
+	held := entry.held
+	if n := len(held); n > 0 && held[n-1].Status == event.Status && held[n-1].Time.Equal(event.Time) {
+		held = held[:n-1]
+	}
+
+	if len(held) > 0 {
+		event.Missed = append(slices.Clone(held), event.Missed...)
+	}
+
+	return event, false
+}
+
+// delivered records the delivery of the event to the target and clears its digest.
+//
+// Parameters:
+//   - target: The target the event was delivered to.
+//   - event: The delivered event.
+//   - now: The current time.
+func (t *throttle) delivered(target entities.Target, event entities.Event, now time.Time) {
+	if t.window <= 0 && target.Throttle <= 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := throttleKey{id: event.ID, target: target.Name}
+
+	entry := t.entries[key]
+	if entry == nil {
+		entry = &throttleEntry{last: make(map[entities.Status]time.Time), delivered: event.Status, window: 0, held: nil}
+		t.entries[key] = entry
+	}
+
+	entry.last[event.Status] = now
+	entry.delivered = event.Status
+	entry.held = nil
+}
+
+// forget drops the notification history of every target of the webhook.
+//
+// Parameters:
+//   - id: The UUID of the webhook.
+func (t *throttle) forget(id uuid.UUID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for key := range t.entries {
+		if key.id == id {
+			delete(t.entries, key)
+		}
+	}
+}
+
+// due returns the latest held events whose window has passed and whose status
+// differs from the status last delivered to the target.
+//
+// The held events with the status the target already knows are kept and are
+// attached to the next notification.
+//
+// Parameters:
+//   - now: The current time.
+//
+// Returns:
+//   - The latest held events indexed by the webhook UUID and the target name.
+func (t *throttle) due(now time.Time) map[uuid.UUID]map[string]entities.Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	result := make(map[uuid.UUID]map[string]entities.Event)
+
+	for key, entry := range t.entries {
+		if len(entry.held) == 0 {
+			continue
+		}
+
+		latest := entry.held[len(entry.held)-1]
+		if latest.Status == entry.delivered || now.Sub(entry.last[latest.Status]) < entry.window {
+			continue
+		}
+
+		if result[key.id] == nil {
+			result[key.id] = make(map[string]entities.Event)
+		}
+
+		result[key.id][key.target] = latest
+	}
+
+	return result
+}
+
+// Throttle delivers the notifications held back by the throttling.
+//
+// When the throttle window of a target passes and the latest held status differs
+// from the status the target knows, the status is delivered with the digest of
+// the held events. Throttle blocks until the context is canceled.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the loop.
+func (s *StateManager) Throttle(ctx context.Context) {
+	// Set a timeout for the delivery.
+	const timeout = 15 * time.Second
+
+	ticker := time.NewTicker(s.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if !s.active() {
+				continue
+			}
+
+			for id, events := range s.throttling.due(now) {
+				func() {
+					ctx, cancel := context.WithTimeout(ctx, timeout)
+					defer cancel()
+
+					targets, err := s.repo.Get(ctx, id)
+					if err != nil {
+						return
+					}
+
+					for _, target := range targets {
+						if event, ok := events[target.Name]; ok {
+							// The digest is attached by deliver.
+							event.Missed = nil
+							_, _ = s.deliver(ctx, []entities.Target{target}, event)
+						}
+					}
+				}()
+			}
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"sync"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// Watcher broadcasts the status transitions to the subscribers.
+//
+// The transitions are published without blocking: a subscriber that does not
+// keep up with them is dropped, and its channel is closed, so it can tell that
+// it missed transitions and resubscribe.
+type Watcher struct {
+	// mu protects the subscribers.
+	mu sync.Mutex
+
+	// subscribers are the channels of the subscribers.
+	subscribers map[chan entities.Transition]struct{}
+}
+
+// NewWatcher creates a Watcher without subscribers.
+//
+// Returns:
+//   - A pointer to the initialized Watcher.
+func NewWatcher() *Watcher {
+	return &Watcher{
+		mu:          sync.Mutex{},
+		subscribers: make(map[chan entities.Transition]struct{}),
+	}
+}
+
+// Subscribe registers a subscriber of the status transitions.
+//
+// Parameters:
+//   - buffer: The number of the transitions buffered for the subscriber
+//     before it is dropped.
+//
+// Returns:
+//   - The channel of the transitions. It is closed when the subscriber is
+//     dropped or unsubscribed.
+//   - The function that unsubscribes the subscriber. It may be called more than once.
+func (w *Watcher) Subscribe(buffer int) (<-chan entities.Transition, func()) {
+	events := make(chan entities.Transition, max(buffer, 1))
+
+	w.mu.Lock()
+	w.subscribers[events] = struct{}{}
+	w.mu.Unlock()
+
+	return events, func() {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+
+		w.drop(events)
+	}
+}
+
+// Publish sends the transition to the subscribers.
+//
+// Parameters:
+//   - transition: The status transition.
+func (w *Watcher) Publish(transition entities.Transition) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for events := range w.subscribers {
+		select {
+		case events <- transition:
+		default:
+			// The subscriber does not keep up with the transitions.
+			w.drop(events)
+		}
+	}
+}
+
+// Len returns the number of the subscribers.
+func (w *Watcher) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return len(w.subscribers)
+}
+
+// drop removes the subscriber and closes its channel. The mutex must be held.
+func (w *Watcher) drop(events chan entities.Transition) {
+	if _, ok := w.subscribers[events]; ok {
+		delete(w.subscribers, events)
+		close(events)
+	}
+}
@@ -0,0 +1,94 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/domain/services"
+)
+
+// WatcherTestSuite represents the test suite for the watcher of the status transitions.
+type WatcherTestSuite struct {
+	suite.Suite
+}
+
+// TestWatcher_Transitions verifies that the status transitions are published,
+// while the heartbeats that do not change the status are not.
+func (suite *WatcherTestSuite) TestWatcher_Transitions() {
+	id := uuid.New()
+	log := zerolog.Nop()
+	watcher := services.NewWatcher()
+
+	manager := services.NewStateManager(
+		&recordingAPI{},
+		staticRegistry{id: {{Name: "slack", Type: "slack"}}},
+		&log,
+		services.WithWatcher(watcher),
+	)
+	defer manager.Close()
+
+	events, unsubscribe := watcher.Subscribe(10)
+	defer unsubscribe()
+
+	for _, status := range []entities.Status{entities.Up, entities.Up, entities.Down} {
+		suite.Require().NoError(manager.Send(context.Background(), id, status))
+	}
+
+	suite.Require().Len(events, 2)
+	suite.Equal(entities.Up, (<-events).Status)
+
+	transition := <-events
+	suite.Equal(id, transition.ID)
+	suite.Equal(entities.Down, transition.Status)
+}
+
+// TestWatcher_Slow verifies that a subscriber that does not keep up is dropped.
+func (suite *WatcherTestSuite) TestWatcher_Slow() {
+	watcher := services.NewWatcher()
+
+	events, unsubscribe := watcher.Subscribe(1)
+	suite.Equal(1, watcher.Len())
+
+	watcher.Publish(entities.Transition{ID: uuid.New(), Status: entities.Up})
+	watcher.Publish(entities.Transition{ID: uuid.New(), Status: entities.Down})
+
+	// The buffered transition is delivered, then the channel is closed.
+	_, ok := <-events
+	suite.True(ok)
+
+	_, ok = <-events
+	suite.False(ok)
+	suite.Zero(watcher.Len())
+
+	// Unsubscribing a dropped subscriber is a no-op.
+	suite.NotPanics(unsubscribe)
+}
+
+// TestAnnouncer_Announce verifies that the notices reach the subscribed agents only.
+func (suite *WatcherTestSuite) TestAnnouncer_Announce() {
+	announcer := services.NewAnnouncer()
+
+	first, unsubscribe := announcer.Subscribe()
+	second, unsubscribeSecond := announcer.Subscribe()
+	defer unsubscribeSecond()
+
+	unsubscribe()
+
+	notice := entities.Notice{Kind: entities.NoticeShutdown, Delay: time.Second, Reason: "restart"}
+	suite.Equal(1, announcer.Announce(notice))
+
+	suite.Empty(first)
+	suite.Equal(notice, <-second)
+}
+
+// TestWatcherTestSuite runs the watcher test suite.
+func TestWatcherTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(WatcherTestSuite))
+}
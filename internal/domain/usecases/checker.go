@@ -2,6 +2,10 @@ package usecases
 
 import (
 	"context"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
@@ -42,79 +46,461 @@ type StateManager interface {
 	//   - An error if the status update cannot be sent to the state service,
 	//     or nil if the status update was sent successfully.
 	Send(ctx context.Context, id uuid.UUID, status entities.Status) error
+
+	// Beat records the heartbeat reported by the agent of the service.
+	//
+	// Parameters:
+	//   - ctx: The context.Context used to cancel the operation if needed.
+	//   - heartbeat: The entities.Heartbeat with the status reported by the agent.
+	//
+	// Returns:
+	//   - An error if the status update cannot be sent to the state service.
+	Beat(ctx context.Context, heartbeat entities.Heartbeat) error
+
+	// Delayed accounts the heartbeats of the service that were delayed by the network.
+	//
+	// Parameters:
+	//   - ctx: The context.Context used to cancel the operation if needed.
+	//   - id: The UUID of the service.
+	//   - times: The times the heartbeats were sent.
+	Delayed(ctx context.Context, id uuid.UUID, times ...time.Time)
+}
+
+// Broadcaster is an interface that defines the behavior for publishing the
+// heartbeats received by the instance, e.g. to the other instances of the
+// cluster or to an event stream.
+type Broadcaster interface {
+	// Broadcast publishes the heartbeat. It must not block the caller.
+	//
+	// Parameters:
+	//   - heartbeat: The entities.Heartbeat received by the instance.
+	Broadcast(heartbeat entities.Heartbeat)
 }
 
 // Checker represents a struct that handles the logic for sending status updates to the state service.
 //
 // The Checker struct has the following fields:
-// - Events: A channel of type uuid.UUID that is used to send UUIDs to the goroutine that sends status updates.
+// - Events: A channel of type entities.Heartbeat that is used to send heartbeats to the goroutine that sends status updates.
 // - state: A StateManager interface that is used to send status updates to the state service.
 type Checker struct {
-	// Events is a channel of type uuid.UUID that is used to send UUIDs to the goroutine that sends status updates.
-	// The channel has a buffer size of 64.
-	Events chan uuid.UUID
+	// Events is a channel of type entities.Heartbeat that is used to send heartbeats to the goroutine that sends
+	// status updates. The channel has a buffer size of 64 unless it is set with WithBufferSize.
+	Events chan entities.Heartbeat
 	// state is a StateManager interface that is used to send status updates to the state service.
 	state StateManager
+	// workers is the number of the goroutines that send status updates concurrently.
+	workers int
+	// window is the time the events are collected for before they are sent, so
+	// the duplicate UUIDs are sent once. If it is zero, the events are sent immediately.
+	window time.Duration
+	// overflow is the policy applied when the Events channel is full.
+	overflow entities.Overflow
+	// dropped is the number of the events dropped by the overflow policy.
+	dropped atomic.Uint64
+	// watermark is the highest number of the events buffered at once.
+	watermark atomic.Int64
+	// broadcasters publish the heartbeats, e.g. to the other instances of the cluster.
+	broadcasters []Broadcaster
+	// closing is held for reading while an event is buffered, and for writing
+	// while the Events channel is closed, so no event is sent on a closed channel.
+	closing sync.RWMutex
+	// closed reports whether the Events channel is closed. It is guarded by closing.
+	closed bool
+	// done is closed by Stop, so the buffering blocked on a full channel gives up.
+	done chan struct{}
+	// release closes done once.
+	release sync.Once
+}
+
+// CheckerOption is a function that can be used to configure a Checker instance.
+type CheckerOption func(*Checker)
+
+// WithBufferSize returns a CheckerOption that sets the buffer size of the Events channel.
+//
+// Parameters:
+//   - size: The number of the events buffered before Send blocks. The values
+//     below 1 are ignored.
+//
+// Returns:
+//   - A CheckerOption that sets the buffer size of the Checker.
+func WithBufferSize(size int) CheckerOption {
+	return func(c *Checker) {
+		if size > 0 {
+			c.Events = make(chan entities.Heartbeat, size)
+		}
+	}
+}
+
+// WithWorkers returns a CheckerOption that sets the number of the goroutines
+// that send status updates concurrently.
+//
+// The events of the same UUID are always handled by the same goroutine, so
+// they are sent in the order they were received.
+//
+// Parameters:
+//   - workers: The number of the goroutines. The values below 1 are ignored.
+//
+// Returns:
+//   - A CheckerOption that sets the concurrency of the Checker.
+func WithWorkers(workers int) CheckerOption {
+	return func(c *Checker) {
+		if workers > 0 {
+			c.workers = workers
+		}
+	}
+}
+
+// WithCoalescing returns a CheckerOption that coalesces the duplicate events.
+//
+// The events are collected for the window and the status update is sent once
+// per UUID and status with the latest heartbeat, in the order the UUIDs were
+// first received. A change of the status is never coalesced, so a service
+// that goes Up, Down and Up again within the window is sent three times. The
+// agents resend the same UUIDs frequently, so this saves a round-trip to the
+// state service for every duplicate at the cost of delaying the events by up
+// to the window.
+//
+// Parameters:
+//   - window: The time the events are collected for. If it is zero, the
+//     events are sent immediately.
+//
+// Returns:
+//   - A CheckerOption that sets the coalescing window of the Checker.
+func WithCoalescing(window time.Duration) CheckerOption {
+	return func(c *Checker) {
+		c.window = window
+	}
+}
+
+// WithOverflow returns a CheckerOption that sets the policy applied when the
+// Events channel is full.
+//
+// Parameters:
+//   - overflow: The overflow policy. By default, Send blocks until the
+//     channel has room.
+//
+// Returns:
+//   - A CheckerOption that sets the overflow policy of the Checker.
+func WithOverflow(overflow entities.Overflow) CheckerOption {
+	return func(c *Checker) {
+		c.overflow = overflow
+	}
+}
+
+// WithBroadcaster returns a CheckerOption that publishes the heartbeats, e.g.
+// to the other instances of the cluster. The option can be given more than once.
+//
+// Parameters:
+//   - broadcaster: The Broadcaster of the heartbeats passed to Beat.
+//
+// Returns:
+//   - A CheckerOption that adds the broadcaster to the Checker.
+func WithBroadcaster(broadcaster Broadcaster) CheckerOption {
+	return func(c *Checker) {
+		c.broadcasters = append(c.broadcasters, broadcaster)
+	}
 }
 
 // NewChecker creates a new instance of the Checker struct.
 //
 // It takes a StateManager interface as a parameter and returns a pointer to a Checker struct.
 // The Checker struct is used to handle the logic for sending status updates to the state service.
-// It initializes the Events channel with a buffer size of 64, which is used to send UUIDs to
+// It initializes the Events channel with a buffer size of 64, which is used to send heartbeats to
 // the goroutine that sends status updates.
 //
 // Parameters:
 //   - client: A StateManager interface used to send events to the state service.
+//   - options: Optional configurations for the Checker.
 //
 // Returns:
 //   - A pointer to a Checker struct.
-func NewChecker(client StateManager) *Checker {
+func NewChecker(client StateManager, options ...CheckerOption) *Checker {
 	const bufferSize = 64 // Buffer size for the Events channel.
 
 	// Create a new instance of the Checker struct.
 	// The Checker struct is used to handle the logic for sending status updates to the state service.
-	// It initializes the Events channel with a buffer size of 64, which is used to send UUIDs to
+	// It initializes the Events channel with a buffer size of 64, which is used to send heartbeats to
 	// the goroutine that sends status updates.
-	return &Checker{
-		// Events is a channel of type uuid.UUID that is used to send UUIDs to the goroutine that sends status updates.
-		// The channel has a buffer size of 64.
-		Events: make(chan uuid.UUID, bufferSize),
+	checker := &Checker{
+		// Events is a channel of type entities.Heartbeat that is used to send heartbeats to the goroutine
+		// that sends status updates. The channel has a buffer size of 64 by default.
+		Events: make(chan entities.Heartbeat, bufferSize),
 		// state is a StateManager interface that is used to send status updates to the state service.
 		state: client,
+		// The events are processed one by one by default.
+		workers: 1,
+		// The events are sent immediately by default.
+		window: 0,
+		// Send blocks while the Events channel is full by default.
+		overflow: entities.Block,
+		// No events are dropped yet.
+		dropped: atomic.Uint64{},
+		// No events are buffered yet.
+		watermark: atomic.Int64{},
+		// The heartbeats are not published by default.
+		broadcasters: nil,
+		// The Events channel is open until Close is called.
+		closing: sync.RWMutex{},
+		closed:  false,
+		done:    make(chan struct{}),
+		release: sync.Once{},
+	}
+
+	// Apply any optional configurations provided through the options parameter.
+	for _, option := range options {
+		option(checker)
 	}
+
+	return checker
 }
 
 // Send sends an event to the events channel of the Checker.
 //
-// This function sends an event to the events channel of the Checker,
+// The event is a heartbeat with the status Up, as reported by the agents
+// speaking the first version of the API. See Beat.
+//
+// Parameters:
+//   - id: The uuid.UUID of the service.
+//
+// Returns:
+//   - true if the event is buffered, false if it was dropped.
+func (c *Checker) Send(id uuid.UUID) bool {
+	return c.Beat(entities.Heartbeat{ID: id, Status: entities.Up, Message: "", TTL: 0, RequestID: "", Sent: time.Time{}})
+}
+
+// Beat sends a heartbeat to the events channel of the Checker.
+//
+// This function sends a heartbeat to the events channel of the Checker,
 // which is used to trigger the handler function to process the event.
+// If the channel is full, the overflow policy of the Checker decides whether
+// Beat blocks, drops the oldest buffered event or drops the event. The
+// buffered heartbeat is passed to the broadcasters.
 //
 // Parameters:
-//   - id: The uuid.UUID object representing the event to be sent.
-func (c *Checker) Send(id uuid.UUID) {
-	// Send the event to the events channel.
-	// The event is sent to the Events channel of the Checker.
-	// The Events channel is a channel of type uuid.UUID that is used to send events to the goroutine that processes the events.
-	//
-	// This function does not return anything.
-	//
-	// Send the event to the events channel.
-	c.Events <- id
+//   - heartbeat: The entities.Heartbeat to be sent.
+//
+// Returns:
+//   - true if the event is buffered, false if it was dropped.
+func (c *Checker) Beat(heartbeat entities.Heartbeat) bool {
+	if !c.buffer(heartbeat) {
+		return false
+	}
+
+	for _, broadcaster := range c.broadcasters {
+		broadcaster.Broadcast(heartbeat)
+	}
+
+	return true
+}
+
+// Remote sends a heartbeat received by another instance of the cluster to the
+// events channel of the Checker.
+//
+// Unlike Beat, the heartbeat is not passed to the broadcasters, as it has
+// already been published by the instance that received it.
+//
+// Parameters:
+//   - heartbeat: The entities.Heartbeat to be sent.
+//
+// Returns:
+//   - true if the event is buffered, false if it was dropped.
+func (c *Checker) Remote(heartbeat entities.Heartbeat) bool {
+	return c.buffer(heartbeat)
+}
+
+// buffer sends a heartbeat to the events channel according to the overflow policy.
+//
+// Parameters:
+//   - heartbeat: The entities.Heartbeat to be sent.
+//
+// Returns:
+//   - true if the event is buffered, false if it was dropped.
+func (c *Checker) buffer(heartbeat entities.Heartbeat) bool {
+	c.closing.RLock()
+	defer c.closing.RUnlock()
+
+	// Drop the event if the Checker is stopped, e.g. during a shutdown.
+	if c.closed || c.stopped() {
+		c.dropped.Add(1)
+
+		return false
+	}
+
+	// Record the depth of the buffer once the event is in.
+	defer c.mark()
+
+	switch c.overflow {
+	case entities.DropNewest:
+		// Drop the event if the channel is full.
+		select {
+		case c.Events <- heartbeat:
+			return true
+		default:
+			c.dropped.Add(1)
+
+			return false
+		}
+	case entities.DropOldest:
+		// Drop the oldest events until there is room for the event.
+		for {
+			select {
+			case c.Events <- heartbeat:
+				return true
+			case <-c.done:
+				c.dropped.Add(1)
+
+				return false
+			default:
+			}
+
+			select {
+			case <-c.Events:
+				c.dropped.Add(1)
+			default:
+			}
+		}
+	default:
+		// Block until the channel has room or the Checker is closed.
+		select {
+		case c.Events <- heartbeat:
+			return true
+		case <-c.done:
+			c.dropped.Add(1)
+
+			return false
+		}
+	}
+}
+
+// Dropped returns the number of the events dropped by the overflow policy
+// since the Checker was created.
+func (c *Checker) Dropped() uint64 {
+	return c.dropped.Load()
+}
+
+// Depth returns the number of the events buffered in the Events channel.
+func (c *Checker) Depth() int {
+	return len(c.Events)
+}
+
+// Capacity returns the size of the buffer of the Events channel.
+func (c *Checker) Capacity() int {
+	return cap(c.Events)
+}
+
+// HighWatermark returns the highest number of the events buffered in the
+// Events channel at once since the Checker was created.
+func (c *Checker) HighWatermark() int {
+	return int(c.watermark.Load())
+}
+
+// mark raises the high watermark to the current depth of the buffer.
+func (c *Checker) mark() {
+	depth := int64(len(c.Events))
+
+	for {
+		watermark := c.watermark.Load()
+		if depth <= watermark || c.watermark.CompareAndSwap(watermark, depth) {
+			return
+		}
+	}
+}
+
+// Delayed accounts the heartbeats that were delayed by the network.
+//
+// Unlike Send, the heartbeats are accounted synchronously, so they are taken
+// into account before the live heartbeats sent after them are processed.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - id: The uuid.UUID of the service.
+//   - times: The times the heartbeats were sent.
+func (c *Checker) Delayed(ctx context.Context, id uuid.UUID, times ...time.Time) {
+	c.state.Delayed(ctx, id, times...)
 }
 
 // Handler is a goroutine that processes events from the Events channel.
 //
-// This function continuously listens for events on the Events channel.
-// When an event is received, it sends a status update to the state service.
-// If the context is canceled, the function returns.
+// This function continuously listens for events on the Events channel and fans
+// them out to the workers, which send the status updates to the state service.
+// The worker is chosen by the UUID of the event, so the events of the same
+// service are sent in the order they were received, while a slow delivery does
+// not stall the other services. The dropped events are reported periodically.
+// If the coalescing is enabled, the events are
+// collected for the window and every UUID is handed over once. If the Events
+// channel is closed, the collected events are handed over, and the function
+// waits for the workers and returns. If the context is canceled, the collected
+// events are dropped.
 //
 // Parameters:
 // - ctx: The context.Context object that is used to cancel the goroutine.
 func (c *Checker) Handler(ctx context.Context) {
+	const (
+		// The number of the events queued per worker.
+		queueSize = 16
+		// The interval between the reports of the dropped events.
+		reportInterval = 10 * time.Second
+	)
+
 	// Get the logger from the context.
 	logger := zerolog.Ctx(ctx)
 
+	var wg sync.WaitGroup
+
+	// Start the workers.
+	queues := make([]chan entities.Heartbeat, c.workers)
+	for i := range queues {
+		queues[i] = make(chan entities.Heartbeat, queueSize)
+
+		wg.Add(1)
+
+		go func(queue <-chan entities.Heartbeat) {
+			defer wg.Done()
+
+			c.work(ctx, queue)
+		}(queues[i])
+	}
+
+	// Stop the workers once the events are no longer received.
+	defer func() {
+		for _, queue := range queues {
+			close(queue)
+		}
+
+		wg.Wait()
+	}()
+
+	// dispatch hands the events over to the workers of their UUIDs.
+	// It reports false if the context is canceled.
+	dispatch := func(heartbeats ...entities.Heartbeat) bool {
+		for _, heartbeat := range heartbeats {
+			select {
+			case queues[worker(heartbeat.ID, len(queues))] <- heartbeat:
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		return true
+	}
+
+	// Create a ticker for the coalesced events. It never ticks if the coalescing is disabled.
+	flush := make(<-chan time.Time)
+	batch := newBatch()
+
+	if c.window > 0 {
+		ticker := time.NewTicker(c.window)
+		defer ticker.Stop()
+
+		flush = ticker.C
+	}
+
+	// Create a ticker for the reports of the dropped events.
+	report := time.NewTicker(reportInterval)
+	defer report.Stop()
+
+	reported := c.Dropped()
+
 	// Continuously listen for events on the Events channel.
 	for {
 		// Receive an event from the Events channel.
@@ -122,17 +508,41 @@ func (c *Checker) Handler(ctx context.Context) {
 		// If the channel is closed, the receive operation will return a boolean value of false.
 		select {
 		// Receive an event from the Events channel.
-		case id, ok := <-c.Events:
-			// If the channel is closed, return from the function.
+		case heartbeat, ok := <-c.Events:
+			// If the channel is closed, hand over the collected events and return from the function.
 			if !ok {
+				dispatch(batch.drain()...)
+
+				return
+			}
+
+			// Collect the event until the next flush.
+			if c.window > 0 {
+				batch.add(heartbeat)
+
+				continue
+			}
+
+			// Hand the event over to the worker of the UUID.
+			if !dispatch(heartbeat) {
 				return
 			}
 
-			// Send a status update to the state service.
-			// If an error occurs, log the error.
-			if err := c.state.Send(ctx, id, entities.Up); err != nil {
-				// Log the error that occurred during sending the event.
-				logger.Err(err).Str("id", id.String()).Msg("checker: failed to send event")
+		// Hand the collected events over to the workers.
+		case <-flush:
+			if !dispatch(batch.drain()...) {
+				return
+			}
+
+		// Report the events dropped since the last report.
+		case <-report.C:
+			if dropped := c.Dropped(); dropped > reported {
+				logger.Warn().
+					Uint64("dropped", dropped-reported).
+					Str("overflow", c.overflow.String()).
+					Msg("checker: events dropped, the buffer is full")
+
+				reported = dropped
 			}
 
 		// If the context is canceled, return from the function.
@@ -142,8 +552,120 @@ func (c *Checker) Handler(ctx context.Context) {
 	}
 }
 
-// Close closes the Events channel of the Checker.
+// work sends the status updates for the events of the queue until it is closed.
+//
+// Parameters:
+//   - ctx: The context.Context holding the logger.
+//   - queue: The events handed over to the worker.
+func (c *Checker) work(ctx context.Context, queue <-chan entities.Heartbeat) {
+	// Get the logger from the context.
+	logger := zerolog.Ctx(ctx)
+
+	for heartbeat := range queue {
+		// Send a status update to the state service.
+		// If an error occurs, log the error.
+		if err := c.state.Beat(ctx, heartbeat); err != nil {
+			// Log the error that occurred during sending the event.
+			logger.Err(err).Str("id", heartbeat.ID.String()).Msg("checker: failed to send event")
+		}
+	}
+}
+
+// worker returns the index of the worker that handles the events of the UUID.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//   - workers: The number of the workers.
+//
+// Returns:
+//   - The index of the worker.
+func worker(id uuid.UUID, workers int) int {
+	hash := fnv.New32a()
+	_, _ = hash.Write(id[:])
+
+	return int(hash.Sum32() % uint32(workers)) //nolint:gosec
+}
+
+// Stop stops buffering the events, e.g. once the Handler no longer receives them.
+//
+// The events buffered after Stop, and the ones blocked on a full channel, are
+// dropped, so the sources of the heartbeats can return before the Checker is
+// closed. It is safe to call Stop more than once.
+func (c *Checker) Stop() {
+	c.release.Do(func() { close(c.done) })
+}
+
+// stopped reports whether Stop has been called.
+func (c *Checker) stopped() bool {
+	select {
+	case <-c.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Close stops the Checker and closes its Events channel.
+//
+// The events buffered after Close are dropped, so the heartbeats received
+// during a shutdown never panic. Close waits for the events being buffered.
+// It is safe to call Close more than once.
 func (c *Checker) Close() {
+	// Release the events blocked on a full channel before the lock is taken.
+	c.Stop()
+
+	c.closing.Lock()
+	defer c.closing.Unlock()
+
+	if c.closed {
+		return
+	}
+
+	c.closed = true
+
 	// Close the Events channel to indicate that no more events will be sent.
 	close(c.Events)
 }
+
+// batch is a set of the heartbeats, one per UUID and status in a row, that
+// keeps the order the heartbeats were added in.
+type batch struct {
+	heartbeats []entities.Heartbeat
+	// seen is the index of the latest heartbeat of the UUID.
+	seen map[uuid.UUID]int
+}
+
+// newBatch creates an empty batch.
+func newBatch() *batch {
+	return &batch{heartbeats: nil, seen: make(map[uuid.UUID]int)}
+}
+
+// add adds the heartbeat to the batch. If the latest heartbeat of the UUID in
+// the batch has the same status, it is replaced, so the latest message is
+// sent. Otherwise the heartbeat is appended, so the transitions are kept.
+//
+// Parameters:
+//   - heartbeat: The heartbeat of the service.
+func (b *batch) add(heartbeat entities.Heartbeat) {
+	if i, ok := b.seen[heartbeat.ID]; ok && b.heartbeats[i].Status == heartbeat.Status {
+		b.heartbeats[i] = heartbeat
+
+		return
+	}
+
+	b.seen[heartbeat.ID] = len(b.heartbeats)
+	b.heartbeats = append(b.heartbeats, heartbeat)
+}
+
+// drain empties the batch.
+//
+// Returns:
+//   - The heartbeats of the batch in the order they were added in.
+func (b *batch) drain() []entities.Heartbeat {
+	heartbeats := b.heartbeats
+
+	b.heartbeats = nil
+	clear(b.seen)
+
+	return heartbeats
+}
@@ -0,0 +1,386 @@
+package usecases_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/domain/usecases"
+)
+
+// recordingState is a usecases.StateManager recording the heartbeats it receives.
+type recordingState struct {
+	mu         sync.Mutex
+	heartbeats []entities.Heartbeat
+	delay      time.Duration
+	// active is the number of the heartbeats being recorded, and peak the highest of it.
+	active, peak atomic.Int32
+}
+
+// Send records an Up heartbeat of the service.
+func (s *recordingState) Send(ctx context.Context, id uuid.UUID, status entities.Status) error {
+	return s.Beat(ctx, entities.Heartbeat{ID: id, Status: status})
+}
+
+// Beat records the heartbeat.
+func (s *recordingState) Beat(_ context.Context, heartbeat entities.Heartbeat) error {
+	active := s.active.Add(1)
+	defer s.active.Add(-1)
+
+	for {
+		peak := s.peak.Load()
+		if active <= peak || s.peak.CompareAndSwap(peak, active) {
+			break
+		}
+	}
+
+	time.Sleep(s.delay)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.heartbeats = append(s.heartbeats, heartbeat)
+
+	return nil
+}
+
+// Delayed ignores the delayed heartbeats.
+func (s *recordingState) Delayed(context.Context, uuid.UUID, ...time.Time) {}
+
+// received returns the heartbeats recorded so far.
+func (s *recordingState) received() []entities.Heartbeat {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]entities.Heartbeat(nil), s.heartbeats...)
+}
+
+// CheckerTestSuite represents the test suite for the processing of the heartbeats.
+type CheckerTestSuite struct {
+	suite.Suite
+}
+
+// TestChecker_Shutdown verifies that the heartbeats sent while the Checker is
+// stopped and closed are dropped instead of panicking, whatever the overflow policy.
+func (suite *CheckerTestSuite) TestChecker_Shutdown() {
+	for _, overflow := range []entities.Overflow{entities.Block, entities.DropOldest, entities.DropNewest} {
+		state := &recordingState{delay: time.Millisecond}
+		checker := usecases.NewChecker(state, usecases.WithBufferSize(4), usecases.WithOverflow(overflow))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		handled := make(chan struct{})
+
+		go func() {
+			defer close(handled)
+
+			checker.Handler(ctx)
+		}()
+
+		// Beat from several sources while the Checker is shut down, as the
+		// servers do until they are stopped.
+		var sources sync.WaitGroup
+
+		for range 8 {
+			sources.Add(1)
+
+			go func() {
+				defer sources.Done()
+
+				for range 200 {
+					checker.Beat(entities.Heartbeat{ID: uuid.New(), Status: entities.Up})
+					checker.Remote(entities.Heartbeat{ID: uuid.New(), Status: entities.Up})
+				}
+			}()
+		}
+
+		time.Sleep(5 * time.Millisecond)
+
+		// Shut down in the order of the server: stop, wait for the sources, close.
+		cancel()
+		checker.Stop()
+
+		sources.Wait()
+		suite.Require().NotPanics(checker.Close)
+		suite.Require().NotPanics(checker.Close)
+
+		<-handled
+
+		// The heartbeats sent once the Checker is closed are dropped.
+		dropped := checker.Dropped()
+		suite.False(checker.Beat(entities.Heartbeat{ID: uuid.New(), Status: entities.Up}), overflow.String())
+		suite.Equal(dropped+1, checker.Dropped(), overflow.String())
+	}
+}
+
+// TestChecker_CloseWhileBeating verifies that closing the Checker while the
+// sources still beat never sends on the closed channel.
+func (suite *CheckerTestSuite) TestChecker_CloseWhileBeating() {
+	state := &recordingState{}
+	checker := usecases.NewChecker(state, usecases.WithBufferSize(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go checker.Handler(ctx)
+
+	var sources sync.WaitGroup
+
+	for range 8 {
+		sources.Add(1)
+
+		go func() {
+			defer sources.Done()
+
+			for range 500 {
+				checker.Beat(entities.Heartbeat{ID: uuid.New(), Status: entities.Up})
+			}
+		}()
+	}
+
+	time.Sleep(time.Millisecond)
+	suite.Require().NotPanics(checker.Close)
+
+	sources.Wait()
+}
+
+// handle starts the Handler of the Checker and returns a function closing the
+// Checker and waiting for the Handler to return.
+func (suite *CheckerTestSuite) handle(checker *usecases.Checker) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	suite.T().Cleanup(cancel)
+
+	handled := make(chan struct{})
+
+	go func() {
+		defer close(handled)
+
+		checker.Handler(ctx)
+	}()
+
+	return func() {
+		checker.Close()
+		<-handled
+	}
+}
+
+// beat returns a heartbeat of the service with the message.
+func beat(id uuid.UUID, message string) entities.Heartbeat {
+	return entities.Heartbeat{ID: id, Status: entities.Up, Message: message}
+}
+
+// messages returns the messages of the heartbeats of the service, in order.
+func messages(heartbeats []entities.Heartbeat, id uuid.UUID) []string {
+	var messages []string
+
+	for _, heartbeat := range heartbeats {
+		if heartbeat.ID == id {
+			messages = append(messages, heartbeat.Message)
+		}
+	}
+
+	return messages
+}
+
+// TestChecker_Workers verifies that the heartbeats of the different services
+// are sent concurrently, while the ones of a service are sent in order.
+func (suite *CheckerTestSuite) TestChecker_Workers() {
+	state := &recordingState{delay: 5 * time.Millisecond}
+	checker := usecases.NewChecker(state, usecases.WithWorkers(4))
+	closeChecker := suite.handle(checker)
+
+	ids := make([]uuid.UUID, 16)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	for i := range 4 {
+		for _, id := range ids {
+			suite.Require().True(checker.Beat(beat(id, strconv.Itoa(i))))
+		}
+	}
+
+	closeChecker()
+
+	received := state.received()
+	suite.Require().Len(received, 4*len(ids))
+
+	for _, id := range ids {
+		suite.Equal([]string{"0", "1", "2", "3"}, messages(received, id))
+	}
+
+	suite.Greater(state.peak.Load(), int32(1))
+	suite.LessOrEqual(state.peak.Load(), int32(4))
+}
+
+// TestChecker_SingleWorker verifies that the heartbeats are sent one by one by default.
+func (suite *CheckerTestSuite) TestChecker_SingleWorker() {
+	state := &recordingState{delay: time.Millisecond}
+	checker := usecases.NewChecker(state)
+	closeChecker := suite.handle(checker)
+
+	for range 16 {
+		suite.Require().True(checker.Send(uuid.New()))
+	}
+
+	closeChecker()
+
+	suite.Len(state.received(), 16)
+	suite.Equal(int32(1), state.peak.Load())
+}
+
+// TestChecker_Coalescing verifies that the heartbeats of a service received
+// within the window are sent once, with the latest heartbeat, in the order the
+// services were first received.
+func (suite *CheckerTestSuite) TestChecker_Coalescing() {
+	state := &recordingState{}
+	checker := usecases.NewChecker(state, usecases.WithCoalescing(50*time.Millisecond))
+	closeChecker := suite.handle(checker)
+
+	first, second := uuid.New(), uuid.New()
+
+	suite.Require().True(checker.Beat(beat(first, "a")))
+	suite.Require().True(checker.Beat(beat(second, "b")))
+	suite.Require().True(checker.Beat(beat(first, "c")))
+
+	suite.Eventually(func() bool { return len(state.received()) == 2 }, time.Second, 5*time.Millisecond)
+	suite.Equal([]entities.Heartbeat{beat(first, "c"), beat(second, "b")}, state.received())
+
+	// The next window sends the service again.
+	suite.Require().True(checker.Beat(beat(first, "d")))
+	suite.Eventually(func() bool { return len(state.received()) == 3 }, time.Second, 5*time.Millisecond)
+
+	time.Sleep(100 * time.Millisecond)
+	closeChecker()
+
+	suite.Equal([]string{"c", "d"}, messages(state.received(), first))
+}
+
+// TestChecker_CoalescingClose verifies that the heartbeats collected within
+// the window are sent once the Checker is closed.
+func (suite *CheckerTestSuite) TestChecker_CoalescingClose() {
+	state := &recordingState{}
+	checker := usecases.NewChecker(state, usecases.WithCoalescing(time.Hour))
+	closeChecker := suite.handle(checker)
+
+	id := uuid.New()
+
+	suite.Require().True(checker.Beat(beat(id, "a")))
+	suite.Require().True(checker.Beat(beat(id, "b")))
+
+	time.Sleep(20 * time.Millisecond)
+	suite.Empty(state.received())
+
+	closeChecker()
+
+	suite.Equal([]entities.Heartbeat{beat(id, "b")}, state.received())
+}
+
+// TestChecker_CoalescingTransitions verifies that the heartbeats of a service
+// with a different status are not coalesced within the window.
+func (suite *CheckerTestSuite) TestChecker_CoalescingTransitions() {
+	state := &recordingState{}
+	checker := usecases.NewChecker(state, usecases.WithCoalescing(time.Hour))
+	closeChecker := suite.handle(checker)
+
+	id := uuid.New()
+	down := entities.Heartbeat{ID: id, Status: entities.Down, Message: "c"}
+
+	suite.Require().True(checker.Beat(beat(id, "a")))
+	suite.Require().True(checker.Beat(beat(id, "b")))
+	suite.Require().True(checker.Beat(down))
+	suite.Require().True(checker.Beat(beat(id, "d")))
+
+	closeChecker()
+
+	suite.Equal([]entities.Heartbeat{beat(id, "b"), down, beat(id, "d")}, state.received())
+}
+
+// buffered returns the messages of the heartbeats buffered in the Events channel.
+func buffered(checker *usecases.Checker) []string {
+	var messages []string
+
+	for checker.Depth() > 0 {
+		messages = append(messages, (<-checker.Events).Message)
+	}
+
+	return messages
+}
+
+// TestChecker_DropNewest verifies that the heartbeats received while the
+// buffer is full are dropped.
+func (suite *CheckerTestSuite) TestChecker_DropNewest() {
+	checker := usecases.NewChecker(&recordingState{}, usecases.WithBufferSize(2), usecases.WithOverflow(entities.DropNewest))
+	id := uuid.New()
+
+	suite.True(checker.Beat(beat(id, "a")))
+	suite.True(checker.Beat(beat(id, "b")))
+	suite.False(checker.Beat(beat(id, "c")))
+	suite.False(checker.Remote(beat(id, "d")))
+
+	suite.Equal(uint64(2), checker.Dropped())
+	suite.Equal(2, checker.HighWatermark())
+	suite.Equal([]string{"a", "b"}, buffered(checker))
+}
+
+// TestChecker_DropOldest verifies that the oldest buffered heartbeats are
+// dropped to make room for the new ones.
+func (suite *CheckerTestSuite) TestChecker_DropOldest() {
+	checker := usecases.NewChecker(&recordingState{}, usecases.WithBufferSize(2), usecases.WithOverflow(entities.DropOldest))
+	id := uuid.New()
+
+	suite.True(checker.Beat(beat(id, "a")))
+	suite.True(checker.Beat(beat(id, "b")))
+	suite.True(checker.Beat(beat(id, "c")))
+	suite.True(checker.Remote(beat(id, "d")))
+
+	suite.Equal(uint64(2), checker.Dropped())
+	suite.Equal(2, checker.HighWatermark())
+	suite.Equal([]string{"c", "d"}, buffered(checker))
+}
+
+// TestChecker_Block verifies that the heartbeats received while the buffer is
+// full wait for room, and are dropped only once the Checker is stopped.
+func (suite *CheckerTestSuite) TestChecker_Block() {
+	checker := usecases.NewChecker(&recordingState{}, usecases.WithBufferSize(1))
+	id := uuid.New()
+
+	suite.Require().True(checker.Beat(beat(id, "a")))
+
+	results := make(chan bool, 2)
+
+	go func() { results <- checker.Beat(beat(id, "b")) }()
+
+	// The heartbeat waits while the buffer is full.
+	select {
+	case <-results:
+		suite.FailNow("the heartbeat is not blocked")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	suite.Equal("a", (<-checker.Events).Message)
+	suite.True(<-results)
+	suite.Zero(checker.Dropped())
+
+	// The heartbeat blocked on the full buffer is dropped once the Checker is stopped.
+	go func() { results <- checker.Beat(beat(id, "c")) }()
+
+	time.Sleep(20 * time.Millisecond)
+	checker.Stop()
+
+	suite.False(<-results)
+	suite.Equal(uint64(1), checker.Dropped())
+	suite.Equal([]string{"b"}, buffered(checker))
+}
+
+// TestCheckerTestSuite runs the test suite for the processing of the heartbeats.
+func TestCheckerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CheckerTestSuite))
+}
@@ -0,0 +1,209 @@
+package alertmanager
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/httpx"
+)
+
+// ErrUnexpectedStatus is an error that indicates that Alertmanager responded with a non-2xx status code.
+var ErrUnexpectedStatus = errors.New("alertmanager: unexpected response status")
+
+// AlertsPath is the path of the alerts endpoint of the Alertmanager API.
+const AlertsPath = "/api/v2/alerts"
+
+// firingWindow is how long a firing alert stays active without being resolved.
+//
+// The alerts are sent on the transitions only, not repeatedly like Prometheus
+// does, so they are given an end far in the future instead of the resolve
+// timeout of Alertmanager.
+const firingWindow = 365 * 24 * time.Hour
+
+// Alert names of the statuses of the services.
+const (
+	AlertDown     = "VakeelWayServiceDown"
+	AlertDegraded = "VakeelWayServiceDegraded"
+)
+
+// ResponseError is an error returned when Alertmanager responds with a non-2xx
+// status code. It wraps ErrUnexpectedStatus and carries the response body.
+type ResponseError = httpx.ResponseError
+
+// API is a client for the Alertmanager API.
+//
+// The alerts endpoint accepts a POST request with a JSON list of alerts. An
+// alert is identified by its labels: it fires until its end and is resolved
+// by posting it again with the end in the past.
+type API struct {
+	// client: The HTTP client used to make requests to Alertmanager.
+	client *http.Client
+}
+
+// Option is a function that can be used to configure an API instance.
+type Option func(*API)
+
+// WithClient returns an Option function that sets the http.Client used to send HTTP requests
+// to Alertmanager.
+func WithClient(c http.Client) Option {
+	return func(api *API) {
+		api.client = &c
+	}
+}
+
+// NewAPI creates a new Alertmanager API client.
+//
+// Parameters:
+//
+//	ops: A variadic number of Option functions.
+//
+// Returns:
+//
+//	A pointer to an API struct.
+//
+//nolint:exhaustruct
+func NewAPI(ops ...Option) *API {
+	// Create a new API struct with default settings for the http.Client.
+	api := &API{
+		client: &http.Client{},
+	}
+
+	// Apply all provided options to the API struct.
+	for _, op := range ops {
+		op(api)
+	}
+
+	return api
+}
+
+// alert is an alert of the Alertmanager API.
+type alert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+}
+
+// Send posts the alerts of the service of the event to Alertmanager.
+//
+// Every service has two alerts: AlertDown and AlertDegraded. The alert of the
+// status of the service fires and the other one is resolved, so a service
+// that goes from degraded to down does not keep both alerts firing. Both
+// alerts are resolved when the service is up. The alerts are labeled with
+// the UUID of the service and the severity, and annotated with the rendered
+// message as the summary.
+//
+// Parameters:
+// - ctx: The context.Context to use for the request.
+// - target: The entities.Target with the URL of Alertmanager. The alerts
+// path is appended unless the URL already ends with it.
+// - event: The entities.Event to post the alerts of.
+//
+// Returns an error if the request cannot be sent, or a *ResponseError if
+// Alertmanager rejects the alerts.
+func (s *API) Send(ctx context.Context, target entities.Target, event entities.Event) error {
+	data, err := json.Marshal(alerts(event))
+	if err != nil {
+		return err
+	}
+
+	url := strings.TrimRight(target.URL, "/")
+	if !strings.HasSuffix(url, AlertsPath) {
+		url += AlertsPath
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Alertmanager responds with 200 OK when the alerts are accepted.
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return httpx.NewResponseError(ErrUnexpectedStatus, resp)
+	}
+
+	return nil
+}
+
+// alerts returns the alerts of the service of the event.
+func alerts(event entities.Event) []alert {
+	now := event.Time.UTC()
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+
+	// The downtime started when the service was last seen, if it is known.
+	startsAt := now
+
+	switch {
+	case event.Status == entities.Up && !event.Since.IsZero():
+		startsAt = event.Since.UTC()
+	case event.Status != entities.Up && !event.LastSeen.IsZero():
+		startsAt = event.LastSeen.UTC()
+	}
+
+	// Use the rendered message, if any.
+	summary := event.Message
+	if summary == "" {
+		summary = fmt.Sprintf("Service %s is %s", event.ID, event.Status)
+	}
+
+	annotations := map[string]string{"summary": summary, "status": event.Status.String()}
+	if event.Reason != "" {
+		annotations["description"] = event.Reason
+	}
+
+	if event.Agent.Hostname != "" {
+		annotations["hostname"] = event.Agent.Hostname
+	}
+
+	result := make([]alert, 0, 2) //nolint:mnd
+	for _, name := range []string{AlertDown, AlertDegraded} {
+		firing := (name == AlertDown && event.Status == entities.Down) ||
+			(name == AlertDegraded && event.Status == entities.Degraded)
+
+		endsAt := now
+		if firing {
+			endsAt = now.Add(firingWindow)
+		}
+
+		result = append(result, alert{
+			Labels:      labels(name, event),
+			Annotations: annotations,
+			StartsAt:    startsAt,
+			EndsAt:      endsAt,
+		})
+	}
+
+	return result
+}
+
+// labels returns the labels identifying the alert of the service.
+func labels(name string, event entities.Event) map[string]string {
+	severity := "critical"
+	if name == AlertDegraded {
+		severity = "warning"
+	}
+
+	return map[string]string{
+		"alertname": name,
+		"service":   event.ID.String(),
+		"severity":  severity,
+		"source":    "vakeel-way",
+	}
+}
@@ -0,0 +1,115 @@
+package alertmanager_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/alertmanager"
+)
+
+// alert is an alert received by Alertmanager.
+type alert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+}
+
+// ClientTestSuite represents the test suite for the Alertmanager client.
+type ClientTestSuite struct {
+	suite.Suite
+}
+
+// send delivers the event to a fake Alertmanager and returns the received alerts by name.
+func (suite *ClientTestSuite) send(event entities.Event) map[string]alert {
+	var received []alert
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		suite.Equal(http.MethodPost, r.Method)
+		suite.Equal(alertmanager.AlertsPath, r.URL.Path)
+		suite.NoError(json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+
+	suite.Require().NoError(alertmanager.NewAPI().Send(context.Background(),
+		entities.Target{Name: "alertmanager", Type: "alertmanager", URL: server.URL + "/"}, event))
+
+	alerts := make(map[string]alert, len(received))
+	for _, a := range received {
+		alerts[a.Labels["alertname"]] = a
+	}
+
+	return alerts
+}
+
+// TestClient_Firing verifies that the alert of the status fires and the other one is resolved.
+func (suite *ClientTestSuite) TestClient_Firing() {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	id := uuid.New()
+
+	alerts := suite.send(entities.Event{
+		ID:       id,
+		Status:   entities.Down,
+		Time:     now,
+		LastSeen: now.Add(-time.Minute),
+		Message:  "api is down",
+	})
+	suite.Require().Len(alerts, 2)
+
+	down := alerts[alertmanager.AlertDown]
+	suite.Equal(id.String(), down.Labels["service"])
+	suite.Equal("critical", down.Labels["severity"])
+	suite.Equal("api is down", down.Annotations["summary"])
+	suite.True(down.StartsAt.Equal(now.Add(-time.Minute)))
+	suite.True(down.EndsAt.After(now))
+
+	degraded := alerts[alertmanager.AlertDegraded]
+	suite.Equal("warning", degraded.Labels["severity"])
+	suite.True(degraded.EndsAt.Equal(now))
+}
+
+// TestClient_Resolved verifies that both alerts are resolved when the service is up.
+func (suite *ClientTestSuite) TestClient_Resolved() {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	alerts := suite.send(entities.Event{ID: uuid.New(), Status: entities.Up, Time: now, Since: now.Add(-time.Hour)})
+	suite.Require().Len(alerts, 2)
+
+	for _, a := range alerts {
+		suite.True(a.EndsAt.Equal(now))
+		suite.True(a.StartsAt.Equal(now.Add(-time.Hour)))
+	}
+}
+
+// TestClient_Rejected verifies that the rejected alerts are reported.
+func (suite *ClientTestSuite) TestClient_Rejected() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("start time must be before end time\n"))
+	}))
+	defer server.Close()
+
+	err := alertmanager.NewAPI().Send(context.Background(),
+		entities.Target{Name: "alertmanager", Type: "alertmanager", URL: server.URL + alertmanager.AlertsPath},
+		entities.Event{ID: uuid.New(), Status: entities.Down})
+	suite.Require().ErrorIs(err, alertmanager.ErrUnexpectedStatus)
+
+	var rerr *alertmanager.ResponseError
+	suite.Require().ErrorAs(err, &rerr)
+	suite.Equal("start time must be before end time", rerr.Message)
+	suite.False(rerr.Temporary())
+}
+
+// TestClientTestSuite runs the test suite for the Alertmanager client.
+func TestClientTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ClientTestSuite))
+}
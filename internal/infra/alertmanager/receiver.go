@@ -0,0 +1,165 @@
+package alertmanager
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// Rule maps the alerts to a service.
+type Rule struct {
+	// Alertname is the name of the matched alerts, or empty for any name.
+	Alertname string
+
+	// Labels are the labels the matched alerts must have.
+	Labels map[string]string
+
+	// ID is the UUID of the service reported by the matched alerts.
+	ID uuid.UUID
+
+	// Status is the status reported by the firing alerts, Down or Degraded.
+	Status entities.Status
+}
+
+// Receiver maps the alerts of the Alertmanager webhooks to the statuses of the services.
+//
+// An alert is mapped to the service whose UUID is the value of its label, or
+// to the service of the first matching rule. A firing alert reports the
+// service down, or degraded if the rule says so, and a resolved alert reports
+// it up. If several alerts of a webhook are mapped to the same service, a
+// firing alert wins over a resolved one, and down wins over degraded.
+type Receiver struct {
+	// label is the label of the alerts carrying the UUID of the service.
+	label string
+
+	// rules map the alerts without the label to the services.
+	rules []Rule
+
+	// ttl is the TTL of the status reported by a resolved alert.
+	ttl time.Duration
+}
+
+// NewReceiver creates a new instance of the Receiver struct.
+//
+// Parameters:
+//   - label: The label of the alerts carrying the UUID of the service, or empty.
+//   - ttl: The TTL of the status reported by a resolved alert, or zero for the
+//     TTL configured on the server.
+//   - rules: The rules mapping the alerts without the label to the services.
+//
+// Returns:
+//   - A pointer to the initialized Receiver.
+func NewReceiver(label string, ttl time.Duration, rules []Rule) *Receiver {
+	return &Receiver{label: label, rules: rules, ttl: ttl}
+}
+
+// webhook is the payload of an Alertmanager webhook (version 4).
+type webhook struct {
+	Alerts []webhookAlert `json:"alerts"`
+}
+
+// webhookAlert is an alert of an Alertmanager webhook.
+type webhookAlert struct {
+	Status      string            `json:"status"`
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+}
+
+// Heartbeats decodes the webhook and returns the heartbeats of the services of its alerts.
+//
+// The alerts that are not mapped to any service are skipped. The summary or
+// the description of a firing alert is the message of the heartbeat.
+//
+// Parameters:
+//   - body: The body of the webhook.
+//
+// Returns:
+//   - The heartbeats of the services, in the order of their first alert.
+//   - The number of the alerts not mapped to any service.
+//   - An error if the body is not an Alertmanager webhook.
+func (r *Receiver) Heartbeats(body io.Reader) ([]entities.Heartbeat, int, error) {
+	var payload webhook
+	if err := json.NewDecoder(body).Decode(&payload); err != nil {
+		return nil, 0, err
+	}
+
+	var (
+		heartbeats []entities.Heartbeat
+		unmapped   int
+	)
+
+	for _, alert := range payload.Alerts {
+		id, status, ok := r.match(alert.Labels)
+		if !ok {
+			unmapped++
+
+			continue
+		}
+
+		heartbeat := entities.Heartbeat{ID: id, Status: entities.Up, Message: "", TTL: r.ttl, RequestID: "", Sent: time.Time{}}
+		if alert.Status == "firing" {
+			heartbeat = entities.Heartbeat{ID: id, Status: status, Message: message(alert), TTL: 0, RequestID: "", Sent: time.Time{}}
+		}
+
+		idx := slices.IndexFunc(heartbeats, func(h entities.Heartbeat) bool { return h.ID == id })
+
+		switch {
+		case idx < 0:
+			heartbeats = append(heartbeats, heartbeat)
+		case severity(heartbeat.Status) > severity(heartbeats[idx].Status):
+			heartbeats[idx] = heartbeat
+		}
+	}
+
+	return heartbeats, unmapped, nil
+}
+
+// match returns the service of the alert and the status reported while it fires.
+func (r *Receiver) match(labels map[string]string) (uuid.UUID, entities.Status, bool) {
+	if value, ok := labels[r.label]; ok && r.label != "" {
+		if id, err := uuid.Parse(value); err == nil {
+			return id, entities.Down, true
+		}
+	}
+
+	for _, rule := range r.rules {
+		if rule.Alertname != "" && rule.Alertname != labels["alertname"] {
+			continue
+		}
+
+		if entities.MatchLabels(rule.Labels, labels) {
+			return rule.ID, rule.Status, true
+		}
+	}
+
+	return uuid.Nil, entities.Down, false
+}
+
+// message returns the summary or the description of the alert, or its name.
+func message(alert webhookAlert) string {
+	for _, key := range []string{"summary", "description"} {
+		if text := strings.TrimSpace(alert.Annotations[key]); text != "" {
+			return text
+		}
+	}
+
+	return alert.Labels["alertname"]
+}
+
+// severity orders the statuses reported for a service: down wins over degraded, and degraded over up.
+func severity(status entities.Status) int {
+	switch status {
+	case entities.Down:
+		return 2 //nolint:mnd
+	case entities.Degraded:
+		return 1
+	default:
+		return 0
+	}
+}
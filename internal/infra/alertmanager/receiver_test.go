@@ -0,0 +1,77 @@
+package alertmanager_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/alertmanager"
+)
+
+// ReceiverTestSuite represents the test suite for the receiver of the Alertmanager webhooks.
+type ReceiverTestSuite struct {
+	suite.Suite
+
+	api      uuid.UUID
+	db       uuid.UUID
+	receiver *alertmanager.Receiver
+}
+
+func (suite *ReceiverTestSuite) SetupTest() {
+	suite.api = uuid.MustParse("224f8a59-6705-4f3e-b7de-177757932aad")
+	suite.db = uuid.MustParse("4b1e3c2a-7f4d-4c8e-9a51-0d3f5b6e7c81")
+	suite.receiver = alertmanager.NewReceiver("vakeel_way_id", time.Hour, []alertmanager.Rule{
+		{Alertname: "SlowQueries", Labels: map[string]string{"job": "db"}, ID: suite.db, Status: entities.Degraded},
+		{Alertname: "", Labels: map[string]string{"job": "db"}, ID: suite.db, Status: entities.Down},
+	})
+}
+
+// TestReceiver_Label verifies that the alerts with the label are mapped to the service directly.
+func (suite *ReceiverTestSuite) TestReceiver_Label() {
+	heartbeats, unmapped, err := suite.receiver.Heartbeats(strings.NewReader(`{"alerts": [
+		{"status": "firing", "labels": {"alertname": "HighErrorRate", "vakeel_way_id": "` + suite.api.String() + `"},
+		 "annotations": {"summary": "5% of the requests fail"}},
+		{"status": "firing", "labels": {"alertname": "Unknown", "job": "web"}}
+	]}`))
+	suite.Require().NoError(err)
+	suite.Equal(1, unmapped)
+	suite.Equal([]entities.Heartbeat{
+		{ID: suite.api, Status: entities.Down, Message: "5% of the requests fail"},
+	}, heartbeats)
+}
+
+// TestReceiver_Rules verifies that the first matching rule wins and the worst status is reported.
+func (suite *ReceiverTestSuite) TestReceiver_Rules() {
+	heartbeats, _, err := suite.receiver.Heartbeats(strings.NewReader(`{"alerts": [
+		{"status": "resolved", "labels": {"alertname": "DiskFull", "job": "db"}},
+		{"status": "firing", "labels": {"alertname": "SlowQueries", "job": "db"}}
+	]}`))
+	suite.Require().NoError(err)
+	suite.Equal([]entities.Heartbeat{
+		{ID: suite.db, Status: entities.Degraded, Message: "SlowQueries"},
+	}, heartbeats)
+
+	heartbeats, _, err = suite.receiver.Heartbeats(strings.NewReader(`{"alerts": [
+		{"status": "resolved", "labels": {"alertname": "DiskFull", "job": "db"}}
+	]}`))
+	suite.Require().NoError(err)
+	suite.Equal([]entities.Heartbeat{
+		{ID: suite.db, Status: entities.Up, TTL: time.Hour},
+	}, heartbeats)
+}
+
+// TestReceiver_Invalid verifies that a body that is not a webhook is rejected.
+func (suite *ReceiverTestSuite) TestReceiver_Invalid() {
+	_, _, err := suite.receiver.Heartbeats(strings.NewReader(`not json`))
+	suite.Require().Error(err)
+}
+
+// TestReceiverTestSuite runs the test suite for the receiver of the Alertmanager webhooks.
+func TestReceiverTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ReceiverTestSuite))
+}
@@ -0,0 +1,62 @@
+package atomicfile
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// Write replaces the content of the file at once.
+//
+// The data is written to a temporary file in the same directory, synced, and
+// renamed over the file, then the directory is synced, so a crash leaves
+// either the previous or the new content, never a truncated file, and the
+// rename survives a power loss.
+//
+// Parameters:
+//   - path: The path of the file.
+//   - data: The new content of the file.
+//
+// Returns:
+//   - An error if the file cannot be written.
+func Write(path string, data []byte) error {
+	dir := filepath.Dir(path)
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	return syncDir(dir)
+}
+
+// syncDir flushes the entries of the directory, so a rename within it is durable.
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return f.Sync()
+}
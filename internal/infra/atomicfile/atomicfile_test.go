@@ -0,0 +1,44 @@
+package atomicfile_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/atomicfile"
+)
+
+// AtomicFileTestSuite represents the test suite for the atomic writes of the files.
+type AtomicFileTestSuite struct {
+	suite.Suite
+}
+
+// TestWrite verifies that the file is created, then replaced, without leaving the temporary files.
+func (suite *AtomicFileTestSuite) TestWrite() {
+	dir := suite.T().TempDir()
+	path := filepath.Join(dir, "state.json")
+
+	suite.Require().NoError(atomicfile.Write(path, []byte("first")))
+	suite.Require().NoError(atomicfile.Write(path, []byte("second")))
+
+	data, err := os.ReadFile(path)
+	suite.Require().NoError(err)
+	suite.Equal("second", string(data))
+
+	entries, err := os.ReadDir(dir)
+	suite.Require().NoError(err)
+	suite.Len(entries, 1)
+}
+
+// TestWrite_MissingDir verifies that the file of a missing directory is not written.
+func (suite *AtomicFileTestSuite) TestWrite_MissingDir() {
+	suite.Require().Error(atomicfile.Write(filepath.Join(suite.T().TempDir(), "missing", "state.json"), []byte("data")))
+}
+
+// TestAtomicFileTestSuite runs the test suite for the atomic writes of the files.
+func TestAtomicFileTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(AtomicFileTestSuite))
+}
@@ -0,0 +1,192 @@
+package audit
+
+import (
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// DefaultSize is the default number of the heartbeats kept in memory.
+const DefaultSize = 1000
+
+// Sink represents an interface for storing the heartbeats outside the memory
+// of the server, e.g. in a file or an external system.
+type Sink interface {
+	// Write stores the heartbeat.
+	//
+	// Parameters:
+	//   - record: The received heartbeat.
+	//
+	// Returns:
+	//   - An error if the heartbeat cannot be stored.
+	Write(record entities.HeartbeatRecord) error
+}
+
+// Log is the audit log of the received heartbeats.
+//
+// The latest heartbeats are kept in a ring buffer, so "did the agent actually
+// report?" can be answered with the administrative API, and every heartbeat is
+// optionally forwarded to the sinks for a longer retention.
+type Log struct {
+	// records is the ring buffer of the latest heartbeats. It is empty if the
+	// audit log is disabled.
+	records []entities.HeartbeatRecord
+
+	// next is the position of the next heartbeat in the ring buffer.
+	next int
+
+	// full reports whether the ring buffer has wrapped around.
+	full bool
+
+	// sinks are the sinks every heartbeat is forwarded to.
+	sinks []Sink
+
+	// mu is the mutex used to synchronize access to the ring buffer.
+	mu sync.Mutex
+}
+
+// Option is a function that can be used to configure a Log instance.
+type Option func(*Log)
+
+// WithSize returns an Option that sets the number of the heartbeats kept in memory.
+//
+// Parameters:
+//   - size: The size of the ring buffer. If it is zero, the heartbeats are
+//     not kept in memory.
+//
+// Returns:
+//   - An Option that sets the size of the ring buffer.
+func WithSize(size int) Option {
+	return func(l *Log) {
+		l.records = make([]entities.HeartbeatRecord, max(size, 0))
+	}
+}
+
+// WithSink returns an Option that adds a sink the heartbeats are forwarded to.
+//
+// Parameters:
+//   - sink: The Sink of the heartbeats.
+//
+// Returns:
+//   - An Option that adds the sink to the Log.
+func WithSink(sink Sink) Option {
+	return func(l *Log) {
+		l.sinks = append(l.sinks, sink)
+	}
+}
+
+// NewLog creates a new audit log.
+//
+// Parameters:
+//   - options: Optional configurations for the Log.
+//
+// Returns:
+//   - A pointer to the initialized Log.
+//
+//nolint:exhaustruct
+func NewLog(options ...Option) *Log {
+	log := &Log{
+		records: make([]entities.HeartbeatRecord, DefaultSize),
+	}
+
+	// Apply any optional configurations provided through the options parameter.
+	for _, option := range options {
+		option(log)
+	}
+
+	return log
+}
+
+// Enabled reports whether the heartbeats are kept in memory or forwarded to a sink.
+func (l *Log) Enabled() bool {
+	return len(l.records) > 0 || len(l.sinks) > 0
+}
+
+// Record keeps the heartbeat and forwards it to the sinks.
+//
+// Parameters:
+//   - record: The received heartbeat.
+//
+// Returns:
+//   - The first error returned by a sink. The heartbeat is kept in memory
+//     and forwarded to the other sinks regardless.
+func (l *Log) Record(record entities.HeartbeatRecord) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.records) > 0 {
+		l.records[l.next] = record
+		l.next = (l.next + 1) % len(l.records)
+		l.full = l.full || l.next == 0
+	}
+
+	var err error
+
+	for _, sink := range l.sinks {
+		if sinkErr := sink.Write(record); sinkErr != nil && err == nil {
+			err = sinkErr
+		}
+	}
+
+	return err
+}
+
+// Close closes the sinks that hold resources, e.g. the open files.
+//
+// Returns:
+//   - The errors returned by the sinks, joined.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var errs []error
+
+	for _, sink := range l.sinks {
+		if closer, ok := sink.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// List returns the latest heartbeats kept in memory, oldest first.
+//
+// Parameters:
+//   - id: The UUID of the service, or uuid.Nil to list the heartbeats of every service.
+//   - limit: The maximum number of the heartbeats. If it is zero, every
+//     heartbeat kept in memory is returned.
+//
+// Returns:
+//   - The list of the heartbeats.
+func (l *Log) List(id uuid.UUID, limit int) []entities.HeartbeatRecord {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var ordered []entities.HeartbeatRecord
+	if l.full {
+		ordered = append(ordered, l.records[l.next:]...)
+	}
+
+	ordered = append(ordered, l.records[:l.next]...)
+
+	// Filter the heartbeats of the service in place.
+	records := ordered[:0]
+
+	for _, record := range ordered {
+		if id == uuid.Nil || record.ID == id {
+			records = append(records, record)
+		}
+	}
+
+	// Keep the latest heartbeats.
+	if limit > 0 && len(records) > limit {
+		records = records[len(records)-limit:]
+	}
+
+	return records
+}
@@ -0,0 +1,81 @@
+package audit_test
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/audit"
+)
+
+// AuditTestSuite represents the test suite for the audit log.
+type AuditTestSuite struct {
+	suite.Suite
+}
+
+// TestLog_List verifies that the latest heartbeats are kept and filtered, oldest first.
+func (suite *AuditTestSuite) TestLog_List() {
+	log := audit.NewLog(audit.WithSize(3))
+	first, second := uuid.New(), uuid.New()
+
+	for _, id := range []uuid.UUID{first, second, first, second} {
+		suite.Require().NoError(log.Record(entities.HeartbeatRecord{ID: id, Peer: id.String()}))
+	}
+
+	// The oldest heartbeat is overwritten.
+	all := log.List(uuid.Nil, 0)
+	suite.Require().Len(all, 3)
+	suite.Equal(second, all[0].ID)
+	suite.Equal(second, all[2].ID)
+
+	suite.Len(log.List(first, 0), 1)
+	suite.Len(log.List(second, 1), 1)
+}
+
+// TestLog_Disabled verifies that nothing is kept if the size is zero.
+func (suite *AuditTestSuite) TestLog_Disabled() {
+	log := audit.NewLog(audit.WithSize(0))
+
+	suite.False(log.Enabled())
+	suite.Require().NoError(log.Record(entities.HeartbeatRecord{ID: uuid.New()}))
+	suite.Empty(log.List(uuid.Nil, 0))
+}
+
+// TestLog_FileSink verifies that every heartbeat is appended to the file.
+func (suite *AuditTestSuite) TestLog_FileSink() {
+	path := filepath.Join(suite.T().TempDir(), "heartbeats.jsonl")
+
+	sink, err := audit.OpenFileSink(path)
+	suite.Require().NoError(err)
+
+	log := audit.NewLog(audit.WithSize(1), audit.WithSink(sink))
+
+	for range 3 {
+		suite.Require().NoError(log.Record(entities.HeartbeatRecord{ID: uuid.New(), Result: "accepted"}))
+	}
+
+	suite.Require().NoError(log.Close())
+
+	file, err := os.Open(path)
+	suite.Require().NoError(err)
+
+	defer file.Close()
+
+	lines := 0
+	for scanner := bufio.NewScanner(file); scanner.Scan(); {
+		lines++
+	}
+
+	suite.Equal(3, lines)
+}
+
+// TestAuditTestSuite runs the audit log test suite.
+func TestAuditTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(AuditTestSuite))
+}
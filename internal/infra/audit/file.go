@@ -0,0 +1,87 @@
+package audit
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// FileSink is a Sink that appends the heartbeats to a file as JSON lines.
+type FileSink struct {
+	// file is the file the heartbeats are appended to.
+	file *os.File
+
+	// encoder encodes the heartbeats into the file.
+	encoder *json.Encoder
+
+	// mu is the mutex used to synchronize access to the file.
+	mu sync.Mutex
+}
+
+// line is a heartbeat in the file.
+type line struct {
+	At     time.Time `json:"at"`
+	ID     uuid.UUID `json:"id"`
+	Peer   string    `json:"peer"`
+	Token  string    `json:"token,omitempty"`
+	Agent  string    `json:"agent,omitempty"`
+	Result string    `json:"result"`
+}
+
+// OpenFileSink opens the file the heartbeats are appended to.
+//
+// The file is created if it does not exist.
+//
+// Parameters:
+//   - path: The path to the file.
+//
+// Returns:
+//   - A pointer to the opened FileSink.
+//   - An error if the file cannot be opened.
+func OpenFileSink(path string) (*FileSink, error) {
+	const perm = 0o600
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, perm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileSink{file: file, encoder: json.NewEncoder(file), mu: sync.Mutex{}}, nil
+}
+
+// Write appends the heartbeat to the file.
+//
+// Parameters:
+//   - record: The received heartbeat.
+//
+// Returns:
+//   - An error if the heartbeat cannot be written.
+func (s *FileSink) Write(record entities.HeartbeatRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.encoder.Encode(line{
+		At:     record.At,
+		ID:     record.ID,
+		Peer:   record.Peer,
+		Token:  record.Token,
+		Agent:  record.Agent,
+		Result: record.Result,
+	})
+}
+
+// Close closes the file.
+//
+// Returns:
+//   - An error if the file cannot be closed.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// ErrForbidden is an error that indicates that the token may not report the service.
+var ErrForbidden = errors.New("auth: the token may not report the service")
+
+// ACL restricts the services a token may report heartbeats for.
+//
+// A service is allowed if its UUID is listed or it belongs to a listed
+// namespace. The zero ACL allows every service.
+type ACL struct {
+	// IDs are the UUIDs of the services the token may report.
+	IDs []uuid.UUID
+
+	// Namespaces are the namespaces whose services the token may report.
+	Namespaces []string
+}
+
+// Restricted reports whether the ACL restricts the services at all.
+func (a ACL) Restricted() bool {
+	return len(a.IDs) > 0 || len(a.Namespaces) > 0
+}
+
+// Allows reports whether the token may report the service.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//   - namespace: The namespace of the service, or empty if it belongs to none.
+//
+// Returns:
+//   - true if the ACL is not restricted, or the service is listed in it.
+func (a ACL) Allows(id uuid.UUID, namespace string) bool {
+	if !a.Restricted() || slices.Contains(a.IDs, id) {
+		return true
+	}
+
+	return namespace != "" && slices.Contains(a.Namespaces, namespace)
+}
@@ -0,0 +1,43 @@
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/auth"
+)
+
+// ACLTestSuite represents the test suite for the ACLs of the tokens.
+type ACLTestSuite struct {
+	suite.Suite
+}
+
+// TestACL_Unrestricted verifies that the zero ACL allows every service.
+func (suite *ACLTestSuite) TestACL_Unrestricted() {
+	var acl auth.ACL
+
+	suite.False(acl.Restricted())
+	suite.True(acl.Allows(uuid.New(), ""))
+	suite.True(acl.Allows(uuid.New(), "payments"))
+}
+
+// TestACL_Allows verifies that the listed services and the services of the listed namespaces are allowed.
+func (suite *ACLTestSuite) TestACL_Allows() {
+	listed := uuid.New()
+	acl := auth.ACL{IDs: []uuid.UUID{listed}, Namespaces: []string{"payments"}}
+
+	suite.True(acl.Restricted())
+	suite.True(acl.Allows(listed, ""))
+	suite.True(acl.Allows(listed, "search"))
+	suite.True(acl.Allows(uuid.New(), "payments"))
+	suite.False(acl.Allows(uuid.New(), "search"))
+	suite.False(acl.Allows(uuid.New(), ""))
+}
+
+// TestACLTestSuite runs the test suite for the ACLs of the tokens.
+func TestACLTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ACLTestSuite))
+}
@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrInvalidNetwork is an error that indicates that a network of the allowlist is neither a CIDR nor an IP address.
+	ErrInvalidNetwork = errors.New("auth: invalid network")
+
+	// ErrNetworkNotAllowed is an error that indicates that the client connects from a network missing in the allowlist.
+	ErrNetworkNotAllowed = errors.New("auth: the network is not allowed")
+)
+
+// Allowlist restricts the networks the clients may connect from.
+//
+// The address of a client is the address of the peer of the connection, so a
+// client behind a proxy or a NAT is seen with the address of the proxy. The
+// empty Allowlist allows every network.
+type Allowlist struct {
+	prefixes []netip.Prefix
+}
+
+// NewAllowlist creates a new Allowlist.
+//
+// Parameters:
+//   - networks: The allowed networks in the CIDR notation, e.g. 10.0.0.0/8, or
+//     single IP addresses.
+//
+// Returns:
+//   - A pointer to an Allowlist.
+//   - ErrInvalidNetwork if a network cannot be parsed.
+func NewAllowlist(networks []string) (*Allowlist, error) {
+	prefixes := make([]netip.Prefix, 0, len(networks))
+
+	for _, network := range networks {
+		if addr, err := netip.ParseAddr(network); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(network)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidNetwork, network)
+		}
+
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return &Allowlist{prefixes: prefixes}, nil
+}
+
+// Enabled reports whether the Allowlist restricts the networks at all.
+func (a *Allowlist) Enabled() bool {
+	return len(a.prefixes) > 0
+}
+
+// Allows reports whether the client may connect from the address.
+//
+// Parameters:
+//   - addr: The address of the client.
+//
+// Returns:
+//   - true if the Allowlist is not enabled, or the address belongs to an allowed network.
+func (a *Allowlist) Allows(addr netip.Addr) bool {
+	if !a.Enabled() {
+		return true
+	}
+
+	addr = addr.Unmap()
+
+	for _, prefix := range a.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allowsAddr reports whether the client may connect from the address of the connection.
+//
+// The connections without an IP address, e.g. over a Unix socket, are local
+// and always allowed.
+func (a *Allowlist) allowsAddr(addr net.Addr) bool {
+	if !a.Enabled() || addr == nil {
+		return true
+	}
+
+	switch addr.Network() {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6":
+	default:
+		return true
+	}
+
+	port, err := netip.ParseAddrPort(addr.String())
+
+	return err == nil && a.Allows(port.Addr())
+}
+
+// check rejects the call if its peer connects from a network missing in the Allowlist.
+func (a *Allowlist) check(ctx context.Context) error {
+	p, ok := peer.FromContext(ctx)
+	if !ok || a.allowsAddr(p.Addr) {
+		return nil
+	}
+
+	zerolog.Ctx(ctx).Warn().Str("addr", p.Addr.String()).Msg("Connection from an unknown network rejected")
+
+	return status.Error(codes.PermissionDenied, ErrNetworkNotAllowed.Error())
+}
+
+// UnaryInterceptor is a gRPC interceptor that rejects the unary requests from unknown networks.
+//
+// The requests are rejected with the PermissionDenied code.
+func (a *Allowlist) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if err := a.check(ctx); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor is a gRPC interceptor that rejects the streams from unknown networks.
+//
+// The streams are rejected with the PermissionDenied code.
+func (a *Allowlist) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		_ *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if err := a.check(ss.Context()); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// Middleware returns the HTTP handler rejecting the requests from unknown networks.
+//
+// The requests are rejected with 403 Forbidden.
+//
+// Parameters:
+//   - next: The handler of the allowed requests.
+//
+// Returns:
+//   - The http.Handler enforcing the Allowlist.
+func (a *Allowlist) Middleware(next http.Handler) http.Handler {
+	if !a.Enabled() {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		port, err := netip.ParseAddrPort(r.RemoteAddr)
+		if err != nil || !a.Allows(port.Addr()) {
+			zerolog.Ctx(r.Context()).Warn().Str("addr", r.RemoteAddr).Msg("Connection from an unknown network rejected")
+			http.Error(w, ErrNetworkNotAllowed.Error(), http.StatusForbidden)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,104 @@
+package auth_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"github.com/bavix/vakeel-way/internal/infra/auth"
+)
+
+// AllowlistTestSuite represents the test suite for the allowlist of the networks.
+type AllowlistTestSuite struct {
+	suite.Suite
+}
+
+// TestAllowlist_Allows verifies that the addresses of the listed networks and hosts are allowed.
+func (suite *AllowlistTestSuite) TestAllowlist_Allows() {
+	allowlist, err := auth.NewAllowlist([]string{"10.0.0.0/8", "192.168.1.7", "fd00::/8"})
+	suite.Require().NoError(err)
+
+	suite.True(allowlist.Enabled())
+	suite.True(allowlist.Allows(netip.MustParseAddr("10.20.30.40")))
+	suite.True(allowlist.Allows(netip.MustParseAddr("::ffff:10.0.0.1")))
+	suite.True(allowlist.Allows(netip.MustParseAddr("192.168.1.7")))
+	suite.True(allowlist.Allows(netip.MustParseAddr("fd00::1")))
+	suite.False(allowlist.Allows(netip.MustParseAddr("192.168.1.8")))
+	suite.False(allowlist.Allows(netip.MustParseAddr("8.8.8.8")))
+}
+
+// TestAllowlist_Empty verifies that the empty allowlist allows every network.
+func (suite *AllowlistTestSuite) TestAllowlist_Empty() {
+	allowlist, err := auth.NewAllowlist(nil)
+	suite.Require().NoError(err)
+
+	suite.False(allowlist.Enabled())
+	suite.True(allowlist.Allows(netip.MustParseAddr("8.8.8.8")))
+}
+
+// TestAllowlist_Invalid verifies that a malformed network is rejected.
+func (suite *AllowlistTestSuite) TestAllowlist_Invalid() {
+	_, err := auth.NewAllowlist([]string{"10.0.0.0/33"})
+	suite.Require().ErrorIs(err, auth.ErrInvalidNetwork)
+}
+
+// TestAllowlist_Interceptor verifies that the gRPC calls from unknown networks are rejected.
+func (suite *AllowlistTestSuite) TestAllowlist_Interceptor() {
+	allowlist, err := auth.NewAllowlist([]string{"10.0.0.0/8"})
+	suite.Require().NoError(err)
+
+	interceptor := allowlist.UnaryInterceptor()
+	handler := func(_ context.Context, _ interface{}) (interface{}, error) { return "ok", nil }
+
+	call := func(addr net.Addr) error {
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: addr}) //nolint:exhaustruct
+		_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)    //nolint:exhaustruct
+
+		return err
+	}
+
+	allowed := &net.TCPAddr{IP: net.ParseIP("10.1.2.3"), Port: 5000} //nolint:exhaustruct
+	unknown := &net.TCPAddr{IP: net.ParseIP("8.8.8.8"), Port: 5000}  //nolint:exhaustruct
+	local := &net.UnixAddr{Name: "/run/vakeel-way.sock", Net: "unix"}
+
+	suite.Require().NoError(call(allowed))
+	suite.Require().NoError(call(local))
+	suite.Equal(codes.PermissionDenied, status.Code(call(unknown)))
+}
+
+// TestAllowlist_Middleware verifies that the HTTP requests from unknown networks are rejected.
+func (suite *AllowlistTestSuite) TestAllowlist_Middleware() {
+	allowlist, err := auth.NewAllowlist([]string{"10.0.0.0/8"})
+	suite.Require().NoError(err)
+
+	handler := allowlist.Middleware(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	request := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+
+	request.RemoteAddr = "10.0.0.1:5000"
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	suite.Equal(http.StatusNoContent, recorder.Code)
+
+	request.RemoteAddr = "[2001:db8::1]:5000"
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	suite.Equal(http.StatusForbidden, recorder.Code)
+}
+
+// TestAllowlistTestSuite runs the test suite for the allowlist of the networks.
+func TestAllowlistTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(AllowlistTestSuite))
+}
@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	// ErrMissingToken is an error that indicates that the request carries no token.
+	ErrMissingToken = errors.New("auth: missing token")
+
+	// ErrInvalidToken is an error that indicates that the token is not recognized.
+	ErrInvalidToken = errors.New("auth: invalid token")
+)
+
+// Principal represents the authenticated caller.
+type Principal struct {
+	// Name is the name of the token, used in the logs and metrics.
+	Name string
+
+	// Namespace is the namespace the token is scoped to, or empty if the
+	// token sees every service.
+	Namespace string
+
+	// Quota is the quota of the token.
+	Quota Quota
+
+	// ACL restricts the services the token may report heartbeats for.
+	ACL ACL
+
+	// Scopes are the scopes granted to the caller by its JWT or its token
+	// configuration, or nil if the caller is not restricted to any scopes.
+	Scopes []string
+}
+
+// HasScope reports whether the caller was granted the scope.
+//
+// The callers without scopes have every scope, and every caller has the empty scope.
+func (p Principal) HasScope(scope string) bool {
+	return p.Scopes == nil || scope == "" || slices.Contains(p.Scopes, scope)
+}
+
+// Quota represents the limits of a single token.
+//
+// A zero value means that the limit is not enforced.
+type Quota struct {
+	// MaxIDs is the maximum number of distinct UUIDs the token may report.
+	MaxIDs int
+
+	// HeartbeatsPerMinute is the maximum number of heartbeats the token may send per minute.
+	HeartbeatsPerMinute int
+}
+
+// Token represents a configured API token.
+type Token struct {
+	// Secret is the value sent by the clients.
+	Secret string
+
+	// Principal is the caller identified by the token.
+	Principal Principal
+}
+
+// Authenticator resolves the API tokens sent by the clients into principals.
+//
+// If no tokens are configured and the JWTs are not accepted, the
+// authentication is disabled and every request is accepted anonymously.
+type Authenticator struct {
+	// tokens is the list of the configured tokens.
+	tokens []Token
+
+	// jwt is the JWTVerifier of the signed tokens, or nil if they are not accepted.
+	jwt *JWTVerifier
+}
+
+// NewAuthenticator creates a new instance of the Authenticator struct.
+//
+// Parameters:
+//   - tokens: The configured API tokens.
+//   - jwt: The JWTVerifier of the signed tokens, or nil if they are not accepted.
+//
+// Returns:
+//   - A pointer to the initialized Authenticator.
+func NewAuthenticator(tokens []Token, jwt *JWTVerifier) *Authenticator {
+	return &Authenticator{tokens: tokens, jwt: jwt}
+}
+
+// Enabled reports whether the authentication is enabled.
+func (a *Authenticator) Enabled() bool {
+	return len(a.tokens) > 0 || a.jwt != nil
+}
+
+// Authenticate resolves the token into a principal.
+//
+// The static tokens are compared in constant time to avoid leaking them
+// through timing differences. The other tokens are verified as JWTs, if they
+// are accepted.
+//
+// Parameters:
+//   - ctx: The context.Context used to fetch the keys of the JWTs.
+//   - secret: The token sent by the client.
+//
+// Returns:
+//   - The principal identified by the token.
+//   - ErrMissingToken or ErrInvalidToken if the token is not accepted.
+func (a *Authenticator) Authenticate(ctx context.Context, secret string) (Principal, error) {
+	if secret == "" {
+		return Principal{}, ErrMissingToken
+	}
+
+	for _, token := range a.tokens {
+		if subtle.ConstantTimeCompare([]byte(token.Secret), []byte(secret)) == 1 {
+			return token.Principal, nil
+		}
+	}
+
+	if a.jwt != nil {
+		return a.jwt.Verify(ctx, secret)
+	}
+
+	return Principal{}, ErrInvalidToken
+}
+
+// principalKey is the context key of the principal.
+type principalKey struct{}
+
+// WithPrincipal returns a copy of the context carrying the principal.
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the principal carried by the context.
+//
+// Returns:
+//   - The principal.
+//   - false if the request is anonymous.
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(Principal)
+
+	return principal, ok
+}
+
+// tokenFromMetadata extracts the bearer token from the incoming gRPC metadata.
+//
+// The token is read from the "authorization" header, with or without the
+// "Bearer " prefix.
+func tokenFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return ""
+	}
+
+	value := strings.TrimSpace(values[0])
+	if len(value) > len("bearer ") && strings.EqualFold(value[:len("bearer ")], "bearer ") {
+		value = strings.TrimSpace(value[len("bearer "):])
+	}
+
+	return value
+}
@@ -0,0 +1,84 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// serverStream is a wrapper around a gRPC server stream that replaces its context.
+type serverStream struct {
+	grpc.ServerStream
+
+	ctx context.Context //nolint:containedctx
+}
+
+// Context returns the context carrying the principal.
+func (s serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// authenticate resolves the principal of the request and attaches it to the context.
+//
+// The callers authenticated with a JWT without the scope required by the
+// method are rejected with the PermissionDenied code.
+func (a *Authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	// Accept every request anonymously if the authentication is disabled, and
+	// the requests of the public methods.
+	if !a.Enabled() || Public(method) {
+		return ctx, nil
+	}
+
+	principal, err := a.Authenticate(ctx, tokenFromMetadata(ctx))
+	if err != nil {
+		return ctx, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	if scope := RequiredScope(method); !principal.HasScope(scope) {
+		return ctx, status.Errorf(codes.PermissionDenied, "%s: the %s scope is required", ErrMissingScope, scope)
+	}
+
+	return WithPrincipal(ctx, principal), nil
+}
+
+// UnaryInterceptor is a gRPC interceptor that authenticates the unary requests.
+//
+// The requests without a valid token are rejected with the Unauthenticated code,
+// and the requests without the required scope with the PermissionDenied code.
+func (a *Authenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		ctx, err := a.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor is a gRPC interceptor that authenticates the streams.
+//
+// The streams without a valid token are rejected with the Unauthenticated code,
+// and the streams without the required scope with the PermissionDenied code.
+func (a *Authenticator) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		ctx, err := a.authenticate(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
@@ -0,0 +1,70 @@
+package auth_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/bavix/vakeel-way/internal/infra/auth"
+)
+
+// InterceptorTestSuite represents the test suite for the authentication of the gRPC calls.
+type InterceptorTestSuite struct {
+	suite.Suite
+
+	authenticator *auth.Authenticator
+}
+
+// SetupTest configures a heartbeat token and an admin token.
+func (suite *InterceptorTestSuite) SetupTest() {
+	suite.authenticator = auth.NewAuthenticator([]auth.Token{
+		{Secret: "agent", Principal: auth.Principal{Name: "agent", Scopes: []string{auth.ScopeReport, auth.ScopeRead}}},
+		{Secret: "operator", Principal: auth.Principal{Name: "operator", Scopes: []string{auth.ScopeAdmin}}},
+	}, nil)
+}
+
+// call calls the method with the token and returns the code of the result.
+func (suite *InterceptorTestSuite) call(method, token string) codes.Code {
+	ctx := context.Background()
+	if token != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", "Bearer "+token))
+	}
+
+	handler := func(_ context.Context, _ interface{}) (interface{}, error) { return "ok", nil }
+
+	_, err := suite.authenticator.UnaryInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: method}, handler) //nolint:exhaustruct
+
+	return status.Code(err)
+}
+
+// TestInterceptor_Public verifies that the capabilities are served without a token.
+func (suite *InterceptorTestSuite) TestInterceptor_Public() {
+	suite.Equal(codes.OK, suite.call("/vakeel_way.CapabilityService/GetCapabilities", ""))
+	suite.Equal(codes.OK, suite.call("/vakeel_way.CapabilityService/GetCapabilities", "agent"))
+
+	suite.Equal(codes.Unauthenticated, suite.call("/vakeel_way.StateService/GetStatus", ""))
+	suite.Equal(codes.Unauthenticated, suite.call("/vakeel_way.StateService/GetStatus", "guess"))
+	suite.Equal(codes.Unauthenticated, suite.call("/vakeel_way.AdminService/Reload", ""))
+}
+
+// TestInterceptor_Admin verifies that only the tokens granted the admin scope call the AdminService.
+func (suite *InterceptorTestSuite) TestInterceptor_Admin() {
+	suite.Equal(codes.OK, suite.call("/vakeel_way.StateService/Update", "agent"))
+	suite.Equal(codes.OK, suite.call("/vakeel_way.StateService/UpdateStream", "agent"))
+	suite.Equal(codes.OK, suite.call("/vakeel_way.StateService/GetStatus", "agent"))
+	suite.Equal(codes.PermissionDenied, suite.call("/vakeel_way.AdminService/Reload", "agent"))
+
+	suite.Equal(codes.OK, suite.call("/vakeel_way.AdminService/Reload", "operator"))
+	suite.Equal(codes.PermissionDenied, suite.call("/vakeel_way.StateService/Update", "operator"))
+}
+
+// TestInterceptorTestSuite runs the test suite for the authentication of the gRPC calls.
+func TestInterceptorTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(InterceptorTestSuite))
+}
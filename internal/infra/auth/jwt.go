@@ -0,0 +1,589 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"math/big"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrJWKSUnavailable is an error that indicates that the JWKS cannot be fetched.
+var ErrJWKSUnavailable = errors.New("auth: jwks unavailable")
+
+const (
+	// jwksRefresh is the time the keys of the JWKS are cached for.
+	jwksRefresh = time.Hour
+
+	// jwksRetry is the minimum time between two fetches of the JWKS, so the
+	// tokens signed with unknown keys cannot flood the identity provider.
+	jwksRetry = time.Minute
+
+	// maxJWKSBody is the maximum size of the JWKS document.
+	maxJWKSBody = 1 << 20
+)
+
+// JWTOption is a function that configures a JWTVerifier.
+type JWTOption func(*JWTVerifier)
+
+// WithHMAC returns a JWTOption that accepts the tokens signed with the shared
+// secret using HS256, HS384 or HS512.
+//
+// Parameters:
+//   - secret: The shared secret.
+//
+// Returns a JWTOption that sets the secret of the JWTVerifier.
+func WithHMAC(secret string) JWTOption {
+	return func(v *JWTVerifier) {
+		v.secret = []byte(secret)
+	}
+}
+
+// WithJWKS returns a JWTOption that accepts the tokens signed with the keys of
+// a JSON Web Key Set using RS256, RS384, RS512, PS256, PS384, PS512, ES256,
+// ES384 or ES512.
+//
+// The keys are fetched on the first token, cached for an hour and fetched
+// again when a token is signed with an unknown key, at most once a minute.
+//
+// Parameters:
+//   - url: The URL of the JWKS, e.g. https://idp.example.com/.well-known/jwks.json.
+//   - client: The HTTP client used to fetch the JWKS.
+//
+// Returns a JWTOption that sets the JWKS of the JWTVerifier.
+func WithJWKS(url string, client *http.Client) JWTOption {
+	return func(v *JWTVerifier) {
+		v.jwks, v.client = url, client
+	}
+}
+
+// WithIssuer returns a JWTOption that requires the iss claim of the tokens.
+func WithIssuer(issuer string) JWTOption {
+	return func(v *JWTVerifier) {
+		v.issuer = issuer
+	}
+}
+
+// WithAudience returns a JWTOption that requires the aud claim of the tokens to contain the audience.
+func WithAudience(audience string) JWTOption {
+	return func(v *JWTVerifier) {
+		v.audience = audience
+	}
+}
+
+// WithClaims returns a JWTOption that sets the claims carrying the tenant and the scopes.
+//
+// Parameters:
+//   - tenant: The claim carrying the namespace the caller is scoped to.
+//   - scope: The claim carrying the scopes of the caller, either a string of
+//     space-separated scopes or a list of strings.
+//
+// Returns a JWTOption that sets the claims of the JWTVerifier.
+func WithClaims(tenant, scope string) JWTOption {
+	return func(v *JWTVerifier) {
+		v.tenantClaim, v.scopeClaim = tenant, scope
+	}
+}
+
+// WithLeeway returns a JWTOption that sets the tolerated clock skew of the exp and nbf claims.
+func WithLeeway(leeway time.Duration) JWTOption {
+	return func(v *JWTVerifier) {
+		v.leeway = leeway
+	}
+}
+
+// JWTVerifier verifies the signed JSON Web Tokens of the callers.
+//
+// A token is accepted if it is signed with the shared secret or with a key of
+// the JWKS, has not expired and carries the required issuer and audience.
+// The algorithm of the token must match the kind of the key, so a token
+// signed with the public key of the JWKS as an HMAC secret is rejected. The
+// tokens without the exp claim are rejected.
+type JWTVerifier struct {
+	// secret is the shared secret of the HMAC tokens, or nil.
+	secret []byte
+
+	// jwks is the URL of the JWKS, or empty.
+	jwks string
+
+	// client is the HTTP client used to fetch the JWKS.
+	client *http.Client
+
+	// issuer is the required iss claim, or empty.
+	issuer string
+
+	// audience is the required aud claim, or empty.
+	audience string
+
+	// tenantClaim is the claim carrying the namespace of the caller.
+	tenantClaim string
+
+	// scopeClaim is the claim carrying the scopes of the caller.
+	scopeClaim string
+
+	// leeway is the tolerated clock skew.
+	leeway time.Duration
+
+	// now returns the current time.
+	now func() time.Time
+
+	// mu is the mutex used to synchronize access to the keys.
+	mu sync.Mutex
+
+	// keys maps the key IDs of the JWKS to the public keys.
+	keys map[string]crypto.PublicKey
+
+	// fetched is the last time the JWKS was fetched.
+	fetched time.Time
+}
+
+// NewJWTVerifier creates a new instance of the JWTVerifier struct.
+//
+// By default the tenant is read from the tenant claim and the scopes from the
+// scope claim, and a minute of clock skew is tolerated.
+//
+// Parameters:
+//   - opts: The options of the verifier.
+//
+// Returns:
+//   - A pointer to the initialized JWTVerifier.
+//
+//nolint:exhaustruct
+func NewJWTVerifier(opts ...JWTOption) *JWTVerifier {
+	verifier := &JWTVerifier{
+		client:      http.DefaultClient,
+		tenantClaim: "tenant",
+		scopeClaim:  "scope",
+		leeway:      time.Minute,
+		now:         time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(verifier)
+	}
+
+	return verifier
+}
+
+// jwtHeader is the JOSE header of a token.
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+// Claims represents the claims of a verified token.
+type Claims map[string]json.RawMessage
+
+// String returns the value of a string claim, or empty if it is missing or not a string.
+func (c Claims) String(name string) string {
+	return stringClaim(c[name])
+}
+
+// Verify checks the signature and the claims of the token and returns the caller.
+//
+// The sub claim is the name of the principal, the tenant claim its namespace
+// and the scope claim its scopes. A token without scopes may call only the
+// methods that require none.
+//
+// Parameters:
+//   - ctx: The context.Context used to fetch the JWKS.
+//   - token: The compact serialization of the token.
+//
+// Returns:
+//   - The principal identified by the token.
+//   - ErrInvalidToken if the token is not accepted, or ErrJWKSUnavailable if
+//     its key cannot be fetched.
+func (v *JWTVerifier) Verify(ctx context.Context, token string) (Principal, error) {
+	claims, err := v.Claims(ctx, token)
+	if err != nil {
+		return Principal{}, err
+	}
+
+	name := "jwt"
+	if subject := claims.String("sub"); subject != "" {
+		name = "jwt:" + subject
+	}
+
+	return Principal{
+		Name:      name,
+		Namespace: claims.String(v.tenantClaim),
+		Quota:     Quota{MaxIDs: 0, HeartbeatsPerMinute: 0},
+		ACL:       ACL{IDs: nil, Namespaces: nil},
+		Scopes:    scopes(claims[v.scopeClaim]),
+	}, nil
+}
+
+// Claims checks the signature and the registered claims of the token and returns all its claims.
+//
+// Parameters:
+//   - ctx: The context.Context used to fetch the JWKS.
+//   - token: The compact serialization of the token.
+//
+// Returns:
+//   - The claims of the token.
+//   - ErrInvalidToken if the token is not accepted, or ErrJWKSUnavailable if
+//     its key cannot be fetched.
+func (v *JWTVerifier) Claims(ctx context.Context, token string) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 { //nolint:mnd
+		return nil, fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	var header jwtHeader
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed signature", ErrInvalidToken)
+	}
+
+	if err := v.verifySignature(ctx, header, []byte(parts[0]+"."+parts[1]), signature); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+
+	if err := v.verifyClaims(claims); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// verifySignature checks the signature of the token with the key matching its algorithm.
+func (v *JWTVerifier) verifySignature(ctx context.Context, header jwtHeader, signed, signature []byte) error {
+	if len(header.Alg) != 5 { //nolint:mnd
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, header.Alg)
+	}
+
+	var hasher func() hash.Hash
+
+	digest := crypto.Hash(0)
+
+	switch header.Alg[2:] {
+	case "256":
+		hasher, digest = sha256.New, crypto.SHA256
+	case "384":
+		hasher, digest = sha512.New384, crypto.SHA384
+	case "512":
+		hasher, digest = sha512.New, crypto.SHA512
+	default:
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, header.Alg)
+	}
+
+	// The HMAC tokens are verified with the shared secret only.
+	if header.Alg[:2] == "HS" {
+		if len(v.secret) == 0 {
+			return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, header.Alg)
+		}
+
+		mac := hmac.New(hasher, v.secret)
+		mac.Write(signed)
+
+		if !hmac.Equal(mac.Sum(nil), signature) {
+			return fmt.Errorf("%w: invalid signature", ErrInvalidToken)
+		}
+
+		return nil
+	}
+
+	if v.jwks == "" {
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, header.Alg)
+	}
+
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		return err
+	}
+
+	h := hasher()
+	h.Write(signed)
+	sum := h.Sum(nil)
+
+	valid := false
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		switch header.Alg[:2] {
+		case "RS":
+			valid = rsa.VerifyPKCS1v15(k, digest, sum, signature) == nil
+		case "PS":
+			valid = rsa.VerifyPSS(k, digest, sum, signature, nil) == nil
+		}
+	case *ecdsa.PublicKey:
+		size := (k.Curve.Params().BitSize + 7) / 8 //nolint:mnd
+		if header.Alg[:2] == "ES" && len(signature) == 2*size {
+			r := new(big.Int).SetBytes(signature[:size])
+			s := new(big.Int).SetBytes(signature[size:])
+			valid = ecdsa.Verify(k, sum, r, s)
+		}
+	}
+
+	if !valid {
+		return fmt.Errorf("%w: invalid signature", ErrInvalidToken)
+	}
+
+	return nil
+}
+
+// verifyClaims checks the expiration, the issuer and the audience of the token.
+func (v *JWTVerifier) verifyClaims(claims Claims) error {
+	now := v.now()
+
+	expires, ok := timeClaim(claims["exp"])
+	if !ok {
+		return fmt.Errorf("%w: missing expiration", ErrInvalidToken)
+	}
+
+	if now.After(expires.Add(v.leeway)) {
+		return fmt.Errorf("%w: token expired", ErrInvalidToken)
+	}
+
+	if notBefore, ok := timeClaim(claims["nbf"]); ok && now.Add(v.leeway).Before(notBefore) {
+		return fmt.Errorf("%w: token not valid yet", ErrInvalidToken)
+	}
+
+	if v.issuer != "" && stringClaim(claims["iss"]) != v.issuer {
+		return fmt.Errorf("%w: unexpected issuer", ErrInvalidToken)
+	}
+
+	if v.audience != "" && !slices.Contains(listClaim(claims["aud"]), v.audience) {
+		return fmt.Errorf("%w: unexpected audience", ErrInvalidToken)
+	}
+
+	return nil
+}
+
+// key returns the public key of the JWKS with the key ID.
+//
+// If the token has no key ID, the JWKS must hold a single key.
+func (v *JWTVerifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	lookup := func() (crypto.PublicKey, bool) {
+		if kid == "" && len(v.keys) == 1 {
+			for _, key := range v.keys {
+				return key, true
+			}
+		}
+
+		key, ok := v.keys[kid]
+
+		return key, ok
+	}
+
+	now := v.now()
+	stale := now.Sub(v.fetched) >= jwksRefresh
+
+	key, ok := lookup()
+	if ok && !stale {
+		return key, nil
+	}
+
+	// Fetch the keys again if they are stale, or if the key is unknown and
+	// the keys were not fetched recently.
+	if stale || now.Sub(v.fetched) >= jwksRetry {
+		keys, err := v.fetch(ctx)
+		if err != nil && !ok {
+			return nil, err
+		}
+
+		// Keep the cached keys if the JWKS is unavailable.
+		if err == nil {
+			v.keys, v.fetched = keys, now
+		}
+
+		key, ok = lookup()
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+	}
+
+	return key, nil
+}
+
+// jwk is a JSON Web Key of the JWKS.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// fetch downloads the JWKS and returns its signing keys by key ID.
+//
+// The keys of unknown types and the encryption keys are skipped.
+func (v *JWTVerifier) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.jwks, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJWKSUnavailable, err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJWKSUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: status %d", ErrJWKSUnavailable, resp.StatusCode)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJWKSBody)).Decode(&set); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrJWKSUnavailable, err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+
+	for _, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		if public, ok := key.public(); ok {
+			keys[key.Kid] = public
+		}
+	}
+
+	return keys, nil
+}
+
+// public returns the public key of the JWK, or false if it is not an RSA or an EC key.
+func (k jwk) public() (crypto.PublicKey, bool) {
+	decode := func(value string) (*big.Int, bool) {
+		data, err := base64.RawURLEncoding.DecodeString(value)
+
+		return new(big.Int).SetBytes(data), err == nil && len(data) > 0
+	}
+
+	switch k.Kty {
+	case "RSA":
+		n, okN := decode(k.N)
+		e, okE := decode(k.E)
+
+		if !okN || !okE || !e.IsInt64() {
+			return nil, false
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, true
+	case "EC":
+		curves := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384(), "P-521": elliptic.P521()}
+
+		curve, ok := curves[k.Crv]
+		x, okX := decode(k.X)
+		y, okY := decode(k.Y)
+
+		if !ok || !okX || !okY {
+			return nil, false
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, true
+	default:
+		return nil, false
+	}
+}
+
+// decodeSegment decodes a base64url-encoded JSON segment of the token.
+func decodeSegment(segment string, out any) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(out); err != nil {
+		return fmt.Errorf("%w: malformed token", ErrInvalidToken)
+	}
+
+	return nil
+}
+
+// stringClaim returns the value of a string claim, or empty.
+func stringClaim(raw json.RawMessage) string {
+	var value string
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return ""
+	}
+
+	return value
+}
+
+// listClaim returns the values of a claim that is a string or a list of strings.
+func listClaim(raw json.RawMessage) []string {
+	var values []string
+	if err := json.Unmarshal(raw, &values); err == nil {
+		return values
+	}
+
+	if value := stringClaim(raw); value != "" {
+		return []string{value}
+	}
+
+	return nil
+}
+
+// scopes returns the scopes of a claim that is a string of space-separated
+// scopes or a list of strings. It is never nil, so a token without scopes is
+// restricted.
+func scopes(raw json.RawMessage) []string {
+	var values []string
+	if err := json.Unmarshal(raw, &values); err != nil {
+		values = strings.Fields(stringClaim(raw))
+	}
+
+	if values == nil {
+		values = []string{}
+	}
+
+	return values
+}
+
+// timeClaim returns the time of a NumericDate claim, or false if it is missing.
+func timeClaim(raw json.RawMessage) (time.Time, bool) {
+	var seconds json.Number
+	if err := json.Unmarshal(raw, &seconds); err != nil {
+		return time.Time{}, false
+	}
+
+	value, err := seconds.Float64()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(int64(value), 0), true
+}
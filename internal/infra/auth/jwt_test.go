@@ -0,0 +1,198 @@
+package auth_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/auth"
+)
+
+// JWTTestSuite represents the test suite for the verifier of the JWTs.
+type JWTTestSuite struct {
+	suite.Suite
+}
+
+// encode returns the base64url encoding of the JSON value.
+func encode(value any) string {
+	data, _ := json.Marshal(value)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// sign returns the token with the header and the claims signed by the function.
+func sign(header, claims map[string]any, signer func(signed []byte) []byte) string {
+	signed := encode(header) + "." + encode(claims)
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signer([]byte(signed)))
+}
+
+// hs256 returns the signer of the HS256 tokens.
+func hs256(secret string) func([]byte) []byte {
+	return func(signed []byte) []byte {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(signed)
+
+		return mac.Sum(nil)
+	}
+}
+
+// claims returns the claims of a token expiring in an hour.
+func claims() map[string]any {
+	return map[string]any{
+		"sub":    "ci",
+		"tenant": "payments",
+		"scope":  "report read",
+		"iss":    "https://idp.example.com",
+		"aud":    []string{"vakeel-way"},
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+// TestJWT_HMAC verifies that a token signed with the shared secret identifies the caller.
+func (suite *JWTTestSuite) TestJWT_HMAC() {
+	verifier := auth.NewJWTVerifier(
+		auth.WithHMAC("s3cr3t"),
+		auth.WithIssuer("https://idp.example.com"),
+		auth.WithAudience("vakeel-way"),
+	)
+
+	principal, err := verifier.Verify(context.Background(),
+		sign(map[string]any{"alg": "HS256", "typ": "JWT"}, claims(), hs256("s3cr3t")))
+	suite.Require().NoError(err)
+
+	suite.Equal("jwt:ci", principal.Name)
+	suite.Equal("payments", principal.Namespace)
+	suite.Equal([]string{"report", "read"}, principal.Scopes)
+	suite.True(principal.HasScope(auth.ScopeReport))
+	suite.False(principal.HasScope(auth.ScopeAdmin))
+	suite.True(principal.HasScope(""))
+}
+
+// TestJWT_Rejected verifies that the forged, expired and foreign tokens are rejected.
+func (suite *JWTTestSuite) TestJWT_Rejected() {
+	verifier := auth.NewJWTVerifier(
+		auth.WithHMAC("s3cr3t"),
+		auth.WithIssuer("https://idp.example.com"),
+		auth.WithAudience("vakeel-way"),
+		auth.WithLeeway(0),
+	)
+
+	header := map[string]any{"alg": "HS256"}
+
+	expired := claims()
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+
+	noExpiry := claims()
+	delete(noExpiry, "exp")
+
+	foreign := claims()
+	foreign["aud"] = "another-service"
+
+	tokens := map[string]string{
+		"wrong secret": sign(header, claims(), hs256("guessed")),
+		"expired":      sign(header, expired, hs256("s3cr3t")),
+		"no expiry":    sign(header, noExpiry, hs256("s3cr3t")),
+		"audience":     sign(header, foreign, hs256("s3cr3t")),
+		"none":         encode(map[string]any{"alg": "none"}) + "." + encode(claims()) + ".",
+		"malformed":    "not-a-jwt",
+		"rs256":        sign(map[string]any{"alg": "RS256"}, claims(), hs256("s3cr3t")),
+	}
+
+	for name, token := range tokens {
+		_, err := verifier.Verify(context.Background(), token)
+		suite.Require().ErrorIs(err, auth.ErrInvalidToken, name)
+	}
+}
+
+// TestJWT_JWKS verifies that the tokens signed with the RSA and EC keys of the JWKS are accepted.
+func (suite *JWTTestSuite) TestJWT_JWKS() {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().NoError(err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.Require().NoError(err)
+
+	b64 := func(value *big.Int) string {
+		return base64.RawURLEncoding.EncodeToString(value.Bytes())
+	}
+
+	fetches := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches++
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]any{
+			{"kty": "RSA", "kid": "rsa", "use": "sig", "n": b64(rsaKey.N), "e": b64(big.NewInt(int64(rsaKey.E)))},
+			{"kty": "EC", "kid": "ec", "crv": "P-256", "x": b64(ecKey.X), "y": b64(ecKey.Y)},
+		}})
+	}))
+	defer server.Close()
+
+	verifier := auth.NewJWTVerifier(auth.WithJWKS(server.URL, server.Client()))
+
+	rs256 := sign(map[string]any{"alg": "RS256", "kid": "rsa"}, claims(), func(signed []byte) []byte {
+		sum := sha256.Sum256(signed)
+		signature, _ := rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, sum[:])
+
+		return signature
+	})
+
+	es256 := sign(map[string]any{"alg": "ES256", "kid": "ec"}, claims(), func(signed []byte) []byte {
+		sum := sha256.Sum256(signed)
+		r, s, _ := ecdsa.Sign(rand.Reader, ecKey, sum[:])
+
+		signature := make([]byte, 64)
+		r.FillBytes(signature[:32])
+		s.FillBytes(signature[32:])
+
+		return signature
+	})
+
+	for _, token := range []string{rs256, es256} {
+		principal, err := verifier.Verify(context.Background(), token)
+		suite.Require().NoError(err)
+		suite.Equal("payments", principal.Namespace)
+	}
+
+	// The keys are cached, and an unknown key is not fetched again right away.
+	_, err = verifier.Verify(context.Background(), sign(map[string]any{"alg": "RS256", "kid": "rotated"}, claims(),
+		func([]byte) []byte { return []byte("signature") }))
+	suite.Require().ErrorIs(err, auth.ErrInvalidToken)
+	suite.Equal(1, fetches)
+
+	// The public key of the JWKS cannot be used as an HMAC secret.
+	_, err = verifier.Verify(context.Background(), sign(map[string]any{"alg": "HS256", "kid": "rsa"}, claims(),
+		hs256(b64(rsaKey.N))))
+	suite.Require().ErrorIs(err, auth.ErrInvalidToken)
+}
+
+// TestRequiredScope verifies the scopes required by the gRPC methods.
+func (suite *JWTTestSuite) TestRequiredScope() {
+	suite.Equal(auth.ScopeReport, auth.RequiredScope("/vakeel_way.StateService/Update"))
+	suite.Equal(auth.ScopeReport, auth.RequiredScope("/vakeel_way.StateService/UpdateStream"))
+	suite.Equal(auth.ScopeReport, auth.RequiredScope("/vakeel_way.v2.StateService/Update"))
+	suite.Equal(auth.ScopeRead, auth.RequiredScope("/vakeel_way.StateService/GetStatus"))
+	suite.Equal(auth.ScopeAdmin, auth.RequiredScope("/vakeel_way.AdminService/Reload"))
+	suite.Empty(auth.RequiredScope("/vakeel_way.CapabilityService/GetCapabilities"))
+}
+
+// TestJWTTestSuite runs the test suite for the verifier of the JWTs.
+func TestJWTTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(JWTTestSuite))
+}
@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	// ErrTooManyIDs is an error that indicates that the token reports more distinct UUIDs than allowed.
+	ErrTooManyIDs = errors.New("auth: quota exceeded: too many distinct ids")
+
+	// ErrRateLimited is an error that indicates that the token sends more heartbeats per minute than allowed.
+	ErrRateLimited = errors.New("auth: quota exceeded: too many heartbeats per minute")
+)
+
+// usage is the quota usage of a single token.
+type usage struct {
+	// ids is the set of the distinct UUIDs reported by the token.
+	ids map[uuid.UUID]struct{}
+
+	// tokens is the number of heartbeats the token may still send (token bucket).
+	tokens float64
+
+	// updated is the last time the bucket was refilled.
+	updated time.Time
+}
+
+// Limiter enforces the quotas of the tokens.
+//
+// The number of distinct UUIDs is tracked for the whole lifetime of the
+// process, while the heartbeats are limited with a token bucket refilled at
+// the configured rate per minute.
+type Limiter struct {
+	// usage maps the names of the tokens to their quota usage.
+	usage map[string]*usage
+
+	// mu is the mutex used to synchronize access to the usage.
+	mu sync.Mutex
+
+	// now returns the current time.
+	now func() time.Time
+
+	// exceeded counts the rejected heartbeats by token and reason.
+	exceeded *prometheus.CounterVec
+
+	// accepted counts the accepted heartbeats by token.
+	accepted *prometheus.CounterVec
+}
+
+// NewLimiter creates a new instance of the Limiter struct.
+//
+// Parameters:
+//   - registerer: The prometheus.Registerer used to register the quota metrics.
+//
+// Returns:
+//   - A pointer to the initialized Limiter.
+func NewLimiter(registerer prometheus.Registerer) *Limiter {
+	limiter := &Limiter{
+		usage: make(map[string]*usage),
+		mu:    sync.Mutex{},
+		now:   time.Now,
+		exceeded: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "vakeel",
+			Name:      "quota_exceeded_total",
+			Help:      "Number of heartbeats rejected because the token exceeded its quota.",
+		}, []string{"token", "reason"}),
+		accepted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "vakeel",
+			Name:      "heartbeats_total",
+			Help:      "Number of heartbeats accepted per token.",
+		}, []string{"token"}),
+	}
+
+	registerer.MustRegister(limiter.exceeded, limiter.accepted)
+
+	return limiter
+}
+
+// Allow checks every heartbeat of the principal against its quota.
+//
+// The heartbeats are admitted one by one, in order, so a batch exceeding the
+// quota has its first heartbeats accepted and the rest rejected. Only the
+// accepted heartbeats are counted against the quota.
+//
+// Parameters:
+//   - principal: The principal sending the heartbeats.
+//   - ids: The UUIDs of the heartbeats.
+//
+// Returns:
+//   - The errors of the heartbeats, in the order of the UUIDs: nil if the
+//     heartbeat is accepted, ErrTooManyIDs or ErrRateLimited otherwise.
+func (l *Limiter) Allow(principal Principal, ids []uuid.UUID) []error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	quota := principal.Quota
+	now := l.now()
+
+	// Create the usage of the token on the first heartbeat.
+	use, ok := l.usage[principal.Name]
+	if !ok {
+		use = &usage{
+			ids:     make(map[uuid.UUID]struct{}),
+			tokens:  float64(quota.HeartbeatsPerMinute),
+			updated: now,
+		}
+		l.usage[principal.Name] = use
+	}
+
+	// Refill the bucket.
+	if quota.HeartbeatsPerMinute > 0 {
+		limit := float64(quota.HeartbeatsPerMinute)
+
+		use.tokens = min(limit, use.tokens+now.Sub(use.updated).Minutes()*limit)
+		use.updated = now
+	}
+
+	errs := make([]error, len(ids))
+
+	for i, id := range ids {
+		_, known := use.ids[id]
+
+		switch {
+		case quota.MaxIDs > 0 && !known && len(use.ids) >= quota.MaxIDs:
+			l.exceeded.WithLabelValues(principal.Name, "ids").Inc()
+			errs[i] = ErrTooManyIDs
+		case quota.HeartbeatsPerMinute > 0 && use.tokens < 1:
+			l.exceeded.WithLabelValues(principal.Name, "rate").Inc()
+			errs[i] = ErrRateLimited
+		default:
+			// Take a token for the heartbeat and remember its UUID.
+			if quota.HeartbeatsPerMinute > 0 {
+				use.tokens--
+			}
+
+			if quota.MaxIDs > 0 {
+				use.ids[id] = struct{}{}
+			}
+
+			l.accepted.WithLabelValues(principal.Name).Inc()
+		}
+	}
+
+	return errs
+}
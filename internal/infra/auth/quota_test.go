@@ -0,0 +1,86 @@
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/auth"
+)
+
+// QuotaTestSuite represents the test suite for the quotas of the tokens.
+type QuotaTestSuite struct {
+	suite.Suite
+}
+
+// TestLimiter_Rate verifies that only the heartbeats over the rate are rejected.
+func (suite *QuotaTestSuite) TestLimiter_Rate() {
+	limiter := auth.NewLimiter(prometheus.NewRegistry())
+	principal := auth.Principal{Name: "ci", Quota: auth.Quota{HeartbeatsPerMinute: 3}}
+
+	id := uuid.New()
+
+	suite.Equal([]error{nil, nil}, limiter.Allow(principal, []uuid.UUID{id, id}))
+
+	// The batch crossing the rate has its first heartbeat accepted.
+	errs := limiter.Allow(principal, []uuid.UUID{id, id, id})
+	suite.Require().Len(errs, 3)
+	suite.Require().NoError(errs[0])
+	suite.Require().ErrorIs(errs[1], auth.ErrRateLimited)
+	suite.Require().ErrorIs(errs[2], auth.ErrRateLimited)
+
+	// The rejected heartbeats are not counted, so the next ones are rejected
+	// until the bucket is refilled, without closing anything.
+	suite.Require().ErrorIs(limiter.Allow(principal, []uuid.UUID{id})[0], auth.ErrRateLimited)
+}
+
+// TestLimiter_IDs verifies that only the heartbeats of the UUIDs over the limit are rejected.
+func (suite *QuotaTestSuite) TestLimiter_IDs() {
+	limiter := auth.NewLimiter(prometheus.NewRegistry())
+	principal := auth.Principal{Name: "ci", Quota: auth.Quota{MaxIDs: 2}}
+
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+
+	errs := limiter.Allow(principal, []uuid.UUID{first, second, third, first})
+	suite.Require().Len(errs, 4)
+	suite.Require().NoError(errs[0])
+	suite.Require().NoError(errs[1])
+	suite.Require().ErrorIs(errs[2], auth.ErrTooManyIDs)
+	suite.Require().NoError(errs[3])
+
+	// The UUIDs already reported keep being accepted.
+	suite.Equal([]error{nil, nil}, limiter.Allow(principal, []uuid.UUID{second, first}))
+	suite.Require().ErrorIs(limiter.Allow(principal, []uuid.UUID{third})[0], auth.ErrTooManyIDs)
+}
+
+// TestLimiter_Names verifies that every token has a quota of its own.
+func (suite *QuotaTestSuite) TestLimiter_Names() {
+	limiter := auth.NewLimiter(prometheus.NewRegistry())
+	quota := auth.Quota{HeartbeatsPerMinute: 1}
+
+	id := uuid.New()
+
+	suite.Equal([]error{nil}, limiter.Allow(auth.Principal{Name: "ci", Quota: quota}, []uuid.UUID{id}))
+	suite.Equal([]error{nil}, limiter.Allow(auth.Principal{Name: "cron", Quota: quota}, []uuid.UUID{id}))
+	suite.Require().ErrorIs(limiter.Allow(auth.Principal{Name: "ci", Quota: quota}, []uuid.UUID{id})[0], auth.ErrRateLimited)
+}
+
+// TestLimiter_Unlimited verifies that the tokens without a quota are never rejected.
+func (suite *QuotaTestSuite) TestLimiter_Unlimited() {
+	limiter := auth.NewLimiter(prometheus.NewRegistry())
+
+	ids := make([]uuid.UUID, 100)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	suite.Equal(make([]error, len(ids)), limiter.Allow(auth.Principal{Name: "ci"}, ids))
+}
+
+// TestQuotaTestSuite runs the test suite for the quotas of the tokens.
+func TestQuotaTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(QuotaTestSuite))
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"errors"
+	"strings"
+
+	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+	wayv2 "github.com/bavix/vakeel-way/pkg/api/vakeel_way/v2"
+)
+
+// ErrMissingScope is an error that indicates that the caller was not granted the scope required by the call.
+var ErrMissingScope = errors.New("auth: missing scope")
+
+// Scopes granted to the callers by their JWTs.
+const (
+	// ScopeReport allows the caller to report heartbeats.
+	ScopeReport = "report"
+
+	// ScopeRead allows the caller to query and watch the statuses of the services.
+	ScopeRead = "read"
+
+	// ScopeAdmin allows the caller to call the AdminService.
+	ScopeAdmin = "admin"
+)
+
+// RequiredScope returns the scope required to call the gRPC method.
+//
+// Parameters:
+//   - method: The full name of the method, e.g. "/vakeel_way.StateService/Update".
+//
+// Returns:
+//   - The scope, or empty if the method requires none, e.g. the capabilities
+//     and the reflection of the server.
+func RequiredScope(method string) string {
+	switch {
+	case method == way.StateService_Update_FullMethodName, method == way.StateService_UpdateStream_FullMethodName,
+		method == wayv2.StateService_Update_FullMethodName:
+		return ScopeReport
+	case strings.HasPrefix(method, "/"+way.StateService_ServiceDesc.ServiceName+"/"):
+		return ScopeRead
+	case strings.HasPrefix(method, "/"+way.AdminService_ServiceDesc.ServiceName+"/"):
+		return ScopeAdmin
+	default:
+		return ""
+	}
+}
+
+// Public reports whether the gRPC method is served without authentication.
+//
+// The capabilities are public like the health probes, so the clients can
+// discover the features of the server before they are given a token.
+//
+// Parameters:
+//   - method: The full name of the method, e.g. "/vakeel_way.CapabilityService/GetCapabilities".
+func Public(method string) bool {
+	return method == way.CapabilityService_GetCapabilities_FullMethodName
+}
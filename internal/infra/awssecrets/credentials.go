@@ -0,0 +1,215 @@
+package awssecrets
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ErrNoCredentials is an error that indicates that no AWS credentials are found.
+var ErrNoCredentials = errors.New("aws: no credentials found")
+
+const (
+	// containerHost is the host of the credentials endpoint of ECS.
+	containerHost = "http://169.254.170.2"
+
+	// imdsHost is the host of the instance metadata service of EC2.
+	imdsHost = "http://169.254.169.254"
+
+	// maxBody is the maximum size of a response of AWS.
+	maxBody = 1 << 20
+)
+
+// credentials returns the credentials of the process.
+//
+// The credentials are looked up in the order of the AWS SDKs: the environment
+// variables, the web identity token of EKS (IRSA), the credentials endpoint of
+// ECS or the EKS Pod Identity, and the instance metadata service of EC2.
+func (r *Resolver) credentials(ctx context.Context) (Credentials, error) {
+	if id, secret := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"); id != "" && secret != "" {
+		return Credentials{AccessKeyID: id, SecretAccessKey: secret, SessionToken: os.Getenv("AWS_SESSION_TOKEN")}, nil
+	}
+
+	if tokenFile, role := os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"), os.Getenv("AWS_ROLE_ARN"); tokenFile != "" && role != "" {
+		return r.webIdentity(ctx, tokenFile, role)
+	}
+
+	if uri := os.Getenv("AWS_CONTAINER_CREDENTIALS_FULL_URI"); uri != "" {
+		return r.container(ctx, uri)
+	}
+
+	if uri := os.Getenv("AWS_CONTAINER_CREDENTIALS_RELATIVE_URI"); uri != "" {
+		return r.container(ctx, containerHost+uri)
+	}
+
+	creds, err := r.instance(ctx)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("%w: %w", ErrNoCredentials, err)
+	}
+
+	return creds, nil
+}
+
+// temporary is the JSON of the temporary credentials served by ECS and EC2.
+type temporary struct {
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey"`
+	Token           string `json:"Token"`
+}
+
+// webIdentity exchanges the web identity token for the credentials of the role.
+func (r *Resolver) webIdentity(ctx context.Context, tokenFile, role string) (Credentials, error) {
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	session := os.Getenv("AWS_ROLE_SESSION_NAME")
+	if session == "" {
+		session = "vakeel-way"
+	}
+
+	form := url.Values{
+		"Action":           {"AssumeRoleWithWebIdentity"},
+		"Version":          {"2011-06-15"},
+		"RoleArn":          {role},
+		"RoleSessionName":  {session},
+		"WebIdentityToken": {strings.TrimSpace(string(token))},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint("sts", r.region), strings.NewReader(form.Encode()))
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	body, err := r.fetch(req)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	var resp struct {
+		Credentials struct {
+			AccessKeyID     string `xml:"AccessKeyId"`
+			SecretAccessKey string `xml:"SecretAccessKey"`
+			SessionToken    string `xml:"SessionToken"`
+		} `xml:"AssumeRoleWithWebIdentityResult>Credentials"`
+	}
+
+	if err := xml.Unmarshal(body, &resp); err != nil {
+		return Credentials{}, err
+	}
+
+	return Credentials{
+		AccessKeyID:     resp.Credentials.AccessKeyID,
+		SecretAccessKey: resp.Credentials.SecretAccessKey,
+		SessionToken:    resp.Credentials.SessionToken,
+	}, nil
+}
+
+// container returns the credentials served by the credentials endpoint of ECS or the EKS Pod Identity.
+func (r *Resolver) container(ctx context.Context, uri string) (Credentials, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	authorization := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN")
+	if file := os.Getenv("AWS_CONTAINER_AUTHORIZATION_TOKEN_FILE"); file != "" {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return Credentials{}, err
+		}
+
+		authorization = strings.TrimSpace(string(data))
+	}
+
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	return r.temporary(req)
+}
+
+// instance returns the credentials of the role of the EC2 instance, using IMDSv2.
+func (r *Resolver) instance(ctx context.Context) (Credentials, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, imdsHost+"/latest/api/token", nil)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	req.Header.Set("X-Aws-Ec2-Metadata-Token-Ttl-Seconds", "300")
+
+	token, err := r.fetch(req)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	const path = "/latest/meta-data/iam/security-credentials/"
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, imdsHost+path, nil)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	req.Header.Set("X-Aws-Ec2-Metadata-Token", string(token))
+
+	role, err := r.fetch(req)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	name, _, _ := strings.Cut(strings.TrimSpace(string(role)), "\n")
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, imdsHost+path+name, nil)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	req.Header.Set("X-Aws-Ec2-Metadata-Token", string(token))
+
+	return r.temporary(req)
+}
+
+// temporary fetches the JSON of the temporary credentials.
+func (r *Resolver) temporary(req *http.Request) (Credentials, error) {
+	body, err := r.fetch(req)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	var creds temporary
+	if err := json.Unmarshal(body, &creds); err != nil {
+		return Credentials{}, err
+	}
+
+	return Credentials{AccessKeyID: creds.AccessKeyID, SecretAccessKey: creds.SecretAccessKey, SessionToken: creds.Token}, nil
+}
+
+// fetch sends the request and returns the body of the successful response.
+func (r *Resolver) fetch(req *http.Request) ([]byte, error) {
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("aws: %s %s: %s", req.Method, req.URL.Path, resp.Status) //nolint:err113
+	}
+
+	return body, nil
+}
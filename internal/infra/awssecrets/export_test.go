@@ -0,0 +1,4 @@
+package awssecrets
+
+// Sign exposes the Signature Version 4 of AWS to the tests.
+var Sign = sign
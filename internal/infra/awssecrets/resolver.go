@@ -0,0 +1,283 @@
+package awssecrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// SecretsManagerPrefix is the prefix of the references to the secrets of AWS Secrets Manager.
+	//
+	// A reference has the format "aws-sm://<name or ARN>[#<field>]". The field
+	// selects a key of a secret stored as a JSON object.
+	SecretsManagerPrefix = "aws-sm://"
+
+	// SSMPrefix is the prefix of the references to the parameters of the AWS Systems Manager Parameter Store.
+	//
+	// A reference has the format "ssm://<name>", e.g. "ssm://prod/vakeel-way/token"
+	// for the parameter /prod/vakeel-way/token. The SecureString parameters are decrypted.
+	SSMPrefix = "ssm://"
+)
+
+var (
+	// ErrNoRegion is an error that indicates that the region of the AWS services is unknown.
+	ErrNoRegion = errors.New("aws: the region is not configured")
+
+	// ErrReference is an error that indicates that the reference cannot be resolved.
+	ErrReference = errors.New("aws: invalid reference")
+)
+
+// IsReference reports whether the value references a secret of AWS Secrets Manager or a parameter of SSM.
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, SecretsManagerPrefix) || strings.HasPrefix(value, SSMPrefix)
+}
+
+// Option is a function that configures a Resolver.
+type Option func(*Resolver)
+
+// WithRegion returns an Option that sets the region of the AWS services.
+//
+// By default, the region is read from the AWS_REGION or the
+// AWS_DEFAULT_REGION environment variable. The secrets referenced by an ARN
+// are read from the region of the ARN.
+func WithRegion(region string) Option {
+	return func(r *Resolver) {
+		if region != "" {
+			r.region = region
+		}
+	}
+}
+
+// WithEndpoint returns an Option that sends every request to the endpoint, e.g. of LocalStack.
+func WithEndpoint(endpoint string) Option {
+	return func(r *Resolver) {
+		r.custom = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+// WithCredentials returns an Option that sets the credentials instead of looking them up.
+func WithCredentials(creds Credentials) Option {
+	return func(r *Resolver) {
+		r.creds = &creds
+	}
+}
+
+// WithHTTPClient returns an Option that sets the HTTP client used to call AWS.
+func WithHTTPClient(client *http.Client) Option {
+	return func(r *Resolver) {
+		r.client = client
+	}
+}
+
+// Resolver resolves the references to the secrets of AWS Secrets Manager and
+// the parameters of the SSM Parameter Store.
+//
+// The credentials are looked up on the first reference, like the AWS SDKs do,
+// and every reference is read once.
+type Resolver struct {
+	region string
+	custom string
+	client *http.Client
+
+	mu     sync.Mutex
+	creds  *Credentials
+	values map[string]string
+}
+
+// NewResolver creates a new Resolver.
+//
+// Parameters:
+//   - opts: The options of the Resolver.
+//
+// Returns:
+//   - A pointer to a Resolver.
+//
+//nolint:exhaustruct
+func NewResolver(opts ...Option) *Resolver {
+	r := &Resolver{
+		region: os.Getenv("AWS_REGION"),
+		client: http.DefaultClient,
+		values: make(map[string]string),
+	}
+
+	if r.region == "" {
+		r.region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Resolve returns the value referenced by the value.
+//
+// Parameters:
+//   - ctx: The context.Context of the requests.
+//   - value: The reference, or any other value that is returned as is.
+//
+// Returns:
+//   - The referenced value.
+//   - An error if the reference cannot be resolved.
+func (r *Resolver) Resolve(ctx context.Context, value string) (string, error) {
+	if !IsReference(value) {
+		return value, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if resolved, ok := r.values[value]; ok {
+		return resolved, nil
+	}
+
+	var (
+		resolved string
+		err      error
+	)
+
+	if name, ok := strings.CutPrefix(value, SecretsManagerPrefix); ok {
+		resolved, err = r.secret(ctx, name)
+	} else {
+		resolved, err = r.parameter(ctx, strings.TrimPrefix(value, SSMPrefix))
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %w", ErrReference, value, err)
+	}
+
+	r.values[value] = resolved
+
+	return resolved, nil
+}
+
+// secret reads the secret of AWS Secrets Manager.
+func (r *Resolver) secret(ctx context.Context, reference string) (string, error) {
+	name, field, _ := strings.Cut(reference, "#")
+
+	var resp struct {
+		SecretString string `json:"SecretString"`
+	}
+
+	if err := r.call(ctx, "secretsmanager", "secretsmanager.GetSecretValue", arnRegion(name),
+		map[string]any{"SecretId": name}, &resp); err != nil {
+		return "", err
+	}
+
+	if field == "" {
+		return resp.SecretString, nil
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(resp.SecretString), &fields); err != nil {
+		return "", fmt.Errorf("the secret is not a JSON object: %w", err)
+	}
+
+	switch value := fields[field].(type) {
+	case string:
+		return value, nil
+	case nil:
+		return "", fmt.Errorf("the secret has no field %q", field) //nolint:err113
+	default:
+		encoded, err := json.Marshal(value)
+
+		return string(encoded), err
+	}
+}
+
+// parameter reads the parameter of the SSM Parameter Store.
+func (r *Resolver) parameter(ctx context.Context, name string) (string, error) {
+	// The names of the parameters with a hierarchy begin with a slash.
+	if strings.Contains(name, "/") && !strings.HasPrefix(name, "/") && !strings.HasPrefix(name, "arn:") {
+		name = "/" + name
+	}
+
+	var resp struct {
+		Parameter struct {
+			Value string `json:"Value"`
+		} `json:"Parameter"`
+	}
+
+	if err := r.call(ctx, "ssm", "AmazonSSM.GetParameter", arnRegion(name),
+		map[string]any{"Name": name, "WithDecryption": true}, &resp); err != nil {
+		return "", err
+	}
+
+	return resp.Parameter.Value, nil
+}
+
+// call calls the JSON API of the AWS service.
+func (r *Resolver) call(ctx context.Context, service, target, region string, input, output any) error {
+	if region == "" {
+		region = r.region
+	}
+
+	if region == "" {
+		return ErrNoRegion
+	}
+
+	if r.creds == nil {
+		creds, err := r.credentials(ctx)
+		if err != nil {
+			return err
+		}
+
+		r.creds = &creds
+	}
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint(service, region), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", target)
+
+	sign(req, body, *r.creds, region, service, time.Now())
+
+	data, err := r.fetch(req)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, output)
+}
+
+// endpoint returns the URL of the AWS service in the region.
+func (r *Resolver) endpoint(service, region string) string {
+	if r.custom != "" {
+		return r.custom + "/"
+	}
+
+	// The global endpoint of STS serves the callers without a region.
+	if region == "" {
+		return "https://" + service + ".amazonaws.com/"
+	}
+
+	return "https://" + service + "." + region + ".amazonaws.com/"
+}
+
+// arnRegion returns the region of the ARN, or empty if the name is not an ARN.
+func arnRegion(name string) string {
+	// An ARN has the format arn:partition:service:region:account:resource.
+	parts := strings.SplitN(name, ":", 6) //nolint:mnd
+	if len(parts) < 6 || parts[0] != "arn" {
+		return ""
+	}
+
+	return parts[3]
+}
@@ -0,0 +1,128 @@
+package awssecrets_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/awssecrets"
+)
+
+// ResolverTestSuite represents the test suite for the references to the AWS secret stores.
+type ResolverTestSuite struct {
+	suite.Suite
+
+	server   *httptest.Server
+	requests atomic.Int32
+	scopes   chan string
+}
+
+// SetupTest starts a fake endpoint of Secrets Manager and SSM.
+func (suite *ResolverTestSuite) SetupTest() {
+	suite.requests.Store(0)
+	suite.scopes = make(chan string, 10)
+
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.requests.Add(1)
+
+		authorization := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKID/") ||
+			!strings.Contains(authorization, "x-amz-security-token") {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		credential, _, _ := strings.Cut(strings.TrimPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKID/"), ",")
+		suite.scopes <- credential
+
+		var input map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&input)
+
+		switch {
+		case r.Header.Get("X-Amz-Target") == "secretsmanager.GetSecretValue" && input["SecretId"] == "prod/slack":
+			_, _ = w.Write([]byte(`{"SecretString":"{\"token\":\"xoxb-1\",\"retries\":3}"}`))
+		case r.Header.Get("X-Amz-Target") == "secretsmanager.GetSecretValue" &&
+			input["SecretId"] == "arn:aws:secretsmanager:us-west-2:123456789012:secret:instatus":
+			_, _ = w.Write([]byte(`{"SecretString":"instatus-key"}`))
+		case r.Header.Get("X-Amz-Target") == "AmazonSSM.GetParameter" && input["Name"] == "/prod/way/token" &&
+			input["WithDecryption"] == true:
+			_, _ = w.Write([]byte(`{"Parameter":{"Value":"ssm-token"}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+}
+
+// TearDownTest stops the fake endpoint.
+func (suite *ResolverTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+// resolver returns the Resolver of the fake endpoint in the eu-west-1 region.
+func (suite *ResolverTestSuite) resolver() *awssecrets.Resolver {
+	return awssecrets.NewResolver(
+		awssecrets.WithRegion("eu-west-1"),
+		awssecrets.WithEndpoint(suite.server.URL),
+		awssecrets.WithCredentials(awssecrets.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session"}),
+	)
+}
+
+// TestResolver_SecretsManager verifies that the secrets and their JSON fields are resolved.
+func (suite *ResolverTestSuite) TestResolver_SecretsManager() {
+	resolver := suite.resolver()
+
+	value, err := resolver.Resolve(context.Background(), "aws-sm://prod/slack#token")
+	suite.Require().NoError(err)
+	suite.Equal("xoxb-1", value)
+	suite.Contains(<-suite.scopes, "/eu-west-1/secretsmanager/aws4_request")
+
+	value, err = resolver.Resolve(context.Background(), "aws-sm://prod/slack#retries")
+	suite.Require().NoError(err)
+	suite.Equal("3", value)
+	<-suite.scopes
+
+	_, err = resolver.Resolve(context.Background(), "aws-sm://prod/slack#missing")
+	suite.Require().ErrorIs(err, awssecrets.ErrReference)
+	<-suite.scopes
+
+	// The secret referenced by an ARN is read from the region of the ARN.
+	value, err = resolver.Resolve(context.Background(), "aws-sm://arn:aws:secretsmanager:us-west-2:123456789012:secret:instatus")
+	suite.Require().NoError(err)
+	suite.Equal("instatus-key", value)
+	suite.Contains(<-suite.scopes, "/us-west-2/secretsmanager/aws4_request")
+}
+
+// TestResolver_SSM verifies that the parameters are resolved once.
+func (suite *ResolverTestSuite) TestResolver_SSM() {
+	resolver := suite.resolver()
+
+	for range 2 {
+		value, err := resolver.Resolve(context.Background(), "ssm://prod/way/token")
+		suite.Require().NoError(err)
+		suite.Equal("ssm-token", value)
+	}
+
+	suite.Equal(int32(1), suite.requests.Load())
+	suite.Contains(<-suite.scopes, "/eu-west-1/ssm/aws4_request")
+}
+
+// TestResolver_Plain verifies that the other values are returned as is without a request.
+func (suite *ResolverTestSuite) TestResolver_Plain() {
+	value, err := suite.resolver().Resolve(context.Background(), "https://hooks.example.com")
+	suite.Require().NoError(err)
+	suite.Equal("https://hooks.example.com", value)
+	suite.Zero(suite.requests.Load())
+}
+
+// TestResolverTestSuite runs the test suite for the references to the AWS secret stores.
+func TestResolverTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ResolverTestSuite))
+}
@@ -0,0 +1,120 @@
+package awssecrets_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/awssecrets"
+)
+
+// SignerTestSuite represents the test suite for the Signature Version 4 of AWS.
+//
+// The cases are taken from the AWS Signature Version 4 test suite, signed with
+// its credentials for the service "service" in us-east-1 on 2015-08-30.
+type SignerTestSuite struct {
+	suite.Suite
+}
+
+// TestSign verifies the signatures against the AWS test suite.
+func (suite *SignerTestSuite) TestSign() {
+	creds := awssecrets.Credentials{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		SessionToken:    "",
+	}
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		name      string
+		method    string
+		target    string
+		headers   map[string]string
+		body      string
+		signed    string
+		signature string
+	}{
+		{
+			name:      "get-vanilla",
+			method:    http.MethodGet,
+			target:    "/",
+			signed:    "host;x-amz-date",
+			signature: "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			name:      "post-vanilla",
+			method:    http.MethodPost,
+			target:    "/",
+			signed:    "host;x-amz-date",
+			signature: "5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+		},
+		{
+			name:      "get-vanilla-empty-query-key",
+			method:    http.MethodGet,
+			target:    "/?Param1=value1",
+			signed:    "host;x-amz-date",
+			signature: "a67d582fa61cc504c4bae71f336f98b97f1ea3c7a6bfe1b6e45aec72011b9aeb",
+		},
+		{
+			name:      "get-vanilla-query-order-key-case",
+			method:    http.MethodGet,
+			target:    "/?Param2=value2&Param1=value1",
+			signed:    "host;x-amz-date",
+			signature: "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500",
+		},
+		{
+			name:   "get-vanilla-query-unreserved",
+			method: http.MethodGet,
+			target: "/?-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz=" +
+				"-._~0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz",
+			signed:    "host;x-amz-date",
+			signature: "9c3e54bfcdf0b19771a7f523ee5669cdf59bc7cc0884027167c21bb143a40197",
+		},
+		{
+			name:      "post-vanilla-query",
+			method:    http.MethodPost,
+			target:    "/?Param1=value1",
+			signed:    "host;x-amz-date",
+			signature: "28038455d6de14eafc1f9222cf5aa6f1a96197d7deb8263271d420d138af7f11",
+		},
+		{
+			name:      "get-header-value-trim",
+			method:    http.MethodGet,
+			target:    "/",
+			headers:   map[string]string{"My-Header1": " value1", "My-Header2": ` "a   b   c"`},
+			signed:    "host;my-header1;my-header2;x-amz-date",
+			signature: "acc3ed3afb60bb290fc8d2dd0098b9911fcaa05412b367055dee359757a9c736",
+		},
+		{
+			name:      "post-x-www-form-urlencoded",
+			method:    http.MethodPost,
+			target:    "/",
+			headers:   map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			body:      "Param1=value1",
+			signed:    "content-type;host;x-amz-date",
+			signature: "ff11897932ad3f4e8b18135d722051e5ac45fc38421b1da7b9d196a0fe09473a",
+		},
+	} {
+		req, err := http.NewRequest(tc.method, "https://example.amazonaws.com"+tc.target, strings.NewReader(tc.body))
+		suite.Require().NoError(err)
+
+		for name, value := range tc.headers {
+			req.Header.Set(name, value)
+		}
+
+		awssecrets.Sign(req, []byte(tc.body), creds, "us-east-1", "service", now)
+
+		suite.Equal("20150830T123600Z", req.Header.Get("X-Amz-Date"), tc.name)
+		suite.Equal("AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, "+
+			"SignedHeaders="+tc.signed+", Signature="+tc.signature, req.Header.Get("Authorization"), tc.name)
+	}
+}
+
+// TestSignerTestSuite runs the test suite for the Signature Version 4 of AWS.
+func TestSignerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(SignerTestSuite))
+}
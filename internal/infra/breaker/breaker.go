@@ -0,0 +1,331 @@
+package breaker
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// ErrOpen is an error that indicates that the circuit of the target is open.
+var ErrOpen = errors.New("breaker: circuit is open")
+
+// State is the state of the circuit of a target.
+type State uint8
+
+// State constants represent the states of a circuit.
+const (
+	// Closed represents a healthy target: every event is delivered.
+	Closed State = iota
+	// HalfOpen represents a target being probed: a single event is delivered.
+	HalfOpen
+	// Open represents a target that is down: the events are rejected immediately.
+	Open
+)
+
+// String returns the string representation of the state.
+func (s State) String() string {
+	switch s {
+	case Closed:
+		return "closed"
+	case HalfOpen:
+		return "half-open"
+	case Open:
+		return "open"
+	default:
+		return "Undefined"
+	}
+}
+
+// OpenError is an error returned instead of delivering the event while the circuit is open.
+//
+// It wraps ErrOpen. It is not temporary: the target is known to be down, so
+// retrying the event right away is pointless.
+type OpenError struct {
+	// Until is the time the circuit is half-opened to probe the target.
+	Until time.Time
+}
+
+// Error returns the description of the error.
+func (e *OpenError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrOpen, e.Until.Format(time.RFC3339))
+}
+
+// Unwrap returns ErrOpen.
+func (e *OpenError) Unwrap() error {
+	return ErrOpen
+}
+
+// Temporary reports false: the target is known to be down.
+func (e *OpenError) Temporary() bool {
+	return false
+}
+
+// temporary is implemented by the errors that tell whether a retry may succeed,
+// e.g. the errors of the HTTP responses of the targets.
+type temporary interface {
+	Temporary() bool
+}
+
+// Sender represents an interface for delivering events to the targets.
+type Sender interface {
+	// Send delivers the event to the target.
+	Send(ctx context.Context, target entities.Target, event entities.Event) error
+}
+
+// circuit is the state of the circuit of a single target endpoint.
+type circuit struct {
+	// state is the current state of the circuit.
+	state State
+
+	// failures is the number of consecutive failures.
+	failures int
+
+	// until is the time the open circuit is half-opened.
+	until time.Time
+
+	// host and key are the metric labels of the circuit.
+	host, key string
+}
+
+// Breaker wraps a Sender with a circuit breaker per target endpoint.
+//
+// After the configured number of consecutive failures the circuit of the endpoint
+// opens, and the events are rejected immediately with an *OpenError instead of
+// waiting for the timeout of a target that is hard-down. After the cooldown the
+// circuit is half-opened: a single event probes the target, and the circuit is
+// closed on success or opened again on failure.
+type Breaker struct {
+	// next is the Sender the events are delivered with.
+	next Sender
+
+	// threshold is the number of consecutive failures that open the circuit.
+	threshold int
+
+	// cooldown is the time the circuit stays open before it is half-opened.
+	cooldown time.Duration
+
+	// circuits maps the target endpoints to their circuits.
+	circuits map[string]*circuit
+
+	// mu is the mutex used to synchronize access to the circuits.
+	mu sync.Mutex
+
+	// now returns the current time.
+	now func() time.Time
+
+	// state reports the state of the circuits.
+	state *prometheus.GaugeVec
+
+	// rejected counts the events rejected by the open circuits.
+	rejected *prometheus.CounterVec
+
+	// transitions counts the transitions of the circuits.
+	transitions *prometheus.CounterVec
+}
+
+// NewBreaker creates a new instance of the Breaker struct.
+//
+// Parameters:
+//   - next: The Sender the events are delivered with.
+//   - threshold: The number of consecutive failures that open the circuit.
+//   - cooldown: The time the circuit stays open before the target is probed.
+//   - registerer: The prometheus.Registerer used to register the breaker metrics.
+//
+// Returns:
+//   - A pointer to the initialized Breaker.
+func NewBreaker(next Sender, threshold int, cooldown time.Duration, registerer prometheus.Registerer) *Breaker {
+	breaker := &Breaker{
+		next:      next,
+		threshold: threshold,
+		cooldown:  cooldown,
+		circuits:  make(map[string]*circuit),
+		mu:        sync.Mutex{},
+		now:       time.Now,
+		state: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "vakeel",
+			Name:      "breaker_state",
+			Help:      "State of the circuit of a target: 0 closed, 1 half-open, 2 open.",
+		}, []string{"host", "key"}),
+		rejected: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "vakeel",
+			Name:      "breaker_rejected_total",
+			Help:      "Number of events rejected because the circuit of the target was open.",
+		}, []string{"host", "key"}),
+		transitions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "vakeel",
+			Name:      "breaker_transitions_total",
+			Help:      "Number of transitions of the circuits by the new state.",
+		}, []string{"host", "key", "state"}),
+	}
+
+	registerer.MustRegister(breaker.state, breaker.rejected, breaker.transitions)
+
+	return breaker
+}
+
+// Send delivers the event unless the circuit of the target endpoint is open.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - target: The entities.Target to deliver the event to.
+//   - event: The entities.Event to deliver.
+//
+// Returns:
+//   - An *OpenError if the circuit is open or another probe is in flight.
+//   - The error of the wrapped Sender otherwise.
+func (b *Breaker) Send(ctx context.Context, target entities.Target, event entities.Event) error {
+	if b.threshold <= 0 {
+		return b.next.Send(ctx, target, event)
+	}
+
+	cir, err := b.acquire(target)
+	if err != nil {
+		return err
+	}
+
+	err = b.next.Send(ctx, target, event)
+
+	b.release(cir, err)
+
+	return err
+}
+
+// State returns the state of the circuit of the target.
+func (b *Breaker) State(target entities.Target) State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if cir, ok := b.circuits[circuitKey(target)]; ok {
+		return cir.state
+	}
+
+	return Closed
+}
+
+// acquire checks the circuit of the target before the delivery.
+func (b *Breaker) acquire(target entities.Target) (*circuit, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := circuitKey(target)
+
+	cir, ok := b.circuits[key]
+	if !ok {
+		host, label := labels(target, key)
+		cir = &circuit{state: Closed, failures: 0, until: time.Time{}, host: host, key: label}
+		b.circuits[key] = cir
+	}
+
+	switch cir.state {
+	case Closed:
+		return cir, nil
+	case Open:
+		// Probe the target once the cooldown passes.
+		if b.now().Before(cir.until) {
+			b.rejected.WithLabelValues(cir.host, cir.key).Inc()
+
+			return nil, &OpenError{Until: cir.until}
+		}
+
+		b.transition(cir, HalfOpen)
+
+		return cir, nil
+	default:
+		// Another probe is in flight.
+		b.rejected.WithLabelValues(cir.host, cir.key).Inc()
+
+		return nil, &OpenError{Until: b.now().Add(b.cooldown)}
+	}
+}
+
+// release records the result of the delivery.
+func (b *Breaker) release(cir *circuit, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		cir.failures = 0
+
+		if cir.state != Closed {
+			b.transition(cir, Closed)
+		}
+
+		return
+	}
+
+	// The target answered, so the rejections of the event, e.g. a 4xx
+	// response, tell nothing about its availability.
+	var tmp temporary
+	if errors.As(err, &tmp) && !tmp.Temporary() {
+		cir.failures = 0
+
+		if cir.state != Closed {
+			b.transition(cir, Closed)
+		}
+
+		return
+	}
+
+	// The canceled deliveries tell nothing about the target.
+	if errors.Is(err, context.Canceled) {
+		if cir.state == HalfOpen {
+			b.transition(cir, Open)
+		}
+
+		return
+	}
+
+	cir.failures++
+
+	if cir.state == HalfOpen || cir.failures >= b.threshold {
+		cir.until = b.now().Add(b.cooldown)
+		b.transition(cir, Open)
+	}
+}
+
+// transition switches the state of the circuit and updates the metrics.
+// The caller must hold the mutex.
+func (b *Breaker) transition(cir *circuit, state State) {
+	cir.state = state
+
+	b.state.WithLabelValues(cir.host, cir.key).Set(float64(state))
+	b.transitions.WithLabelValues(cir.host, cir.key, state.String()).Inc()
+}
+
+// circuitKey returns the key of the circuit of the target: its type and the
+// endpoint it is delivered to, so the targets sharing the default endpoint of
+// the notifier, e.g. the PagerDuty services, have a circuit per routing key.
+func circuitKey(target entities.Target) string {
+	return strings.Join([]string{
+		target.Type,
+		target.URL,
+		target.RoutingKey,
+		target.Token,
+		target.Page,
+		target.Component,
+	}, "\x00")
+}
+
+// labels returns the metric labels of the circuit without exposing the secrets
+// embedded into the URL or the routing key: the host, or the type of the
+// targets with the default endpoint, and a short hash of the whole key.
+func labels(target entities.Target, key string) (string, string) {
+	sum := sha256.Sum256([]byte(key))
+	hash := hex.EncodeToString(sum[:4])
+
+	if u, err := url.Parse(target.URL); err == nil && u.Host != "" {
+		return u.Hostname(), hash
+	}
+
+	return target.Type, hash
+}
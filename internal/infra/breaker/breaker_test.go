@@ -0,0 +1,168 @@
+package breaker_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/breaker"
+)
+
+// errTarget is the error returned by the failing target.
+var errTarget = errors.New("target is down")
+
+// responseError is the error of a target answering with a status code.
+type responseError struct {
+	temporary bool
+}
+
+func (e responseError) Error() string {
+	return "unexpected response status"
+}
+
+func (e responseError) Temporary() bool {
+	return e.temporary
+}
+
+// countingSender counts the deliveries and fails while err is set.
+type countingSender struct {
+	calls int
+	err   error
+}
+
+func (s *countingSender) Send(context.Context, entities.Target, entities.Event) error {
+	s.calls++
+
+	return s.err
+}
+
+// BreakerTestSuite represents the test suite for the circuit breaker functionality.
+type BreakerTestSuite struct {
+	suite.Suite
+}
+
+// TestBreaker_OpensAndProbes verifies that the circuit opens after the threshold,
+// rejects the deliveries while open and closes after a successful probe.
+func (suite *BreakerTestSuite) TestBreaker_OpensAndProbes() {
+	sender := &countingSender{err: errTarget}
+	cb := breaker.NewBreaker(sender, 2, 20*time.Millisecond, prometheus.NewRegistry())
+	target := entities.Target{Name: "slack", Type: "slack", URL: "https://hooks.example.com/secret"}
+
+	suite.Require().ErrorIs(cb.Send(context.Background(), target, entities.Event{}), errTarget)
+	suite.Require().ErrorIs(cb.Send(context.Background(), target, entities.Event{}), errTarget)
+	suite.Equal(breaker.Open, cb.State(target))
+
+	// The open circuit fails fast with a permanent error.
+	err := cb.Send(context.Background(), target, entities.Event{})
+	suite.Require().ErrorIs(err, breaker.ErrOpen)
+
+	var openErr *breaker.OpenError
+
+	suite.Require().ErrorAs(err, &openErr)
+	suite.False(openErr.Temporary())
+	suite.Equal(2, sender.calls)
+
+	// The failed probe opens the circuit again.
+	time.Sleep(30 * time.Millisecond)
+	suite.Require().ErrorIs(cb.Send(context.Background(), target, entities.Event{}), errTarget)
+	suite.Equal(breaker.Open, cb.State(target))
+	suite.Equal(3, sender.calls)
+
+	// The successful probe closes the circuit.
+	sender.err = nil
+
+	time.Sleep(30 * time.Millisecond)
+	suite.Require().NoError(cb.Send(context.Background(), target, entities.Event{}))
+	suite.Equal(breaker.Closed, cb.State(target))
+}
+
+// TestBreaker_PerURL verifies that the circuits of different URLs are independent.
+func (suite *BreakerTestSuite) TestBreaker_PerURL() {
+	sender := &countingSender{err: errTarget}
+	cb := breaker.NewBreaker(sender, 1, time.Minute, prometheus.NewRegistry())
+	down := entities.Target{Type: "webhook", URL: "https://down.example.com"}
+	up := entities.Target{Type: "webhook", URL: "https://up.example.com"}
+
+	suite.Require().ErrorIs(cb.Send(context.Background(), down, entities.Event{}), errTarget)
+	suite.Equal(breaker.Open, cb.State(down))
+	suite.Equal(breaker.Closed, cb.State(up))
+
+	sender.err = nil
+
+	suite.Require().NoError(cb.Send(context.Background(), up, entities.Event{}))
+	suite.Require().ErrorIs(cb.Send(context.Background(), down, entities.Event{}), breaker.ErrOpen)
+}
+
+// TestBreaker_DefaultEndpoint verifies that the targets with the default
+// endpoint of the notifier have a circuit per routing key.
+func (suite *BreakerTestSuite) TestBreaker_DefaultEndpoint() {
+	sender := &countingSender{err: errTarget}
+	cb := breaker.NewBreaker(sender, 1, time.Minute, prometheus.NewRegistry())
+	down := entities.Target{Type: "pagerduty", RoutingKey: "down"}
+	up := entities.Target{Type: "pagerduty", RoutingKey: "up"}
+	slack := entities.Target{Type: "slack"}
+
+	suite.Require().ErrorIs(cb.Send(context.Background(), down, entities.Event{}), errTarget)
+	suite.Equal(breaker.Open, cb.State(down))
+	suite.Equal(breaker.Closed, cb.State(up))
+	suite.Equal(breaker.Closed, cb.State(slack))
+
+	sender.err = nil
+
+	suite.Require().NoError(cb.Send(context.Background(), up, entities.Event{}))
+	suite.Require().NoError(cb.Send(context.Background(), slack, entities.Event{}))
+	suite.Require().ErrorIs(cb.Send(context.Background(), down, entities.Event{}), breaker.ErrOpen)
+}
+
+// TestBreaker_Rejections verifies that only the temporary errors open the
+// circuit, and a target rejecting the events is still probed successfully.
+func (suite *BreakerTestSuite) TestBreaker_Rejections() {
+	sender := &countingSender{err: responseError{temporary: false}}
+	cb := breaker.NewBreaker(sender, 2, 20*time.Millisecond, prometheus.NewRegistry())
+	target := entities.Target{Type: "instatus", URL: "https://instatus.example.com/hook"}
+
+	// The 4xx responses never open the circuit.
+	for range 5 {
+		suite.Require().ErrorAs(cb.Send(context.Background(), target, entities.Event{}), new(responseError))
+	}
+
+	suite.Equal(breaker.Closed, cb.State(target))
+	suite.Equal(5, sender.calls)
+
+	// The 5xx responses do.
+	sender.err = responseError{temporary: true}
+
+	suite.Require().Error(cb.Send(context.Background(), target, entities.Event{}))
+	suite.Require().Error(cb.Send(context.Background(), target, entities.Event{}))
+	suite.Equal(breaker.Open, cb.State(target))
+
+	// A probe answered with a 4xx response proves the target is up.
+	sender.err = responseError{temporary: false}
+
+	time.Sleep(30 * time.Millisecond)
+	suite.Require().Error(cb.Send(context.Background(), target, entities.Event{}))
+	suite.Equal(breaker.Closed, cb.State(target))
+}
+
+// TestBreaker_Disabled verifies that a zero threshold disables the breaker.
+func (suite *BreakerTestSuite) TestBreaker_Disabled() {
+	sender := &countingSender{err: errTarget}
+	cb := breaker.NewBreaker(sender, 0, time.Minute, prometheus.NewRegistry())
+
+	for range 10 {
+		suite.Require().ErrorIs(cb.Send(context.Background(), entities.Target{Type: "slack"}, entities.Event{}), errTarget)
+	}
+
+	suite.Equal(10, sender.calls)
+}
+
+// TestBreakerTestSuite runs the test suite for the circuit breaker functionality.
+func TestBreakerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(BreakerTestSuite))
+}
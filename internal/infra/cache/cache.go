@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"container/heap"
 	"sync"
 	"time"
 )
@@ -39,7 +40,11 @@ type Cache[K comparable, V any] struct {
 	// items is a map that stores the key-value pairs of the cache. The keys are the strings that
 	// are used to identify the items in the cache, and the values are pointers to item structs,
 	// which contain the value associated with the key and the time-to-live (TTL) of the item.
-	items map[K]*item[V]
+	items map[K]*item[K, V]
+
+	// expirations is the min-heap of the items ordered by their TTL. The
+	// cleanup goroutine pops only the items that are due.
+	expirations expirations[K, V]
 
 	// clock is an interface that provides the current time. It is used to get the current time
 	// and calculate the expiration time of the items in the cache. The current time is used to
@@ -53,6 +58,12 @@ type Cache[K comparable, V any] struct {
 	// For example, the onEvict parameter can be used to log the eviction of an item.
 	onEvict Fn[K, V]
 
+	// evictWorkers is the number of the goroutines calling the onEvict function.
+	evictWorkers int
+
+	// evictor calls the onEvict function outside the lock of the cache.
+	evictor *evictor[K, V]
+
 	// evictDuration is the duration after which an item is evicted from the cache. It specifies
 	// the time interval after which an item is considered expired and is evicted from the
 	// cache. The evictDuration is used to calculate the expiration time of the items in the cache.
@@ -64,13 +75,33 @@ type Cache[K comparable, V any] struct {
 	// cache from concurrent modifications. The mu is used to ensure that the cache is accessed
 	// and modified in a thread-safe way.
 	mu sync.RWMutex
+
+	// done is closed when the cache is closed. It stops the cleanup goroutine.
+	done chan struct{}
+
+	// closed reports whether the cache is closed. The onEvict function is not
+	// called for a closed cache.
+	closed bool
+
+	// path is the path to the snapshot file. If it is empty, the cache is kept
+	// in memory only.
+	path string
+
+	// snapshotInterval is the interval between the snapshots.
+	snapshotInterval time.Duration
+
+	// onError is called when the cache cannot be restored or persisted.
+	onError func(error)
 }
 
 // item is a struct that represents an item stored in the cache.
 //
 // It contains the key used to identify the item in the cache, the value associated with the key,
 // and the time-to-live (TTL) of the item.
-type item[V any] struct {
+type item[K comparable, V any] struct {
+	// Key is the key used to identify the item in the cache.
+	Key K
+
 	// Value is the value associated with the key.
 	//
 	// It is the value that is stored in the cache and can be retrieved using the key.
@@ -82,6 +113,9 @@ type item[V any] struct {
 	// It represents the duration after which the item should be removed from the cache.
 	// The TTL is calculated based on the current time and the evictDuration parameter of the Cache struct.
 	TTL time.Time
+
+	// index is the position of the item in the heap of the expirations.
+	index int
 }
 
 // NewCacheWithOptions creates a new Cache instance with the specified minimum capacity and optional configurations.
@@ -102,13 +136,21 @@ func NewCache[K comparable, V any](minimumCapacity int, options ...Option[K, V])
 	// Initialize a new Cache instance with the specified minimum capacity and default values.
 	cache := &Cache[K, V]{
 		// Create a map to store the items in the cache. The map has a minimum capacity specified by the minimumCapacity parameter.
-		items: make(map[K]*item[V], minimumCapacity),
+		items: make(map[K]*item[K, V], minimumCapacity),
+		// Create the heap of the expirations with the same capacity.
+		expirations: make(expirations[K, V], 0, minimumCapacity),
 		// Use the default clock implementation.
 		clock: clock{},
 		// Set the default onEvict function to do nothing.
 		onEvict: func(K, V) {},
+		// Call the onEvict function from the default number of the goroutines.
+		evictWorkers: DefaultEvictWorkers,
 		// Set the default evict duration to 1 minute.
 		evictDuration: time.Minute,
+		// Create the channel that stops the cleanup goroutine.
+		done: make(chan struct{}),
+		// Ignore the persistence errors by default.
+		onError: func(error) {},
 	}
 
 	// Apply any optional configurations provided through the options parameter.
@@ -117,6 +159,16 @@ func NewCache[K comparable, V any](minimumCapacity int, options ...Option[K, V])
 		option(cache)
 	}
 
+	// Start the goroutines calling the onEvict function.
+	cache.evictor = newEvictor[K, V](cache.evictWorkers, cache.done)
+
+	// Restore the items persisted before the restart.
+	if cache.path != "" {
+		if err := cache.restore(); err != nil {
+			cache.onError(err)
+		}
+	}
+
 	// Start a cleanup goroutine for the cache.
 	// The cleanup goroutine periodically removes expired items from the cache.
 	go cache.cleanup()
@@ -175,7 +227,9 @@ func (c *Cache[K, V]) Add(key K, value V, ttl time.Duration) {
 
 	// Create a new item with the given key, value, and TTL.
 	// The item struct contains the value associated with the key and the time-to-live (TTL) of the item.
-	item := &item[V]{
+	item := &item[K, V]{
+		// The key used to identify the item.
+		Key: key,
 		// The value associated with the key.
 		Value: value, // Associate the value with the key.
 		// The time-to-live (TTL) of the item. The item will be automatically removed
@@ -185,7 +239,222 @@ func (c *Cache[K, V]) Add(key K, value V, ttl time.Duration) {
 
 	// Add the new item to the cache with the given key.
 	// If the key already exists in the cache, its value and TTL are updated.
-	c.items[key] = item // Add or update the item in the cache.
+	c.put(item) // Add or update the item in the cache.
+}
+
+// GetOrAdd retrieves the value associated with the given key, or adds the value
+// returned by the factory if the key is not in the cache.
+//
+// The lookup and the addition are done under the same lock, so concurrent
+// callers never initialize the same key twice. The TTL of an existing item is
+// not changed.
+//
+// Parameters:
+//   - key: The key used to identify the item in the cache.
+//   - factory: The function returning the value to add. It is called under the
+//     lock of the cache, so it must not use the cache.
+//   - ttl: The time-to-live (TTL) of the added item.
+//
+// Returns:
+//   - value: A pointer to the value associated with the key.
+//   - loaded: A boolean indicating whether the key was already in the cache.
+func (c *Cache[K, V]) GetOrAdd(key K, factory func() V, ttl time.Duration) (*V, bool) {
+	// Lock the cache for write access.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Return the existing value if the key exists in the cache.
+	if v, ok := c.items[key]; ok {
+		return &v.Value, true
+	}
+
+	// Add the new value to the cache.
+	item := &item[K, V]{Key: key, Value: factory(), TTL: c.clock.Now().Add(ttl)}
+	c.put(item)
+
+	return &item.Value, false
+}
+
+// Touch prolongs the life of the item with the given key without changing its value.
+//
+// Touching a key that is not in the cache is a no-op.
+//
+// Parameters:
+//   - key: The key used to identify the item in the cache.
+//   - ttl: The new time-to-live (TTL) of the item, counted from now.
+//
+// Returns:
+//   - A boolean indicating whether the key was found in the cache.
+func (c *Cache[K, V]) Touch(key K, ttl time.Duration) bool {
+	// Lock the cache for write access.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Check if the key exists in the cache.
+	item, ok := c.items[key]
+	if !ok {
+		return false
+	}
+
+	// Reset the time-to-live (TTL) of the item and restore the order of the heap.
+	item.TTL = c.clock.Now().Add(ttl)
+	heap.Fix(&c.expirations, item.index)
+
+	return true
+}
+
+// Expiry returns the time the item with the given key expires at.
+//
+// Parameters:
+//   - key: The key used to identify the item in the cache.
+//
+// Returns:
+//   - The expiration time of the item, or zero if the key is not in the cache.
+//   - A boolean indicating whether the key was found in the cache.
+func (c *Cache[K, V]) Expiry(key K) (time.Time, bool) {
+	// Lock the cache for read access.
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if item, ok := c.items[key]; ok {
+		return item.TTL, true
+	}
+
+	return time.Time{}, false
+}
+
+// Delete removes the item with the given key from the cache.
+//
+// The item is removed explicitly, so the onEvict function is not called.
+// Deleting a key that is not in the cache is a no-op.
+//
+// Parameters:
+//   - key: The key used to identify the item in the cache.
+//
+// Returns:
+//   - A boolean indicating whether the key was found in the cache.
+func (c *Cache[K, V]) Delete(key K) bool {
+	// Lock the cache for write access.
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// Check if the key exists in the cache.
+	item, ok := c.items[key]
+
+	// Remove the item from the cache.
+	if ok {
+		c.remove(item)
+	}
+
+	return ok
+}
+
+// Contains reports whether the item with the given key is in the cache.
+//
+// Like Get, it reports the items that have expired but have not been evicted yet.
+//
+// Parameters:
+//   - key: The key used to identify the item in the cache.
+//
+// Returns:
+//   - A boolean indicating whether the key was found in the cache.
+func (c *Cache[K, V]) Contains(key K) bool {
+	// Lock the cache for read access.
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	_, ok := c.items[key]
+
+	return ok
+}
+
+// Len returns the number of items in the cache.
+//
+// The items that have expired but have not been evicted yet are counted.
+//
+// Returns:
+//   - The number of items in the cache.
+func (c *Cache[K, V]) Len() int {
+	// Lock the cache for read access.
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.items)
+}
+
+// Range calls fn for each item in the cache until fn returns false.
+//
+// The items are iterated over a snapshot taken under the read lock, so fn may
+// use the cache. Like Get, it visits the items that have expired but have not
+// been evicted yet. The order of the items is not specified.
+//
+// Parameters:
+//   - fn: The function called with the key and the value of each item.
+func (c *Cache[K, V]) Range(fn func(key K, value V) bool) {
+	// Take a snapshot of the items under the read lock.
+	c.mu.RLock()
+
+	keys := make([]K, 0, len(c.items))
+	values := make([]V, 0, len(c.items))
+
+	for key, item := range c.items {
+		keys = append(keys, key)
+		values = append(values, item.Value)
+	}
+
+	c.mu.RUnlock()
+
+	// Call fn without holding the lock.
+	for i := range keys {
+		if !fn(keys[i], values[i]) {
+			return
+		}
+	}
+}
+
+// Keys returns the keys of the items in the cache.
+//
+// The order of the keys is not specified.
+//
+// Returns:
+//   - The keys of the items in the cache.
+func (c *Cache[K, V]) Keys() []K {
+	// Lock the cache for read access.
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	keys := make([]K, 0, len(c.items))
+	for key := range c.items {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// Close stops the cleanup goroutine of the cache.
+//
+// After the cache is closed, the expired items are no longer evicted and the
+// onEvict function is not called anymore, the queued evictions are dropped. The items can still be read and
+// written. If the persistence is enabled, the items are written to the
+// snapshot file. Closing a closed cache is a no-op.
+func (c *Cache[K, V]) Close() {
+	// Lock the cache for write access.
+	c.mu.Lock()
+
+	// Check if the cache is already closed.
+	if c.closed {
+		c.mu.Unlock()
+
+		return
+	}
+
+	// Mark the cache as closed and stop the cleanup goroutine.
+	c.closed = true
+	close(c.done)
+	c.mu.Unlock()
+
+	// Write the last snapshot.
+	c.persist()
 }
 
 // OnEvict sets a callback function that will be called when an item is evicted
@@ -239,12 +508,12 @@ func (c *Cache[K, V]) OnEvict(fn func(K, V)) {
 // The removeExpiredItems function is called periodically to remove the expired
 // items from the cache.
 //
-// The cleanup goroutine runs indefinitely until the program is terminated. It
-// is started when the Cache instance is created.
+// The cleanup goroutine runs until the cache is closed. It is started when the
+// Cache instance is created.
 //
 // cleanup is a goroutine, meaning it runs concurrently with other goroutines in
 // the program. It is started when the Cache instance is created and it runs until
-// the program is terminated.
+// the Close method is called.
 func (c *Cache[K, V]) cleanup() {
 	// Create a ticker that ticks every evictDuration.
 	// The ticker is used to schedule the cleanup process.
@@ -253,51 +522,76 @@ func (c *Cache[K, V]) cleanup() {
 	// Ensure that the ticker is stopped even if the function returns early.
 	defer ticker.Stop()
 
-	// Run the cleanup loop until the program is terminated.
-	// The loop runs indefinitely until the program is terminated.
-	for range ticker.C {
-		// Remove expired items from the cache.
-		// This function is called periodically by the cleanup goroutine.
-		c.removeExpiredItems()
+	// Create a ticker for the snapshots. It never ticks if the persistence is
+	// disabled or the snapshots are written on close only.
+	snapshots := make(<-chan time.Time)
+
+	if c.path != "" && c.snapshotInterval > 0 {
+		snapshotTicker := time.NewTicker(c.snapshotInterval)
+		defer snapshotTicker.Stop()
+
+		snapshots = snapshotTicker.C
+	}
+
+	// Run the cleanup loop until the cache is closed.
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			// Remove expired items from the cache.
+			// This function is called periodically by the cleanup goroutine.
+			c.removeExpiredItems()
+		case <-snapshots:
+			// Write the non-expired items to the snapshot file.
+			c.persist()
+		}
 	}
 }
 
 // removeExpiredItems removes the expired items from the cache.
 //
 // This function is called periodically by the cleanup goroutine to remove the expired items from the cache.
-// It locks the cache for write access and then iterates over each item in the cache. For each item, it checks
-// if the item has expired by comparing the item's TTL (time-to-live) with the current time. If an item has
-// expired, it calls the onEvict function with the key of the expired item. The onEvict function is a
-// callback function that is called when an item is evicted from the cache. It is used to perform an action
-// when an item is evicted, such as logging the eviction of an item. Finally, it removes the expired item from
-// the cache.
+// It locks the cache for write access and pops the items from the heap of the expirations while the
+// earliest one has expired, so only the due items are touched and the lock is held briefly. The onEvict
+// function is called for every expired item by the workers of the evictor after the lock is released,
+// so the onEvict function may take long and may add the item back.
 func (c *Cache[K, V]) removeExpiredItems() {
 	// Lock the cache for write access.
 	c.mu.Lock()
-	defer c.mu.Unlock()
 
-	// Iterate over each item in the cache.
-	for k := range c.items {
-		// Get the item from the cache. If the item is nil, skip it.
-		item := c.items[k]
-		if item == nil {
-			continue
-		}
+	// Do not evict anything once the cache is closed.
+	if c.closed {
+		c.mu.Unlock()
 
-		// Check if the item has expired.
-		// An item is considered expired if its TTL (time-to-live) is before the current time.
-		if item.TTL.Before(c.clock.Now()) {
-			// Call the onEvict function with the key of the expired item.
-			// The onEvict function is a callback function that is called when an item is evicted from the cache.
-			// It is used to perform an action when an item is evicted, such as logging the eviction of an item.
-			if c.onEvict != nil {
-				c.onEvict(k, item.Value)
-			}
-
-			// Remove the expired item from the cache.
-			// The delete function removes the item with the given key from the cache.
-			// It does not return any value.
-			delete(c.items, k)
-		}
+		return
+	}
+
+	now := c.clock.Now()
+	onEvict := c.onEvict
+
+	var expired []*item[K, V]
+
+	// Pop the items while the earliest one has expired.
+	// An item is considered expired if its TTL (time-to-live) is before the current time.
+	for len(c.expirations) > 0 && c.expirations[0].TTL.Before(now) {
+		it := c.expirations[0]
+
+		// Remove the expired item from the cache.
+		c.remove(it)
+
+		expired = append(expired, it)
+	}
+
+	c.mu.Unlock()
+
+	// Call the onEvict function with the key of every expired item in the order they expired.
+	// It is used to perform an action when an item is evicted, such as logging the eviction of an item.
+	if onEvict == nil {
+		return
+	}
+
+	for _, it := range expired {
+		c.evictor.dispatch(it.Key, it.Value, onEvict)
 	}
 }
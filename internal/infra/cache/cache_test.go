@@ -1,6 +1,7 @@
 package cache_test
 
 import (
+	"path/filepath"
 	"slices"
 	"sync"
 	"sync/atomic"
@@ -43,9 +44,12 @@ func (suite *CacheTestSuite) SetupTest() {
 // The method is part of the suite.Suite interface from the testify package.
 // It is used to clean up resources after a test has finished running.
 //
-// In this case, the method is used to set the cache instance to nil to prevent
-// memory leaks.
+// In this case, the method is used to close the cache and set the cache instance
+// to nil to prevent memory leaks.
 func (suite *CacheTestSuite) AfterTest(_, _ string) {
+	// Stop the cleanup goroutine of the cache.
+	suite.cache.Close()
+
 	// Set the cache instance to nil to prevent memory leaks.
 	suite.cache = nil
 }
@@ -81,6 +85,38 @@ func (suite *CacheTestSuite) TestCache_Get() {
 	suite.Equal("hello", *item, "Retrieved item has incorrect value")
 }
 
+// TestCache_Delete tests the Delete, Contains and Len methods of the Cache struct.
+//
+// The test verifies that an explicitly deleted item is removed from the cache
+// without calling the onEvict function.
+func (suite *CacheTestSuite) TestCache_Delete() {
+	var evicted atomic.Int32
+
+	suite.cache.OnEvict(func(int, string) {
+		evicted.Add(1)
+	})
+
+	suite.cache.Add(1, "hello", time.Second)
+	suite.cache.Add(2, "world", time.Second)
+
+	suite.Equal(2, suite.cache.Len())
+	suite.True(suite.cache.Contains(1))
+
+	// Delete the item and check that it is gone.
+	suite.True(suite.cache.Delete(1))
+	suite.False(suite.cache.Contains(1))
+	suite.Equal(1, suite.cache.Len())
+
+	// Deleting the missing key is a no-op.
+	suite.False(suite.cache.Delete(1))
+	suite.Equal(1, suite.cache.Len())
+
+	item, ok := suite.cache.Get(2)
+	suite.True(ok)
+	suite.Equal("world", *item)
+	suite.Zero(evicted.Load())
+}
+
 // TestCache_Expire tests the expiration of items in the cache.
 // It demonstrates that items added to the cache with a time-to-live (TTL)
 // expire after the specified duration.
@@ -260,6 +296,241 @@ func (suite *CacheTestSuite) TestCache_OnEvict() {
 	suite.Equal(int64(4), onEvictCount.Load())
 }
 
+// TestCache_GetOrAdd tests that the factory is called only for the missing keys.
+//
+// The test calls GetOrAdd concurrently for the same key and checks that the
+// factory was called once and every caller got the same value.
+func (suite *CacheTestSuite) TestCache_GetOrAdd() {
+	// Initialize an atomic counter to track the number of factory calls.
+	var calls atomic.Int64
+
+	factory := func() string {
+		calls.Add(1)
+
+		return "hello"
+	}
+
+	var (
+		wg     sync.WaitGroup
+		loaded atomic.Int64
+	)
+
+	for range 10 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			value, ok := suite.cache.GetOrAdd(1, factory, time.Second)
+			suite.Equal("hello", *value)
+
+			if ok {
+				loaded.Add(1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	// The value was created once and loaded by the other callers.
+	suite.Equal(int64(1), calls.Load())
+	suite.Equal(int64(9), loaded.Load())
+
+	// The existing value is not replaced.
+	value, ok := suite.cache.GetOrAdd(1, func() string { return "world" }, time.Second)
+	suite.True(ok)
+	suite.Equal("hello", *value)
+}
+
+// TestCache_Touch tests that touching an item prolongs its life without changing its value.
+func (suite *CacheTestSuite) TestCache_Touch() {
+	// Touching a missing key is a no-op.
+	suite.False(suite.cache.Touch(1, time.Second))
+
+	// Add an item that expires soon and prolong it.
+	suite.cache.Add(1, "hello", 10*time.Millisecond)
+	suite.True(suite.cache.Touch(1, time.Second))
+
+	// Wait for the original TTL to expire.
+	time.Sleep(50 * time.Millisecond)
+
+	value, ok := suite.cache.Get(1)
+	suite.True(ok)
+	suite.Equal("hello", *value)
+}
+
+// TestCache_Range tests that every item is visited until the callback stops the iteration.
+//
+// The callback modifies the cache to check that it is called without the lock.
+func (suite *CacheTestSuite) TestCache_Range() {
+	for key := range 5 {
+		suite.cache.Add(key, "hello", time.Second)
+	}
+
+	keys := suite.cache.Keys()
+	slices.Sort(keys)
+	suite.Equal([]int{0, 1, 2, 3, 4}, keys)
+
+	// Visit every item and delete it.
+	visited := 0
+	suite.cache.Range(func(key int, value string) bool {
+		visited++
+
+		suite.Equal("hello", value)
+		suite.True(suite.cache.Delete(key))
+
+		return true
+	})
+
+	suite.Equal(5, visited)
+	suite.Zero(suite.cache.Len())
+
+	// Stop after the first item.
+	suite.cache.Add(1, "hello", time.Second)
+	suite.cache.Add(2, "world", time.Second)
+
+	visited = 0
+	suite.cache.Range(func(int, string) bool {
+		visited++
+
+		return false
+	})
+
+	suite.Equal(1, visited)
+}
+
+// TestCache_Persistence tests that the items survive the restart of the cache.
+//
+// The test adds an item to a persistent cache, closes it and checks that a new
+// cache with the same snapshot file restores the item with its TTL.
+func (suite *CacheTestSuite) TestCache_Persistence() {
+	path := filepath.Join(suite.T().TempDir(), "cache.json")
+
+	first := cache.NewCache(10, cache.WithPersistence[int, string](path, time.Hour))
+	first.Add(1, "hello", time.Hour)
+	first.Add(2, "expired", -time.Second)
+	first.Close()
+
+	var errs []error
+
+	second := cache.NewCache(
+		10,
+		cache.WithPersistence[int, string](path, time.Hour),
+		cache.WithOnError[int, string](func(err error) { errs = append(errs, err) }),
+	)
+	defer second.Close()
+
+	suite.Empty(errs)
+	suite.Equal([]int{1}, second.Keys())
+
+	value, ok := second.Get(1)
+	suite.True(ok)
+	suite.Equal("hello", *value)
+}
+
+// TestCache_ExpirationOrder tests that only the due items are evicted and that
+// the updated TTLs are taken into account. The callbacks of different keys run
+// concurrently, so their order is not checked.
+func (suite *CacheTestSuite) TestCache_ExpirationOrder() {
+	var (
+		mu      sync.Mutex
+		evicted []int
+	)
+
+	suite.cache.OnEvict(func(k int, _ string) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		evicted = append(evicted, k)
+	})
+
+	// Add the items out of order.
+	suite.cache.Add(3, "three", 3*time.Millisecond)
+	suite.cache.Add(1, "one", time.Millisecond)
+	suite.cache.Add(2, "two", 2*time.Millisecond)
+	suite.cache.Add(4, "four", time.Millisecond)
+
+	// Prolong the life of the item 4 and replace the item 2.
+	suite.True(suite.cache.Touch(4, time.Hour))
+	suite.cache.Add(2, "two", time.Hour)
+
+	// Wait for a short period of time to allow the items to expire.
+	time.Sleep(50 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	suite.ElementsMatch([]int{1, 3}, evicted)
+	suite.Equal(2, suite.cache.Len())
+}
+
+// TestCache_EvictOutsideLock tests that the OnEvict callback function runs
+// outside the lock of the cache.
+//
+// The callback function reads the cache and adds the evicted item back, which
+// would deadlock if it was called under the lock. A slow callback function
+// must not block the other operations of the cache.
+func (suite *CacheTestSuite) TestCache_EvictOutsideLock() {
+	evicted := make(chan int, 1)
+	release := make(chan struct{})
+
+	suite.cache.OnEvict(func(k int, v string) {
+		// Read the cache and add the item back.
+		suite.cache.Add(k, v, time.Hour)
+		suite.False(suite.cache.Contains(k + 1))
+
+		evicted <- k
+
+		// Block the worker until the test is done.
+		<-release
+	})
+
+	suite.cache.Add(1, "hello", time.Millisecond)
+
+	select {
+	case k := <-evicted:
+		suite.Equal(1, k)
+	case <-time.After(time.Second):
+		suite.FailNow("OnEvict callback function was not called")
+	}
+
+	// The cache is usable while the callback function is blocked.
+	suite.cache.Add(5, "world", time.Hour)
+	suite.True(suite.cache.Contains(1))
+	suite.Equal(2, suite.cache.Len())
+
+	close(release)
+}
+
+// TestCache_Close tests that the expired items are no longer evicted once the
+// cache is closed.
+//
+// The test closes the cache, adds an item with a short TTL and waits for it to
+// expire. The OnEvict callback function must not be called, and the item must
+// still be in the cache. Closing the cache twice must not panic.
+func (suite *CacheTestSuite) TestCache_Close() {
+	// Initialize an atomic counter to track the number of evicted items.
+	var onEvictCount atomic.Int64
+
+	suite.cache.OnEvict(func(int, string) {
+		onEvictCount.Add(1)
+	})
+
+	// Close the cache and add an item that expires immediately.
+	suite.cache.Close()
+	suite.cache.Add(1, "hello", 100*time.Microsecond)
+
+	// Wait for a short period of time to allow the item to expire.
+	time.Sleep(50 * time.Millisecond)
+
+	// The item is not evicted.
+	suite.Zero(onEvictCount.Load())
+	suite.True(suite.cache.Contains(1))
+
+	// Closing a closed cache is a no-op.
+	suite.NotPanics(suite.cache.Close)
+}
+
 // TestCache_ProlongLife tests the functionality of prolonging the life of cache items.
 //
 // This test adds two items to the cache with different time-to-live (TTL) durations.
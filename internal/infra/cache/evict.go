@@ -0,0 +1,147 @@
+package cache
+
+import "sync"
+
+const (
+	// DefaultEvictWorkers is the default number of the goroutines calling the
+	// onEvict function.
+	DefaultEvictWorkers = 4
+
+	// evictQueueSize is the number of the evictions queued per worker before
+	// the cleanup goroutine waits for the worker.
+	evictQueueSize = 64
+)
+
+// eviction is an expired item waiting for the onEvict function.
+type eviction[K comparable, V any] struct {
+	key   K
+	value V
+	fn    Fn[K, V]
+}
+
+// pending tracks the evictions of a key that are queued or running.
+type pending struct {
+	worker int
+	count  int
+}
+
+// evictor calls the onEvict functions outside the lock of the cache.
+//
+// The evictions are dispatched to a bounded pool of workers, so a slow onEvict
+// function (e.g. a notification sent over HTTP) does not block the reads and
+// writes of the cache. The evictions of a key stick to the worker that already
+// handles it, so they are delivered in the order the key expired.
+type evictor[K comparable, V any] struct {
+	// queues are the bounded queues of the workers.
+	queues []chan eviction[K, V]
+
+	// done is closed when the cache is closed. It stops the workers and drops
+	// the queued evictions.
+	done <-chan struct{}
+
+	// mu protects inflight and next.
+	mu sync.Mutex
+
+	// inflight maps the keys to the worker that handles their evictions.
+	inflight map[K]*pending
+
+	// next is the worker that gets the next key without queued evictions.
+	next int
+}
+
+// newEvictor creates an evictor and starts its workers.
+//
+// Parameters:
+//   - workers: The number of the workers. It is at least one.
+//   - done: The channel closed when the cache is closed.
+//
+// Returns:
+//   - A pointer to the started evictor.
+//
+//nolint:exhaustruct
+func newEvictor[K comparable, V any](workers int, done <-chan struct{}) *evictor[K, V] {
+	e := &evictor[K, V]{
+		queues:   make([]chan eviction[K, V], max(workers, 1)),
+		done:     done,
+		inflight: make(map[K]*pending),
+	}
+
+	for i := range e.queues {
+		e.queues[i] = make(chan eviction[K, V], evictQueueSize)
+
+		go e.work(e.queues[i])
+	}
+
+	return e
+}
+
+// dispatch queues the eviction of the key.
+//
+// It blocks while the queue of the worker is full, so the cleanup goroutine is
+// slowed down instead of the memory growing without bounds. The eviction is
+// dropped if the cache is closed in the meantime.
+//
+// Parameters:
+//   - key: The key of the expired item.
+//   - value: The value of the expired item.
+//   - fn: The onEvict function to call.
+func (e *evictor[K, V]) dispatch(key K, value V, fn Fn[K, V]) {
+	e.mu.Lock()
+
+	// Keep the evictions of the key on the same worker to preserve their order.
+	p, ok := e.inflight[key]
+	if !ok {
+		p = &pending{worker: e.next}
+		e.inflight[key] = p
+		e.next = (e.next + 1) % len(e.queues)
+	}
+
+	p.count++
+	queue := e.queues[p.worker]
+	e.mu.Unlock()
+
+	select {
+	case queue <- eviction[K, V]{key: key, value: value, fn: fn}:
+	case <-e.done:
+		e.release(key)
+	}
+}
+
+// work calls the onEvict function for the queued evictions until the cache is closed.
+//
+// Parameters:
+//   - queue: The queue of the worker.
+func (e *evictor[K, V]) work(queue <-chan eviction[K, V]) {
+	for {
+		select {
+		case <-e.done:
+			return
+		case ev := <-queue:
+			// Drop the eviction if the cache was closed while it was queued.
+			select {
+			case <-e.done:
+				return
+			default:
+			}
+
+			ev.fn(ev.key, ev.value)
+			e.release(ev.key)
+		}
+	}
+}
+
+// release marks an eviction of the key as handled, so the next eviction of
+// the key may go to another worker.
+//
+// Parameters:
+//   - key: The key of the handled eviction.
+func (e *evictor[K, V]) release(key K) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if p, ok := e.inflight[key]; ok {
+		if p.count--; p.count <= 0 {
+			delete(e.inflight, key)
+		}
+	}
+}
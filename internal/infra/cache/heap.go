@@ -0,0 +1,60 @@
+package cache
+
+import "container/heap"
+
+// expirations is a min-heap of the items ordered by their TTL.
+//
+// It lets the cleanup goroutine touch only the items that are due instead of
+// scanning the whole cache on every tick. Every item knows its position in the
+// heap, so an update of the TTL is O(log n).
+type expirations[K comparable, V any] []*item[K, V]
+
+// Len returns the number of the items in the heap.
+func (h expirations[K, V]) Len() int { return len(h) }
+
+// Less reports whether the item i expires before the item j.
+func (h expirations[K, V]) Less(i, j int) bool { return h[i].TTL.Before(h[j].TTL) }
+
+// Swap swaps the items i and j and updates their positions.
+func (h expirations[K, V]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+// Push appends the item to the heap. It is called by container/heap.
+func (h *expirations[K, V]) Push(x any) {
+	it, _ := x.(*item[K, V])
+	it.index = len(*h)
+	*h = append(*h, it)
+}
+
+// Pop removes the last item of the heap. It is called by container/heap.
+func (h *expirations[K, V]) Pop() any {
+	old := *h
+	it := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+
+	return it
+}
+
+// put adds the item to the cache or replaces the item with the same key.
+// The caller must hold the write lock.
+func (c *Cache[K, V]) put(it *item[K, V]) {
+	if old, ok := c.items[it.Key]; ok {
+		// Take the place of the old item in the heap.
+		it.index = old.index
+		c.expirations[it.index] = it
+		heap.Fix(&c.expirations, it.index)
+	} else {
+		heap.Push(&c.expirations, it)
+	}
+
+	c.items[it.Key] = it
+}
+
+// remove removes the item from the cache. The caller must hold the write lock.
+func (c *Cache[K, V]) remove(it *item[K, V]) {
+	heap.Remove(&c.expirations, it.index)
+	delete(c.items, it.Key)
+}
@@ -117,3 +117,58 @@ func WithEvictDuration[K comparable, V any](evictDuration time.Duration) Option[
 		c.evictDuration = evictDuration
 	}
 }
+
+// WithPersistence is an option that makes the cache survive restarts.
+//
+// The non-expired items are written to the file every interval and when the
+// cache is closed, and are restored by NewCache. The items that expire while
+// the process is down are restored too, so they are evicted and the onEvict
+// function is called for them as if the process never stopped. The values are
+// serialized as JSON, so their type must support encoding/json.
+//
+// Parameters:
+//   - path: The path to the snapshot file.
+//   - interval: The interval between the snapshots. If it is zero, the cache
+//     is only written when it is closed.
+//
+// Returns:
+//   - An Option that enables the persistence of the cache.
+func WithPersistence[K comparable, V any](path string, interval time.Duration) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.path = path
+		c.snapshotInterval = interval
+	}
+}
+
+// WithOnError is an option that sets the function called when the cache cannot
+// be restored or persisted.
+//
+// Parameters:
+//   - onError: The function called with the error.
+//
+// Returns:
+//   - An Option that sets the error handler of the cache.
+func WithOnError[K comparable, V any](onError func(error)) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.onError = onError
+	}
+}
+
+// WithEvictWorkers is an option that sets the number of the goroutines calling
+// the onEvict function.
+//
+// The onEvict function is called outside the lock of the cache, so a slow
+// function does not block the cache. The evictions of the same key are handled
+// by one goroutine at a time, in the order the key expired. The default is
+// DefaultEvictWorkers.
+//
+// Parameters:
+//   - workers: The number of the goroutines. It is at least one.
+//
+// Returns:
+//   - An Option that sets the number of the eviction workers.
+func WithEvictWorkers[K comparable, V any](workers int) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.evictWorkers = workers
+	}
+}
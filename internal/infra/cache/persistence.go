@@ -0,0 +1,85 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/bavix/vakeel-way/internal/infra/atomicfile"
+)
+
+// entry is an item of the cache in the snapshot file.
+type entry[K comparable, V any] struct {
+	Key   K         `json:"key"`
+	Value V         `json:"value"`
+	TTL   time.Time `json:"ttl"`
+}
+
+// Snapshot writes the non-expired items to the snapshot file.
+//
+// It is a no-op if the persistence is disabled. The file is replaced
+// atomically, so a crash never leaves a truncated snapshot.
+//
+// Returns:
+//   - An error if the items cannot be encoded or written.
+func (c *Cache[K, V]) Snapshot() error {
+	if c.path == "" {
+		return nil
+	}
+
+	// Copy the non-expired items under the read lock.
+	c.mu.RLock()
+
+	now := c.clock.Now()
+	entries := make([]entry[K, V], 0, len(c.items))
+
+	for key, item := range c.items {
+		if !item.TTL.Before(now) {
+			entries = append(entries, entry[K, V]{Key: key, Value: item.Value, TTL: item.TTL})
+		}
+	}
+
+	c.mu.RUnlock()
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(c.path, data)
+}
+
+// restore loads the items from the snapshot file. A missing file is not an error.
+func (c *Cache[K, V]) restore() error {
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	var entries []entry[K, V]
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, e := range entries {
+		c.put(&item[K, V]{Key: e.Key, Value: e.Value, TTL: e.TTL, index: 0})
+	}
+
+	return nil
+}
+
+// persist writes the snapshot and reports the error, if any.
+func (c *Cache[K, V]) persist() {
+	if err := c.Snapshot(); err != nil {
+		c.onError(err)
+	}
+}
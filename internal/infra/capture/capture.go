@@ -0,0 +1,176 @@
+package capture
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// DefaultSize is the default number of the notifications kept in memory.
+const DefaultSize = 100
+
+// API is a notifier that captures the rendered notifications instead of
+// delivering them.
+//
+// The latest notifications are kept in a ring buffer, and every notification
+// is optionally appended to a file as a JSON line. It lets the staging
+// environments exercise the full pipeline without any external calls.
+type API struct {
+	// notifications is the ring buffer of the latest notifications.
+	notifications []entities.CapturedNotification
+
+	// next is the position of the next notification in the ring buffer.
+	next int
+
+	// full reports whether the ring buffer has wrapped around.
+	full bool
+
+	// path is the path to the file the notifications are appended to. It is optional.
+	path string
+
+	// mu is the mutex used to synchronize access to the ring buffer and the file.
+	mu sync.Mutex
+
+	// now returns the current time.
+	now func() time.Time
+}
+
+// Option is a function that can be used to configure an API instance.
+type Option func(*API)
+
+// WithSize returns an Option that sets the number of the notifications kept in memory.
+//
+// Parameters:
+//   - size: The size of the ring buffer. The values below 1 are ignored.
+//
+// Returns:
+//   - An Option that sets the size of the ring buffer.
+func WithSize(size int) Option {
+	return func(api *API) {
+		if size > 0 {
+			api.notifications = make([]entities.CapturedNotification, size)
+		}
+	}
+}
+
+// WithFile returns an Option that sets the file the notifications are appended to.
+//
+// Parameters:
+//   - path: The path to the file.
+//
+// Returns:
+//   - An Option that sets the file of the captured notifications.
+func WithFile(path string) Option {
+	return func(api *API) {
+		api.path = path
+	}
+}
+
+// NewAPI creates a new capture notifier.
+//
+// Parameters:
+//   - ops: A variadic number of Option functions.
+//
+// Returns:
+//   - A pointer to an API struct.
+//
+//nolint:exhaustruct
+func NewAPI(ops ...Option) *API {
+	api := &API{
+		notifications: make([]entities.CapturedNotification, DefaultSize),
+		now:           time.Now,
+	}
+
+	// Apply all provided options to the API struct.
+	for _, op := range ops {
+		op(api)
+	}
+
+	return api
+}
+
+// line is a captured notification in the file.
+type line struct {
+	At      time.Time `json:"at"`
+	Target  string    `json:"target"`
+	ID      uuid.UUID `json:"id"`
+	Status  string    `json:"status"`
+	Message string    `json:"message"`
+}
+
+// Send captures the notification.
+//
+// Parameters:
+//   - ctx: The context.Context, unused.
+//   - target: The entities.Target the notification is addressed to.
+//   - event: The entities.Event with the rendered message.
+//
+// Returns:
+//   - An error if the notification cannot be appended to the file.
+func (a *API) Send(_ context.Context, target entities.Target, event entities.Event) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	captured := entities.CapturedNotification{At: a.now(), Target: target.Name, Event: event}
+
+	// Append the notification to the file first, so a failure is retried as a
+	// failed delivery.
+	if a.path != "" {
+		if err := a.append(captured); err != nil {
+			return err
+		}
+	}
+
+	a.notifications[a.next] = captured
+	a.next = (a.next + 1) % len(a.notifications)
+	a.full = a.full || a.next == 0
+
+	return nil
+}
+
+// List returns the captured notifications kept in memory, oldest first.
+//
+// Returns:
+//   - The list of the captured notifications.
+func (a *API) List() []entities.CapturedNotification {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !a.full {
+		return append([]entities.CapturedNotification(nil), a.notifications[:a.next]...)
+	}
+
+	list := make([]entities.CapturedNotification, 0, len(a.notifications))
+	list = append(list, a.notifications[a.next:]...)
+
+	return append(list, a.notifications[:a.next]...)
+}
+
+// append appends the notification to the file as a JSON line.
+func (a *API) append(captured entities.CapturedNotification) error {
+	const perm = 0o600
+
+	file, err := os.OpenFile(a.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, perm)
+	if err != nil {
+		return err
+	}
+
+	err = json.NewEncoder(file).Encode(line{
+		At:      captured.At,
+		Target:  captured.Target,
+		ID:      captured.Event.ID,
+		Status:  captured.Event.Status.String(),
+		Message: captured.Event.Message,
+	})
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
@@ -0,0 +1,76 @@
+package capture_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/capture"
+)
+
+// CaptureTestSuite represents the test suite for the capture notifier.
+type CaptureTestSuite struct {
+	suite.Suite
+}
+
+// TestAPI_Ring verifies that only the latest notifications are kept, oldest first.
+func (suite *CaptureTestSuite) TestAPI_Ring() {
+	api := capture.NewAPI(capture.WithSize(2))
+	target := entities.Target{Name: "staging", Type: "capture"}
+
+	suite.Empty(api.List())
+
+	for _, message := range []string{"first", "second", "third"} {
+		suite.Require().NoError(api.Send(context.Background(), target, entities.Event{Message: message}))
+	}
+
+	list := api.List()
+	suite.Require().Len(list, 2)
+	suite.Equal("second", list[0].Event.Message)
+	suite.Equal("third", list[1].Event.Message)
+	suite.Equal("staging", list[1].Target)
+}
+
+// TestAPI_File verifies that every notification is appended to the file.
+func (suite *CaptureTestSuite) TestAPI_File() {
+	path := filepath.Join(suite.T().TempDir(), "captured.jsonl")
+	api := capture.NewAPI(capture.WithSize(1), capture.WithFile(path))
+	id := uuid.New()
+
+	for _, status := range []entities.Status{entities.Up, entities.Down} {
+		event := entities.Event{ID: id, Status: status, Message: status.String()}
+		suite.Require().NoError(api.Send(context.Background(), entities.Target{Name: "staging"}, event))
+	}
+
+	file, err := os.Open(path)
+	suite.Require().NoError(err)
+
+	defer file.Close()
+
+	var lines []map[string]any
+
+	for scanner := bufio.NewScanner(file); scanner.Scan(); {
+		var line map[string]any
+		suite.Require().NoError(json.Unmarshal(scanner.Bytes(), &line))
+
+		lines = append(lines, line)
+	}
+
+	suite.Require().Len(lines, 2)
+	suite.Equal(id.String(), lines[0]["id"])
+	suite.Equal(entities.Down.String(), lines[1]["status"])
+	suite.Equal("staging", lines[1]["target"])
+}
+
+// TestCaptureTestSuite runs the capture notifier test suite.
+func TestCaptureTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CaptureTestSuite))
+}
@@ -0,0 +1,38 @@
+package certs
+
+import (
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// NewACMEManager creates the manager of the certificates obtained from an ACME authority.
+//
+// The certificates are obtained on the first TLS handshake for a domain,
+// stored in the cache directory and renewed before they expire. The
+// handshakes for the unlisted server names are rejected, so the rate limits of
+// the authority cannot be exhausted by the scanners.
+//
+// Parameters:
+//   - domains: The domains the certificates are obtained for.
+//   - cacheDir: The directory the certificates and the account key are stored in.
+//   - email: The contact email of the account, or empty.
+//   - directoryURL: The directory of the authority, or empty for Let's Encrypt.
+//
+// Returns:
+//   - A pointer to an autocert.Manager.
+//
+//nolint:exhaustruct
+func NewACMEManager(domains []string, cacheDir, email, directoryURL string) *autocert.Manager {
+	manager := &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		Cache:      autocert.DirCache(cacheDir),
+		HostPolicy: autocert.HostWhitelist(domains...),
+		Email:      email,
+	}
+
+	if directoryURL != "" {
+		manager.Client = &acme.Client{DirectoryURL: directoryURL}
+	}
+
+	return manager
+}
@@ -0,0 +1,40 @@
+package certs_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/certs"
+)
+
+// ACMETestSuite represents the test suite for the automatic certificates.
+type ACMETestSuite struct {
+	suite.Suite
+}
+
+// TestACMEManager_HostPolicy verifies that the certificates are obtained for the listed domains only.
+func (suite *ACMETestSuite) TestACMEManager_HostPolicy() {
+	manager := certs.NewACMEManager([]string{"way.example.com"}, suite.T().TempDir(), "ops@example.com", "")
+
+	suite.Require().NoError(manager.HostPolicy(context.Background(), "way.example.com"))
+	suite.Require().Error(manager.HostPolicy(context.Background(), "scanner.example.org"))
+	suite.Equal("ops@example.com", manager.Email)
+	suite.Nil(manager.Client)
+}
+
+// TestACMEManager_Directory verifies that a custom directory is used, e.g. the staging of Let's Encrypt.
+func (suite *ACMETestSuite) TestACMEManager_Directory() {
+	staging := "https://acme-staging-v02.api.letsencrypt.org/directory"
+	manager := certs.NewACMEManager([]string{"way.example.com"}, suite.T().TempDir(), "", staging)
+
+	suite.Require().NotNil(manager.Client)
+	suite.Equal(staging, manager.Client.DirectoryURL)
+}
+
+// TestACMETestSuite runs the test suite for the automatic certificates.
+func TestACMETestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ACMETestSuite))
+}
@@ -0,0 +1,58 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrInvalidCA is an error that indicates that the CA file holds no PEM certificate.
+var ErrInvalidCA = errors.New("tls: no certificate in the ca file")
+
+// ClientTLS returns the TLS configuration of the clients of the gRPC server.
+//
+// The certificate of the server is verified with the system roots, so the
+// certificates of a public CA, e.g. the ones issued by ACME, are trusted out of
+// the box. The CA certificates of the file, e.g. the CA of a private PKI or the
+// self-signed certificate of the server itself, are trusted in addition.
+//
+// Parameters:
+//   - caFile: The path to the PEM CA certificates, or empty for the system roots only.
+//   - serverName: The name the certificate is verified against, or empty for
+//     the host of the address dialed.
+//
+// Returns:
+//   - A pointer to a tls.Config.
+//   - ErrInvalidCA if the CA file holds no certificate, or an error if it
+//     cannot be read.
+func ClientTLS(caFile, serverName string) (*tls.Config, error) {
+	// The system roots are unavailable on some platforms, the pool is empty then.
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+
+	//nolint:exhaustruct
+	tlsConfig := &tls.Config{
+		RootCAs:    pool,
+		ServerName: serverName,
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if caFile == "" {
+		return tlsConfig, nil
+	}
+
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidCA, caFile)
+	}
+
+	return tlsConfig, nil
+}
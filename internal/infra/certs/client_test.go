@@ -0,0 +1,149 @@
+package certs_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+
+	"github.com/bavix/vakeel-way/internal/infra/certs"
+)
+
+// ClientTestSuite represents the test suite for the TLS of the gRPC clients.
+type ClientTestSuite struct {
+	suite.Suite
+
+	dir  string
+	addr string
+}
+
+// SetupTest starts a gRPC server over TLS with a self-signed certificate of way.example.com.
+func (suite *ClientTestSuite) SetupTest() {
+	suite.dir = suite.T().TempDir()
+
+	certFile, keyFile := suite.issue("server", "way.example.com")
+
+	reloader, err := certs.NewReloader(certFile, keyFile)
+	suite.Require().NoError(err)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(reloader.TLSConfig())))
+	healthpb.RegisterHealthServer(server, health.NewServer())
+
+	go func() { _ = server.Serve(listener) }()
+
+	suite.T().Cleanup(server.Stop)
+
+	suite.addr = listener.Addr().String()
+}
+
+// issue writes a self-signed key pair of the domain and returns the paths of its files.
+func (suite *ClientTestSuite) issue(name, domain string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.Require().NoError(err)
+
+	//nolint:exhaustruct
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: domain},
+		DNSNames:     []string{domain},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	suite.Require().NoError(err)
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	suite.Require().NoError(err)
+
+	certFile := filepath.Join(suite.dir, name+".crt")
+	keyFile := filepath.Join(suite.dir, name+".key")
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})   //nolint:exhaustruct
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}) //nolint:exhaustruct
+
+	suite.Require().NoError(os.WriteFile(certFile, certPEM, 0o600))
+	suite.Require().NoError(os.WriteFile(keyFile, keyPEM, 0o600))
+
+	return certFile, keyFile
+}
+
+// check dials the server with the CA file and the server name and returns the code of a health check.
+func (suite *ClientTestSuite) check(caFile, serverName string) codes.Code {
+	tlsConfig, err := certs.ClientTLS(caFile, serverName)
+	suite.Require().NoError(err)
+
+	conn, err := grpc.NewClient(suite.addr, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	suite.Require().NoError(err)
+
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+
+	return status.Code(err)
+}
+
+// TestClientTLS_Dial verifies that the server certificate issued by the CA is accepted.
+func (suite *ClientTestSuite) TestClientTLS_Dial() {
+	suite.Equal(codes.OK, suite.check(filepath.Join(suite.dir, "server.crt"), "way.example.com"))
+}
+
+// TestClientTLS_UnknownCA verifies that the server certificate issued by another CA is rejected.
+func (suite *ClientTestSuite) TestClientTLS_UnknownCA() {
+	caFile, _ := suite.issue("other", "way.example.com")
+
+	suite.Equal(codes.Unavailable, suite.check(caFile, "way.example.com"))
+}
+
+// TestClientTLS_ServerName verifies that the server certificate of another name is rejected.
+func (suite *ClientTestSuite) TestClientTLS_ServerName() {
+	suite.Equal(codes.Unavailable, suite.check(filepath.Join(suite.dir, "server.crt"), "other.example.com"))
+}
+
+// TestClientTLS_SystemRoots verifies that the system roots are used without a CA file.
+func (suite *ClientTestSuite) TestClientTLS_SystemRoots() {
+	tlsConfig, err := certs.ClientTLS("", "way.example.com")
+	suite.Require().NoError(err)
+	suite.Require().NotNil(tlsConfig.RootCAs)
+
+	// The self-signed certificate is not issued by a system root.
+	suite.Equal(codes.Unavailable, suite.check("", "way.example.com"))
+}
+
+// TestClientTLS_InvalidCA verifies that a CA file without certificates is reported.
+func (suite *ClientTestSuite) TestClientTLS_InvalidCA() {
+	caFile := filepath.Join(suite.dir, "ca.crt")
+	suite.Require().NoError(os.WriteFile(caFile, []byte("broken"), 0o600))
+
+	_, err := certs.ClientTLS(caFile, "")
+	suite.Require().ErrorIs(err, certs.ErrInvalidCA)
+}
+
+// TestClientTestSuite runs the test suite for the TLS of the gRPC clients.
+func TestClientTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ClientTestSuite))
+}
@@ -0,0 +1,181 @@
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// ErrNoCertificate is an error that indicates that the certificate file holds no certificate.
+var ErrNoCertificate = errors.New("certs: no certificate in the file")
+
+// Option is a function that configures a Reloader.
+type Option func(*Reloader)
+
+// WithInterval returns an Option that sets the interval the files are checked for changes at.
+func WithInterval(interval time.Duration) Option {
+	return func(r *Reloader) {
+		r.interval = interval
+	}
+}
+
+// Reloader serves the TLS certificate of a key pair and reloads it when its files change.
+//
+// The files are read again periodically and the certificate is swapped when
+// their content changes, so the renewals of cert-manager or certbot take
+// effect without a restart. A renewal that cannot be loaded, e.g. while only
+// one of the files has been written, keeps the previous certificate.
+type Reloader struct {
+	certFile string
+	keyFile  string
+	interval time.Duration
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+	pem  []byte
+}
+
+// NewReloader creates a new Reloader and loads the key pair.
+//
+// Parameters:
+//   - certFile: The path to the PEM certificate chain.
+//   - keyFile: The path to the PEM private key.
+//   - opts: The options of the Reloader.
+//
+// Returns:
+//   - A pointer to a Reloader.
+//   - An error if the key pair cannot be loaded.
+//
+//nolint:exhaustruct
+func NewReloader(certFile, keyFile string, opts ...Option) (*Reloader, error) {
+	r := &Reloader{certFile: certFile, keyFile: keyFile, interval: 30 * time.Second} //nolint:mnd
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if _, err := r.load(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// GetCertificate returns the current certificate. It is the GetCertificate
+// callback of the tls.Config.
+func (r *Reloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert, nil
+}
+
+// TLSConfig returns the server tls.Config serving the current certificate.
+//
+// Returns:
+//   - A pointer to a tls.Config requiring TLS 1.2 or newer.
+func (r *Reloader) TLSConfig() *tls.Config {
+	//nolint:exhaustruct
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.GetCertificate,
+	}
+}
+
+// Watch checks the files for changes until the context is closed.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the watch, holding the logger.
+func (r *Reloader) Watch(ctx context.Context) {
+	logger := zerolog.Ctx(ctx)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			changed, err := r.load()
+			if err != nil {
+				logger.Error().Err(err).Str("cert", r.certFile).Msg("Failed to reload the TLS certificate")
+
+				continue
+			}
+
+			if changed {
+				logger.Info().
+					Str("cert", r.certFile).
+					Time("not_after", r.expiry()).
+					Msg("TLS certificate reloaded")
+			}
+		}
+	}
+}
+
+// load reads the key pair and swaps the certificate if it has changed.
+//
+// Returns:
+//   - Whether the certificate has been swapped.
+//   - An error if the key pair cannot be loaded.
+func (r *Reloader) load() (bool, error) {
+	certPEM, err := os.ReadFile(r.certFile)
+	if err != nil {
+		return false, err
+	}
+
+	keyPEM, err := os.ReadFile(r.keyFile)
+	if err != nil {
+		return false, err
+	}
+
+	// The files are compared as a whole, so a new key with the same
+	// certificate is loaded as well.
+	content := append(append([]byte(nil), certPEM...), keyPEM...)
+
+	r.mu.RLock()
+	unchanged := bytes.Equal(content, r.pem)
+	r.mu.RUnlock()
+
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return false, fmt.Errorf("certs: %s: %w", r.certFile, err)
+	}
+
+	if len(cert.Certificate) == 0 {
+		return false, fmt.Errorf("%w: %s", ErrNoCertificate, r.certFile)
+	}
+
+	if cert.Leaf == nil {
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return false, fmt.Errorf("certs: %s: %w", r.certFile, err)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert, r.pem = &cert, content
+	r.mu.Unlock()
+
+	return true, nil
+}
+
+// expiry returns the expiration time of the current certificate.
+func (r *Reloader) expiry() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert.Leaf.NotAfter
+}
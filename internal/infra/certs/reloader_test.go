@@ -0,0 +1,117 @@
+package certs_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/certs"
+)
+
+// ReloaderTestSuite represents the test suite for the reloads of the TLS certificates.
+type ReloaderTestSuite struct {
+	suite.Suite
+
+	certFile string
+	keyFile  string
+}
+
+// SetupTest sets the paths of the key pair in a temporary directory.
+func (suite *ReloaderTestSuite) SetupTest() {
+	dir := suite.T().TempDir()
+
+	suite.certFile = filepath.Join(dir, "tls.crt")
+	suite.keyFile = filepath.Join(dir, "tls.key")
+}
+
+// issue writes a self-signed key pair with the serial number.
+func (suite *ReloaderTestSuite) issue(serial int64) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	suite.Require().NoError(err)
+
+	//nolint:exhaustruct
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "way.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	suite.Require().NoError(err)
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	suite.Require().NoError(err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})   //nolint:exhaustruct
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}) //nolint:exhaustruct
+
+	suite.Require().NoError(os.WriteFile(suite.certFile, certPEM, 0o600))
+	suite.Require().NoError(os.WriteFile(suite.keyFile, keyPEM, 0o600))
+}
+
+// serial returns the serial number of the certificate served by the Reloader.
+func (suite *ReloaderTestSuite) serial(reloader *certs.Reloader) int64 {
+	cert, err := reloader.GetCertificate(nil)
+	suite.Require().NoError(err)
+
+	return cert.Leaf.SerialNumber.Int64()
+}
+
+// TestReloader_Renewal verifies that a renewed certificate is served without a restart.
+func (suite *ReloaderTestSuite) TestReloader_Renewal() {
+	suite.issue(1)
+
+	reloader, err := certs.NewReloader(suite.certFile, suite.keyFile, certs.WithInterval(10*time.Millisecond))
+	suite.Require().NoError(err)
+	suite.Equal(int64(1), suite.serial(reloader))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go reloader.Watch(ctx)
+
+	suite.issue(2)
+	suite.Eventually(func() bool { return suite.serial(reloader) == 2 }, time.Second, 10*time.Millisecond)
+}
+
+// TestReloader_Broken verifies that a key pair that cannot be loaded keeps the previous certificate.
+func (suite *ReloaderTestSuite) TestReloader_Broken() {
+	suite.issue(1)
+
+	reloader, err := certs.NewReloader(suite.certFile, suite.keyFile, certs.WithInterval(10*time.Millisecond))
+	suite.Require().NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go reloader.Watch(ctx)
+
+	// The certificate is renewed but the key is not written yet.
+	suite.Require().NoError(os.WriteFile(suite.keyFile, []byte("broken"), 0o600))
+	time.Sleep(50 * time.Millisecond)
+	suite.Equal(int64(1), suite.serial(reloader))
+}
+
+// TestReloader_Missing verifies that a missing key pair is reported on startup.
+func (suite *ReloaderTestSuite) TestReloader_Missing() {
+	_, err := certs.NewReloader(suite.certFile, suite.keyFile)
+	suite.Require().ErrorIs(err, os.ErrNotExist)
+}
+
+// TestReloaderTestSuite runs the test suite for the reloads of the TLS certificates.
+func TestReloaderTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ReloaderTestSuite))
+}
@@ -0,0 +1,122 @@
+package cloudevents
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// ContentType is the media type of the structured CloudEvents in JSON.
+const ContentType = "application/cloudevents+json"
+
+// Source is the source of the events.
+const Source = "vakeel-way"
+
+// TypePrefix is the prefix of the types of the events, followed by the status,
+// e.g. "io.github.bavix.vakeel-way.service.down", or by "warning" for the
+// early warnings.
+const TypePrefix = "io.github.bavix.vakeel-way.service."
+
+// Envelope is a CloudEvents 1.0 event in the structured JSON format.
+type Envelope struct {
+	SpecVersion     string    `json:"specversion"`
+	ID              string    `json:"id"`
+	Source          string    `json:"source"`
+	Type            string    `json:"type"`
+	Subject         string    `json:"subject"`
+	Time            time.Time `json:"time"`
+	DataContentType string    `json:"datacontenttype"`
+	Data            Data      `json:"data"`
+}
+
+// Data is the data of the event: the status transition of the service.
+type Data struct {
+	ID          uuid.UUID         `json:"id"`
+	Status      string            `json:"status"`
+	Message     string            `json:"message,omitempty"`
+	Reason      string            `json:"reason,omitempty"`
+	Since       *time.Time        `json:"since,omitempty"`
+	LastSeen    *time.Time        `json:"last_seen,omitempty"`
+	Cause       *uuid.UUID        `json:"cause,omitempty"`
+	Unreachable bool              `json:"unreachable,omitempty"`
+	Warning     bool              `json:"warning,omitempty"`
+	Hostname    string            `json:"hostname,omitempty"`
+	Version     string            `json:"version,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	RequestID   string            `json:"request_id,omitempty"`
+}
+
+// New returns the CloudEvents envelope of the event.
+//
+// The identifier of the envelope is derived from the service, the status and
+// the time of the event, so a retried delivery carries the same identifier
+// and the brokers can drop the duplicates.
+//
+// Parameters:
+//   - event: The entities.Event to wrap.
+//
+// Returns:
+//   - The Envelope of the event.
+func New(event entities.Event) Envelope {
+	data := Data{
+		ID:          event.ID,
+		Status:      event.Status.String(),
+		Message:     event.Message,
+		Reason:      event.Reason,
+		Since:       optional(event.Since),
+		LastSeen:    optional(event.LastSeen),
+		Cause:       nil,
+		Unreachable: event.Unreachable,
+		Warning:     event.Warning(),
+		Hostname:    event.Agent.Hostname,
+		Version:     event.Agent.Version,
+		Labels:      event.Agent.Labels,
+		RequestID:   event.RequestID,
+	}
+
+	if event.Cause != uuid.Nil {
+		data.Cause = &event.Cause
+	}
+
+	// The early warnings have a type of their own, so the consumers of the
+	// transitions do not mistake them for recoveries.
+	kind := event.Status.String()
+	if event.Warning() {
+		kind = event.Kind.String()
+	}
+
+	return Envelope{
+		SpecVersion:     "1.0",
+		ID:              uuid.NewSHA1(event.ID, []byte(kind+"@"+event.Time.UTC().Format(time.RFC3339Nano))).String(),
+		Source:          Source,
+		Type:            TypePrefix + kind,
+		Subject:         event.ID.String(),
+		Time:            event.Time.UTC(),
+		DataContentType: "application/json",
+		Data:            data,
+	}
+}
+
+// Marshal returns the CloudEvents envelope of the event encoded as JSON.
+//
+// Parameters:
+//   - event: The entities.Event to wrap.
+//
+// Returns:
+//   - The encoded envelope.
+//   - An error if the envelope cannot be encoded.
+func Marshal(event entities.Event) ([]byte, error) {
+	return json.Marshal(New(event))
+}
+
+// optional returns a pointer to the time, or nil if it is zero.
+func optional(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
@@ -0,0 +1,61 @@
+package cloudevents_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/cloudevents"
+)
+
+// EnvelopeTestSuite represents the test suite for the CloudEvents envelope.
+type EnvelopeTestSuite struct {
+	suite.Suite
+}
+
+// TestEnvelope_Marshal verifies that the event is wrapped into a CloudEvents 1.0 envelope.
+func (suite *EnvelopeTestSuite) TestEnvelope_Marshal() {
+	id := uuid.MustParse("224f8a59-6705-4f3e-b7de-177757932aad")
+	event := entities.Event{
+		ID:      id,
+		Status:  entities.Down,
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Message: "api is down",
+		Agent:   entities.Agent{Hostname: "node-1"},
+	}
+
+	data, err := cloudevents.Marshal(event)
+	suite.Require().NoError(err)
+
+	var envelope map[string]any
+	suite.Require().NoError(json.Unmarshal(data, &envelope))
+
+	suite.Equal("1.0", envelope["specversion"])
+	suite.Equal("vakeel-way", envelope["source"])
+	suite.Equal("io.github.bavix.vakeel-way.service.down", envelope["type"])
+	suite.Equal(id.String(), envelope["subject"])
+	suite.Equal("2024-01-02T03:04:05Z", envelope["time"])
+	suite.Equal("application/json", envelope["datacontenttype"])
+	suite.Equal(map[string]any{
+		"id":       id.String(),
+		"status":   "down",
+		"message":  "api is down",
+		"hostname": "node-1",
+	}, envelope["data"])
+
+	// A retried delivery carries the same identifier.
+	suite.Equal(cloudevents.New(event).ID, envelope["id"])
+
+	event.Status = entities.Up
+	suite.NotEqual(cloudevents.New(event).ID, envelope["id"])
+}
+
+// TestEnvelopeTestSuite runs the test suite for the CloudEvents envelope.
+func TestEnvelopeTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(EnvelopeTestSuite))
+}
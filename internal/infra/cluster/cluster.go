@@ -0,0 +1,402 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// renewScript extends the lease if it is still held by the node.
+//
+//nolint:gochecknoglobals
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseScript removes the lease if it is still held by the node.
+//
+//nolint:gochecknoglobals
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// The kinds of the events published on the channel of the cluster.
+const (
+	kindHeartbeat  = "heartbeat"
+	kindTransition = "transition"
+)
+
+// envelope is a heartbeat or a status transition published on the channel of the cluster.
+//
+// The Sent time of a transition is the time of the transition.
+type envelope struct {
+	Node    string        `json:"node"`
+	Kind    string        `json:"kind,omitempty"`
+	ID      uuid.UUID     `json:"id"`
+	Status  string        `json:"status"`
+	Message string        `json:"message,omitempty"`
+	TTL     time.Duration `json:"ttl,omitempty"`
+	Request string        `json:"request,omitempty"`
+	Sent    time.Time     `json:"sent"`
+}
+
+// Node is the member of the cluster of vakeel-way instances.
+//
+// Every node publishes the heartbeats received from its agents on a Redis
+// channel and feeds the heartbeats published by the other nodes into its own
+// checker, so every instance tracks the same statuses whichever instance the
+// agents are connected to. The active node publishes its status transitions
+// as well, e.g. the services that went down because their heartbeats stopped,
+// so the standby nodes follow the statuses the notifications were sent for.
+// The node coordinates the replica with a lease in
+// Redis: only the node holding the lease is active and dispatches the
+// notifications, the others are standby and take over once the lease expires.
+type Node struct {
+	// client is the Redis client.
+	client redis.UniversalClient
+
+	// id is the unique name of the node in the cluster.
+	id string
+
+	// channel is the Redis channel the heartbeats are published on.
+	channel string
+
+	// key is the Redis key holding the lease of the leader.
+	key string
+
+	// lease is the time the leadership is held without a renewal.
+	lease time.Duration
+
+	// outbox buffers the heartbeats and the transitions waiting to be published.
+	outbox chan envelope
+
+	// dropped is the number of the events dropped because the outbox was full.
+	dropped atomic.Uint64
+}
+
+// Option is a function that can be used to configure a Node instance.
+type Option func(*Node)
+
+// WithPrefix returns an Option that sets the prefix of the Redis channel and key.
+//
+// The instances sharing the prefix form a cluster, so several clusters can
+// share a Redis server.
+//
+// Parameters:
+//   - prefix: The prefix. The heartbeats are published on "<prefix>:heartbeats"
+//     and the lease is held in "<prefix>:leader".
+//
+// Returns:
+//   - An Option that sets the prefix of the Node.
+func WithPrefix(prefix string) Option {
+	return func(n *Node) {
+		n.channel = prefix + ":heartbeats"
+		n.key = prefix + ":leader"
+	}
+}
+
+// WithLease returns an Option that sets the duration of the lease of the leader.
+//
+// Once the leader is gone, another node takes over within the duration.
+//
+// Parameters:
+//   - lease: The duration of the lease. The values below a second are ignored.
+//
+// Returns:
+//   - An Option that sets the lease of the Node.
+func WithLease(lease time.Duration) Option {
+	return func(n *Node) {
+		if lease >= time.Second {
+			n.lease = lease
+		}
+	}
+}
+
+// WithBufferSize returns an Option that sets the number of the heartbeats and
+// the transitions buffered while they are published.
+//
+// Parameters:
+//   - size: The size of the buffer. The values below 1 are ignored.
+//
+// Returns:
+//   - An Option that sets the buffer size of the Node.
+func WithBufferSize(size int) Option {
+	return func(n *Node) {
+		if size > 0 {
+			n.outbox = make(chan envelope, size)
+		}
+	}
+}
+
+// NewNode creates a new instance of the Node struct.
+//
+// Parameters:
+//   - client: The Redis client.
+//   - id: The unique name of the node in the cluster.
+//   - options: Optional configurations for the Node.
+//
+// Returns:
+//   - A pointer to the initialized Node.
+//
+//nolint:exhaustruct
+func NewNode(client redis.UniversalClient, id string, options ...Option) *Node {
+	const (
+		bufferSize = 1024
+		lease      = 15 * time.Second
+	)
+
+	node := &Node{
+		client: client,
+		id:     id,
+		lease:  lease,
+		outbox: make(chan envelope, bufferSize),
+	}
+
+	WithPrefix("vakeel-way")(node)
+
+	// Apply any optional configurations provided through the options parameter.
+	for _, option := range options {
+		option(node)
+	}
+
+	return node
+}
+
+// ID returns the unique name of the node in the cluster.
+func (n *Node) ID() string {
+	return n.id
+}
+
+// Dropped returns the number of the events dropped because the outbox was full.
+func (n *Node) Dropped() uint64 {
+	return n.dropped.Load()
+}
+
+// Broadcast queues the heartbeat to be published to the other nodes.
+//
+// It never blocks the caller: the heartbeat is dropped if the outbox is full,
+// the agent resends it anyway.
+//
+// Parameters:
+//   - heartbeat: The entities.Heartbeat received by the instance.
+func (n *Node) Broadcast(heartbeat entities.Heartbeat) {
+	n.queue(envelope{
+		Node:    n.id,
+		Kind:    kindHeartbeat,
+		ID:      heartbeat.ID,
+		Status:  heartbeat.Status.String(),
+		Message: heartbeat.Message,
+		TTL:     heartbeat.TTL,
+		Request: heartbeat.RequestID,
+		Sent:    heartbeat.Sent,
+	})
+}
+
+// Transition queues the status transition to be published to the other nodes.
+//
+// It is called for the transitions of the active node only. The other nodes
+// receive the transition as a heartbeat with the status, sent at the time of
+// the transition. It never blocks the caller.
+//
+// Parameters:
+//   - transition: The entities.Transition of a service.
+func (n *Node) Transition(transition entities.Transition) {
+	n.queue(envelope{
+		Node:    n.id,
+		Kind:    kindTransition,
+		ID:      transition.ID,
+		Status:  transition.Status.String(),
+		Message: "",
+		TTL:     0,
+		Request: "",
+		Sent:    transition.At,
+	})
+}
+
+// queue queues the event to be published, or drops it if the outbox is full.
+//
+// Parameters:
+//   - event: The envelope of the event.
+func (n *Node) queue(event envelope) {
+	select {
+	case n.outbox <- event:
+	default:
+		n.dropped.Add(1)
+	}
+}
+
+// Ping checks that the Redis server can be reached.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//
+// Returns:
+//   - An error if Redis cannot be reached.
+func (n *Node) Ping(ctx context.Context) error {
+	return n.client.Ping(ctx).Err()
+}
+
+// Acquire takes or renews the lease of the leader for the node.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//
+// Returns:
+//   - entities.ErrNotLeader if another node holds the lease.
+//   - An error if Redis cannot be reached.
+func (n *Node) Acquire(ctx context.Context) error {
+	acquired, err := n.client.SetNX(ctx, n.key, n.id, n.lease).Result()
+	if err != nil {
+		return err
+	}
+
+	if acquired {
+		return nil
+	}
+
+	renewed, err := renewScript.Run(ctx, n.client, []string{n.key}, n.id, n.lease.Milliseconds()).Int()
+	if err != nil {
+		return err
+	}
+
+	if renewed == 0 {
+		return entities.ErrNotLeader
+	}
+
+	return nil
+}
+
+// Release gives the lease of the leader up, if the node holds it, so another
+// node takes over without waiting for the lease to expire.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//
+// Returns:
+//   - An error if Redis cannot be reached.
+func (n *Node) Release(ctx context.Context) error {
+	return releaseScript.Run(ctx, n.client, []string{n.key}, n.id).Err()
+}
+
+// Lease returns the duration of the lease of the leader.
+func (n *Node) Lease() time.Duration {
+	return n.lease
+}
+
+// Run shares the heartbeats with the other nodes until the context is canceled.
+//
+// The heartbeats and the transitions queued by Broadcast and Transition are
+// published, and the ones published by the other nodes are passed to the
+// handler as heartbeats.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the node.
+//   - handle: The function receiving the heartbeats of the other nodes.
+func (n *Node) Run(ctx context.Context, handle func(entities.Heartbeat) bool) {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		n.publish(ctx)
+	}()
+
+	n.subscribe(ctx, handle)
+	<-done
+}
+
+// publish publishes the queued heartbeats and transitions until the context is canceled.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the publishing.
+func (n *Node) publish(ctx context.Context) {
+	logger := zerolog.Ctx(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-n.outbox:
+			payload, err := json.Marshal(event)
+			if err != nil {
+				logger.Err(err).Str("kind", event.Kind).Msg("cluster: failed to encode the event")
+
+				continue
+			}
+
+			if err := n.client.Publish(ctx, n.channel, payload).Err(); err != nil && ctx.Err() == nil {
+				logger.Err(err).Str("id", event.ID.String()).Str("kind", event.Kind).Msg("cluster: failed to publish the event")
+			}
+		}
+	}
+}
+
+// subscribe passes the heartbeats and the transitions of the other nodes to
+// the handler until the context is canceled. The client resubscribes after a connection loss.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the subscription.
+//   - handle: The function receiving the heartbeats.
+func (n *Node) subscribe(ctx context.Context, handle func(entities.Heartbeat) bool) {
+	logger := zerolog.Ctx(ctx)
+
+	pubsub := n.client.Subscribe(ctx, n.channel)
+	defer pubsub.Close()
+
+	messages := pubsub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			var env envelope
+			if err := json.Unmarshal([]byte(msg.Payload), &env); err != nil {
+				logger.Err(err).Msg("cluster: failed to decode the heartbeat")
+
+				continue
+			}
+
+			// The heartbeats of the node are already handled.
+			if env.Node == n.id {
+				continue
+			}
+
+			status, err := entities.ParseStatus(env.Status)
+			if err != nil {
+				logger.Err(err).Str("node", env.Node).Msg("cluster: failed to decode the heartbeat")
+
+				continue
+			}
+
+			heartbeat := entities.Heartbeat{
+				ID:        env.ID,
+				Status:    status,
+				Message:   env.Message,
+				TTL:       env.TTL,
+				RequestID: env.Request,
+				Sent:      env.Sent,
+			}
+			if !handle(heartbeat) {
+				logger.Warn().Str("id", env.ID.String()).Str("node", env.Node).Str("kind", env.Kind).Msg("cluster: event dropped")
+			}
+		}
+	}
+}
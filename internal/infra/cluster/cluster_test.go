@@ -0,0 +1,159 @@
+package cluster_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/cluster"
+)
+
+// ClusterTestSuite represents the test suite for the cluster functionality.
+type ClusterTestSuite struct {
+	suite.Suite
+
+	server *miniredis.Miniredis
+}
+
+// SetupTest starts an in-memory Redis server for every test.
+func (suite *ClusterTestSuite) SetupTest() {
+	suite.server = miniredis.RunT(suite.T())
+}
+
+// node creates a node of the cluster connected to the Redis server.
+//
+//nolint:exhaustruct
+func (suite *ClusterTestSuite) node(id string) *cluster.Node {
+	client := redis.NewClient(&redis.Options{Addr: suite.server.Addr()})
+	suite.T().Cleanup(func() { _ = client.Close() })
+
+	return cluster.NewNode(client, id, cluster.WithPrefix("test"), cluster.WithLease(time.Second))
+}
+
+// TestNode_Lease verifies that a single node holds the lease, renews it, and
+// hands it over once it is released or expired.
+func (suite *ClusterTestSuite) TestNode_Lease() {
+	ctx := context.Background()
+	first, second := suite.node("first"), suite.node("second")
+
+	suite.Require().NoError(first.Acquire(ctx))
+	suite.Require().NoError(first.Acquire(ctx))
+	suite.Require().ErrorIs(second.Acquire(ctx), entities.ErrNotLeader)
+
+	// The release of a lease held by another node is ignored.
+	suite.Require().NoError(second.Release(ctx))
+	suite.Require().ErrorIs(second.Acquire(ctx), entities.ErrNotLeader)
+
+	suite.Require().NoError(first.Release(ctx))
+	suite.Require().NoError(second.Acquire(ctx))
+
+	// The lease expires without a renewal.
+	suite.server.FastForward(2 * time.Second)
+	suite.Require().NoError(first.Acquire(ctx))
+}
+
+// TestNode_Run verifies that the heartbeats are shared between the nodes, and
+// that a node skips its own heartbeats.
+func (suite *ClusterTestSuite) TestNode_Run() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first, second := suite.node("first"), suite.node("second")
+
+	var (
+		mu       sync.Mutex
+		received = make(map[string][]entities.Heartbeat)
+		wg       sync.WaitGroup
+	)
+
+	for _, node := range []*cluster.Node{first, second} {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			node.Run(ctx, func(heartbeat entities.Heartbeat) bool {
+				mu.Lock()
+				defer mu.Unlock()
+
+				received[node.ID()] = append(received[node.ID()], heartbeat)
+
+				return true
+			})
+		}()
+	}
+
+	// The heartbeat is delivered to the other node only. It is resent until
+	// the subscription is established.
+	heartbeat := entities.Heartbeat{
+		ID:      uuid.New(),
+		Status:  entities.Degraded,
+		Message: "slow",
+		TTL:     time.Minute,
+		Sent:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	suite.Eventually(func() bool {
+		first.Broadcast(heartbeat)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		return len(received["second"]) > 0
+	}, time.Second, 50*time.Millisecond)
+
+	cancel()
+	wg.Wait()
+
+	suite.Equal(heartbeat, received["second"][0])
+	suite.Empty(received["first"])
+}
+
+// TestNode_Transition verifies that the status transitions are delivered to
+// the other nodes as the heartbeats sent at the time of the transition.
+func (suite *ClusterTestSuite) TestNode_Transition() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first, second := suite.node("first"), suite.node("second")
+	received := make(chan entities.Heartbeat, 16)
+
+	go first.Run(ctx, func(entities.Heartbeat) bool { return true })
+	go second.Run(ctx, func(heartbeat entities.Heartbeat) bool {
+		received <- heartbeat
+
+		return true
+	})
+
+	transition := entities.Transition{ID: uuid.New(), Status: entities.Down, At: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+
+	var heartbeat entities.Heartbeat
+
+	// The transition is resent until the subscription is established.
+	suite.Eventually(func() bool {
+		first.Transition(transition)
+
+		select {
+		case heartbeat = <-received:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, 50*time.Millisecond)
+
+	//nolint:exhaustruct
+	suite.Equal(entities.Heartbeat{ID: transition.ID, Status: entities.Down, Sent: transition.At}, heartbeat)
+}
+
+// TestClusterTestSuite runs the test suite for the cluster functionality.
+func TestClusterTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ClusterTestSuite))
+}
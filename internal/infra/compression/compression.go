@@ -0,0 +1,203 @@
+package compression
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc/encoding"
+)
+
+// Names of the compression algorithms, as negotiated in the grpc-encoding header.
+const (
+	Gzip = "gzip"
+	Zstd = "zstd"
+)
+
+// maxDecodedSize is the maximum size of a decompressed zstd message.
+//
+// The zstd messages are decompressed at once, so the size is bounded to keep
+// a decompression bomb from exhausting the memory. It is well above the
+// default maximum size of the gRPC messages.
+const maxDecodedSize = 64 << 20
+
+var (
+	// ErrUnknownAlgorithm is an error that indicates that the compression algorithm is unknown.
+	ErrUnknownAlgorithm = errors.New("compression: unknown algorithm")
+
+	// ErrInvalidLevel is an error that indicates that the gzip compression level is out of range.
+	ErrInvalidLevel = errors.New("compression: gzip level must be between 1 and 9")
+)
+
+// Register registers the gRPC compressors of the algorithms.
+//
+// The server decompresses the messages of the clients using any registered
+// algorithm and compresses its responses with the algorithm of the request,
+// so the agents opt in by compressing their messages. The compressors are
+// registered globally, so Register must be called before the server starts.
+//
+// Parameters:
+//   - algorithms: The names of the algorithms, "gzip" or "zstd".
+//   - level: The gzip compression level from 1 (fastest) to 9 (smallest), or
+//     zero for the default level.
+//
+// Returns:
+//   - ErrUnknownAlgorithm or ErrInvalidLevel if the configuration is invalid.
+//     Nothing is registered in that case.
+//   - An error if the zstd codec cannot be created.
+func Register(algorithms []string, level int) error {
+	if level == 0 {
+		level = gzip.DefaultCompression
+	} else if level < gzip.BestSpeed || level > gzip.BestCompression {
+		return fmt.Errorf("%w: %d", ErrInvalidLevel, level)
+	}
+
+	compressors := make([]encoding.Compressor, 0, len(algorithms))
+
+	for _, algorithm := range algorithms {
+		switch algorithm {
+		case Gzip:
+			compressors = append(compressors, newGzipCompressor(level))
+		case Zstd:
+			compressor, err := newZstdCompressor()
+			if err != nil {
+				return err
+			}
+
+			compressors = append(compressors, compressor)
+		default:
+			return fmt.Errorf("%w: %q", ErrUnknownAlgorithm, algorithm)
+		}
+	}
+
+	for _, compressor := range compressors {
+		encoding.RegisterCompressor(compressor)
+	}
+
+	return nil
+}
+
+// gzipCompressor is the gzip encoding.Compressor.
+//
+// The writers are pooled, as every message is compressed on its own.
+type gzipCompressor struct {
+	level   int
+	writers sync.Pool
+}
+
+// newGzipCompressor creates a new instance of the gzipCompressor struct.
+func newGzipCompressor(level int) *gzipCompressor {
+	return &gzipCompressor{level: level, writers: sync.Pool{}}
+}
+
+// Name returns the name of the algorithm.
+func (c *gzipCompressor) Name() string {
+	return Gzip
+}
+
+// Compress returns a writer compressing the message into w.
+func (c *gzipCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	if writer, ok := c.writers.Get().(*gzipWriter); ok {
+		writer.Reset(w)
+
+		return writer, nil
+	}
+
+	writer, err := gzip.NewWriterLevel(w, c.level)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gzipWriter{Writer: writer, pool: &c.writers}, nil
+}
+
+// Decompress returns a reader decompressing the message from r.
+func (c *gzipCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return gzip.NewReader(r)
+}
+
+// gzipWriter is a gzip writer returned to the pool once the message is compressed.
+type gzipWriter struct {
+	*gzip.Writer
+
+	pool *sync.Pool
+}
+
+// Close flushes the message and returns the writer to the pool.
+func (w *gzipWriter) Close() error {
+	defer w.pool.Put(w)
+
+	return w.Writer.Close()
+}
+
+// zstdCompressor is the zstd encoding.Compressor.
+//
+// The encoder and the decoder are shared: the messages are compressed and
+// decompressed at once, which is safe for concurrent use.
+type zstdCompressor struct {
+	encoder *zstd.Encoder
+	decoder *zstd.Decoder
+}
+
+// newZstdCompressor creates a new instance of the zstdCompressor struct.
+func newZstdCompressor() (*zstdCompressor, error) {
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(0), zstd.WithDecoderMaxMemory(maxDecodedSize))
+	if err != nil {
+		return nil, err
+	}
+
+	return &zstdCompressor{encoder: encoder, decoder: decoder}, nil
+}
+
+// Name returns the name of the algorithm.
+func (c *zstdCompressor) Name() string {
+	return Zstd
+}
+
+// Compress returns a writer compressing the message into w once it is closed.
+func (c *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return &zstdWriter{w: w, encoder: c.encoder, buf: bytes.Buffer{}}, nil
+}
+
+// Decompress returns a reader of the message decompressed from r.
+func (c *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	compressed, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := c.decoder.DecodeAll(compressed, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(message), nil
+}
+
+// zstdWriter buffers the message and compresses it once it is closed.
+type zstdWriter struct {
+	w       io.Writer
+	encoder *zstd.Encoder
+	buf     bytes.Buffer
+}
+
+// Write buffers the message.
+func (w *zstdWriter) Write(p []byte) (int, error) {
+	return w.buf.Write(p)
+}
+
+// Close compresses the buffered message into the underlying writer.
+func (w *zstdWriter) Close() error {
+	_, err := w.w.Write(w.encoder.EncodeAll(w.buf.Bytes(), nil))
+
+	return err
+}
@@ -0,0 +1,62 @@
+package compression_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc/encoding"
+
+	"github.com/bavix/vakeel-way/internal/infra/compression"
+)
+
+// CompressionTestSuite represents the test suite for the compressors of the gRPC messages.
+type CompressionTestSuite struct {
+	suite.Suite
+}
+
+// TestRegister_RoundTrip verifies that the registered compressors restore the messages.
+func (suite *CompressionTestSuite) TestRegister_RoundTrip() {
+	suite.Require().NoError(compression.Register([]string{compression.Gzip, compression.Zstd}, 6))
+
+	message := bytes.Repeat([]byte("224f8a59-6705-4f3e-b7de-177757932aad"), 1000)
+
+	for _, name := range []string{compression.Gzip, compression.Zstd} {
+		compressor := encoding.GetCompressor(name)
+		suite.Require().NotNil(compressor, name)
+
+		// Compress twice, so a pooled writer is reused.
+		for range 2 {
+			var buf bytes.Buffer
+
+			writer, err := compressor.Compress(&buf)
+			suite.Require().NoError(err)
+
+			_, err = writer.Write(message)
+			suite.Require().NoError(err)
+			suite.Require().NoError(writer.Close())
+			suite.Less(buf.Len(), len(message)/10, name)
+
+			reader, err := compressor.Decompress(&buf)
+			suite.Require().NoError(err)
+
+			decompressed, err := io.ReadAll(reader)
+			suite.Require().NoError(err)
+			suite.Equal(message, decompressed, name)
+		}
+	}
+}
+
+// TestRegister_Invalid verifies that an invalid configuration is rejected.
+func (suite *CompressionTestSuite) TestRegister_Invalid() {
+	suite.Require().ErrorIs(compression.Register([]string{"brotli"}, 0), compression.ErrUnknownAlgorithm)
+	suite.Require().ErrorIs(compression.Register([]string{compression.Gzip}, 10), compression.ErrInvalidLevel)
+	suite.Nil(encoding.GetCompressor("brotli"))
+}
+
+// TestCompressionTestSuite runs the test suite for the compressors of the gRPC messages.
+func TestCompressionTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(CompressionTestSuite))
+}
@@ -0,0 +1,228 @@
+package consul
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrUnexpectedStatus is an error that indicates that the Consul agent responded with an unexpected status.
+var ErrUnexpectedStatus = errors.New("consul: unexpected status")
+
+// Check represents the health check of a registered service.
+//
+// The check is run by the Consul agent: either an HTTP check of the URL, or a
+// TCP check of the address if the URL is empty.
+type Check struct {
+	// HTTP is the URL the agent probes with GET requests, e.g. the readiness probe.
+	HTTP string
+
+	// TCP is the address the agent connects to, if the HTTP URL is empty.
+	TCP string
+
+	// Interval is the interval between the checks.
+	Interval time.Duration
+
+	// Timeout is the time allowed for a check.
+	Timeout time.Duration
+
+	// DeregisterAfter is the time after which the service failing its check is
+	// deregistered, so the instances that crashed do not linger. Zero keeps them.
+	DeregisterAfter time.Duration
+}
+
+// Service represents a service registered in the Consul agent.
+type Service struct {
+	// ID is the unique identifier of the instance of the service.
+	ID string
+
+	// Name is the name the service is discovered by.
+	Name string
+
+	// Address is the address the service is reached at. If it is empty, the
+	// address of the node of the agent is used.
+	Address string
+
+	// Port is the port the service is reached at.
+	Port int
+
+	// Tags are the tags the service is filtered by in the discovery.
+	Tags []string
+
+	// Meta is the metadata of the service.
+	Meta map[string]string
+
+	// Check is the health check of the service.
+	Check Check
+}
+
+// Option is a function that configures a Registry.
+type Option func(*Registry)
+
+// WithToken returns an Option that sets the ACL token sent to the Consul agent.
+//
+// Parameters:
+//   - token: The ACL token with the service:write permission. If it is empty,
+//     no token is sent.
+//
+// Returns:
+//   - An Option that sets the token of the Registry.
+func WithToken(token string) Option {
+	return func(r *Registry) {
+		r.token = token
+	}
+}
+
+// WithHTTPClient returns an Option that sets the HTTP client of the Consul agent.
+//
+// Parameters:
+//   - client: The HTTP client.
+//
+// Returns:
+//   - An Option that sets the HTTP client of the Registry.
+func WithHTTPClient(client *http.Client) Option {
+	return func(r *Registry) {
+		r.client = client
+	}
+}
+
+// Registry registers the services in the catalog of a Consul agent.
+type Registry struct {
+	// address is the URL of the HTTP API of the agent.
+	address string
+
+	// token is the ACL token.
+	token string
+
+	// client is the HTTP client of the agent.
+	client *http.Client
+}
+
+// NewRegistry creates a new instance of the Registry struct.
+//
+// Parameters:
+//   - address: The URL of the HTTP API of the Consul agent, e.g. "http://127.0.0.1:8500".
+//   - opts: Optional configurations for the Registry.
+//
+// Returns:
+//   - A pointer to the initialized Registry.
+//
+//nolint:exhaustruct
+func NewRegistry(address string, opts ...Option) *Registry {
+	registry := &Registry{
+		address: strings.TrimSuffix(address, "/"),
+		client:  http.DefaultClient,
+	}
+
+	// Apply any optional configurations provided through the options parameter.
+	for _, opt := range opts {
+		opt(registry)
+	}
+
+	return registry
+}
+
+// Register registers the service with its health check in the agent.
+//
+// The registration replaces the previous one of the same ID.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - service: The service to register.
+//
+// Returns:
+//   - An error if the agent cannot be reached or rejects the registration.
+func (r *Registry) Register(ctx context.Context, service Service) error {
+	check := map[string]any{
+		"Interval": service.Check.Interval.String(),
+		"Timeout":  service.Check.Timeout.String(),
+	}
+
+	if service.Check.HTTP != "" {
+		check["HTTP"] = service.Check.HTTP
+	} else {
+		check["TCP"] = service.Check.TCP
+	}
+
+	if service.Check.DeregisterAfter > 0 {
+		check["DeregisterCriticalServiceAfter"] = service.Check.DeregisterAfter.String()
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"ID":      service.ID,
+		"Name":    service.Name,
+		"Address": service.Address,
+		"Port":    service.Port,
+		"Tags":    service.Tags,
+		"Meta":    service.Meta,
+		"Check":   check,
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.put(ctx, "/v1/agent/service/register?replace-existing-checks=true", body)
+}
+
+// Deregister removes the service from the agent.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - id: The ID of the registered service.
+//
+// Returns:
+//   - An error if the agent cannot be reached or rejects the request.
+func (r *Registry) Deregister(ctx context.Context, id string) error {
+	return r.put(ctx, "/v1/agent/service/deregister/"+url.PathEscape(id), nil)
+}
+
+// put sends a PUT request to the agent.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - path: The path and the query of the request.
+//   - body: The JSON body of the request, or nil.
+//
+// Returns:
+//   - An error if the request fails or the agent does not respond with 200 OK.
+func (r *Registry) put(ctx context.Context, path string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, r.address+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if r.token != "" {
+		req.Header.Set("X-Consul-Token", r.token)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// The agent explains the rejections in the body.
+	const maxReason = 512
+
+	if resp.StatusCode != http.StatusOK {
+		reason, _ := io.ReadAll(io.LimitReader(resp.Body, maxReason))
+
+		return fmt.Errorf("%w: %s: %s", ErrUnexpectedStatus, resp.Status, strings.TrimSpace(string(reason)))
+	}
+
+	// Drain the body, so the connection is reused.
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package consul_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/consul"
+)
+
+// RegistryTestSuite represents the test suite for the registration in Consul.
+type RegistryTestSuite struct {
+	suite.Suite
+
+	server   *httptest.Server
+	services map[string]map[string]any
+}
+
+// SetupTest starts a fake Consul agent accepting the token "acl".
+func (suite *RegistryTestSuite) SetupTest() {
+	suite.services = make(map[string]map[string]any)
+
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("PUT /v1/agent/service/register", func(w http.ResponseWriter, r *http.Request) {
+		var service map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&service); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		id, _ := service["ID"].(string)
+		suite.services[id] = service
+	})
+
+	mux.HandleFunc("PUT /v1/agent/service/deregister/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := suite.services[r.PathValue("id")]; !ok {
+			http.Error(w, "Unknown service ID", http.StatusNotFound)
+
+			return
+		}
+
+		delete(suite.services, r.PathValue("id"))
+	})
+
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Consul-Token") != "acl" {
+			http.Error(w, "Permission denied", http.StatusForbidden)
+
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	}))
+}
+
+// TearDownTest stops the fake Consul agent.
+func (suite *RegistryTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+// TestRegistry_Register verifies that the service is registered with its check and deregistered.
+func (suite *RegistryTestSuite) TestRegistry_Register() {
+	registry := consul.NewRegistry(suite.server.URL+"/", consul.WithToken("acl"))
+
+	err := registry.Register(context.Background(), consul.Service{
+		ID:   "vakeel-way-pod-1",
+		Name: "vakeel-way",
+		Port: 4643,
+		Tags: []string{"grpc"},
+		Meta: map[string]string{"version": "1.2.3"},
+		Check: consul.Check{
+			HTTP:            "http://10.0.0.5:8080/readyz",
+			Interval:        10 * time.Second,
+			Timeout:         2 * time.Second,
+			DeregisterAfter: time.Minute,
+		},
+	})
+	suite.Require().NoError(err)
+
+	service := suite.services["vakeel-way-pod-1"]
+	suite.Require().NotNil(service)
+	suite.Equal("vakeel-way", service["Name"])
+	suite.InDelta(4643, service["Port"], 0)
+	suite.Equal(map[string]any{
+		"HTTP":                           "http://10.0.0.5:8080/readyz",
+		"Interval":                       "10s",
+		"Timeout":                        "2s",
+		"DeregisterCriticalServiceAfter": "1m0s",
+	}, service["Check"])
+
+	suite.Require().NoError(registry.Deregister(context.Background(), "vakeel-way-pod-1"))
+	suite.Empty(suite.services)
+
+	err = registry.Deregister(context.Background(), "vakeel-way-pod-1")
+	suite.Require().ErrorIs(err, consul.ErrUnexpectedStatus)
+	suite.Contains(err.Error(), "Unknown service ID")
+}
+
+// TestRegistry_TCPCheck verifies that the TCP check is registered without an HTTP URL.
+func (suite *RegistryTestSuite) TestRegistry_TCPCheck() {
+	registry := consul.NewRegistry(suite.server.URL, consul.WithToken("acl"))
+
+	err := registry.Register(context.Background(), consul.Service{
+		ID:    "vakeel-way",
+		Name:  "vakeel-way",
+		Port:  4643,
+		Check: consul.Check{TCP: "127.0.0.1:4643", Interval: 10 * time.Second, Timeout: time.Second},
+	})
+	suite.Require().NoError(err)
+
+	suite.Equal(map[string]any{"TCP": "127.0.0.1:4643", "Interval": "10s", "Timeout": "1s"}, suite.services["vakeel-way"]["Check"])
+}
+
+// TestRegistry_Forbidden verifies that the rejected registrations are reported.
+func (suite *RegistryTestSuite) TestRegistry_Forbidden() {
+	err := consul.NewRegistry(suite.server.URL).Register(context.Background(), consul.Service{ID: "vakeel-way", Name: "vakeel-way"})
+	suite.Require().ErrorIs(err, consul.ErrUnexpectedStatus)
+	suite.Empty(suite.services)
+}
+
+// TestRegistryTestSuite runs the test suite for the registration in Consul.
+func TestRegistryTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(RegistryTestSuite))
+}
@@ -0,0 +1,328 @@
+package deadletter
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/atomicfile"
+)
+
+// letter is the persisted representation of an entities.DeadLetter.
+type letter struct {
+	ID       uuid.UUID `json:"id"`
+	Target   string    `json:"target"`
+	Event    event     `json:"event"`
+	Error    string    `json:"error,omitempty"`
+	Attempts int       `json:"attempts,omitempty"`
+	At       time.Time `json:"at"`
+}
+
+// event is the persisted representation of an entities.Event.
+type event struct {
+	ID          uuid.UUID         `json:"id"`
+	Status      string            `json:"status"`
+	Time        time.Time         `json:"time"`
+	Since       time.Time         `json:"since"`
+	LastSeen    time.Time         `json:"last_seen"`
+	Message     string            `json:"message,omitempty"`
+	Reason      string            `json:"reason,omitempty"`
+	Cause       uuid.UUID         `json:"cause,omitempty"`
+	Missed      []event           `json:"missed,omitempty"`
+	Unreachable bool              `json:"unreachable,omitempty"`
+	Warning     bool              `json:"warning,omitempty"`
+	Hostname    string            `json:"hostname,omitempty"`
+	Version     string            `json:"version,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	RequestID   string            `json:"request_id,omitempty"`
+}
+
+// Store keeps the notifications that could not be delivered.
+//
+// The dead letters are kept in memory and, if a path is given, are written to
+// a JSON file after every change, so they survive a restart of the process.
+// The file is replaced atomically. The store is bounded: the oldest dead
+// letters are dropped once the limit is reached, and counted.
+type Store struct {
+	// path is the path to the file of the store, or empty for an in-memory store.
+	path string
+
+	// size is the maximum number of the dead letters kept.
+	size int
+
+	// letters are the dead letters, oldest first.
+	letters []letter
+
+	// mu is the mutex used to synchronize access to the dead letters.
+	mu sync.Mutex
+
+	// added is the number of the dead letters added since the start.
+	added atomic.Uint64
+
+	// dropped is the number of the dead letters dropped because the store was full.
+	dropped atomic.Uint64
+}
+
+// Open creates a new instance of the Store struct and loads the dead letters.
+//
+// Parameters:
+//   - path: The path to the file of the store. If it is empty, the dead letters
+//     are kept in memory only.
+//   - size: The maximum number of the dead letters kept. If it is below 1,
+//     1000 dead letters are kept.
+//
+// Returns:
+//   - A pointer to the initialized Store.
+//   - An error if the file exists but cannot be read.
+//
+//nolint:exhaustruct
+func Open(path string, size int) (*Store, error) {
+	const defaultSize = 1000
+
+	if size < 1 {
+		size = defaultSize
+	}
+
+	store := &Store{path: path, size: size}
+
+	if path == "" {
+		return store, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &store.letters); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// Add keeps the dead letter.
+//
+// A random identifier is assigned to the dead letter if it has none, and the
+// current time if it has no time.
+//
+// Parameters:
+//   - dead: The entities.DeadLetter to keep.
+//
+// Returns:
+//   - An error if the store cannot be persisted.
+func (s *Store) Add(dead entities.DeadLetter) error {
+	if dead.ID == uuid.Nil {
+		dead.ID = uuid.New()
+	}
+
+	if dead.At.IsZero() {
+		dead.At = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.letters = append(s.letters, encodeLetter(dead))
+	s.added.Add(1)
+
+	// Drop the oldest dead letters.
+	if excess := len(s.letters) - s.size; excess > 0 {
+		s.letters = slices.Delete(s.letters, 0, excess)
+		s.dropped.Add(uint64(excess))
+	}
+
+	return s.persist()
+}
+
+// List returns the dead letters, oldest first.
+func (s *Store) List() []entities.DeadLetter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	letters := make([]entities.DeadLetter, 0, len(s.letters))
+	for _, l := range s.letters {
+		letters = append(letters, l.decode())
+	}
+
+	return letters
+}
+
+// Get returns the dead letter.
+//
+// Parameters:
+//   - id: The identifier of the dead letter.
+//
+// Returns:
+//   - The dead letter, and whether it exists.
+func (s *Store) Get(id uuid.UUID) (entities.DeadLetter, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := s.index(id)
+	if idx < 0 {
+		return entities.DeadLetter{}, false //nolint:exhaustruct
+	}
+
+	return s.letters[idx].decode(), true
+}
+
+// Remove removes the dead letter.
+//
+// Parameters:
+//   - id: The identifier of the dead letter.
+//
+// Returns:
+//   - Whether the dead letter existed.
+//   - An error if the store cannot be persisted.
+func (s *Store) Remove(id uuid.UUID) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	idx := s.index(id)
+	if idx < 0 {
+		return false, nil
+	}
+
+	s.letters = slices.Delete(s.letters, idx, idx+1)
+
+	return true, s.persist()
+}
+
+// Len returns the number of the dead letters kept.
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.letters)
+}
+
+// Added returns the number of the dead letters added since the start.
+func (s *Store) Added() uint64 {
+	return s.added.Load()
+}
+
+// Dropped returns the number of the dead letters dropped because the store was full.
+func (s *Store) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// index returns the index of the dead letter, or -1. The caller must hold the mutex.
+func (s *Store) index(id uuid.UUID) int {
+	return slices.IndexFunc(s.letters, func(l letter) bool {
+		return l.ID == id
+	})
+}
+
+// persist writes the dead letters to the file. The caller must hold the mutex.
+func (s *Store) persist() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(s.letters)
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(s.path, data)
+}
+
+// encodeLetter returns the persisted representation of the dead letter.
+func encodeLetter(dead entities.DeadLetter) letter {
+	return letter{
+		ID:       dead.ID,
+		Target:   dead.Target,
+		Event:    encodeEvent(dead.Event),
+		Error:    dead.Error,
+		Attempts: dead.Attempts,
+		At:       dead.At,
+	}
+}
+
+// decode returns the entities.DeadLetter of the persisted representation.
+func (l letter) decode() entities.DeadLetter {
+	return entities.DeadLetter{
+		ID:       l.ID,
+		Target:   l.Target,
+		Event:    l.Event.decode(),
+		Error:    l.Error,
+		Attempts: l.Attempts,
+		At:       l.At,
+	}
+}
+
+// encodeEvent returns the persisted representation of the event.
+func encodeEvent(e entities.Event) event {
+	var missed []event
+
+	for _, m := range e.Missed {
+		missed = append(missed, encodeEvent(m))
+	}
+
+	return event{
+		ID:          e.ID,
+		Status:      e.Status.String(),
+		Time:        e.Time,
+		Since:       e.Since,
+		LastSeen:    e.LastSeen,
+		Message:     e.Message,
+		Reason:      e.Reason,
+		Cause:       e.Cause,
+		Missed:      missed,
+		Unreachable: e.Unreachable,
+		Warning:     e.Warning(),
+		Hostname:    e.Agent.Hostname,
+		Version:     e.Agent.Version,
+		Labels:      e.Agent.Labels,
+		RequestID:   e.RequestID,
+	}
+}
+
+// decode returns the entities.Event of the persisted representation.
+func (e event) decode() entities.Event {
+	var missed []entities.Event
+
+	for _, m := range e.Missed {
+		missed = append(missed, m.decode())
+	}
+
+	// The events are written by encodeEvent, so the status is always valid.
+	status, _ := entities.ParseStatus(e.Status)
+
+	kind := entities.KindTransition
+	if e.Warning {
+		kind = entities.KindWarning
+	}
+
+	return entities.Event{
+		Kind:        kind,
+		ID:          e.ID,
+		Status:      status,
+		Time:        e.Time,
+		Since:       e.Since,
+		LastSeen:    e.LastSeen,
+		Message:     e.Message,
+		Reason:      e.Reason,
+		Cause:       e.Cause,
+		Missed:      missed,
+		Unreachable: e.Unreachable,
+		Agent: entities.Agent{
+			Hostname: e.Hostname,
+			Version:  e.Version,
+			Labels:   e.Labels,
+		},
+		RequestID: e.RequestID,
+	}
+}
@@ -0,0 +1,89 @@
+package deadletter_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/deadletter"
+)
+
+// StoreTestSuite represents the test suite for the dead letter store.
+type StoreTestSuite struct {
+	suite.Suite
+}
+
+// TestStore_Persist verifies that the dead letters survive reopening the store
+// and that the removed ones are forgotten.
+func (suite *StoreTestSuite) TestStore_Persist() {
+	path := filepath.Join(suite.T().TempDir(), "dead-letters.json")
+	id := uuid.New()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	store, err := deadletter.Open(path, 0)
+	suite.Require().NoError(err)
+	suite.Require().NoError(store.Add(entities.DeadLetter{
+		Target:   "slack",
+		Event:    entities.Event{ID: id, Status: entities.Down, Time: now, Agent: entities.Agent{Hostname: "node-1"}},
+		Error:    "503 Service Unavailable",
+		Attempts: 20,
+	}))
+
+	reopened, err := deadletter.Open(path, 0)
+	suite.Require().NoError(err)
+
+	letters := reopened.List()
+	suite.Require().Len(letters, 1)
+	suite.NotEqual(uuid.Nil, letters[0].ID)
+	suite.False(letters[0].At.IsZero())
+	suite.Equal("slack", letters[0].Target)
+	suite.Equal(id, letters[0].Event.ID)
+	suite.Equal(entities.Down, letters[0].Event.Status)
+	suite.True(now.Equal(letters[0].Event.Time))
+	suite.Equal("node-1", letters[0].Event.Agent.Hostname)
+	suite.Equal("503 Service Unavailable", letters[0].Error)
+	suite.Equal(20, letters[0].Attempts)
+
+	letter, ok := reopened.Get(letters[0].ID)
+	suite.True(ok)
+	suite.Equal(letters[0], letter)
+
+	removed, err := reopened.Remove(letters[0].ID)
+	suite.Require().NoError(err)
+	suite.True(removed)
+
+	removed, err = reopened.Remove(letters[0].ID)
+	suite.Require().NoError(err)
+	suite.False(removed)
+
+	reopened, err = deadletter.Open(path, 0)
+	suite.Require().NoError(err)
+	suite.Equal(0, reopened.Len())
+}
+
+// TestStore_Bounded verifies that the oldest dead letters are dropped and counted.
+func (suite *StoreTestSuite) TestStore_Bounded() {
+	store, err := deadletter.Open("", 2)
+	suite.Require().NoError(err)
+
+	for _, target := range []string{"a", "b", "c"} {
+		suite.Require().NoError(store.Add(entities.DeadLetter{Target: target}))
+	}
+
+	letters := store.List()
+	suite.Require().Len(letters, 2)
+	suite.Equal("b", letters[0].Target)
+	suite.Equal("c", letters[1].Target)
+	suite.Equal(uint64(3), store.Added())
+	suite.Equal(uint64(1), store.Dropped())
+}
+
+// TestStoreTestSuite runs the test suite for the dead letter store.
+func TestStoreTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(StoreTestSuite))
+}
@@ -0,0 +1,97 @@
+package diagnostics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/bavix/vakeel-way/internal/infra/srv"
+)
+
+// ErrNoHost is an error that indicates that the URL has no host.
+var ErrNoHost = errors.New("diagnostics: the URL has no host")
+
+// Bindable checks that the address can be listened on, e.g. that no other
+// process uses the port.
+//
+// Parameters:
+//   - network: The network of the address, e.g. "tcp" or "udp".
+//   - address: The address to listen on.
+//
+// Returns:
+//   - An error if the address cannot be listened on.
+func Bindable(network, address string) error {
+	if strings.HasPrefix(network, "udp") {
+		conn, err := net.ListenPacket(network, address)
+		if err != nil {
+			return err
+		}
+
+		return conn.Close()
+	}
+
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return err
+	}
+
+	return listener.Close()
+}
+
+// Reachable checks that the host of the URL resolves and accepts TCP
+// connections, without sending any request. The hosts of the "http+srv" and
+// "https+srv" URLs are checked through a target of their SRV records.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the check, e.g. on timeout.
+//   - rawURL: The URL to check.
+//
+// Returns:
+//   - An error if the URL is invalid, the host does not resolve, or the
+//     connection is refused.
+func Reachable(ctx context.Context, rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	host := u.Hostname()
+	if host == "" {
+		return fmt.Errorf("%w: %s", ErrNoHost, u.Redacted())
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = u.Scheme
+	}
+
+	// The hosts of the SRV URLs are the names of the records of the receivers.
+	if srv.IsSRV(u.Scheme) {
+		address, err := srv.NewTransport(http.DefaultTransport).Pick(ctx, host)
+		if err != nil {
+			return err
+		}
+
+		if host, port, err = net.SplitHostPort(address); err != nil {
+			return err
+		}
+	}
+
+	// Resolve the host first, so a DNS failure is told apart from a refused connection.
+	if _, err := net.DefaultResolver.LookupHost(ctx, host); err != nil {
+		return err
+	}
+
+	var dialer net.Dialer
+
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, port))
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
@@ -0,0 +1,52 @@
+package diagnostics_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/diagnostics"
+)
+
+// DiagnosticsTestSuite represents the test suite for the diagnostics.
+type DiagnosticsTestSuite struct {
+	suite.Suite
+}
+
+// TestBindable verifies that an address in use is reported.
+func (suite *DiagnosticsTestSuite) TestBindable() {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+
+	address := listener.Addr().String()
+	suite.Require().Error(diagnostics.Bindable("tcp", address))
+
+	suite.Require().NoError(listener.Close())
+	suite.Require().NoError(diagnostics.Bindable("tcp", address))
+}
+
+// TestReachable verifies that the listening hosts are reachable and the
+// others are reported.
+func (suite *DiagnosticsTestSuite) TestReachable() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	suite.Require().NoError(diagnostics.Reachable(ctx, url+"/webhook"))
+
+	server.Close()
+	suite.Require().Error(diagnostics.Reachable(ctx, url+"/webhook"))
+	suite.Require().ErrorIs(diagnostics.Reachable(ctx, "/webhook"), diagnostics.ErrNoHost)
+}
+
+// TestDiagnosticsTestSuite runs the test suite for the diagnostics.
+func TestDiagnosticsTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(DiagnosticsTestSuite))
+}
@@ -0,0 +1,175 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// Formats of the exported downtimes.
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// ErrUnknownFormat is an error that indicates that the export format is unknown.
+var ErrUnknownFormat = errors.New("export: unknown format")
+
+// Downtime is a row of the exported downtimes.
+type Downtime struct {
+	// ID is the UUID of the service.
+	ID uuid.UUID `json:"id"`
+
+	// Started is the time the service went down.
+	Started time.Time `json:"started"`
+
+	// Resolved is the time the service recovered, or nil if it is still down.
+	Resolved *time.Time `json:"resolved,omitempty"`
+
+	// Duration is the length of the incident in seconds, up to now if it is ongoing.
+	Duration float64 `json:"duration_seconds"`
+
+	// Downtime is the length of the part of the incident within the period in seconds.
+	//
+	// It is the figure to subtract from the period for an SLA report, as the
+	// incidents overlapping the edges of the period are only counted partly.
+	Downtime float64 `json:"downtime_seconds"`
+}
+
+// ContentType returns the MIME type of the format.
+//
+// Parameters:
+//   - format: The export format, "csv" or "json".
+//
+// Returns:
+//   - The MIME type of the format.
+//   - ErrUnknownFormat if the format is unknown.
+func ContentType(format string) (string, error) {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8", nil
+	case FormatJSON:
+		return "application/json", nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// Downtimes converts the incidents into the rows of the downtime report of the period.
+//
+// Parameters:
+//   - incidents: The incidents overlapping the period.
+//   - from: The beginning of the period.
+//   - to: The end of the period.
+//   - now: The current time, which ends the ongoing incidents.
+//
+// Returns:
+//   - The rows of the report, in the order of the incidents.
+//
+//nolint:exhaustruct
+func Downtimes(incidents []entities.Incident, from, to, now time.Time) []Downtime {
+	rows := make([]Downtime, 0, len(incidents))
+
+	for _, incident := range incidents {
+		row := Downtime{
+			ID:       incident.ID,
+			Started:  incident.Start,
+			Duration: incident.Duration(now).Seconds(),
+		}
+
+		end := now
+		if !incident.Ongoing() {
+			resolved := incident.End
+			row.Resolved, end = &resolved, resolved
+		}
+
+		// The part of the incident within the period.
+		start := incident.Start
+		if start.Before(from) {
+			start = from
+		}
+
+		if end.After(to) {
+			end = to
+		}
+
+		if end.After(start) {
+			row.Downtime = end.Sub(start).Seconds()
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows
+}
+
+// Write writes the rows of the downtime report in the format.
+//
+// The CSV has a header row and the columns id, started, resolved,
+// duration_seconds and downtime_seconds, with the times in RFC 3339 format
+// and an empty resolved column for the ongoing incidents. The JSON is an
+// array of the rows.
+//
+// Parameters:
+//   - w: The writer of the report.
+//   - format: The export format, "csv" or "json".
+//   - rows: The rows of the report.
+//
+// Returns:
+//   - ErrUnknownFormat if the format is unknown.
+//   - An error if the report cannot be written.
+func Write(w io.Writer, format string, rows []Downtime) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(rows)
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+		_ = writer.Write([]string{"id", "started", "resolved", "duration_seconds", "downtime_seconds"})
+
+		for _, row := range rows {
+			resolved := ""
+			if row.Resolved != nil {
+				resolved = row.Resolved.UTC().Format(time.RFC3339)
+			}
+
+			_ = writer.Write([]string{
+				row.ID.String(),
+				row.Started.UTC().Format(time.RFC3339),
+				resolved,
+				strconv.FormatFloat(row.Duration, 'f', 0, 64),
+				strconv.FormatFloat(row.Downtime, 'f', 0, 64),
+			})
+		}
+
+		writer.Flush()
+
+		return writer.Error()
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// PreviousMonth returns the period of the calendar month before the one of now.
+//
+// Parameters:
+//   - now: The current time, whose location is the location of the period.
+//
+// Returns:
+//   - The first moment of the previous month.
+//   - The first moment of the month of now, which ends the period.
+func PreviousMonth(now time.Time) (time.Time, time.Time) {
+	to := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	return to.AddDate(0, -1, 0), to
+}
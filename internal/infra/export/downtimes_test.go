@@ -0,0 +1,76 @@
+package export_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/export"
+)
+
+// DowntimesTestSuite represents the test suite for the export of the downtimes.
+type DowntimesTestSuite struct {
+	suite.Suite
+}
+
+// TestDowntimes_Period verifies that only the part of the incidents within the period is counted.
+func (suite *DowntimesTestSuite) TestDowntimes_Period() {
+	id := uuid.New()
+	from := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	now := to.Add(time.Hour)
+
+	rows := export.Downtimes([]entities.Incident{
+		{ID: id, Start: to.Add(-time.Hour), End: time.Time{}},
+		{ID: id, Start: from.Add(time.Hour), End: from.Add(90 * time.Minute)},
+		{ID: id, Start: from.Add(-time.Hour), End: from.Add(time.Minute)},
+	}, from, to, now)
+
+	suite.Require().Len(rows, 3)
+	suite.Nil(rows[0].Resolved)
+	suite.InDelta(7200, rows[0].Duration, 0)
+	suite.InDelta(3600, rows[0].Downtime, 0)
+	suite.InDelta(1800, rows[1].Downtime, 0)
+	suite.InDelta(3660, rows[2].Duration, 0)
+	suite.InDelta(60, rows[2].Downtime, 0)
+}
+
+// TestDowntimes_CSV verifies the columns of the CSV report.
+func (suite *DowntimesTestSuite) TestDowntimes_CSV() {
+	id := uuid.New()
+	start := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+
+	rows := export.Downtimes([]entities.Incident{{ID: id, Start: start, End: start.Add(time.Minute)}},
+		start.Add(-time.Hour), start.Add(time.Hour), start.Add(time.Hour))
+
+	var buf bytes.Buffer
+	suite.Require().NoError(export.Write(&buf, export.FormatCSV, rows))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	suite.Require().NoError(err)
+	suite.Equal([][]string{
+		{"id", "started", "resolved", "duration_seconds", "downtime_seconds"},
+		{id.String(), "2024-09-01T12:00:00Z", "2024-09-01T12:01:00Z", "60", "60"},
+	}, records)
+
+	suite.Require().ErrorIs(export.Write(&buf, "xml", rows), export.ErrUnknownFormat)
+}
+
+// TestPreviousMonth verifies the period of the monthly report.
+func (suite *DowntimesTestSuite) TestPreviousMonth() {
+	from, to := export.PreviousMonth(time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC))
+
+	suite.Equal(time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC), from)
+	suite.Equal(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), to)
+}
+
+// TestDowntimesTestSuite runs the test suite for the export of the downtimes.
+func TestDowntimesTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(DowntimesTestSuite))
+}
@@ -0,0 +1,601 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/atomicfile"
+)
+
+// Resolutions of the aggregates.
+const (
+	// Hour is the resolution of the hourly aggregates.
+	Hour = time.Hour
+
+	// Day is the resolution of the daily aggregates.
+	Day = 24 * time.Hour
+)
+
+// ErrRetention is an error that indicates that the retention periods are too short to downsample the history.
+var ErrRetention = errors.New("history: raw retention must be at least 2h and hourly retention at least 48h")
+
+// Retention defines how long each resolution of the history is kept.
+type Retention struct {
+	// Raw is the retention of the raw status transitions.
+	Raw time.Duration
+
+	// Hourly is the retention of the hourly aggregates.
+	Hourly time.Duration
+
+	// Daily is the retention of the daily aggregates.
+	Daily time.Duration
+}
+
+// Transition is a raw status transition of a service.
+type Transition struct {
+	// Status is the new status of the service.
+	Status entities.Status `json:"status"`
+
+	// Time is the time of the transition.
+	Time time.Time `json:"time"`
+}
+
+// Downtime is an interval the service was down.
+type Downtime struct {
+	// Start is the time the service went down.
+	Start time.Time `json:"start"`
+
+	// End is the time the service recovered, or zero if it is still down.
+	End time.Time `json:"end,omitempty"`
+}
+
+// Bucket is an aggregate of the uptime of a service over an hour or a day.
+type Bucket struct {
+	// Start is the beginning of the period of the bucket.
+	Start time.Time `json:"start"`
+
+	// Up is the time the service was up within the period.
+	Up time.Duration `json:"up"`
+
+	// Total is the time the status of the service was known within the period.
+	Total time.Duration `json:"total"`
+}
+
+// series is the history of a single service.
+type series struct {
+	// Raw is the list of the raw transitions, oldest first.
+	Raw []Transition `json:"raw"`
+
+	// Hourly is the list of the hourly aggregates, oldest first.
+	Hourly []Bucket `json:"hourly"`
+
+	// Daily is the list of the daily aggregates, oldest first.
+	Daily []Bucket `json:"daily"`
+
+	// Cursor is the time up to which the raw transitions are aggregated into the hourly buckets.
+	Cursor time.Time `json:"cursor"`
+
+	// Status is the status of the service at the cursor.
+	Status entities.Status `json:"status"`
+
+	// DailyCursor is the time up to which the hourly buckets are aggregated into the daily buckets.
+	DailyCursor time.Time `json:"daily_cursor"`
+
+	// Seen is the time of the last heartbeat of the service.
+	Seen time.Time `json:"seen,omitempty"`
+
+	// Watched is the time the service was first watched, whether or not it has
+	// ever sent a heartbeat.
+	Watched time.Time `json:"watched,omitempty"`
+
+	// Downtimes is the list of the downtimes, oldest first. They are kept for
+	// the retention of the daily aggregates, unlike the raw transitions.
+	Downtimes []Downtime `json:"downtimes,omitempty"`
+}
+
+// Store keeps the status history of the services.
+//
+// The raw status transitions are downsampled in the background into hourly and
+// daily aggregates of the uptime, so long-range uptime queries only touch a few
+// hundred buckets. Every resolution has its own retention, which keeps the
+// storage bounded. If a path is given, the history is written to a JSON file
+// after every downsampling and is loaded on startup.
+type Store struct {
+	// path is the path to the file of the history, or empty for an in-memory history.
+	path string
+
+	// retention defines how long each resolution is kept.
+	retention Retention
+
+	// series maps the UUIDs of the services to their history.
+	series map[uuid.UUID]*series
+
+	// mu is the mutex used to synchronize access to the series.
+	mu sync.RWMutex
+}
+
+// Open creates a new instance of the Store struct and loads the history.
+//
+// Parameters:
+//   - path: The path to the file of the history. If it is empty, the history is
+//     kept in memory only.
+//   - retention: The retention of each resolution.
+//
+// Returns:
+//   - A pointer to the initialized Store.
+//   - ErrRetention if the retention periods are too short.
+//   - An error if the file exists but cannot be read.
+func Open(path string, retention Retention) (*Store, error) {
+	// The raw transitions must outlive the aggregation lag, and the hourly buckets
+	// must outlive the aggregation into the daily buckets.
+	if retention.Raw < 2*Hour || retention.Hourly < 2*Day {
+		return nil, ErrRetention
+	}
+
+	store := &Store{
+		path:      path,
+		retention: retention,
+		series:    make(map[uuid.UUID]*series),
+		mu:        sync.RWMutex{},
+	}
+
+	if path == "" {
+		return store, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &store.series); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// Record appends the status transition of the service.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//   - status: The new status of the service.
+//   - at: The time of the transition.
+func (s *Store) Record(id uuid.UUID, status entities.Status, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ser := s.series[id]
+	if ser == nil {
+		ser = newSeries(status, at)
+		s.series[id] = ser
+	}
+
+	// The transitions of the already aggregated period cannot be applied anymore.
+	if !ser.Cursor.IsZero() && at.Before(ser.Cursor) {
+		at = ser.Cursor
+	}
+
+	ser.Raw = append(ser.Raw, Transition{Status: status, Time: at})
+
+	// Open a downtime when the service goes down, and close it once it is not down anymore.
+	open := len(ser.Downtimes) > 0 && ser.Downtimes[len(ser.Downtimes)-1].End.IsZero()
+
+	switch {
+	case status == entities.Down && !open:
+		ser.Downtimes = append(ser.Downtimes, Downtime{Start: at, End: time.Time{}})
+	case status != entities.Down && open:
+		ser.Downtimes[len(ser.Downtimes)-1].End = at
+	}
+}
+
+// Incidents returns the downtimes of the services matching the filter, most
+// recent first. The incidents that started at the same time are ordered by
+// the UUIDs of their services.
+//
+// Parameters:
+//   - filter: The criteria of the incidents.
+//
+// Returns:
+//   - The matching incidents.
+func (s *Store) Incidents(filter entities.IncidentFilter) []entities.Incident {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+
+	var incidents []entities.Incident
+
+	for id, ser := range s.series {
+		for _, downtime := range ser.Downtimes {
+			incident := entities.Incident{ID: id, Start: downtime.Start, End: downtime.End}
+			if filter.Matches(incident, now) {
+				incidents = append(incidents, incident)
+			}
+		}
+	}
+
+	sort.Slice(incidents, func(i, j int) bool {
+		if !incidents[i].Start.Equal(incidents[j].Start) {
+			return incidents[i].Start.After(incidents[j].Start)
+		}
+
+		return incidents[i].ID.String() < incidents[j].ID.String()
+	})
+
+	return incidents
+}
+
+// Seen records the last heartbeat of the service.
+//
+// The time is persisted with the history, so the services that have not sent
+// a heartbeat for a long time can be told apart across restarts.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//   - at: The time of the heartbeat.
+func (s *Store) Seen(id uuid.UUID, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ser := s.series[id]
+	if ser == nil {
+		ser = newSeries(entities.Up, at)
+		s.series[id] = ser
+	}
+
+	if at.After(ser.Seen) {
+		ser.Seen = at
+	}
+}
+
+// Watch records the time the service is first watched, if it is unknown.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//   - at: The current time.
+func (s *Store) Watch(id uuid.UUID, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch ser := s.series[id]; {
+	case ser == nil:
+		s.series[id] = newSeries(entities.Down, at)
+	case ser.Watched.IsZero():
+		// The history recorded before the services were watched.
+		ser.Watched = at
+	}
+}
+
+// LastSeen returns the time of the last heartbeat of the service.
+//
+// For a service that has never sent a heartbeat, it returns the time the
+// service was first watched.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//
+// Returns:
+//   - The time of the last heartbeat, or the time the service was first watched.
+//   - A boolean indicating whether the service has ever sent a heartbeat.
+func (s *Store) LastSeen(id uuid.UUID) (time.Time, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ser := s.series[id]
+	if ser == nil {
+		return time.Time{}, false
+	}
+
+	if ser.Seen.IsZero() {
+		return ser.Watched, false
+	}
+
+	return ser.Seen, true
+}
+
+// Downsample aggregates the raw transitions into the hourly buckets and the
+// hourly buckets into the daily buckets, and removes the data past its retention.
+//
+// Only the complete hours and days are aggregated.
+//
+// Parameters:
+//   - now: The current time.
+//
+// Returns:
+//   - An error if the history cannot be persisted.
+func (s *Store) Downsample(now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ser := range s.series {
+		ser.downsample(now)
+		ser.prune(now, s.retention)
+	}
+
+	return s.persist()
+}
+
+// Uptime returns the time the service was up and the time its status was known
+// within the period.
+//
+// The daily aggregates are used for the days past the hourly retention, the
+// hourly aggregates for the hours past the aggregation cursor, and the raw
+// transitions for the rest. The aggregates are included if they begin within
+// the period, so the result is precise up to the resolution of the aggregates
+// at the edges of the period. The period is clamped to the current time.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//   - from: The beginning of the period.
+//   - to: The end of the period.
+//
+// Returns:
+//   - The time the service was up.
+//   - The time the status of the service was known.
+func (s *Store) Uptime(id uuid.UUID, from, to time.Time) (time.Duration, time.Duration) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ser := s.series[id]
+	if ser == nil {
+		return 0, 0
+	}
+
+	to = minTime(to, time.Now())
+
+	var up, total time.Duration
+
+	// The hourly buckets begin at the cursor if nothing is aggregated yet.
+	hourly := ser.Cursor
+	if len(ser.Hourly) > 0 {
+		hourly = ser.Hourly[0].Start
+	}
+
+	// Use the daily buckets only for the days entirely before the hourly buckets.
+	for _, bucket := range ser.Daily {
+		if !bucket.Start.Before(from) && bucket.Start.Before(to) && !bucket.Start.Add(Day).After(hourly) {
+			up, total = up+bucket.Up, total+bucket.Total
+		}
+	}
+
+	for _, bucket := range ser.Hourly {
+		if !bucket.Start.Before(from) && bucket.Start.Before(to) {
+			up, total = up+bucket.Up, total+bucket.Total
+		}
+	}
+
+	// Use the raw transitions for the period that is not aggregated yet.
+	start, status := ser.Cursor, ser.Status
+	if start.IsZero() && len(ser.Raw) > 0 {
+		start, status = ser.Raw[0].Time, ser.Raw[0].Status
+	}
+
+	if !start.IsZero() && to.After(start) {
+		rawUp, rawTotal, _ := span(ser.Raw, start, status, maxTime(from, start), to)
+		up, total = up+rawUp, total+rawTotal
+	}
+
+	return up, total
+}
+
+// Run downsamples the history periodically until the context is canceled.
+//
+// The history is downsampled and persisted once more when the context is
+// canceled, so the transitions recorded since the last run are not lost.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the loop.
+//   - interval: The interval between the downsampling runs.
+//   - onError: The function called if the history cannot be persisted.
+func (s *Store) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := s.Downsample(time.Now()); err != nil {
+				onError(err)
+			}
+
+			return
+		case now := <-ticker.C:
+			if err := s.Downsample(now); err != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// newSeries creates the history of a service first watched at the given time.
+func newSeries(status entities.Status, at time.Time) *series {
+	return &series{
+		Raw:         nil,
+		Hourly:      nil,
+		Daily:       nil,
+		Cursor:      time.Time{},
+		Status:      status,
+		DailyCursor: time.Time{},
+		Seen:        time.Time{},
+		Watched:     at,
+		Downtimes:   nil,
+	}
+}
+
+// downsample aggregates the complete hours and days of the series.
+func (ser *series) downsample(now time.Time) {
+	if ser.Cursor.IsZero() {
+		if len(ser.Raw) == 0 {
+			return
+		}
+
+		ser.Cursor, ser.Status = ser.Raw[0].Time, ser.Raw[0].Status
+	}
+
+	// Aggregate the raw transitions into the complete hours.
+	for boundary := now.Truncate(Hour); ser.Cursor.Before(boundary); {
+		start := ser.Cursor.Truncate(Hour)
+		end := start.Add(Hour)
+
+		var up, total time.Duration
+
+		up, total, ser.Status = span(ser.Raw, ser.Cursor, ser.Status, ser.Cursor, end)
+		ser.Hourly = addBucket(ser.Hourly, start, up, total)
+		ser.Cursor = end
+	}
+
+	// Aggregate the hourly buckets into the complete days.
+	boundary := now.Truncate(Day)
+	for _, bucket := range ser.Hourly {
+		if !bucket.Start.Before(ser.DailyCursor) && bucket.Start.Before(boundary) {
+			ser.Daily = addBucket(ser.Daily, bucket.Start.Truncate(Day), bucket.Up, bucket.Total)
+		}
+	}
+
+	if boundary.After(ser.DailyCursor) {
+		ser.DailyCursor = boundary
+	}
+}
+
+// prune removes the data of the series past its retention.
+func (ser *series) prune(now time.Time, retention Retention) {
+	// Keep the raw transitions that are not aggregated yet.
+	rawCutoff := now.Add(-retention.Raw)
+	if ser.Cursor.Before(rawCutoff) {
+		rawCutoff = ser.Cursor
+	}
+
+	ser.Raw = ser.Raw[sort.Search(len(ser.Raw), func(i int) bool {
+		return !ser.Raw[i].Time.Before(rawCutoff)
+	}):]
+
+	ser.Hourly = pruneBuckets(ser.Hourly, now.Add(-retention.Hourly))
+	ser.Daily = pruneBuckets(ser.Daily, now.Add(-retention.Daily))
+
+	// Keep the downtimes that are ongoing or ended within the daily retention.
+	cutoff := now.Add(-retention.Daily)
+	ser.Downtimes = slices.DeleteFunc(ser.Downtimes, func(downtime Downtime) bool {
+		return !downtime.End.IsZero() && downtime.End.Before(cutoff)
+	})
+}
+
+// span computes the uptime within [lo, hi) by walking the transitions from the start.
+//
+// Parameters:
+//   - raw: The transitions, oldest first.
+//   - start: The time the status is known at.
+//   - status: The status at the start.
+//   - lo: The beginning of the measured period, not before the start.
+//   - hi: The end of the measured period.
+//
+// Returns:
+//   - The time the service was up, or degraded, within the period.
+//   - The length of the period.
+//   - The status at the end of the period.
+func span(
+	raw []Transition,
+	start time.Time,
+	status entities.Status,
+	lo, hi time.Time,
+) (time.Duration, time.Duration, entities.Status) {
+	var up time.Duration
+
+	// measure returns the length of [a, b) within [lo, hi).
+	measure := func(a, b time.Time) time.Duration {
+		a, b = maxTime(a, lo), minTime(b, hi)
+		if !b.After(a) {
+			return 0
+		}
+
+		return b.Sub(a)
+	}
+
+	at := start
+
+	for _, transition := range raw {
+		if transition.Time.Before(start) {
+			continue
+		}
+
+		if !transition.Time.Before(hi) {
+			break
+		}
+
+		if status != entities.Down {
+			up += measure(at, transition.Time)
+		}
+
+		at, status = transition.Time, transition.Status
+	}
+
+	if status != entities.Down {
+		up += measure(at, hi)
+	}
+
+	return up, measure(lo, hi), status
+}
+
+// addBucket adds the uptime to the bucket beginning at the start, creating it if needed.
+func addBucket(buckets []Bucket, start time.Time, up, total time.Duration) []Bucket {
+	if n := len(buckets); n > 0 && buckets[n-1].Start.Equal(start) {
+		buckets[n-1].Up += up
+		buckets[n-1].Total += total
+
+		return buckets
+	}
+
+	return append(buckets, Bucket{Start: start, Up: up, Total: total})
+}
+
+// pruneBuckets removes the buckets beginning before the cutoff.
+func pruneBuckets(buckets []Bucket, cutoff time.Time) []Bucket {
+	return buckets[sort.Search(len(buckets), func(i int) bool {
+		return !buckets[i].Start.Before(cutoff)
+	}):]
+}
+
+// maxTime returns the later of the times.
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
+
+// minTime returns the earlier of the times.
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
+
+// persist writes the history to the file. The caller must hold the mutex.
+func (s *Store) persist() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(s.series)
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(s.path, data)
+}
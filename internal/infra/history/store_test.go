@@ -0,0 +1,133 @@
+package history_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/history"
+)
+
+// HistoryTestSuite represents the test suite for the history functionality.
+type HistoryTestSuite struct {
+	suite.Suite
+}
+
+// retention is the retention used by the tests.
+var retention = history.Retention{Raw: 2 * history.Hour, Hourly: 2 * history.Day, Daily: 365 * history.Day}
+
+// TestStore_Downsample verifies that the uptime is preserved by the downsampling
+// and that the raw data is pruned.
+func (suite *HistoryTestSuite) TestStore_Downsample() {
+	store, err := history.Open("", retention)
+	suite.Require().NoError(err)
+
+	id := uuid.New()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Up for 30 minutes every 2 hours during 10 days.
+	for t := start; t.Before(start.Add(10 * history.Day)); t = t.Add(2 * history.Hour) {
+		store.Record(id, entities.Up, t)
+		store.Record(id, entities.Down, t.Add(30*time.Minute))
+	}
+
+	end := start.Add(10 * history.Day)
+
+	up, total := store.Uptime(id, start, end)
+	suite.Equal(10*12*30*time.Minute, up)
+	suite.Equal(10*history.Day, total)
+
+	// Downsample a few times as the time goes by.
+	for now := start; !now.After(end.Add(history.Day)); now = now.Add(history.Hour) {
+		suite.Require().NoError(store.Downsample(now))
+	}
+
+	// The raw data of the first days is gone, but the uptime is preserved.
+	up, total = store.Uptime(id, start, end)
+	suite.Equal(10*12*30*time.Minute, up)
+	suite.Equal(10*history.Day, total)
+
+	// A single day from the daily aggregates.
+	up, total = store.Uptime(id, start.Add(history.Day), start.Add(2*history.Day))
+	suite.Equal(12*30*time.Minute, up)
+	suite.Equal(history.Day, total)
+}
+
+// TestStore_Persist verifies that the history survives reopening the store.
+func (suite *HistoryTestSuite) TestStore_Persist() {
+	path := filepath.Join(suite.T().TempDir(), "history.json")
+	id := uuid.New()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store, err := history.Open(path, retention)
+	suite.Require().NoError(err)
+
+	store.Record(id, entities.Up, start)
+	store.Record(id, entities.Down, start.Add(3*history.Hour))
+	suite.Require().NoError(store.Downsample(start.Add(5 * history.Hour)))
+
+	reopened, err := history.Open(path, retention)
+	suite.Require().NoError(err)
+
+	up, total := reopened.Uptime(id, start, start.Add(5*history.Hour))
+	suite.Equal(3*history.Hour, up)
+	suite.Equal(5*history.Hour, total)
+}
+
+// TestStore_Incidents verifies that the downtimes are kept past the raw
+// retention and filtered by service, period and duration.
+func (suite *HistoryTestSuite) TestStore_Incidents() {
+	store, err := history.Open("", retention)
+	suite.Require().NoError(err)
+
+	first, second := uuid.New(), uuid.New()
+	start := time.Now().Add(-3 * history.Day).Truncate(history.Hour)
+
+	store.Record(first, entities.Up, start)
+	store.Record(first, entities.Down, start.Add(history.Hour))
+	store.Record(first, entities.Degraded, start.Add(history.Hour+5*time.Minute))
+	store.Record(first, entities.Down, start.Add(2*history.Hour))
+	store.Record(second, entities.Down, start.Add(3*history.Hour))
+	store.Record(second, entities.Down, start.Add(4*history.Hour))
+	store.Record(second, entities.Up, start.Add(5*history.Hour))
+	store.Record(first, entities.Up, start.Add(6*history.Hour))
+
+	// The raw transitions are pruned, the downtimes are not.
+	suite.Require().NoError(store.Downsample(time.Now()))
+
+	incidents := store.Incidents(entities.IncidentFilter{})
+	suite.Require().Len(incidents, 3)
+	suite.Equal(second, incidents[0].ID)
+	suite.Equal(2*history.Hour, incidents[0].End.Sub(incidents[0].Start))
+	suite.Equal(first, incidents[1].ID)
+	suite.Equal(4*history.Hour, incidents[1].End.Sub(incidents[1].Start))
+	suite.Equal(5*time.Minute, incidents[2].End.Sub(incidents[2].Start))
+
+	suite.Len(store.Incidents(entities.IncidentFilter{IDs: []uuid.UUID{first}}), 2)
+	suite.Len(store.Incidents(entities.IncidentFilter{MinDuration: time.Hour}), 2)
+	suite.Len(store.Incidents(entities.IncidentFilter{From: start.Add(90 * time.Minute)}), 2)
+	suite.Len(store.Incidents(entities.IncidentFilter{To: start.Add(2 * history.Hour)}), 1)
+
+	// An ongoing incident lasts until now.
+	store.Record(second, entities.Down, time.Now().Add(-time.Minute))
+
+	incidents = store.Incidents(entities.IncidentFilter{IDs: []uuid.UUID{second}})
+	suite.Require().Len(incidents, 2)
+	suite.True(incidents[0].Ongoing())
+}
+
+// TestStore_Retention verifies that too short retention periods are rejected.
+func (suite *HistoryTestSuite) TestStore_Retention() {
+	_, err := history.Open("", history.Retention{Raw: history.Hour, Hourly: history.Day, Daily: history.Day})
+	suite.Require().ErrorIs(err, history.ErrRetention)
+}
+
+// TestHistoryTestSuite runs the history test suite.
+func TestHistoryTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(HistoryTestSuite))
+}
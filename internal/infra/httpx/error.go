@@ -0,0 +1,70 @@
+package httpx
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// MaxErrorBody is the maximum number of bytes of the response body read on failure.
+const MaxErrorBody = 64 << 10
+
+// ResponseError is an error returned when a service responds with a non-2xx status code.
+//
+// It wraps the sentinel error of the client, e.g. the ErrUnexpectedStatus of
+// its package, and carries the error reported by the service, so the failed
+// deliveries are visible in the logs and are retried if they are temporary.
+type ResponseError struct {
+	// Err is the sentinel error of the client wrapped by the error.
+	Err error
+
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// Code is the error code reported by the service, if any.
+	Code string
+
+	// Message is the error message reported by the service, or the raw response body.
+	Message string
+}
+
+// NewResponseError creates a ResponseError from the response, with the
+// response body as the message.
+//
+// Parameters:
+//   - err: The sentinel error of the client.
+//   - resp: The response with a non-2xx status code.
+//
+// Returns:
+//   - A pointer to a ResponseError.
+func NewResponseError(err error, resp *http.Response) *ResponseError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, MaxErrorBody))
+
+	return &ResponseError{Err: err, StatusCode: resp.StatusCode, Code: "", Message: string(bytes.TrimSpace(body))}
+}
+
+// Error returns the description of the error.
+func (e *ResponseError) Error() string {
+	switch {
+	case e.Code != "" && e.Message != "":
+		return fmt.Sprintf("%s: %d: %s: %s", e.Err, e.StatusCode, e.Code, e.Message)
+	case e.Message != "":
+		return fmt.Sprintf("%s: %d: %s", e.Err, e.StatusCode, e.Message)
+	default:
+		return fmt.Sprintf("%s: %d", e.Err, e.StatusCode)
+	}
+}
+
+// Unwrap returns the sentinel error of the client.
+func (e *ResponseError) Unwrap() error {
+	return e.Err
+}
+
+// Temporary reports whether the delivery may succeed if it is retried, i.e.
+// the service failed or throttled the request.
+func (e *ResponseError) Temporary() bool {
+	return e.StatusCode >= http.StatusInternalServerError ||
+		e.StatusCode == http.StatusTooManyRequests ||
+		e.StatusCode == http.StatusRequestTimeout
+}
@@ -0,0 +1,61 @@
+package httpx_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/httpx"
+)
+
+// errTest is the sentinel error of the client under test.
+var errTest = errors.New("test: unexpected response status")
+
+// ErrorTestSuite represents the test suite for the errors of the HTTP responses.
+type ErrorTestSuite struct {
+	suite.Suite
+}
+
+// TestResponseError verifies that the response body is reported and the sentinel error is wrapped.
+func (suite *ErrorTestSuite) TestResponseError() {
+	//nolint:exhaustruct
+	resp := &http.Response{StatusCode: http.StatusBadRequest, Body: io.NopCloser(strings.NewReader(" invalid payload\n"))}
+
+	err := httpx.NewResponseError(errTest, resp)
+	suite.Require().ErrorIs(err, errTest)
+	suite.Equal("test: unexpected response status: 400: invalid payload", err.Error())
+	suite.False(err.Temporary())
+
+	err.Code = "E42"
+	suite.Equal("test: unexpected response status: 400: E42: invalid payload", err.Error())
+
+	err = &httpx.ResponseError{Err: errTest, StatusCode: http.StatusServiceUnavailable, Code: "", Message: ""}
+	suite.Equal("test: unexpected response status: 503", err.Error())
+	suite.True(err.Temporary())
+}
+
+// TestResponseError_Temporary verifies that the failed and throttled requests are temporary.
+func (suite *ErrorTestSuite) TestResponseError_Temporary() {
+	for code, temporary := range map[int]bool{
+		http.StatusRequestTimeout:      true,
+		http.StatusTooManyRequests:     true,
+		http.StatusInternalServerError: true,
+		http.StatusBadGateway:          true,
+		http.StatusUnauthorized:        false,
+		http.StatusNotFound:            false,
+		http.StatusConflict:            false,
+	} {
+		err := &httpx.ResponseError{Err: errTest, StatusCode: code, Code: "", Message: ""}
+		suite.Equal(temporary, err.Temporary(), code)
+	}
+}
+
+// TestErrorTestSuite runs the test suite for the errors of the HTTP responses.
+func TestErrorTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ErrorTestSuite))
+}
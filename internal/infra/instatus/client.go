@@ -3,12 +3,89 @@ package instatus
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
 
 	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/cloudevents"
+	"github.com/bavix/vakeel-way/internal/infra/httpx"
+	"github.com/bavix/vakeel-way/pkg/requestid"
 )
 
+// ErrUnexpectedStatus is an error that indicates that Instatus responded with a non-2xx status code.
+var ErrUnexpectedStatus = errors.New("instatus: unexpected response status")
+
+// FormatCloudEvents is the format of the targets whose body is a CloudEvents 1.0 envelope.
+const FormatCloudEvents = "cloudevents"
+
+// SignatureHeader is the header carrying the signature of the request body.
+const SignatureHeader = "X-Signature"
+
+// ResponseError is an error returned when Instatus responds with a non-2xx status code.
+//
+// It wraps ErrUnexpectedStatus and carries the error reported by Instatus, so
+// the failed deliveries are visible in the logs and are retried.
+type ResponseError = httpx.ResponseError
+
+// errorPayload is the error payload of the Instatus API.
+//
+// The error is reported either as {"message": "..."} or as
+// {"error": {"code": "...", "message": "..."}} or as {"error": "..."}.
+type errorPayload struct {
+	Message string          `json:"message"`
+	Error   json.RawMessage `json:"error"`
+}
+
+// newResponseError creates a ResponseError from the response.
+func newResponseError(resp *http.Response) *ResponseError {
+	rerr := &ResponseError{Err: ErrUnexpectedStatus, StatusCode: resp.StatusCode, Code: "", Message: ""}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, httpx.MaxErrorBody))
+	if err != nil || len(bytes.TrimSpace(body)) == 0 {
+		return rerr
+	}
+
+	// Fall back to the raw body if it is not an Instatus error payload.
+	var payload errorPayload
+	if json.Unmarshal(body, &payload) != nil {
+		rerr.Message = string(bytes.TrimSpace(body))
+
+		return rerr
+	}
+
+	rerr.Message = payload.Message
+
+	var detail struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+
+	var text string
+
+	switch {
+	case json.Unmarshal(payload.Error, &text) == nil:
+		rerr.Message = text
+	case json.Unmarshal(payload.Error, &detail) == nil && (detail.Code != "" || detail.Message != ""):
+		rerr.Code = detail.Code
+		if detail.Message != "" {
+			rerr.Message = detail.Message
+		}
+	}
+
+	if rerr.Message == "" && rerr.Code == "" {
+		rerr.Message = string(bytes.TrimSpace(body))
+	}
+
+	return rerr
+}
+
 // API is a client for the Instatus API.
 //
 // The Instatus API is used to send status updates to the Instatus service.
@@ -21,6 +98,9 @@ import (
 type API struct {
 	// client: The HTTP client used to make requests to the Instatus API.
 	client *http.Client
+
+	// incidents tracks the open incidents.
+	incidents IncidentStore
 }
 
 // NewAPI creates a new Instatus API client.
@@ -64,7 +144,8 @@ type API struct {
 func NewAPI(ops ...Option) *API {
 	// Create a new Api struct with default settings for the http.Client.
 	api := &API{
-		client: &http.Client{},
+		client:    &http.Client{},
+		incidents: newMemoryIncidents(),
 	}
 
 	// Apply all provided options to the Api struct.
@@ -112,7 +193,7 @@ func WithClient(c http.Client) Option {
 	}
 }
 
-// Send sends a POST request to the given URL with the specified status.
+// Send sends a POST request to the URL of the target with the status of the event.
 //
 // The request is sent with the provided context and the status is used to
 // determine the value of the "trigger" field in the request payload.
@@ -120,34 +201,88 @@ func WithClient(c http.Client) Option {
 // value that corresponds to the status. The context is used to cancel the
 // request if it takes too long to complete.
 //
-// Returns an error if the request cannot be created, sent, or if the response
-// cannot be read.
+// The method, the body and the media type of the request can be overridden by
+// the target, for the receivers that expect e.g. PUT or form-encoded bodies.
+// The targets of the FormatCloudEvents format receive the event wrapped into
+// a CloudEvents 1.0 envelope instead of the "trigger" payload.
+//
+// If the target has a secret, the body is signed and the signature is sent in
+// the SignatureHeader.
+//
+// If the target has a component, the status of the component is updated
+// through the Instatus API instead, using the mapping of the statuses of the
+// target (see ComponentStatus). If the target has an incident title, an
+// incident is opened when the service goes down and resolved when it recovers.
+//
+// Returns an error if the request cannot be created or sent, or a *ResponseError
+// if Instatus responds with a non-2xx status code.
 //
 // Parameters:
 // - ctx: The context.Context to use for the request.
-// - url: The URL to send the request to.
-// - status: The entities.Status to use in the request payload.
-func (s *API) Send(ctx context.Context, url string, status entities.Status) error {
+// - target: The entities.Target to send the request to.
+// - event: The entities.Event whose status is used in the request payload.
+func (s *API) Send(ctx context.Context, target entities.Target, event entities.Event) error {
+	// Open or resolve the incident, or update the component, instead of
+	// triggering the automation webhook.
+	switch {
+	case target.Title != "":
+		return s.syncIncident(ctx, target, event)
+	case target.Component != "":
+		return s.updateComponent(ctx, target, event)
+	}
+
 	// Create the request payload as a JSON object with a single key "trigger"
-	// and a value that corresponds to the status.
-	// The payload is created as a string with the JSON object in it.
-	// The string is created using fmt.Sprintf() with the status as the
-	// parameter.
-	payload := fmt.Sprintf(`{"trigger": "%s"}`, status)
+	// and a value that corresponds to the status, unless the target overrides
+	// the body. The body of the target is already rendered by the notifier.Mux.
+	payload := fmt.Sprintf(`{"trigger": "%s"}`, event.Status)
+	contentType := "application/json"
+
+	switch {
+	case target.Body != "":
+		payload = target.Body
+	case target.Format == FormatCloudEvents:
+		// Wrap the event into a CloudEvents envelope.
+		data, err := cloudevents.Marshal(event)
+		if err != nil {
+			return err
+		}
+
+		payload, contentType = string(data), cloudevents.ContentType
+	}
+
+	// Use POST unless the target overrides the method.
+	method := http.MethodPost
+	if target.Method != "" {
+		method = strings.ToUpper(target.Method)
+	}
 
 	// Create a new HTTP request with the provided context and the specified URL.
-	// The request is a POST request with the payload as the request body.
 	// The request is created using http.NewRequestWithContext().
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url,
+	req, err := http.NewRequestWithContext(ctx, method, target.URL,
 		bytes.NewBufferString(payload))
 	if err != nil {
 		return err
 	}
 
 	// Set the "Content-Type" header of the request to "application/json" to
-	// indicate that the request body is in JSON format.
-	// The header is set using the Set() method of the Header map.
-	req.Header.Set("Content-Type", "application/json")
+	// indicate that the request body is in JSON format, unless the target
+	// overrides the media type, e.g. for form-encoded bodies.
+	if target.ContentType != "" {
+		contentType = target.ContentType
+	}
+
+	req.Header.Set("Content-Type", contentType)
+
+	// Pass the ID of the request that caused the transition, so the
+	// notification can be traced back to the heartbeat of the agent.
+	if event.RequestID != "" {
+		req.Header.Set(requestid.HTTPHeader, event.RequestID)
+	}
+
+	// Sign the body, so the receiver can verify the origin of the notification.
+	if target.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(target.Secret, []byte(payload)))
+	}
 
 	// Send the request and get the response.
 	// The request is sent using the Do() method of the client.
@@ -157,7 +292,81 @@ func (s *API) Send(ctx context.Context, url string, status entities.Status) erro
 	}
 	defer resp.Body.Close()
 
-	// No error occurred, return nil.
-	// The response is not needed, so it is closed immediately.
+	// Instatus responds with 2xx when the status is accepted. Otherwise report
+	// the error payload, so the delivery is retried.
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return newResponseError(resp)
+	}
+
 	return nil
 }
+
+// Sign returns the signature of the request body.
+//
+// The signature is the hex-encoded HMAC-SHA256 of the body with the secret,
+// prefixed with "sha256=". The receivers compute it the same way and compare
+// it with the SignatureHeader in constant time.
+//
+// Parameters:
+// - secret: The secret of the target.
+// - body: The request body.
+//
+// Returns:
+// - The value of the SignatureHeader.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks that Instatus knows the webhook of the target without
+// changing the status of the component.
+//
+// The URL of the webhook is requested with GET, which Instatus does not
+// treat as a trigger. The webhooks that do not exist or are not authorized
+// are rejected with 401, 403 or 404, while the valid ones are answered with
+// another status, e.g. 405 Method Not Allowed.
+//
+// The targets with a component or an incident title are verified by reading
+// the component or the incidents through the Instatus API, which checks the
+// API token as well.
+//
+// Parameters:
+// - ctx: The context.Context to use for the request.
+// - target: The entities.Target whose webhook is verified.
+//
+// Returns an error if the request cannot be sent, or a *ResponseError if the
+// webhook is rejected.
+func (s *API) Verify(ctx context.Context, target entities.Target) error {
+	address := target.URL
+
+	switch {
+	case target.Component != "":
+		address = endpoint(target, "components", target.Component)
+	case target.Title != "":
+		address = endpoint(target, "incidents")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return err
+	}
+
+	if UsesAPI(target) {
+		req.Header.Set("Authorization", "Bearer "+target.Token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return newResponseError(resp)
+	default:
+		return nil
+	}
+}
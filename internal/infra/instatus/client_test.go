@@ -0,0 +1,316 @@
+package instatus_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/instatus"
+	"github.com/bavix/vakeel-way/pkg/requestid"
+)
+
+// ClientTestSuite represents the test suite for the Instatus client.
+type ClientTestSuite struct {
+	suite.Suite
+}
+
+// send delivers an event to a server responding with the status code and body.
+func (suite *ClientTestSuite) send(code int, body string) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(code)
+		_, _ = w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	return instatus.NewAPI().Send(
+		context.Background(),
+		entities.Target{Name: "instatus", Type: "instatus", URL: server.URL},
+		entities.Event{ID: uuid.New(), Status: entities.Down},
+	)
+}
+
+// TestClient_Success verifies that 2xx responses are accepted.
+func (suite *ClientTestSuite) TestClient_Success() {
+	suite.Require().NoError(suite.send(http.StatusOK, `{"success": true}`))
+}
+
+// TestClient_ErrorPayload verifies that the Instatus error payload is reported.
+func (suite *ClientTestSuite) TestClient_ErrorPayload() {
+	err := suite.send(http.StatusNotFound, `{"error": {"code": "not_found", "message": "Webhook not found"}}`)
+	suite.Require().ErrorIs(err, instatus.ErrUnexpectedStatus)
+
+	var rerr *instatus.ResponseError
+	suite.Require().ErrorAs(err, &rerr)
+	suite.Equal(http.StatusNotFound, rerr.StatusCode)
+	suite.Equal("not_found", rerr.Code)
+	suite.Equal("Webhook not found", rerr.Message)
+	suite.False(rerr.Temporary())
+}
+
+// TestClient_RawBody verifies that a body that is not JSON is reported as is.
+func (suite *ClientTestSuite) TestClient_RawBody() {
+	err := suite.send(http.StatusBadGateway, "upstream unavailable\n")
+
+	var rerr *instatus.ResponseError
+	suite.Require().ErrorAs(err, &rerr)
+	suite.Equal("upstream unavailable", rerr.Message)
+	suite.True(rerr.Temporary())
+}
+
+// TestClient_Signature verifies that the body is signed with the secret of the target.
+func (suite *ClientTestSuite) TestClient_Signature() {
+	var body []byte
+
+	var signature, unsigned string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/unsigned" {
+			unsigned = r.Header.Get(instatus.SignatureHeader)
+
+			return
+		}
+
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(instatus.SignatureHeader)
+	}))
+	defer server.Close()
+
+	api := instatus.NewAPI()
+	event := entities.Event{ID: uuid.New(), Status: entities.Down}
+
+	suite.Require().NoError(api.Send(context.Background(),
+		entities.Target{Name: "signed", Type: "instatus", URL: server.URL, Secret: "s3cr3t"}, event))
+	suite.Require().NoError(api.Send(context.Background(),
+		entities.Target{Name: "unsigned", Type: "instatus", URL: server.URL + "/unsigned"}, event))
+
+	mac := hmac.New(sha256.New, []byte("s3cr3t"))
+	mac.Write(body)
+
+	suite.Equal("sha256="+hex.EncodeToString(mac.Sum(nil)), signature)
+	suite.Equal(instatus.Sign("s3cr3t", body), signature)
+	suite.Empty(unsigned)
+}
+
+// TestClient_RequestID verifies that the request ID of the event is passed in the headers.
+func (suite *ClientTestSuite) TestClient_RequestID() {
+	var ids []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		ids = append(ids, r.Header.Get(requestid.HTTPHeader))
+	}))
+	defer server.Close()
+
+	api := instatus.NewAPI()
+	target := entities.Target{Name: "instatus", Type: "instatus", URL: server.URL}
+
+	suite.Require().NoError(api.Send(context.Background(), target,
+		entities.Event{ID: uuid.New(), Status: entities.Up, RequestID: "req-1"}))
+	suite.Require().NoError(api.Send(context.Background(), target,
+		entities.Event{ID: uuid.New(), Status: entities.Down}))
+
+	suite.Equal([]string{"req-1", ""}, ids)
+}
+
+// TestClient_Override verifies that the target overrides the method, the body and the media type.
+func (suite *ClientTestSuite) TestClient_Override() {
+	var method, contentType, body string
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+
+		method, contentType, body = r.Method, r.Header.Get("Content-Type"), string(data)
+	}))
+	defer server.Close()
+
+	suite.Require().NoError(instatus.NewAPI().Send(context.Background(), entities.Target{
+		Name:        "form",
+		Type:        "instatus",
+		URL:         server.URL,
+		Method:      "put",
+		Body:        "status=down",
+		ContentType: "application/x-www-form-urlencoded",
+	}, entities.Event{ID: uuid.New(), Status: entities.Down}))
+
+	suite.Equal(http.MethodPut, method)
+	suite.Equal("application/x-www-form-urlencoded", contentType)
+	suite.Equal("status=down", body)
+}
+
+// TestClient_CloudEvents verifies that the event is wrapped into a CloudEvents envelope.
+func (suite *ClientTestSuite) TestClient_CloudEvents() {
+	var contentType string
+
+	var envelope map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		_ = json.NewDecoder(r.Body).Decode(&envelope)
+	}))
+	defer server.Close()
+
+	id := uuid.New()
+
+	suite.Require().NoError(instatus.NewAPI().Send(context.Background(),
+		entities.Target{Name: "broker", Type: "instatus", URL: server.URL, Format: instatus.FormatCloudEvents},
+		entities.Event{ID: id, Status: entities.Down}))
+
+	suite.Equal("application/cloudevents+json", contentType)
+	suite.Equal("1.0", envelope["specversion"])
+	suite.Equal("io.github.bavix.vakeel-way.service.down", envelope["type"])
+	suite.Equal(id.String(), envelope["subject"])
+}
+
+// TestClient_Component verifies that the status of the component is updated through the Instatus API.
+func (suite *ClientTestSuite) TestClient_Component() {
+	var method, path, auth, body string
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+
+		method, path, auth, body = r.Method, r.URL.Path, r.Header.Get("Authorization"), string(data)
+	}))
+	defer server.Close()
+
+	api := instatus.NewAPI()
+	target := entities.Target{
+		Name:      "component",
+		Type:      "instatus",
+		URL:       server.URL,
+		Token:     "key",
+		Page:      "page-1",
+		Component: "api",
+		Statuses:  map[string]string{"down": "partialoutage"},
+	}
+
+	suite.Require().NoError(api.Send(context.Background(), target, entities.Event{ID: uuid.New(), Status: entities.Down}))
+	suite.Equal(http.MethodPut, method)
+	suite.Equal("/v1/page-1/components/api", path)
+	suite.Equal("Bearer key", auth)
+	suite.JSONEq(`{"status": "PARTIALOUTAGE"}`, body)
+
+	// The unmapped statuses use the default mapping.
+	suite.Require().NoError(api.Send(context.Background(), target, entities.Event{ID: uuid.New(), Status: entities.Up}))
+	suite.JSONEq(`{"status": "OPERATIONAL"}`, body)
+
+	target.Statuses = map[string]string{"down": "broken"}
+	suite.Require().ErrorIs(
+		api.Send(context.Background(), target, entities.Event{ID: uuid.New(), Status: entities.Down}),
+		instatus.ErrUnknownComponentStatus,
+	)
+}
+
+// TestClient_Incident verifies that an incident is opened once per downtime and resolved on recovery.
+func (suite *ClientTestSuite) TestClient_Incident() {
+	var requests []string
+
+	var payloads []map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		payloads = append(payloads, payload)
+
+		_, _ = w.Write([]byte(`{"id": "inc-1"}`))
+	}))
+	defer server.Close()
+
+	api := instatus.NewAPI()
+	id := uuid.New()
+	target := entities.Target{
+		Name:      "incidents",
+		Type:      "instatus",
+		URL:       server.URL,
+		Token:     "key",
+		Page:      "page-1",
+		Component: "api",
+		Title:     "API is down",
+	}
+
+	for _, status := range []entities.Status{entities.Down, entities.Degraded, entities.Up, entities.Up} {
+		suite.Require().NoError(api.Send(context.Background(), target, entities.Event{ID: id, Status: status, Message: "msg"}))
+	}
+
+	suite.Equal([]string{
+		"POST /v1/page-1/incidents",
+		"PUT /v1/page-1/components/api",
+		"POST /v1/page-1/incidents/inc-1/incident-updates",
+		"PUT /v1/page-1/components/api",
+	}, requests)
+
+	suite.Equal("API is down", payloads[0]["name"])
+	suite.Equal("INVESTIGATING", payloads[0]["status"])
+	suite.Equal("msg", payloads[0]["message"])
+	suite.Equal([]any{map[string]any{"id": "api", "status": "MAJOROUTAGE"}}, payloads[0]["statuses"])
+	suite.Equal("DEGRADEDPERFORMANCE", payloads[1]["status"])
+	suite.Equal("RESOLVED", payloads[2]["status"])
+	suite.Equal([]any{map[string]any{"id": "api", "status": "OPERATIONAL"}}, payloads[2]["statuses"])
+}
+
+// TestClient_IncidentDeleted verifies that an incident deleted in Instatus is considered resolved.
+func (suite *ClientTestSuite) TestClient_IncidentDeleted() {
+	var requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if r.Method == http.MethodPost && r.URL.Path == "/v1/page-1/incidents" {
+			_, _ = w.Write([]byte(`{"id": "inc-1"}`))
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	api := instatus.NewAPI()
+	id := uuid.New()
+	target := entities.Target{Name: "incidents", Type: "instatus", URL: server.URL, Token: "key", Page: "page-1", Title: "down"}
+
+	suite.Require().NoError(api.Send(context.Background(), target, entities.Event{ID: id, Status: entities.Down}))
+	suite.Require().NoError(api.Send(context.Background(), target, entities.Event{ID: id, Status: entities.Up}))
+
+	// The incident is no longer tracked, so a recovery without a component is a no-op.
+	suite.Require().NoError(api.Send(context.Background(), target, entities.Event{ID: id, Status: entities.Up}))
+	suite.Equal(2, requests)
+}
+
+// verify verifies the webhook of a server responding with the status code.
+func (suite *ClientTestSuite) verify(code int) error {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.Equal(http.MethodGet, r.Method)
+		w.WriteHeader(code)
+	}))
+	defer server.Close()
+
+	return instatus.NewAPI().Verify(
+		context.Background(),
+		entities.Target{Name: "instatus", Type: "instatus", URL: server.URL},
+	)
+}
+
+// TestClient_Verify verifies that only the unknown and unauthorized webhooks are rejected.
+func (suite *ClientTestSuite) TestClient_Verify() {
+	suite.Require().NoError(suite.verify(http.StatusMethodNotAllowed))
+	suite.Require().NoError(suite.verify(http.StatusOK))
+	suite.Require().ErrorIs(suite.verify(http.StatusNotFound), instatus.ErrUnexpectedStatus)
+	suite.Require().ErrorIs(suite.verify(http.StatusUnauthorized), instatus.ErrUnexpectedStatus)
+}
+
+// TestClientTestSuite runs the Instatus client test suite.
+func TestClientTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ClientTestSuite))
+}
@@ -0,0 +1,137 @@
+package instatus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// APIURL is the base URL of the public Instatus API.
+const APIURL = "https://api.instatus.com"
+
+// ErrUnknownComponentStatus is an error that indicates that a status is mapped
+// to a status Instatus does not know.
+var ErrUnknownComponentStatus = errors.New("instatus: unknown component status")
+
+// ComponentStatuses are the statuses of the Instatus components.
+var ComponentStatuses = []string{
+	"OPERATIONAL",
+	"UNDERMAINTENANCE",
+	"DEGRADEDPERFORMANCE",
+	"PARTIALOUTAGE",
+	"MAJOROUTAGE",
+}
+
+// defaultStatuses maps the statuses of the services to the statuses of the components by default.
+var defaultStatuses = map[entities.Status]string{
+	entities.Up:       "OPERATIONAL",
+	entities.Degraded: "DEGRADEDPERFORMANCE",
+	entities.Down:     "MAJOROUTAGE",
+}
+
+// ComponentStatus returns the status of the component of the target for the
+// status of the service.
+//
+// The mapping of the target overrides the default one: the services that are
+// up are operational, the degraded ones have degraded performance and the
+// ones that are down have a major outage.
+//
+// Parameters:
+// - target: The entities.Target with the mapping of the statuses.
+// - status: The status of the service.
+//
+// Returns:
+// - The status of the component.
+// - ErrUnknownComponentStatus if the status is mapped to an unknown status.
+func ComponentStatus(target entities.Target, status entities.Status) (string, error) {
+	mapped, ok := target.Statuses[status.String()]
+	if !ok {
+		return defaultStatuses[status], nil
+	}
+
+	mapped = strings.ToUpper(mapped)
+	if !slices.Contains(ComponentStatuses, mapped) {
+		return "", fmt.Errorf("%w: %s", ErrUnknownComponentStatus, mapped)
+	}
+
+	return mapped, nil
+}
+
+// UsesAPI reports whether the target is delivered through the Instatus API,
+// i.e. it updates a component or opens incidents, rather than through an
+// automation webhook.
+func UsesAPI(target entities.Target) bool {
+	return target.Component != "" || target.Title != ""
+}
+
+// endpoint returns the URL of the resource of the page of the target in the Instatus API.
+func endpoint(target entities.Target, elems ...string) string {
+	base := target.URL
+	if base == "" {
+		base = APIURL
+	}
+
+	path := "/v1/" + url.PathEscape(target.Page)
+	for _, elem := range elems {
+		path += "/" + url.PathEscape(elem)
+	}
+
+	return strings.TrimRight(base, "/") + path
+}
+
+// call sends the request with the payload to the Instatus API and decodes the response into out.
+func (s *API) call(ctx context.Context, target entities.Target, method, address string, payload, out any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, address, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+target.Token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return newResponseError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	// An empty response leaves out unchanged.
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	return nil
+}
+
+// updateComponent updates the status of the component of the target through the Instatus API.
+func (s *API) updateComponent(ctx context.Context, target entities.Target, event entities.Event) error {
+	status, err := ComponentStatus(target, event.Status)
+	if err != nil {
+		return err
+	}
+
+	return s.call(ctx, target, http.MethodPut, endpoint(target, "components", target.Component),
+		map[string]string{"status": status}, nil)
+}
@@ -0,0 +1,202 @@
+package instatus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// incidentTTL is the time an open incident is tracked for.
+//
+// The incidents of the services that never recover are forgotten after it,
+// so a new incident is opened on their next downtime.
+const incidentTTL = 90 * 24 * time.Hour
+
+// IncidentStore represents an interface for tracking the open Instatus incidents.
+//
+// It is satisfied by *cache.Cache[string, string].
+type IncidentStore interface {
+	// Get returns the identifier of the open incident and whether there is one.
+	Get(key string) (*string, bool)
+
+	// Add tracks the open incident.
+	Add(key string, id string, ttl time.Duration)
+
+	// Delete stops tracking the incident and reports whether it was tracked.
+	Delete(key string) bool
+}
+
+// WithIncidents returns an Option that sets the IncidentStore tracking the open incidents.
+//
+// By default the incidents are tracked in memory, so the incidents opened
+// before a restart are not resolved.
+//
+// Parameters:
+// - store: The IncidentStore tracking the open incidents.
+//
+// Returns an Option that sets the IncidentStore of the API.
+func WithIncidents(store IncidentStore) Option {
+	return func(api *API) {
+		api.incidents = store
+	}
+}
+
+// incident is the payload of an incident and of an incident update in the Instatus API.
+type incident struct {
+	Name       string            `json:"name,omitempty"`
+	Message    string            `json:"message"`
+	Components []string          `json:"components"`
+	Started    string            `json:"started"`
+	Status     string            `json:"status"`
+	Notify     bool              `json:"notify"`
+	Statuses   []componentStatus `json:"statuses"`
+}
+
+// componentStatus is the status of a component affected by an incident.
+type componentStatus struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
+
+// syncIncident opens an incident when the service goes down and resolves it
+// when the service recovers.
+//
+// The identifier of the open incident is tracked in the IncidentStore under
+// the webhook and the target, so an incident is opened once per downtime.
+func (s *API) syncIncident(ctx context.Context, target entities.Target, event entities.Event) error {
+	key := event.ID.String() + "/" + target.Name
+	id, open := s.incidents.Get(key)
+
+	switch {
+	case event.Status != entities.Up && open, event.Status == entities.Up && !open:
+		// The incident is already open or there is none to resolve, so only the
+		// status of the component changes.
+		if target.Component != "" {
+			return s.updateComponent(ctx, target, event)
+		}
+
+		return nil
+	case event.Status == entities.Up:
+		return s.resolveIncident(ctx, target, event, key, *id)
+	default:
+		return s.openIncident(ctx, target, event, key)
+	}
+}
+
+// openIncident opens an incident with the rendered title of the target.
+func (s *API) openIncident(ctx context.Context, target entities.Target, event entities.Event, key string) error {
+	payload, err := newIncident(target, event, "INVESTIGATING")
+	if err != nil {
+		return err
+	}
+
+	payload.Name = target.Title
+
+	var created struct {
+		ID string `json:"id"`
+	}
+
+	if err := s.call(ctx, target, http.MethodPost, endpoint(target, "incidents"), payload, &created); err != nil {
+		return err
+	}
+
+	// An incident without an identifier cannot be resolved, so it is not tracked.
+	if created.ID != "" {
+		s.incidents.Add(key, created.ID, incidentTTL)
+	}
+
+	return nil
+}
+
+// resolveIncident resolves the open incident.
+//
+// An incident that was deleted in Instatus is considered resolved.
+func (s *API) resolveIncident(ctx context.Context, target entities.Target, event entities.Event, key, id string) error {
+	payload, err := newIncident(target, event, "RESOLVED")
+	if err != nil {
+		return err
+	}
+
+	err = s.call(ctx, target, http.MethodPost, endpoint(target, "incidents", id, "incident-updates"), payload, nil)
+
+	var rerr *ResponseError
+	if err != nil && (!errors.As(err, &rerr) || rerr.StatusCode != http.StatusNotFound) {
+		return err
+	}
+
+	s.incidents.Delete(key)
+
+	return nil
+}
+
+// newIncident returns the payload of the incident with the status.
+func newIncident(target entities.Target, event entities.Event, status string) (incident, error) {
+	payload := incident{
+		Name:       "",
+		Message:    event.Message,
+		Components: []string{},
+		Started:    event.Time.UTC().Format(time.RFC3339),
+		Status:     status,
+		Notify:     true,
+		Statuses:   []componentStatus{},
+	}
+
+	if target.Component != "" {
+		mapped, err := ComponentStatus(target, event.Status)
+		if err != nil {
+			return payload, err
+		}
+
+		payload.Components = append(payload.Components, target.Component)
+		payload.Statuses = append(payload.Statuses, componentStatus{ID: target.Component, Status: mapped})
+	}
+
+	return payload, nil
+}
+
+// memoryIncidents is an IncidentStore that keeps the open incidents in memory.
+type memoryIncidents struct {
+	mu  sync.Mutex
+	ids map[string]string
+}
+
+// newMemoryIncidents creates a new instance of the memoryIncidents struct.
+func newMemoryIncidents() *memoryIncidents {
+	return &memoryIncidents{mu: sync.Mutex{}, ids: make(map[string]string)}
+}
+
+// Get returns the identifier of the open incident and whether there is one.
+func (m *memoryIncidents) Get(key string) (*string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	id, ok := m.ids[key]
+	if !ok {
+		return nil, false
+	}
+
+	return &id, true
+}
+
+// Add tracks the open incident. The incidents kept in memory never expire.
+func (m *memoryIncidents) Add(key string, id string, _ time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.ids[key] = id
+}
+
+// Delete stops tracking the incident and reports whether it was tracked.
+func (m *memoryIncidents) Delete(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, ok := m.ids[key]
+	delete(m.ids, key)
+
+	return ok
+}
@@ -0,0 +1,123 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ServiceAccount is the directory the credentials of the service account are mounted in the pods.
+const ServiceAccount = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+var (
+	// ErrNotInCluster is an error that indicates that the API server cannot be found in the environment.
+	ErrNotInCluster = errors.New("kubernetes: not running in a cluster")
+
+	// ErrInvalidCA is an error that indicates that the CA bundle holds no certificate.
+	ErrInvalidCA = errors.New("kubernetes: no certificate in the CA bundle")
+)
+
+// Endpoint returns the URL of the API server from the environment of the pod.
+//
+// Returns:
+//   - The URL of the API server.
+//   - ErrNotInCluster if the environment has no API server.
+func Endpoint() (string, error) {
+	host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+	if host == "" || port == "" {
+		return "", ErrNotInCluster
+	}
+
+	return "https://" + net.JoinHostPort(host, port), nil
+}
+
+// Namespace returns the namespace of the pod from its service account.
+//
+// Returns:
+//   - The namespace of the pod.
+//   - An error if the namespace cannot be read.
+func Namespace() (string, error) {
+	namespace, err := os.ReadFile(filepath.Join(ServiceAccount, "namespace"))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(namespace)), nil
+}
+
+// NewHTTPClient creates the HTTP client of the API server.
+//
+// Parameters:
+//   - caFile: The path to the CA bundle the certificate of the API server is
+//     verified with. If it is empty, the system roots are used.
+//
+// Returns:
+//   - A pointer to the http.Client.
+//   - An error if the CA bundle cannot be read.
+//
+//nolint:exhaustruct
+func NewHTTPClient(caFile string) (*http.Client, error) {
+	if caFile == "" {
+		return http.DefaultClient, nil
+	}
+
+	bundle, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, err
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(bundle) {
+		return nil, ErrInvalidCA
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone() //nolint:forcetypeassert
+	transport.TLSClientConfig = &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// send sends a request to the API server.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - client: The HTTP client of the API server.
+//   - tokenFile: The path to the bearer token, read on every request as the
+//     projected tokens are rotated. If it is empty, no token is sent.
+//   - method: The HTTP method.
+//   - target: The URL of the request.
+//   - body: The JSON body of the request, or nil.
+//
+// Returns:
+//   - The response of the API server.
+//   - An error if the request fails.
+func send(ctx context.Context, client *http.Client, tokenFile, method, target string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if tokenFile != "" {
+		token, err := os.ReadFile(tokenFile)
+		if err != nil {
+			return nil, err
+		}
+
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	return client.Do(req)
+}
@@ -0,0 +1,381 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// ErrUnexpectedStatus is an error that indicates that the API server responded with an unexpected status.
+var ErrUnexpectedStatus = errors.New("kubernetes: unexpected status")
+
+// microTime is the layout of the MicroTime fields of the Kubernetes API.
+const microTime = "2006-01-02T15:04:05.000000Z07:00"
+
+// leaseObject is the coordination.k8s.io/v1 Lease object.
+type leaseObject struct {
+	APIVersion string        `json:"apiVersion"`
+	Kind       string        `json:"kind"`
+	Metadata   leaseMetadata `json:"metadata"`
+	Spec       leaseSpec     `json:"spec"`
+}
+
+// leaseMetadata is the metadata of the Lease object.
+type leaseMetadata struct {
+	Name            string `json:"name"`
+	Namespace       string `json:"namespace"`
+	ResourceVersion string `json:"resourceVersion,omitempty"`
+}
+
+// leaseSpec is the specification of the Lease object.
+type leaseSpec struct {
+	HolderIdentity       string `json:"holderIdentity,omitempty"`
+	LeaseDurationSeconds int    `json:"leaseDurationSeconds,omitempty"`
+	AcquireTime          string `json:"acquireTime,omitempty"`
+	RenewTime            string `json:"renewTime,omitempty"`
+	LeaseTransitions     int    `json:"leaseTransitions,omitempty"`
+}
+
+// expired reports whether the lease is free to be taken over at the time.
+func (s leaseSpec) expired(now time.Time) bool {
+	if s.HolderIdentity == "" {
+		return true
+	}
+
+	renewed, err := time.Parse(microTime, s.RenewTime)
+	if err != nil {
+		return true
+	}
+
+	return now.After(renewed.Add(time.Duration(s.LeaseDurationSeconds) * time.Second))
+}
+
+// Lease holds the leadership of the instance with a Kubernetes Lease.
+//
+// The instances of a deployment share the Lease object: the instance named as
+// its holder is the leader, and renews the lease. Once the holder stops
+// renewing it, another instance takes it over. The concurrent takeovers are
+// resolved by the API server with the resource version of the object.
+type Lease struct {
+	// endpoint is the URL of the API server.
+	endpoint string
+
+	// namespace is the namespace of the Lease object.
+	namespace string
+
+	// name is the name of the Lease object.
+	name string
+
+	// identity is the unique name of the instance.
+	identity string
+
+	// duration is the time the leadership is held without a renewal.
+	duration time.Duration
+
+	// tokenFile is the path to the token of the service account. It is read on
+	// every request, as the projected tokens are rotated.
+	tokenFile string
+
+	// client is the HTTP client of the API server.
+	client *http.Client
+
+	// now returns the current time.
+	now func() time.Time
+}
+
+// LeaseOption is a function that can be used to configure a Lease instance.
+type LeaseOption func(*Lease)
+
+// WithEndpoint returns a LeaseOption that sets the URL of the API server.
+//
+// Parameters:
+//   - endpoint: The URL of the API server, e.g. "https://10.0.0.1:443".
+//
+// Returns:
+//   - A LeaseOption that sets the endpoint of the Lease.
+func WithEndpoint(endpoint string) LeaseOption {
+	return func(l *Lease) {
+		l.endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+// WithTokenFile returns a LeaseOption that sets the path to the bearer token.
+//
+// Parameters:
+//   - path: The path to the token. If it is empty, no token is sent.
+//
+// Returns:
+//   - A LeaseOption that sets the token file of the Lease.
+func WithTokenFile(path string) LeaseOption {
+	return func(l *Lease) {
+		l.tokenFile = path
+	}
+}
+
+// WithHTTPClient returns a LeaseOption that sets the HTTP client of the API server.
+//
+// Parameters:
+//   - client: The HTTP client, trusting the certificate of the API server.
+//
+// Returns:
+//   - A LeaseOption that sets the HTTP client of the Lease.
+func WithHTTPClient(client *http.Client) LeaseOption {
+	return func(l *Lease) {
+		l.client = client
+	}
+}
+
+// WithDuration returns a LeaseOption that sets the duration of the lease.
+//
+// Parameters:
+//   - duration: The duration of the lease, rounded down to seconds. The
+//     values below a second are ignored.
+//
+// Returns:
+//   - A LeaseOption that sets the duration of the Lease.
+func WithDuration(duration time.Duration) LeaseOption {
+	return func(l *Lease) {
+		if duration >= time.Second {
+			l.duration = duration.Truncate(time.Second)
+		}
+	}
+}
+
+// NewLease creates a new instance of the Lease struct.
+//
+// Parameters:
+//   - namespace: The namespace of the Lease object.
+//   - name: The name of the Lease object.
+//   - identity: The unique name of the instance, usually the name of the pod.
+//   - options: Optional configurations for the Lease.
+//
+// Returns:
+//   - A pointer to the initialized Lease.
+//
+//nolint:exhaustruct
+func NewLease(namespace, name, identity string, options ...LeaseOption) *Lease {
+	const duration = 15 * time.Second
+
+	lease := &Lease{
+		namespace: namespace,
+		name:      name,
+		identity:  identity,
+		duration:  duration,
+		client:    http.DefaultClient,
+		now:       time.Now,
+	}
+
+	// Apply any optional configurations provided through the options parameter.
+	for _, option := range options {
+		option(lease)
+	}
+
+	return lease
+}
+
+// Duration returns the duration of the lease.
+func (l *Lease) Duration() time.Duration {
+	return l.duration
+}
+
+// Acquire takes over or renews the lease for the instance.
+//
+// The lease is created if it does not exist, and taken over if it is free or
+// expired.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//
+// Returns:
+//   - entities.ErrNotLeader if another instance holds the lease, or took it
+//     over concurrently.
+//   - An error if the API server cannot be reached.
+func (l *Lease) Acquire(ctx context.Context) error {
+	current, err := l.get(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := l.now()
+	stamp := now.UTC().Format(microTime)
+
+	// Create the lease on the first election.
+	if current == nil {
+		lease := l.object("")
+		lease.Spec.AcquireTime = stamp
+		lease.Spec.RenewTime = stamp
+
+		return l.write(ctx, http.MethodPost, l.collection(), lease)
+	}
+
+	held := current.Spec.HolderIdentity == l.identity
+	if !held && !current.Spec.expired(now) {
+		return entities.ErrNotLeader
+	}
+
+	lease := l.object(current.Metadata.ResourceVersion)
+	lease.Spec.AcquireTime = current.Spec.AcquireTime
+	lease.Spec.RenewTime = stamp
+	lease.Spec.LeaseTransitions = current.Spec.LeaseTransitions
+
+	if !held {
+		lease.Spec.AcquireTime = stamp
+		lease.Spec.LeaseTransitions++
+	}
+
+	return l.write(ctx, http.MethodPut, l.resource(), lease)
+}
+
+// Release gives the lease up, if the instance holds it, so another instance
+// takes it over without waiting for it to expire.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//
+// Returns:
+//   - An error if the API server cannot be reached.
+func (l *Lease) Release(ctx context.Context) error {
+	current, err := l.get(ctx)
+	if err != nil || current == nil || current.Spec.HolderIdentity != l.identity {
+		return err
+	}
+
+	lease := l.object(current.Metadata.ResourceVersion)
+	lease.Spec.HolderIdentity = ""
+	lease.Spec.LeaseDurationSeconds = 1
+	lease.Spec.AcquireTime = current.Spec.AcquireTime
+	lease.Spec.RenewTime = l.now().UTC().Format(microTime)
+	lease.Spec.LeaseTransitions = current.Spec.LeaseTransitions
+
+	// Another instance took the lease over in the meantime.
+	if err := l.write(ctx, http.MethodPut, l.resource(), lease); !errors.Is(err, entities.ErrNotLeader) {
+		return err
+	}
+
+	return nil
+}
+
+// object returns the Lease object held by the instance.
+//
+// Parameters:
+//   - version: The resource version of the object being replaced, or empty.
+//
+// Returns:
+//   - The Lease object.
+//
+//nolint:exhaustruct
+func (l *Lease) object(version string) leaseObject {
+	return leaseObject{
+		APIVersion: "coordination.k8s.io/v1",
+		Kind:       "Lease",
+		Metadata: leaseMetadata{
+			Name:            l.name,
+			Namespace:       l.namespace,
+			ResourceVersion: version,
+		},
+		Spec: leaseSpec{
+			HolderIdentity:       l.identity,
+			LeaseDurationSeconds: int(l.duration / time.Second),
+		},
+	}
+}
+
+// collection returns the URL of the leases of the namespace.
+func (l *Lease) collection() string {
+	return l.endpoint + "/apis/coordination.k8s.io/v1/namespaces/" + url.PathEscape(l.namespace) + "/leases"
+}
+
+// resource returns the URL of the Lease object.
+func (l *Lease) resource() string {
+	return l.collection() + "/" + url.PathEscape(l.name)
+}
+
+// get fetches the Lease object.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//
+// Returns:
+//   - The Lease object, or nil if it does not exist.
+//   - An error if the API server cannot be reached.
+func (l *Lease) get(ctx context.Context) (*leaseObject, error) {
+	resp, err := l.do(ctx, http.MethodGet, l.resource(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil //nolint:nilnil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+	}
+
+	var lease leaseObject
+	if err := json.NewDecoder(resp.Body).Decode(&lease); err != nil {
+		return nil, err
+	}
+
+	return &lease, nil
+}
+
+// write creates or replaces the Lease object.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - method: The HTTP method, POST to create or PUT to replace.
+//   - target: The URL of the request.
+//   - lease: The Lease object.
+//
+// Returns:
+//   - entities.ErrNotLeader if the object was changed by another instance.
+//   - An error if the API server cannot be reached.
+func (l *Lease) write(ctx context.Context, method, target string, lease leaseObject) error {
+	body, err := json.Marshal(lease)
+	if err != nil {
+		return err
+	}
+
+	resp, err := l.do(ctx, method, target, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body, so the connection is reused.
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		return nil
+	case http.StatusConflict:
+		return entities.ErrNotLeader
+	default:
+		return fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+	}
+}
+
+// do sends a request to the API server.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - method: The HTTP method.
+//   - target: The URL of the request.
+//   - body: The JSON body of the request, or nil.
+//
+// Returns:
+//   - The response of the API server.
+//   - An error if the request fails.
+func (l *Lease) do(ctx context.Context, method, target string, body []byte) (*http.Response, error) {
+	return send(ctx, l.client, l.tokenFile, method, target, body)
+}
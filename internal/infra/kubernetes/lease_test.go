@@ -0,0 +1,136 @@
+package kubernetes_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/kubernetes"
+)
+
+// apiServer is a fake API server holding a single Lease object.
+type apiServer struct {
+	mu      sync.Mutex
+	lease   map[string]any
+	version int
+}
+
+// ServeHTTP handles the GET, POST and PUT requests of the Lease object.
+func (s *apiServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.Method {
+	case http.MethodGet:
+		if s.lease == nil {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(s.lease)
+	case http.MethodPost, http.MethodPut:
+		var lease map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&lease); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		metadata, _ := lease["metadata"].(map[string]any)
+
+		// The object is replaced at its latest version only.
+		if (r.Method == http.MethodPost) != (s.lease == nil) ||
+			(r.Method == http.MethodPut && metadata["resourceVersion"] != strconv.Itoa(s.version)) {
+			w.WriteHeader(http.StatusConflict)
+
+			return
+		}
+
+		s.version++
+		metadata["resourceVersion"] = strconv.Itoa(s.version)
+		s.lease = lease
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// holder returns the holder of the lease.
+func (s *apiServer) holder() any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	spec, _ := s.lease["spec"].(map[string]any)
+
+	return spec["holderIdentity"]
+}
+
+// LeaseTestSuite represents the test suite for the Kubernetes Lease functionality.
+type LeaseTestSuite struct {
+	suite.Suite
+}
+
+// TestLease_Election verifies that a single instance holds the lease, renews
+// it, and hands it over once it is released.
+//
+//nolint:exhaustruct
+func (suite *LeaseTestSuite) TestLease_Election() {
+	ctx := context.Background()
+	api := &apiServer{}
+	server := httptest.NewServer(api)
+	suite.T().Cleanup(server.Close)
+
+	first := kubernetes.NewLease("default", "vakeel-way", "first", kubernetes.WithEndpoint(server.URL))
+	second := kubernetes.NewLease("default", "vakeel-way", "second", kubernetes.WithEndpoint(server.URL))
+
+	suite.Require().NoError(first.Acquire(ctx))
+	suite.Require().NoError(first.Acquire(ctx))
+	suite.Equal("first", api.holder())
+	suite.Require().ErrorIs(second.Acquire(ctx), entities.ErrNotLeader)
+
+	// The release of a lease held by another instance is ignored.
+	suite.Require().NoError(second.Release(ctx))
+	suite.Equal("first", api.holder())
+
+	suite.Require().NoError(first.Release(ctx))
+	suite.Require().NoError(second.Acquire(ctx))
+	suite.Equal("second", api.holder())
+}
+
+// TestLease_Expired verifies that an expired lease is taken over.
+//
+//nolint:exhaustruct
+func (suite *LeaseTestSuite) TestLease_Expired() {
+	ctx := context.Background()
+	api := &apiServer{}
+	server := httptest.NewServer(api)
+	suite.T().Cleanup(server.Close)
+
+	first := kubernetes.NewLease("default", "vakeel-way", "first",
+		kubernetes.WithEndpoint(server.URL), kubernetes.WithDuration(time.Second))
+	second := kubernetes.NewLease("default", "vakeel-way", "second", kubernetes.WithEndpoint(server.URL))
+
+	suite.Require().NoError(first.Acquire(ctx))
+	suite.Require().ErrorIs(second.Acquire(ctx), entities.ErrNotLeader)
+
+	suite.Eventually(func() bool {
+		return second.Acquire(ctx) == nil
+	}, 3*time.Second, 100*time.Millisecond)
+
+	suite.Equal("second", api.holder())
+	suite.Require().ErrorIs(first.Acquire(ctx), entities.ErrNotLeader)
+}
+
+// TestLeaseTestSuite runs the test suite for the Kubernetes Lease functionality.
+func TestLeaseTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(LeaseTestSuite))
+}
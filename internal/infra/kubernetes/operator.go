@@ -0,0 +1,426 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// WebhookGroup is the API group of the Webhook custom resources.
+	WebhookGroup = "vakeel-way.bavix.dev"
+
+	// WebhookVersion is the API version of the Webhook custom resources.
+	WebhookVersion = "v1alpha1"
+
+	// WebhooksAnnotation is the annotation of the ConfigMaps holding webhooks.
+	//
+	// Every key of the data of a ConfigMap annotated with "true" holds a YAML
+	// list of webhooks in the format of the configuration file.
+	WebhooksAnnotation = "vakeel-way.bavix.dev/webhooks"
+)
+
+// Resource represents an object of the API server defining webhooks.
+type Resource struct {
+	// Source identifies the object, e.g. "Webhook/payments-api" or "ConfigMap/team-a/webhooks.yaml".
+	Source string
+
+	// Spec is the YAML or JSON document of the webhooks.
+	Spec []byte
+
+	// List reports whether the Spec is a list of webhooks rather than a single webhook.
+	List bool
+}
+
+// object is the part of the Webhook and ConfigMap objects read by the Operator.
+type object struct {
+	Metadata struct {
+		Name        string            `json:"name"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+
+	// Spec is the specification of a Webhook.
+	Spec json.RawMessage `json:"spec"`
+
+	// Data is the data of a ConfigMap.
+	Data map[string]string `json:"data"`
+}
+
+// objectList is the list of the objects of a collection.
+type objectList struct {
+	Metadata struct {
+		ResourceVersion string `json:"resourceVersion"`
+	} `json:"metadata"`
+
+	Items []object `json:"items"`
+}
+
+// OperatorOption is a function that can be used to configure an Operator instance.
+type OperatorOption func(*Operator)
+
+// WithOperatorEndpoint returns an OperatorOption that sets the URL of the API server.
+//
+// Parameters:
+//   - endpoint: The URL of the API server, e.g. "https://10.0.0.1:443".
+//
+// Returns:
+//   - An OperatorOption that sets the endpoint of the Operator.
+func WithOperatorEndpoint(endpoint string) OperatorOption {
+	return func(o *Operator) {
+		o.endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+// WithOperatorTokenFile returns an OperatorOption that sets the path to the bearer token.
+//
+// Parameters:
+//   - path: The path to the token. If it is empty, no token is sent.
+//
+// Returns:
+//   - An OperatorOption that sets the token file of the Operator.
+func WithOperatorTokenFile(path string) OperatorOption {
+	return func(o *Operator) {
+		o.tokenFile = path
+	}
+}
+
+// WithOperatorHTTPClient returns an OperatorOption that sets the HTTP client of the API server.
+//
+// Parameters:
+//   - client: The HTTP client, trusting the certificate of the API server.
+//
+// Returns:
+//   - An OperatorOption that sets the HTTP client of the Operator.
+func WithOperatorHTTPClient(client *http.Client) OperatorOption {
+	return func(o *Operator) {
+		o.client = client
+	}
+}
+
+// WithResync returns an OperatorOption that sets the interval of the full resynchronization.
+//
+// Parameters:
+//   - interval: The longest time between two lists of the objects, even if
+//     the watches report no change. The values below a second are ignored.
+//
+// Returns:
+//   - An OperatorOption that sets the resync interval of the Operator.
+func WithResync(interval time.Duration) OperatorOption {
+	return func(o *Operator) {
+		if interval >= time.Second {
+			o.resync = interval
+		}
+	}
+}
+
+// Operator watches the webhooks defined in a namespace of Kubernetes.
+//
+// The webhooks are defined by the Webhook custom resources of the
+// vakeel-way.bavix.dev group, whose spec is a webhook in the format of the
+// configuration file, and by the ConfigMaps annotated with
+// WebhooksAnnotation. The objects are listed, then watched, and listed again
+// on every change, so the webhooks follow the manifests applied by GitOps.
+type Operator struct {
+	// endpoint is the URL of the API server.
+	endpoint string
+
+	// namespace is the namespace the objects are watched in.
+	namespace string
+
+	// tokenFile is the path to the token of the service account.
+	tokenFile string
+
+	// client is the HTTP client of the API server.
+	client *http.Client
+
+	// resync is the longest time between two lists of the objects.
+	resync time.Duration
+
+	// settle is the time the changes are collected for before the objects
+	// are listed again, so an apply of many manifests is synced once.
+	settle time.Duration
+}
+
+// NewOperator creates a new instance of the Operator struct.
+//
+// Parameters:
+//   - namespace: The namespace the objects are watched in.
+//   - options: Optional configurations for the Operator.
+//
+// Returns:
+//   - A pointer to the initialized Operator.
+//
+//nolint:exhaustruct
+func NewOperator(namespace string, options ...OperatorOption) *Operator {
+	const (
+		resync = 5 * time.Minute
+		settle = time.Second
+	)
+
+	operator := &Operator{
+		namespace: namespace,
+		client:    http.DefaultClient,
+		resync:    resync,
+		settle:    settle,
+	}
+
+	// Apply any optional configurations provided through the options parameter.
+	for _, option := range options {
+		option(operator)
+	}
+
+	return operator
+}
+
+// Namespace returns the namespace the objects are watched in.
+func (o *Operator) Namespace() string {
+	return o.namespace
+}
+
+// List lists the objects defining webhooks.
+//
+// The Webhook objects are skipped if their custom resource is not installed.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//
+// Returns:
+//   - The resources sorted by their source.
+//   - An error if the API server cannot be reached.
+func (o *Operator) List(ctx context.Context) ([]Resource, error) {
+	resources, _, err := o.list(ctx)
+
+	return resources, err
+}
+
+// Run lists the objects and calls the sync function with them until the context is closed.
+//
+// The objects are listed again once the watches report a change, and at
+// least every resync interval. The errors of the API server are logged and
+// retried, so the webhooks synced last are kept while it is unavailable.
+//
+// Parameters:
+//   - ctx: The context.Context holding the logger and closing the Operator.
+//   - sync: The function called with the listed resources.
+func (o *Operator) Run(ctx context.Context, sync func(ctx context.Context, resources []Resource)) {
+	// The time waited before the objects are listed again after an error.
+	const retry = 5 * time.Second
+
+	logger := zerolog.Ctx(ctx)
+
+	for {
+		resources, versions, err := o.list(ctx)
+		if err == nil {
+			sync(ctx, resources)
+
+			err = o.watch(ctx, versions)
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+
+		wait := o.settle
+		if err != nil {
+			logger.Warn().Err(err).Str("namespace", o.namespace).Msg("Failed to watch the webhooks in Kubernetes")
+
+			wait = retry
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+	}
+}
+
+// list lists the Webhook objects and the annotated ConfigMaps.
+//
+// Returns:
+//   - The resources sorted by their source.
+//   - The resource versions of the listed collections by their URLs.
+//   - An error if the API server cannot be reached.
+func (o *Operator) list(ctx context.Context) ([]Resource, map[string]string, error) {
+	versions := make(map[string]string, 2) //nolint:mnd
+
+	var resources []Resource
+
+	webhooks, err := o.get(ctx, o.webhooks())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The custom resource is optional: the ConfigMaps are watched without it.
+	if webhooks != nil {
+		versions[o.webhooks()] = webhooks.Metadata.ResourceVersion
+
+		for _, item := range webhooks.Items {
+			resources = append(resources, Resource{Source: "Webhook/" + item.Metadata.Name, Spec: item.Spec, List: false})
+		}
+	}
+
+	configMaps, err := o.get(ctx, o.configMaps())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if configMaps != nil {
+		versions[o.configMaps()] = configMaps.Metadata.ResourceVersion
+
+		for _, item := range configMaps.Items {
+			if item.Metadata.Annotations[WebhooksAnnotation] != "true" {
+				continue
+			}
+
+			for key, data := range item.Data {
+				resources = append(resources, Resource{
+					Source: "ConfigMap/" + item.Metadata.Name + "/" + key,
+					Spec:   []byte(data),
+					List:   true,
+				})
+			}
+		}
+	}
+
+	slices.SortFunc(resources, func(a, b Resource) int {
+		return strings.Compare(a.Source, b.Source)
+	})
+
+	return resources, versions, nil
+}
+
+// watch watches the collections from their resource versions until one of
+// them changes or the resync interval passes.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - versions: The resource versions of the collections by their URLs.
+//
+// Returns:
+//   - An error if a collection cannot be watched.
+func (o *Operator) watch(ctx context.Context, versions map[string]string) error {
+	ctx, cancel := context.WithTimeout(ctx, o.resync)
+	defer cancel()
+
+	// Nothing can be watched: the objects are listed again on the resync.
+	if len(versions) == 0 {
+		<-ctx.Done()
+
+		return nil
+	}
+
+	done := make(chan error, len(versions))
+
+	for collection, version := range versions {
+		go func() {
+			done <- o.watchCollection(ctx, collection, version)
+		}()
+	}
+
+	// The first watch ending stops the others, so all objects are listed again.
+	err := <-done
+
+	cancel()
+
+	for range len(versions) - 1 {
+		<-done
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil
+	}
+
+	return err
+}
+
+// watchCollection streams the events of the collection until the first one.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the watch.
+//   - collection: The URL of the collection.
+//   - version: The resource version the changes are watched from.
+//
+// Returns:
+//   - nil once the collection changes or the server ends the watch.
+//   - An error if the collection cannot be watched.
+func (o *Operator) watchCollection(ctx context.Context, collection, version string) error {
+	query := url.Values{
+		"watch":           {"true"},
+		"resourceVersion": {version},
+		"timeoutSeconds":  {strconv.Itoa(int(o.resync / time.Second))},
+	}
+
+	resp, err := send(ctx, o.client, o.tokenFile, http.MethodGet, collection+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+	}
+
+	var event struct {
+		Type string `json:"type"`
+	}
+
+	// Any event, including the expiry of the resource version, lists the objects again.
+	if err := json.NewDecoder(resp.Body).Decode(&event); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	return nil
+}
+
+// get fetches the objects of the collection.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - collection: The URL of the collection.
+//
+// Returns:
+//   - The list of the objects, or nil if the collection does not exist.
+//   - An error if the API server cannot be reached.
+func (o *Operator) get(ctx context.Context, collection string) (*objectList, error) {
+	resp, err := send(ctx, o.client, o.tokenFile, http.MethodGet, collection, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return nil, nil //nolint:nilnil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+	}
+
+	var list objectList
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+// webhooks returns the URL of the Webhook objects of the namespace.
+func (o *Operator) webhooks() string {
+	return o.endpoint + "/apis/" + WebhookGroup + "/" + WebhookVersion + "/namespaces/" + url.PathEscape(o.namespace) + "/webhooks"
+}
+
+// configMaps returns the URL of the ConfigMaps of the namespace.
+func (o *Operator) configMaps() string {
+	return o.endpoint + "/api/v1/namespaces/" + url.PathEscape(o.namespace) + "/configmaps"
+}
@@ -0,0 +1,199 @@
+package kubernetes_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/kubernetes"
+)
+
+// objectServer is a fake API server holding the Webhook objects and the ConfigMaps of a namespace.
+type objectServer struct {
+	mu         sync.Mutex
+	crd        bool
+	webhooks   []map[string]any
+	configMaps []map[string]any
+	version    int
+	changed    chan struct{}
+}
+
+// ServeHTTP handles the lists and the watches of the collections.
+func (s *objectServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+
+	var items []map[string]any
+
+	switch r.URL.Path {
+	case "/apis/vakeel-way.bavix.dev/v1alpha1/namespaces/monitoring/webhooks":
+		if !s.crd {
+			s.mu.Unlock()
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		items = s.webhooks
+	case "/api/v1/namespaces/monitoring/configmaps":
+		items = s.configMaps
+	default:
+		s.mu.Unlock()
+		w.WriteHeader(http.StatusNotFound)
+
+		return
+	}
+
+	changed, version := s.changed, s.version
+	s.mu.Unlock()
+
+	if r.URL.Query().Get("watch") != "true" {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"metadata": map[string]any{"resourceVersion": strconv.Itoa(version)},
+			"items":    items,
+		})
+
+		return
+	}
+
+	// The changes since the resource version are sent right away.
+	if r.URL.Query().Get("resourceVersion") != strconv.Itoa(version) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"type": "MODIFIED", "object": map[string]any{}})
+
+		return
+	}
+
+	w.(http.Flusher).Flush() //nolint:forcetypeassert
+
+	select {
+	case <-changed:
+		_ = json.NewEncoder(w).Encode(map[string]any{"type": "MODIFIED", "object": map[string]any{}})
+	case <-r.Context().Done():
+	}
+}
+
+// change replaces the Webhook objects and notifies the watches.
+func (s *objectServer) change(webhooks ...map[string]any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.webhooks = webhooks
+	s.version++
+
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// webhook returns a Webhook object.
+func webhook(name, id string) map[string]any {
+	return map[string]any{
+		"metadata": map[string]any{"name": name},
+		"spec":     map[string]any{"id": id, "target": "https://example.com/" + name},
+	}
+}
+
+// OperatorTestSuite represents the test suite for the webhooks watched in Kubernetes.
+type OperatorTestSuite struct {
+	suite.Suite
+
+	api    *objectServer
+	server *httptest.Server
+}
+
+// SetupTest starts a fake API server with a Webhook and two ConfigMaps.
+func (suite *OperatorTestSuite) SetupTest() {
+	suite.api = &objectServer{
+		crd:      true,
+		webhooks: []map[string]any{webhook("payments", "2a6b1b1e-3bd4-4a4c-8a55-9f1d4f8d1a01")},
+		configMaps: []map[string]any{
+			{
+				"metadata": map[string]any{
+					"name":        "team-a",
+					"annotations": map[string]any{kubernetes.WebhooksAnnotation: "true"},
+				},
+				"data": map[string]any{"webhooks.yaml": "- id: 0b7d2c34-5f4c-4f49-9a4b-3e1f7a9c2b02\n"},
+			},
+			{
+				"metadata": map[string]any{"name": "unrelated"},
+				"data":     map[string]any{"app.yaml": "debug: true\n"},
+			},
+		},
+		changed: make(chan struct{}),
+	}
+
+	suite.server = httptest.NewServer(suite.api)
+}
+
+// TearDownTest stops the fake API server.
+func (suite *OperatorTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+// operator returns the Operator of the fake API server.
+func (suite *OperatorTestSuite) operator() *kubernetes.Operator {
+	return kubernetes.NewOperator("monitoring", kubernetes.WithOperatorEndpoint(suite.server.URL))
+}
+
+// TestOperator_List verifies that the Webhook objects and the annotated ConfigMaps are listed.
+func (suite *OperatorTestSuite) TestOperator_List() {
+	resources, err := suite.operator().List(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().Len(resources, 2)
+
+	suite.Equal("ConfigMap/team-a/webhooks.yaml", resources[0].Source)
+	suite.True(resources[0].List)
+	suite.Equal("- id: 0b7d2c34-5f4c-4f49-9a4b-3e1f7a9c2b02\n", string(resources[0].Spec))
+
+	suite.Equal("Webhook/payments", resources[1].Source)
+	suite.False(resources[1].List)
+	suite.JSONEq(`{"id":"2a6b1b1e-3bd4-4a4c-8a55-9f1d4f8d1a01","target":"https://example.com/payments"}`, string(resources[1].Spec))
+}
+
+// TestOperator_NoCRD verifies that the ConfigMaps are listed without the custom resource.
+func (suite *OperatorTestSuite) TestOperator_NoCRD() {
+	suite.api.crd = false
+
+	resources, err := suite.operator().List(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().Len(resources, 1)
+	suite.Equal("ConfigMap/team-a/webhooks.yaml", resources[0].Source)
+}
+
+// TestOperator_Run verifies that the objects are synced again once they change.
+func (suite *OperatorTestSuite) TestOperator_Run() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	synced := make(chan []kubernetes.Resource, 1)
+
+	go suite.operator().Run(ctx, func(_ context.Context, resources []kubernetes.Resource) {
+		synced <- resources
+	})
+
+	suite.Len(<-synced, 2)
+
+	suite.api.change(
+		webhook("payments", "2a6b1b1e-3bd4-4a4c-8a55-9f1d4f8d1a01"),
+		webhook("billing", "5c8e9d0f-1a2b-4c3d-8e4f-5a6b7c8d9e03"),
+	)
+
+	select {
+	case resources := <-synced:
+		suite.Require().Len(resources, 3)
+		suite.Equal("Webhook/billing", resources[1].Source)
+	case <-time.After(5 * time.Second):
+		suite.Fail("the change was not synced")
+	}
+}
+
+// TestOperatorTestSuite runs the test suite for the webhooks watched in Kubernetes.
+func TestOperatorTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(OperatorTestSuite))
+}
@@ -0,0 +1,143 @@
+package kuma
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/httpx"
+)
+
+// ErrUnexpectedStatus is an error that indicates that Uptime Kuma rejected the push.
+var ErrUnexpectedStatus = errors.New("kuma: unexpected response status")
+
+// ResponseError is an error returned when Uptime Kuma rejects the push.
+//
+// It wraps ErrUnexpectedStatus and carries the message reported by Uptime
+// Kuma, e.g. "Monitor not found or not active.".
+type ResponseError = httpx.ResponseError
+
+// API is a client for Uptime Kuma push monitors.
+//
+// The push monitor accepts a GET request to its push URL with the status,
+// the message and the response time of the service in the query.
+type API struct {
+	// client: The HTTP client used to make requests to Uptime Kuma.
+	client *http.Client
+}
+
+// Option is a function that can be used to configure an API instance.
+type Option func(*API)
+
+// WithClient returns an Option function that sets the http.Client used to send HTTP requests
+// to Uptime Kuma.
+func WithClient(c http.Client) Option {
+	return func(api *API) {
+		api.client = &c
+	}
+}
+
+// NewAPI creates a new Uptime Kuma API client.
+//
+// Parameters:
+//
+//	ops: A variadic number of Option functions.
+//
+// Returns:
+//
+//	A pointer to an API struct.
+//
+//nolint:exhaustruct
+func NewAPI(ops ...Option) *API {
+	// Create a new API struct with default settings for the http.Client.
+	api := &API{
+		client: &http.Client{},
+	}
+
+	// Apply all provided options to the API struct.
+	for _, op := range ops {
+		op(api)
+	}
+
+	return api
+}
+
+// response is the payload of the response of a push monitor.
+type response struct {
+	OK  bool   `json:"ok"`
+	Msg string `json:"msg"`
+}
+
+// Send pushes the status of the event to the push monitor of the target.
+//
+// The services that are up or degraded are pushed as "up", the others as
+// "down", and the rendered message is pushed as "msg". Uptime Kuma has no
+// degraded state, so the message of a degraded service is prefixed with the
+// status to carry the detail. The response time of the services is unknown,
+// so the "ping" parameter is removed rather than sent empty. The other query
+// parameters of the URL are kept.
+//
+// Parameters:
+// - ctx: The context.Context to use for the request.
+// - target: The entities.Target with the push URL of the monitor.
+// - event: The entities.Event to push the status of.
+//
+// Returns an error if the request cannot be sent, or a *ResponseError if
+// Uptime Kuma rejects the push.
+func (s *API) Send(ctx context.Context, target entities.Target, event entities.Event) error {
+	endpoint, err := url.Parse(target.URL)
+	if err != nil {
+		return err
+	}
+
+	// Uptime Kuma knows only whether the monitor is up or down. A degraded
+	// service still responds, so it is up.
+	status := "down"
+	if event.Status == entities.Up || event.Status == entities.Degraded {
+		status = "up"
+	}
+
+	// Use the rendered message, if any.
+	msg := event.Message
+
+	switch {
+	case msg == "":
+		msg = event.Status.String()
+	case event.Status == entities.Degraded:
+		msg = event.Status.String() + ": " + msg
+	}
+
+	query := endpoint.Query()
+	query.Set("status", status)
+	query.Set("msg", msg)
+	query.Del("ping")
+	endpoint.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Uptime Kuma reports the rejected pushes with {"ok": false}, with or
+	// without an error status code.
+	var payload response
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, httpx.MaxErrorBody))
+	decoded := json.Unmarshal(body, &payload) == nil
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices || (decoded && !payload.OK) {
+		return &ResponseError{Err: ErrUnexpectedStatus, StatusCode: resp.StatusCode, Code: "", Message: payload.Msg}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package kuma_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/kuma"
+)
+
+// ClientTestSuite represents the test suite for the Uptime Kuma client.
+type ClientTestSuite struct {
+	suite.Suite
+}
+
+// TestClient_Push verifies that the status and the message are pushed in the
+// query, and that the degraded services are pushed as up with the detail in
+// the message.
+func (suite *ClientTestSuite) TestClient_Push() {
+	var query url.Values
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.Equal(http.MethodGet, r.Method)
+		suite.Equal("/api/push/abc", r.URL.Path)
+
+		query = r.URL.Query()
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	}))
+	defer server.Close()
+
+	target := entities.Target{Name: "kuma", Type: "kuma", URL: server.URL + "/api/push/abc?status=up&msg=OK&ping="}
+
+	suite.Require().NoError(kuma.NewAPI().Send(context.Background(), target,
+		entities.Event{ID: uuid.New(), Status: entities.Degraded, Message: "disk is full"}))
+	suite.Equal("up", query.Get("status"))
+	suite.Equal("degraded: disk is full", query.Get("msg"))
+	suite.False(query.Has("ping"))
+
+	suite.Require().NoError(kuma.NewAPI().Send(context.Background(), target,
+		entities.Event{ID: uuid.New(), Status: entities.Up}))
+	suite.Equal("up", query.Get("status"))
+	suite.Equal("up", query.Get("msg"))
+
+	suite.Require().NoError(kuma.NewAPI().Send(context.Background(), target,
+		entities.Event{ID: uuid.New(), Status: entities.Down, Message: "no heartbeat"}))
+	suite.Equal("down", query.Get("status"))
+	suite.Equal("no heartbeat", query.Get("msg"))
+}
+
+// TestClient_Rejected verifies that the pushes rejected by Uptime Kuma are reported.
+func (suite *ClientTestSuite) TestClient_Rejected() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"ok": false, "msg": "Monitor not found or not active."}`))
+	}))
+	defer server.Close()
+
+	err := kuma.NewAPI().Send(context.Background(),
+		entities.Target{Name: "kuma", Type: "kuma", URL: server.URL + "/api/push/abc"},
+		entities.Event{ID: uuid.New(), Status: entities.Down})
+	suite.Require().ErrorIs(err, kuma.ErrUnexpectedStatus)
+
+	var rerr *kuma.ResponseError
+	suite.Require().ErrorAs(err, &rerr)
+	suite.Equal("Monitor not found or not active.", rerr.Message)
+	suite.False(rerr.Temporary())
+}
+
+// TestClientTestSuite runs the test suite for the Uptime Kuma client.
+func TestClientTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ClientTestSuite))
+}
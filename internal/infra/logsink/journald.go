@@ -0,0 +1,208 @@
+package logsink
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultJournalSocket is the socket of the native protocol of journald.
+const DefaultJournalSocket = "/run/systemd/journal/socket"
+
+// Journald is a zerolog.LevelWriter that sends the logs to journald over its
+// native protocol.
+//
+// The fields of the JSON events are sent as the fields of the journal
+// entries, so they can be filtered with journalctl, e.g.
+// journalctl ID=224f8a59-6705-4f3e-b7de-177757932aad. The names of the fields
+// are upper-cased and the characters journald rejects are replaced with
+// underscores. The level is mapped to the syslog priority.
+type Journald struct {
+	// conn is the datagram connection to the socket of journald.
+	conn *net.UnixConn
+
+	// identifier is the SYSLOG_IDENTIFIER of the entries.
+	identifier string
+
+	// mu serializes the writes, so the buffer is reused.
+	mu sync.Mutex
+
+	// buf is the buffer of the entry being sent.
+	buf bytes.Buffer
+}
+
+// DialJournald connects to the socket of journald.
+//
+// Parameters:
+//   - socket: The path to the socket, or empty for DefaultJournalSocket.
+//   - identifier: The SYSLOG_IDENTIFIER of the entries.
+//
+// Returns:
+//   - A pointer to the connected Journald.
+//   - An error if the socket cannot be reached.
+//
+//nolint:exhaustruct
+func DialJournald(socket, identifier string) (*Journald, error) {
+	if socket == "" {
+		socket = DefaultJournalSocket
+	}
+
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Journald{conn: conn, identifier: identifier}, nil
+}
+
+// Write sends the event without a level as an informational entry.
+func (j *Journald) Write(p []byte) (int, error) {
+	return j.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel sends the JSON event as a journal entry with the priority of the level.
+//
+// The events that are not JSON objects are sent as the message of the entry.
+func (j *Journald) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.buf.Reset()
+	j.field("PRIORITY", priority(level))
+
+	if j.identifier != "" {
+		j.field("SYSLOG_IDENTIFIER", j.identifier)
+	}
+
+	var event map[string]json.RawMessage
+	if err := json.Unmarshal(p, &event); err != nil {
+		j.field("MESSAGE", strings.TrimSuffix(string(p), "\n"))
+	} else {
+		j.fields(event)
+	}
+
+	if _, err := j.conn.Write(j.buf.Bytes()); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Close closes the connection to journald.
+func (j *Journald) Close() error {
+	return j.conn.Close()
+}
+
+// fields appends the fields of the JSON event, sorted by name.
+//
+// Parameters:
+//   - event: The fields of the event.
+func (j *Journald) fields(event map[string]json.RawMessage) {
+	names := make([]string, 0, len(event))
+	for name := range event {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		// The level is sent as the priority.
+		if name == zerolog.LevelFieldName {
+			continue
+		}
+
+		key := fieldName(name)
+		if name == zerolog.MessageFieldName {
+			key = "MESSAGE"
+		}
+
+		// The strings are sent unquoted, the other values as JSON.
+		var value string
+		if err := json.Unmarshal(event[name], &value); err != nil {
+			value = string(event[name])
+		}
+
+		j.field(key, value)
+	}
+}
+
+// field appends a field to the entry.
+//
+// The values with a new line are sent in the binary format of the protocol.
+//
+// Parameters:
+//   - key: The name of the field.
+//   - value: The value of the field.
+func (j *Journald) field(key, value string) {
+	j.buf.WriteString(key)
+
+	if !strings.Contains(value, "\n") {
+		j.buf.WriteByte('=')
+		j.buf.WriteString(value)
+		j.buf.WriteByte('\n')
+
+		return
+	}
+
+	j.buf.WriteByte('\n')
+	_ = binary.Write(&j.buf, binary.LittleEndian, uint64(len(value)))
+	j.buf.WriteString(value)
+	j.buf.WriteByte('\n')
+}
+
+// fieldName converts the name of a JSON field to the name of a journal field.
+//
+// The journal fields consist of upper-case letters, digits and underscores,
+// and do not start with an underscore or a digit.
+//
+// Parameters:
+//   - name: The name of the JSON field.
+//
+// Returns:
+//   - The name of the journal field.
+func fieldName(name string) string {
+	key := []byte(strings.ToUpper(name))
+
+	for i, c := range key {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			key[i] = '_'
+		}
+	}
+
+	if len(key) == 0 || key[0] == '_' || (key[0] >= '0' && key[0] <= '9') {
+		return "F_" + string(key)
+	}
+
+	return string(key)
+}
+
+// priority maps the level to the syslog priority.
+//
+// Parameters:
+//   - level: The zerolog level.
+//
+// Returns:
+//   - The syslog priority, from "0" (emergency) to "7" (debug).
+func priority(level zerolog.Level) string {
+	//nolint:exhaustive
+	switch level {
+	case zerolog.TraceLevel, zerolog.DebugLevel:
+		return "7"
+	case zerolog.WarnLevel:
+		return "4"
+	case zerolog.ErrorLevel:
+		return "3"
+	case zerolog.FatalLevel:
+		return "2"
+	case zerolog.PanicLevel:
+		return "0"
+	default:
+		return "6"
+	}
+}
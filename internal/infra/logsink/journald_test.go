@@ -0,0 +1,85 @@
+package logsink_test
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/logsink"
+)
+
+// JournaldTestSuite represents the test suite for the journald writer.
+type JournaldTestSuite struct {
+	suite.Suite
+}
+
+// listen creates a socket standing in for journald.
+func (suite *JournaldTestSuite) listen() (*net.UnixConn, string) {
+	socket := filepath.Join(suite.T().TempDir(), "journal.socket")
+
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	suite.Require().NoError(err)
+	suite.T().Cleanup(func() { _ = conn.Close() })
+
+	return conn, socket
+}
+
+// receive reads an entry from the socket.
+func (suite *JournaldTestSuite) receive(conn *net.UnixConn) string {
+	buf := make([]byte, 4096)
+
+	n, err := conn.Read(buf)
+	suite.Require().NoError(err)
+
+	return string(buf[:n])
+}
+
+// TestJournald_Fields verifies that the fields of the events are sent as the
+// fields of the entries, and the level as the priority.
+func (suite *JournaldTestSuite) TestJournald_Fields() {
+	conn, socket := suite.listen()
+
+	writer, err := logsink.DialJournald(socket, "vakeel-way")
+	suite.Require().NoError(err)
+
+	defer writer.Close()
+
+	logger := zerolog.New(writer)
+	logger.Warn().Str("id", "224f8a59").Int("retry-count", 3).Msg("Delivery failed")
+
+	entry := suite.receive(conn)
+	suite.Contains(entry, "PRIORITY=4\n")
+	suite.Contains(entry, "SYSLOG_IDENTIFIER=vakeel-way\n")
+	suite.Contains(entry, "MESSAGE=Delivery failed\n")
+	suite.Contains(entry, "ID=224f8a59\n")
+	suite.Contains(entry, "RETRY_COUNT=3\n")
+	suite.NotContains(entry, "LEVEL=")
+}
+
+// TestJournald_Multiline verifies that the values with a new line are sent in
+// the binary format.
+func (suite *JournaldTestSuite) TestJournald_Multiline() {
+	conn, socket := suite.listen()
+
+	writer, err := logsink.DialJournald(socket, "")
+	suite.Require().NoError(err)
+
+	defer writer.Close()
+
+	logger := zerolog.New(writer)
+	logger.Error().Msg("first\nsecond")
+
+	entry := suite.receive(conn)
+	suite.Contains(entry, "PRIORITY=3\n")
+	suite.Contains(entry, "MESSAGE\n\x0c\x00\x00\x00\x00\x00\x00\x00first\nsecond\n")
+	suite.NotContains(entry, "SYSLOG_IDENTIFIER")
+}
+
+// TestJournaldTestSuite runs the test suite for the journald writer.
+func TestJournaldTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(JournaldTestSuite))
+}
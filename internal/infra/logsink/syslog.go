@@ -0,0 +1,31 @@
+//go:build !windows && !plan9
+
+package logsink
+
+import (
+	"log/syslog"
+
+	"github.com/rs/zerolog"
+)
+
+// DialSyslog connects to a syslog daemon.
+//
+// The levels are mapped to the syslog priorities, and the JSON events are
+// sent as the messages.
+//
+// Parameters:
+//   - network: The network of the daemon, e.g. "udp", or empty for the local daemon.
+//   - address: The address of the daemon, or empty for the local daemon.
+//   - tag: The tag of the messages.
+//
+// Returns:
+//   - The zerolog.LevelWriter of the daemon.
+//   - An error if the daemon cannot be reached.
+func DialSyslog(network, address, tag string) (zerolog.LevelWriter, error) {
+	writer, err := syslog.Dial(network, address, syslog.LOG_INFO|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	return zerolog.SyslogLevelWriter(writer), nil
+}
@@ -0,0 +1,17 @@
+//go:build windows || plan9
+
+package logsink
+
+import (
+	"errors"
+
+	"github.com/rs/zerolog"
+)
+
+// ErrSyslogUnsupported is an error that indicates that syslog is not available on the platform.
+var ErrSyslogUnsupported = errors.New("logsink: syslog is not supported on this platform")
+
+// DialSyslog reports that syslog is not available on the platform.
+func DialSyslog(_, _, _ string) (zerolog.LevelWriter, error) {
+	return nil, ErrSyslogUnsupported
+}
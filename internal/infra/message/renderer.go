@@ -0,0 +1,188 @@
+package message
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// DefaultTemplate is the template used when no template is configured.
+const DefaultTemplate = `{{ if .Warning }}Early warning: {{ end }}Service {{ .ID }} is {{ .Status }}` +
+	`{{ with .Reason }}: {{ . }}{{ end }}` +
+	`{{ if .Caused }}, caused by {{ .Cause }} being down{{ end }}` +
+	`{{ if and (eq .Status.String "down") (not .LastSeen.IsZero) }}, last seen {{ since .LastSeen }} ago{{ end }}` +
+	`{{ if and (eq .Status.String "up") (not .Since.IsZero) }} after {{ duration .Duration }} of downtime{{ end }}` +
+	`{{ if .Unreachable }}, the service kept running but was unreachable{{ end }}` +
+	`{{ with .Agent.Hostname }}, reported by {{ . }}{{ end }}` +
+	`{{ with .Missed }} (missed: {{ range $i, $e := . }}{{ if $i }}, {{ end }}{{ $e.Status }} at {{ timestamp $e.Time }}{{ end }}){{ end }}`
+
+// Renderer renders the notification messages from Go text/template templates.
+//
+// The templates are executed with the entities.Event as data, so the agent
+// reporting the service is available as .Agent, e.g. {{ .Agent.Hostname }}.
+// The templates have access to the following functions:
+//   - duration: formats a time.Duration in a human-readable way, e.g. "1h 2m 3s".
+//   - since: returns the time elapsed since the given time, formatted like duration.
+//   - timestamp: formats a time.Time as RFC 3339 in UTC.
+//   - formatTime: formats a time.Time with the given layout.
+//   - upper, lower: change the case of a string.
+//   - json: encodes a value as JSON, e.g. {"message": {{ json .Message }}}.
+type Renderer struct {
+	// fallback is the template used for targets without their own template.
+	fallback string
+
+	// templates caches the parsed templates by their source.
+	templates map[string]*template.Template
+
+	// mu is the mutex used to synchronize access to the templates.
+	mu sync.Mutex
+
+	// now returns the current time.
+	now func() time.Time
+}
+
+// NewRenderer creates a new instance of the Renderer struct.
+//
+// Parameters:
+//   - fallback: The global template used for targets without their own template.
+//     If it is empty, DefaultTemplate is used.
+//
+// Returns:
+//   - A pointer to the initialized Renderer.
+//   - An error if the global template cannot be parsed.
+func NewRenderer(fallback string) (*Renderer, error) {
+	if fallback == "" {
+		fallback = DefaultTemplate
+	}
+
+	renderer := &Renderer{
+		fallback:  fallback,
+		templates: make(map[string]*template.Template),
+		mu:        sync.Mutex{},
+		now:       time.Now,
+	}
+
+	// Parse the global template upfront to report errors on startup.
+	if err := renderer.Compile(fallback); err != nil {
+		return nil, err
+	}
+
+	return renderer, nil
+}
+
+// Compile parses the template and caches it.
+//
+// It is used to validate the configured templates on startup.
+//
+// Parameters:
+//   - source: The source of the template.
+//
+// Returns:
+//   - An error if the template cannot be parsed.
+func (r *Renderer) Compile(source string) error {
+	_, err := r.parse(source)
+
+	return err
+}
+
+// Render renders the message of the event.
+//
+// Parameters:
+//   - source: The source of the template. If it is empty, the global template is used.
+//   - event: The entities.Event the message is rendered for.
+//
+// Returns:
+//   - The rendered message.
+//   - An error if the template cannot be parsed or executed.
+func (r *Renderer) Render(source string, event entities.Event) (string, error) {
+	if source == "" {
+		source = r.fallback
+	}
+
+	tpl, err := r.parse(source)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.Execute(&buf, event); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// parse returns the parsed template from the cache, or parses it.
+func (r *Renderer) parse(source string) (*template.Template, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if tpl, ok := r.templates[source]; ok {
+		return tpl, nil
+	}
+
+	tpl, err := template.New("message").Funcs(r.funcs()).Parse(source)
+	if err != nil {
+		return nil, fmt.Errorf("message: %w", err)
+	}
+
+	r.templates[source] = tpl
+
+	return tpl, nil
+}
+
+// funcs returns the functions available in the templates.
+func (r *Renderer) funcs() template.FuncMap {
+	return template.FuncMap{
+		"duration": Duration,
+		"since": func(t time.Time) string {
+			return Duration(r.now().Sub(t))
+		},
+		"timestamp": func(t time.Time) string {
+			return t.UTC().Format(time.RFC3339)
+		},
+		"formatTime": func(layout string, t time.Time) string {
+			return t.Format(layout)
+		},
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+
+			return string(data), err
+		},
+	}
+}
+
+// Duration formats the duration in a human-readable way, e.g. "1h 2m 3s".
+//
+// The duration is rounded to seconds; durations shorter than a second are
+// formatted as "0s".
+func Duration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d <= 0 {
+		return "0s"
+	}
+
+	const day = 24 * time.Hour
+
+	var parts []string
+
+	for _, unit := range []struct {
+		size   time.Duration
+		suffix string
+	}{{day, "d"}, {time.Hour, "h"}, {time.Minute, "m"}, {time.Second, "s"}} {
+		if d >= unit.size {
+			parts = append(parts, fmt.Sprintf("%d%s", d/unit.size, unit.suffix))
+			d %= unit.size
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
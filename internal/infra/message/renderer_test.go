@@ -0,0 +1,76 @@
+package message_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/message"
+)
+
+// RendererTestSuite represents the test suite for the message renderer.
+type RendererTestSuite struct {
+	suite.Suite
+}
+
+// TestRenderer_Default verifies that the built-in template reports the downtime.
+func (suite *RendererTestSuite) TestRenderer_Default() {
+	renderer, err := message.NewRenderer("")
+	suite.Require().NoError(err)
+
+	now := time.Now()
+	id := uuid.MustParse("224f8a59-6705-4f3e-b7de-177757932aad")
+
+	text, err := renderer.Render("", entities.Event{
+		ID:     id,
+		Status: entities.Up,
+		Time:   now,
+		Since:  now.Add(-90 * time.Minute),
+	})
+	suite.Require().NoError(err)
+	suite.Equal("Service 224f8a59-6705-4f3e-b7de-177757932aad is up after 1h 30m of downtime", text)
+}
+
+// TestRenderer_Warning verifies that the built-in template tells the early warnings apart.
+func (suite *RendererTestSuite) TestRenderer_Warning() {
+	renderer, err := message.NewRenderer("")
+	suite.Require().NoError(err)
+
+	text, err := renderer.Render("", entities.Event{
+		Kind:   entities.KindWarning,
+		ID:     uuid.MustParse("224f8a59-6705-4f3e-b7de-177757932aad"),
+		Status: entities.Up,
+		Reason: "no heartbeat for 45s, usually every 10s, the status expires after 1m0s",
+	})
+	suite.Require().NoError(err)
+	suite.Equal("Early warning: Service 224f8a59-6705-4f3e-b7de-177757932aad is up: "+
+		"no heartbeat for 45s, usually every 10s, the status expires after 1m0s", text)
+}
+
+// TestRenderer_Custom verifies that a custom template has access to the functions.
+func (suite *RendererTestSuite) TestRenderer_Custom() {
+	renderer, err := message.NewRenderer("")
+	suite.Require().NoError(err)
+
+	text, err := renderer.Render(`{{ .Status.String | upper }} at {{ timestamp .Time }}`, entities.Event{
+		Status: entities.Down,
+		Time:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	})
+	suite.Require().NoError(err)
+	suite.Equal("DOWN at 2024-01-02T03:04:05Z", text)
+
+	text, err = renderer.Render(`{"message": {{ json .Message }}}`, entities.Event{Message: `say "hi"`})
+	suite.Require().NoError(err)
+	suite.Equal(`{"message": "say \"hi\""}`, text)
+
+	suite.Require().Error(renderer.Compile(`{{ .Status`))
+}
+
+// TestRendererTestSuite runs the renderer test suite.
+func TestRendererTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(RendererTestSuite))
+}
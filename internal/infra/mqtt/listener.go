@@ -0,0 +1,166 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// ErrTimeout is an error that indicates that the broker did not accept the connection in time.
+var ErrTimeout = errors.New("mqtt: timed out connecting to the broker")
+
+// Listener subscribes to the heartbeats the devices publish to an MQTT broker.
+//
+// Every message published to a topic matching the pattern is a heartbeat of
+// the service the topic resolves to, whatever its payload, so the devices
+// only have to publish to their topic. The subscription is restored after the
+// connection to the broker is lost.
+type Listener struct {
+	// options are the options of the MQTT client.
+	options *paho.ClientOptions
+
+	// topics maps the topics to the UUIDs of the services.
+	topics *Topics
+
+	// qos is the quality of service of the subscription.
+	qos byte
+
+	// timeout is the time allowed to connect and subscribe.
+	timeout time.Duration
+}
+
+// Option is a function that can be used to configure a Listener instance.
+type Option func(*Listener)
+
+// WithCredentials returns an Option that sets the credentials of the broker.
+//
+// Parameters:
+//   - username: The username of the broker.
+//   - password: The password of the broker.
+//
+// Returns:
+//   - An Option that sets the credentials of the Listener.
+func WithCredentials(username, password string) Option {
+	return func(l *Listener) {
+		l.options.SetUsername(username)
+		l.options.SetPassword(password)
+	}
+}
+
+// WithClientID returns an Option that sets the client ID of the listener.
+//
+// Parameters:
+//   - id: The client ID, unique per broker.
+//
+// Returns:
+//   - An Option that sets the client ID of the Listener.
+func WithClientID(id string) Option {
+	return func(l *Listener) {
+		l.options.SetClientID(id)
+	}
+}
+
+// WithQoS returns an Option that sets the quality of service of the subscription.
+//
+// Parameters:
+//   - qos: The quality of service, 0, 1 or 2. The greater values are ignored.
+//
+// Returns:
+//   - An Option that sets the quality of service of the Listener.
+func WithQoS(qos byte) Option {
+	return func(l *Listener) {
+		if qos <= 2 { //nolint:mnd
+			l.qos = qos
+		}
+	}
+}
+
+// NewListener creates a new instance of the Listener struct.
+//
+// Parameters:
+//   - broker: The URL of the broker, e.g. "tcp://127.0.0.1:1883".
+//   - topics: The Topics mapping the topics to the UUIDs of the services.
+//   - options: Optional configurations for the Listener.
+//
+// Returns:
+//   - A pointer to the initialized Listener.
+func NewListener(broker string, topics *Topics, options ...Option) *Listener {
+	const timeout = 10 * time.Second
+
+	listener := &Listener{
+		options: paho.NewClientOptions().
+			AddBroker(broker).
+			SetClientID("vakeel-way-" + uuid.NewString()[:8]).
+			SetAutoReconnect(true).
+			SetCleanSession(true),
+		topics:  topics,
+		qos:     0,
+		timeout: timeout,
+	}
+
+	// Apply any optional configurations provided through the options parameter.
+	for _, option := range options {
+		option(listener)
+	}
+
+	return listener
+}
+
+// Run subscribes to the heartbeats until the context is canceled.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the listener.
+//   - handle: The function receiving the UUIDs of the heartbeats.
+//
+// Returns:
+//   - An error if the broker cannot be reached on startup.
+func (l *Listener) Run(ctx context.Context, handle func(id uuid.UUID)) error {
+	logger := zerolog.Ctx(ctx)
+	filter := l.topics.Filter()
+
+	receive := func(_ paho.Client, msg paho.Message) {
+		id, ok := l.topics.Resolve(msg.Topic())
+		if !ok {
+			logger.Debug().Str("topic", msg.Topic()).Msg("mqtt: the topic resolves to no service")
+
+			return
+		}
+
+		handle(id)
+	}
+
+	// The subscription is restored on every connection, as the session is clean.
+	options := *l.options
+	options.SetOnConnectHandler(func(client paho.Client) {
+		token := client.Subscribe(filter, l.qos, receive)
+		if token.WaitTimeout(l.timeout) && token.Error() != nil {
+			logger.Err(token.Error()).Str("filter", filter).Msg("mqtt: failed to subscribe")
+		}
+	})
+	options.SetConnectionLostHandler(func(_ paho.Client, err error) {
+		logger.Warn().Err(err).Msg("mqtt: connection lost")
+	})
+
+	client := paho.NewClient(&options)
+
+	token := client.Connect()
+	if !token.WaitTimeout(l.timeout) {
+		return ErrTimeout
+	}
+
+	if err := token.Error(); err != nil {
+		return err
+	}
+
+	<-ctx.Done()
+
+	const quiesce = 250 // The milliseconds allowed to complete the work in progress.
+
+	client.Disconnect(quiesce)
+
+	return nil
+}
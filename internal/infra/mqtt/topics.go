@@ -0,0 +1,105 @@
+package mqtt
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Placeholder is the topic segment holding the UUID or the alias of the service.
+const Placeholder = "{id}"
+
+// ErrInvalidTopic is an error that indicates that the topic pattern has no single placeholder segment.
+var ErrInvalidTopic = errors.New("mqtt: the topic must have a single {id} segment and no wildcards")
+
+// Topics maps the topics of the heartbeats to the UUIDs of the services.
+//
+// The pattern of the topics has a placeholder segment, e.g.
+// "devices/{id}/alive": the segment of a topic matching the pattern is either
+// the UUID of the service, or an alias mapped to it, so the devices do not
+// have to know the UUIDs.
+type Topics struct {
+	// segments are the segments of the pattern.
+	segments []string
+
+	// index is the index of the placeholder segment.
+	index int
+
+	// aliases maps the aliases to the UUIDs of the services.
+	aliases map[string]uuid.UUID
+}
+
+// NewTopics creates a new instance of the Topics struct.
+//
+// Parameters:
+//   - pattern: The pattern of the topics, with a single {id} segment.
+//   - aliases: The UUIDs of the services by their aliases, or nil.
+//
+// Returns:
+//   - A pointer to the Topics.
+//   - ErrInvalidTopic if the pattern has no single placeholder segment, or has wildcards.
+func NewTopics(pattern string, aliases map[string]uuid.UUID) (*Topics, error) {
+	segments := strings.Split(pattern, "/")
+	index := -1
+
+	for i, segment := range segments {
+		switch segment {
+		case Placeholder:
+			if index >= 0 {
+				return nil, ErrInvalidTopic
+			}
+
+			index = i
+		case "+", "#":
+			return nil, ErrInvalidTopic
+		}
+	}
+
+	if index < 0 {
+		return nil, ErrInvalidTopic
+	}
+
+	return &Topics{segments: segments, index: index, aliases: aliases}, nil
+}
+
+// Filter returns the topic filter subscribed to, with a wildcard in place of
+// the placeholder segment.
+func (t *Topics) Filter() string {
+	segments := make([]string, len(t.segments))
+	copy(segments, t.segments)
+	segments[t.index] = "+"
+
+	return strings.Join(segments, "/")
+}
+
+// Resolve returns the UUID of the service the topic belongs to.
+//
+// Parameters:
+//   - topic: The topic of the heartbeat.
+//
+// Returns:
+//   - The UUID of the service.
+//   - A boolean indicating whether the topic matches the pattern, and its
+//     segment is a UUID or a known alias.
+func (t *Topics) Resolve(topic string) (uuid.UUID, bool) {
+	segments := strings.Split(topic, "/")
+	if len(segments) != len(t.segments) {
+		return uuid.Nil, false
+	}
+
+	for i, segment := range t.segments {
+		if i != t.index && segments[i] != segment {
+			return uuid.Nil, false
+		}
+	}
+
+	segment := segments[t.index]
+	if id, ok := t.aliases[segment]; ok {
+		return id, true
+	}
+
+	id, err := uuid.Parse(segment)
+
+	return id, err == nil
+}
@@ -0,0 +1,65 @@
+package mqtt_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/mqtt"
+)
+
+// TopicsTestSuite represents the test suite for the topic mapping functionality.
+type TopicsTestSuite struct {
+	suite.Suite
+}
+
+// TestTopics_Resolve verifies that the topics resolve to the UUIDs of the
+// services by their UUID or alias segment.
+func (suite *TopicsTestSuite) TestTopics_Resolve() {
+	boiler := uuid.New()
+
+	topics, err := mqtt.NewTopics("devices/{id}/alive", map[string]uuid.UUID{"boiler-1": boiler})
+	suite.Require().NoError(err)
+	suite.Equal("devices/+/alive", topics.Filter())
+
+	id, ok := topics.Resolve("devices/boiler-1/alive")
+	suite.True(ok)
+	suite.Equal(boiler, id)
+
+	other := uuid.New()
+	id, ok = topics.Resolve("devices/" + other.String() + "/alive")
+	suite.True(ok)
+	suite.Equal(other, id)
+
+	for _, topic := range []string{
+		"devices/boiler-2/alive",
+		"devices/boiler-1/dead",
+		"devices/boiler-1/alive/extra",
+		"sensors/boiler-1/alive",
+	} {
+		_, ok := topics.Resolve(topic)
+		suite.False(ok, topic)
+	}
+}
+
+// TestTopics_Invalid verifies that the patterns without a single placeholder
+// segment, or with wildcards, are rejected.
+func (suite *TopicsTestSuite) TestTopics_Invalid() {
+	for _, pattern := range []string{
+		"devices/alive",
+		"devices/{id}/{id}",
+		"devices/{id}/+",
+		"devices/{id}/#",
+		"devices/x{id}/alive",
+	} {
+		_, err := mqtt.NewTopics(pattern, nil)
+		suite.Require().ErrorIs(err, mqtt.ErrInvalidTopic, pattern)
+	}
+}
+
+// TestTopicsTestSuite runs the test suite for the topic mapping functionality.
+func TestTopicsTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(TopicsTestSuite))
+}
@@ -0,0 +1,163 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// ErrUnknownType is an error that indicates that no sender is registered for the target type.
+var ErrUnknownType = errors.New("notifier: unknown target type")
+
+// Sender represents an interface for delivering events to a single kind of target.
+type Sender interface {
+	// Send delivers the event to the target.
+	//
+	// Parameters:
+	//   - ctx: The context.Context used to cancel the operation if needed.
+	//   - target: The entities.Target to deliver the event to.
+	//   - event: The entities.Event to deliver.
+	//
+	// Returns:
+	//   - An error if the event cannot be delivered.
+	Send(ctx context.Context, target entities.Target, event entities.Event) error
+}
+
+// Renderer represents an interface for rendering the notification messages.
+type Renderer interface {
+	// Render renders the message of the event from the template source.
+	//
+	// Parameters:
+	//   - source: The source of the template, or an empty string for the global template.
+	//   - event: The entities.Event the message is rendered for.
+	//
+	// Returns:
+	//   - The rendered message.
+	//   - An error if the template cannot be rendered.
+	Render(source string, event entities.Event) (string, error)
+}
+
+// Mux routes the events to the sender registered for the type of the target.
+//
+// It allows a single webhook to fan out to several kinds of targets (e.g.
+// Instatus, Slack and PagerDuty).
+type Mux struct {
+	// senders maps the target types to the senders.
+	senders map[string]Sender
+
+	// renderer renders the message of the event before it is delivered. It is optional.
+	renderer Renderer
+}
+
+// Option is a function that can be used to configure a Mux instance.
+type Option func(*Mux)
+
+// WithRenderer returns an Option that sets the Renderer of the notification messages.
+//
+// Parameters:
+//   - renderer: The Renderer used to render the message of every event.
+//
+// Returns:
+//   - An Option that sets the Renderer of the Mux.
+func WithRenderer(renderer Renderer) Option {
+	return func(m *Mux) {
+		m.renderer = renderer
+	}
+}
+
+// NewMux creates a new instance of the Mux struct without any senders.
+//
+// Parameters:
+//   - options: Optional configurations for the Mux.
+//
+// Returns:
+//   - A pointer to the initialized Mux.
+//
+//nolint:exhaustruct
+func NewMux(options ...Option) *Mux {
+	mux := &Mux{senders: make(map[string]Sender)}
+
+	// Apply any optional configurations provided through the options parameter.
+	for _, option := range options {
+		option(mux)
+	}
+
+	return mux
+}
+
+// Handle registers the sender for the target type.
+//
+// Parameters:
+//   - typ: The type of the target, e.g. "slack".
+//   - sender: The Sender used to deliver the events to the targets of this type.
+func (m *Mux) Handle(typ string, sender Sender) {
+	m.senders[typ] = sender
+}
+
+// Supports reports whether a sender is registered for the target type.
+func (m *Mux) Supports(typ string) bool {
+	_, ok := m.senders[typ]
+
+	return ok
+}
+
+// Types returns the sorted list of the target types with a registered sender.
+func (m *Mux) Types() []string {
+	types := make([]string, 0, len(m.senders))
+	for typ := range m.senders {
+		types = append(types, typ)
+	}
+
+	slices.Sort(types)
+
+	return types
+}
+
+// Send delivers the event to the target using the sender registered for its type.
+//
+// If a Renderer is set, the message of the event is rendered from the template
+// of the target before the event is delivered, and so are the body and the
+// title of the target if it has them.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - target: The entities.Target to deliver the event to.
+//   - event: The entities.Event to deliver.
+//
+// Returns:
+//   - ErrUnknownType if no sender is registered for the type of the target.
+//   - An error if the event cannot be delivered.
+func (m *Mux) Send(ctx context.Context, target entities.Target, event entities.Event) error {
+	sender, ok := m.senders[target.Type]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownType, target.Type)
+	}
+
+	// Render the message of the event.
+	if m.renderer != nil {
+		message, err := m.renderer.Render(target.Template, event)
+		if err != nil {
+			return err
+		}
+
+		event.Message = message
+
+		// Render the body and the title after the message, so it is available as .Message.
+		if target.Body != "" {
+			if target.Body, err = m.renderer.Render(target.Body, event); err != nil {
+				return err
+			}
+		}
+
+		if target.Title != "" {
+			if target.Title, err = m.renderer.Render(target.Title, event); err != nil {
+				return err
+			}
+		}
+	}
+
+	return sender.Send(ctx, target, event)
+}
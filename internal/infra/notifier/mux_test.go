@@ -0,0 +1,95 @@
+package notifier_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/notifier"
+)
+
+// capturingSender records the targets and the events it delivers.
+type capturingSender struct {
+	targets []entities.Target
+	events  []entities.Event
+}
+
+// Send records the target and the event.
+func (s *capturingSender) Send(_ context.Context, target entities.Target, event entities.Event) error {
+	s.targets = append(s.targets, target)
+	s.events = append(s.events, event)
+
+	return nil
+}
+
+// upperRenderer renders the templates by upper-casing them and appending the status.
+type upperRenderer struct{}
+
+// Render returns the upper-cased source followed by the status of the event.
+func (upperRenderer) Render(source string, event entities.Event) (string, error) {
+	return strings.ToUpper(source) + " " + event.Status.String(), nil
+}
+
+// MuxTestSuite represents the test suite for the routing of the events.
+type MuxTestSuite struct {
+	suite.Suite
+}
+
+// TestMux_Routes verifies that the events are routed by the type of the target.
+func (suite *MuxTestSuite) TestMux_Routes() {
+	slack, pagerduty := &capturingSender{}, &capturingSender{}
+
+	mux := notifier.NewMux()
+	mux.Handle("slack", slack)
+	mux.Handle("pagerduty", pagerduty)
+
+	suite.True(mux.Supports("slack"))
+	suite.False(mux.Supports("teams"))
+	suite.Equal([]string{"pagerduty", "slack"}, mux.Types())
+
+	event := entities.Event{ID: uuid.New(), Status: entities.Down}
+
+	suite.Require().NoError(mux.Send(context.Background(), entities.Target{Name: "chat", Type: "slack"}, event))
+	suite.Require().NoError(mux.Send(context.Background(), entities.Target{Name: "oncall", Type: "pagerduty"}, event))
+
+	suite.Require().Len(slack.targets, 1)
+	suite.Equal("chat", slack.targets[0].Name)
+	suite.Require().Len(pagerduty.targets, 1)
+	suite.Equal("oncall", pagerduty.targets[0].Name)
+
+	// The events of the unknown types are rejected.
+	err := mux.Send(context.Background(), entities.Target{Name: "teams", Type: "teams"}, event)
+	suite.Require().ErrorIs(err, notifier.ErrUnknownType)
+}
+
+// TestMux_Render verifies that the message, the body and the title are rendered before the delivery.
+func (suite *MuxTestSuite) TestMux_Render() {
+	sender := &capturingSender{}
+
+	mux := notifier.NewMux(notifier.WithRenderer(upperRenderer{}))
+	mux.Handle("webhook", sender)
+
+	target := entities.Target{Name: "hook", Type: "webhook", Template: "message", Body: "body", Title: "title"}
+	suite.Require().NoError(mux.Send(context.Background(), target, entities.Event{ID: uuid.New(), Status: entities.Up}))
+
+	suite.Require().Len(sender.events, 1)
+	suite.Equal("MESSAGE up", sender.events[0].Message)
+	suite.Equal("BODY up", sender.targets[0].Body)
+	suite.Equal("TITLE up", sender.targets[0].Title)
+
+	// The targets without a body or a title keep them empty.
+	suite.Require().NoError(mux.Send(context.Background(), entities.Target{Name: "hook", Type: "webhook"}, entities.Event{Status: entities.Down}))
+	suite.Equal(" down", sender.events[1].Message)
+	suite.Empty(sender.targets[1].Body)
+	suite.Empty(sender.targets[1].Title)
+}
+
+// TestMuxTestSuite runs the test suite for the routing of the events.
+func TestMuxTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(MuxTestSuite))
+}
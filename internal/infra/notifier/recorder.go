@@ -0,0 +1,88 @@
+package notifier
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Request is an HTTP request recorded by the Recorder instead of being sent.
+type Request struct {
+	// Method is the HTTP method of the request.
+	Method string
+
+	// URL is the URL the request would be sent to.
+	URL string
+
+	// Header is the header of the request.
+	Header http.Header
+
+	// Body is the payload of the request.
+	Body []byte
+}
+
+// Recorder is an http.RoundTripper that records the requests instead of sending them.
+//
+// It is used to preview the exact payloads of the senders: every recorded request
+// is answered with 202 Accepted, so the senders behave as if the delivery succeeded.
+type Recorder struct {
+	// requests is the list of the recorded requests.
+	requests []Request
+
+	// mu is the mutex used to synchronize access to the requests.
+	mu sync.Mutex
+}
+
+// NewRecorder creates a new instance of the Recorder struct.
+//
+//nolint:exhaustruct
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// RoundTrip records the request and returns an empty 202 Accepted response.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+
+	if req.Body != nil {
+		defer req.Body.Close()
+
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	r.mu.Lock()
+	r.requests = append(r.requests, Request{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: req.Header.Clone(),
+		Body:   body,
+	})
+	r.mu.Unlock()
+
+	//nolint:exhaustruct
+	return &http.Response{
+		Status:     http.StatusText(http.StatusAccepted),
+		StatusCode: http.StatusAccepted,
+		Proto:      req.Proto,
+		ProtoMajor: req.ProtoMajor,
+		ProtoMinor: req.ProtoMinor,
+		Header:     make(http.Header),
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+		Request:    req,
+	}, nil
+}
+
+// Requests returns the recorded requests and forgets them.
+func (r *Recorder) Requests() []Request {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	requests := r.requests
+	r.requests = nil
+
+	return requests
+}
@@ -0,0 +1,79 @@
+package notifier_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/notifier"
+)
+
+// RecorderTestSuite represents the test suite for the recording of the requests.
+type RecorderTestSuite struct {
+	suite.Suite
+}
+
+// TestRecorder_RoundTrip verifies that the requests are recorded and accepted without being sent.
+func (suite *RecorderTestSuite) TestRecorder_RoundTrip() {
+	recorder := notifier.NewRecorder()
+	client := http.Client{Transport: recorder}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "http://127.0.0.1:1/hook", strings.NewReader(`{"text":"down"}`))
+	suite.Require().NoError(err)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	suite.Require().NoError(err)
+	suite.Require().NoError(resp.Body.Close())
+	suite.Equal(http.StatusAccepted, resp.StatusCode)
+
+	req, err = http.NewRequestWithContext(context.Background(), http.MethodGet, "http://127.0.0.1:1/push?status=up", nil)
+	suite.Require().NoError(err)
+
+	resp, err = client.Do(req)
+	suite.Require().NoError(err)
+	suite.Require().NoError(resp.Body.Close())
+
+	requests := recorder.Requests()
+	suite.Require().Len(requests, 2)
+
+	suite.Equal(http.MethodPost, requests[0].Method)
+	suite.Equal("http://127.0.0.1:1/hook", requests[0].URL)
+	suite.Equal("application/json", requests[0].Header.Get("Content-Type"))
+	suite.Equal(`{"text":"down"}`, string(requests[0].Body))
+
+	suite.Equal(http.MethodGet, requests[1].Method)
+	suite.Equal("http://127.0.0.1:1/push?status=up", requests[1].URL)
+	suite.Empty(requests[1].Body)
+
+	// The recorded requests are forgotten once returned.
+	suite.Empty(recorder.Requests())
+}
+
+// TestRecorder_Header verifies that the recorded header is not changed with the request.
+func (suite *RecorderTestSuite) TestRecorder_Header() {
+	recorder := notifier.NewRecorder()
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "http://127.0.0.1:1/hook", nil)
+	suite.Require().NoError(err)
+	req.Header.Set("Authorization", "Bearer token")
+
+	resp, err := recorder.RoundTrip(req)
+	suite.Require().NoError(err)
+	suite.Require().NoError(resp.Body.Close())
+
+	req.Header.Set("Authorization", "Bearer other")
+
+	requests := recorder.Requests()
+	suite.Require().Len(requests, 1)
+	suite.Equal("Bearer token", requests[0].Header.Get("Authorization"))
+}
+
+// TestRecorderTestSuite runs the test suite for the recording of the requests.
+func TestRecorderTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(RecorderTestSuite))
+}
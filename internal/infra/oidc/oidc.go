@@ -0,0 +1,559 @@
+package oidc
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/infra/auth"
+)
+
+var (
+	// ErrDiscovery is an error that indicates that the configuration of the provider cannot be discovered.
+	ErrDiscovery = errors.New("oidc: discovery failed")
+
+	// ErrInvalidState is an error that indicates that the callback does not match the login it completes.
+	ErrInvalidState = errors.New("oidc: invalid state")
+
+	// ErrExchange is an error that indicates that the authorization code cannot be exchanged for the tokens.
+	ErrExchange = errors.New("oidc: code exchange failed")
+
+	// ErrNotAllowed is an error that indicates that the user is not allowed to sign in.
+	ErrNotAllowed = errors.New("oidc: the user is not allowed")
+)
+
+const (
+	// SessionCookie is the name of the cookie carrying the session.
+	SessionCookie = "vakeel_way_session"
+
+	// stateCookie is the name of the cookie carrying the state of a login.
+	stateCookie = "vakeel_way_oidc"
+
+	// stateTTL is the time allowed to complete a login.
+	stateTTL = 10 * time.Minute
+
+	// maxBody is the maximum size of the responses of the provider.
+	maxBody = 1 << 20
+)
+
+// Option is a function that configures a Provider.
+type Option func(*Provider)
+
+// WithScopes returns an Option that sets the scopes requested from the provider.
+//
+// The openid scope is always requested.
+func WithScopes(scopes []string) Option {
+	return func(p *Provider) {
+		p.scopes = scopes
+	}
+}
+
+// WithAllowedEmails returns an Option that allows the users with the listed emails to sign in.
+func WithAllowedEmails(emails []string) Option {
+	return func(p *Provider) {
+		p.emails = emails
+	}
+}
+
+// WithAllowedDomains returns an Option that allows the users whose emails belong to the listed domains to sign in.
+func WithAllowedDomains(domains []string) Option {
+	return func(p *Provider) {
+		p.domains = domains
+	}
+}
+
+// WithSessionTTL returns an Option that sets the lifetime of the sessions.
+func WithSessionTTL(ttl time.Duration) Option {
+	return func(p *Provider) {
+		p.sessionTTL = ttl
+	}
+}
+
+// WithHTTPClient returns an Option that sets the HTTP client used to call the provider.
+func WithHTTPClient(client *http.Client) Option {
+	return func(p *Provider) {
+		p.client = client
+	}
+}
+
+// Provider signs the users in with the authorization code flow of an OpenID
+// Connect provider and keeps their sessions in signed cookies.
+//
+// The configuration of the provider is discovered from its issuer on the
+// first login. The login is protected by a state, a nonce and PKCE, and the
+// ID token is verified with the keys published by the provider. A user is
+// allowed to sign in if their verified email or its domain is listed.
+type Provider struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	redirectURL  string
+	scopes       []string
+	emails       []string
+	domains      []string
+	sessionTTL   time.Duration
+	client       *http.Client
+	key          []byte
+
+	mu        sync.Mutex
+	discovery *discovery
+	verifier  *auth.JWTVerifier
+}
+
+// discovery is the part of the configuration of the provider used by the login.
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// NewProvider creates a new Provider.
+//
+// The sessions are signed with a key derived from the client secret, so they
+// survive restarts and are shared by the replicas. A public client, without a
+// secret, signs them with a random key instead.
+//
+// Parameters:
+//   - issuer: The issuer of the provider, e.g. https://accounts.google.com.
+//   - clientID: The client ID registered at the provider.
+//   - clientSecret: The client secret, or empty for a public client.
+//   - redirectURL: The URL of the callback registered at the provider.
+//   - opts: The options of the Provider.
+//
+// Returns:
+//   - A pointer to a Provider.
+//   - An error if the random key cannot be generated.
+//
+//nolint:exhaustruct
+func NewProvider(issuer, clientID, clientSecret, redirectURL string, opts ...Option) (*Provider, error) {
+	p := &Provider{
+		issuer:       strings.TrimSuffix(issuer, "/"),
+		clientID:     clientID,
+		clientSecret: clientSecret,
+		redirectURL:  redirectURL,
+		scopes:       []string{"openid", "email", "profile"},
+		sessionTTL:   12 * time.Hour, //nolint:mnd
+		client:       http.DefaultClient,
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if !slices.Contains(p.scopes, "openid") {
+		p.scopes = append([]string{"openid"}, p.scopes...)
+	}
+
+	if clientSecret != "" {
+		mac := hmac.New(sha256.New, []byte(clientSecret))
+		mac.Write([]byte("vakeel-way session"))
+		p.key = mac.Sum(nil)
+
+		return p, nil
+	}
+
+	p.key = make([]byte, sha256.Size)
+	if _, err := rand.Read(p.key); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// LoginURL returns the path starting a login that returns to the page.
+//
+// Parameters:
+//   - next: The path of the page to return to after the login.
+//
+// Returns:
+//   - The path of the login.
+func (p *Provider) LoginURL(next string) string {
+	return "/auth/login?" + url.Values{"next": {next}}.Encode()
+}
+
+// CallbackPath returns the path of the callback, taken from the redirect URL.
+func (p *Provider) CallbackPath() string {
+	if u, err := url.Parse(p.redirectURL); err == nil && u.Path != "" {
+		return u.Path
+	}
+
+	return "/auth/callback"
+}
+
+// Principal returns the user signed in by the session cookie of the request.
+//
+// The user is granted every scope of the admin REST API.
+//
+// Parameters:
+//   - r: The HTTP request.
+//
+// Returns:
+//   - The principal named oidc:<email>.
+//   - Whether the request carries a valid session.
+func (p *Provider) Principal(r *http.Request) (auth.Principal, bool) {
+	cookie, err := r.Cookie(SessionCookie)
+	if err != nil {
+		return auth.Principal{}, false
+	}
+
+	var s session
+	if !p.open(cookie.Value, &s) || time.Now().Unix() >= s.Expiry {
+		return auth.Principal{}, false
+	}
+
+	return auth.Principal{
+		Name:      "oidc:" + s.Email,
+		Namespace: "",
+		Quota:     auth.Quota{MaxIDs: 0, HeartbeatsPerMinute: 0},
+		ACL:       auth.ACL{IDs: nil, Namespaces: nil},
+		Scopes:    nil,
+	}, true
+}
+
+// session is the content of the session cookie.
+type session struct {
+	Email   string `json:"email"`
+	Subject string `json:"sub"`
+	Expiry  int64  `json:"exp"`
+}
+
+// login is the content of the state cookie of a login in progress.
+type login struct {
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	Next     string `json:"next"`
+	Expiry   int64  `json:"exp"`
+}
+
+// Login handles GET /auth/login by redirecting the user to the provider.
+//
+// The next query parameter is the local path the user returns to after the
+// login, /api/v1/services by default.
+func (p *Provider) Login(w http.ResponseWriter, r *http.Request) {
+	d, err := p.discover(r.Context())
+	if err != nil {
+		zerolog.Ctx(r.Context()).Error().Err(err).Str("issuer", p.issuer).Msg("Failed to discover the OIDC provider")
+		http.Error(w, "the identity provider is unavailable", http.StatusBadGateway)
+
+		return
+	}
+
+	next := r.URL.Query().Get("next")
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		next = "/api/v1/services"
+	}
+
+	state := login{
+		State:    random(),
+		Nonce:    random(),
+		Verifier: random(),
+		Next:     next,
+		Expiry:   time.Now().Add(stateTTL).Unix(),
+	}
+
+	p.setCookie(w, stateCookie, p.seal(state), stateTTL)
+
+	challenge := sha256.Sum256([]byte(state.Verifier))
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {p.clientID},
+		"redirect_uri":          {p.redirectURL},
+		"scope":                 {strings.Join(p.scopes, " ")},
+		"state":                 {state.State},
+		"nonce":                 {state.Nonce},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(d.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+
+	http.Redirect(w, r, d.AuthorizationEndpoint+separator+query.Encode(), http.StatusFound)
+}
+
+// Callback handles the redirect of the provider by exchanging the
+// authorization code for the ID token and starting the session of the user.
+func (p *Provider) Callback(w http.ResponseWriter, r *http.Request) {
+	logger := zerolog.Ctx(r.Context())
+
+	s, next, err := p.complete(r)
+	if err != nil {
+		logger.Warn().Err(err).Str("email", s.Email).Msg("OIDC login rejected")
+
+		code := http.StatusUnauthorized
+		if errors.Is(err, ErrNotAllowed) {
+			code = http.StatusForbidden
+		}
+
+		http.Error(w, err.Error(), code)
+
+		return
+	}
+
+	logger.Info().Str("email", s.Email).Msg("OIDC login")
+
+	p.setCookie(w, stateCookie, "", -1)
+	p.setCookie(w, SessionCookie, p.seal(s), p.sessionTTL)
+	http.Redirect(w, r, next, http.StatusFound)
+}
+
+// Logout handles POST /auth/logout by ending the session of the user.
+func (p *Provider) Logout(w http.ResponseWriter, _ *http.Request) {
+	p.setCookie(w, SessionCookie, "", -1)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// complete verifies the callback and returns the session of the user.
+//
+// Returns:
+//   - The session of the user, with the email set if it is known.
+//   - The path to return to.
+//   - An error if the login is rejected.
+func (p *Provider) complete(r *http.Request) (session, string, error) {
+	var s session
+
+	cookie, err := r.Cookie(stateCookie)
+	if err != nil {
+		return s, "", fmt.Errorf("%w: no login in progress", ErrInvalidState)
+	}
+
+	var state login
+	if !p.open(cookie.Value, &state) || time.Now().Unix() >= state.Expiry {
+		return s, "", fmt.Errorf("%w: the login has expired", ErrInvalidState)
+	}
+
+	query := r.URL.Query()
+	if query.Get("state") != state.State {
+		return s, "", fmt.Errorf("%w: the state does not match", ErrInvalidState)
+	}
+
+	if reason := query.Get("error"); reason != "" {
+		return s, "", fmt.Errorf("%w: %s", ErrExchange, reason)
+	}
+
+	claims, err := p.exchange(r.Context(), query.Get("code"), state.Verifier)
+	if err != nil {
+		return s, "", err
+	}
+
+	if claims.String("nonce") != state.Nonce {
+		return s, "", fmt.Errorf("%w: the nonce does not match", ErrInvalidState)
+	}
+
+	s = session{
+		Email:   strings.ToLower(claims.String("email")),
+		Subject: claims.String("sub"),
+		Expiry:  time.Now().Add(p.sessionTTL).Unix(),
+	}
+
+	var verified bool
+	if raw, ok := claims["email_verified"]; ok && json.Unmarshal(raw, &verified) == nil && !verified {
+		return s, "", fmt.Errorf("%w: the email is not verified", ErrNotAllowed)
+	}
+
+	if !p.allowed(s.Email) {
+		return s, "", ErrNotAllowed
+	}
+
+	return s, state.Next, nil
+}
+
+// allowed reports whether the user with the email may sign in.
+func (p *Provider) allowed(email string) bool {
+	if email == "" {
+		return false
+	}
+
+	if slices.ContainsFunc(p.emails, func(allowed string) bool { return strings.EqualFold(allowed, email) }) {
+		return true
+	}
+
+	_, domain, _ := strings.Cut(email, "@")
+
+	return slices.ContainsFunc(p.domains, func(allowed string) bool { return strings.EqualFold(allowed, domain) })
+}
+
+// exchange exchanges the authorization code for the tokens and verifies the ID token.
+func (p *Provider) exchange(ctx context.Context, code, verifier string) (auth.Claims, error) {
+	d, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.redirectURL},
+		"code_verifier": {verifier},
+		"client_id":     {p.clientID},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExchange, err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	if p.clientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.clientID), url.QueryEscape(p.clientSecret))
+	}
+
+	var tokens struct {
+		IDToken string `json:"id_token"`
+	}
+
+	if err := p.do(req, &tokens); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrExchange, err)
+	}
+
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("%w: no id_token in the response", ErrExchange)
+	}
+
+	return p.verifier.Claims(ctx, tokens.IDToken)
+}
+
+// discover returns the configuration of the provider, fetching it on the first call.
+func (p *Provider) discover(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDiscovery, err)
+	}
+
+	var d discovery
+	if err := p.do(req, &d); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrDiscovery, err)
+	}
+
+	if strings.TrimSuffix(d.Issuer, "/") != p.issuer {
+		return nil, fmt.Errorf("%w: the issuer %q does not match", ErrDiscovery, d.Issuer)
+	}
+
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: the configuration is incomplete", ErrDiscovery)
+	}
+
+	opts := []auth.JWTOption{
+		auth.WithJWKS(d.JWKSURI, p.client),
+		auth.WithIssuer(d.Issuer),
+		auth.WithAudience(p.clientID),
+	}
+
+	// The ID tokens of a confidential client may be signed with its secret.
+	if p.clientSecret != "" {
+		opts = append(opts, auth.WithHMAC(p.clientSecret))
+	}
+
+	p.discovery, p.verifier = &d, auth.NewJWTVerifier(opts...)
+
+	return p.discovery, nil
+}
+
+// do sends the request and decodes the JSON response.
+func (p *Provider) do(req *http.Request, out any) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status) //nolint:err113
+	}
+
+	return json.Unmarshal(body, out)
+}
+
+// setCookie sets or, with a negative TTL, clears the cookie.
+func (p *Provider) setCookie(w http.ResponseWriter, name, value string, ttl time.Duration) {
+	//nolint:exhaustruct
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   strings.HasPrefix(p.redirectURL, "https://"),
+		SameSite: http.SameSiteLaxMode,
+		MaxAge:   int(ttl.Seconds()),
+	}
+
+	if ttl < 0 {
+		cookie.MaxAge = -1
+	}
+
+	http.SetCookie(w, cookie)
+}
+
+// seal encodes the value and signs it with the key of the sessions.
+func (p *Provider) seal(value any) string {
+	payload, _ := json.Marshal(value) //nolint:errchkjson
+
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write(payload)
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// open verifies the signature of the sealed value and decodes it.
+func (p *Provider) open(sealed string, out any) bool {
+	encoded, signature, ok := strings.Cut(sealed, ".")
+	if !ok {
+		return false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return false
+	}
+
+	sum, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, p.key)
+	mac.Write(payload)
+
+	return hmac.Equal(sum, mac.Sum(nil)) && json.Unmarshal(payload, out) == nil
+}
+
+// random returns a random URL-safe string of 256 bits.
+func random() string {
+	b := make([]byte, 32) //nolint:mnd
+	_, _ = rand.Read(b)
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,210 @@
+package oidc_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/oidc"
+)
+
+// OIDCTestSuite represents the test suite for the OIDC login.
+type OIDCTestSuite struct {
+	suite.Suite
+
+	key    *rsa.PrivateKey
+	idp    *httptest.Server
+	email  string
+	nonce  string
+	verify string
+}
+
+// SetupTest starts a fake identity provider signing the ID tokens with an RSA key.
+func (suite *OIDCTestSuite) SetupTest() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	suite.Require().NoError(err)
+
+	suite.key, suite.email = key, "alice@example.com"
+
+	mux := http.NewServeMux()
+	suite.idp = httptest.NewServer(mux)
+
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 suite.idp.URL,
+			"authorization_endpoint": suite.idp.URL + "/authorize",
+			"token_endpoint":         suite.idp.URL + "/token",
+			"jwks_uri":               suite.idp.URL + "/jwks",
+		})
+	})
+
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "idp",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		challenge := sha256.Sum256([]byte(r.PostFormValue("code_verifier")))
+
+		if id != "way" || secret != "s3cr3t" || r.PostFormValue("code") != "code" ||
+			base64.RawURLEncoding.EncodeToString(challenge[:]) != suite.verify {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": suite.idToken()})
+	})
+}
+
+// TearDownTest stops the fake identity provider.
+func (suite *OIDCTestSuite) TearDownTest() {
+	suite.idp.Close()
+}
+
+// idToken returns an ID token of the user signed by the fake identity provider.
+func (suite *OIDCTestSuite) idToken() string {
+	encode := func(value any) string {
+		data, _ := json.Marshal(value)
+
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	signed := encode(map[string]string{"alg": "RS256", "kid": "idp"}) + "." + encode(map[string]any{
+		"iss":            suite.idp.URL,
+		"aud":            "way",
+		"sub":            "42",
+		"email":          suite.email,
+		"email_verified": true,
+		"nonce":          suite.nonce,
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	})
+
+	digest := sha256.Sum256([]byte(signed))
+	signature, _ := rsa.SignPKCS1v15(rand.Reader, suite.key, crypto.SHA256, digest[:])
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// provider returns the Provider of the fake identity provider allowing the example.com domain.
+func (suite *OIDCTestSuite) provider() *oidc.Provider {
+	provider, err := oidc.NewProvider(suite.idp.URL, "way", "s3cr3t", "https://way.example.com/auth/callback",
+		oidc.WithAllowedDomains([]string{"example.com"}),
+		oidc.WithHTTPClient(suite.idp.Client()),
+	)
+	suite.Require().NoError(err)
+
+	return provider
+}
+
+// login starts a login and returns the callback request completing it.
+func (suite *OIDCTestSuite) login(provider *oidc.Provider) *http.Request {
+	recorder := httptest.NewRecorder()
+	provider.Login(recorder, httptest.NewRequest(http.MethodGet, "/auth/login?next=/api/v1/incidents", nil))
+	suite.Require().Equal(http.StatusFound, recorder.Code)
+
+	location, err := url.Parse(recorder.Header().Get("Location"))
+	suite.Require().NoError(err)
+	suite.Equal(suite.idp.URL+"/authorize", location.Scheme+"://"+location.Host+location.Path)
+	suite.Equal("S256", location.Query().Get("code_challenge_method"))
+
+	suite.nonce, suite.verify = location.Query().Get("nonce"), location.Query().Get("code_challenge")
+
+	callback := httptest.NewRequest(http.MethodGet,
+		"/auth/callback?"+url.Values{"code": {"code"}, "state": {location.Query().Get("state")}}.Encode(), nil)
+
+	for _, cookie := range recorder.Result().Cookies() {
+		callback.AddCookie(cookie)
+	}
+
+	return callback
+}
+
+// TestProvider_Login verifies that a login signs the user in and returns to the page.
+func (suite *OIDCTestSuite) TestProvider_Login() {
+	provider := suite.provider()
+
+	recorder := httptest.NewRecorder()
+	provider.Callback(recorder, suite.login(provider))
+	suite.Require().Equal(http.StatusFound, recorder.Code)
+	suite.Equal("/api/v1/incidents", recorder.Header().Get("Location"))
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/services", nil)
+
+	for _, cookie := range recorder.Result().Cookies() {
+		if cookie.Name == oidc.SessionCookie {
+			suite.True(cookie.HttpOnly)
+			suite.True(cookie.Secure)
+			request.AddCookie(cookie)
+		}
+	}
+
+	principal, ok := provider.Principal(request)
+	suite.Require().True(ok)
+	suite.Equal("oidc:alice@example.com", principal.Name)
+	suite.True(principal.HasScope("admin"))
+}
+
+// TestProvider_NotAllowed verifies that a user outside the allowed domains cannot sign in.
+func (suite *OIDCTestSuite) TestProvider_NotAllowed() {
+	suite.email = "mallory@example.org"
+	provider := suite.provider()
+
+	recorder := httptest.NewRecorder()
+	provider.Callback(recorder, suite.login(provider))
+	suite.Equal(http.StatusForbidden, recorder.Code)
+	suite.Empty(recorder.Result().Cookies())
+}
+
+// TestProvider_State verifies that a callback not matching the login is rejected.
+func (suite *OIDCTestSuite) TestProvider_State() {
+	provider := suite.provider()
+
+	callback := suite.login(provider)
+	query := callback.URL.Query()
+	query.Set("state", "forged")
+	callback.URL.RawQuery = query.Encode()
+
+	recorder := httptest.NewRecorder()
+	provider.Callback(recorder, callback)
+	suite.Equal(http.StatusUnauthorized, recorder.Code)
+}
+
+// TestProvider_Session verifies that a forged session cookie is rejected.
+func (suite *OIDCTestSuite) TestProvider_Session() {
+	provider := suite.provider()
+
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/services", nil)
+	request.AddCookie(&http.Cookie{ //nolint:exhaustruct
+		Name:  oidc.SessionCookie,
+		Value: base64.RawURLEncoding.EncodeToString([]byte(`{"email":"mallory@example.org","exp":9999999999}`)) + ".AAAA",
+	})
+
+	_, ok := provider.Principal(request)
+	suite.False(ok)
+
+	_, ok = provider.Principal(httptest.NewRequest(http.MethodGet, "/api/v1/services", nil))
+	suite.False(ok)
+}
+
+// TestOIDCTestSuite runs the test suite for the OIDC login.
+func TestOIDCTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(OIDCTestSuite))
+}
@@ -0,0 +1,142 @@
+package oncall
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/httpx"
+)
+
+// ErrUnexpectedStatus is an error that indicates that Grafana OnCall responded with a non-2xx status code.
+var ErrUnexpectedStatus = errors.New("oncall: unexpected response status")
+
+// States of the alerts of the formatted webhook integration.
+const (
+	StateAlerting = "alerting"
+	StateOK       = "ok"
+)
+
+// ResponseError is an error returned when Grafana OnCall responds with a
+// non-2xx status code. It wraps ErrUnexpectedStatus and carries the response body.
+type ResponseError = httpx.ResponseError
+
+// API is a client for the formatted webhook integration of Grafana OnCall.
+//
+// The integration accepts a POST request with an alert. The alerts with the
+// same alert_uid are grouped into an alert group, which is resolved by an
+// alert in the "ok" state.
+type API struct {
+	// client: The HTTP client used to make requests to Grafana OnCall.
+	client *http.Client
+}
+
+// Option is a function that can be used to configure an API instance.
+type Option func(*API)
+
+// WithClient returns an Option function that sets the http.Client used to send HTTP requests
+// to Grafana OnCall.
+func WithClient(c http.Client) Option {
+	return func(api *API) {
+		api.client = &c
+	}
+}
+
+// NewAPI creates a new Grafana OnCall API client.
+//
+// Parameters:
+//
+//	ops: A variadic number of Option functions.
+//
+// Returns:
+//
+//	A pointer to an API struct.
+//
+//nolint:exhaustruct
+func NewAPI(ops ...Option) *API {
+	// Create a new API struct with default settings for the http.Client.
+	api := &API{
+		client: &http.Client{},
+	}
+
+	// Apply all provided options to the API struct.
+	for _, op := range ops {
+		op(api)
+	}
+
+	return api
+}
+
+// alert is the payload of the formatted webhook integration.
+type alert struct {
+	AlertUID string `json:"alert_uid"`
+	Title    string `json:"title"`
+	State    string `json:"state"`
+	Message  string `json:"message"`
+}
+
+// Send posts the alert of the service of the event to Grafana OnCall.
+//
+// The alert_uid is the UUID of the service, so the alerts of a downtime are
+// grouped together, and a degraded service that goes down escalates the same
+// alert group. The alert is in the "alerting" state while the service is down
+// or degraded, and in the "ok" state, which resolves the alert group, when it
+// is up. The rendered message is the message of the alert.
+//
+// Parameters:
+// - ctx: The context.Context to use for the request.
+// - target: The entities.Target with the URL of the integration.
+// - event: The entities.Event to post the alert of.
+//
+// Returns an error if the request cannot be sent, or a *ResponseError if
+// Grafana OnCall rejects the alert.
+func (s *API) Send(ctx context.Context, target entities.Target, event entities.Event) error {
+	data, err := json.Marshal(newAlert(event))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return httpx.NewResponseError(ErrUnexpectedStatus, resp)
+	}
+
+	return nil
+}
+
+// newAlert returns the alert of the service of the event.
+func newAlert(event entities.Event) alert {
+	state := StateAlerting
+	if event.Status == entities.Up {
+		state = StateOK
+	}
+
+	// Use the rendered message, if any.
+	message := event.Message
+	if message == "" {
+		message = event.Reason
+	}
+
+	return alert{
+		AlertUID: event.ID.String(),
+		Title:    fmt.Sprintf("Service %s is %s", event.ID, event.Status),
+		State:    state,
+		Message:  message,
+	}
+}
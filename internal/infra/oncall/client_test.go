@@ -0,0 +1,83 @@
+package oncall_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/oncall"
+)
+
+// ClientTestSuite represents the test suite for the Grafana OnCall client.
+type ClientTestSuite struct {
+	suite.Suite
+}
+
+// TestClient_Alert verifies that the alerts of a downtime share the alert_uid and that it is resolved.
+func (suite *ClientTestSuite) TestClient_Alert() {
+	var alerts []map[string]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		suite.Equal(http.MethodPost, r.Method)
+		suite.Equal("application/json", r.Header.Get("Content-Type"))
+
+		var alert map[string]string
+		suite.NoError(json.NewDecoder(r.Body).Decode(&alert))
+
+		alerts = append(alerts, alert)
+	}))
+	defer server.Close()
+
+	id := uuid.New()
+	target := entities.Target{Name: "oncall", Type: "oncall", URL: server.URL + "/integrations/v1/formatted_webhook/abc/"}
+
+	for _, event := range []entities.Event{
+		{ID: id, Status: entities.Degraded, Message: "slow"},
+		{ID: id, Status: entities.Down, Message: "database is down"},
+		{ID: id, Status: entities.Up},
+	} {
+		suite.Require().NoError(oncall.NewAPI().Send(context.Background(), target, event))
+	}
+
+	suite.Require().Len(alerts, 3)
+
+	for _, alert := range alerts {
+		suite.Equal(id.String(), alert["alert_uid"])
+	}
+
+	suite.Equal(oncall.StateAlerting, alerts[0]["state"])
+	suite.Equal("slow", alerts[0]["message"])
+	suite.Equal(oncall.StateAlerting, alerts[1]["state"])
+	suite.Equal("Service "+id.String()+" is down", alerts[1]["title"])
+	suite.Equal(oncall.StateOK, alerts[2]["state"])
+}
+
+// TestClient_Rejected verifies that the alerts rejected by Grafana OnCall are reported.
+func (suite *ClientTestSuite) TestClient_Rejected() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "integration not found", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	err := oncall.NewAPI().Send(context.Background(),
+		entities.Target{Name: "oncall", Type: "oncall", URL: server.URL},
+		entities.Event{ID: uuid.New(), Status: entities.Down})
+	suite.Require().ErrorIs(err, oncall.ErrUnexpectedStatus)
+
+	var rerr *oncall.ResponseError
+	suite.Require().ErrorAs(err, &rerr)
+	suite.Equal("integration not found", rerr.Message)
+	suite.False(rerr.Temporary())
+}
+
+// TestClientTestSuite runs the test suite for the Grafana OnCall client.
+func TestClientTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ClientTestSuite))
+}
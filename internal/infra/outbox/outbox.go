@@ -0,0 +1,576 @@
+package outbox
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"go.etcd.io/bbolt"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// bucket is the name of the BoltDB bucket holding the deliveries.
+//
+//nolint:gochecknoglobals
+var bucket = []byte("deliveries")
+
+// Sender represents an interface for delivering events to the targets.
+type Sender interface {
+	// Send delivers the event to the target.
+	Send(ctx context.Context, target entities.Target, event entities.Event) error
+}
+
+// Resolver represents an interface for resolving the targets of a webhook.
+//
+// Only the names of the targets are persisted, so their URLs and keys are not
+// written to the disk, and the retries follow the changes of the configuration.
+type Resolver interface {
+	// Get retrieves the targets of a webhook by its ID.
+	Get(ctx context.Context, id uuid.UUID) ([]entities.Target, error)
+
+	// All returns all webhook IDs.
+	All() []uuid.UUID
+}
+
+// DeadLetter represents an interface for keeping the events the outbox gave up on.
+type DeadLetter interface {
+	// Add keeps the dead letter.
+	Add(dead entities.DeadLetter) error
+}
+
+// temporary is implemented by the errors that tell whether a retry may succeed,
+// e.g. the errors of the HTTP responses of the targets.
+type temporary interface {
+	Temporary() bool
+}
+
+// Outbox wraps a Sender with a durable queue of the deliveries.
+//
+// Every event is written to a BoltDB file before it is delivered, and removed
+// once the target accepts it. The deliveries failing with a temporary error
+// are retried with an exponential backoff by Run, so they survive a restart of
+// the process. The events of a target are delivered in order: a new event waits
+// behind the queued ones. The deliveries exhausting their attempts, or rejected
+// permanently by the target, are handed over to the dead letter store.
+type Outbox struct {
+	// db is the BoltDB database of the queue.
+	db *bbolt.DB
+
+	// next is the Sender the events are delivered with.
+	next Sender
+
+	// resolver resolves the targets of the queued deliveries.
+	resolver Resolver
+
+	// deadLetter keeps the deliveries the outbox gave up on, if any.
+	deadLetter DeadLetter
+
+	// backoff is the delay before the first retry, doubled on every attempt.
+	backoff time.Duration
+
+	// maxBackoff is the maximum delay between two attempts.
+	maxBackoff time.Duration
+
+	// attempts is the maximum number of attempts of a delivery.
+	attempts int
+
+	// interval is the time between two scans of the queue.
+	interval time.Duration
+
+	// queued maps the targets to the number of their queued deliveries.
+	queued map[key]int
+
+	// mu is the mutex used to synchronize access to the queued deliveries.
+	mu sync.Mutex
+
+	// now returns the current time.
+	now func() time.Time
+}
+
+// key identifies the target of a webhook.
+type key struct {
+	id     uuid.UUID
+	target string
+}
+
+// Option is a function that can be used to configure an Outbox instance.
+type Option func(*Outbox)
+
+// WithDeadLetter returns an Option that sets the store of the deliveries the outbox gave up on.
+//
+// Parameters:
+//   - deadLetter: The DeadLetter store.
+//
+// Returns:
+//   - An Option that sets the dead letter store of the Outbox.
+func WithDeadLetter(deadLetter DeadLetter) Option {
+	return func(o *Outbox) {
+		o.deadLetter = deadLetter
+	}
+}
+
+// WithBackoff returns an Option that sets the delays between the attempts.
+//
+// Parameters:
+//   - backoff: The delay before the first retry. The values below 1 are ignored.
+//   - maxBackoff: The maximum delay. The values below 1 are ignored.
+//
+// Returns:
+//   - An Option that sets the backoff of the Outbox.
+func WithBackoff(backoff, maxBackoff time.Duration) Option {
+	return func(o *Outbox) {
+		if backoff > 0 {
+			o.backoff = backoff
+		}
+
+		if maxBackoff > 0 {
+			o.maxBackoff = maxBackoff
+		}
+	}
+}
+
+// WithAttempts returns an Option that sets the maximum number of attempts of a delivery.
+//
+// Parameters:
+//   - attempts: The number of attempts. The values below 1 are ignored.
+//
+// Returns:
+//   - An Option that sets the attempts of the Outbox.
+func WithAttempts(attempts int) Option {
+	return func(o *Outbox) {
+		if attempts > 0 {
+			o.attempts = attempts
+		}
+	}
+}
+
+// Open creates a new instance of the Outbox struct and loads the queued deliveries.
+//
+// Parameters:
+//   - path: The path to the BoltDB file of the queue.
+//   - next: The Sender the events are delivered with.
+//   - resolver: The Resolver of the targets of the queued deliveries.
+//   - options: Optional configurations for the Outbox.
+//
+// Returns:
+//   - A pointer to the initialized Outbox.
+//   - An error if the file cannot be opened, e.g. it is locked by another process.
+//
+//nolint:exhaustruct
+func Open(path string, next Sender, resolver Resolver, options ...Option) (*Outbox, error) {
+	const (
+		backoff    = 5 * time.Second
+		maxBackoff = 10 * time.Minute
+		attempts   = 20
+		interval   = time.Second
+		timeout    = time.Second
+	)
+
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: timeout}) //nolint:mnd
+	if err != nil {
+		return nil, fmt.Errorf("outbox: open %s: %w", path, err)
+	}
+
+	outbox := &Outbox{
+		db:         db,
+		next:       next,
+		resolver:   resolver,
+		backoff:    backoff,
+		maxBackoff: maxBackoff,
+		attempts:   attempts,
+		interval:   interval,
+		queued:     make(map[key]int),
+		now:        time.Now,
+	}
+
+	// Apply any optional configurations provided through the options parameter.
+	for _, option := range options {
+		option(outbox)
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(bucket)
+		if err != nil {
+			return err
+		}
+
+		return b.ForEach(func(_, value []byte) error {
+			var entry delivery
+			if err := json.Unmarshal(value, &entry); err != nil {
+				return err
+			}
+
+			outbox.queued[entry.key()]++
+
+			return nil
+		})
+	})
+	if err != nil {
+		_ = db.Close()
+
+		return nil, err
+	}
+
+	return outbox, nil
+}
+
+// Close closes the BoltDB file of the queue.
+//
+// Returns:
+//   - An error if the file cannot be closed.
+func (o *Outbox) Close() error {
+	return o.db.Close()
+}
+
+// Len returns the number of the queued deliveries.
+func (o *Outbox) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	total := 0
+	for _, n := range o.queued {
+		total += n
+	}
+
+	return total
+}
+
+// Send queues the event and delivers it, unless older events of the target are queued.
+//
+// The delivery is persisted before the first attempt and scheduled for a retry,
+// so it is retried even if the process stops during the attempt.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - target: The entities.Target to deliver the event to.
+//   - event: The entities.Event to deliver.
+//
+// Returns:
+//   - An error if the event cannot be queued, or if the target rejected it
+//     permanently. The event is not queued in that case.
+//   - nil if the event is delivered or queued for a retry.
+func (o *Outbox) Send(ctx context.Context, target entities.Target, event entities.Event) error {
+	entry := delivery{
+		Webhook: event.ID,
+		Target:  target.Name,
+		Event:   encodeEvent(event),
+		Attempt: 1,
+		Due:     o.now().Add(o.delay(1)),
+		Error:   "",
+	}
+
+	o.mu.Lock()
+	// The events of the target are delivered in order.
+	waiting := o.queued[entry.key()] > 0
+
+	seq, err := o.put(0, entry)
+	if err == nil {
+		o.queued[entry.key()]++
+	}
+	o.mu.Unlock()
+
+	if err != nil || waiting {
+		return err
+	}
+
+	err = o.next.Send(ctx, target, event)
+
+	var tmp temporary
+	if err != nil && (!errors.As(err, &tmp) || tmp.Temporary()) {
+		// Keep the delivery scheduled for a retry.
+		entry.Error = err.Error()
+
+		if err := o.update(seq, entry); err != nil {
+			return err
+		}
+
+		zerolog.Ctx(ctx).Debug().Err(err).
+			Str("id", event.ID.String()).
+			Str("target", target.Name).
+			Time("retry", entry.Due).
+			Msg("outbox: delivery failed, retrying later")
+
+		return nil
+	}
+
+	// The event is delivered, or rejected permanently and handled by the caller.
+	return errors.Join(err, o.remove(seq, entry.key()))
+}
+
+// Run retries the due deliveries until the context is canceled.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the outbox.
+func (o *Outbox) Run(ctx context.Context) {
+	ticker := time.NewTicker(o.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			o.Retry(ctx)
+		}
+	}
+}
+
+// Retry delivers the due deliveries once, oldest first.
+//
+// A target whose delivery fails is not tried again until its next attempt,
+// so its events keep their order.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the deliveries.
+func (o *Outbox) Retry(ctx context.Context) {
+	logger := zerolog.Ctx(ctx)
+
+	entries, err := o.list()
+	if err != nil {
+		logger.Err(err).Msg("outbox: failed to read the queue")
+
+		return
+	}
+
+	blocked := make(map[key]bool)
+	targets := make(map[uuid.UUID][]entities.Target)
+	webhooks := o.resolver.All()
+	now := o.now()
+
+	for _, item := range entries {
+		if ctx.Err() != nil {
+			return
+		}
+
+		entry, k := item.entry, item.entry.key()
+		if blocked[k] {
+			continue
+		}
+
+		// The later events of the target wait for the due one.
+		if entry.Due.After(now) {
+			blocked[k] = true
+
+			continue
+		}
+
+		if _, ok := targets[entry.Webhook]; !ok && slices.Contains(webhooks, entry.Webhook) {
+			resolved, err := o.resolver.Get(ctx, entry.Webhook)
+			if err != nil {
+				logger.Err(err).Str("id", entry.Webhook.String()).Msg("outbox: failed to resolve the targets")
+
+				blocked[k] = true
+
+				continue
+			}
+
+			targets[entry.Webhook] = resolved
+		}
+
+		idx := slices.IndexFunc(targets[entry.Webhook], func(target entities.Target) bool {
+			return target.Name == entry.Target
+		})
+
+		// The webhook or the target was removed.
+		if idx < 0 {
+			o.discard(ctx, item.seq, entry)
+
+			continue
+		}
+
+		if !o.deliver(ctx, item.seq, targets[entry.Webhook][idx], entry) {
+			blocked[k] = true
+		}
+	}
+}
+
+// deliver retries the queued delivery.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the delivery.
+//   - seq: The sequence number of the delivery.
+//   - target: The resolved entities.Target.
+//   - entry: The queued delivery.
+//
+// Returns:
+//   - Whether the delivery left the queue.
+func (o *Outbox) deliver(ctx context.Context, seq uint64, target entities.Target, entry delivery) bool {
+	logger := zerolog.Ctx(ctx)
+	event := entry.Event.decode()
+
+	err := o.next.Send(ctx, target, event)
+	if err == nil {
+		if err := o.remove(seq, entry.key()); err != nil {
+			logger.Err(err).Msg("outbox: failed to update the queue")
+		}
+
+		logger.Info().
+			Str("id", entry.Webhook.String()).
+			Str("target", entry.Target).
+			Int("attempt", entry.Attempt+1).
+			Msg("outbox: queued status update delivered")
+
+		return true
+	}
+
+	var tmp temporary
+	if (errors.As(err, &tmp) && !tmp.Temporary()) || entry.Attempt+1 >= o.attempts {
+		logger.Warn().Err(err).
+			Str("id", entry.Webhook.String()).
+			Str("target", entry.Target).
+			Int("attempt", entry.Attempt+1).
+			Msg("outbox: giving up on status update")
+
+		o.bury(ctx, seq, entry, event, err)
+
+		return true
+	}
+
+	entry.Attempt++
+	entry.Due = o.now().Add(o.delay(entry.Attempt))
+	entry.Error = err.Error()
+
+	if err := o.update(seq, entry); err != nil {
+		logger.Err(err).Msg("outbox: failed to update the queue")
+	}
+
+	return false
+}
+
+// bury hands the delivery over to the dead letter store and removes it.
+func (o *Outbox) bury(ctx context.Context, seq uint64, entry delivery, event entities.Event, cause error) {
+	if o.deadLetter != nil {
+		err := o.deadLetter.Add(entities.DeadLetter{
+			ID:       uuid.Nil,
+			Target:   entry.Target,
+			Event:    event,
+			Error:    cause.Error(),
+			Attempts: entry.Attempt + 1,
+			At:       o.now(),
+		})
+		if err != nil {
+			zerolog.Ctx(ctx).Err(err).Str("id", entry.Webhook.String()).Str("target", entry.Target).
+				Msg("outbox: failed to keep the dead letter")
+		}
+	}
+
+	o.discard(ctx, seq, entry)
+}
+
+// discard removes the delivery from the queue.
+func (o *Outbox) discard(ctx context.Context, seq uint64, entry delivery) {
+	if err := o.remove(seq, entry.key()); err != nil {
+		zerolog.Ctx(ctx).Err(err).Msg("outbox: failed to update the queue")
+	}
+}
+
+// delay returns the delay before the attempt following the given one.
+//
+// Parameters:
+//   - attempt: The number of the attempts made, starting at 1.
+//
+// Returns:
+//   - The backoff doubled on every attempt, capped at the maximum backoff.
+func (o *Outbox) delay(attempt int) time.Duration {
+	delay := o.backoff
+
+	for range attempt - 1 {
+		if delay >= o.maxBackoff/2 { //nolint:mnd
+			return o.maxBackoff
+		}
+
+		delay *= 2
+	}
+
+	return min(delay, o.maxBackoff)
+}
+
+// item is a delivery read from the queue with its sequence number.
+type item struct {
+	seq   uint64
+	entry delivery
+}
+
+// list returns the queued deliveries, oldest first.
+func (o *Outbox) list() ([]item, error) {
+	var items []item
+
+	err := o.db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucket).ForEach(func(k, value []byte) error {
+			var entry delivery
+			if err := json.Unmarshal(value, &entry); err != nil {
+				return err
+			}
+
+			items = append(items, item{seq: binary.BigEndian.Uint64(k), entry: entry})
+
+			return nil
+		})
+	})
+
+	return items, err
+}
+
+// put writes the delivery to the queue.
+//
+// Parameters:
+//   - seq: The sequence number of the delivery, or 0 to append it.
+//   - entry: The delivery.
+//
+// Returns:
+//   - The sequence number of the delivery.
+//   - An error if the delivery cannot be written.
+func (o *Outbox) put(seq uint64, entry delivery) (uint64, error) {
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return 0, err
+	}
+
+	err = o.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(bucket)
+
+		if seq == 0 {
+			if seq, err = b.NextSequence(); err != nil {
+				return err
+			}
+		}
+
+		return b.Put(binary.BigEndian.AppendUint64(nil, seq), value)
+	})
+
+	return seq, err
+}
+
+// update rewrites the queued delivery.
+func (o *Outbox) update(seq uint64, entry delivery) error {
+	_, err := o.put(seq, entry)
+
+	return err
+}
+
+// remove deletes the delivery from the queue.
+func (o *Outbox) remove(seq uint64, k key) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	err := o.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucket).Delete(binary.BigEndian.AppendUint64(nil, seq))
+	})
+	if err != nil {
+		return err
+	}
+
+	if o.queued[k]--; o.queued[k] <= 0 {
+		delete(o.queued, k)
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+package outbox_test
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/outbox"
+)
+
+// deliveryError is the error of a failed delivery.
+type deliveryError struct {
+	temporary bool
+}
+
+func (e deliveryError) Error() string {
+	return "delivery failed"
+}
+
+func (e deliveryError) Temporary() bool {
+	return e.temporary
+}
+
+// recordingSender records the delivered events and fails while err is set.
+type recordingSender struct {
+	mu     sync.Mutex
+	err    error
+	calls  int
+	events []entities.Event
+}
+
+func (s *recordingSender) Send(_ context.Context, _ entities.Target, event entities.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.calls++
+
+	if s.err != nil {
+		return s.err
+	}
+
+	s.events = append(s.events, event)
+
+	return nil
+}
+
+func (s *recordingSender) fail(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.err = err
+}
+
+// registry is a Resolver with a single webhook.
+type registry struct {
+	id      uuid.UUID
+	targets []entities.Target
+}
+
+func (r registry) Get(context.Context, uuid.UUID) ([]entities.Target, error) {
+	return r.targets, nil
+}
+
+func (r registry) All() []uuid.UUID {
+	return []uuid.UUID{r.id}
+}
+
+// deadLetter records the deliveries the outbox gave up on.
+type deadLetter struct {
+	letters []entities.DeadLetter
+}
+
+func (d *deadLetter) Add(dead entities.DeadLetter) error {
+	d.letters = append(d.letters, dead)
+
+	return nil
+}
+
+// OutboxTestSuite represents the test suite for the durable delivery queue.
+type OutboxTestSuite struct {
+	suite.Suite
+
+	path     string
+	registry registry
+	target   entities.Target
+}
+
+func (suite *OutboxTestSuite) SetupTest() {
+	suite.path = filepath.Join(suite.T().TempDir(), "outbox.db")
+	suite.target = entities.Target{Name: "ops", Type: "webhook", URL: "https://hooks.example.com"}
+	suite.registry = registry{id: uuid.New(), targets: []entities.Target{suite.target}}
+}
+
+func (suite *OutboxTestSuite) open(sender *recordingSender, options ...outbox.Option) *outbox.Outbox {
+	options = append([]outbox.Option{outbox.WithBackoff(time.Millisecond, time.Millisecond)}, options...)
+
+	box, err := outbox.Open(suite.path, sender, suite.registry, options...)
+	suite.Require().NoError(err)
+
+	return box
+}
+
+func (suite *OutboxTestSuite) event(status entities.Status) entities.Event {
+	return entities.Event{
+		ID:     suite.registry.id,
+		Status: status,
+		Time:   time.Now().UTC().Truncate(time.Second),
+		Agent:  entities.Agent{Hostname: "node-1"},
+	}
+}
+
+// TestOutbox_Delivered verifies that a delivered event leaves the queue.
+func (suite *OutboxTestSuite) TestOutbox_Delivered() {
+	sender := &recordingSender{}
+	box := suite.open(sender)
+
+	defer box.Close()
+
+	suite.Require().NoError(box.Send(context.Background(), suite.target, suite.event(entities.Up)))
+	suite.Equal(0, box.Len())
+	suite.Len(sender.events, 1)
+}
+
+// TestOutbox_SurvivesRestart verifies that a failed delivery is retried after a restart.
+func (suite *OutboxTestSuite) TestOutbox_SurvivesRestart() {
+	sender := &recordingSender{err: deliveryError{temporary: true}}
+	box := suite.open(sender)
+
+	event := suite.event(entities.Down)
+	event.Missed = []entities.Event{suite.event(entities.Up)}
+
+	suite.Require().NoError(box.Send(context.Background(), suite.target, event))
+	suite.Equal(1, box.Len())
+	suite.Require().NoError(box.Close())
+
+	// The delivery is loaded from the file.
+	sender.fail(nil)
+
+	box = suite.open(sender)
+	defer box.Close()
+
+	suite.Equal(1, box.Len())
+
+	time.Sleep(5 * time.Millisecond)
+	box.Retry(context.Background())
+
+	suite.Equal(0, box.Len())
+	suite.Require().Len(sender.events, 1)
+	suite.Equal(entities.Down, sender.events[0].Status)
+	suite.True(event.Time.Equal(sender.events[0].Time))
+	suite.Equal("node-1", sender.events[0].Agent.Hostname)
+	suite.Require().Len(sender.events[0].Missed, 1)
+	suite.Equal(entities.Up, sender.events[0].Missed[0].Status)
+}
+
+// TestOutbox_KeepsOrder verifies that the events of a target wait behind the queued ones.
+func (suite *OutboxTestSuite) TestOutbox_KeepsOrder() {
+	sender := &recordingSender{err: deliveryError{temporary: true}}
+	box := suite.open(sender)
+
+	defer box.Close()
+
+	suite.Require().NoError(box.Send(context.Background(), suite.target, suite.event(entities.Down)))
+
+	sender.fail(nil)
+
+	// The target recovered, but the event waits for the queued one.
+	suite.Require().NoError(box.Send(context.Background(), suite.target, suite.event(entities.Up)))
+	suite.Empty(sender.events)
+	suite.Equal(2, box.Len())
+
+	time.Sleep(5 * time.Millisecond)
+	box.Retry(context.Background())
+
+	suite.Equal(0, box.Len())
+	suite.Require().Len(sender.events, 2)
+	suite.Equal(entities.Down, sender.events[0].Status)
+	suite.Equal(entities.Up, sender.events[1].Status)
+}
+
+// TestOutbox_Rejected verifies that a permanently rejected event is returned to the caller.
+func (suite *OutboxTestSuite) TestOutbox_Rejected() {
+	sender := &recordingSender{err: deliveryError{temporary: false}}
+	box := suite.open(sender)
+
+	defer box.Close()
+
+	suite.Require().ErrorAs(box.Send(context.Background(), suite.target, suite.event(entities.Down)), &deliveryError{})
+	suite.Equal(0, box.Len())
+}
+
+// TestOutbox_GivesUp verifies that a delivery exhausting its attempts is handed over to the dead letter store.
+func (suite *OutboxTestSuite) TestOutbox_GivesUp() {
+	sender := &recordingSender{err: deliveryError{temporary: true}}
+	dead := &deadLetter{}
+	box := suite.open(sender, outbox.WithAttempts(3), outbox.WithDeadLetter(dead))
+
+	defer box.Close()
+
+	suite.Require().NoError(box.Send(context.Background(), suite.target, suite.event(entities.Down)))
+
+	for range 2 {
+		time.Sleep(5 * time.Millisecond)
+		box.Retry(context.Background())
+	}
+
+	suite.Equal(3, sender.calls)
+	suite.Equal(0, box.Len())
+	suite.Require().Len(dead.letters, 1)
+	suite.Equal("ops", dead.letters[0].Target)
+	suite.Equal(entities.Down, dead.letters[0].Event.Status)
+	suite.Equal("delivery failed", dead.letters[0].Error)
+	suite.Equal(3, dead.letters[0].Attempts)
+}
+
+// TestOutbox_RemovedTarget verifies that the deliveries of a removed target are discarded.
+func (suite *OutboxTestSuite) TestOutbox_RemovedTarget() {
+	sender := &recordingSender{err: deliveryError{temporary: true}}
+	box := suite.open(sender)
+
+	suite.Require().NoError(box.Send(context.Background(), suite.target, suite.event(entities.Down)))
+	suite.Require().NoError(box.Close())
+
+	suite.registry.targets = nil
+	box = suite.open(sender)
+
+	defer box.Close()
+
+	time.Sleep(5 * time.Millisecond)
+	box.Retry(context.Background())
+
+	suite.Equal(0, box.Len())
+	suite.Equal(1, sender.calls)
+}
+
+// TestOutboxTestSuite runs the test suite for the durable delivery queue.
+func TestOutboxTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(OutboxTestSuite))
+}
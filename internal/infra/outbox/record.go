@@ -0,0 +1,118 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// delivery is the persisted representation of a queued delivery.
+type delivery struct {
+	// Webhook is the UUID of the webhook.
+	Webhook uuid.UUID `json:"webhook"`
+
+	// Target is the name of the target.
+	Target string `json:"target"`
+
+	// Event is the event to deliver.
+	Event event `json:"event"`
+
+	// Attempt is the number of the attempts made.
+	Attempt int `json:"attempt"`
+
+	// Due is the time of the next attempt.
+	Due time.Time `json:"due"`
+
+	// Error is the error of the last attempt.
+	Error string `json:"error,omitempty"`
+}
+
+// key returns the target of the delivery.
+func (d delivery) key() key {
+	return key{id: d.Webhook, target: d.Target}
+}
+
+// event is the persisted representation of an entities.Event.
+type event struct {
+	ID          uuid.UUID         `json:"id"`
+	Status      string            `json:"status"`
+	Time        time.Time         `json:"time"`
+	Since       time.Time         `json:"since"`
+	LastSeen    time.Time         `json:"last_seen"`
+	Message     string            `json:"message,omitempty"`
+	Reason      string            `json:"reason,omitempty"`
+	Cause       uuid.UUID         `json:"cause,omitempty"`
+	Missed      []event           `json:"missed,omitempty"`
+	Unreachable bool              `json:"unreachable,omitempty"`
+	Warning     bool              `json:"warning,omitempty"`
+	Hostname    string            `json:"hostname,omitempty"`
+	Version     string            `json:"version,omitempty"`
+	Labels      map[string]string `json:"labels,omitempty"`
+	RequestID   string            `json:"request_id,omitempty"`
+}
+
+// encodeEvent returns the persisted representation of the event.
+func encodeEvent(e entities.Event) event {
+	var missed []event
+
+	for _, m := range e.Missed {
+		missed = append(missed, encodeEvent(m))
+	}
+
+	return event{
+		ID:          e.ID,
+		Status:      e.Status.String(),
+		Time:        e.Time,
+		Since:       e.Since,
+		LastSeen:    e.LastSeen,
+		Message:     e.Message,
+		Reason:      e.Reason,
+		Cause:       e.Cause,
+		Missed:      missed,
+		Unreachable: e.Unreachable,
+		Warning:     e.Warning(),
+		Hostname:    e.Agent.Hostname,
+		Version:     e.Agent.Version,
+		Labels:      e.Agent.Labels,
+		RequestID:   e.RequestID,
+	}
+}
+
+// decode returns the entities.Event of the persisted representation.
+func (e event) decode() entities.Event {
+	var missed []entities.Event
+
+	for _, m := range e.Missed {
+		missed = append(missed, m.decode())
+	}
+
+	// The events are written by encodeEvent, so the status is always valid.
+	status, _ := entities.ParseStatus(e.Status)
+
+	kind := entities.KindTransition
+	if e.Warning {
+		kind = entities.KindWarning
+	}
+
+	return entities.Event{
+		Kind:        kind,
+		ID:          e.ID,
+		Status:      status,
+		Time:        e.Time,
+		Since:       e.Since,
+		LastSeen:    e.LastSeen,
+		Message:     e.Message,
+		Reason:      e.Reason,
+		Cause:       e.Cause,
+		Missed:      missed,
+		Unreachable: e.Unreachable,
+		Agent: entities.Agent{
+			Hostname: e.Hostname,
+			Version:  e.Version,
+			Labels:   e.Labels,
+		},
+		RequestID: e.RequestID,
+	}
+}
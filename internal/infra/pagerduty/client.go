@@ -0,0 +1,165 @@
+package pagerduty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// EventsURL is the default endpoint of the PagerDuty Events API v2.
+const EventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+var (
+	// ErrRoutingKeyRequired is an error that indicates that the target has no routing key.
+	ErrRoutingKeyRequired = errors.New("pagerduty: routing key is required")
+
+	// ErrUnexpectedStatus is an error that indicates that PagerDuty responded with a non-2xx status code.
+	ErrUnexpectedStatus = errors.New("pagerduty: unexpected response status")
+)
+
+// API is a client for the PagerDuty Events API v2.
+//
+// A Down event triggers an alert and an Up event resolves it. The UUID of the
+// service is used as the deduplication key, so repeated events for the same
+// service are grouped into a single incident.
+type API struct {
+	// client: The HTTP client used to make requests to PagerDuty.
+	client *http.Client
+}
+
+// Option is a function that can be used to configure an API instance.
+type Option func(*API)
+
+// WithClient returns an Option function that sets the http.Client used to send HTTP requests
+// to PagerDuty.
+func WithClient(c http.Client) Option {
+	return func(api *API) {
+		api.client = &c
+	}
+}
+
+// NewAPI creates a new PagerDuty API client.
+//
+// Parameters:
+//
+//	ops: A variadic number of Option functions.
+//
+// Returns:
+//
+//	A pointer to an API struct.
+//
+//nolint:exhaustruct
+func NewAPI(ops ...Option) *API {
+	// Create a new API struct with default settings for the http.Client.
+	api := &API{
+		client: &http.Client{},
+	}
+
+	// Apply all provided options to the API struct.
+	for _, op := range ops {
+		op(api)
+	}
+
+	return api
+}
+
+// event is the payload of the PagerDuty Events API v2.
+type event struct {
+	RoutingKey  string   `json:"routing_key"`
+	EventAction string   `json:"event_action"`
+	DedupKey    string   `json:"dedup_key"`
+	Payload     *payload `json:"payload,omitempty"`
+}
+
+// payload is the alert details of a trigger event.
+type payload struct {
+	Summary   string `json:"summary"`
+	Source    string `json:"source"`
+	Severity  string `json:"severity"`
+	Timestamp string `json:"timestamp"`
+}
+
+// Send triggers or resolves the alert of the service depending on the status of the event.
+//
+// Parameters:
+// - ctx: The context.Context to use for the request.
+// - target: The entities.Target with the routing key and an optional URL.
+// - e: The entities.Event to deliver.
+//
+// Returns an error if the request cannot be sent or PagerDuty rejects the event.
+func (s *API) Send(ctx context.Context, target entities.Target, e entities.Event) error {
+	if target.RoutingKey == "" {
+		return ErrRoutingKeyRequired
+	}
+
+	// Resolve the alert when the service is up again.
+	body := event{
+		RoutingKey:  target.RoutingKey,
+		EventAction: "resolve",
+		DedupKey:    e.ID.String(),
+		Payload:     nil,
+	}
+
+	// Trigger the alert when the service is down, or a warning when it is degraded.
+	if e.Status == entities.Down || e.Status == entities.Degraded {
+		// Use the rendered message, if any.
+		summary := e.Message
+		if summary == "" {
+			summary = fmt.Sprintf("Service %s is %s", e.ID, e.Status)
+		}
+
+		body.EventAction = "trigger"
+		body.Payload = &payload{
+			Summary:   summary,
+			Source:    "vakeel-way",
+			Severity:  severity(e.Status),
+			Timestamp: e.Time.UTC().Format(time.RFC3339),
+		}
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	// Use the public endpoint unless the target overrides it.
+	url := target.URL
+	if url == "" {
+		url = EventsURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// PagerDuty responds with 202 Accepted when the event is enqueued.
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// severity returns the severity of the alert triggered for the status.
+func severity(status entities.Status) string {
+	if status == entities.Degraded {
+		return "warning"
+	}
+
+	return "critical"
+}
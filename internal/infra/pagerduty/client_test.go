@@ -0,0 +1,106 @@
+package pagerduty_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/pagerduty"
+)
+
+// event is the payload received by the fake Events API.
+type event struct {
+	RoutingKey  string `json:"routing_key"`
+	EventAction string `json:"event_action"`
+	DedupKey    string `json:"dedup_key"`
+	Payload     *struct {
+		Summary   string `json:"summary"`
+		Source    string `json:"source"`
+		Severity  string `json:"severity"`
+		Timestamp string `json:"timestamp"`
+	} `json:"payload"`
+}
+
+// ClientTestSuite represents the test suite for the PagerDuty client.
+type ClientTestSuite struct {
+	suite.Suite
+}
+
+// TestClient_Send verifies that the alert of the service is triggered and resolved.
+func (suite *ClientTestSuite) TestClient_Send() {
+	var events []event
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var received event
+
+		suite.NoError(json.NewDecoder(r.Body).Decode(&received))
+
+		events = append(events, received)
+
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	id := uuid.New()
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	target := entities.Target{Name: "pagerduty", Type: "pagerduty", URL: server.URL, RoutingKey: "key"}
+
+	for _, e := range []entities.Event{
+		{ID: id, Status: entities.Down, Time: at},
+		{ID: id, Status: entities.Degraded, Message: "replica lag", Time: at},
+		{ID: id, Status: entities.Up, Time: at},
+	} {
+		suite.Require().NoError(pagerduty.NewAPI().Send(context.Background(), target, e))
+	}
+
+	suite.Require().Len(events, 3)
+
+	for _, received := range events {
+		suite.Equal("key", received.RoutingKey)
+		suite.Equal(id.String(), received.DedupKey)
+	}
+
+	suite.Equal("trigger", events[0].EventAction)
+	suite.Require().NotNil(events[0].Payload)
+	suite.Equal("critical", events[0].Payload.Severity)
+	suite.Equal("Service "+id.String()+" is down", events[0].Payload.Summary)
+	suite.Equal("2024-05-01T12:00:00Z", events[0].Payload.Timestamp)
+
+	suite.Equal("trigger", events[1].EventAction)
+	suite.Require().NotNil(events[1].Payload)
+	suite.Equal("warning", events[1].Payload.Severity)
+	suite.Equal("replica lag", events[1].Payload.Summary)
+
+	suite.Equal("resolve", events[2].EventAction)
+	suite.Nil(events[2].Payload)
+}
+
+// TestClient_Errors verifies that the targets without a routing key and the rejected events are reported.
+func (suite *ClientTestSuite) TestClient_Errors() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	api := pagerduty.NewAPI()
+	down := entities.Event{ID: uuid.New(), Status: entities.Down}
+
+	err := api.Send(context.Background(), entities.Target{Name: "pagerduty", Type: "pagerduty", URL: server.URL}, down)
+	suite.Require().ErrorIs(err, pagerduty.ErrRoutingKeyRequired)
+
+	err = api.Send(context.Background(), entities.Target{Name: "pagerduty", Type: "pagerduty", URL: server.URL, RoutingKey: "key"}, down)
+	suite.Require().ErrorIs(err, pagerduty.ErrUnexpectedStatus)
+}
+
+// TestClientTestSuite runs the test suite for the PagerDuty client.
+func TestClientTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ClientTestSuite))
+}
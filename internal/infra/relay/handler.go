@@ -0,0 +1,97 @@
+package relay
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// heartbeatRequest is the JSON body of a batch of heartbeats.
+type heartbeatRequest struct {
+	// IDs is the list of the UUIDs of the services.
+	IDs []uuid.UUID `json:"ids"`
+}
+
+// agentConfig is the JSON representation of the configuration of the agents of a service.
+type agentConfig struct {
+	// Interval is the desired interval between two heartbeats, e.g. "30s".
+	Interval string `json:"interval,omitempty"`
+
+	// Features is the list of the feature flags of the agents.
+	Features []string `json:"features,omitempty"`
+}
+
+// heartbeatResponse is the JSON body of the response to the heartbeats.
+type heartbeatResponse struct {
+	// Configs is the configuration of the agents of the services pushed by the server.
+	Configs map[uuid.UUID]agentConfig `json:"configs"`
+}
+
+// Handler returns the HTTP handler accepting the heartbeats of the local processes.
+//
+// The handler serves the following endpoints:
+//   - POST /heartbeat/{id}: the heartbeat of a single service.
+//   - POST /heartbeat: the heartbeats of several services, as {"ids": [...]}.
+//
+// The heartbeats are accepted with 202 Accepted, or rejected with
+// 503 Service Unavailable if the buffer of the relay is full. The response
+// carries the configuration of the agents of the services pushed by the
+// server, as {"configs": {"<id>": {"interval": "30s", "features": [...]}}}, so
+// the agents adapt to the changes without being redeployed.
+//
+// Returns:
+//   - The http.Handler of the relay.
+func (r *Relay) Handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /heartbeat/{id}", func(w http.ResponseWriter, req *http.Request) {
+		id, err := uuid.Parse(req.PathValue("id"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		r.accept(w, id)
+	})
+
+	mux.HandleFunc("POST /heartbeat", func(w http.ResponseWriter, req *http.Request) {
+		// The maximum size of the request body.
+		const maxBody = 1 << 20
+
+		var body heartbeatRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, maxBody)).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		r.accept(w, body.IDs...)
+	})
+
+	return mux
+}
+
+// accept buffers the heartbeats and writes the response.
+func (r *Relay) accept(w http.ResponseWriter, ids ...uuid.UUID) {
+	if err := r.Push(ids...); errors.Is(err, ErrBufferFull) {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+
+		return
+	}
+
+	resp := heartbeatResponse{Configs: make(map[uuid.UUID]agentConfig)}
+
+	for _, id := range ids {
+		if agent, ok := r.Config(id); ok {
+			resp.Configs[id] = agentConfig{Interval: agent.Interval.String(), Features: agent.Features}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,194 @@
+package relay
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/infra/atomicfile"
+)
+
+// MaxDelayed is the maximum number of the delayed heartbeats buffered per service.
+//
+// The oldest heartbeats are discarded when the limit is reached. At the
+// default resolution it covers an outage of a day.
+const MaxDelayed = 1440
+
+// Offline is a durable buffer of the heartbeats received while the upstream
+// server is unreachable.
+//
+// The heartbeats are kept with the time they were received, at most one per
+// resolution per service, and are written to a JSON file after every change,
+// so they survive a restart of the relay. The file is replaced atomically.
+type Offline struct {
+	// path is the path to the file of the buffer, or empty for an in-memory buffer.
+	path string
+
+	// resolution is the minimum time between two buffered heartbeats of a service.
+	resolution time.Duration
+
+	// heartbeats maps the UUIDs of the services to their buffered heartbeats, oldest first.
+	heartbeats map[uuid.UUID][]time.Time
+
+	// mu is the mutex used to synchronize access to the heartbeats.
+	mu sync.Mutex
+}
+
+// OpenOffline creates a new instance of the Offline struct and loads the buffered heartbeats.
+//
+// Parameters:
+//   - path: The path to the file of the buffer. If it is empty, the buffer is kept
+//     in memory only.
+//   - resolution: The minimum time between two buffered heartbeats of a service.
+//
+// Returns:
+//   - A pointer to the initialized Offline.
+//   - An error if the file exists but cannot be read.
+func OpenOffline(path string, resolution time.Duration) (*Offline, error) {
+	offline := &Offline{
+		path:       path,
+		resolution: resolution,
+		heartbeats: make(map[uuid.UUID][]time.Time),
+		mu:         sync.Mutex{},
+	}
+
+	if path == "" {
+		return offline, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return offline, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &offline.heartbeats); err != nil {
+		return nil, err
+	}
+
+	return offline, nil
+}
+
+// Add buffers the heartbeats of the services received at the given time.
+//
+// Parameters:
+//   - at: The time the heartbeats were received.
+//   - ids: The UUIDs of the services.
+//
+// Returns:
+//   - An error if the buffer cannot be written.
+func (o *Offline) Add(at time.Time, ids ...uuid.UUID) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	changed := false
+
+	for _, id := range ids {
+		times := o.heartbeats[id]
+
+		// Keep a single heartbeat per resolution.
+		if len(times) > 0 && at.Sub(times[len(times)-1]) < o.resolution {
+			continue
+		}
+
+		times = append(times, at)
+		if len(times) > MaxDelayed {
+			times = times[len(times)-MaxDelayed:]
+		}
+
+		o.heartbeats[id] = times
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+
+	return o.persist()
+}
+
+// Heartbeats returns the buffered heartbeats.
+//
+// Returns:
+//   - A map of the UUIDs of the services to their buffered heartbeats, oldest first.
+func (o *Offline) Heartbeats() map[uuid.UUID][]time.Time {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	heartbeats := make(map[uuid.UUID][]time.Time, len(o.heartbeats))
+	for id, times := range o.heartbeats {
+		heartbeats[id] = append([]time.Time(nil), times...)
+	}
+
+	return heartbeats
+}
+
+// Ack removes the heartbeats that have been delivered.
+//
+// Parameters:
+//   - heartbeats: The delivered heartbeats, as returned by Heartbeats.
+//
+// Returns:
+//   - An error if the buffer cannot be written.
+func (o *Offline) Ack(heartbeats map[uuid.UUID][]time.Time) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	for id, delivered := range heartbeats {
+		times := o.heartbeats[id]
+		if len(delivered) == 0 || len(times) == 0 {
+			continue
+		}
+
+		// The heartbeats are ordered, so the delivered ones are a prefix.
+		last := delivered[len(delivered)-1]
+		n := 0
+
+		for n < len(times) && !times[n].After(last) {
+			n++
+		}
+
+		if n == len(times) {
+			delete(o.heartbeats, id)
+		} else {
+			o.heartbeats[id] = times[n:]
+		}
+	}
+
+	return o.persist()
+}
+
+// Len returns the number of the buffered heartbeats.
+func (o *Offline) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	n := 0
+	for _, times := range o.heartbeats {
+		n += len(times)
+	}
+
+	return n
+}
+
+// persist writes the buffer to the file, if any.
+// The caller must hold the mutex.
+func (o *Offline) persist() error {
+	if o.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(o.heartbeats)
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(o.path, data)
+}
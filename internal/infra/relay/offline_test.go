@@ -0,0 +1,50 @@
+package relay_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/relay"
+)
+
+// OfflineTestSuite represents the test suite for the offline buffer functionality.
+type OfflineTestSuite struct {
+	suite.Suite
+}
+
+// TestOffline_Persist verifies that the buffered heartbeats are downsampled to
+// the resolution, survive reopening the buffer and are removed once acknowledged.
+func (suite *OfflineTestSuite) TestOffline_Persist() {
+	path := filepath.Join(suite.T().TempDir(), "relay.json")
+	id := uuid.New()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	offline, err := relay.OpenOffline(path, time.Minute)
+	suite.Require().NoError(err)
+	suite.Require().NoError(offline.Add(now, id))
+	suite.Require().NoError(offline.Add(now.Add(time.Second), id))
+	suite.Require().NoError(offline.Add(now.Add(time.Minute), id))
+	suite.Equal(2, offline.Len())
+
+	reopened, err := relay.OpenOffline(path, time.Minute)
+	suite.Require().NoError(err)
+
+	heartbeats := reopened.Heartbeats()
+	suite.Require().Len(heartbeats[id], 2)
+	suite.True(now.Equal(heartbeats[id][0]))
+
+	// A heartbeat buffered after the snapshot is kept.
+	suite.Require().NoError(reopened.Add(now.Add(2*time.Minute), id))
+	suite.Require().NoError(reopened.Ack(heartbeats))
+	suite.Equal(1, reopened.Len())
+}
+
+// TestOfflineTestSuite runs the test suite for the offline buffer functionality.
+func TestOfflineTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(OfflineTestSuite))
+}
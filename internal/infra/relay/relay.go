@@ -0,0 +1,631 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	apiv1 "github.com/bavix/apis/pkg/bavix/api/v1"
+	"github.com/bavix/apis/pkg/uuidconv"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+)
+
+// ErrBufferFull is an error that indicates that the buffer of the relay is full.
+var ErrBufferFull = errors.New("relay: buffer is full")
+
+// Relay multiplexes the heartbeats of the local processes over a single
+// upstream gRPC stream.
+//
+// The heartbeats are collected into a set and flushed upstream as a single
+// UpdateRequest every interval, so any number of local processes share one
+// connection. While the upstream is unreachable, the heartbeats are buffered
+// and flushed once the stream is reestablished: with an offline buffer they
+// keep the time they were received and are flushed as delayed heartbeats.
+type Relay struct {
+	// client is the StateService client of the upstream server.
+	client way.StateServiceClient
+
+	// token is the API token sent to the upstream server, if any.
+	token string
+
+	// interval is the time between two flushes.
+	interval time.Duration
+
+	// size is the maximum number of the distinct buffered IDs.
+	size int
+
+	// agent is the agent introduced to the upstream server in the handshake.
+	agent entities.Agent
+
+	// offline buffers the heartbeats durably while the upstream is unreachable.
+	// If it is nil, the heartbeats are kept in the pending set only.
+	offline *Offline
+
+	// pending is the set of the IDs received since the last flush.
+	pending map[uuid.UUID]struct{}
+
+	// configs is the configuration of the agents pushed by the server.
+	configs map[uuid.UUID]entities.AgentConfig
+
+	// reconnectAfter is the time the server asked to wait before reconnecting
+	// when it shut down, or zero.
+	reconnectAfter time.Duration
+
+	// throttledUntil is the time the server asked to hold back the heartbeats
+	// until when it was overloaded.
+	throttledUntil time.Time
+
+	// mu is the mutex used to synchronize access to the pending IDs and the configs.
+	mu sync.Mutex
+}
+
+// Option is a function that can be used to configure a Relay instance.
+type Option func(*Relay)
+
+// WithToken returns an Option that sets the API token sent to the upstream server.
+//
+// Parameters:
+//   - token: The API token of the relay.
+//
+// Returns:
+//   - An Option that sets the token of the Relay.
+func WithToken(token string) Option {
+	return func(r *Relay) {
+		r.token = token
+	}
+}
+
+// WithInterval returns an Option that sets the time between two flushes.
+//
+// Parameters:
+//   - interval: The time between two flushes.
+//
+// Returns:
+//   - An Option that sets the flush interval of the Relay.
+func WithInterval(interval time.Duration) Option {
+	return func(r *Relay) {
+		r.interval = interval
+	}
+}
+
+// WithBufferSize returns an Option that sets the maximum number of the distinct buffered IDs.
+//
+// Parameters:
+//   - size: The maximum number of the distinct IDs buffered during an upstream outage.
+//
+// Returns:
+//   - An Option that sets the buffer size of the Relay.
+func WithBufferSize(size int) Option {
+	return func(r *Relay) {
+		r.size = size
+	}
+}
+
+// WithAgent returns an Option that sets the agent introduced to the upstream
+// server in the handshake of every stream.
+//
+// Parameters:
+//   - agent: The hostname, the version and the labels of the relay.
+//
+// Returns:
+//   - An Option that sets the agent of the Relay.
+func WithAgent(agent entities.Agent) Option {
+	return func(r *Relay) {
+		r.agent = agent
+	}
+}
+
+// WithOffline returns an Option that sets the durable buffer of the heartbeats
+// received while the upstream is unreachable.
+//
+// The buffered heartbeats keep the time they were received and are flushed as
+// delayed heartbeats once the upstream is reachable again.
+//
+// Parameters:
+//   - offline: The Offline buffer of the heartbeats.
+//
+// Returns:
+//   - An Option that sets the offline buffer of the Relay.
+func WithOffline(offline *Offline) Option {
+	return func(r *Relay) {
+		r.offline = offline
+	}
+}
+
+// NewRelay creates a new instance of the Relay struct.
+//
+// Parameters:
+//   - client: The StateService client of the upstream server.
+//   - options: Optional configurations for the Relay.
+//
+// Returns:
+//   - A pointer to the initialized Relay.
+//
+//nolint:exhaustruct
+func NewRelay(client way.StateServiceClient, options ...Option) *Relay {
+	// The defaults of the relay.
+	const (
+		interval = time.Second
+		size     = 10000
+	)
+
+	relay := &Relay{
+		client:   client,
+		interval: interval,
+		size:     size,
+		pending:  make(map[uuid.UUID]struct{}),
+		configs:  make(map[uuid.UUID]entities.AgentConfig),
+	}
+
+	// Apply any optional configurations provided through the options parameter.
+	for _, option := range options {
+		option(relay)
+	}
+
+	return relay
+}
+
+// Push buffers the heartbeats of the services until the next flush.
+//
+// Parameters:
+//   - ids: The UUIDs of the services.
+//
+// Returns:
+//   - ErrBufferFull if the buffer cannot hold a new ID. The IDs that are
+//     already buffered are accepted anyway.
+func (r *Relay) Push(ids ...uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var err error
+
+	for _, id := range ids {
+		if _, ok := r.pending[id]; !ok && len(r.pending) >= r.size {
+			err = ErrBufferFull
+
+			continue
+		}
+
+		r.pending[id] = struct{}{}
+	}
+
+	return err
+}
+
+// Pending returns the number of the buffered IDs.
+func (r *Relay) Pending() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.pending)
+}
+
+// Run flushes the buffered heartbeats upstream until the context is canceled.
+//
+// The stream is reopened with an exponential backoff if the upstream server
+// is unreachable, or after the time the server asked for when it shut down.
+// The heartbeats are kept in the buffer meanwhile, or moved into the offline
+// buffer every interval, if any.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the relay.
+func (r *Relay) Run(ctx context.Context) {
+	// The bounds of the backoff between the reconnection attempts.
+	const (
+		minBackoff = time.Second
+		maxBackoff = 30 * time.Second
+	)
+
+	logger := zerolog.Ctx(ctx)
+	backoff := minBackoff
+
+	for ctx.Err() == nil {
+		sent, err := r.stream(ctx)
+		if ctx.Err() != nil {
+			continue
+		}
+
+		// Follow the server if it announced its shutdown, even if it closed
+		// the stream gracefully.
+		if delay := r.takeReconnectAfter(); delay > 0 {
+			logger.Info().Err(err).
+				Int("pending", r.Pending()).
+				Dur("retry", delay).
+				Msg("Upstream is shutting down, buffering heartbeats")
+
+			r.wait(ctx, delay)
+
+			backoff = minBackoff
+
+			continue
+		}
+
+		if err == nil {
+			backoff = minBackoff
+
+			continue
+		}
+
+		// Reset the backoff once the stream has worked for a while.
+		if sent > 0 {
+			backoff = minBackoff
+		}
+
+		logger.Warn().Err(err).
+			Int("pending", r.Pending()).
+			Dur("retry", backoff).
+			Msg("Upstream is unavailable, buffering heartbeats")
+
+		r.wait(ctx, backoff)
+
+		backoff = min(2*backoff, maxBackoff)
+	}
+}
+
+// stream opens an upstream stream and flushes the heartbeats over it.
+//
+// The responses of the server are received in the background to keep the
+// configuration of the agents pushed by the server. The heartbeats are held
+// back while the server asks to slow down. The stream is closed when the
+// context is canceled, after the last heartbeats are flushed.
+//
+// Returns:
+//   - The number of the requests sent over the stream.
+//   - An error if the stream failed.
+func (r *Relay) stream(ctx context.Context) (int, error) {
+	// The stream outlives the context to flush the last heartbeats on shutdown.
+	streamCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+
+	if r.token != "" {
+		streamCtx = metadata.AppendToOutgoingContext(streamCtx, "authorization", "Bearer "+r.token)
+	}
+
+	// Offer every supported protocol version, the server chooses the latest it supports.
+	streamCtx = metadata.AppendToOutgoingContext(
+		streamCtx, entities.ProtocolMetadata, strings.Join(entities.Protocols(), ","),
+	)
+
+	stream, err := r.client.UpdateStream(streamCtx)
+	if err != nil {
+		return 0, err
+	}
+
+	// The heartbeats buffered during the outage.
+	heartbeats, batches := r.offlineBatches()
+
+	// Receive the responses until the stream ends. The replies to the first
+	// requests are counted, so the delayed heartbeats are removed from the
+	// buffer once the server has processed them.
+	done := make(chan error, 1)
+	replies := make(chan struct{}, 1+len(batches))
+
+	go func() {
+		done <- r.receive(stream, replies)
+	}()
+
+	requests := 0
+
+	// Introduce the relay to the server first.
+	if !r.agent.Empty() {
+		handshake := &way.Handshake{Hostname: r.agent.Hostname, Version: r.agent.Version, Labels: r.agent.Labels}
+
+		if err := stream.Send(&way.UpdateRequest{Handshake: handshake}); err != nil {
+			return 0, <-done
+		}
+
+		requests++
+	}
+
+	// Flush the heartbeats buffered during the outage.
+	// The actual error of a failed send is returned by the server on the receive.
+	for _, delayed := range batches {
+		if err := stream.Send(&way.UpdateRequest{Delayed: delayed}); err != nil {
+			return 0, <-done
+		}
+
+		requests++
+	}
+
+	if len(batches) > 0 {
+		// Wait for the server to process the delayed heartbeats. The
+		// heartbeats are sent again on the next stream if this one fails.
+		for range requests {
+			select {
+			case <-replies:
+			case err := <-done:
+				return 0, err
+			}
+		}
+
+		if err := r.offline.Ack(heartbeats); err != nil {
+			zerolog.Ctx(ctx).Err(err).Msg("Failed to remove delayed heartbeats")
+		}
+	}
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	sent := 0
+
+	for ctx.Err() == nil {
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		case err := <-done:
+			// The server closed the stream.
+			return sent, err
+		}
+
+		// Hold the heartbeats back while the server is overloaded, unless
+		// the relay is shutting down.
+		if ctx.Err() == nil && r.throttled() {
+			continue
+		}
+
+		ids := r.take()
+		if len(ids) == 0 {
+			continue
+		}
+
+		if err := stream.Send(&way.UpdateRequest{Ids: ids}); err != nil {
+			// Keep the heartbeats until the stream is reestablished.
+			r.restore(ids)
+
+			return sent, <-done
+		}
+
+		sent++
+	}
+
+	// Close the stream and wait for the server to acknowledge the last requests.
+	if err := stream.CloseSend(); err != nil {
+		return sent, err
+	}
+
+	return sent, <-done
+}
+
+// receive stores the configuration of the agents pushed by the server until
+// the stream ends. The heartbeats throttled by the server are put back into
+// the buffer, the throttle notice of the server holds the heartbeats back, and
+// the shutdown notice sets the time to wait before reconnecting.
+//
+// Parameters:
+//   - stream: The upstream stream.
+//   - replies: The channel signaled on every reply to a request, as long as
+//     it has room.
+//
+// Returns:
+//   - nil if the server closed the stream gracefully, or the error of the stream.
+func (r *Relay) receive(stream way.StateService_UpdateStreamClient, replies chan<- struct{}) error {
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		// The server replies to every request in order, and sends the notices
+		// on its own initiative without any result.
+		if resp.GetNotice() == nil || len(resp.GetResults()) > 0 {
+			select {
+			case replies <- struct{}{}:
+			default:
+			}
+		}
+
+		// Hold the heartbeats back for the time the overloaded server asked for.
+		if throttle := resp.GetNotice().GetThrottle(); throttle != nil {
+			r.mu.Lock()
+			r.throttledUntil = time.Now().Add(throttle.GetDelay().AsDuration())
+			r.mu.Unlock()
+		}
+
+		// Resend the heartbeats dropped by the overloaded server with the next flush.
+		var throttled []*apiv1.UUID
+
+		for _, result := range resp.GetResults() {
+			if result.GetResult() == way.UpdateResult_RESULT_THROTTLED {
+				throttled = append(throttled, result.GetId())
+			}
+		}
+
+		r.restore(throttled)
+
+		// Remember the time to wait before reconnecting if the server is going away.
+		if shutdown := resp.GetNotice().GetShutdown(); shutdown != nil {
+			r.mu.Lock()
+			r.reconnectAfter = shutdown.GetReconnectAfter().AsDuration()
+			r.mu.Unlock()
+		}
+
+		if len(resp.GetConfigs()) == 0 {
+			continue
+		}
+
+		r.mu.Lock()
+
+		for _, agent := range resp.GetConfigs() {
+			id := uuidconv.DoubleInt2UUID(agent.GetId().GetHigh(), agent.GetId().GetLow())
+			r.configs[id] = entities.AgentConfig{
+				Interval: agent.GetInterval().AsDuration(),
+				Features: agent.GetFeatures(),
+			}
+		}
+
+		r.mu.Unlock()
+	}
+}
+
+// Config returns the configuration of the agents of the service pushed by the server.
+//
+// Parameters:
+//   - id: The UUID of the service.
+//
+// Returns:
+//   - The configuration of the agents of the service.
+//   - A boolean indicating whether the server has pushed the configuration.
+func (r *Relay) Config(id uuid.UUID) (entities.AgentConfig, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	agent, ok := r.configs[id]
+
+	return agent, ok
+}
+
+// wait waits for the backoff to pass, moving the heartbeats received meanwhile
+// into the offline buffer every interval.
+func (r *Relay) wait(ctx context.Context, backoff time.Duration) {
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		r.spill(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// spill moves the pending heartbeats into the offline buffer, if any.
+func (r *Relay) spill(ctx context.Context) {
+	if r.offline == nil {
+		return
+	}
+
+	ids := r.drain()
+	if len(ids) == 0 {
+		return
+	}
+
+	if err := r.offline.Add(time.Now(), ids...); err != nil {
+		zerolog.Ctx(ctx).Err(err).Msg("Failed to buffer heartbeats")
+
+		// Keep the heartbeats in memory.
+		r.mu.Lock()
+		for _, id := range ids {
+			r.pending[id] = struct{}{}
+		}
+		r.mu.Unlock()
+	}
+}
+
+// offlineBatches returns the heartbeats of the offline buffer, and the
+// delayed heartbeats to send for them in batches.
+func (r *Relay) offlineBatches() (map[uuid.UUID][]time.Time, [][]*way.Heartbeat) {
+	// The maximum number of the delayed heartbeats per request.
+	const batch = 1000
+
+	if r.offline == nil {
+		return nil, nil
+	}
+
+	heartbeats := r.offline.Heartbeats()
+
+	var (
+		batches [][]*way.Heartbeat
+		delayed []*way.Heartbeat
+	)
+
+	for id, times := range heartbeats {
+		high, low := uuidconv.UUID2DoubleInt(id)
+
+		for _, at := range times {
+			delayed = append(delayed, &way.Heartbeat{
+				Id:   &apiv1.UUID{High: high, Low: low},
+				Time: timestamppb.New(at),
+			})
+
+			if len(delayed) == batch {
+				batches = append(batches, delayed)
+				delayed = nil
+			}
+		}
+	}
+
+	if len(delayed) > 0 {
+		batches = append(batches, delayed)
+	}
+
+	return heartbeats, batches
+}
+
+// drain removes the buffered IDs and returns them.
+func (r *Relay) drain() []uuid.UUID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ids := make([]uuid.UUID, 0, len(r.pending))
+	for id := range r.pending {
+		ids = append(ids, id)
+	}
+
+	clear(r.pending)
+
+	return ids
+}
+
+// take removes the buffered IDs and returns them as an UpdateRequest payload.
+func (r *Relay) take() []*apiv1.UUID {
+	ids := r.drain()
+	payload := make([]*apiv1.UUID, 0, len(ids))
+
+	for _, id := range ids {
+		high, low := uuidconv.UUID2DoubleInt(id)
+		payload = append(payload, &apiv1.UUID{High: high, Low: low})
+	}
+
+	return payload
+}
+
+// takeReconnectAfter returns and resets the time the server asked to wait
+// before reconnecting.
+func (r *Relay) takeReconnectAfter() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delay := r.reconnectAfter
+	r.reconnectAfter = 0
+
+	return delay
+}
+
+// throttled reports whether the server asked to hold the heartbeats back.
+func (r *Relay) throttled() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return time.Now().Before(r.throttledUntil)
+}
+
+// restore puts the IDs that failed to be sent back into the buffer.
+func (r *Relay) restore(ids []*apiv1.UUID) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range ids {
+		r.pending[uuidconv.DoubleInt2UUID(id.GetHigh(), id.GetLow())] = struct{}{}
+	}
+}
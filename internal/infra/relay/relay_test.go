@@ -0,0 +1,63 @@
+package relay_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/relay"
+)
+
+// RelayTestSuite represents the test suite for the relay functionality.
+type RelayTestSuite struct {
+	suite.Suite
+}
+
+// TestRelay_Buffer verifies that the relay deduplicates the heartbeats and
+// rejects the new services once the buffer is full.
+func (suite *RelayTestSuite) TestRelay_Buffer() {
+	r := relay.NewRelay(nil, relay.WithBufferSize(2))
+	first, second := uuid.New(), uuid.New()
+
+	suite.Require().NoError(r.Push(first, first, second))
+	suite.Equal(2, r.Pending())
+
+	// The buffered services are accepted, the new ones are not.
+	suite.Require().ErrorIs(r.Push(second, uuid.New()), relay.ErrBufferFull)
+	suite.Equal(2, r.Pending())
+}
+
+// TestRelay_Handler verifies the HTTP endpoint of the relay.
+func (suite *RelayTestSuite) TestRelay_Handler() {
+	r := relay.NewRelay(nil, relay.WithBufferSize(2))
+	handler := r.Handler()
+	first, second := uuid.New(), uuid.New()
+
+	for _, tc := range []struct {
+		path string
+		body string
+		code int
+	}{
+		{path: "/heartbeat/" + first.String(), code: http.StatusAccepted},
+		{path: "/heartbeat/not-a-uuid", code: http.StatusBadRequest},
+		{path: "/heartbeat", body: `{"ids": ["` + first.String() + `", "` + second.String() + `"]}`, code: http.StatusAccepted},
+		{path: "/heartbeat", body: `{"ids": [`, code: http.StatusBadRequest},
+		{path: "/heartbeat/" + uuid.NewString(), code: http.StatusServiceUnavailable},
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body)))
+		suite.Equal(tc.code, rec.Code, tc.path)
+	}
+
+	suite.Equal(2, r.Pending())
+}
+
+// TestRelayTestSuite runs the test suite for the relay functionality.
+func TestRelayTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(RelayTestSuite))
+}
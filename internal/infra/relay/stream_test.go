@@ -0,0 +1,192 @@
+package relay_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/bavix/vakeel-way/internal/infra/relay"
+	way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+)
+
+// upstreamStream is a fake upstream stream controlled by the test.
+type upstreamStream struct {
+	grpc.ClientStream
+
+	requests  chan *way.UpdateRequest
+	responses chan *way.UpdateResponse
+}
+
+// Send passes the request to the test.
+func (s *upstreamStream) Send(req *way.UpdateRequest) error {
+	s.requests <- req
+
+	return nil
+}
+
+// Recv returns the responses of the test, then io.EOF once they are closed.
+func (s *upstreamStream) Recv() (*way.UpdateResponse, error) {
+	resp, ok := <-s.responses
+	if !ok {
+		return nil, io.EOF
+	}
+
+	return resp, nil
+}
+
+// CloseSend does nothing.
+func (s *upstreamStream) CloseSend() error {
+	return nil
+}
+
+// upstream is a fake StateServiceClient opening the fake streams.
+type upstream struct {
+	way.StateServiceClient
+
+	streams chan *upstreamStream
+}
+
+// UpdateStream opens a new fake stream and passes it to the test.
+func (u *upstream) UpdateStream(context.Context, ...grpc.CallOption) (grpc.BidiStreamingClient[way.UpdateRequest, way.UpdateResponse], error) {
+	stream := &upstreamStream{
+		requests:  make(chan *way.UpdateRequest, 16),
+		responses: make(chan *way.UpdateResponse, 16),
+	}
+
+	u.streams <- stream
+
+	return stream, nil
+}
+
+// StreamTestSuite represents the test suite for the upstream streams of the relay.
+type StreamTestSuite struct {
+	suite.Suite
+}
+
+// run starts the relay and returns the fake upstream.
+func (suite *StreamTestSuite) run(options ...relay.Option) (*relay.Relay, *upstream) {
+	client := &upstream{streams: make(chan *upstreamStream, 4)}
+	r := relay.NewRelay(client, append([]relay.Option{relay.WithInterval(10 * time.Millisecond)}, options...)...)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	suite.T().Cleanup(cancel)
+
+	go r.Run(ctx)
+
+	return r, client
+}
+
+// next returns the next value of the channel, or fails the test.
+func next[T any](suite *StreamTestSuite, ch <-chan T) T {
+	select {
+	case value := <-ch:
+		return value
+	case <-time.After(5 * time.Second):
+		suite.FailNow("timed out")
+
+		var zero T
+
+		return zero
+	}
+}
+
+// TestStream_AckOffline verifies that the offline buffer is cleared only once
+// the server replied to the delayed heartbeats.
+func (suite *StreamTestSuite) TestStream_AckOffline() {
+	offline, err := relay.OpenOffline("", time.Minute)
+	suite.Require().NoError(err)
+	suite.Require().NoError(offline.Add(time.Now().Add(-time.Hour), uuid.New()))
+
+	_, client := suite.run(relay.WithOffline(offline))
+	stream := next(suite, client.streams)
+
+	req := next(suite, stream.requests)
+	suite.Len(req.GetDelayed(), 1)
+
+	// The delayed heartbeats are sent, but not processed yet.
+	time.Sleep(50 * time.Millisecond)
+	suite.Equal(1, offline.Len())
+
+	// A notice is not a reply.
+	stream.responses <- &way.UpdateResponse{Notice: &way.Notice{Kind: &way.Notice_Throttle{Throttle: &way.Throttle{}}}}
+
+	time.Sleep(50 * time.Millisecond)
+	suite.Equal(1, offline.Len())
+
+	stream.responses <- &way.UpdateResponse{}
+
+	suite.Eventually(func() bool { return offline.Len() == 0 }, time.Second, 5*time.Millisecond)
+}
+
+// TestStream_BrokenOffline verifies that the delayed heartbeats are sent again
+// if the stream ends before the server replied.
+func (suite *StreamTestSuite) TestStream_BrokenOffline() {
+	offline, err := relay.OpenOffline("", time.Minute)
+	suite.Require().NoError(err)
+	suite.Require().NoError(offline.Add(time.Now().Add(-time.Hour), uuid.New()))
+
+	_, client := suite.run(relay.WithOffline(offline))
+	stream := next(suite, client.streams)
+
+	suite.Len(next(suite, stream.requests).GetDelayed(), 1)
+	close(stream.responses)
+
+	stream = next(suite, client.streams)
+	suite.Len(next(suite, stream.requests).GetDelayed(), 1)
+	suite.Equal(1, offline.Len())
+}
+
+// TestStream_Throttle verifies that the heartbeats are held back for the time
+// the overloaded server asked for.
+func (suite *StreamTestSuite) TestStream_Throttle() {
+	const delay = 300 * time.Millisecond
+
+	r, client := suite.run()
+	stream := next(suite, client.streams)
+
+	suite.Require().NoError(r.Push(uuid.New()))
+	suite.Len(next(suite, stream.requests).GetIds(), 1)
+
+	stream.responses <- &way.UpdateResponse{
+		Notice: &way.Notice{Kind: &way.Notice_Throttle{Throttle: &way.Throttle{Delay: durationpb.New(delay)}}},
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	held := time.Now()
+
+	suite.Require().NoError(r.Push(uuid.New()))
+	suite.Len(next(suite, stream.requests).GetIds(), 1)
+	suite.GreaterOrEqual(time.Since(held), delay/2)
+}
+
+// TestStream_Shutdown verifies that the relay reconnects after the time the
+// server asked for, even if the server closed the stream gracefully.
+func (suite *StreamTestSuite) TestStream_Shutdown() {
+	const delay = 300 * time.Millisecond
+
+	_, client := suite.run()
+	stream := next(suite, client.streams)
+
+	stream.responses <- &way.UpdateResponse{
+		Notice: &way.Notice{Kind: &way.Notice_Shutdown{Shutdown: &way.Shutdown{ReconnectAfter: durationpb.New(delay)}}},
+	}
+	close(stream.responses)
+
+	closed := time.Now()
+
+	next(suite, client.streams)
+	suite.GreaterOrEqual(time.Since(closed), delay)
+}
+
+// TestStreamTestSuite runs the test suite for the upstream streams of the relay.
+func TestStreamTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(StreamTestSuite))
+}
@@ -0,0 +1,189 @@
+package reporting
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// Options represents the options of the Sentry client.
+type Options struct {
+	// DSN is the Data Source Name of the Sentry project. If it is empty, the
+	// SENTRY_DSN environment variable is used.
+	DSN string
+
+	// Environment is the environment of the reported events, e.g. "production".
+	Environment string
+
+	// Release is the release of the reported events, i.e. the version of the application.
+	Release string
+
+	// SampleRate is the share of the reported events that are sent. Zero sends every event.
+	SampleRate float64
+}
+
+// Reporter reports the errors to Sentry.
+//
+// The delivery failures are tagged with the UUID of the service and the name
+// of the target, so they can be grouped per service in Sentry. A Reporter
+// without a DSN is disabled and drops the errors.
+type Reporter struct {
+	// hub is the Sentry hub, or nil if the reporter is disabled.
+	hub *sentry.Hub
+}
+
+// New creates a Reporter.
+//
+// Parameters:
+//   - options: The options of the Sentry client.
+//
+// Returns:
+//   - A pointer to the Reporter, disabled if no DSN is configured.
+//   - An error if the DSN is invalid.
+//
+//nolint:exhaustruct
+func New(options Options) (*Reporter, error) {
+	client, err := sentry.NewClient(sentry.ClientOptions{
+		Dsn:         options.DSN,
+		Environment: options.Environment,
+		Release:     options.Release,
+		SampleRate:  options.SampleRate,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The client falls back to the environment, so the DSN is checked after it is created.
+	if client.Options().Dsn == "" {
+		return &Reporter{}, nil
+	}
+
+	return &Reporter{hub: sentry.NewHub(client, sentry.NewScope())}, nil
+}
+
+// Enabled reports whether the errors are sent to Sentry.
+func (r *Reporter) Enabled() bool {
+	return r.hub != nil
+}
+
+// DeliveryFailed reports a status update that cannot be delivered to a target.
+//
+// Parameters:
+//   - event: The undelivered event.
+//   - target: The target that failed.
+//   - err: The error of the delivery.
+func (r *Reporter) DeliveryFailed(event entities.Event, target entities.Target, err error) {
+	r.capture(err, map[string]string{
+		"kind":        "delivery",
+		"service":     event.ID.String(),
+		"target":      target.Name,
+		"target_type": target.Type,
+		"status":      event.Status.String(),
+	})
+}
+
+// ConfigError reports a configuration that cannot be loaded.
+//
+// Parameters:
+//   - err: The error of the configuration.
+func (r *Reporter) ConfigError(err error) {
+	r.capture(err, map[string]string{"kind": "config"})
+}
+
+// UnaryInterceptor returns a gRPC interceptor that recovers the panics of the
+// calls, reports them and fails the calls with the Internal code.
+func (r *Reporter) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (resp interface{}, err error) {
+		defer r.recover(ctx, info.FullMethod, &err)
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor returns a gRPC interceptor that recovers the panics of the
+// streams, reports them and fails the streams with the Internal code.
+func (r *Reporter) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) (err error) {
+		defer r.recover(ss.Context(), info.FullMethod, &err)
+
+		return handler(srv, ss)
+	}
+}
+
+// Flush waits until the reported errors are sent or the timeout passes.
+//
+// Parameters:
+//   - timeout: The maximum time to wait.
+func (r *Reporter) Flush(timeout time.Duration) {
+	if r.hub != nil {
+		r.hub.Flush(timeout)
+	}
+}
+
+// recover recovers the panic of a call and reports it.
+//
+// The panic is recovered and logged even if the reporter is disabled, so a
+// single call cannot bring the server down.
+//
+// Parameters:
+//   - ctx: The context.Context of the call.
+//   - method: The full name of the method.
+//   - err: The error of the call, set to an Internal status error on panic.
+func (r *Reporter) recover(ctx context.Context, method string, err *error) {
+	v := recover() //nolint:revive
+	if v == nil {
+		return
+	}
+
+	*err = status.Error(codes.Internal, "internal error")
+
+	zerolog.Ctx(ctx).Error().
+		Str("method", method).
+		Interface("panic", v).
+		Str("stack", string(debug.Stack())).
+		Msg("Recovered from panic")
+
+	if r.hub == nil {
+		return
+	}
+
+	r.hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetTag("kind", "panic")
+		scope.SetTag("method", method)
+		r.hub.RecoverWithContext(ctx, v)
+	})
+}
+
+// capture reports the error with the tags.
+//
+// Parameters:
+//   - err: The error to report.
+//   - tags: The tags of the event.
+func (r *Reporter) capture(err error, tags map[string]string) {
+	if r.hub == nil || err == nil {
+		return
+	}
+
+	r.hub.WithScope(func(scope *sentry.Scope) {
+		scope.SetTags(tags)
+		r.hub.CaptureException(err)
+	})
+}
@@ -6,82 +6,403 @@ import (
 	"sync"
 
 	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
 )
 
 // ErrWebhookNotFound is an error that indicates that the requested webhook was not found.
 var ErrWebhookNotFound = errors.New("webhook not found")
 
+// Opener represents an interface for decrypting values stored at rest.
+//
+// Values that are not encrypted must be returned as is.
+type Opener interface {
+	// Open decrypts the stored value.
+	Open(value string) (string, error)
+}
+
 // WebhookStubRepository is a simple in-memory implementation of the WebhookRepository interface.
 //
-// It stores the UUIDs and their associated values in a map. The mutex is used to synchronize access to the map.
+// It stores the UUIDs and their associated targets in a map. The mutex is used to synchronize access to the map.
 //
 // Fields:
 //
-// storage is a map that stores the UUIDs and their associated values.
-// The map is used to store the UUIDs as keys and their associated values as values.
+// storage is a map that stores the UUIDs and their associated targets.
+// The map is used to store the UUIDs as keys and their associated targets as values.
 //
 // mu is a mutex used to synchronize access to the storage map.
 // The mutex is used to ensure that only one goroutine can modify the storage map at a time.
 type WebhookStubRepository struct {
-	// storage is a map that stores the UUIDs and their associated values.
-	storage map[uuid.UUID]string
+	// storage is a map that stores the UUIDs and their associated targets.
+	storage map[uuid.UUID][]entities.Target
 	// mu is a mutex used to synchronize access to the storage map.
 	// The mutex is used to ensure that only one goroutine can modify the storage map at a time.
 	mu sync.Mutex
+	// opener is used to decrypt the stored values. The values are kept encrypted in
+	// the storage and are decrypted only when they are retrieved.
+	opener Opener
+	// labels stores the labels of the webhooks indexed by their UUIDs.
+	labels map[uuid.UUID]map[string]string
+	// namespaces stores the namespaces of the webhooks indexed by their UUIDs.
+	namespaces map[uuid.UUID]string
+	// tolerances stores the timing tolerances of the webhooks indexed by their UUIDs.
+	tolerances map[uuid.UUID]entities.Tolerance
+	// agents stores the configuration of the agents of the webhooks indexed by their UUIDs.
+	agents map[uuid.UUID]entities.AgentConfig
+	// badges stores the configuration of the uptime badges of the webhooks indexed by their UUIDs.
+	badges map[uuid.UUID]entities.Badge
+	// dependencies stores the UUIDs of the webhooks each webhook depends on, indexed by its UUID.
+	dependencies map[uuid.UUID][]uuid.UUID
+	// archived stores the targets of the webhooks removed at runtime.
+	archived map[uuid.UUID][]entities.Target
+}
+
+// Option is a function that can be used to configure a WebhookStubRepository instance.
+type Option func(*WebhookStubRepository)
+
+// WithOpener returns an Option that sets the Opener used to decrypt the stored values.
+//
+// Parameters:
+// - opener: The Opener used to decrypt the stored values.
+//
+// Returns:
+// - An Option that sets the Opener of the repository.
+func WithOpener(opener Opener) Option {
+	return func(w *WebhookStubRepository) {
+		w.opener = opener
+	}
+}
+
+// WithLabels returns an Option that sets the labels of the webhooks.
+//
+// Parameters:
+// - labels: The labels of the webhooks indexed by their UUIDs.
+//
+// Returns:
+// - An Option that sets the labels of the repository.
+func WithLabels(labels map[uuid.UUID]map[string]string) Option {
+	return func(w *WebhookStubRepository) {
+		w.labels = labels
+	}
+}
+
+// WithNamespaces returns an Option that sets the namespaces of the webhooks.
+//
+// Parameters:
+// - namespaces: The namespaces of the webhooks indexed by their UUIDs.
+//
+// Returns:
+// - An Option that sets the namespaces of the repository.
+func WithNamespaces(namespaces map[uuid.UUID]string) Option {
+	return func(w *WebhookStubRepository) {
+		w.namespaces = namespaces
+	}
+}
+
+// WithTolerances returns an Option that sets the timing tolerances of the webhooks.
+//
+// Parameters:
+// - tolerances: The timing tolerances of the webhooks indexed by their UUIDs.
+//
+// Returns:
+// - An Option that sets the tolerances of the repository.
+func WithTolerances(tolerances map[uuid.UUID]entities.Tolerance) Option {
+	return func(w *WebhookStubRepository) {
+		w.tolerances = tolerances
+	}
+}
+
+// WithAgentConfigs returns an Option that sets the configuration of the agents of the webhooks.
+//
+// Parameters:
+// - agents: The configuration of the agents indexed by the UUIDs of the webhooks.
+//
+// Returns:
+// - An Option that sets the configuration of the agents of the repository.
+func WithAgentConfigs(agents map[uuid.UUID]entities.AgentConfig) Option {
+	return func(w *WebhookStubRepository) {
+		w.agents = agents
+	}
+}
+
+// WithBadges returns an Option that sets the configuration of the uptime badges of the webhooks.
+//
+// Parameters:
+// - badges: The configuration of the badges indexed by the UUIDs of the webhooks.
+//
+// Returns:
+// - An Option that sets the configuration of the badges of the repository.
+func WithBadges(badges map[uuid.UUID]entities.Badge) Option {
+	return func(w *WebhookStubRepository) {
+		w.badges = badges
+	}
+}
+
+// WithDependencies returns an Option that sets the dependencies of the webhooks.
+//
+// Parameters:
+// - dependencies: The UUIDs of the webhooks each webhook depends on, indexed by its UUID.
+//
+// Returns:
+// - An Option that sets the dependencies of the repository.
+func WithDependencies(dependencies map[uuid.UUID][]uuid.UUID) Option {
+	return func(w *WebhookStubRepository) {
+		w.dependencies = dependencies
+	}
 }
 
 // NewWebhookRepository creates a new instance of the WebhookStubRepository.
 //
-// This function takes a map that stores the UUIDs and their associated values as input and returns
+// This function takes a map that stores the UUIDs and their associated targets as input and returns
 // a pointer to the newly created WebhookStubRepository.
 // The WebhookStubRepository is a simple in-memory implementation of the WebhookRepository interface,
 // which stores the UUIDs and their associated values in a map.
 //
 // Parameters:
-// - storage: A map that stores the UUIDs and their associated values.
+// - storage: A map that stores the UUIDs and their associated targets.
+// - options: Optional configurations for the repository.
 //
 // Returns:
 // - A pointer to the newly created WebhookStubRepository.
 //
 //nolint:exhaustruct
-func NewWebhookRepository(storage map[uuid.UUID]string) *WebhookStubRepository {
+func NewWebhookRepository(storage map[uuid.UUID][]entities.Target, options ...Option) *WebhookStubRepository {
 	// Create a new instance of the WebhookStubRepository.
 	// The WebhookStubRepository stores the UUIDs and their associated values in the provided map.
-	return &WebhookStubRepository{
-		storage: storage, // Store the UUIDs and their associated values in the storage map.
+	repo := &WebhookStubRepository{
+		storage:  storage,                               // Store the UUIDs and their associated values in the storage map.
+		archived: make(map[uuid.UUID][]entities.Target), // Initialize the archived webhooks.
+	}
+
+	// Apply any optional configurations provided through the options parameter.
+	for _, option := range options {
+		option(repo)
 	}
+
+	return repo
 }
 
-// Get retrieves the value associated with the given UUID from the storage.
+// Get retrieves the targets associated with the given UUID from the storage.
 //
 // Parameters:
 // - ctx: The context.Context used to cancel the operation if needed.
 // - id: The UUID of the webhook.
 //
 // Returns:
-// - The value associated with the given UUID.
-// - An error if the UUID is not found.
+// - The targets associated with the given UUID.
+// - An error if the UUID is not found or a target cannot be decrypted.
 //
 // The function locks the mutex to prevent concurrent access to the storage.
-// It retrieves the value associated with the given UUID from the storage.
+// It retrieves the targets associated with the given UUID from the storage.
 // If the UUID is not found, it returns an error.
-// Otherwise, it returns the value associated with the given UUID.
-func (w *WebhookStubRepository) Get(_ context.Context, id uuid.UUID) (string, error) {
+// Otherwise, it returns a decrypted copy of the targets associated with the given UUID.
+func (w *WebhookStubRepository) Get(_ context.Context, id uuid.UUID) ([]entities.Target, error) {
 	// Lock the mutex to prevent concurrent access to the storage.
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	// Retrieve the value associated with the given UUID from the storage.
+	// Retrieve the targets associated with the given UUID from the storage.
 	val, ok := w.storage[id]
 
 	// If the UUID is not found, return an error.
 	if !ok {
 		// Return an error indicating that the webhook was not found.
-		return "", ErrWebhookNotFound
+		return nil, ErrWebhookNotFound
+	}
+
+	// Copy the targets, so that the decrypted values never reach the storage.
+	targets := make([]entities.Target, len(val))
+	copy(targets, val)
+
+	// Decrypt the secret fields if they are stored encrypted.
+	if w.opener != nil {
+		for i := range targets {
+			var err error
+
+			if targets[i].URL, err = w.opener.Open(targets[i].URL); err != nil {
+				return nil, err
+			}
+
+			if targets[i].RoutingKey, err = w.opener.Open(targets[i].RoutingKey); err != nil {
+				return nil, err
+			}
+
+			if targets[i].Token, err = w.opener.Open(targets[i].Token); err != nil {
+				return nil, err
+			}
+
+			if targets[i].Secret, err = w.opener.Open(targets[i].Secret); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Return the targets associated with the given UUID.
+	return targets, nil
+}
+
+// Labels returns the labels of the webhook with the given UUID.
+//
+// Parameters:
+// - id: The UUID of the webhook.
+//
+// Returns:
+// - The labels of the webhook, or nil if it has none.
+func (w *WebhookStubRepository) Labels(id uuid.UUID) map[string]string {
+	// Lock the mutex to prevent concurrent access to the storage.
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.labels[id]
+}
+
+// Namespace returns the namespace of the webhook with the given UUID.
+//
+// Parameters:
+// - id: The UUID of the webhook.
+//
+// Returns:
+// - The namespace of the webhook, or an empty string for the default namespace.
+func (w *WebhookStubRepository) Namespace(id uuid.UUID) string {
+	// Lock the mutex to prevent concurrent access to the storage.
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.namespaces[id]
+}
+
+// Tolerance returns the timing tolerance of the webhook with the given UUID.
+//
+// Parameters:
+// - id: The UUID of the webhook.
+//
+// Returns:
+// - The timing tolerance of the webhook, or the zero tolerance if it has none.
+func (w *WebhookStubRepository) Tolerance(id uuid.UUID) entities.Tolerance {
+	// Lock the mutex to prevent concurrent access to the storage.
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.tolerances[id]
+}
+
+// Dependencies returns the UUIDs of the webhooks the webhook with the given UUID depends on.
+//
+// Parameters:
+// - id: The UUID of the webhook.
+//
+// Returns:
+// - The UUIDs of the dependencies of the webhook, or nil if it has none.
+func (w *WebhookStubRepository) Dependencies(id uuid.UUID) []uuid.UUID {
+	// Lock the mutex to prevent concurrent access to the storage.
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.dependencies[id]
+}
+
+// AgentConfig returns the configuration of the agents of the webhook with the given UUID.
+//
+// Parameters:
+// - id: The UUID of the webhook.
+//
+// Returns:
+// - The configuration of the agents of the webhook.
+// - A boolean indicating whether the configuration is set.
+func (w *WebhookStubRepository) AgentConfig(id uuid.UUID) (entities.AgentConfig, bool) {
+	// Lock the mutex to prevent concurrent access to the storage.
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	agent, ok := w.agents[id]
+
+	return agent, ok
+}
+
+// Badge returns the configuration of the uptime badge of the webhook with the given UUID.
+//
+// The unset fields are taken from the default badge.
+//
+// Parameters:
+// - id: The UUID of the webhook.
+//
+// Returns:
+// - The configuration of the badge of the webhook.
+// - A boolean indicating whether the webhook exists.
+func (w *WebhookStubRepository) Badge(id uuid.UUID) (entities.Badge, bool) {
+	// Lock the mutex to prevent concurrent access to the storage.
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.storage[id]; !ok {
+		return entities.Badge{}, false
+	}
+
+	return w.badges[id].Merge(entities.DefaultBadge()), true
+}
+
+// Exists reports whether the webhook with the given UUID is configured and not archived.
+//
+// Parameters:
+// - id: The UUID of the webhook.
+//
+// Returns:
+// - true if the webhook exists, false otherwise.
+func (w *WebhookStubRepository) Exists(id uuid.UUID) bool {
+	// Lock the mutex to prevent concurrent access to the storage.
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, ok := w.storage[id]
+
+	return ok
+}
+
+// Forget archives the webhook with the given UUID.
+//
+// The archived webhook is no longer returned by Get and All until the
+// repository is reloaded from the configuration.
+//
+// Parameters:
+// - id: The UUID of the webhook.
+func (w *WebhookStubRepository) Forget(id uuid.UUID) {
+	// Lock the mutex to prevent concurrent access to the storage.
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if targets, ok := w.storage[id]; ok {
+		w.archived[id] = targets
+		delete(w.storage, id)
 	}
+}
+
+// Replace replaces the webhooks with the ones reloaded from the configuration.
+//
+// The labels, the namespaces, the tolerances, the dependencies, the
+// configuration of the agents and of the badges are reset and set again with the options. The archived
+// webhooks are restored if they are still configured.
+//
+// Parameters:
+// - storage: A map that stores the UUIDs and their associated targets.
+// - options: The options setting the data of the webhooks, e.g. WithLabels.
+func (w *WebhookStubRepository) Replace(storage map[uuid.UUID][]entities.Target, options ...Option) {
+	// Lock the mutex to prevent concurrent access to the storage.
+	w.mu.Lock()
+	defer w.mu.Unlock()
 
-	// Return the value associated with the given UUID.
-	return val, nil
+	w.storage = storage
+	w.archived = make(map[uuid.UUID][]entities.Target)
+	w.labels = nil
+	w.namespaces = nil
+	w.tolerances = nil
+	w.agents = nil
+	w.badges = nil
+	w.dependencies = nil
+
+	// Apply the data of the reloaded webhooks.
+	for _, option := range options {
+		option(w)
+	}
 }
 
 // All returns all keys from the storage.
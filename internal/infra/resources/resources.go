@@ -0,0 +1,200 @@
+package resources
+
+import (
+	"errors"
+	"io/fs"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// CgroupRoot is the mount point of the cgroup file system.
+const CgroupRoot = "/sys/fs/cgroup"
+
+// unlimitedMemory is the threshold above which a cgroup v1 memory limit means "no limit".
+//
+// The kernel reports the absence of a limit as the largest page-aligned int64.
+const unlimitedMemory = 1 << 62
+
+// errUnlimited is returned by the parsers when no limit is set.
+var errUnlimited = errors.New("unlimited")
+
+// Limits represents the resource limits of the container the process runs in.
+type Limits struct {
+	// CPUs is the number of the CPUs available to the container, e.g. 0.5.
+	// Zero means no limit.
+	CPUs float64
+
+	// Memory is the memory limit of the container in bytes. Zero means no limit.
+	Memory int64
+}
+
+// Sizing represents the sizes of the internal buffers derived from the limits.
+type Sizing struct {
+	// Procs is the number of the OS threads executing Go code (GOMAXPROCS).
+	Procs int
+
+	// MemoryLimit is the soft memory limit of the Go runtime (GOMEMLIMIT) in
+	// bytes. Zero means no limit.
+	MemoryLimit int64
+
+	// EventBuffer is the size of the buffer of the heartbeats waiting to be processed.
+	EventBuffer int
+}
+
+// Detect reads the resource limits of the container from the cgroup file system.
+//
+// Both cgroup v2 and cgroup v1 are supported. The limits that cannot be read
+// are reported as unlimited, so the process behaves as on a bare host.
+//
+// Parameters:
+//   - fsys: The cgroup file system, e.g. os.DirFS(CgroupRoot).
+//
+// Returns:
+//   - The detected limits.
+func Detect(fsys fs.FS) Limits {
+	var limits Limits
+
+	// cgroup v2 exposes the limits of the cgroup in the unified hierarchy.
+	if memory, err := readMemoryV2(fsys); err == nil {
+		limits.Memory = memory
+	} else if memory, err := readMemoryV1(fsys); err == nil {
+		limits.Memory = memory
+	}
+
+	if cpus, err := readCPUV2(fsys); err == nil {
+		limits.CPUs = cpus
+	} else if cpus, err := readCPUV1(fsys); err == nil {
+		limits.CPUs = cpus
+	}
+
+	return limits
+}
+
+// DetectHost reads the resource limits of the container the process runs in.
+//
+// Returns:
+//   - The detected limits.
+func DetectHost() Limits {
+	return Detect(os.DirFS(CgroupRoot))
+}
+
+// Sizing derives the sizes of the internal buffers from the limits.
+//
+// The number of the threads is the CPU limit rounded up, but at most the
+// number of the CPUs of the host. The memory limit of the Go runtime leaves
+// 10% of the container limit for the memory the runtime does not manage.
+//
+// Parameters:
+//   - numCPU: The number of the CPUs of the host.
+//
+// Returns:
+//   - The sizes of the internal buffers.
+func (l Limits) Sizing(numCPU int) Sizing {
+	// The number of the heartbeats buffered per thread.
+	const eventsPerProc = 64
+
+	procs := numCPU
+	if l.CPUs > 0 {
+		procs = max(1, min(numCPU, int(math.Ceil(l.CPUs))))
+	}
+
+	return Sizing{
+		Procs:       procs,
+		MemoryLimit: l.Memory / 10 * 9, //nolint:mnd
+		EventBuffer: procs * eventsPerProc,
+	}
+}
+
+// readMemoryV2 reads the memory limit of cgroup v2.
+func readMemoryV2(fsys fs.FS) (int64, error) {
+	value, err := readValue(fsys, "memory.max")
+	if err != nil {
+		return 0, err
+	}
+
+	if value == "max" {
+		return 0, nil
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// readMemoryV1 reads the memory limit of cgroup v1.
+func readMemoryV1(fsys fs.FS) (int64, error) {
+	value, err := readValue(fsys, "memory/memory.limit_in_bytes")
+	if err != nil {
+		return 0, err
+	}
+
+	memory, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || memory >= unlimitedMemory {
+		return 0, err
+	}
+
+	return memory, nil
+}
+
+// readCPUV2 reads the CPU limit of cgroup v2 in the "$MAX $PERIOD" format.
+func readCPUV2(fsys fs.FS) (float64, error) {
+	value, err := readValue(fsys, "cpu.max")
+	if err != nil {
+		return 0, err
+	}
+
+	quota, period, _ := strings.Cut(value, " ")
+	if quota == "max" {
+		return 0, nil
+	}
+
+	return ratio(quota, period)
+}
+
+// readCPUV1 reads the CPU limit of cgroup v1 from the CFS quota and period.
+func readCPUV1(fsys fs.FS) (float64, error) {
+	quota, err := readValue(fsys, "cpu/cpu.cfs_quota_us")
+	if err != nil {
+		return 0, err
+	}
+
+	if quota == "-1" {
+		return 0, nil
+	}
+
+	period, err := readValue(fsys, "cpu/cpu.cfs_period_us")
+	if err != nil {
+		return 0, err
+	}
+
+	return ratio(quota, period)
+}
+
+// ratio divides the CPU quota by the period.
+func ratio(quota, period string) (float64, error) {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if q <= 0 || p <= 0 {
+		return 0, errUnlimited
+	}
+
+	return q / p, nil
+}
+
+// readValue reads the trimmed content of the file.
+func readValue(fsys fs.FS, name string) (string, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
@@ -0,0 +1,84 @@
+package resources_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/resources"
+)
+
+// ResourcesTestSuite represents the test suite for the detection of the resource limits.
+type ResourcesTestSuite struct {
+	suite.Suite
+}
+
+// TestDetect verifies that the limits of both cgroup versions are detected.
+func (suite *ResourcesTestSuite) TestDetect() {
+	for _, tc := range []struct {
+		name   string
+		fsys   fstest.MapFS
+		limits resources.Limits
+	}{
+		{
+			name:   "bare host",
+			fsys:   fstest.MapFS{},
+			limits: resources.Limits{CPUs: 0, Memory: 0},
+		},
+		{
+			name: "cgroup v2",
+			fsys: fstest.MapFS{
+				"memory.max": {Data: []byte("268435456\n")},
+				"cpu.max":    {Data: []byte("50000 100000\n")},
+			},
+			limits: resources.Limits{CPUs: 0.5, Memory: 268435456},
+		},
+		{
+			name: "cgroup v2 unlimited",
+			fsys: fstest.MapFS{
+				"memory.max": {Data: []byte("max\n")},
+				"cpu.max":    {Data: []byte("max 100000\n")},
+			},
+			limits: resources.Limits{CPUs: 0, Memory: 0},
+		},
+		{
+			name: "cgroup v1",
+			fsys: fstest.MapFS{
+				"memory/memory.limit_in_bytes": {Data: []byte("536870912\n")},
+				"cpu/cpu.cfs_quota_us":         {Data: []byte("200000\n")},
+				"cpu/cpu.cfs_period_us":        {Data: []byte("100000\n")},
+			},
+			limits: resources.Limits{CPUs: 2, Memory: 536870912},
+		},
+		{
+			name: "cgroup v1 unlimited",
+			fsys: fstest.MapFS{
+				"memory/memory.limit_in_bytes": {Data: []byte("9223372036854771712\n")},
+				"cpu/cpu.cfs_quota_us":         {Data: []byte("-1\n")},
+			},
+			limits: resources.Limits{CPUs: 0, Memory: 0},
+		},
+	} {
+		suite.Equal(tc.limits, resources.Detect(tc.fsys), tc.name)
+	}
+}
+
+// TestSizing verifies that the buffers are sized by the limits.
+func (suite *ResourcesTestSuite) TestSizing() {
+	// Half a CPU still needs a thread.
+	sizing := resources.Limits{CPUs: 0.5, Memory: 1000}.Sizing(8)
+	suite.Equal(resources.Sizing{Procs: 1, MemoryLimit: 900, EventBuffer: 64}, sizing)
+
+	// The limit above the host is capped.
+	suite.Equal(4, resources.Limits{CPUs: 16, Memory: 0}.Sizing(4).Procs)
+
+	// No limits at all.
+	suite.Equal(resources.Sizing{Procs: 4, MemoryLimit: 0, EventBuffer: 256}, resources.Limits{}.Sizing(4))
+}
+
+// TestResourcesTestSuite runs the test suite for the detection of the resource limits.
+func TestResourcesTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ResourcesTestSuite))
+}
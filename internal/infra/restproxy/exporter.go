@@ -0,0 +1,412 @@
+package restproxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// contentType is the content type of the records with JSON values of the REST Proxy API v2.
+const contentType = "application/vnd.kafka.json.v2+json"
+
+var (
+	// ErrUnexpectedStatus is an error that indicates that the REST Proxy responded with a non-2xx status code.
+	ErrUnexpectedStatus = errors.New("restproxy: unexpected response status")
+
+	// ErrRejected is an error that indicates that the REST Proxy failed to produce some records of a batch.
+	ErrRejected = errors.New("restproxy: records rejected")
+)
+
+// Event is a structured event exported to the topic.
+type Event struct {
+	// Type is the type of the event: "transition" or "heartbeat".
+	Type string `json:"type"`
+
+	// ID is the UUID of the service.
+	ID uuid.UUID `json:"id"`
+
+	// Status is the status of the service.
+	Status string `json:"status"`
+
+	// Message is the message of the heartbeat, if any.
+	Message string `json:"message,omitempty"`
+
+	// TTL is the time the heartbeat is valid for, if the agent proposed one.
+	TTL string `json:"ttl,omitempty"`
+
+	// Time is the time of the event.
+	Time time.Time `json:"time"`
+
+	// Source is the name of the instance that exported the event.
+	Source string `json:"source,omitempty"`
+}
+
+// record is a record of the REST Proxy API v2.
+type record struct {
+	Key   string `json:"key"`
+	Value Event  `json:"value"`
+}
+
+// offsets is the response of the REST Proxy API v2 to a batch.
+type offsets struct {
+	Offsets []struct {
+		ErrorCode *int   `json:"error_code"`
+		Error     string `json:"error"`
+	} `json:"offsets"`
+}
+
+// Exporter publishes the events to a Kafka topic through the REST Proxy.
+//
+// The events are produced with the REST Proxy API v2 of Confluent, so the REST
+// Proxy, or a compatible one such as the HTTP Proxy of Redpanda, has to be
+// deployed in front of the brokers. The brokers are never reached directly.
+//
+// The events are buffered and produced in batches every interval. The UUID of
+// the service is the key of the records, so the events of a service keep
+// their order in a partition. The events are exported without blocking the
+// caller: if the buffer is full, the new events are dropped.
+type Exporter struct {
+	// client is the HTTP client of the REST Proxy.
+	client *http.Client
+
+	// endpoint is the URL of the topic on the REST Proxy.
+	endpoint string
+
+	// username and password are the credentials of the REST Proxy, if any.
+	username string
+	password string
+
+	// source is the name of the instance set on the events.
+	source string
+
+	// interval is the time between two batches.
+	interval time.Duration
+
+	// size is the maximum number of the buffered events.
+	size int
+
+	// pending are the events waiting to be produced.
+	pending []Event
+
+	// mu is the mutex used to synchronize access to the pending events.
+	mu sync.Mutex
+
+	// dropped is the number of the events dropped because the buffer was full.
+	dropped atomic.Uint64
+
+	// now returns the current time.
+	now func() time.Time
+}
+
+// Option is a function that can be used to configure an Exporter instance.
+type Option func(*Exporter)
+
+// WithClient returns an Option that sets the HTTP client of the REST Proxy.
+//
+// Parameters:
+//   - client: The HTTP client.
+//
+// Returns:
+//   - An Option that sets the HTTP client of the Exporter.
+func WithClient(client *http.Client) Option {
+	return func(e *Exporter) {
+		e.client = client
+	}
+}
+
+// WithCredentials returns an Option that sets the basic credentials of the REST Proxy.
+//
+// Parameters:
+//   - username: The username. If it is empty, no credentials are sent.
+//   - password: The password.
+//
+// Returns:
+//   - An Option that sets the credentials of the Exporter.
+func WithCredentials(username, password string) Option {
+	return func(e *Exporter) {
+		e.username = username
+		e.password = password
+	}
+}
+
+// WithSource returns an Option that sets the name of the instance set on the events.
+//
+// Parameters:
+//   - source: The name of the instance, e.g. its hostname.
+//
+// Returns:
+//   - An Option that sets the source of the Exporter.
+func WithSource(source string) Option {
+	return func(e *Exporter) {
+		e.source = source
+	}
+}
+
+// WithInterval returns an Option that sets the time between two batches.
+//
+// Parameters:
+//   - interval: The time between two batches. The values below 1 are ignored.
+//
+// Returns:
+//   - An Option that sets the interval of the Exporter.
+func WithInterval(interval time.Duration) Option {
+	return func(e *Exporter) {
+		if interval > 0 {
+			e.interval = interval
+		}
+	}
+}
+
+// WithBufferSize returns an Option that sets the maximum number of the buffered events.
+//
+// Parameters:
+//   - size: The size of the buffer. The values below 1 are ignored.
+//
+// Returns:
+//   - An Option that sets the buffer size of the Exporter.
+func WithBufferSize(size int) Option {
+	return func(e *Exporter) {
+		if size > 0 {
+			e.size = size
+		}
+	}
+}
+
+// NewExporter creates a new instance of the Exporter struct.
+//
+// Parameters:
+//   - proxy: The URL of the REST Proxy, e.g. "http://127.0.0.1:8082".
+//   - topic: The topic the events are produced to.
+//   - options: Optional configurations for the Exporter.
+//
+// Returns:
+//   - A pointer to the initialized Exporter.
+//
+//nolint:exhaustruct
+func NewExporter(proxy, topic string, options ...Option) *Exporter {
+	const (
+		interval = time.Second
+		size     = 10000
+	)
+
+	exporter := &Exporter{
+		client:   &http.Client{Timeout: 10 * time.Second}, //nolint:mnd
+		endpoint: strings.TrimSuffix(proxy, "/") + "/topics/" + url.PathEscape(topic),
+		interval: interval,
+		size:     size,
+		now:      time.Now,
+	}
+
+	// Apply any optional configurations provided through the options parameter.
+	for _, option := range options {
+		option(exporter)
+	}
+
+	return exporter
+}
+
+// Dropped returns the number of the events dropped because the buffer was full.
+func (e *Exporter) Dropped() uint64 {
+	return e.dropped.Load()
+}
+
+// Transition exports the status transition.
+//
+// Parameters:
+//   - transition: The entities.Transition of a service.
+func (e *Exporter) Transition(transition entities.Transition) {
+	e.export(Event{
+		Type:    "transition",
+		ID:      transition.ID,
+		Status:  transition.Status.String(),
+		Message: "",
+		TTL:     "",
+		Time:    transition.At,
+		Source:  e.source,
+	})
+}
+
+// Broadcast exports the heartbeat.
+//
+// Parameters:
+//   - heartbeat: The entities.Heartbeat received by the instance.
+func (e *Exporter) Broadcast(heartbeat entities.Heartbeat) {
+	event := Event{
+		Type:    "heartbeat",
+		ID:      heartbeat.ID,
+		Status:  heartbeat.Status.String(),
+		Message: heartbeat.Message,
+		TTL:     "",
+		Time:    e.now(),
+		Source:  e.source,
+	}
+
+	if heartbeat.TTL > 0 {
+		event.TTL = heartbeat.TTL.String()
+	}
+
+	e.export(event)
+}
+
+// export buffers the event, or drops it if the buffer is full.
+func (e *Exporter) export(event Event) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.pending) >= e.size {
+		e.dropped.Add(1)
+
+		return
+	}
+
+	e.pending = append(e.pending, event)
+}
+
+// Run produces the buffered events every interval until the context is
+// canceled, and then produces the remaining ones once.
+//
+// A batch that cannot be produced is kept and retried with the next one.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the exporter.
+func (e *Exporter) Run(ctx context.Context) {
+	logger := zerolog.Ctx(ctx)
+
+	ticker := time.NewTicker(e.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := e.Flush(context.WithoutCancel(ctx)); err != nil {
+				logger.Err(err).Msg("restproxy: failed to export the last events")
+			}
+
+			return
+		case <-ticker.C:
+			if err := e.Flush(ctx); err != nil {
+				logger.Err(err).Msg("restproxy: failed to export the events")
+			}
+		}
+	}
+}
+
+// Flush produces the buffered events as a single batch.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the request.
+//
+// Returns:
+//   - An error if the batch cannot be produced. The events are kept, unless
+//     the REST Proxy rejected some of them, as the others were produced.
+func (e *Exporter) Flush(ctx context.Context) error {
+	e.mu.Lock()
+	batch := e.pending
+	e.pending = nil
+	e.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	if err := e.produce(ctx, batch); err != nil && !errors.Is(err, ErrRejected) {
+		// Put the batch back in front of the events buffered in the meantime.
+		e.mu.Lock()
+		e.pending = append(batch, e.pending...)
+
+		if excess := len(e.pending) - e.size; excess > 0 {
+			e.pending = e.pending[:e.size]
+			e.dropped.Add(uint64(excess))
+		}
+		e.mu.Unlock()
+
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// produce sends the batch to the REST Proxy.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the request.
+//   - batch: The events of the batch.
+//
+// Returns:
+//   - An error if the REST Proxy cannot be reached or rejects the batch.
+//   - ErrRejected if some records of the batch were not produced.
+func (e *Exporter) produce(ctx context.Context, batch []Event) error {
+	records := make([]record, 0, len(batch))
+	for _, event := range batch {
+		records = append(records, record{Key: event.ID.String(), Value: event})
+	}
+
+	body, err := json.Marshal(struct {
+		Records []record `json:"records"`
+	}{Records: records})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	if e.username != "" {
+		req.SetBasicAuth(e.username, e.password)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Drain the body, so the connection is reused.
+		_, _ = io.Copy(io.Discard, resp.Body)
+
+		return fmt.Errorf("%w: %s", ErrUnexpectedStatus, resp.Status)
+	}
+
+	var result offsets
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		// The batch was accepted, the offsets are informative only.
+		return nil //nolint:nilerr
+	}
+
+	rejected, reason := 0, ""
+
+	for _, offset := range result.Offsets {
+		if offset.ErrorCode != nil {
+			rejected++
+			reason = offset.Error
+		}
+	}
+
+	if rejected > 0 {
+		return fmt.Errorf("%w: %d of %d: %s", ErrRejected, rejected, len(batch), reason)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package restproxy_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/restproxy"
+)
+
+// proxy is a fake REST Proxy recording the produced records.
+type proxy struct {
+	mu      sync.Mutex
+	fail    bool
+	records []map[string]any
+}
+
+// ServeHTTP records the records of the batch, or fails if asked to.
+func (p *proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.fail || r.URL.Path != "/topics/events" || r.Header.Get("Content-Type") != "application/vnd.kafka.json.v2+json" {
+		w.WriteHeader(http.StatusServiceUnavailable)
+
+		return
+	}
+
+	var batch struct {
+		Records []map[string]any `json:"records"`
+	}
+
+	_ = json.NewDecoder(r.Body).Decode(&batch)
+	p.records = append(p.records, batch.Records...)
+
+	_, _ = w.Write([]byte(`{"offsets":[{"partition":0,"offset":0}]}`))
+}
+
+// ExporterTestSuite represents the test suite for the export through the Kafka REST Proxy.
+type ExporterTestSuite struct {
+	suite.Suite
+}
+
+// TestExporter_Flush verifies that the events are produced keyed by the UUID
+// of the service, and kept while the REST Proxy is unavailable.
+//
+//nolint:exhaustruct
+func (suite *ExporterTestSuite) TestExporter_Flush() {
+	ctx := context.Background()
+	fake := &proxy{fail: true}
+	server := httptest.NewServer(fake)
+	suite.T().Cleanup(server.Close)
+
+	exporter := restproxy.NewExporter(server.URL, "events", restproxy.WithSource("test"))
+	id := uuid.New()
+	at := time.Now().UTC().Truncate(time.Second)
+
+	exporter.Transition(entities.Transition{ID: id, Status: entities.Down, At: at})
+	exporter.Broadcast(entities.Heartbeat{ID: id, Status: entities.Degraded, Message: "slow", TTL: time.Minute})
+
+	suite.Require().ErrorIs(exporter.Flush(ctx), restproxy.ErrUnexpectedStatus)
+
+	fake.fail = false
+	suite.Require().NoError(exporter.Flush(ctx))
+	suite.Require().Len(fake.records, 2)
+
+	suite.Equal(id.String(), fake.records[0]["key"])
+	suite.Equal(map[string]any{
+		"type":   "transition",
+		"id":     id.String(),
+		"status": "down",
+		"time":   at.Format(time.RFC3339),
+		"source": "test",
+	}, fake.records[0]["value"])
+
+	heartbeat, _ := fake.records[1]["value"].(map[string]any)
+	suite.Equal("heartbeat", heartbeat["type"])
+	suite.Equal("degraded", heartbeat["status"])
+	suite.Equal("slow", heartbeat["message"])
+	suite.Equal("1m0s", heartbeat["ttl"])
+
+	// Nothing is left to produce.
+	suite.Require().NoError(exporter.Flush(ctx))
+	suite.Len(fake.records, 2)
+}
+
+// TestExporter_BufferSize verifies that the events beyond the buffer are dropped.
+func (suite *ExporterTestSuite) TestExporter_BufferSize() {
+	exporter := restproxy.NewExporter("http://127.0.0.1:1", "events", restproxy.WithBufferSize(1))
+
+	exporter.Broadcast(entities.Heartbeat{ID: uuid.New(), Status: entities.Up, Message: "", TTL: 0})
+	exporter.Broadcast(entities.Heartbeat{ID: uuid.New(), Status: entities.Up, Message: "", TTL: 0})
+
+	suite.Equal(uint64(1), exporter.Dropped())
+}
+
+// TestExporterTestSuite runs the test suite for the export through the Kafka REST Proxy.
+func TestExporterTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ExporterTestSuite))
+}
@@ -0,0 +1,272 @@
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Prefix is the prefix of every sealed value.
+//
+// A sealed value has the format "enc:<key id>:<base64(nonce|ciphertext)>".
+const Prefix = "enc:"
+
+// vaultTimeout is the time allowed to read a value from Vault.
+const vaultTimeout = 10 * time.Second
+
+// KeySize is the size of the key material in bytes (AES-256).
+const KeySize = 32
+
+var (
+	// ErrUnknownKey is an error that indicates that the value was sealed with a key missing from the keyring.
+	ErrUnknownKey = errors.New("secrets: unknown key")
+
+	// ErrMalformed is an error that indicates that the sealed value cannot be parsed.
+	ErrMalformed = errors.New("secrets: malformed sealed value")
+
+	// ErrNoPrimaryKey is an error that indicates that the keyring has no key to seal values with.
+	ErrNoPrimaryKey = errors.New("secrets: primary key is not configured")
+
+	// ErrKeySize is an error that indicates that the key material has an invalid size.
+	ErrKeySize = errors.New("secrets: key must be 32 bytes long")
+
+	// ErrKeyID is an error that indicates that the key identifier has an invalid format.
+	ErrKeyID = errors.New("secrets: key identifier must consist of letters, digits, \"_\", \".\" and \"-\"")
+)
+
+// keyID matches the identifiers of the keys.
+//
+// It is the key identifier of the sealed pattern, so every value sealed with
+// a key of the keyring is found by Rotate.
+var keyID = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// sealed matches sealed values embedded into a text, e.g. a configuration file.
+var sealed = regexp.MustCompile(`enc:[A-Za-z0-9_.-]+:[A-Za-z0-9_-]+`)
+
+// Keyring seals and opens sensitive values with AES-256-GCM.
+//
+// The keyring holds several keys to support key rotation: values are always
+// sealed with the primary key, but can be opened with any key of the keyring.
+type Keyring struct {
+	// primary is the identifier of the key used to seal new values.
+	primary string
+
+	// keys maps key identifiers to the AEAD ciphers.
+	keys map[string]cipher.AEAD
+
+	// vault reads the values referenced in Vault, or nil if Vault is not configured.
+	vault *Vault
+}
+
+// NewKeyring creates a new instance of the Keyring struct.
+//
+// Parameters:
+//   - primary: The identifier of the key used to seal new values. It may be empty
+//     if the keyring is only used to open values.
+//   - keys: The key material indexed by the key identifier.
+//
+// Returns:
+//   - A pointer to the initialized Keyring.
+//   - An error if a key identifier or the key material is invalid, or the
+//     primary key is missing.
+func NewKeyring(primary string, keys map[string][]byte) (*Keyring, error) {
+	ring := &Keyring{
+		primary: primary,
+		keys:    make(map[string]cipher.AEAD, len(keys)),
+		vault:   nil,
+	}
+
+	// Create an AEAD cipher for every key.
+	for id, key := range keys {
+		if !keyID.MatchString(id) {
+			return nil, fmt.Errorf("%w: %q", ErrKeyID, id)
+		}
+
+		if len(key) != KeySize {
+			return nil, fmt.Errorf("%w: %s", ErrKeySize, id)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		ring.keys[id] = aead
+	}
+
+	// The primary key must be a part of the keyring.
+	if _, ok := ring.keys[primary]; primary != "" && !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownKey, primary)
+	}
+
+	return ring, nil
+}
+
+// SetVault sets the Vault the values referenced with the vault: prefix are read from.
+//
+// Parameters:
+//   - vault: The Vault, or nil to reject the references.
+func (k *Keyring) SetVault(vault *Vault) {
+	k.vault = vault
+}
+
+// Vault returns the Vault the values referenced with the vault: prefix are read from, or nil.
+func (k *Keyring) Vault() *Vault {
+	return k.vault
+}
+
+// GenerateKey returns new random key material encoded with base64.
+func GenerateKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// IsSealed reports whether the value is sealed.
+func IsSealed(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// Seal encrypts the value with the primary key.
+//
+// Parameters:
+//   - value: The plaintext value.
+//
+// Returns:
+//   - The sealed value.
+//   - ErrNoPrimaryKey if the keyring has no primary key.
+func (k *Keyring) Seal(value string) (string, error) {
+	aead, ok := k.keys[k.primary]
+	if !ok {
+		return "", ErrNoPrimaryKey
+	}
+
+	// Generate a random nonce and prepend it to the ciphertext.
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	// The key identifier is authenticated as additional data.
+	data := aead.Seal(nonce, nonce, []byte(value), []byte(k.primary))
+
+	return Prefix + k.primary + ":" + base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// Open decrypts the sealed value.
+//
+// Values that are not sealed are returned as is, so plaintext and sealed
+// values can be mixed during a migration. The values referencing Vault are
+// read from Vault.
+//
+// Parameters:
+//   - value: The sealed (or plaintext) value, or a reference to Vault.
+//
+// Returns:
+//   - The plaintext value.
+//   - An error if the value cannot be decrypted or read from Vault.
+func (k *Keyring) Open(value string) (string, error) {
+	if IsVaultReference(value) {
+		if k.vault == nil {
+			return "", ErrNoVault
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), vaultTimeout)
+		defer cancel()
+
+		return k.vault.Read(ctx, value)
+	}
+
+	if !IsSealed(value) {
+		return value, nil
+	}
+
+	// Split the value into the key identifier and the payload.
+	id, payload, ok := strings.Cut(strings.TrimPrefix(value, Prefix), ":")
+	if !ok {
+		return "", ErrMalformed
+	}
+
+	aead, ok := k.keys[id]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKey, id)
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil || len(data) < aead.NonceSize() {
+		return "", ErrMalformed
+	}
+
+	plaintext, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], []byte(id))
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
+// Rotate re-seals every sealed value found in the text with the primary key.
+//
+// It is used to rotate the keys of a configuration file: after a new primary
+// key is added to the keyring, every value sealed with an older key is
+// re-encrypted, and the older key can be removed afterwards.
+//
+// Parameters:
+//   - text: The text containing sealed values.
+//
+// Returns:
+//   - The text with the re-sealed values.
+//   - The number of re-sealed values.
+//   - An error if a value cannot be opened or sealed.
+func (k *Keyring) Rotate(text string) (string, int, error) {
+	var (
+		count int
+		fail  error
+	)
+
+	result := sealed.ReplaceAllStringFunc(text, func(value string) string {
+		// Skip the values that are already sealed with the primary key.
+		if fail != nil || strings.HasPrefix(value, Prefix+k.primary+":") {
+			return value
+		}
+
+		plaintext, err := k.Open(value)
+		if err != nil {
+			fail = err
+
+			return value
+		}
+
+		resealed, err := k.Seal(plaintext)
+		if err != nil {
+			fail = err
+
+			return value
+		}
+
+		count++
+
+		return resealed
+	})
+
+	if fail != nil {
+		return text, 0, fail
+	}
+
+	return result, count, nil
+}
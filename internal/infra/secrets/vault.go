@@ -0,0 +1,338 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// VaultPrefix is the prefix of every reference to a secret stored in Vault.
+//
+// A reference has the format "vault:<path>#<field>", e.g.
+// "vault:secret/data/vakeel-way/slack#token".
+const VaultPrefix = "vault:"
+
+var (
+	// ErrNoVault is an error that indicates that a value references Vault, but Vault is not configured.
+	ErrNoVault = errors.New("secrets: vault is not configured")
+
+	// ErrVaultReference is an error that indicates that the reference to a secret cannot be parsed.
+	ErrVaultReference = errors.New("secrets: malformed vault reference")
+
+	// ErrVaultField is an error that indicates that the secret has no such field.
+	ErrVaultField = errors.New("secrets: the vault secret has no such field")
+
+	// ErrVaultUnavailable is an error that indicates that Vault cannot be reached or refuses the request.
+	ErrVaultUnavailable = errors.New("secrets: vault unavailable")
+)
+
+// maxVaultBody is the maximum size of a response of Vault.
+const maxVaultBody = 1 << 20
+
+// IsVaultReference reports whether the value references a secret stored in Vault.
+func IsVaultReference(value string) bool {
+	return strings.HasPrefix(value, VaultPrefix)
+}
+
+// VaultOption is a function that configures a Vault.
+type VaultOption func(*Vault)
+
+// WithVaultToken returns a VaultOption that authenticates the requests with the token.
+func WithVaultToken(token string) VaultOption {
+	return func(v *Vault) {
+		v.token = token
+	}
+}
+
+// WithVaultTokenFile returns a VaultOption that authenticates the requests
+// with the token read from the file, e.g. the sink of a Vault Agent.
+//
+// The file is read on every request, so the token renewed by the agent is
+// picked up, and the token is not renewed by the Vault.
+func WithVaultTokenFile(path string) VaultOption {
+	return func(v *Vault) {
+		v.tokenFile = path
+	}
+}
+
+// WithVaultNamespace returns a VaultOption that sets the namespace of the requests (Vault Enterprise).
+func WithVaultNamespace(namespace string) VaultOption {
+	return func(v *Vault) {
+		v.namespace = namespace
+	}
+}
+
+// WithVaultTTL returns a VaultOption that sets the time the secrets are cached for.
+//
+// The secrets with a lease are cached for the duration of their lease instead.
+func WithVaultTTL(ttl time.Duration) VaultOption {
+	return func(v *Vault) {
+		v.ttl = ttl
+	}
+}
+
+// WithVaultClient returns a VaultOption that sets the HTTP client used to call Vault.
+func WithVaultClient(client *http.Client) VaultOption {
+	return func(v *Vault) {
+		v.client = client
+	}
+}
+
+// Vault reads the secrets referenced by the configuration from HashiCorp Vault.
+//
+// The secrets are read from the KV secrets engine, version 1 or 2, and cached,
+// so the values rotated in Vault are picked up when their cache expires. If
+// Vault cannot be reached, the expired values are served until it recovers.
+type Vault struct {
+	addr      string
+	token     string
+	tokenFile string
+	namespace string
+	ttl       time.Duration
+	client    *http.Client
+
+	mu    sync.Mutex
+	cache map[string]vaultEntry
+}
+
+// vaultEntry is a secret cached by the Vault.
+type vaultEntry struct {
+	data    map[string]any
+	expires time.Time
+}
+
+// NewVault creates a new Vault.
+//
+// Parameters:
+//   - addr: The address of Vault, e.g. https://vault.example.com:8200.
+//   - opts: The options of the Vault.
+//
+// Returns:
+//   - A pointer to a Vault.
+//
+//nolint:exhaustruct
+func NewVault(addr string, opts ...VaultOption) *Vault {
+	v := &Vault{
+		addr:   strings.TrimSuffix(addr, "/"),
+		ttl:    5 * time.Minute, //nolint:mnd
+		client: http.DefaultClient,
+		cache:  make(map[string]vaultEntry),
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// Read returns the field of the secret referenced by the value.
+//
+// Parameters:
+//   - ctx: The context.Context of the request.
+//   - reference: The reference, e.g. "vault:secret/data/vakeel-way/slack#token".
+//
+// Returns:
+//   - The value of the field, formatted as JSON unless it is a string.
+//   - An error if the reference is malformed, or the secret cannot be read and is not cached.
+func (v *Vault) Read(ctx context.Context, reference string) (string, error) {
+	path, field, ok := strings.Cut(strings.TrimPrefix(reference, VaultPrefix), "#")
+	if !ok || path == "" || field == "" {
+		return "", fmt.Errorf("%w: %q", ErrVaultReference, reference)
+	}
+
+	data, err := v.secret(ctx, strings.Trim(path, "/"))
+	if err != nil {
+		return "", err
+	}
+
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("%w: %s#%s", ErrVaultField, path, field)
+	}
+
+	if text, ok := value.(string); ok {
+		return text, nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
+
+// secret returns the data of the secret, from the cache if it has not expired.
+func (v *Vault) secret(ctx context.Context, path string) (map[string]any, error) {
+	v.mu.Lock()
+	entry, cached := v.cache[path]
+	v.mu.Unlock()
+
+	if cached && time.Now().Before(entry.expires) {
+		return entry.data, nil
+	}
+
+	var resp struct {
+		LeaseDuration int            `json:"lease_duration"`
+		Data          map[string]any `json:"data"`
+	}
+
+	if err := v.do(ctx, http.MethodGet, path, &resp); err != nil {
+		// Serve the expired secret while Vault is unavailable.
+		if cached && errors.Is(err, ErrVaultUnavailable) {
+			zerolog.Ctx(ctx).Warn().Err(err).Str("path", path).Msg("Serving a cached Vault secret")
+
+			return entry.data, nil
+		}
+
+		return nil, err
+	}
+
+	data := resp.Data
+
+	// The KV secrets engine version 2 nests the data of the secret with its metadata.
+	if nested, ok := data["data"].(map[string]any); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+
+	ttl := v.ttl
+	if lease := time.Duration(resp.LeaseDuration) * time.Second; lease > 0 && lease < ttl {
+		ttl = lease
+	}
+
+	v.mu.Lock()
+	v.cache[path] = vaultEntry{data: data, expires: time.Now().Add(ttl)}
+	v.mu.Unlock()
+
+	return data, nil
+}
+
+// Run renews the token until the context is closed.
+//
+// The token is renewed at the half of its TTL. The tokens read from a file,
+// the root tokens and the tokens that are not renewable are not renewed.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the renewals, holding the logger.
+func (v *Vault) Run(ctx context.Context) {
+	if v.tokenFile != "" || v.token == "" {
+		return
+	}
+
+	logger := zerolog.Ctx(ctx)
+
+	var lookup struct {
+		Data struct {
+			TTL       int  `json:"ttl"`
+			Renewable bool `json:"renewable"`
+		} `json:"data"`
+	}
+
+	if err := v.do(ctx, http.MethodGet, "auth/token/lookup-self", &lookup); err != nil {
+		logger.Error().Err(err).Msg("Failed to look up the Vault token")
+
+		return
+	}
+
+	if !lookup.Data.Renewable || lookup.Data.TTL <= 0 {
+		return
+	}
+
+	ttl := time.Duration(lookup.Data.TTL) * time.Second
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(ttl / 2): //nolint:mnd
+		}
+
+		var renewal struct {
+			Auth struct {
+				LeaseDuration int `json:"lease_duration"`
+			} `json:"auth"`
+		}
+
+		if err := v.do(ctx, http.MethodPost, "auth/token/renew-self", &renewal); err != nil {
+			logger.Error().Err(err).Msg("Failed to renew the Vault token")
+
+			// Retry at the half of the remaining TTL, at most every five seconds.
+			ttl = max(ttl/2, 10*time.Second) //nolint:mnd
+
+			continue
+		}
+
+		ttl = time.Duration(renewal.Auth.LeaseDuration) * time.Second
+		if ttl <= 0 {
+			return
+		}
+
+		logger.Debug().Dur("ttl", ttl).Msg("Vault token renewed")
+	}
+}
+
+// do sends the request to the API of Vault and decodes the JSON response.
+func (v *Vault) do(ctx context.Context, method, path string, out any) error {
+	token, err := v.currentToken()
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, v.addr+"/v1/"+path, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("X-Vault-Token", token)
+	req.Header.Set("X-Vault-Request", "true")
+
+	if v.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.namespace)
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrVaultUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxVaultBody))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrVaultUnavailable, err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return json.Unmarshal(body, out)
+	case resp.StatusCode >= http.StatusInternalServerError, resp.StatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("%w: %s %s", ErrVaultUnavailable, path, resp.Status)
+	default:
+		return fmt.Errorf("secrets: vault %s: %s", path, resp.Status) //nolint:err113
+	}
+}
+
+// currentToken returns the token of the requests.
+func (v *Vault) currentToken() (string, error) {
+	if v.tokenFile == "" {
+		return v.token, nil
+	}
+
+	data, err := os.ReadFile(v.tokenFile)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
@@ -0,0 +1,119 @@
+package secrets_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/secrets"
+)
+
+// VaultTestSuite represents the test suite for the secrets read from Vault.
+type VaultTestSuite struct {
+	suite.Suite
+
+	server   *httptest.Server
+	requests atomic.Int32
+	down     atomic.Bool
+}
+
+// SetupTest starts a fake Vault serving a KV v2 and a KV v1 secret to the token "root".
+func (suite *VaultTestSuite) SetupTest() {
+	suite.requests.Store(0)
+	suite.down.Store(false)
+
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.requests.Add(1)
+
+		switch {
+		case suite.down.Load():
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.Header.Get("X-Vault-Token") != "root":
+			w.WriteHeader(http.StatusForbidden)
+		case r.URL.Path == "/v1/secret/data/way/slack":
+			_, _ = w.Write([]byte(`{"data":{"data":{"token":"xoxb-1","port":8443},"metadata":{"version":3}}}`))
+		case r.URL.Path == "/v1/kv/way/instatus":
+			_, _ = w.Write([]byte(`{"lease_duration":60,"data":{"key":"instatus-key"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+// TearDownTest stops the fake Vault.
+func (suite *VaultTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+// TestVault_Read verifies that the fields of the KV v1 and v2 secrets are read.
+func (suite *VaultTestSuite) TestVault_Read() {
+	vault := secrets.NewVault(suite.server.URL, secrets.WithVaultToken("root"))
+
+	value, err := vault.Read(context.Background(), "vault:secret/data/way/slack#token")
+	suite.Require().NoError(err)
+	suite.Equal("xoxb-1", value)
+
+	value, err = vault.Read(context.Background(), "vault:secret/data/way/slack#port")
+	suite.Require().NoError(err)
+	suite.Equal("8443", value)
+
+	value, err = vault.Read(context.Background(), "vault:kv/way/instatus#key")
+	suite.Require().NoError(err)
+	suite.Equal("instatus-key", value)
+
+	_, err = vault.Read(context.Background(), "vault:secret/data/way/slack#missing")
+	suite.Require().ErrorIs(err, secrets.ErrVaultField)
+
+	_, err = vault.Read(context.Background(), "vault:secret/data/way/slack")
+	suite.Require().ErrorIs(err, secrets.ErrVaultReference)
+}
+
+// TestVault_Cache verifies that the secrets are cached and served while Vault is unavailable.
+func (suite *VaultTestSuite) TestVault_Cache() {
+	vault := secrets.NewVault(suite.server.URL, secrets.WithVaultToken("root"), secrets.WithVaultTTL(time.Millisecond))
+
+	_, err := vault.Read(context.Background(), "vault:secret/data/way/slack#token")
+	suite.Require().NoError(err)
+
+	time.Sleep(5 * time.Millisecond)
+	suite.down.Store(true)
+
+	value, err := vault.Read(context.Background(), "vault:secret/data/way/slack#token")
+	suite.Require().NoError(err)
+	suite.Equal("xoxb-1", value)
+	suite.Equal(int32(2), suite.requests.Load())
+
+	_, err = vault.Read(context.Background(), "vault:kv/way/instatus#key")
+	suite.Require().ErrorIs(err, secrets.ErrVaultUnavailable)
+}
+
+// TestKeyring_Vault verifies that the keyring reads the values referencing Vault.
+func (suite *VaultTestSuite) TestKeyring_Vault() {
+	ring, err := secrets.NewKeyring("", nil)
+	suite.Require().NoError(err)
+
+	_, err = ring.Open("vault:kv/way/instatus#key")
+	suite.Require().ErrorIs(err, secrets.ErrNoVault)
+
+	ring.SetVault(secrets.NewVault(suite.server.URL, secrets.WithVaultToken("root")))
+
+	value, err := ring.Open("vault:kv/way/instatus#key")
+	suite.Require().NoError(err)
+	suite.Equal("instatus-key", value)
+
+	// The secret is cached.
+	_, err = ring.Open("vault:kv/way/instatus#key")
+	suite.Require().NoError(err)
+	suite.Equal(int32(1), suite.requests.Load())
+}
+
+// TestVaultTestSuite runs the test suite for the secrets read from Vault.
+func TestVaultTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(VaultTestSuite))
+}
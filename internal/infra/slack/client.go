@@ -0,0 +1,123 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// ErrUnexpectedStatus is an error that indicates that Slack responded with a non-2xx status code.
+var ErrUnexpectedStatus = errors.New("slack: unexpected response status")
+
+// API is a client for Slack incoming webhooks.
+//
+// The incoming webhook accepts a POST request with a JSON payload containing
+// the text of the message to post into the channel.
+type API struct {
+	// client: The HTTP client used to make requests to Slack.
+	client *http.Client
+}
+
+// Option is a function that can be used to configure an API instance.
+type Option func(*API)
+
+// WithClient returns an Option function that sets the http.Client used to send HTTP requests
+// to Slack.
+func WithClient(c http.Client) Option {
+	return func(api *API) {
+		api.client = &c
+	}
+}
+
+// NewAPI creates a new Slack API client.
+//
+// Parameters:
+//
+//	ops: A variadic number of Option functions.
+//
+// Returns:
+//
+//	A pointer to an API struct.
+//
+//nolint:exhaustruct
+func NewAPI(ops ...Option) *API {
+	// Create a new API struct with default settings for the http.Client.
+	api := &API{
+		client: &http.Client{},
+	}
+
+	// Apply all provided options to the API struct.
+	for _, op := range ops {
+		op(api)
+	}
+
+	return api
+}
+
+// message is the payload of a Slack incoming webhook.
+type message struct {
+	Text string `json:"text"`
+}
+
+// Send posts a message about the event to the incoming webhook of the target.
+//
+// Parameters:
+// - ctx: The context.Context to use for the request.
+// - target: The entities.Target with the URL of the incoming webhook.
+// - event: The entities.Event to post a message about.
+//
+// Returns an error if the request cannot be sent or Slack rejects the message.
+func (s *API) Send(ctx context.Context, target entities.Target, event entities.Event) error {
+	// Pick an emoji that corresponds to the status, or to the early warning.
+	emoji := ":large_green_circle:"
+
+	switch {
+	case event.Warning():
+		emoji = ":warning:"
+	case event.Status == entities.Down:
+		emoji = ":red_circle:"
+	case event.Status == entities.Degraded:
+		emoji = ":large_yellow_circle:"
+	}
+
+	// Use the rendered message, if any.
+	text := event.Message
+	if text == "" {
+		text = fmt.Sprintf("Service `%s` is %s", event.ID, event.Status)
+	}
+
+	// Encode the message without escaping the HTML characters of the text.
+	var payload bytes.Buffer
+
+	encoder := json.NewEncoder(&payload)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(message{Text: emoji + " " + text}); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.URL, &payload)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// Slack responds with 200 OK when the message is accepted.
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package slack_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/slack"
+)
+
+// ClientTestSuite represents the test suite for the Slack client.
+type ClientTestSuite struct {
+	suite.Suite
+}
+
+// TestClient_Send verifies that the message is posted with the emoji of the status.
+func (suite *ClientTestSuite) TestClient_Send() {
+	var texts []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		suite.Equal(http.MethodPost, r.Method)
+		suite.Equal("application/json", r.Header.Get("Content-Type"))
+
+		var payload struct {
+			Text string `json:"text"`
+		}
+
+		suite.NoError(json.NewDecoder(r.Body).Decode(&payload))
+
+		texts = append(texts, payload.Text)
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	id := uuid.MustParse("224f8a59-6705-4f3e-b7de-177757932aad")
+	target := entities.Target{Name: "slack", Type: "slack", URL: server.URL}
+
+	for _, event := range []entities.Event{
+		{ID: id, Status: entities.Down},
+		{ID: id, Status: entities.Degraded, Message: "disk <90%> full"},
+		{ID: id, Status: entities.Up},
+	} {
+		suite.Require().NoError(slack.NewAPI().Send(context.Background(), target, event))
+	}
+
+	suite.Equal([]string{
+		":red_circle: Service `224f8a59-6705-4f3e-b7de-177757932aad` is down",
+		":large_yellow_circle: disk <90%> full",
+		":large_green_circle: Service `224f8a59-6705-4f3e-b7de-177757932aad` is up",
+	}, texts)
+}
+
+// TestClient_Rejected verifies that the messages rejected by Slack are reported.
+func (suite *ClientTestSuite) TestClient_Rejected() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	err := slack.NewAPI().Send(context.Background(),
+		entities.Target{Name: "slack", Type: "slack", URL: server.URL},
+		entities.Event{ID: uuid.New(), Status: entities.Down})
+	suite.Require().ErrorIs(err, slack.ErrUnexpectedStatus)
+}
+
+// TestClientTestSuite runs the test suite for the Slack client.
+func TestClientTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ClientTestSuite))
+}
@@ -0,0 +1,267 @@
+package spool
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/atomicfile"
+)
+
+// MaxEvents is the maximum number of the events queued per target.
+//
+// The oldest events are discarded when the limit is reached: the catch-up
+// notification only summarizes the transitions, so the queue stays bounded
+// during a long outage of a target.
+const MaxEvents = 100
+
+// record is the persisted representation of an entities.Event.
+type record struct {
+	Status   string    `json:"status"`
+	Time     time.Time `json:"time"`
+	Since    time.Time `json:"since"`
+	LastSeen time.Time `json:"last_seen"`
+}
+
+// Spool is a durable queue of the events per target.
+//
+// The queue is kept in memory and, if a path is given, is written to a JSON
+// file after every change, so the queued events survive a restart of the
+// process. The file is replaced atomically.
+type Spool struct {
+	// path is the path to the file of the queue, or empty for an in-memory queue.
+	path string
+
+	// queues maps the webhooks and the names of their targets to the queued events.
+	queues map[uuid.UUID]map[string][]record
+
+	// mu is the mutex used to synchronize access to the queues.
+	mu sync.Mutex
+
+	// deadLetter receives the events discarded from the full queues, if any.
+	deadLetter DeadLetter
+}
+
+// DeadLetter represents an interface for keeping the events that could not be delivered.
+type DeadLetter interface {
+	// Add keeps the dead letter.
+	Add(dead entities.DeadLetter) error
+}
+
+// Option is a function that can be used to configure a Spool instance.
+type Option func(*Spool)
+
+// WithDeadLetter returns an Option that sets the store of the events
+// discarded from the full queues, so they are not lost silently.
+//
+// Parameters:
+//   - deadLetter: The DeadLetter store.
+//
+// Returns:
+//   - An Option that sets the dead letter store of the Spool.
+func WithDeadLetter(deadLetter DeadLetter) Option {
+	return func(s *Spool) {
+		s.deadLetter = deadLetter
+	}
+}
+
+// Open creates a new instance of the Spool struct and loads the queued events.
+//
+// Parameters:
+//   - path: The path to the file of the queue. If it is empty, the queue is kept
+//     in memory only.
+//   - options: Optional configurations for the Spool.
+//
+// Returns:
+//   - A pointer to the initialized Spool.
+//   - An error if the file exists but cannot be read.
+func Open(path string, options ...Option) (*Spool, error) {
+	spool := &Spool{
+		path:       path,
+		queues:     make(map[uuid.UUID]map[string][]record),
+		mu:         sync.Mutex{},
+		deadLetter: nil,
+	}
+
+	// Apply any optional configurations provided through the options parameter.
+	for _, option := range options {
+		option(spool)
+	}
+
+	if path == "" {
+		return spool, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return spool, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, &spool.queues); err != nil {
+		return nil, err
+	}
+
+	return spool, nil
+}
+
+// Push appends the event to the queue of the target.
+//
+// Parameters:
+//   - id: The UUID of the webhook.
+//   - target: The name of the target.
+//   - event: The entities.Event to queue.
+//
+// Returns:
+//   - An error if the queue cannot be persisted.
+func (s *Spool) Push(id uuid.UUID, target string, event entities.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.queues[id] == nil {
+		s.queues[id] = make(map[string][]record)
+	}
+
+	queue := append(s.queues[id][target], record{
+		Status:   event.Status.String(),
+		Time:     event.Time,
+		Since:    event.Since,
+		LastSeen: event.LastSeen,
+	})
+
+	// Discard the oldest events.
+	if len(queue) > MaxEvents {
+		s.discard(id, target, queue[:len(queue)-MaxEvents])
+		queue = queue[len(queue)-MaxEvents:]
+	}
+
+	s.queues[id][target] = queue
+
+	return s.persist()
+}
+
+// Queued returns the events queued for the target, oldest first.
+//
+// Parameters:
+//   - id: The UUID of the webhook.
+//   - target: The name of the target.
+func (s *Spool) Queued(id uuid.UUID, target string) []entities.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	queue := s.queues[id][target]
+	events := make([]entities.Event, 0, len(queue))
+
+	for _, rec := range queue {
+		// The records are written by Push, so the status is always valid.
+		status, _ := entities.ParseStatus(rec.Status)
+
+		events = append(events, entities.Event{
+			ID:       id,
+			Status:   status,
+			Time:     rec.Time,
+			Since:    rec.Since,
+			LastSeen: rec.LastSeen,
+			Message:  "",
+			Missed:   nil,
+		})
+	}
+
+	return events
+}
+
+// Targets returns the names of the targets with queued events per webhook.
+func (s *Spool) Targets() map[uuid.UUID][]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make(map[uuid.UUID][]string, len(s.queues))
+
+	for id, targets := range s.queues {
+		for target := range targets {
+			result[id] = append(result[id], target)
+		}
+	}
+
+	return result
+}
+
+// Ack removes the oldest events from the queue of the target after they were delivered.
+//
+// Parameters:
+//   - id: The UUID of the webhook.
+//   - target: The name of the target.
+//   - n: The number of the delivered events.
+//
+// Returns:
+//   - An error if the queue cannot be persisted.
+func (s *Spool) Ack(id uuid.UUID, target string, n int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	queue := s.queues[id][target]
+	if n >= len(queue) {
+		delete(s.queues[id], target)
+
+		if len(s.queues[id]) == 0 {
+			delete(s.queues, id)
+		}
+	} else {
+		s.queues[id][target] = queue[n:]
+	}
+
+	return s.persist()
+}
+
+// discard hands the events discarded from the full queue of the target over
+// to the dead letter store.
+//
+// Parameters:
+//   - id: The UUID of the webhook.
+//   - target: The name of the target.
+//   - records: The discarded events.
+func (s *Spool) discard(id uuid.UUID, target string, records []record) {
+	if s.deadLetter == nil {
+		return
+	}
+
+	for _, rec := range records {
+		// The records are written by Push, so the status is always valid.
+		status, _ := entities.ParseStatus(rec.Status)
+
+		// The dead letter is kept in memory even if the store cannot be persisted.
+		_ = s.deadLetter.Add(entities.DeadLetter{ //nolint:exhaustruct
+			Target: target,
+			Event: entities.Event{ //nolint:exhaustruct
+				ID:       id,
+				Status:   status,
+				Time:     rec.Time,
+				Since:    rec.Since,
+				LastSeen: rec.LastSeen,
+			},
+			Error: "discarded from the full queue of the unavailable target",
+		})
+	}
+}
+
+// persist writes the queues to the file. The caller must hold the mutex.
+func (s *Spool) persist() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(s.queues)
+	if err != nil {
+		return err
+	}
+
+	return atomicfile.Write(s.path, data)
+}
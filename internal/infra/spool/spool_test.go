@@ -0,0 +1,86 @@
+package spool_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/spool"
+)
+
+// SpoolTestSuite represents the test suite for the spool functionality.
+type SpoolTestSuite struct {
+	suite.Suite
+}
+
+// TestSpool_Persist verifies that the queued events survive reopening the spool
+// and that acknowledged events are removed.
+func (suite *SpoolTestSuite) TestSpool_Persist() {
+	path := filepath.Join(suite.T().TempDir(), "spool.json")
+	id := uuid.New()
+	now := time.Now().UTC().Truncate(time.Second)
+
+	queue, err := spool.Open(path)
+	suite.Require().NoError(err)
+	suite.Require().NoError(queue.Push(id, "slack", entities.Event{ID: id, Status: entities.Down, Time: now}))
+	suite.Require().NoError(queue.Push(id, "slack", entities.Event{ID: id, Status: entities.Up, Time: now.Add(time.Minute)}))
+
+	reopened, err := spool.Open(path)
+	suite.Require().NoError(err)
+	suite.Equal(map[uuid.UUID][]string{id: {"slack"}}, reopened.Targets())
+
+	events := reopened.Queued(id, "slack")
+	suite.Require().Len(events, 2)
+	suite.Equal(entities.Down, events[0].Status)
+	suite.Equal(entities.Up, events[1].Status)
+	suite.True(now.Equal(events[0].Time))
+
+	suite.Require().NoError(reopened.Ack(id, "slack", 1))
+	suite.Len(reopened.Queued(id, "slack"), 1)
+
+	suite.Require().NoError(reopened.Ack(id, "slack", 1))
+	suite.Empty(reopened.Targets())
+}
+
+// deadLetters records the events discarded by the spool.
+type deadLetters struct {
+	letters []entities.DeadLetter
+}
+
+func (d *deadLetters) Add(dead entities.DeadLetter) error {
+	d.letters = append(d.letters, dead)
+
+	return nil
+}
+
+// TestSpool_Bounded verifies that the oldest events are discarded and kept as dead letters.
+func (suite *SpoolTestSuite) TestSpool_Bounded() {
+	dead := &deadLetters{}
+
+	queue, err := spool.Open("", spool.WithDeadLetter(dead))
+	suite.Require().NoError(err)
+
+	id := uuid.New()
+	for i := range spool.MaxEvents + 10 {
+		suite.Require().NoError(queue.Push(id, "pagerduty", entities.Event{ID: id, Time: time.Unix(int64(i), 0)}))
+	}
+
+	events := queue.Queued(id, "pagerduty")
+	suite.Require().Len(events, spool.MaxEvents)
+	suite.Equal(int64(10), events[0].Time.Unix())
+
+	suite.Require().Len(dead.letters, 10)
+	suite.Equal("pagerduty", dead.letters[0].Target)
+	suite.Equal(id, dead.letters[0].Event.ID)
+	suite.Equal(int64(0), dead.letters[0].Event.Time.Unix())
+}
+
+// TestSpoolTestSuite runs the spool test suite.
+func TestSpoolTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(SpoolTestSuite))
+}
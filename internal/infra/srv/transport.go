@@ -0,0 +1,287 @@
+package srv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Suffix is the suffix of the schemes of the URLs whose host is resolved with SRV records.
+//
+// The host of a URL such as "https+srv://_hooks._tcp.example.com/notify" is
+// the name of the SRV records, and every request is sent to one of their
+// targets, over HTTPS in this case.
+const Suffix = "+srv"
+
+var (
+	// ErrNoTargets is an error that indicates that the SRV records have no target.
+	ErrNoTargets = errors.New("srv: no target")
+
+	// ErrUnsupportedScheme is an error that indicates that the scheme of the URL is neither HTTP nor HTTPS.
+	ErrUnsupportedScheme = errors.New("srv: unsupported scheme")
+)
+
+// Resolver looks up the SRV records, like net.Resolver.
+type Resolver interface {
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+// IsSRV reports whether the scheme resolves the host with SRV records.
+func IsSRV(scheme string) bool {
+	return strings.HasSuffix(scheme, Suffix)
+}
+
+// Option is a function that configures a Transport.
+type Option func(*Transport)
+
+// WithResolver returns an Option that sets the resolver of the SRV records.
+//
+// Parameters:
+//   - resolver: The resolver, net.DefaultResolver by default.
+//
+// Returns:
+//   - An Option that sets the resolver of the Transport.
+func WithResolver(resolver Resolver) Option {
+	return func(t *Transport) {
+		t.resolver = resolver
+	}
+}
+
+// WithRefresh returns an Option that sets the interval the hosts are resolved again after.
+//
+// Parameters:
+//   - refresh: The time the SRV records are cached for, and the idle
+//     connections are kept for before the hosts are resolved again. The
+//     values below a second are ignored.
+//
+// Returns:
+//   - An Option that sets the refresh interval of the Transport.
+func WithRefresh(refresh time.Duration) Option {
+	return func(t *Transport) {
+		if refresh >= time.Second {
+			t.refresh = refresh
+		}
+	}
+}
+
+// records are the SRV records of a name resolved at a time.
+type records struct {
+	targets  []*net.SRV
+	resolved time.Time
+}
+
+// Transport is an http.RoundTripper re-resolving the hosts of the webhook targets.
+//
+// The requests to the URLs with an SRV scheme are sent to a target of the
+// SRV records of their host, selected per request by the priority and the
+// weight of the records, so every retry of a notification may reach another
+// backend. The records are cached for the refresh interval, and the last
+// records are used while the name cannot be resolved. The idle connections
+// are closed every refresh interval, so the plain hostnames are resolved
+// again and the notifications follow the backends whose addresses change.
+type Transport struct {
+	base     http.RoundTripper
+	resolver Resolver
+	refresh  time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	records map[string]records
+	flushed time.Time
+}
+
+// NewTransport creates a new instance of the Transport struct.
+//
+// Parameters:
+//   - base: The transport the requests are sent with.
+//   - opts: Optional configurations for the Transport.
+//
+// Returns:
+//   - A pointer to the initialized Transport.
+//
+//nolint:exhaustruct
+func NewTransport(base http.RoundTripper, opts ...Option) *Transport {
+	const refresh = 30 * time.Second
+
+	transport := &Transport{
+		base:     base,
+		resolver: net.DefaultResolver,
+		refresh:  refresh,
+		now:      time.Now,
+		records:  make(map[string]records),
+	}
+
+	// Apply any optional configurations provided through the options parameter.
+	for _, opt := range opts {
+		opt(transport)
+	}
+
+	transport.flushed = transport.now()
+
+	return transport
+}
+
+// RoundTrip sends the request, to a target of the SRV records of its host if its scheme ends with Suffix.
+//
+// Parameters:
+//   - req: The request.
+//
+// Returns:
+//   - The response.
+//   - An error if the SRV records cannot be resolved or the request fails.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.flush()
+
+	if !IsSRV(req.URL.Scheme) {
+		return t.base.RoundTrip(req)
+	}
+
+	scheme := strings.TrimSuffix(req.URL.Scheme, Suffix)
+	if scheme != "http" && scheme != "https" {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedScheme, req.URL.Scheme)
+	}
+
+	address, err := t.Pick(req.Context(), req.URL.Hostname())
+	if err != nil {
+		return nil, err
+	}
+
+	// The request is sent to the target, as if it was configured with its address.
+	target := req.Clone(req.Context())
+	target.URL.Scheme = scheme
+	target.URL.Host = address
+	target.Host = ""
+
+	return t.base.RoundTrip(target)
+}
+
+// Pick resolves the SRV records of the name and selects one of their targets.
+//
+// The target is selected among the records of the lowest priority, at random
+// in proportion to their weights, as specified by RFC 2782.
+//
+// Parameters:
+//   - ctx: The context.Context of the lookup.
+//   - name: The name of the SRV records, e.g. "_hooks._tcp.example.com".
+//
+// Returns:
+//   - The address of the target in the format "host:port".
+//   - An error if the SRV records cannot be resolved or have no target.
+func (t *Transport) Pick(ctx context.Context, name string) (string, error) {
+	targets, err := t.lookup(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	// The records are sorted by priority, so the first ones have the lowest one.
+	last := 1
+	for last < len(targets) && targets[last].Priority == targets[0].Priority {
+		last++
+	}
+
+	candidates := targets[:last]
+
+	selected := candidates[0]
+
+	total := 0
+	for _, candidate := range candidates {
+		total += int(candidate.Weight)
+	}
+
+	if total == 0 {
+		selected = candidates[rand.IntN(len(candidates))] //nolint:gosec
+	} else {
+		pick := rand.IntN(total) //nolint:gosec
+
+		for _, candidate := range candidates {
+			if pick -= int(candidate.Weight); pick < 0 {
+				selected = candidate
+
+				break
+			}
+		}
+	}
+
+	return net.JoinHostPort(strings.TrimSuffix(selected.Target, "."), strconv.Itoa(int(selected.Port))), nil
+}
+
+// lookup returns the SRV records of the name, resolved at most once per refresh interval.
+//
+// Parameters:
+//   - ctx: The context.Context of the lookup.
+//   - name: The name of the SRV records.
+//
+// Returns:
+//   - The targets sorted by priority.
+//   - An error if the name cannot be resolved and was never resolved, or has no target.
+func (t *Transport) lookup(ctx context.Context, name string) ([]*net.SRV, error) {
+	t.mu.Lock()
+	cached, ok := t.records[name]
+	t.mu.Unlock()
+
+	now := t.now()
+	if ok && now.Sub(cached.resolved) < t.refresh {
+		return cached.targets, nil
+	}
+
+	_, targets, err := t.resolver.LookupSRV(ctx, "", "", name)
+	if err != nil {
+		// The last records are used while the name cannot be resolved.
+		if ok {
+			return cached.targets, nil
+		}
+
+		return nil, err
+	}
+
+	// A single target "." means that the service is decidedly not available.
+	targets = slices.DeleteFunc(slices.Clone(targets), func(target *net.SRV) bool {
+		return target.Target == "" || target.Target == "."
+	})
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoTargets, name)
+	}
+
+	slices.SortStableFunc(targets, func(a, b *net.SRV) int {
+		return int(a.Priority) - int(b.Priority)
+	})
+
+	t.mu.Lock()
+	t.records[name] = records{targets: targets, resolved: now}
+	t.mu.Unlock()
+
+	return targets, nil
+}
+
+// flush closes the idle connections of the base transport once per refresh
+// interval, so the hosts are resolved again on the next connection.
+func (t *Transport) flush() {
+	closer, ok := t.base.(interface{ CloseIdleConnections() })
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+
+	now := t.now()
+	expired := now.Sub(t.flushed) >= t.refresh
+
+	if expired {
+		t.flushed = now
+	}
+
+	t.mu.Unlock()
+
+	if expired {
+		closer.CloseIdleConnections()
+	}
+}
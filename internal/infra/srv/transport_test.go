@@ -0,0 +1,143 @@
+package srv_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/srv"
+)
+
+// resolver is a fake resolver of the SRV records counting the lookups.
+type resolver struct {
+	records map[string][]*net.SRV
+	lookups atomic.Int32
+}
+
+// LookupSRV returns the records of the name.
+func (r *resolver) LookupSRV(_ context.Context, _, _, name string) (string, []*net.SRV, error) {
+	r.lookups.Add(1)
+
+	records, ok := r.records[name]
+	if !ok {
+		return "", nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+
+	return name, records, nil
+}
+
+// TransportTestSuite represents the test suite for the resolution of the webhook targets.
+type TransportTestSuite struct {
+	suite.Suite
+
+	server   *httptest.Server
+	resolver *resolver
+}
+
+// SetupTest starts a backend and the SRV records pointing to it.
+func (suite *TransportTestSuite) SetupTest() {
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Host + r.URL.Path))
+	}))
+
+	backend, _ := url.Parse(suite.server.URL)
+	port, _ := strconv.Atoi(backend.Port())
+
+	suite.resolver = &resolver{records: map[string][]*net.SRV{
+		"_hooks._tcp.example.com": {
+			{Target: "standby.example.com.", Port: 8080, Priority: 20, Weight: 100},
+			{Target: "127.0.0.1.", Port: uint16(port), Priority: 10, Weight: 0},
+		},
+		"_weighted._tcp.example.com": {
+			{Target: "a.example.com.", Port: 80, Priority: 10, Weight: 0},
+			{Target: "b.example.com.", Port: 80, Priority: 10, Weight: 5},
+		},
+		"_down._tcp.example.com": {
+			{Target: ".", Port: 0, Priority: 0, Weight: 0},
+		},
+	}}
+}
+
+// TearDownTest stops the backend.
+func (suite *TransportTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+// TestTransport_RoundTrip verifies that the requests are sent to the target of the SRV records.
+func (suite *TransportTestSuite) TestTransport_RoundTrip() {
+	client := http.Client{Transport: srv.NewTransport(http.DefaultTransport, srv.WithResolver(suite.resolver))}
+
+	for range 3 {
+		req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, "http+srv://_hooks._tcp.example.com/notify", nil)
+		suite.Require().NoError(err)
+
+		resp, err := client.Do(req)
+		suite.Require().NoError(err)
+
+		body := make([]byte, 64)
+		n, _ := resp.Body.Read(body)
+		_ = resp.Body.Close()
+
+		suite.Equal(http.StatusOK, resp.StatusCode)
+		suite.Equal(suite.server.Listener.Addr().String()+"/notify", string(body[:n]))
+	}
+
+	// The records are resolved once per refresh interval.
+	suite.Equal(int32(1), suite.resolver.lookups.Load())
+}
+
+// TestTransport_Plain verifies that the other URLs are sent as is.
+func (suite *TransportTestSuite) TestTransport_Plain() {
+	client := http.Client{Transport: srv.NewTransport(http.DefaultTransport, srv.WithResolver(suite.resolver))}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, suite.server.URL+"/plain", nil)
+	suite.Require().NoError(err)
+
+	resp, err := client.Do(req)
+	suite.Require().NoError(err)
+	_ = resp.Body.Close()
+
+	suite.Equal(http.StatusOK, resp.StatusCode)
+	suite.Zero(suite.resolver.lookups.Load())
+}
+
+// TestTransport_Pick verifies that the targets are selected by their priority and weight.
+func (suite *TransportTestSuite) TestTransport_Pick() {
+	transport := srv.NewTransport(http.DefaultTransport, srv.WithResolver(suite.resolver))
+
+	for range 20 {
+		address, err := transport.Pick(context.Background(), "_weighted._tcp.example.com")
+		suite.Require().NoError(err)
+		suite.Equal("b.example.com:80", address)
+	}
+
+	_, err := transport.Pick(context.Background(), "_down._tcp.example.com")
+	suite.Require().ErrorIs(err, srv.ErrNoTargets)
+
+	_, err = transport.Pick(context.Background(), "_missing._tcp.example.com")
+	suite.Require().Error(err)
+}
+
+// TestTransport_Scheme verifies that only HTTP and HTTPS are resolved with SRV records.
+func (suite *TransportTestSuite) TestTransport_Scheme() {
+	client := http.Client{Transport: srv.NewTransport(http.DefaultTransport, srv.WithResolver(suite.resolver))}
+
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, "ftp+srv://_hooks._tcp.example.com/", nil)
+	suite.Require().NoError(err)
+
+	_, err = client.Do(req) //nolint:bodyclose
+	suite.Require().ErrorIs(err, srv.ErrUnsupportedScheme)
+}
+
+// TestTransportTestSuite runs the test suite for the resolution of the webhook targets.
+func TestTransportTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(TransportTestSuite))
+}
@@ -0,0 +1,356 @@
+package statsd
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// maxPacket is the size of the largest datagram sent, so the datagrams are not
+// fragmented on the networks with the common MTU of 1500 bytes.
+const maxPacket = 1432
+
+// Client emits the metrics to a StatsD server, or to a Datadog agent with the DogStatsD extensions.
+//
+// The metrics are buffered and sent in batches every interval, so recording a
+// metric never blocks the caller. The counters are summed between the
+// batches, and the timings are kept up to the size of the buffer. The
+// datagrams are sent over UDP, so a server that is down loses the metrics.
+type Client struct {
+	// address is the address of the server in the format "host:port".
+	address string
+
+	// prefix is prepended to the names of the metrics.
+	prefix string
+
+	// tags are the DogStatsD tags of every metric.
+	tags []string
+
+	// dogstatsd specifies whether the tags are sent with the DogStatsD
+	// extension, or folded into the names of the metrics.
+	dogstatsd bool
+
+	// interval is the time between the batches.
+	interval time.Duration
+
+	// size is the maximum number of the timings buffered between the batches.
+	size int
+
+	// counters are the sums of the counters since the last batch, by line prefix.
+	counters map[string]int64
+
+	// timings are the lines of the timings since the last batch.
+	timings []string
+
+	// mu guards the counters and the timings.
+	mu sync.Mutex
+
+	// dropped is the number of the timings dropped because the buffer was full.
+	dropped atomic.Uint64
+
+	// now returns the current time, so the timings can be measured.
+	now func() time.Time
+}
+
+// Option is a function that configures a Client.
+type Option func(*Client)
+
+// WithPrefix returns an Option that sets the prefix of the names of the metrics.
+//
+// Parameters:
+//   - prefix: The prefix, e.g. "vakeel_way.".
+//
+// Returns:
+//   - An Option that sets the prefix of the Client.
+func WithPrefix(prefix string) Option {
+	return func(c *Client) {
+		c.prefix = prefix
+	}
+}
+
+// WithTags returns an Option that sets the tags of every metric.
+//
+// The tags are sent with the DogStatsD extension only.
+//
+// Parameters:
+//   - tags: The tags in the format "key:value", e.g. "env:production".
+//
+// Returns:
+//   - An Option that sets the tags of the Client.
+func WithTags(tags ...string) Option {
+	return func(c *Client) {
+		c.tags = append(c.tags, tags...)
+	}
+}
+
+// WithDogStatsD returns an Option that enables the DogStatsD extensions.
+//
+// Parameters:
+//   - enabled: If true, the tags are sent with the metrics. Otherwise, the
+//     values of the tags of a metric are appended to its name, e.g.
+//     "deliveries.slack.failure", and the tags of every metric are omitted.
+//
+// Returns:
+//   - An Option that enables the DogStatsD extensions of the Client.
+func WithDogStatsD(enabled bool) Option {
+	return func(c *Client) {
+		c.dogstatsd = enabled
+	}
+}
+
+// WithInterval returns an Option that sets the time between the batches.
+//
+// Parameters:
+//   - interval: The time between the batches. Zero keeps the default of a second.
+//
+// Returns:
+//   - An Option that sets the interval of the Client.
+func WithInterval(interval time.Duration) Option {
+	return func(c *Client) {
+		if interval > 0 {
+			c.interval = interval
+		}
+	}
+}
+
+// WithBufferSize returns an Option that sets the maximum number of the timings buffered between the batches.
+//
+// Parameters:
+//   - size: The maximum number of the timings. Zero keeps the default of 10000.
+//
+// Returns:
+//   - An Option that sets the size of the buffer of the Client.
+func WithBufferSize(size int) Option {
+	return func(c *Client) {
+		if size > 0 {
+			c.size = size
+		}
+	}
+}
+
+// NewClient creates a new instance of the Client struct.
+//
+// Parameters:
+//   - address: The address of the StatsD server, e.g. "127.0.0.1:8125".
+//   - options: Optional configurations for the Client.
+//
+// Returns:
+//   - A pointer to the initialized Client.
+//
+//nolint:exhaustruct
+func NewClient(address string, options ...Option) *Client {
+	const (
+		interval = time.Second
+		size     = 10000
+	)
+
+	client := &Client{
+		address:  address,
+		interval: interval,
+		size:     size,
+		counters: make(map[string]int64),
+		now:      time.Now,
+	}
+
+	// Apply any optional configurations provided through the options parameter.
+	for _, option := range options {
+		option(client)
+	}
+
+	return client
+}
+
+// Dropped returns the number of the timings dropped because the buffer was full.
+func (c *Client) Dropped() uint64 {
+	return c.dropped.Load()
+}
+
+// Count adds the value to the counter.
+//
+// Parameters:
+//   - name: The name of the counter, without the prefix.
+//   - value: The value added to the counter.
+//   - tags: The tags of the metric in the format "key:value".
+func (c *Client) Count(name string, value int64, tags ...string) {
+	key := c.line(name, tags)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.counters[key] += value
+}
+
+// Timing records the duration of an operation.
+//
+// Parameters:
+//   - name: The name of the timer, without the prefix.
+//   - duration: The duration of the operation.
+//   - tags: The tags of the metric in the format "key:value".
+func (c *Client) Timing(name string, duration time.Duration, tags ...string) {
+	const millisecond = float64(time.Millisecond)
+
+	value := strconv.FormatFloat(float64(duration)/millisecond, 'f', -1, 64)
+	line := format(c.line(name, tags), value, "ms")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.timings) >= c.size {
+		c.dropped.Add(1)
+
+		return
+	}
+
+	c.timings = append(c.timings, line)
+}
+
+// Broadcast counts the heartbeat received by the instance, by its status.
+//
+// Parameters:
+//   - heartbeat: The entities.Heartbeat received by the instance.
+func (c *Client) Broadcast(heartbeat entities.Heartbeat) {
+	c.Count("heartbeats", 1, "status:"+heartbeat.Status.String())
+}
+
+// Transition counts the status transition of a service, by its new status.
+//
+// Parameters:
+//   - transition: The entities.Transition of the service.
+func (c *Client) Transition(transition entities.Transition) {
+	c.Count("transitions", 1, "status:"+transition.Status.String())
+}
+
+// line returns the name of the metric with its tags, in the format of the server.
+//
+// The value and the type of the metric are inserted by format.
+func (c *Client) line(name string, tags []string) string {
+	if !c.dogstatsd {
+		for _, tag := range tags {
+			_, value, _ := strings.Cut(tag, ":")
+			name += "." + strings.ReplaceAll(sanitize(value), ":", "_")
+		}
+
+		return c.prefix + name
+	}
+
+	tags = append(slices.Clone(c.tags), tags...)
+	for i, tag := range tags {
+		tags[i] = sanitize(tag)
+	}
+
+	if len(tags) == 0 {
+		return c.prefix + name
+	}
+
+	return c.prefix + name + "|#" + strings.Join(tags, ",")
+}
+
+// format inserts the value and the type into the line returned by line.
+func format(line, value, typ string) string {
+	name, tags, tagged := strings.Cut(line, "|#")
+	if !tagged {
+		return name + ":" + value + "|" + typ
+	}
+
+	return name + ":" + value + "|" + typ + "|#" + tags
+}
+
+// sanitize replaces the characters reserved by the protocol.
+func sanitize(value string) string {
+	return strings.NewReplacer("|", "_", "#", "_", ",", "_", "\n", "_", " ", "_").Replace(value)
+}
+
+// Run sends the batches of the metrics every interval until the context is canceled.
+//
+// The last batch is sent when the context is canceled.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the client, holding the logger.
+func (c *Client) Run(ctx context.Context) {
+	logger := zerolog.Ctx(ctx)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if err := c.Flush(); err != nil {
+				logger.Err(err).Msg("statsd: failed to send the last metrics")
+			}
+
+			return
+		case <-ticker.C:
+			if err := c.Flush(); err != nil {
+				logger.Warn().Err(err).Msg("statsd: failed to send the metrics")
+			}
+		}
+	}
+}
+
+// Flush sends the metrics recorded since the last batch.
+//
+// The server is resolved on every batch, so the metrics follow an agent whose
+// address changes. The metrics of a batch that cannot be sent are lost.
+//
+// Returns:
+//   - An error if the server cannot be resolved or a datagram cannot be sent.
+func (c *Client) Flush() error {
+	c.mu.Lock()
+	counters := c.counters
+	timings := c.timings
+	c.counters = make(map[string]int64, len(counters))
+	c.timings = nil
+	c.mu.Unlock()
+
+	lines := make([]string, 0, len(counters)+len(timings))
+	for key, value := range counters {
+		lines = append(lines, format(key, strconv.FormatInt(value, 10), "c"))
+	}
+
+	// The counters are sorted, so the batches are stable.
+	slices.Sort(lines)
+	lines = append(lines, timings...)
+
+	if len(lines) == 0 {
+		return nil
+	}
+
+	conn, err := net.Dial("udp", c.address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// The lines are packed into the datagrams, one metric per line.
+	packet := make([]byte, 0, maxPacket)
+
+	for _, line := range lines {
+		if len(packet) > 0 && len(packet)+1+len(line) > maxPacket {
+			if _, err := conn.Write(packet); err != nil {
+				return err
+			}
+
+			packet = packet[:0]
+		}
+
+		if len(packet) > 0 {
+			packet = append(packet, '\n')
+		}
+
+		packet = append(packet, line...)
+	}
+
+	_, err = conn.Write(packet)
+
+	return err
+}
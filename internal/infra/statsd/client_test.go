@@ -0,0 +1,137 @@
+package statsd_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+	"github.com/bavix/vakeel-way/internal/infra/statsd"
+)
+
+// errUnreachable is the error of the fake target.
+var errUnreachable = errors.New("unreachable")
+
+// sender is a fake Sender failing the deliveries to the URL "down".
+type sender struct{}
+
+// Send fails the deliveries to the URL "down".
+func (sender) Send(_ context.Context, target entities.Target, _ entities.Event) error {
+	if target.URL == "down" {
+		return errUnreachable
+	}
+
+	return nil
+}
+
+// ClientTestSuite represents the test suite for the emission of the metrics to StatsD.
+type ClientTestSuite struct {
+	suite.Suite
+
+	server net.PacketConn
+}
+
+// SetupTest starts a fake StatsD server.
+func (suite *ClientTestSuite) SetupTest() {
+	server, err := net.ListenPacket("udp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+
+	suite.server = server
+}
+
+// TearDownTest stops the fake StatsD server.
+func (suite *ClientTestSuite) TearDownTest() {
+	_ = suite.server.Close()
+}
+
+// receive returns the lines of the datagrams received by the fake server.
+func (suite *ClientTestSuite) receive() []string {
+	var lines []string
+
+	buf := make([]byte, 2048)
+
+	for {
+		_ = suite.server.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+
+		n, _, err := suite.server.ReadFrom(buf)
+		if err != nil {
+			return lines
+		}
+
+		suite.LessOrEqual(n, 1432)
+
+		lines = append(lines, strings.Split(string(buf[:n]), "\n")...)
+	}
+}
+
+// TestClient_DogStatsD verifies that the counters are summed and tagged with the DogStatsD extension.
+func (suite *ClientTestSuite) TestClient_DogStatsD() {
+	client := statsd.NewClient(
+		suite.server.LocalAddr().String(),
+		statsd.WithPrefix("vakeel_way."),
+		statsd.WithTags("env:test"),
+		statsd.WithDogStatsD(true),
+	)
+
+	id := uuid.New()
+
+	client.Broadcast(entities.Heartbeat{ID: id, Status: entities.Up})
+	client.Broadcast(entities.Heartbeat{ID: id, Status: entities.Up})
+	client.Transition(entities.Transition{ID: id, Status: entities.Down, At: time.Now()})
+	client.Timing("delivery.time", 1500*time.Microsecond, "type:slack")
+
+	suite.Require().NoError(client.Flush())
+
+	suite.Equal([]string{
+		"vakeel_way.heartbeats:2|c|#env:test,status:up",
+		"vakeel_way.transitions:1|c|#env:test,status:down",
+		"vakeel_way.delivery.time:1.5|ms|#env:test,type:slack",
+	}, suite.receive())
+
+	// The metrics are sent once.
+	suite.Require().NoError(client.Flush())
+	suite.Empty(suite.receive())
+}
+
+// TestClient_StatsD verifies that the values of the tags are folded into the names without the DogStatsD extension.
+func (suite *ClientTestSuite) TestClient_StatsD() {
+	client := statsd.NewClient(suite.server.LocalAddr().String(), statsd.WithTags("env:test"))
+
+	timed := statsd.NewTimedSender(sender{}, client)
+
+	suite.Require().NoError(timed.Send(context.Background(), entities.Target{Type: "slack", URL: "up"}, entities.Event{}))
+	suite.Require().ErrorIs(timed.Send(context.Background(), entities.Target{Type: "slack", URL: "down"}, entities.Event{}), errUnreachable)
+
+	suite.Require().NoError(client.Flush())
+
+	lines := suite.receive()
+	suite.Require().Len(lines, 4)
+	suite.Equal([]string{"deliveries.slack.failure:1|c", "deliveries.slack.success:1|c"}, lines[:2])
+	suite.True(strings.HasPrefix(lines[2], "delivery.time.slack:"))
+	suite.True(strings.HasSuffix(lines[3], "|ms"))
+}
+
+// TestClient_Packets verifies that the batches are split into the datagrams that are not fragmented.
+func (suite *ClientTestSuite) TestClient_Packets() {
+	client := statsd.NewClient(suite.server.LocalAddr().String(), statsd.WithBufferSize(500))
+
+	for range 600 {
+		client.Timing("delivery.time", time.Second)
+	}
+
+	suite.Equal(uint64(100), client.Dropped())
+	suite.Require().NoError(client.Flush())
+	suite.Len(suite.receive(), 500)
+}
+
+// TestClientTestSuite runs the test suite for the emission of the metrics to StatsD.
+func TestClientTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ClientTestSuite))
+}
@@ -0,0 +1,63 @@
+package statsd
+
+import (
+	"context"
+
+	"github.com/bavix/vakeel-way/internal/domain/entities"
+)
+
+// Sender represents an interface for delivering events to the targets.
+type Sender interface {
+	// Send delivers the event to the target.
+	Send(ctx context.Context, target entities.Target, event entities.Event) error
+}
+
+// TimedSender counts and times the deliveries of a Sender.
+//
+// Every delivery increments the "deliveries" counter, tagged with the type of
+// the target and its result, and records the "delivery.time" timing, tagged
+// with the type of the target.
+type TimedSender struct {
+	// next is the Sender delivering the events.
+	next Sender
+
+	// client is the Client the metrics are emitted with.
+	client *Client
+}
+
+// NewTimedSender creates a new instance of the TimedSender struct.
+//
+// Parameters:
+//   - next: The Sender delivering the events.
+//   - client: The Client the metrics are emitted with.
+//
+// Returns:
+//   - A pointer to the initialized TimedSender.
+func NewTimedSender(next Sender, client *Client) *TimedSender {
+	return &TimedSender{next: next, client: client}
+}
+
+// Send delivers the event to the target and records the metrics of the delivery.
+//
+// Parameters:
+//   - ctx: The context.Context used to cancel the operation if needed.
+//   - target: The entities.Target to deliver the event to.
+//   - event: The entities.Event to deliver.
+//
+// Returns:
+//   - The error of the delivery.
+func (s *TimedSender) Send(ctx context.Context, target entities.Target, event entities.Event) error {
+	started := s.client.now()
+	err := s.next.Send(ctx, target, event)
+	elapsed := s.client.now().Sub(started)
+
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+
+	s.client.Count("deliveries", 1, "type:"+target.Type, "result:"+result)
+	s.client.Timing("delivery.time", elapsed, "type:"+target.Type)
+
+	return err
+}
@@ -0,0 +1,204 @@
+package udp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// ErrNotUUID is an error that indicates that the datagram is not a UUID.
+var ErrNotUUID = errors.New("udp: the datagram is not a UUID")
+
+// maxDatagram is the size of the largest datagram read. A heartbeat is a
+// UUID, so the longer datagrams are truncated and ignored.
+const maxDatagram = 64
+
+// Parse returns the UUID of the service a datagram reports the heartbeat of.
+//
+// The datagram is either the 16 bytes of the UUID, or its text in any form
+// accepted by uuid.Parse, e.g. "224f8a59-6705-4f3e-b7de-177757932aad",
+// surrounded by optional whitespace, so `echo $ID | nc -u` reports a heartbeat.
+//
+// Parameters:
+//   - datagram: The payload of the datagram.
+//
+// Returns:
+//   - The UUID of the service.
+//   - false if the datagram is not a UUID.
+func Parse(datagram []byte) (uuid.UUID, bool) {
+	if len(datagram) == len(uuid.UUID{}) {
+		id, err := uuid.FromBytes(datagram)
+
+		return id, err == nil
+	}
+
+	id, err := uuid.ParseBytes(bytes.TrimSpace(datagram))
+
+	return id, err == nil
+}
+
+// Listener receives the heartbeats sent as UDP datagrams.
+//
+// Every datagram is a heartbeat of a single service, without any
+// acknowledgement, so the reporters sending a heartbeat many times per
+// interval pay almost nothing for it. The datagrams lost on the way are only
+// noticed if no heartbeat arrives within the TTL of the service.
+type Listener struct {
+	// conn is the connection the datagrams are read from.
+	conn net.PacketConn
+
+	// allow reports whether the datagrams of the address are accepted.
+	allow func(addr netip.Addr) bool
+
+	// secret is the secret the datagrams are signed with. If it is empty,
+	// the datagrams are not signed.
+	secret []byte
+
+	// window is the greatest age of a signed datagram.
+	window time.Duration
+
+	// now returns the current time, so the signatures can be checked.
+	now func() time.Time
+}
+
+// Option is a function that can be used to configure a Listener instance.
+type Option func(*Listener)
+
+// WithAllow returns an Option that filters the senders of the datagrams.
+//
+// Parameters:
+//   - allow: The function reporting whether the datagrams of the address are
+//     accepted. All the senders are accepted by default.
+//
+// Returns:
+//   - An Option that sets the filter of the Listener.
+func WithAllow(allow func(addr netip.Addr) bool) Option {
+	return func(l *Listener) {
+		l.allow = allow
+	}
+}
+
+// WithSecret returns an Option that requires the datagrams to be signed.
+//
+// The datagrams are accepted only if they are signed with the secret, as
+// returned by Sign, within the window around the current time, so anyone
+// reaching the port cannot report the heartbeats of the services.
+//
+// Parameters:
+//   - secret: The secret shared with the reporters.
+//   - window: The greatest difference between the time of the signature and
+//     the current time. Zero keeps the default of a minute.
+//
+// Returns:
+//   - An Option that sets the secret of the Listener.
+func WithSecret(secret []byte, window time.Duration) Option {
+	return func(l *Listener) {
+		l.secret = secret
+
+		if window > 0 {
+			l.window = window
+		}
+	}
+}
+
+// NewListener creates a new instance of the Listener struct.
+//
+// Parameters:
+//   - conn: The connection the datagrams are read from. It is closed by Run.
+//   - options: Optional configurations for the Listener.
+//
+// Returns:
+//   - A pointer to the initialized Listener.
+func NewListener(conn net.PacketConn, options ...Option) *Listener {
+	listener := &Listener{
+		conn:   conn,
+		allow:  func(netip.Addr) bool { return true },
+		secret: nil,
+		window: time.Minute,
+		now:    time.Now,
+	}
+
+	// Apply any optional configurations provided through the options parameter.
+	for _, option := range options {
+		option(listener)
+	}
+
+	return listener
+}
+
+// Run reads the heartbeats until the context is canceled, then closes the connection.
+//
+// The datagrams that are not a UUID, the datagrams of the senders that are
+// not allowed, and the unsigned datagrams if a secret is set, are dropped.
+//
+// Parameters:
+//   - ctx: The context.Context used to stop the listener.
+//   - handle: The function receiving the UUIDs of the heartbeats.
+//
+// Returns:
+//   - An error if the connection fails before the context is canceled.
+func (l *Listener) Run(ctx context.Context, handle func(id uuid.UUID)) error {
+	logger := zerolog.Ctx(ctx)
+
+	// Closing the connection unblocks the read.
+	stop := context.AfterFunc(ctx, func() {
+		_ = l.conn.Close()
+	})
+	defer stop()
+	defer l.conn.Close()
+
+	buf := make([]byte, maxDatagram)
+
+	for {
+		n, addr, err := l.conn.ReadFrom(buf)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+
+			return err
+		}
+
+		if udpAddr, ok := addr.(*net.UDPAddr); ok && !l.allow(udpAddr.AddrPort().Addr()) {
+			logger.Debug().Str("addr", addr.String()).Msg("udp: the sender is not allowed")
+
+			continue
+		}
+
+		id, err := l.parse(buf[:n])
+		if err != nil {
+			logger.Debug().Err(err).Str("addr", addr.String()).Int("size", n).Msg("udp: the datagram is rejected")
+
+			continue
+		}
+
+		handle(id)
+	}
+}
+
+// parse returns the UUID of the service a datagram reports the heartbeat of.
+//
+// Parameters:
+//   - datagram: The payload of the datagram.
+//
+// Returns:
+//   - The UUID of the service.
+//   - ErrNotUUID if the datagram is not a UUID, or the error of Verify if a secret is set.
+func (l *Listener) parse(datagram []byte) (uuid.UUID, error) {
+	if len(l.secret) > 0 {
+		return Verify(l.secret, datagram, l.now(), l.window)
+	}
+
+	id, ok := Parse(datagram)
+	if !ok {
+		return uuid.Nil, ErrNotUUID
+	}
+
+	return id, nil
+}
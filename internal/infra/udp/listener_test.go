@@ -0,0 +1,212 @@
+package udp_test
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/bavix/vakeel-way/internal/infra/udp"
+)
+
+// ListenerTestSuite represents the test suite for the UDP heartbeats.
+type ListenerTestSuite struct {
+	suite.Suite
+}
+
+// TestParse verifies that the binary and the text UUIDs are accepted.
+func (suite *ListenerTestSuite) TestParse() {
+	id := uuid.MustParse("224f8a59-6705-4f3e-b7de-177757932aad")
+
+	for _, datagram := range [][]byte{
+		id[:],
+		[]byte(id.String()),
+		[]byte(id.String() + "\n"),
+		[]byte("224f8a5967054f3eb7de177757932aad"),
+	} {
+		parsed, ok := udp.Parse(datagram)
+		suite.Require().True(ok, string(datagram))
+		suite.Equal(id, parsed)
+	}
+
+	for _, datagram := range [][]byte{nil, []byte("ping"), []byte(id.String() + id.String())} {
+		_, ok := udp.Parse(datagram)
+		suite.False(ok, string(datagram))
+	}
+}
+
+// TestListener_Run verifies that the heartbeats of the allowed senders are handled until the context is canceled.
+func (suite *ListenerTestSuite) TestListener_Run() {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+
+	ids := make(chan uuid.UUID, 4)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() {
+		done <- udp.NewListener(conn).Run(ctx, func(id uuid.UUID) { ids <- id })
+	}()
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	suite.Require().NoError(err)
+
+	defer client.Close()
+
+	id := uuid.New()
+
+	_, err = client.Write([]byte("garbage"))
+	suite.Require().NoError(err)
+
+	_, err = client.Write(id[:])
+	suite.Require().NoError(err)
+
+	select {
+	case received := <-ids:
+		suite.Equal(id, received)
+	case <-time.After(5 * time.Second):
+		suite.Fail("the heartbeat is not handled")
+	}
+
+	cancel()
+
+	select {
+	case err := <-done:
+		suite.Require().NoError(err)
+	case <-time.After(5 * time.Second):
+		suite.Fail("the listener is not stopped")
+	}
+
+	suite.Empty(ids)
+}
+
+// TestListener_Allow verifies that the datagrams of the senders that are not allowed are dropped.
+func (suite *ListenerTestSuite) TestListener_Allow() {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+
+	ids := make(chan uuid.UUID, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	defer cancel()
+
+	listener := udp.NewListener(conn, udp.WithAllow(func(addr netip.Addr) bool {
+		return !addr.IsLoopback()
+	}))
+
+	go func() {
+		_ = listener.Run(ctx, func(id uuid.UUID) { ids <- id })
+	}()
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	suite.Require().NoError(err)
+
+	defer client.Close()
+
+	id := uuid.New()
+
+	_, err = client.Write([]byte(id.String()))
+	suite.Require().NoError(err)
+
+	select {
+	case <-ids:
+		suite.Fail("the heartbeat of a denied sender is handled")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+// TestVerify verifies that only the datagrams signed with the secret within the window are accepted.
+func (suite *ListenerTestSuite) TestVerify() {
+	secret := []byte("secret")
+	id := uuid.New()
+	now := time.Now()
+
+	verified, err := udp.Verify(secret, udp.Sign(secret, id, now.Add(-30*time.Second)), now, time.Minute)
+	suite.Require().NoError(err)
+	suite.Equal(id, verified)
+
+	// The unsigned heartbeats are rejected.
+	for _, datagram := range [][]byte{id[:], []byte(id.String()), nil} {
+		_, err = udp.Verify(secret, datagram, now, time.Minute)
+		suite.Require().ErrorIs(err, udp.ErrUnsigned)
+	}
+
+	// The datagrams signed with another secret or tampered with are rejected.
+	_, err = udp.Verify(secret, udp.Sign([]byte("other"), id, now), now, time.Minute)
+	suite.Require().ErrorIs(err, udp.ErrBadSignature)
+
+	tampered := udp.Sign(secret, id, now)
+	tampered[0] ^= 1
+
+	_, err = udp.Verify(secret, tampered, now, time.Minute)
+	suite.Require().ErrorIs(err, udp.ErrBadSignature)
+
+	// The stale and the future datagrams are rejected, so they cannot be replayed.
+	for _, at := range []time.Time{now.Add(-2 * time.Minute), now.Add(2 * time.Minute)} {
+		_, err = udp.Verify(secret, udp.Sign(secret, id, at), now, time.Minute)
+		suite.Require().ErrorIs(err, udp.ErrExpired)
+	}
+}
+
+// TestListener_Secret verifies that only the signed heartbeats are handled if a secret is set.
+func (suite *ListenerTestSuite) TestListener_Secret() {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	suite.Require().NoError(err)
+
+	ids := make(chan uuid.UUID, 8)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	defer cancel()
+
+	secret := []byte("secret")
+
+	go func() {
+		_ = udp.NewListener(conn, udp.WithSecret(secret, time.Minute)).Run(ctx, func(id uuid.UUID) { ids <- id })
+	}()
+
+	client, err := net.Dial("udp", conn.LocalAddr().String())
+	suite.Require().NoError(err)
+
+	defer client.Close()
+
+	spoofed := uuid.New()
+
+	// The unsigned, the badly signed and the stale heartbeats are dropped.
+	for _, datagram := range [][]byte{
+		spoofed[:],
+		[]byte(spoofed.String()),
+		udp.Sign([]byte("guess"), spoofed, time.Now()),
+		udp.Sign(secret, spoofed, time.Now().Add(-time.Hour)),
+	} {
+		_, err = client.Write(datagram)
+		suite.Require().NoError(err)
+	}
+
+	id := uuid.New()
+
+	_, err = client.Write(udp.Sign(secret, id, time.Now()))
+	suite.Require().NoError(err)
+
+	select {
+	case received := <-ids:
+		suite.Equal(id, received)
+	case <-time.After(5 * time.Second):
+		suite.Fail("the signed heartbeat is not handled")
+	}
+
+	select {
+	case received := <-ids:
+		suite.Fail("an unsigned heartbeat is handled", received.String())
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+// TestListenerTestSuite runs the test suite for the UDP heartbeats.
+func TestListenerTestSuite(t *testing.T) {
+	t.Parallel()
+	suite.Run(t, new(ListenerTestSuite))
+}
@@ -0,0 +1,95 @@
+package udp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// timestampSize is the size of the Unix time of a signed datagram.
+	timestampSize = 8
+
+	// signedSize is the size of a signed datagram: the UUID, the Unix time
+	// in seconds and the HMAC-SHA256 of both.
+	signedSize = len(uuid.UUID{}) + timestampSize + sha256.Size
+)
+
+var (
+	// ErrUnsigned is an error that indicates that the datagram is not a signed heartbeat.
+	ErrUnsigned = errors.New("udp: the datagram is not signed")
+
+	// ErrBadSignature is an error that indicates that the signature of the datagram does not match the secret.
+	ErrBadSignature = errors.New("udp: bad signature")
+
+	// ErrExpired is an error that indicates that the datagram was signed too long ago or in the future.
+	ErrExpired = errors.New("udp: the signature is expired")
+)
+
+// Sign returns the signed datagram of a heartbeat.
+//
+// The datagram is the 16 bytes of the UUID, the Unix time in seconds as a
+// big-endian 64-bit integer, and the HMAC-SHA256 of both keyed with the secret.
+//
+// Parameters:
+//   - secret: The secret shared with the server.
+//   - id: The UUID of the service.
+//   - at: The time the heartbeat is sent at.
+//
+// Returns:
+//   - The datagram.
+func Sign(secret []byte, id uuid.UUID, at time.Time) []byte {
+	datagram := make([]byte, 0, signedSize)
+	datagram = append(datagram, id[:]...)
+	datagram = binary.BigEndian.AppendUint64(datagram, uint64(at.Unix())) //nolint:gosec
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(datagram)
+
+	return mac.Sum(datagram)
+}
+
+// Verify returns the UUID of the service a signed datagram reports the heartbeat of.
+//
+// Parameters:
+//   - secret: The secret shared with the reporters.
+//   - datagram: The payload of the datagram.
+//   - now: The current time.
+//   - window: The greatest difference allowed between the time of the
+//     signature and the current time, which bounds the replays of a datagram.
+//
+// Returns:
+//   - The UUID of the service.
+//   - ErrUnsigned if the datagram is not a signed heartbeat.
+//   - ErrBadSignature if the signature does not match the secret.
+//   - ErrExpired if the datagram was signed outside of the window.
+func Verify(secret, datagram []byte, now time.Time, window time.Duration) (uuid.UUID, error) {
+	if len(datagram) != signedSize {
+		return uuid.Nil, ErrUnsigned
+	}
+
+	payload, signature := datagram[:signedSize-sha256.Size], datagram[signedSize-sha256.Size:]
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return uuid.Nil, ErrBadSignature
+	}
+
+	at := time.Unix(int64(binary.BigEndian.Uint64(payload[len(uuid.UUID{}):])), 0) //nolint:gosec
+	if diff := now.Sub(at); diff > window || diff < -window {
+		return uuid.Nil, ErrExpired
+	}
+
+	id, err := uuid.FromBytes(payload[:len(uuid.UUID{})])
+	if err != nil {
+		return uuid.Nil, ErrUnsigned
+	}
+
+	return id, nil
+}
@@ -0,0 +1,749 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.27.1
+// source: api/vakeel_way/admin.proto
+
+package vakeel_way
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AdminService_Promote_FullMethodName                   = "/vakeel_way.AdminService/Promote"
+	AdminService_CreateSilence_FullMethodName             = "/vakeel_way.AdminService/CreateSilence"
+	AdminService_ListSilences_FullMethodName              = "/vakeel_way.AdminService/ListSilences"
+	AdminService_DeleteSilence_FullMethodName             = "/vakeel_way.AdminService/DeleteSilence"
+	AdminService_ListStaleWebhooks_FullMethodName         = "/vakeel_way.AdminService/ListStaleWebhooks"
+	AdminService_ListCapturedNotifications_FullMethodName = "/vakeel_way.AdminService/ListCapturedNotifications"
+	AdminService_ListHeartbeats_FullMethodName            = "/vakeel_way.AdminService/ListHeartbeats"
+	AdminService_SetStatus_FullMethodName                 = "/vakeel_way.AdminService/SetStatus"
+	AdminService_Reload_FullMethodName                    = "/vakeel_way.AdminService/Reload"
+	AdminService_ListDeadLetters_FullMethodName           = "/vakeel_way.AdminService/ListDeadLetters"
+	AdminService_ReplayDeadLetter_FullMethodName          = "/vakeel_way.AdminService/ReplayDeadLetter"
+	AdminService_DiscardDeadLetter_FullMethodName         = "/vakeel_way.AdminService/DiscardDeadLetter"
+)
+
+// AdminServiceClient is the client API for AdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AdminService is a gRPC service that allows operators to manage a running
+// vakeel-way instance.
+//
+// The service is intended for administrative tooling such as the
+// `vakeel-way promote` command. It is served on the same listener as the
+// StateService.
+type AdminServiceClient interface {
+	// Promote switches a standby replica into active mode.
+	//
+	// An active replica dispatches notifications to the configured webhooks,
+	// while a standby replica only tracks heartbeats. Promoting an instance
+	// that is already active is a no-op.
+	//
+	// Parameters:
+	// - The input is a PromoteRequest message with the reason of the promotion.
+	//
+	// Returns:
+	// - The output is a PromoteResponse message with the previous and the
+	//   current mode of the replica.
+	Promote(ctx context.Context, in *PromoteRequest, opts ...grpc.CallOption) (*PromoteResponse, error)
+	// CreateSilence creates a maintenance window.
+	//
+	// During the window the status transitions of the matching services are
+	// recorded, but not notified. If a service is still down when the window
+	// ends, the targets are notified then.
+	//
+	// Parameters:
+	// - The input is a Silence message. The id field is ignored.
+	//
+	// Returns:
+	// - The output is the created Silence message with the assigned id.
+	CreateSilence(ctx context.Context, in *Silence, opts ...grpc.CallOption) (*Silence, error)
+	// ListSilences returns the silences that have not expired yet.
+	//
+	// Returns:
+	// - The output is a ListSilencesResponse message with the silences.
+	ListSilences(ctx context.Context, in *ListSilencesRequest, opts ...grpc.CallOption) (*ListSilencesResponse, error)
+	// DeleteSilence removes a silence before it ends.
+	//
+	// Parameters:
+	// - The input is a DeleteSilenceRequest message with the id of the silence.
+	DeleteSilence(ctx context.Context, in *DeleteSilenceRequest, opts ...grpc.CallOption) (*DeleteSilenceResponse, error)
+	// ListStaleWebhooks returns the webhooks that have not received a heartbeat
+	// for the configured time, oldest first.
+	//
+	// The list includes the archived webhooks, which are no longer tracked by
+	// the running instance but are still present in the configuration.
+	//
+	// Returns:
+	// - The output is a ListStaleWebhooksResponse message with the stale webhooks.
+	ListStaleWebhooks(ctx context.Context, in *ListStaleWebhooksRequest, opts ...grpc.CallOption) (*ListStaleWebhooksResponse, error)
+	// ListCapturedNotifications returns the latest notifications kept by the
+	// targets of the "capture" type, oldest first.
+	//
+	// Returns:
+	// - The output is a ListCapturedNotificationsResponse message with the notifications.
+	ListCapturedNotifications(ctx context.Context, in *ListCapturedNotificationsRequest, opts ...grpc.CallOption) (*ListCapturedNotificationsResponse, error)
+	// ListHeartbeats returns the latest heartbeats recorded by the audit log,
+	// oldest first.
+	//
+	// It answers whether an agent actually reported a service, and from which
+	// address. A FailedPrecondition error is returned if the audit log is disabled.
+	//
+	// Parameters:
+	// - The input is a ListHeartbeatsRequest message with the optional UUID of
+	//   the service and the maximum number of the heartbeats.
+	//
+	// Returns:
+	// - The output is a ListHeartbeatsResponse message with the heartbeats.
+	ListHeartbeats(ctx context.Context, in *ListHeartbeatsRequest, opts ...grpc.CallOption) (*ListHeartbeatsResponse, error)
+	// SetStatus forces the status of a service.
+	//
+	// The transition is handled like one caused by the heartbeats: the targets
+	// are notified and the history is recorded. A forced up status expires like
+	// a heartbeat unless the service keeps reporting.
+	//
+	// Parameters:
+	// - The input is a SetStatusRequest message with the UUID of the service
+	//   and the status, "up" or "down".
+	//
+	// Returns:
+	// - The output is a SetStatusResponse message with the previous and the
+	//   current status of the service.
+	SetStatus(ctx context.Context, in *SetStatusRequest, opts ...grpc.CallOption) (*SetStatusResponse, error)
+	// Reload reloads the webhooks from the configuration file.
+	//
+	// The new webhooks are validated first; the running ones are kept if the
+	// configuration is invalid. The other sections of the configuration take
+	// effect on restart only.
+	//
+	// Returns:
+	// - The output is a ReloadResponse message with the number of the webhooks.
+	Reload(ctx context.Context, in *ReloadRequest, opts ...grpc.CallOption) (*ReloadResponse, error)
+	// ListDeadLetters returns the notifications that could not be delivered,
+	// oldest first.
+	//
+	// A notification becomes a dead letter once its retries are exhausted, or
+	// once it is discarded from the full queue of an unavailable target.
+	//
+	// Returns:
+	// - The output is a ListDeadLettersResponse message with the dead letters.
+	ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (*ListDeadLettersResponse, error)
+	// ReplayDeadLetter delivers a dead letter to its target again.
+	//
+	// The dead letter is removed once the target accepts it. A NotFound error
+	// is returned if there is no such dead letter, a FailedPrecondition error
+	// if its target was removed, and an Unavailable error if the target still
+	// fails.
+	//
+	// Parameters:
+	// - The input is a ReplayDeadLetterRequest message with the id of the dead letter.
+	ReplayDeadLetter(ctx context.Context, in *ReplayDeadLetterRequest, opts ...grpc.CallOption) (*ReplayDeadLetterResponse, error)
+	// DiscardDeadLetter removes a dead letter without delivering it.
+	//
+	// Parameters:
+	// - The input is a DiscardDeadLetterRequest message with the id of the dead letter.
+	DiscardDeadLetter(ctx context.Context, in *DiscardDeadLetterRequest, opts ...grpc.CallOption) (*DiscardDeadLetterResponse, error)
+}
+
+type adminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminServiceClient(cc grpc.ClientConnInterface) AdminServiceClient {
+	return &adminServiceClient{cc}
+}
+
+func (c *adminServiceClient) Promote(ctx context.Context, in *PromoteRequest, opts ...grpc.CallOption) (*PromoteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PromoteResponse)
+	err := c.cc.Invoke(ctx, AdminService_Promote_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) CreateSilence(ctx context.Context, in *Silence, opts ...grpc.CallOption) (*Silence, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Silence)
+	err := c.cc.Invoke(ctx, AdminService_CreateSilence_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListSilences(ctx context.Context, in *ListSilencesRequest, opts ...grpc.CallOption) (*ListSilencesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSilencesResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListSilences_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DeleteSilence(ctx context.Context, in *DeleteSilenceRequest, opts ...grpc.CallOption) (*DeleteSilenceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteSilenceResponse)
+	err := c.cc.Invoke(ctx, AdminService_DeleteSilence_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListStaleWebhooks(ctx context.Context, in *ListStaleWebhooksRequest, opts ...grpc.CallOption) (*ListStaleWebhooksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListStaleWebhooksResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListStaleWebhooks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListCapturedNotifications(ctx context.Context, in *ListCapturedNotificationsRequest, opts ...grpc.CallOption) (*ListCapturedNotificationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListCapturedNotificationsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListCapturedNotifications_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListHeartbeats(ctx context.Context, in *ListHeartbeatsRequest, opts ...grpc.CallOption) (*ListHeartbeatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListHeartbeatsResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListHeartbeats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SetStatus(ctx context.Context, in *SetStatusRequest, opts ...grpc.CallOption) (*SetStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetStatusResponse)
+	err := c.cc.Invoke(ctx, AdminService_SetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) Reload(ctx context.Context, in *ReloadRequest, opts ...grpc.CallOption) (*ReloadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReloadResponse)
+	err := c.cc.Invoke(ctx, AdminService_Reload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ListDeadLetters(ctx context.Context, in *ListDeadLettersRequest, opts ...grpc.CallOption) (*ListDeadLettersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDeadLettersResponse)
+	err := c.cc.Invoke(ctx, AdminService_ListDeadLetters_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ReplayDeadLetter(ctx context.Context, in *ReplayDeadLetterRequest, opts ...grpc.CallOption) (*ReplayDeadLetterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplayDeadLetterResponse)
+	err := c.cc.Invoke(ctx, AdminService_ReplayDeadLetter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DiscardDeadLetter(ctx context.Context, in *DiscardDeadLetterRequest, opts ...grpc.CallOption) (*DiscardDeadLetterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiscardDeadLetterResponse)
+	err := c.cc.Invoke(ctx, AdminService_DiscardDeadLetter_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServiceServer is the server API for AdminService service.
+// All implementations must embed UnimplementedAdminServiceServer
+// for forward compatibility.
+//
+// AdminService is a gRPC service that allows operators to manage a running
+// vakeel-way instance.
+//
+// The service is intended for administrative tooling such as the
+// `vakeel-way promote` command. It is served on the same listener as the
+// StateService.
+type AdminServiceServer interface {
+	// Promote switches a standby replica into active mode.
+	//
+	// An active replica dispatches notifications to the configured webhooks,
+	// while a standby replica only tracks heartbeats. Promoting an instance
+	// that is already active is a no-op.
+	//
+	// Parameters:
+	// - The input is a PromoteRequest message with the reason of the promotion.
+	//
+	// Returns:
+	// - The output is a PromoteResponse message with the previous and the
+	//   current mode of the replica.
+	Promote(context.Context, *PromoteRequest) (*PromoteResponse, error)
+	// CreateSilence creates a maintenance window.
+	//
+	// During the window the status transitions of the matching services are
+	// recorded, but not notified. If a service is still down when the window
+	// ends, the targets are notified then.
+	//
+	// Parameters:
+	// - The input is a Silence message. The id field is ignored.
+	//
+	// Returns:
+	// - The output is the created Silence message with the assigned id.
+	CreateSilence(context.Context, *Silence) (*Silence, error)
+	// ListSilences returns the silences that have not expired yet.
+	//
+	// Returns:
+	// - The output is a ListSilencesResponse message with the silences.
+	ListSilences(context.Context, *ListSilencesRequest) (*ListSilencesResponse, error)
+	// DeleteSilence removes a silence before it ends.
+	//
+	// Parameters:
+	// - The input is a DeleteSilenceRequest message with the id of the silence.
+	DeleteSilence(context.Context, *DeleteSilenceRequest) (*DeleteSilenceResponse, error)
+	// ListStaleWebhooks returns the webhooks that have not received a heartbeat
+	// for the configured time, oldest first.
+	//
+	// The list includes the archived webhooks, which are no longer tracked by
+	// the running instance but are still present in the configuration.
+	//
+	// Returns:
+	// - The output is a ListStaleWebhooksResponse message with the stale webhooks.
+	ListStaleWebhooks(context.Context, *ListStaleWebhooksRequest) (*ListStaleWebhooksResponse, error)
+	// ListCapturedNotifications returns the latest notifications kept by the
+	// targets of the "capture" type, oldest first.
+	//
+	// Returns:
+	// - The output is a ListCapturedNotificationsResponse message with the notifications.
+	ListCapturedNotifications(context.Context, *ListCapturedNotificationsRequest) (*ListCapturedNotificationsResponse, error)
+	// ListHeartbeats returns the latest heartbeats recorded by the audit log,
+	// oldest first.
+	//
+	// It answers whether an agent actually reported a service, and from which
+	// address. A FailedPrecondition error is returned if the audit log is disabled.
+	//
+	// Parameters:
+	// - The input is a ListHeartbeatsRequest message with the optional UUID of
+	//   the service and the maximum number of the heartbeats.
+	//
+	// Returns:
+	// - The output is a ListHeartbeatsResponse message with the heartbeats.
+	ListHeartbeats(context.Context, *ListHeartbeatsRequest) (*ListHeartbeatsResponse, error)
+	// SetStatus forces the status of a service.
+	//
+	// The transition is handled like one caused by the heartbeats: the targets
+	// are notified and the history is recorded. A forced up status expires like
+	// a heartbeat unless the service keeps reporting.
+	//
+	// Parameters:
+	// - The input is a SetStatusRequest message with the UUID of the service
+	//   and the status, "up" or "down".
+	//
+	// Returns:
+	// - The output is a SetStatusResponse message with the previous and the
+	//   current status of the service.
+	SetStatus(context.Context, *SetStatusRequest) (*SetStatusResponse, error)
+	// Reload reloads the webhooks from the configuration file.
+	//
+	// The new webhooks are validated first; the running ones are kept if the
+	// configuration is invalid. The other sections of the configuration take
+	// effect on restart only.
+	//
+	// Returns:
+	// - The output is a ReloadResponse message with the number of the webhooks.
+	Reload(context.Context, *ReloadRequest) (*ReloadResponse, error)
+	// ListDeadLetters returns the notifications that could not be delivered,
+	// oldest first.
+	//
+	// A notification becomes a dead letter once its retries are exhausted, or
+	// once it is discarded from the full queue of an unavailable target.
+	//
+	// Returns:
+	// - The output is a ListDeadLettersResponse message with the dead letters.
+	ListDeadLetters(context.Context, *ListDeadLettersRequest) (*ListDeadLettersResponse, error)
+	// ReplayDeadLetter delivers a dead letter to its target again.
+	//
+	// The dead letter is removed once the target accepts it. A NotFound error
+	// is returned if there is no such dead letter, a FailedPrecondition error
+	// if its target was removed, and an Unavailable error if the target still
+	// fails.
+	//
+	// Parameters:
+	// - The input is a ReplayDeadLetterRequest message with the id of the dead letter.
+	ReplayDeadLetter(context.Context, *ReplayDeadLetterRequest) (*ReplayDeadLetterResponse, error)
+	// DiscardDeadLetter removes a dead letter without delivering it.
+	//
+	// Parameters:
+	// - The input is a DiscardDeadLetterRequest message with the id of the dead letter.
+	DiscardDeadLetter(context.Context, *DiscardDeadLetterRequest) (*DiscardDeadLetterResponse, error)
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+// UnimplementedAdminServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServiceServer struct{}
+
+func (UnimplementedAdminServiceServer) Promote(context.Context, *PromoteRequest) (*PromoteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Promote not implemented")
+}
+func (UnimplementedAdminServiceServer) CreateSilence(context.Context, *Silence) (*Silence, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSilence not implemented")
+}
+func (UnimplementedAdminServiceServer) ListSilences(context.Context, *ListSilencesRequest) (*ListSilencesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSilences not implemented")
+}
+func (UnimplementedAdminServiceServer) DeleteSilence(context.Context, *DeleteSilenceRequest) (*DeleteSilenceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSilence not implemented")
+}
+func (UnimplementedAdminServiceServer) ListStaleWebhooks(context.Context, *ListStaleWebhooksRequest) (*ListStaleWebhooksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListStaleWebhooks not implemented")
+}
+func (UnimplementedAdminServiceServer) ListCapturedNotifications(context.Context, *ListCapturedNotificationsRequest) (*ListCapturedNotificationsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCapturedNotifications not implemented")
+}
+func (UnimplementedAdminServiceServer) ListHeartbeats(context.Context, *ListHeartbeatsRequest) (*ListHeartbeatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListHeartbeats not implemented")
+}
+func (UnimplementedAdminServiceServer) SetStatus(context.Context, *SetStatusRequest) (*SetStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetStatus not implemented")
+}
+func (UnimplementedAdminServiceServer) Reload(context.Context, *ReloadRequest) (*ReloadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Reload not implemented")
+}
+func (UnimplementedAdminServiceServer) ListDeadLetters(context.Context, *ListDeadLettersRequest) (*ListDeadLettersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeadLetters not implemented")
+}
+func (UnimplementedAdminServiceServer) ReplayDeadLetter(context.Context, *ReplayDeadLetterRequest) (*ReplayDeadLetterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayDeadLetter not implemented")
+}
+func (UnimplementedAdminServiceServer) DiscardDeadLetter(context.Context, *DiscardDeadLetterRequest) (*DiscardDeadLetterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiscardDeadLetter not implemented")
+}
+func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
+func (UnimplementedAdminServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServiceServer will
+// result in compilation errors.
+type UnsafeAdminServiceServer interface {
+	mustEmbedUnimplementedAdminServiceServer()
+}
+
+func RegisterAdminServiceServer(s grpc.ServiceRegistrar, srv AdminServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdminService_ServiceDesc, srv)
+}
+
+func _AdminService_Promote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PromoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).Promote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_Promote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).Promote(ctx, req.(*PromoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_CreateSilence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Silence)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).CreateSilence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_CreateSilence_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).CreateSilence(ctx, req.(*Silence))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListSilences_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSilencesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListSilences(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListSilences_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListSilences(ctx, req.(*ListSilencesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DeleteSilence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSilenceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DeleteSilence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DeleteSilence_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DeleteSilence(ctx, req.(*DeleteSilenceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListStaleWebhooks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListStaleWebhooksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListStaleWebhooks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListStaleWebhooks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListStaleWebhooks(ctx, req.(*ListStaleWebhooksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListCapturedNotifications_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCapturedNotificationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListCapturedNotifications(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListCapturedNotifications_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListCapturedNotifications(ctx, req.(*ListCapturedNotificationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListHeartbeats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListHeartbeatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListHeartbeats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListHeartbeats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListHeartbeats(ctx, req.(*ListHeartbeatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SetStatus(ctx, req.(*SetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_Reload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReloadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).Reload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_Reload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).Reload(ctx, req.(*ReloadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ListDeadLetters_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeadLettersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ListDeadLetters(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ListDeadLetters_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ListDeadLetters(ctx, req.(*ListDeadLettersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ReplayDeadLetter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayDeadLetterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ReplayDeadLetter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ReplayDeadLetter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ReplayDeadLetter(ctx, req.(*ReplayDeadLetterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DiscardDeadLetter_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiscardDeadLetterRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DiscardDeadLetter(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DiscardDeadLetter_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DiscardDeadLetter(ctx, req.(*DiscardDeadLetterRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vakeel_way.AdminService",
+	HandlerType: (*AdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Promote",
+			Handler:    _AdminService_Promote_Handler,
+		},
+		{
+			MethodName: "CreateSilence",
+			Handler:    _AdminService_CreateSilence_Handler,
+		},
+		{
+			MethodName: "ListSilences",
+			Handler:    _AdminService_ListSilences_Handler,
+		},
+		{
+			MethodName: "DeleteSilence",
+			Handler:    _AdminService_DeleteSilence_Handler,
+		},
+		{
+			MethodName: "ListStaleWebhooks",
+			Handler:    _AdminService_ListStaleWebhooks_Handler,
+		},
+		{
+			MethodName: "ListCapturedNotifications",
+			Handler:    _AdminService_ListCapturedNotifications_Handler,
+		},
+		{
+			MethodName: "ListHeartbeats",
+			Handler:    _AdminService_ListHeartbeats_Handler,
+		},
+		{
+			MethodName: "SetStatus",
+			Handler:    _AdminService_SetStatus_Handler,
+		},
+		{
+			MethodName: "Reload",
+			Handler:    _AdminService_Reload_Handler,
+		},
+		{
+			MethodName: "ListDeadLetters",
+			Handler:    _AdminService_ListDeadLetters_Handler,
+		},
+		{
+			MethodName: "ReplayDeadLetter",
+			Handler:    _AdminService_ReplayDeadLetter_Handler,
+		},
+		{
+			MethodName: "DiscardDeadLetter",
+			Handler:    _AdminService_DiscardDeadLetter_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/vakeel_way/admin.proto",
+}
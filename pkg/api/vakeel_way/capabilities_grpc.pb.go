@@ -0,0 +1,143 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.27.1
+// source: api/vakeel_way/capabilities.proto
+
+package vakeel_way
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CapabilityService_GetCapabilities_FullMethodName = "/vakeel_way.CapabilityService/GetCapabilities"
+)
+
+// CapabilityServiceClient is the client API for CapabilityService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CapabilityService is a gRPC service that describes the features enabled on
+// a running vakeel-way instance.
+//
+// Agents, SDKs, and dashboards use it to adapt their behavior dynamically,
+// e.g. to check whether the instance requires authentication before
+// streaming heartbeats.
+type CapabilityServiceClient interface {
+	// GetCapabilities returns the features enabled on the instance.
+	//
+	// Returns:
+	// - The output is a Capabilities message.
+	GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*Capabilities, error)
+}
+
+type capabilityServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCapabilityServiceClient(cc grpc.ClientConnInterface) CapabilityServiceClient {
+	return &capabilityServiceClient{cc}
+}
+
+func (c *capabilityServiceClient) GetCapabilities(ctx context.Context, in *GetCapabilitiesRequest, opts ...grpc.CallOption) (*Capabilities, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Capabilities)
+	err := c.cc.Invoke(ctx, CapabilityService_GetCapabilities_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CapabilityServiceServer is the server API for CapabilityService service.
+// All implementations must embed UnimplementedCapabilityServiceServer
+// for forward compatibility.
+//
+// CapabilityService is a gRPC service that describes the features enabled on
+// a running vakeel-way instance.
+//
+// Agents, SDKs, and dashboards use it to adapt their behavior dynamically,
+// e.g. to check whether the instance requires authentication before
+// streaming heartbeats.
+type CapabilityServiceServer interface {
+	// GetCapabilities returns the features enabled on the instance.
+	//
+	// Returns:
+	// - The output is a Capabilities message.
+	GetCapabilities(context.Context, *GetCapabilitiesRequest) (*Capabilities, error)
+	mustEmbedUnimplementedCapabilityServiceServer()
+}
+
+// UnimplementedCapabilityServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCapabilityServiceServer struct{}
+
+func (UnimplementedCapabilityServiceServer) GetCapabilities(context.Context, *GetCapabilitiesRequest) (*Capabilities, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCapabilities not implemented")
+}
+func (UnimplementedCapabilityServiceServer) mustEmbedUnimplementedCapabilityServiceServer() {}
+func (UnimplementedCapabilityServiceServer) testEmbeddedByValue()                           {}
+
+// UnsafeCapabilityServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CapabilityServiceServer will
+// result in compilation errors.
+type UnsafeCapabilityServiceServer interface {
+	mustEmbedUnimplementedCapabilityServiceServer()
+}
+
+func RegisterCapabilityServiceServer(s grpc.ServiceRegistrar, srv CapabilityServiceServer) {
+	// If the following call pancis, it indicates UnimplementedCapabilityServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CapabilityService_ServiceDesc, srv)
+}
+
+func _CapabilityService_GetCapabilities_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCapabilitiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CapabilityServiceServer).GetCapabilities(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CapabilityService_GetCapabilities_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CapabilityServiceServer).GetCapabilities(ctx, req.(*GetCapabilitiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CapabilityService_ServiceDesc is the grpc.ServiceDesc for CapabilityService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CapabilityService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "vakeel_way.CapabilityService",
+	HandlerType: (*CapabilityServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCapabilities",
+			Handler:    _CapabilityService_GetCapabilities_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "api/vakeel_way/capabilities.proto",
+}
@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
 // versions:
-// - protoc-gen-go-grpc v1.4.0
+// - protoc-gen-go-grpc v1.5.1
 // - protoc             v5.27.1
 // source: api/vakeel_way/state.proto
 
@@ -15,11 +15,17 @@ import (
 
 // This is a compile-time assertion to ensure that this generated file
 // is compatible with the grpc package it is being compiled against.
-// Requires gRPC-Go v1.62.0 or later.
-const _ = grpc.SupportPackageIsVersion8
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
 
 const (
-	StateService_Update_FullMethodName = "/vakeel_way.StateService/Update"
+	StateService_Update_FullMethodName        = "/vakeel_way.StateService/Update"
+	StateService_UpdateStream_FullMethodName  = "/vakeel_way.StateService/UpdateStream"
+	StateService_GetStatus_FullMethodName     = "/vakeel_way.StateService/GetStatus"
+	StateService_ListServices_FullMethodName  = "/vakeel_way.StateService/ListServices"
+	StateService_WatchStatus_FullMethodName   = "/vakeel_way.StateService/WatchStatus"
+	StateService_GetUptime_FullMethodName     = "/vakeel_way.StateService/GetUptime"
+	StateService_ListIncidents_FullMethodName = "/vakeel_way.StateService/ListIncidents"
 )
 
 // StateServiceClient is the client API for StateService service.
@@ -36,27 +42,109 @@ const (
 //
 // The service is used to test that the gRPC service is working correctly. This
 // is done by sending a stream of UpdateRequest messages and receiving a single
-// UpdateResponse message in return. The UpdateResponse message is an empty
-// message that indicates that the update operation was successful.
+// UpdateResponse message in return, or an UpdateResponse message for each of
+// them on the UpdateStream method.
 //
 // It is also used to mark services as working for some time. If services stop
 // sending information about themselves, then they do not work and it is
 // necessary to notify monitoring and create an incident.
 type StateServiceClient interface {
-	// Updates the list of UUIDs.
+	// Update is a RPC method that allows clients to update a list of UUIDs.
 	//
-	// Takes an array of IDs as input and marks these services as working for
-	// some time. If services stop sending information about themselves, then they
-	// do not work and it is necessary to notify monitoring and create an
-	// incident.
+	// The method takes a stream of UpdateRequest messages as input. Each
+	// UpdateRequest message contains a list of UUIDs that need to be updated.
 	//
-	// The input is a stream of UpdateRequest messages. Each UpdateRequest
-	// message contains a list of UUIDs that need to be updated.
+	// The method returns a single UpdateResponse message once the client closes
+	// the stream. The UpdateResponse message is empty and indicates that the
+	// update operation was successful. The clients that need the result of
+	// every request, the configuration of the agents or the notices of the
+	// server use the UpdateStream method instead.
 	//
-	// The output is a single UpdateResponse message. The UpdateResponse message
-	// is an empty message that indicates that the update operation was
-	// successful.
-	Update(ctx context.Context, opts ...grpc.CallOption) (StateService_UpdateClient, error)
+	// Parameters:
+	// - The input is a stream of UpdateRequest messages. Each UpdateRequest
+	//   message contains a list of UUIDs that need to be updated.
+	//
+	// Returns:
+	// - The output is a single empty UpdateResponse message.
+	Update(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UpdateRequest, UpdateResponse], error)
+	// UpdateStream is a RPC method that allows clients to update a list of UUIDs
+	// and to receive a response to every request.
+	//
+	// The method takes a stream of UpdateRequest messages as input. Each
+	// UpdateRequest message contains a list of UUIDs that need to be updated.
+	//
+	// The method returns an UpdateResponse message for each UpdateRequest
+	// message. The UpdateResponse message carries the configuration of the
+	// agents of the services that the agent has not received yet on the stream.
+	// Since the protocol version "v2", the server also sends the notices on
+	// its own initiative, e.g. when it is overloaded or shutting down.
+	//
+	// Parameters:
+	// - The input is a stream of UpdateRequest messages. Each UpdateRequest
+	//   message contains a list of UUIDs that need to be updated.
+	//
+	// Returns:
+	// - The output is a stream of UpdateResponse messages, one per UpdateRequest
+	//   message.
+	UpdateStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[UpdateRequest, UpdateResponse], error)
+	// GetStatus is a RPC method that returns the current status of the services.
+	//
+	// Parameters:
+	// - The input is a GetStatusRequest message with the UUIDs of the services.
+	//
+	// Returns:
+	// - The output is a GetStatusResponse message with a ServiceStatus message
+	//   per requested UUID, in the order of the request.
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
+	// ListServices is a RPC method that returns the configured services.
+	//
+	// The services are ordered by their UUIDs and returned page by page.
+	//
+	// Parameters:
+	// - The input is a ListServicesRequest message with the page and the label selector.
+	//
+	// Returns:
+	// - The output is a ListServicesResponse message with the services of the
+	//   page and the token of the next page.
+	ListServices(ctx context.Context, in *ListServicesRequest, opts ...grpc.CallOption) (*ListServicesResponse, error)
+	// WatchStatus is a RPC method that streams the status transitions of the services.
+	//
+	// The stream is closed with the ResourceExhausted code if the client does
+	// not keep up with the transitions, so it can resubscribe and query the
+	// current status with GetStatus.
+	//
+	// Parameters:
+	// - The input is a WatchStatusRequest message with the selector of the services.
+	//
+	// Returns:
+	// - The output is a stream of StatusEvent messages, one per transition.
+	WatchStatus(ctx context.Context, in *WatchStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StatusEvent], error)
+	// GetUptime is a RPC method that returns the uptime of the services over
+	// the rolling windows of 24 hours, 7 days and 30 days.
+	//
+	// The uptime is computed from the status history, so it only covers the
+	// time the history is kept for.
+	//
+	// Parameters:
+	// - The input is a GetUptimeRequest message with the UUIDs of the services.
+	//
+	// Returns:
+	// - The output is a GetUptimeResponse message with a ServiceUptime message
+	//   per requested UUID, in the order of the request.
+	GetUptime(ctx context.Context, in *GetUptimeRequest, opts ...grpc.CallOption) (*GetUptimeResponse, error)
+	// ListIncidents is a RPC method that returns the recorded incidents: the
+	// intervals between the time a service went down and the time it recovered.
+	//
+	// The incidents are ordered from the most recent one and returned page by
+	// page. They are kept for the retention of the daily aggregates of the history.
+	//
+	// Parameters:
+	// - The input is a ListIncidentsRequest message with the filters and the page.
+	//
+	// Returns:
+	// - The output is a ListIncidentsResponse message with the incidents of the
+	//   page and the token of the next page.
+	ListIncidents(ctx context.Context, in *ListIncidentsRequest, opts ...grpc.CallOption) (*ListIncidentsResponse, error)
 }
 
 type stateServiceClient struct {
@@ -67,44 +155,94 @@ func NewStateServiceClient(cc grpc.ClientConnInterface) StateServiceClient {
 	return &stateServiceClient{cc}
 }
 
-func (c *stateServiceClient) Update(ctx context.Context, opts ...grpc.CallOption) (StateService_UpdateClient, error) {
+func (c *stateServiceClient) Update(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UpdateRequest, UpdateResponse], error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	stream, err := c.cc.NewStream(ctx, &StateService_ServiceDesc.Streams[0], StateService_Update_FullMethodName, cOpts...)
 	if err != nil {
 		return nil, err
 	}
-	x := &stateServiceUpdateClient{ClientStream: stream}
+	x := &grpc.GenericClientStream[UpdateRequest, UpdateResponse]{ClientStream: stream}
 	return x, nil
 }
 
-type StateService_UpdateClient interface {
-	Send(*UpdateRequest) error
-	CloseAndRecv() (*UpdateResponse, error)
-	grpc.ClientStream
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StateService_UpdateClient = grpc.ClientStreamingClient[UpdateRequest, UpdateResponse]
+
+func (c *stateServiceClient) UpdateStream(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[UpdateRequest, UpdateResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &StateService_ServiceDesc.Streams[1], StateService_UpdateStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UpdateRequest, UpdateResponse]{ClientStream: stream}
+	return x, nil
 }
 
-type stateServiceUpdateClient struct {
-	grpc.ClientStream
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StateService_UpdateStreamClient = grpc.BidiStreamingClient[UpdateRequest, UpdateResponse]
+
+func (c *stateServiceClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStatusResponse)
+	err := c.cc.Invoke(ctx, StateService_GetStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (x *stateServiceUpdateClient) Send(m *UpdateRequest) error {
-	return x.ClientStream.SendMsg(m)
+func (c *stateServiceClient) ListServices(ctx context.Context, in *ListServicesRequest, opts ...grpc.CallOption) (*ListServicesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListServicesResponse)
+	err := c.cc.Invoke(ctx, StateService_ListServices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
 }
 
-func (x *stateServiceUpdateClient) CloseAndRecv() (*UpdateResponse, error) {
+func (c *stateServiceClient) WatchStatus(ctx context.Context, in *WatchStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[StatusEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &StateService_ServiceDesc.Streams[2], StateService_WatchStatus_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchStatusRequest, StatusEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
 	if err := x.ClientStream.CloseSend(); err != nil {
 		return nil, err
 	}
-	m := new(UpdateResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StateService_WatchStatusClient = grpc.ServerStreamingClient[StatusEvent]
+
+func (c *stateServiceClient) GetUptime(ctx context.Context, in *GetUptimeRequest, opts ...grpc.CallOption) (*GetUptimeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUptimeResponse)
+	err := c.cc.Invoke(ctx, StateService_GetUptime_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *stateServiceClient) ListIncidents(ctx context.Context, in *ListIncidentsRequest, opts ...grpc.CallOption) (*ListIncidentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListIncidentsResponse)
+	err := c.cc.Invoke(ctx, StateService_ListIncidents_FullMethodName, in, out, cOpts...)
+	if err != nil {
 		return nil, err
 	}
-	return m, nil
+	return out, nil
 }
 
 // StateServiceServer is the server API for StateService service.
 // All implementations must embed UnimplementedStateServiceServer
-// for forward compatibility
+// for forward compatibility.
 //
 // StateService is a gRPC service that allows clients to update a list of UUIDs.
 //
@@ -116,38 +254,142 @@ func (x *stateServiceUpdateClient) CloseAndRecv() (*UpdateResponse, error) {
 //
 // The service is used to test that the gRPC service is working correctly. This
 // is done by sending a stream of UpdateRequest messages and receiving a single
-// UpdateResponse message in return. The UpdateResponse message is an empty
-// message that indicates that the update operation was successful.
+// UpdateResponse message in return, or an UpdateResponse message for each of
+// them on the UpdateStream method.
 //
 // It is also used to mark services as working for some time. If services stop
 // sending information about themselves, then they do not work and it is
 // necessary to notify monitoring and create an incident.
 type StateServiceServer interface {
-	// Updates the list of UUIDs.
+	// Update is a RPC method that allows clients to update a list of UUIDs.
+	//
+	// The method takes a stream of UpdateRequest messages as input. Each
+	// UpdateRequest message contains a list of UUIDs that need to be updated.
+	//
+	// The method returns a single UpdateResponse message once the client closes
+	// the stream. The UpdateResponse message is empty and indicates that the
+	// update operation was successful. The clients that need the result of
+	// every request, the configuration of the agents or the notices of the
+	// server use the UpdateStream method instead.
+	//
+	// Parameters:
+	// - The input is a stream of UpdateRequest messages. Each UpdateRequest
+	//   message contains a list of UUIDs that need to be updated.
+	//
+	// Returns:
+	// - The output is a single empty UpdateResponse message.
+	Update(grpc.ClientStreamingServer[UpdateRequest, UpdateResponse]) error
+	// UpdateStream is a RPC method that allows clients to update a list of UUIDs
+	// and to receive a response to every request.
+	//
+	// The method takes a stream of UpdateRequest messages as input. Each
+	// UpdateRequest message contains a list of UUIDs that need to be updated.
+	//
+	// The method returns an UpdateResponse message for each UpdateRequest
+	// message. The UpdateResponse message carries the configuration of the
+	// agents of the services that the agent has not received yet on the stream.
+	// Since the protocol version "v2", the server also sends the notices on
+	// its own initiative, e.g. when it is overloaded or shutting down.
+	//
+	// Parameters:
+	// - The input is a stream of UpdateRequest messages. Each UpdateRequest
+	//   message contains a list of UUIDs that need to be updated.
+	//
+	// Returns:
+	// - The output is a stream of UpdateResponse messages, one per UpdateRequest
+	//   message.
+	UpdateStream(grpc.BidiStreamingServer[UpdateRequest, UpdateResponse]) error
+	// GetStatus is a RPC method that returns the current status of the services.
 	//
-	// Takes an array of IDs as input and marks these services as working for
-	// some time. If services stop sending information about themselves, then they
-	// do not work and it is necessary to notify monitoring and create an
-	// incident.
+	// Parameters:
+	// - The input is a GetStatusRequest message with the UUIDs of the services.
 	//
-	// The input is a stream of UpdateRequest messages. Each UpdateRequest
-	// message contains a list of UUIDs that need to be updated.
+	// Returns:
+	// - The output is a GetStatusResponse message with a ServiceStatus message
+	//   per requested UUID, in the order of the request.
+	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
+	// ListServices is a RPC method that returns the configured services.
 	//
-	// The output is a single UpdateResponse message. The UpdateResponse message
-	// is an empty message that indicates that the update operation was
-	// successful.
-	Update(StateService_UpdateServer) error
+	// The services are ordered by their UUIDs and returned page by page.
+	//
+	// Parameters:
+	// - The input is a ListServicesRequest message with the page and the label selector.
+	//
+	// Returns:
+	// - The output is a ListServicesResponse message with the services of the
+	//   page and the token of the next page.
+	ListServices(context.Context, *ListServicesRequest) (*ListServicesResponse, error)
+	// WatchStatus is a RPC method that streams the status transitions of the services.
+	//
+	// The stream is closed with the ResourceExhausted code if the client does
+	// not keep up with the transitions, so it can resubscribe and query the
+	// current status with GetStatus.
+	//
+	// Parameters:
+	// - The input is a WatchStatusRequest message with the selector of the services.
+	//
+	// Returns:
+	// - The output is a stream of StatusEvent messages, one per transition.
+	WatchStatus(*WatchStatusRequest, grpc.ServerStreamingServer[StatusEvent]) error
+	// GetUptime is a RPC method that returns the uptime of the services over
+	// the rolling windows of 24 hours, 7 days and 30 days.
+	//
+	// The uptime is computed from the status history, so it only covers the
+	// time the history is kept for.
+	//
+	// Parameters:
+	// - The input is a GetUptimeRequest message with the UUIDs of the services.
+	//
+	// Returns:
+	// - The output is a GetUptimeResponse message with a ServiceUptime message
+	//   per requested UUID, in the order of the request.
+	GetUptime(context.Context, *GetUptimeRequest) (*GetUptimeResponse, error)
+	// ListIncidents is a RPC method that returns the recorded incidents: the
+	// intervals between the time a service went down and the time it recovered.
+	//
+	// The incidents are ordered from the most recent one and returned page by
+	// page. They are kept for the retention of the daily aggregates of the history.
+	//
+	// Parameters:
+	// - The input is a ListIncidentsRequest message with the filters and the page.
+	//
+	// Returns:
+	// - The output is a ListIncidentsResponse message with the incidents of the
+	//   page and the token of the next page.
+	ListIncidents(context.Context, *ListIncidentsRequest) (*ListIncidentsResponse, error)
 	mustEmbedUnimplementedStateServiceServer()
 }
 
-// UnimplementedStateServiceServer must be embedded to have forward compatible implementations.
-type UnimplementedStateServiceServer struct {
-}
+// UnimplementedStateServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedStateServiceServer struct{}
 
-func (UnimplementedStateServiceServer) Update(StateService_UpdateServer) error {
+func (UnimplementedStateServiceServer) Update(grpc.ClientStreamingServer[UpdateRequest, UpdateResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method Update not implemented")
 }
+func (UnimplementedStateServiceServer) UpdateStream(grpc.BidiStreamingServer[UpdateRequest, UpdateResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UpdateStream not implemented")
+}
+func (UnimplementedStateServiceServer) GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatus not implemented")
+}
+func (UnimplementedStateServiceServer) ListServices(context.Context, *ListServicesRequest) (*ListServicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListServices not implemented")
+}
+func (UnimplementedStateServiceServer) WatchStatus(*WatchStatusRequest, grpc.ServerStreamingServer[StatusEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchStatus not implemented")
+}
+func (UnimplementedStateServiceServer) GetUptime(context.Context, *GetUptimeRequest) (*GetUptimeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUptime not implemented")
+}
+func (UnimplementedStateServiceServer) ListIncidents(context.Context, *ListIncidentsRequest) (*ListIncidentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListIncidents not implemented")
+}
 func (UnimplementedStateServiceServer) mustEmbedUnimplementedStateServiceServer() {}
+func (UnimplementedStateServiceServer) testEmbeddedByValue()                      {}
 
 // UnsafeStateServiceServer may be embedded to opt out of forward compatibility for this service.
 // Use of this interface is not recommended, as added methods to StateServiceServer will
@@ -157,33 +399,111 @@ type UnsafeStateServiceServer interface {
 }
 
 func RegisterStateServiceServer(s grpc.ServiceRegistrar, srv StateServiceServer) {
+	// If the following call pancis, it indicates UnimplementedStateServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
 	s.RegisterService(&StateService_ServiceDesc, srv)
 }
 
 func _StateService_Update_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(StateServiceServer).Update(&stateServiceUpdateServer{ServerStream: stream})
+	return srv.(StateServiceServer).Update(&grpc.GenericServerStream[UpdateRequest, UpdateResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StateService_UpdateServer = grpc.ClientStreamingServer[UpdateRequest, UpdateResponse]
+
+func _StateService_UpdateStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(StateServiceServer).UpdateStream(&grpc.GenericServerStream[UpdateRequest, UpdateResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StateService_UpdateStreamServer = grpc.BidiStreamingServer[UpdateRequest, UpdateResponse]
+
+func _StateService_GetStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateServiceServer).GetStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StateService_GetStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateServiceServer).GetStatus(ctx, req.(*GetStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type StateService_UpdateServer interface {
-	SendAndClose(*UpdateResponse) error
-	Recv() (*UpdateRequest, error)
-	grpc.ServerStream
+func _StateService_ListServices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListServicesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateServiceServer).ListServices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StateService_ListServices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateServiceServer).ListServices(ctx, req.(*ListServicesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type stateServiceUpdateServer struct {
-	grpc.ServerStream
+func _StateService_WatchStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StateServiceServer).WatchStatus(m, &grpc.GenericServerStream[WatchStatusRequest, StatusEvent]{ServerStream: stream})
 }
 
-func (x *stateServiceUpdateServer) SendAndClose(m *UpdateResponse) error {
-	return x.ServerStream.SendMsg(m)
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StateService_WatchStatusServer = grpc.ServerStreamingServer[StatusEvent]
+
+func _StateService_GetUptime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUptimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StateServiceServer).GetUptime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StateService_GetUptime_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateServiceServer).GetUptime(ctx, req.(*GetUptimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *stateServiceUpdateServer) Recv() (*UpdateRequest, error) {
-	m := new(UpdateRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
+func _StateService_ListIncidents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListIncidentsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(StateServiceServer).ListIncidents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StateService_ListIncidents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StateServiceServer).ListIncidents(ctx, req.(*ListIncidentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
 // StateService_ServiceDesc is the grpc.ServiceDesc for StateService service.
@@ -192,13 +512,41 @@ func (x *stateServiceUpdateServer) Recv() (*UpdateRequest, error) {
 var StateService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "vakeel_way.StateService",
 	HandlerType: (*StateServiceServer)(nil),
-	Methods:     []grpc.MethodDesc{},
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetStatus",
+			Handler:    _StateService_GetStatus_Handler,
+		},
+		{
+			MethodName: "ListServices",
+			Handler:    _StateService_ListServices_Handler,
+		},
+		{
+			MethodName: "GetUptime",
+			Handler:    _StateService_GetUptime_Handler,
+		},
+		{
+			MethodName: "ListIncidents",
+			Handler:    _StateService_ListIncidents_Handler,
+		},
+	},
 	Streams: []grpc.StreamDesc{
 		{
 			StreamName:    "Update",
 			Handler:       _StateService_Update_Handler,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "UpdateStream",
+			Handler:       _StateService_UpdateStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "WatchStatus",
+			Handler:       _StateService_WatchStatus_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "api/vakeel_way/state.proto",
 }
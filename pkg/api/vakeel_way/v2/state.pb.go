@@ -0,0 +1,333 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.1
+// 	protoc        v5.27.1
+// source: api/vakeel_way/v2/state.proto
+
+package vakeel_wayv2
+
+import (
+	v1 "github.com/bavix/apis/pkg/bavix/api/v1"
+	vakeel_way "github.com/bavix/vakeel-way/pkg/api/vakeel_way"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Status is the status of a service reported by its agent.
+type Heartbeat_Status int32
+
+const (
+	// The status is not set, the service is up.
+	Heartbeat_STATUS_UNSPECIFIED Heartbeat_Status = 0
+	// The service works properly.
+	Heartbeat_STATUS_UP Heartbeat_Status = 1
+	// The service is running, but does not work properly.
+	Heartbeat_STATUS_DEGRADED Heartbeat_Status = 2
+)
+
+// Enum value maps for Heartbeat_Status.
+var (
+	Heartbeat_Status_name = map[int32]string{
+		0: "STATUS_UNSPECIFIED",
+		1: "STATUS_UP",
+		2: "STATUS_DEGRADED",
+	}
+	Heartbeat_Status_value = map[string]int32{
+		"STATUS_UNSPECIFIED": 0,
+		"STATUS_UP":          1,
+		"STATUS_DEGRADED":    2,
+	}
+)
+
+func (x Heartbeat_Status) Enum() *Heartbeat_Status {
+	p := new(Heartbeat_Status)
+	*p = x
+	return p
+}
+
+func (x Heartbeat_Status) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Heartbeat_Status) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_vakeel_way_v2_state_proto_enumTypes[0].Descriptor()
+}
+
+func (Heartbeat_Status) Type() protoreflect.EnumType {
+	return &file_api_vakeel_way_v2_state_proto_enumTypes[0]
+}
+
+func (x Heartbeat_Status) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Heartbeat_Status.Descriptor instead.
+func (Heartbeat_Status) EnumDescriptor() ([]byte, []int) {
+	return file_api_vakeel_way_v2_state_proto_rawDescGZIP(), []int{1, 0}
+}
+
+// UpdateRequest is a message that represents a batch of heartbeats.
+type UpdateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The heartbeats of the services.
+	Heartbeats []*Heartbeat `protobuf:"bytes,1,rep,name=heartbeats,proto3" json:"heartbeats,omitempty"`
+	// The list of the delayed heartbeats buffered by a relay while the server
+	// was unreachable. They never mark a service as up by themselves.
+	Delayed []*vakeel_way.Heartbeat `protobuf:"bytes,2,rep,name=delayed,proto3" json:"delayed,omitempty"`
+	// The handshake of the agent, usually sent in the first request of the stream.
+	Handshake     *vakeel_way.Handshake `protobuf:"bytes,3,opt,name=handshake,proto3" json:"handshake,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateRequest) Reset() {
+	*x = UpdateRequest{}
+	mi := &file_api_vakeel_way_v2_state_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRequest) ProtoMessage() {}
+
+func (x *UpdateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_vakeel_way_v2_state_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRequest) Descriptor() ([]byte, []int) {
+	return file_api_vakeel_way_v2_state_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UpdateRequest) GetHeartbeats() []*Heartbeat {
+	if x != nil {
+		return x.Heartbeats
+	}
+	return nil
+}
+
+func (x *UpdateRequest) GetDelayed() []*vakeel_way.Heartbeat {
+	if x != nil {
+		return x.Delayed
+	}
+	return nil
+}
+
+func (x *UpdateRequest) GetHandshake() *vakeel_way.Handshake {
+	if x != nil {
+		return x.Handshake
+	}
+	return nil
+}
+
+// Heartbeat is a message that represents the status of a service reported by its agent.
+type Heartbeat struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The UUID of the service.
+	Id *v1.UUID `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// The status of the service. If it is unspecified, the service is up.
+	Status Heartbeat_Status `protobuf:"varint,2,opt,name=status,proto3,enum=vakeel_way.v2.Heartbeat_Status" json:"status,omitempty"`
+	// The human-readable explanation of the status, e.g. why the service is
+	// degraded. It is attached to the notifications.
+	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	// The time the status is kept without a heartbeat. If it is not set, the
+	// TTL configured on the server is used. It is capped at a day.
+	Ttl           *durationpb.Duration `protobuf:"bytes,4,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Heartbeat) Reset() {
+	*x = Heartbeat{}
+	mi := &file_api_vakeel_way_v2_state_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Heartbeat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Heartbeat) ProtoMessage() {}
+
+func (x *Heartbeat) ProtoReflect() protoreflect.Message {
+	mi := &file_api_vakeel_way_v2_state_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Heartbeat.ProtoReflect.Descriptor instead.
+func (*Heartbeat) Descriptor() ([]byte, []int) {
+	return file_api_vakeel_way_v2_state_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Heartbeat) GetId() *v1.UUID {
+	if x != nil {
+		return x.Id
+	}
+	return nil
+}
+
+func (x *Heartbeat) GetStatus() Heartbeat_Status {
+	if x != nil {
+		return x.Status
+	}
+	return Heartbeat_STATUS_UNSPECIFIED
+}
+
+func (x *Heartbeat) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Heartbeat) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+var File_api_vakeel_way_v2_state_proto protoreflect.FileDescriptor
+
+var file_api_vakeel_way_v2_state_proto_rawDesc = []byte{
+	0x0a, 0x1d, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x61, 0x6b, 0x65, 0x65, 0x6c, 0x5f, 0x77, 0x61, 0x79,
+	0x2f, 0x76, 0x32, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
+	0x0d, 0x76, 0x61, 0x6b, 0x65, 0x65, 0x6c, 0x5f, 0x77, 0x61, 0x79, 0x2e, 0x76, 0x32, 0x1a, 0x1e,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f,
+	0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17,
+	0x62, 0x61, 0x76, 0x69, 0x78, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x31, 0x2f, 0x75, 0x75, 0x69,
+	0x64, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x1a, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x61, 0x6b,
+	0x65, 0x65, 0x6c, 0x5f, 0x77, 0x61, 0x79, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x22, 0xaf, 0x01, 0x0a, 0x0d, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x38, 0x0a, 0x0a, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65,
+	0x61, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x76, 0x61, 0x6b, 0x65,
+	0x65, 0x6c, 0x5f, 0x77, 0x61, 0x79, 0x2e, 0x76, 0x32, 0x2e, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62,
+	0x65, 0x61, 0x74, 0x52, 0x0a, 0x68, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x73, 0x12,
+	0x2f, 0x0a, 0x07, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x65, 0x64, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x15, 0x2e, 0x76, 0x61, 0x6b, 0x65, 0x65, 0x6c, 0x5f, 0x77, 0x61, 0x79, 0x2e, 0x48, 0x65,
+	0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x52, 0x07, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x65, 0x64,
+	0x12, 0x33, 0x0a, 0x09, 0x68, 0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x76, 0x61, 0x6b, 0x65, 0x65, 0x6c, 0x5f, 0x77, 0x61, 0x79,
+	0x2e, 0x48, 0x61, 0x6e, 0x64, 0x73, 0x68, 0x61, 0x6b, 0x65, 0x52, 0x09, 0x68, 0x61, 0x6e, 0x64,
+	0x73, 0x68, 0x61, 0x6b, 0x65, 0x22, 0xf5, 0x01, 0x0a, 0x09, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62,
+	0x65, 0x61, 0x74, 0x12, 0x22, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x12, 0x2e, 0x62, 0x61, 0x76, 0x69, 0x78, 0x2e, 0x61, 0x70, 0x69, 0x2e, 0x76, 0x31, 0x2e, 0x55,
+	0x55, 0x49, 0x44, 0x52, 0x02, 0x69, 0x64, 0x12, 0x37, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x76, 0x61, 0x6b, 0x65, 0x65, 0x6c,
+	0x5f, 0x77, 0x61, 0x79, 0x2e, 0x76, 0x32, 0x2e, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61,
+	0x74, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2b, 0x0a, 0x03, 0x74, 0x74,
+	0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x03, 0x74, 0x74, 0x6c, 0x22, 0x44, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x16, 0x0a, 0x12, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x53, 0x50,
+	0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x53, 0x54, 0x41,
+	0x54, 0x55, 0x53, 0x5f, 0x55, 0x50, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x53, 0x54, 0x41, 0x54,
+	0x55, 0x53, 0x5f, 0x44, 0x45, 0x47, 0x52, 0x41, 0x44, 0x45, 0x44, 0x10, 0x02, 0x32, 0x56, 0x0a,
+	0x0c, 0x53, 0x74, 0x61, 0x74, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x46, 0x0a,
+	0x06, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x1c, 0x2e, 0x76, 0x61, 0x6b, 0x65, 0x65, 0x6c,
+	0x5f, 0x77, 0x61, 0x79, 0x2e, 0x76, 0x32, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x76, 0x61, 0x6b, 0x65, 0x65, 0x6c, 0x5f, 0x77,
+	0x61, 0x79, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x28, 0x01, 0x30, 0x01, 0x42, 0x40, 0x5a, 0x3e, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x61, 0x76, 0x69, 0x78, 0x2f, 0x76, 0x61, 0x6b, 0x65, 0x65, 0x6c,
+	0x2d, 0x77, 0x61, 0x79, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x76, 0x61, 0x6b,
+	0x65, 0x65, 0x6c, 0x5f, 0x77, 0x61, 0x79, 0x2f, 0x76, 0x32, 0x3b, 0x76, 0x61, 0x6b, 0x65, 0x65,
+	0x6c, 0x5f, 0x77, 0x61, 0x79, 0x76, 0x32, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_api_vakeel_way_v2_state_proto_rawDescOnce sync.Once
+	file_api_vakeel_way_v2_state_proto_rawDescData = file_api_vakeel_way_v2_state_proto_rawDesc
+)
+
+func file_api_vakeel_way_v2_state_proto_rawDescGZIP() []byte {
+	file_api_vakeel_way_v2_state_proto_rawDescOnce.Do(func() {
+		file_api_vakeel_way_v2_state_proto_rawDescData = protoimpl.X.CompressGZIP(file_api_vakeel_way_v2_state_proto_rawDescData)
+	})
+	return file_api_vakeel_way_v2_state_proto_rawDescData
+}
+
+var file_api_vakeel_way_v2_state_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_api_vakeel_way_v2_state_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_api_vakeel_way_v2_state_proto_goTypes = []any{
+	(Heartbeat_Status)(0),             // 0: vakeel_way.v2.Heartbeat.Status
+	(*UpdateRequest)(nil),             // 1: vakeel_way.v2.UpdateRequest
+	(*Heartbeat)(nil),                 // 2: vakeel_way.v2.Heartbeat
+	(*vakeel_way.Heartbeat)(nil),      // 3: vakeel_way.Heartbeat
+	(*vakeel_way.Handshake)(nil),      // 4: vakeel_way.Handshake
+	(*v1.UUID)(nil),                   // 5: bavix.api.v1.UUID
+	(*durationpb.Duration)(nil),       // 6: google.protobuf.Duration
+	(*vakeel_way.UpdateResponse)(nil), // 7: vakeel_way.UpdateResponse
+}
+var file_api_vakeel_way_v2_state_proto_depIdxs = []int32{
+	2, // 0: vakeel_way.v2.UpdateRequest.heartbeats:type_name -> vakeel_way.v2.Heartbeat
+	3, // 1: vakeel_way.v2.UpdateRequest.delayed:type_name -> vakeel_way.Heartbeat
+	4, // 2: vakeel_way.v2.UpdateRequest.handshake:type_name -> vakeel_way.Handshake
+	5, // 3: vakeel_way.v2.Heartbeat.id:type_name -> bavix.api.v1.UUID
+	0, // 4: vakeel_way.v2.Heartbeat.status:type_name -> vakeel_way.v2.Heartbeat.Status
+	6, // 5: vakeel_way.v2.Heartbeat.ttl:type_name -> google.protobuf.Duration
+	1, // 6: vakeel_way.v2.StateService.Update:input_type -> vakeel_way.v2.UpdateRequest
+	7, // 7: vakeel_way.v2.StateService.Update:output_type -> vakeel_way.UpdateResponse
+	7, // [7:8] is the sub-list for method output_type
+	6, // [6:7] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_api_vakeel_way_v2_state_proto_init() }
+func file_api_vakeel_way_v2_state_proto_init() {
+	if File_api_vakeel_way_v2_state_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_api_vakeel_way_v2_state_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_api_vakeel_way_v2_state_proto_goTypes,
+		DependencyIndexes: file_api_vakeel_way_v2_state_proto_depIdxs,
+		EnumInfos:         file_api_vakeel_way_v2_state_proto_enumTypes,
+		MessageInfos:      file_api_vakeel_way_v2_state_proto_msgTypes,
+	}.Build()
+	File_api_vakeel_way_v2_state_proto = out.File
+	file_api_vakeel_way_v2_state_proto_rawDesc = nil
+	file_api_vakeel_way_v2_state_proto_goTypes = nil
+	file_api_vakeel_way_v2_state_proto_depIdxs = nil
+}
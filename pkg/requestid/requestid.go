@@ -0,0 +1,139 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataKey is the key of the gRPC metadata carrying the request ID, both
+// in the request and in the response header.
+const MetadataKey = "x-request-id"
+
+// HTTPHeader is the HTTP header carrying the request ID to the webhooks.
+const HTTPHeader = "X-Request-ID"
+
+// maxLength is the maximum length of a request ID accepted from a client.
+const maxLength = 128
+
+// contextKey is the key of the request ID in the context.Context.
+type contextKey struct{}
+
+// NewContext returns a copy of the context carrying the request ID.
+//
+// Parameters:
+//   - ctx: The parent context.Context.
+//   - id: The request ID.
+//
+// Returns:
+//   - The context.Context carrying the request ID.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID carried by the context.
+//
+// Parameters:
+//   - ctx: The context.Context of the call.
+//
+// Returns:
+//   - The request ID, or empty if the context carries none.
+//   - Whether the context carries a request ID.
+func FromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(contextKey{}).(string)
+
+	return id, ok && id != ""
+}
+
+// extract returns the request ID sent by the client, or a new one.
+//
+// The request ID of the client is accepted if it is a non-empty string of at
+// most 128 printable ASCII characters. Otherwise a random UUID is generated,
+// so a malformed ID is never written to the logs or the webhook headers.
+func extract(ctx context.Context) string {
+	for _, id := range metadata.ValueFromIncomingContext(ctx, MetadataKey) {
+		if valid(id) {
+			return id
+		}
+	}
+
+	return uuid.NewString()
+}
+
+// valid reports whether the request ID sent by the client is accepted.
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for i := range len(id) {
+		if id[i] < ' ' || id[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// serverStream is a gRPC server stream with the context carrying the request ID.
+type serverStream struct {
+	grpc.ServerStream
+
+	ctx context.Context //nolint:containedctx
+}
+
+// Context returns the context.Context carrying the request ID.
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// UnaryInterceptor is a gRPC interceptor that assigns a request ID to every call.
+//
+// The request ID is taken from the x-request-id metadata of the request, or
+// generated if the client has not sent one. It is added to the context of the
+// call, where it is found by FromContext, and echoed in the x-request-id
+// metadata of the response header, so the client can correlate the call with
+// the logs of the server and the webhooks it triggers.
+//
+// Returns:
+//   - The grpc.UnaryServerInterceptor assigning the request IDs.
+func UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		_ *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		id := extract(ctx)
+
+		// The request ID is echoed even if the call fails.
+		_ = grpc.SetHeader(ctx, metadata.Pairs(MetadataKey, id))
+
+		return handler(NewContext(ctx, id), req)
+	}
+}
+
+// StreamInterceptor is a gRPC interceptor that assigns a request ID to every stream.
+//
+// The request ID is shared by all the messages of the stream. See
+// UnaryInterceptor.
+//
+// Returns:
+//   - The grpc.StreamServerInterceptor assigning the request IDs.
+func StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		_ *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		id := extract(ss.Context())
+
+		// The request ID is echoed even if the stream fails.
+		_ = ss.SetHeader(metadata.Pairs(MetadataKey, id))
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: NewContext(ss.Context(), id)})
+	}
+}
@@ -0,0 +1,137 @@
+package interceptor
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/bavix/vakeel-way/pkg/requestid"
+)
+
+// accessEvent returns the event of the access log of a call.
+//
+// The calls ending with a server-side failure are logged as warnings, the
+// other calls, including the streams closed by the clients, as information.
+//
+// Parameters:
+//   - ctx: The context.Context of the call, carrying the peer.
+//   - logger: The logger of the access log.
+//   - method: The full name of the method.
+//   - start: The time the call started.
+//   - err: The error returned by the handler.
+//
+// Returns:
+//   - The event with the method, the peer, the duration, the code and the
+//     compression of the call.
+func accessEvent(
+	ctx context.Context,
+	logger *zerolog.Logger,
+	method string,
+	start time.Time,
+	err error,
+) *zerolog.Event {
+	code := status.Code(err)
+
+	// The streams are ended by the context of the client.
+	if errors.Is(err, context.Canceled) {
+		code = codes.Canceled
+	}
+
+	event := logger.Info()
+
+	//nolint:exhaustive
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal,
+		codes.Unavailable, codes.DataLoss:
+		event = logger.Warn()
+	}
+
+	address := "unknown"
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		address = p.Addr.String()
+	}
+
+	return event.
+		Str("method", method).
+		Str("peer", address).
+		Dur("duration", time.Since(start)).
+		Str("code", code.String()).
+		Str("compression", compression(ctx))
+}
+
+// withRequestID returns the logger tagged with the request ID of the call.
+//
+// Parameters:
+//   - ctx: The context.Context of the call, carrying the request ID.
+//   - logger: The logger of the call.
+//
+// Returns:
+//   - The logger with the request_id field, or the logger itself if the call
+//     has no request ID.
+func withRequestID(ctx context.Context, logger *zerolog.Logger) *zerolog.Logger {
+	id, ok := requestid.FromContext(ctx)
+	if !ok {
+		return logger
+	}
+
+	tagged := logger.With().Str("request_id", id).Logger()
+
+	return &tagged
+}
+
+// compression returns the compression negotiated for the call.
+//
+// Parameters:
+//   - ctx: The context.Context of the call, carrying the transport stream.
+//
+// Returns:
+//   - The algorithm of the messages received by the server and, if the
+//     responses are compressed otherwise, the algorithm of the responses
+//     after a slash, e.g. "gzip" or "gzip/identity". It is "identity" for
+//     the uncompressed calls.
+func compression(ctx context.Context) string {
+	stream, ok := grpc.ServerTransportStreamFromContext(ctx).(interface {
+		RecvCompress() string
+		SendCompress() string
+	})
+	if !ok {
+		return "identity"
+	}
+
+	received, sent := stream.RecvCompress(), stream.SendCompress()
+	if received == "" {
+		received = "identity"
+	}
+
+	if sent == "" {
+		sent = "identity"
+	}
+
+	if received == sent {
+		return received
+	}
+
+	return received + "/" + sent
+}
+
+// size returns the size of the wire encoding of the message.
+//
+// Parameters:
+//   - msg: The message.
+//
+// Returns:
+//   - The size in bytes, or zero if the message is not a protobuf message.
+func size(msg interface{}) int {
+	if m, ok := msg.(proto.Message); ok {
+		return proto.Size(m)
+	}
+
+	return 0
+}
@@ -2,6 +2,8 @@ package interceptor
 
 import (
 	"context"
+	"sync/atomic"
+	"time"
 
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
@@ -12,41 +14,62 @@ import (
 //
 // It adds a context.Context field to the wrapper. This field is used to
 // store the context.Context object that is used to log messages related
-// to the gRPC stream.
+// to the gRPC stream. The wrapper also counts the messages of the stream
+// and their sizes for the access log. The counters are atomic, because the
+// messages may be sent and received from different goroutines.
 type serverStreamWrapper struct {
 	ss  grpc.ServerStream
 	ctx context.Context //nolint:containedctx
+
+	received      atomic.Int64
+	receivedBytes atomic.Int64
+	sent          atomic.Int64
+	sentBytes     atomic.Int64
 }
 
 // Context returns the context.Context object stored in the wrapper.
 //
 // It is used to get the context.Context object that is used to log messages
 // related to the gRPC stream.
-func (w serverStreamWrapper) Context() context.Context {
+func (w *serverStreamWrapper) Context() context.Context {
 	return w.ctx
 }
 
 // RecvMsg receives a message from the stream.
 //
-// It is used to receive a message from the stream and log messages related
-// to the message.
-func (w serverStreamWrapper) RecvMsg(msg interface{}) error {
-	return w.ss.RecvMsg(msg)
+// It is used to receive a message from the stream and count it for the
+// access log.
+func (w *serverStreamWrapper) RecvMsg(msg interface{}) error {
+	if err := w.ss.RecvMsg(msg); err != nil {
+		return err
+	}
+
+	w.received.Add(1)
+	w.receivedBytes.Add(int64(size(msg)))
+
+	return nil
 }
 
 // SendMsg sends a message to the stream.
 //
-// It is used to send a message to the stream and log messages related
-// to the message.
-func (w serverStreamWrapper) SendMsg(msg interface{}) error {
-	return w.ss.SendMsg(msg)
+// It is used to send a message to the stream and count it for the access
+// log.
+func (w *serverStreamWrapper) SendMsg(msg interface{}) error {
+	if err := w.ss.SendMsg(msg); err != nil {
+		return err
+	}
+
+	w.sent.Add(1)
+	w.sentBytes.Add(int64(size(msg)))
+
+	return nil
 }
 
 // SendHeader sends a metadata header to the stream.
 //
 // It is used to send a metadata header to the stream and log messages
 // related to the header.
-func (w serverStreamWrapper) SendHeader(md metadata.MD) error {
+func (w *serverStreamWrapper) SendHeader(md metadata.MD) error {
 	return w.ss.SendHeader(md)
 }
 
@@ -54,7 +77,7 @@ func (w serverStreamWrapper) SendHeader(md metadata.MD) error {
 //
 // It is used to set a metadata header on the stream and log messages
 // related to the header.
-func (w serverStreamWrapper) SetHeader(md metadata.MD) error {
+func (w *serverStreamWrapper) SetHeader(md metadata.MD) error {
 	return w.ss.SetHeader(md)
 }
 
@@ -62,7 +85,7 @@ func (w serverStreamWrapper) SetHeader(md metadata.MD) error {
 //
 // It is used to set a metadata trailer on the stream and log messages
 // related to the trailer.
-func (w serverStreamWrapper) SetTrailer(md metadata.MD) {
+func (w *serverStreamWrapper) SetTrailer(md metadata.MD) {
 	w.ss.SetTrailer(md)
 }
 
@@ -72,6 +95,11 @@ func (w serverStreamWrapper) SetTrailer(md metadata.MD) {
 // It takes a logger as a parameter and returns a grpc.StreamServerInterceptor.
 // The returned interceptor is used to intercept the gRPC stream requests.
 //
+// Every stream is written to the access log when it ends, with its method,
+// peer, duration, status code, and the number and size of the messages
+// received and sent. If the stream has a request ID, it is attached to the
+// logger. See requestid.StreamInterceptor.
+//
 // The interceptor function is called for each gRPC stream request.
 // It takes the server, the stream, the server info, and the handler.
 // It returns an error.
@@ -79,16 +107,35 @@ func StreamInterceptor(logger *zerolog.Logger) grpc.StreamServerInterceptor {
 	return func(
 		srv interface{}, // The server object.
 		ss grpc.ServerStream, // The stream object.
-		_ *grpc.StreamServerInfo, // The server info.
+		info *grpc.StreamServerInfo, // The server info.
 		handler grpc.StreamHandler, // The handler function for the stream.
 	) error {
+		start := time.Now()
+
+		// Tag the logs of the stream with its request ID.
+		logger := withRequestID(ss.Context(), logger)
+
 		// Create a serverStreamWrapper object with the stream and context.
 		// The context is created with the logger.
 		//
 		// It is used to log messages related to the gRPC stream.
-		return handler(srv, serverStreamWrapper{
+		//
+		//nolint:exhaustruct
+		wrapper := &serverStreamWrapper{
 			ss:  ss,
 			ctx: logger.WithContext(ss.Context()),
-		})
+		}
+
+		err := handler(srv, wrapper)
+
+		// Write the stream to the access log.
+		accessEvent(ss.Context(), logger, info.FullMethod, start, err).
+			Int64("received", wrapper.received.Load()).
+			Int64("received_bytes", wrapper.receivedBytes.Load()).
+			Int64("sent", wrapper.sent.Load()).
+			Int64("sent_bytes", wrapper.sentBytes.Load()).
+			Msg("gRPC stream")
+
+		return err
 	}
 }
@@ -2,6 +2,7 @@ package interceptor
 
 import (
 	"context"
+	"time"
 
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
@@ -10,6 +11,10 @@ import (
 // UnaryInterceptor is a gRPC interceptor that adds a logger to the context.
 // The logger can be used to log messages related to the gRPC request.
 //
+// Every request is written to the access log with its method, peer, duration,
+// request and response sizes, and status code. If the call has a request ID,
+// it is attached to the logger. See requestid.UnaryInterceptor.
+//
 // It takes a logger as a parameter and returns a grpc.UnaryServerInterceptor.
 // The returned interceptor is used to intercept the gRPC unary requests.
 //
@@ -22,11 +27,24 @@ func UnaryInterceptor(logger *zerolog.Logger) grpc.UnaryServerInterceptor {
 	return func(
 		innerCtx context.Context, // The context of the gRPC request.
 		req interface{}, // The request object.
-		_ *grpc.UnaryServerInfo, // The server info.
+		info *grpc.UnaryServerInfo, // The server info.
 		handler grpc.UnaryHandler, // The handler function for the request.
 	) (interface{}, error) {
+		start := time.Now()
+
+		// Tag the logs of the call with its request ID.
+		logger := withRequestID(innerCtx, logger)
+
 		// Add the logger to the context.
 		// Call the handler.
-		return handler(logger.WithContext(innerCtx), req)
+		resp, err := handler(logger.WithContext(innerCtx), req)
+
+		// Write the call to the access log.
+		accessEvent(innerCtx, logger, info.FullMethod, start, err).
+			Int("request_size", size(req)).
+			Int("response_size", size(resp)).
+			Msg("gRPC call")
+
+		return resp, err
 	}
 }